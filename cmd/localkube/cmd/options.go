@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"net"
 
 	flag "github.com/spf13/pflag"
@@ -28,7 +29,7 @@ import (
 
 func NewLocalkubeServer() *localkube.LocalkubeServer {
 	// net.ParseCIDR returns multiple values. Use the IPNet return value
-	_, defaultServiceClusterIPRange, _ := net.ParseCIDR(util.DefaultServiceClusterIP + "/24")
+	_, defaultServiceClusterIPRange, _ := net.ParseCIDR(util.DefaultServiceClusterIPRange)
 
 	return &localkube.LocalkubeServer{
 		Containerized:            false,
@@ -45,6 +46,7 @@ func NewLocalkubeServer() *localkube.LocalkubeServer {
 		ShowVersion:              false,
 		RuntimeConfig:            map[string]string{"api/all": "true"},
 		ExtraConfig:              util.ExtraOptionSlice{},
+		CryptoMode:               util.GetCryptoMode(),
 	}
 }
 
@@ -56,6 +58,7 @@ func AddFlags(s *localkube.LocalkubeServer) {
 	flag.IPVar(&s.DNSIP, "dns-ip", s.DNSIP, "The cluster dns IP")
 	flag.StringVar(&s.LocalkubeDirectory, "localkube-directory", s.LocalkubeDirectory, "The directory localkube will store files in")
 	flag.IPNetVar(&s.ServiceClusterIPRange, "service-cluster-ip-range", s.ServiceClusterIPRange, "The service-cluster-ip-range for the apiserver")
+	flag.StringVar(&s.PodNetworkCIDR, "pod-network-cidr", "", "The network the pods get their addresses from, given to the kubelet, the proxy and the controller manager. The defaults of the components when empty")
 	flag.IPVar(&s.APIServerAddress, "apiserver-address", s.APIServerAddress, "The address the apiserver will listen securely on")
 	flag.IntVar(&s.APIServerPort, "apiserver-port", s.APIServerPort, "The port the apiserver will listen securely on")
 	flag.IPVar(&s.APIServerInsecureAddress, "apiserver-insecure-address", s.APIServerInsecureAddress, "The address the apiserver will listen insecurely on")
@@ -68,6 +71,11 @@ func AddFlags(s *localkube.LocalkubeServer) {
 	flag.Var(&s.RuntimeConfig, "runtime-config", "A set of key=value pairs that describe runtime configuration that may be passed to apiserver. apis/<groupVersion> key can be used to turn on/off specific api versions. apis/<groupVersion>/<resource> can be used to turn on/off specific resources. api/all and api/legacy are special keys to control all and legacy api versions respectively.")
 	flag.IPVar(&s.NodeIP, "node-ip", s.NodeIP, "IP address of the node. If set, kubelet will use this IP address for the node.")
 	flag.StringVar(&s.ContainerRuntime, "container-runtime", "", "The container runtime to be used")
+	flag.StringVar(&s.Master, "master", "", "The secure URL of the apiserver of the cluster to join as a worker node, only running the kubelet and the proxy. The certificates of the cluster must be in the localkube directory")
+	flag.StringVar(&s.EtcdPeerURL, "etcd-peer-url", "", "The URL etcd advertises to the other members of its cluster, and listens for them on. Only needed for a cluster with several control planes")
+	flag.StringVar(&s.EtcdJoin, "etcd-join", "", "The client URL of a member of the etcd cluster to join as an additional control plane. The certificates of the cluster must be in the localkube directory")
+	flag.StringVar(&s.CryptoMode, "crypto-mode", s.CryptoMode, fmt.Sprintf("Restricts the algorithms of the generated certs and of TLS, one of %v", util.CryptoModes))
+	flag.BoolVar(&s.SeccompDefault, "seccomp-default", false, "Default the seccomp profile of pods without one to the default profile of the container runtime, through a pod security policy")
 	flag.StringVar(&s.NetworkPlugin, "network-plugin", "", "The name of the network plugin")
 	flag.StringVar(&s.FeatureGates, "feature-gates", "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
 	flag.Var(&s.ExtraConfig, "extra-config", "A set of key=value pairs that describe configuration that may be passed to different components. The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.")
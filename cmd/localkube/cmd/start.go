@@ -27,6 +27,7 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"k8s.io/kubernetes/pkg/util/config"
 	"k8s.io/minikube/pkg/localkube"
+	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
 
@@ -66,7 +67,15 @@ func StartLocalkube() {
 }
 
 func SetupServer(s *localkube.LocalkubeServer) {
-	if s.ShouldGenerateCerts {
+	if err := util.SetCryptoMode(s.CryptoMode); err != nil {
+		fmt.Println("Invalid crypto mode!")
+		panic(err)
+	}
+	if s.CryptoMode == util.CryptoModeFIPS && !util.FIPSBuild() {
+		glog.Warningln("The TLS connections of the Kubernetes components are only restricted to the approved cipher suites by a FIPS build of localkube")
+	}
+
+	if s.ShouldGenerateCerts && !s.IsWorker() && !s.IsJoiningControlPlane() {
 		if err := s.GenerateCerts(); err != nil {
 			fmt.Println("Failed to create certificates!")
 			panic(err)
@@ -94,8 +103,17 @@ func SetupServer(s *localkube.LocalkubeServer) {
 	}
 	capabilities.Initialize(c)
 
+	if s.IsWorker() {
+		setupWorker(s)
+		return
+	}
+
 	// setup etcd
-	etcd, err := s.NewEtcd(localkube.KubeEtcdClientURLs, localkube.KubeEtcdPeerURLs, "kubeetcd", s.GetEtcdDataDirectory())
+	peerURLs := localkube.KubeEtcdPeerURLs
+	if s.EtcdPeerURL != "" {
+		peerURLs = []string{s.EtcdPeerURL}
+	}
+	etcd, err := s.NewEtcd(localkube.KubeEtcdClientURLs, peerURLs, s.GetEtcdName(), s.GetEtcdDataDirectory())
 	if err != nil {
 		panic(err)
 	}
@@ -125,6 +143,30 @@ func SetupServer(s *localkube.LocalkubeServer) {
 	proxy := s.NewProxyServer()
 	s.AddServer(proxy)
 
-	storageProvisioner := s.NewStorageProvisionerServer()
-	s.AddServer(storageProvisioner)
+	if s.SeccompDefault {
+		seccompDefault := s.NewSeccompDefaultServer()
+		s.AddServer(seccompDefault)
+	}
+
+	// The first control plane provisions the volumes of the cluster
+	if !s.IsJoiningControlPlane() {
+		storageProvisioner := s.NewStorageProvisionerServer()
+		s.AddServer(storageProvisioner)
+	}
+}
+
+// setupWorker only runs the kubelet and the proxy, joining the cluster of
+// the apiserver at s.Master.
+func setupWorker(s *localkube.LocalkubeServer) {
+	if err := s.WriteWorkerKubeconfig(); err != nil {
+		fmt.Println("Failed to write the kubeconfig of the worker!")
+		panic(err)
+	}
+	fmt.Printf("localkube joining the cluster at %s\n", s.Master)
+
+	kubelet := s.NewKubeletServer()
+	s.AddServer(kubelet)
+
+	proxy := s.NewProxyServer()
+	s.AddServer(proxy)
 }
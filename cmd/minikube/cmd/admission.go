@@ -0,0 +1,143 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/admission"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var admissionFilename string
+
+// admissionCmd represents the admission command
+var admissionCmd = &cobra.Command{
+	Use:   "admission SUBCOMMAND",
+	Short: "Tests manifests against the admission chain of the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var admissionTestCmd = &cobra.Command{
+	Use:   "test -f FILENAME",
+	Short: "Reports which objects of a manifest the admission chain would mutate or reject.",
+	Long: `Reports which objects of a manifest the admission chain of the apiserver would mutate or reject,
+e.g. while developing limit ranges, quotas or pod security policies.
+
+The apiserver has no dry-run, so each object is created in a scratch namespace, with copies of
+the limit ranges, resource quotas and service accounts of its namespace, and compared with the
+object the apiserver returns. The objects are neutralized not to run pods: pods are bound to a
+node which doesn't exist and controllers have no replicas. The scratch namespaces are deleted
+afterwards. Cluster-scoped objects are skipped.
+
+The mutations are listed as "+ field: value" when added, "- field" when removed and
+"~ field: old -> new" when changed, leaving out the defaults of the apiserver. The command exits
+with 1 when an object is rejected.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if admissionFilename == "" {
+			fmt.Fprintln(os.Stderr, "usage: minikube admission test -f FILENAME")
+			os.Exit(1)
+		}
+		in := os.Stdin
+		if admissionFilename != "-" {
+			f, err := os.Open(admissionFilename)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening the manifest: %s\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			in = f
+		}
+		objects, err := admission.Decode(in)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		tester, err := admission.NewTester(client)
+		if err != nil {
+			glog.Errorln("Error testing admission: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		var results []admission.Result
+		for _, o := range objects {
+			r, err := tester.Test(o)
+			if err != nil {
+				glog.Errorln("Error testing admission: ", err)
+				break
+			}
+			results = append(results, r)
+		}
+		if err := tester.Cleanup(); err != nil {
+			glog.Errorln(err)
+		}
+		printAdmissionResults(os.Stdout, results)
+		if len(results) < len(objects) {
+			os.Exit(1)
+		}
+		for _, r := range results {
+			if r.Verdict == admission.Rejected {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func printAdmissionResults(w io.Writer, results []admission.Result) {
+	for _, r := range results {
+		name := r.Name
+		if r.Namespace != "" {
+			name = r.Namespace + "/" + r.Name
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", r.Kind, name, r.Verdict)
+		for _, m := range r.Mutations {
+			fmt.Fprintf(w, "  %s\n", m)
+		}
+		if r.Message != "" {
+			fmt.Fprintf(w, "  %s\n", r.Message)
+		}
+	}
+}
+
+func init() {
+	admissionTestCmd.Flags().StringVarP(&admissionFilename, "filename", "f", "", "The manifest of the objects to test, or - for the standard input")
+	admissionCmd.AddCommand(admissionTestCmd)
+	RootCmd.AddCommand(admissionCmd)
+}
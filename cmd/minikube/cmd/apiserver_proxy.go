@@ -0,0 +1,214 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/loadbalancer"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
+)
+
+const apiserverProxyDaemon = "apiserver-proxy"
+
+var (
+	apiserverProxyAddress     string
+	apiserverProxyPort        int
+	apiserverProxyBackendPort int
+)
+
+// apiserverProxyCmd represents the apiserver-proxy command
+var apiserverProxyCmd = &cobra.Command{
+	Use:   "apiserver-proxy",
+	Short: "Load balances the apiservers of the control planes on a local port.",
+	Long: `Load balances the apiservers of the control planes on a local port, which the kubeconfig points to
+for a cluster started with several control planes or with --apiserver-listen-address. Each connection goes
+to the next running control plane.
+
+minikube start runs it in the background, logging to the logs directory of minikube. The command runs
+until interrupted, or until the cluster is deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		l, err := net.Listen("tcp", net.JoinHostPort(apiserverProxyAddress, strconv.Itoa(apiserverProxyPort)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listening on %s port %d: %s\n", apiserverProxyAddress, apiserverProxyPort, err)
+			os.Exit(1)
+		}
+		unregister, err := daemons.Register(apiserverProxyDaemon)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+
+		lb := loadbalancer.New(nil)
+		go func() {
+			for {
+				backends, err := loadControlPlaneAPIServers(apiserverProxyBackendPort)
+				if err != nil {
+					glog.Errorln("Error listing the control planes: ", err)
+				} else {
+					lb.SetBackends(backends)
+				}
+				time.Sleep(10 * time.Second)
+			}
+		}()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			l.Close()
+		}()
+
+		fmt.Printf("Load balancing the apiservers on %s\n", l.Addr())
+		lb.Serve(l)
+	},
+}
+
+func loadControlPlaneAPIServers(port int) ([]string, error) {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+	return controlPlaneAPIServers(api, port)
+}
+
+// controlPlaneAPIServers returns the address of the apiserver of each
+// running control plane, listening on port.
+func controlPlaneAPIServers(api libmachine.API, port int) ([]string, error) {
+	nodes, err := cluster.ListControlPlaneNodes(api)
+	if err != nil {
+		return nil, err
+	}
+	machines := []string{constants.MachineName}
+	for _, n := range nodes {
+		machines = append(machines, constants.NodeMachineName(n))
+	}
+	var backends []string
+	for _, m := range machines {
+		if ip := machineIP(api, m); ip != "-" {
+			backends = append(backends, net.JoinHostPort(ip, strconv.Itoa(port)))
+		}
+	}
+	return backends, nil
+}
+
+func machineIP(api libmachine.API, machineName string) string {
+	h, err := api.Load(machineName)
+	if err != nil {
+		return "-"
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return "-"
+	}
+	return hostIP(api, machineName, s.String())
+}
+
+// startAPIServerProxy runs minikube apiserver-proxy in the background on
+// address and port, for the apiservers listening on port, replacing the one
+// of a previous start, and waits for it to listen.
+func startAPIServerProxy(address string, port int) error {
+	if _, err := daemons.Stop(apiserverProxyDaemon); err != nil {
+		return err
+	}
+	local := net.JoinHostPort(dialAddress(address), strconv.Itoa(port))
+	if err := waitForFree(local); err != nil {
+		return err
+	}
+
+	logPath := constants.MakeMiniPath("logs", apiserverProxyDaemon+".log")
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening the log of the apiserver proxy")
+	}
+	defer logFile.Close()
+	proxy := exec.Command(os.Args[0], apiserverProxyDaemon,
+		"--address", address, "--port", strconv.Itoa(port), "--backend-port", strconv.Itoa(port))
+	proxy.Stdout = logFile
+	proxy.Stderr = logFile
+	if err := proxy.Start(); err != nil {
+		return errors.Wrap(err, "Error starting the apiserver proxy")
+	}
+	if err := proxy.Process.Release(); err != nil {
+		return err
+	}
+
+	if err := waitForListener(local); err != nil {
+		return errors.Wrapf(err, "The apiserver proxy is not listening on %s, see %s", local, logPath)
+	}
+	return nil
+}
+
+// dialAddress returns the address to connect to a daemon listening on
+// address, which is the loopback one when it listens on all of them.
+func dialAddress(address string) string {
+	if ip := net.ParseIP(address); ip == nil || ip.IsUnspecified() {
+		return "127.0.0.1"
+	}
+	return address
+}
+
+// waitForFree waits for a daemon stopped in the background to release the
+// address.
+func waitForFree(address string) error {
+	free := func() error {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err != nil {
+			return nil
+		}
+		conn.Close()
+		return errors.Errorf("%s is in use", address)
+	}
+	return util.RetryAfter(5, free, time.Second)
+}
+
+// waitForListener waits for a daemon started in the background to listen on
+// the address.
+func waitForListener(address string) error {
+	listening := func() error {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	return util.RetryAfter(10, listening, time.Second)
+}
+
+func init() {
+	apiserverProxyCmd.Flags().StringVar(&apiserverProxyAddress, "address", "127.0.0.1", "The local address to listen on")
+	apiserverProxyCmd.Flags().IntVar(&apiserverProxyPort, "port", constants.APIServerPort, "The local port to listen on")
+	apiserverProxyCmd.Flags().IntVar(&apiserverProxyBackendPort, "backend-port", constants.APIServerPort, "The port the apiservers listen on")
+	RootCmd.AddCommand(apiserverProxyCmd)
+}
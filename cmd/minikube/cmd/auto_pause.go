@@ -0,0 +1,176 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/autopause"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/loadbalancer"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	autoPauseDaemon          = "auto-pause"
+	defaultAutoPauseInterval = time.Minute
+)
+
+var (
+	autoPausePort     int
+	autoPauseBackend  string
+	autoPauseInterval time.Duration
+)
+
+// autoPauseCmd represents the auto-pause command
+var autoPauseCmd = &cobra.Command{
+	Use:   "auto-pause",
+	Short: "Pauses the cluster while the apiserver is idle.",
+	Long: `Forwards the connections to the apiserver from a local port, which the kubeconfig points to when the
+auto-pause addon is enabled. The cluster is paused once no connection was open for the interval, and unpaused
+when the next one is opened.
+
+minikube start runs it in the background, logging to the logs directory of minikube. The command runs
+until interrupted, or until the cluster is deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if autoPauseBackend == "" {
+			fmt.Fprintln(os.Stderr, "The address of the apiserver must be given with --backend")
+			os.Exit(1)
+		}
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(autoPausePort)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listening on port %d: %s\n", autoPausePort, err)
+			os.Exit(1)
+		}
+		unregister, err := daemons.Register(autoPauseDaemon)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+
+		al := autopause.NewListener(l, autoPauseInterval, func() error {
+			fmt.Println("Pausing the idle cluster")
+			return withAPIClient(pauseCluster)
+		}, func() error {
+			fmt.Println("Unpausing the cluster")
+			return withAPIClient(unpauseCluster)
+		})
+		go func() {
+			for now := range time.Tick(10 * time.Second) {
+				if _, err := al.PauseIfIdle(now); err != nil {
+					glog.Errorln(err)
+				}
+			}
+		}()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			l.Close()
+		}()
+
+		fmt.Printf("Forwarding %s to the apiserver at %s, pausing the cluster after %s idle\n", l.Addr(), autoPauseBackend, autoPauseInterval)
+		loadbalancer.New([]string{autoPauseBackend}).Serve(al)
+	},
+}
+
+func withAPIClient(fn func(api libmachine.API) error) error {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return fn(api)
+}
+
+// autoPauseConfig returns whether the auto-pause addon is enabled, and the
+// idle interval set for it.
+func autoPauseConfig() (bool, time.Duration, error) {
+	addon := assets.Addons[autoPauseDaemon]
+	enabled, err := addon.IsEnabled()
+	if err != nil || !enabled {
+		return false, 0, err
+	}
+	values, err := addon.Values()
+	if err != nil {
+		return false, 0, err
+	}
+	interval := defaultAutoPauseInterval
+	if v, ok := values["interval"]; ok {
+		if interval, err = time.ParseDuration(v); err != nil || interval <= 0 {
+			return false, 0, errors.Errorf("%s%s.interval must be a positive duration, e.g. 5m", assets.AddonValuePrefix, autoPauseDaemon)
+		}
+	}
+	return true, interval, nil
+}
+
+// startAutoPause runs minikube auto-pause in the background for the apiserver
+// at backend, replacing the one of a previous start, and waits for it to
+// listen.
+func startAutoPause(backend string, interval time.Duration) error {
+	if _, err := daemons.Stop(autoPauseDaemon); err != nil {
+		return err
+	}
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(constants.AutoPausePort))
+	// The address is only free once the previous proxy exited
+	if err := waitForFree(address); err != nil {
+		return err
+	}
+
+	logPath := constants.MakeMiniPath("logs", autoPauseDaemon+".log")
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening the log of auto-pause")
+	}
+	defer logFile.Close()
+	proxy := exec.Command(os.Args[0], autoPauseDaemon,
+		"--backend="+backend, fmt.Sprintf("--interval=%s", interval))
+	proxy.Stdout = logFile
+	proxy.Stderr = logFile
+	if err := proxy.Start(); err != nil {
+		return errors.Wrap(err, "Error starting auto-pause")
+	}
+	if err := proxy.Process.Release(); err != nil {
+		return err
+	}
+	if err := waitForListener(address); err != nil {
+		return errors.Wrapf(err, "auto-pause is not listening on %s, see %s", address, logPath)
+	}
+	return nil
+}
+
+func init() {
+	autoPauseCmd.Flags().IntVar(&autoPausePort, "port", constants.AutoPausePort, "The local port to listen on")
+	autoPauseCmd.Flags().StringVar(&autoPauseBackend, "backend", "", "The address of the apiserver, as host:port")
+	autoPauseCmd.Flags().DurationVar(&autoPauseInterval, "interval", defaultAutoPauseInterval, "The idle interval after which the cluster is paused")
+	RootCmd.AddCommand(autoPauseCmd)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var buildctlLoad string
+
+// buildctlCmd represents the buildctl command
+var buildctlCmd = &cobra.Command{
+	Use:   "buildctl [--load IMAGE] -- BUILDCTL_ARGS",
+	Short: "Runs buildctl against the buildkit daemon in the minikube VM",
+	Long: `Starts the buildkit daemon in the minikube VM and runs buildctl, which must be on the PATH,
+against it with the client certificates of minikube, e.g.
+
+    minikube buildctl --load myimage:dev -- build --frontend dockerfile.v0 --local context=. --local dockerfile=.
+
+With --load, buildctl build exports the image it builds, which is loaded into the docker daemon
+of the VM, where the cluster runs it: buildkit keeps the images it builds in its own store.
+
+The daemon serves TLS with the certificates of the docker daemon of the VM, which minikube
+generates when it provisions the VM, so that buildctl authenticates as docker does with docker-env.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube buildctl [--load IMAGE] -- BUILDCTL_ARGS")
+			os.Exit(1)
+		}
+		buildctlArgs, err := buildctlArgs(args, buildctlLoad)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		envMap, err := getBuildkitEnv(api)
+		if err != nil {
+			glog.Errorln("Error starting the buildkit daemon:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		c := exec.Command("buildctl", append(buildctlTLSArgs(envMap["BUILDKIT_HOST"], constants.MakeMiniPath("certs")), buildctlArgs...)...)
+		c.Stderr = os.Stderr
+		if buildctlLoad == "" {
+			c.Stdout = os.Stdout
+			if err := c.Run(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+		out, err := c.StdoutPipe()
+		if err != nil {
+			glog.Errorln("Error running buildctl:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := c.Start(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		loadErr := cluster.LoadDockerImage(api, out)
+		if err := c.Wait(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if loadErr != nil {
+			glog.Errorln("Error loading the image into the docker daemon of the VM:", loadErr)
+			cmdUtil.MaybeReportErrorAndExit(loadErr)
+		}
+		fmt.Printf("%s is loaded into the docker daemon of the VM.\n", buildctlLoad)
+	},
+}
+
+// buildctlTLSArgs returns the global arguments of buildctl reaching the
+// daemon at addr with the client certificates in dir, which buildctl reads
+// no variable for.
+func buildctlTLSArgs(addr, dir string) []string {
+	return []string{"--addr", addr, "--tlsdir", dir}
+}
+
+// buildctlArgs returns the arguments of buildctl, which with load build the
+// image into a docker archive written to the output of buildctl.
+func buildctlArgs(args []string, load string) ([]string, error) {
+	if load == "" {
+		return args, nil
+	}
+	if args[0] != "build" {
+		return nil, errors.Errorf("--load loads the image of buildctl build, not of buildctl %s", args[0])
+	}
+	for _, arg := range args {
+		if arg == "--output" || arg == "-o" || strings.HasPrefix(arg, "--output=") {
+			return nil, errors.New("--load sets the output of buildctl build")
+		}
+	}
+	return append(args, "--output", "type=docker,name="+load), nil
+}
+
+func init() {
+	buildctlCmd.Flags().StringVar(&buildctlLoad, "load", "", "Load the image built by buildctl build into the docker daemon of the VM with this name")
+	RootCmd.AddCommand(buildctlCmd)
+}
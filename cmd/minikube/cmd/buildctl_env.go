@@ -0,0 +1,140 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const buildkitEnvTmpl = `{{ .Prefix }}BUILDKIT_HOST{{ .Delimiter }}{{ .BuildkitHost }}{{ .Suffix }}{{ .TLSHint }}{{ .UsageHint }}`
+
+type BuildkitShellConfig struct {
+	Prefix       string
+	Delimiter    string
+	Suffix       string
+	BuildkitHost string
+	TLSHint      string
+	UsageHint    string
+}
+
+// getBuildkitEnv is replaced in tests, as it starts the daemon over ssh.
+var getBuildkitEnv = cluster.GetHostBuildkitEnv
+
+func generateBuildkitUsageHint(userShell string) string {
+	return strings.Replace(generateUsageHint(userShell), "docker-env", "buildctl-env", -1)
+}
+
+// generateBuildkitTLSHint explains how buildctl reaches the daemon, which
+// only accepts the client certificates of minikube, passed with --tlsdir as
+// buildctl reads no variable for them.
+func generateBuildkitTLSHint(userShell, tlsDir string) string {
+	comment := "# "
+	switch userShell {
+	case "cmd":
+		comment = "REM "
+	case "emacs":
+		comment = ";; "
+	}
+	return fmt.Sprintf(`%[1]sbuildctl reaches the daemon over TLS with the client certificates of minikube: buildctl --tlsdir "%[2]s" ...
+%[1]sminikube buildctl passes them, and with --load also loads the image it builds into the docker daemon of the VM.
+`, comment, tlsDir)
+}
+
+func buildkitShellCfgSet(api libmachine.API) (*BuildkitShellConfig, error) {
+	envMap, err := getBuildkitEnv(api)
+	if err != nil {
+		return nil, err
+	}
+
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &BuildkitShellConfig{
+		BuildkitHost: envMap["BUILDKIT_HOST"],
+		TLSHint:      generateBuildkitTLSHint(userShell, constants.MakeMiniPath("certs")),
+		UsageHint:    generateBuildkitUsageHint(userShell),
+	}
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shellSetSyntax(userShell)
+	return shellCfg, nil
+}
+
+func buildkitShellCfgUnset() (*BuildkitShellConfig, error) {
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &BuildkitShellConfig{
+		UsageHint: generateBuildkitUsageHint(userShell),
+	}
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shellUnsetSyntax(userShell)
+	return shellCfg, nil
+}
+
+// buildctlEnvCmd represents the buildctl-env command
+var buildctlEnvCmd = &cobra.Command{
+	Use:   "buildctl-env",
+	Short: "Starts the buildkit daemon in the minikube VM and sets up buildctl env variables",
+	Long: `Starts the buildkit daemon in the minikube VM and sets up buildctl env variables,
+so that images can be built with buildctl directly inside the VM.
+
+The daemon only accepts TLS connections, with the certificates of the docker daemon of the VM:
+buildctl authenticates with the client certificates of minikube, as docker does with docker-env,
+passed with --tlsdir. Buildkit keeps the images it builds in its own store, "minikube buildctl --load"
+also loads them into the docker daemon of the VM, where the cluster runs them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		var shellCfg *BuildkitShellConfig
+		if unset {
+			shellCfg, err = buildkitShellCfgUnset()
+		} else {
+			shellCfg, err = buildkitShellCfgSet(api)
+		}
+		if err != nil {
+			glog.Errorln("Error setting buildkit env variable(s):", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		tmpl := template.Must(template.New("buildkitEnvConfig").Parse(buildkitEnvTmpl))
+		tmpl.Execute(os.Stdout, shellCfg)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(buildctlEnvCmd)
+	buildctlEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	buildctlEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset variables instead of setting them")
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/docker/machine/libmachine"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestBuildkitShellCfgSet(t *testing.T) {
+	defer func(g func(libmachine.API) (map[string]string, error)) { getBuildkitEnv = g }(getBuildkitEnv)
+	getBuildkitEnv = func(libmachine.API) (map[string]string, error) {
+		return map[string]string{
+			"BUILDKIT_HOST": "tcp://192.168.99.100:1234",
+		}, nil
+	}
+	certs := constants.MakeMiniPath("certs")
+
+	var tests = []struct {
+		shell    string
+		expected string
+	}{
+		{
+			shell: "bash",
+			expected: `export BUILDKIT_HOST="tcp://192.168.99.100:1234"
+# buildctl reaches the daemon over TLS with the client certificates of minikube: buildctl --tlsdir "` + certs + `" ...
+# minikube buildctl passes them, and with --load also loads the image it builds into the docker daemon of the VM.
+` + strings.Replace(usageHintMap["bash"], "docker-env", "buildctl-env", -1),
+		},
+		{
+			shell: "cmd",
+			expected: `SET BUILDKIT_HOST=tcp://192.168.99.100:1234
+REM buildctl reaches the daemon over TLS with the client certificates of minikube: buildctl --tlsdir "` + certs + `" ...
+REM minikube buildctl passes them, and with --load also loads the image it builds into the docker daemon of the VM.
+` + strings.Replace(usageHintMap["cmd"], "docker-env", "buildctl-env", -1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.shell, func(t *testing.T) {
+			defaultShellDetector = &FakeShellDetector{test.shell}
+			shellCfg, err := buildkitShellCfgSet(nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			var b bytes.Buffer
+			tmpl := template.Must(template.New("buildkitEnvConfig").Parse(buildkitEnvTmpl))
+			if err := tmpl.Execute(&b, shellCfg); err != nil {
+				t.Fatalf("Error executing the template: %s", err)
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestBuildkitShellCfgUnset(t *testing.T) {
+	var tests = []struct {
+		shell            string
+		expectedShellCfg *BuildkitShellConfig
+	}{
+		{
+			shell: "bash",
+			expectedShellCfg: &BuildkitShellConfig{
+				Prefix:    bashUnsetPfx,
+				Suffix:    bashUnsetSfx,
+				Delimiter: bashUnsetDelim,
+				UsageHint: strings.Replace(usageHintMap["bash"], "docker-env", "buildctl-env", -1),
+			},
+		},
+		{
+			shell: "fish",
+			expectedShellCfg: &BuildkitShellConfig{
+				Prefix:    fishUnsetPfx,
+				Suffix:    fishUnsetSfx,
+				Delimiter: fishUnsetDelim,
+				UsageHint: strings.Replace(usageHintMap["fish"], "docker-env", "buildctl-env", -1),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.shell, func(t *testing.T) {
+			defaultShellDetector = &FakeShellDetector{test.shell}
+			actual, _ := buildkitShellCfgUnset()
+			if !reflect.DeepEqual(actual, test.expectedShellCfg) {
+				t.Errorf("Actual shell config did not match expected: \n\n actual: \n%+v \n\n expected: \n%+v \n\n", actual, test.expectedShellCfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildctlArgs(t *testing.T) {
+	build := []string{"build", "--frontend", "dockerfile.v0", "--local", "context=."}
+
+	args, err := buildctlArgs(build, "")
+	if err != nil || !reflect.DeepEqual(args, build) {
+		t.Errorf("Expected the arguments unchanged without --load, got %v, %v", args, err)
+	}
+	args, err = buildctlArgs(build, "myimage:dev")
+	expected := append(append([]string{}, build...), "--output", "type=docker,name=myimage:dev")
+	if err != nil || !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v, %v", expected, args, err)
+	}
+
+	for _, args := range [][]string{
+		{"du"},
+		{"build", "--output", "type=image"},
+		{"build", "--output=type=local,dest=out"},
+	} {
+		if _, err := buildctlArgs(args, "myimage:dev"); err == nil {
+			t.Errorf("Expected an error loading the image of %v", args)
+		}
+	}
+
+	tls := buildctlTLSArgs("tcp://192.168.99.100:1234", "/home/user/.minikube/certs")
+	if expected := []string{"--addr", "tcp://192.168.99.100:1234", "--tlsdir", "/home/user/.minikube/certs"}; !reflect.DeepEqual(tls, expected) {
+		t.Errorf("Expected %v, got %v", expected, tls)
+	}
+}
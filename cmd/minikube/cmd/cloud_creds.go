@@ -0,0 +1,98 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cloudcreds"
+	"k8s.io/minikube/pkg/minikube/daemons"
+)
+
+var cloudCredsInterval time.Duration
+
+var cloudCredsCmd = &cobra.Command{
+	Use:   cloudcreds.AddonName,
+	Short: "Keeps the secret of the cloud-creds addon in sync with the credentials of the host.",
+	Long: `Reads the cloud credentials of the host at each interval, and writes them to the secret of the cloud-creds
+addon when they changed, so that renewed credentials reach the workloads.
+
+minikube start and minikube addons enable cloud-creds run it in the background, logging to the logs
+directory of minikube. The command runs until interrupted, or until the addon is disabled.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		unregister, err := daemons.Register(cloudcreds.AddonName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		ticker := time.NewTicker(cloudCredsInterval)
+		defer ticker.Stop()
+		fmt.Printf("Syncing the cloud credentials every %s\n", cloudCredsInterval)
+		var synced string
+		for {
+			hash, err := syncCloudCreds(synced)
+			if err != nil {
+				// The credentials may be renewed, or the cluster back, by the next sync
+				glog.Errorln(err)
+			} else {
+				synced = hash
+			}
+			select {
+			case <-ticker.C:
+			case <-signals:
+				return
+			}
+		}
+	},
+}
+
+// syncCloudCreds writes the credentials of the host to the secret unless
+// they are the ones synced last, and returns their hash.
+func syncCloudCreds(synced string) (string, error) {
+	provider, namespace, err := cloudcreds.Config()
+	if err != nil {
+		return synced, err
+	}
+	data, err := cloudcreds.LocalHost().Read(provider)
+	if err != nil {
+		return synced, err
+	}
+	hash := cloudcreds.Hash(data)
+	if hash == synced {
+		return synced, nil
+	}
+	if err := cloudcreds.Sync(namespace, provider, data); err != nil {
+		return synced, err
+	}
+	fmt.Printf("%s Synced the %s credentials to the secret %s/%s\n", time.Now().Format(time.RFC3339), provider, namespace, cloudcreds.SecretName)
+	return hash, nil
+}
+
+func init() {
+	cloudCredsCmd.Flags().DurationVar(&cloudCredsInterval, "interval", time.Minute, "The interval between the reads of the credentials")
+	RootCmd.AddCommand(cloudCredsCmd)
+}
@@ -59,6 +59,29 @@ const boilerPlate = `
 # limitations under the License.
 `
 
+// bashCompletionFunction completes the arguments cobra has no completions
+// for: the namespaces of minikube ns, listed by the command itself.
+const bashCompletionFunction = `
+__minikube_get_namespaces()
+{
+    local minikube_out
+    if minikube_out=$(minikube ns --list 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${minikube_out[*]}" -- "$cur" ) )
+    fi
+}
+
+__custom_func() {
+    case ${last_command} in
+        minikube_ns)
+            __minikube_get_namespaces
+            return
+            ;;
+        *)
+            ;;
+    esac
+}
+`
+
 var completionCmd = &cobra.Command{
 	Use:   "completion SHELL",
 	Short: "Outputs minikube shell completion for the given shell (bash)",
@@ -94,5 +117,6 @@ func GenerateBashCompletion(w io.Writer, cmd *cobra.Command) error {
 }
 
 func init() {
+	RootCmd.BashCompletionFunction = bashCompletionFunction
 	RootCmd.AddCommand(completionCmd)
 }
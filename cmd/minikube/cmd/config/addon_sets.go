@@ -0,0 +1,186 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	// AddonSetPrefix prefixes the properties defining a named set of addons,
+	// addon-set.<set name> = ADDON,ADDON,...
+	AddonSetPrefix = "addon-set."
+	// AddonProfileSetting is the named set of addons applied to the cluster
+	AddonProfileSetting = "addon-profile"
+
+	defaultAddonWaitTimeout = 3 * time.Minute
+)
+
+// parseAddonList splits the comma separated addons, checking that each
+// exists.
+func parseAddonList(list string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := validateAddonName(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("No addon in the list, expected ADDON,ADDON,...")
+	}
+	return names, nil
+}
+
+// addonSet returns the addons of the named set.
+func addonSet(name string) ([]string, error) {
+	m, err := pkgConfig.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	list, ok := m[AddonSetPrefix+name]
+	if !ok {
+		return nil, errors.Errorf("No addon set %s, define it with: minikube config set %s%s ADDON,ADDON,...", name, AddonSetPrefix, name)
+	}
+	return parseAddonList(fmt.Sprintf("%v", list))
+}
+
+// enableAddons enables the addons one after the other, then waits for up to
+// the timeout for all of them to be ready, when minikube is running. If an
+// addon fails to be enabled or to be ready, the addons which were not
+// enabled before are disabled again, leaving the addons as they were.
+func enableAddons(names []string, timeout time.Duration) error {
+	var enabled []string
+	rollback := func(err error) error {
+		if len(enabled) == 0 {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s, disabling %s again\n", err, strings.Join(enabled, ", "))
+		for i := len(enabled) - 1; i >= 0; i-- {
+			if err := setAddon(enabled[i], false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error disabling %s: %s\n", enabled[i], err)
+			}
+		}
+		return err
+	}
+	for _, name := range names {
+		wasEnabled, err := assets.Addons[name].IsEnabled()
+		if err != nil {
+			return err
+		}
+		if !wasEnabled {
+			// Disabling the addon also cleans up what it partially applied
+			enabled = append(enabled, name)
+		}
+		if err := setAddon(name, true); err != nil {
+			return rollback(errors.Wrapf(err, "Error enabling %s", name))
+		}
+	}
+	if timeout <= 0 {
+		return nil
+	}
+	if err := waitAddonsReady(names, timeout); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// setAddon enables or disables the addon and records it in the config, as
+// minikube addons enable and disable do.
+func setAddon(name string, enable bool) error {
+	val := strconv.FormatBool(enable)
+	if err := IsValidAddon(name, val); err != nil {
+		return err
+	}
+	if err := EnableOrDisableAddon(name, val); err != nil {
+		return err
+	}
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	m[name] = enable
+	return WriteConfig(m)
+}
+
+// waitAddonsReady waits for up to the timeout for all the addons to be
+// ready, if minikube is running.
+func waitAddonsReady(names []string, timeout time.Duration) error {
+	api, err := machine.NewAPIClient(GetClientType())
+	if err != nil {
+		return errors.Wrap(err, "Error getting client")
+	}
+	defer api.Close()
+	if running, err := isRunning(api); err != nil || !running {
+		return err
+	}
+	client, err := addons.NewClient()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for _, name := range names {
+		fmt.Printf("Waiting for %s to be ready...\n", name)
+		if err := addons.WaitReady(client, assets.Addons[name], deadline.Sub(time.Now())); err != nil {
+			return errors.Wrapf(err, "%s is not ready after %s", name, timeout)
+		}
+	}
+	return nil
+}
+
+// IsValidAddonSet checks that an addon set property is of the form
+// addon-set.<set name> and lists existing addons.
+func IsValidAddonSet(name string, val string) error {
+	if strings.TrimPrefix(name, AddonSetPrefix) == "" {
+		return errors.Errorf("%s is not of the form %s<set name>", name, AddonSetPrefix)
+	}
+	_, err := parseAddonList(val)
+	return err
+}
+
+// IsValidAddonProfile checks that the addon set is defined.
+func IsValidAddonProfile(name string, val string) error {
+	_, err := addonSet(val)
+	return err
+}
+
+// ApplyAddonProfile enables the addons of the set, all of them or none.
+func ApplyAddonProfile(name string, val string) error {
+	names, err := addonSet(val)
+	if err != nil {
+		return err
+	}
+	if err := enableAddons(names, defaultAddonWaitTimeout); err != nil {
+		return errors.Wrapf(err, "Error applying addon profile %s", val)
+	}
+	fmt.Printf("Addon profile %s applied, %s enabled\n", val, strings.Join(names, ", "))
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestParseAddonList(t *testing.T) {
+	var tcs = []struct {
+		list      string
+		expected  []string
+		shouldErr bool
+	}{
+		{list: "dashboard,heapster", expected: []string{"dashboard", "heapster"}},
+		{list: " ingress , ", expected: []string{"ingress"}},
+		{list: "dashboard,nope", shouldErr: true},
+		{list: ",", shouldErr: true},
+	}
+	for _, test := range tcs {
+		names, err := parseAddonList(test.list)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %q: %v", test.list, err)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("Expected %v for %q, got %v", test.expected, test.list, names)
+		}
+	}
+}
+
+func TestAddonProfileNoVM(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	// The global config file is resolved before the temp dir is set, the
+	// one of a profile is not
+	defer viper.Reset()
+	viper.Set(pkgConfig.ProfileFlag, "addons-test")
+
+	if err := Set(AddonProfileSetting, "web-dev"); err == nil {
+		t.Fatalf("Expected an error for an addon set which is not defined")
+	}
+	if err := Set(AddonSetPrefix+"web-dev", "dashboard,nope"); err == nil {
+		t.Fatalf("Expected an error for an addon set with an unknown addon")
+	}
+	if err := Set(AddonSetPrefix+"web-dev", "ingress,heapster"); err != nil {
+		t.Fatalf("Unexpected error defining the addon set: %s", err)
+	}
+	// Without a VM, the addons are enabled for the next start
+	if err := Set(AddonProfileSetting, "web-dev"); err != nil {
+		t.Fatalf("Unexpected error applying the addon profile: %s", err)
+	}
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		t.Fatalf("Unexpected error reading the config: %s", err)
+	}
+	for k, v := range map[string]interface{}{"ingress": true, "heapster": true, AddonProfileSetting: "web-dev"} {
+		if m[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, m[k])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+var addonsImagesCmd = &cobra.Command{
+	Use:   "images ADDON_NAME",
+	Short: "Lists the container images referenced by the addon w/ADDON_NAME (example: minikube addons images ingress)",
+	Long: `Lists the container images referenced by the addon w/ADDON_NAME (example: minikube addons images ingress).
+The images reflect any overrides set when enabling the addon, so they can be pulled and mirrored ahead of time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube addons images ADDON_NAME")
+			os.Exit(1)
+		}
+		if err := validateAddonName(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		addon := assets.Addons[args[0]]
+		images, err := addon.ReferencedImages()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing images of addon %s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+		for _, image := range images {
+			fmt.Println(image)
+		}
+	},
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsImagesCmd)
+}
@@ -20,13 +20,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/banner"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/dockerdaemon"
+	"k8s.io/minikube/pkg/minikube/fleet"
+	"k8s.io/minikube/pkg/minikube/hooks"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
+	"k8s.io/minikube/pkg/minikube/integrations"
+	"k8s.io/minikube/pkg/minikube/namespaces"
+	"k8s.io/minikube/pkg/minikube/seed"
+	"k8s.io/minikube/pkg/minikube/share"
+	"k8s.io/minikube/pkg/minikube/usage"
+	"k8s.io/minikube/pkg/util"
 )
 
 const useVendoredDriver = "use-vendored-driver"
@@ -38,16 +51,19 @@ type Setting struct {
 	set         func(config.MinikubeConfig, string, string) error
 	validations []setFn
 	callbacks   []setFn
+	// possibleValues lists the valid values, when they are known
+	possibleValues func() ([]string, error)
 }
 
 // These are all the settings that are configurable
 // and their validation and callback fn run on Set
 var settings = []Setting{
 	{
-		name:        "vm-driver",
-		set:         SetString,
-		validations: []setFn{IsValidDriver},
-		callbacks:   []setFn{RequiresRestartMsg},
+		name:           "vm-driver",
+		set:            SetString,
+		validations:    []setFn{IsValidDriver},
+		callbacks:      []setFn{RequiresRestartMsg},
+		possibleValues: supportedVMDrivers,
 	},
 	{
 		name:        "v",
@@ -66,10 +82,33 @@ var settings = []Setting{
 		validations: []setFn{IsValidDiskSize},
 		callbacks:   []setFn{RequiresRestartMsg},
 	},
+	{
+		name:           assets.AddonApplyModeSetting,
+		set:            SetString,
+		validations:    []setFn{IsValidAddonApplyMode},
+		possibleValues: addonApplyModes,
+	},
 	{
 		name:        "host-only-cidr",
 		set:         SetString,
-		validations: []setFn{IsValidCIDR},
+		validations: []setFn{IsValidHostOnlyCIDR},
+	},
+	{
+		name:        "pod-network-cidr",
+		set:         SetString,
+		validations: []setFn{IsValidPodNetworkCIDR},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        config.ServiceClusterIPRangeFlag,
+		set:         SetString,
+		validations: []setFn{IsValidServiceClusterIPRange},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        "static-ip",
+		set:         SetString,
+		validations: []setFn{IsValidIP},
 	},
 	{
 		name:        "memory",
@@ -77,20 +116,139 @@ var settings = []Setting{
 		validations: []setFn{IsPositive},
 		callbacks:   []setFn{RequiresRestartMsg},
 	},
+	{
+		name:        usage.MaxCPUsSetting,
+		set:         SetInt,
+		validations: []setFn{IsNonNegative},
+	},
+	{
+		name:        usage.MaxMemorySetting,
+		set:         SetInt,
+		validations: []setFn{IsNonNegative},
+	},
+	{
+		name:        usage.MaxDiskSizeSetting,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        "system-reserved",
+		set:         SetString,
+		validations: []setFn{IsValidReservedResources},
+	},
+	{
+		name:        "kube-reserved",
+		set:         SetString,
+		validations: []setFn{IsValidReservedResources},
+	},
+	{
+		name:        "eviction-hard",
+		set:         SetString,
+		validations: []setFn{IsValidEvictionThresholds},
+	},
+	{
+		name:        "enable-swap",
+		set:         SetString,
+		validations: []setFn{IsValidSwapSize},
+	},
+	{
+		name:        "hugepages",
+		set:         SetInt,
+		validations: []setFn{IsNonNegative},
+	},
+	{
+		name:           "kernel-variant",
+		set:            SetString,
+		validations:    []setFn{IsValidKernelVariant},
+		callbacks:      []setFn{RequiresRestartMsg},
+		possibleValues: kernelVariants,
+	},
+	{
+		name:           util.CryptoModeSetting,
+		set:            SetString,
+		validations:    []setFn{IsValidCryptoMode},
+		callbacks:      []setFn{RequiresRestartMsg},
+		possibleValues: cryptoModes,
+	},
 	{
 		name:        "log_dir",
 		set:         SetString,
 		validations: []setFn{IsValidPath},
 	},
 	{
-		name: "kubernetes-version",
-		set:  SetString,
+		name:           "kubernetes-version",
+		set:            SetString,
+		possibleValues: kubernetesVersions,
+	},
+	{
+		name:           "container-runtime",
+		set:            SetString,
+		validations:    []setFn{IsValidContainerRuntime},
+		callbacks:      []setFn{RequiresRestartMsg},
+		possibleValues: containerRuntimes,
+	},
+	{
+		name:        "apiserver-port",
+		set:         SetInt,
+		validations: []setFn{IsValidPort},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        "apiserver-listen-address",
+		set:         SetString,
+		validations: []setFn{IsValidIP},
+		callbacks:   []setFn{RequiresRestartMsg},
 	},
 	{
 		name:        "iso-url",
 		set:         SetString,
 		validations: []setFn{IsValidURL},
 	},
+	{
+		name: "preload",
+		set:  SetBool,
+	},
+	{
+		name: "offline",
+		set:  SetBool,
+	},
+	{
+		name:        namespaces.Setting,
+		set:         SetString,
+		validations: []setFn{IsValidNamespaces},
+		callbacks:   []setFn{RequiresStartMsg},
+	},
+	{
+		name: hostmutation.Setting,
+		set:  SetBool,
+	},
+	{
+		name:           integrations.Setting,
+		set:            SetString,
+		validations:    []setFn{IsValidIntegrations},
+		possibleValues: integrations.List,
+	},
+	{
+		name:        banner.Setting,
+		set:         SetString,
+		validations: []setFn{IsValidStartMessage},
+	},
+	{
+		name: hooks.PreStart.Setting(),
+		set:  SetString,
+	},
+	{
+		name: hooks.PostStart.Setting(),
+		set:  SetString,
+	},
+	{
+		name: hooks.PreStop.Setting(),
+		set:  SetString,
+	},
+	{
+		name: hooks.PostDelete.Setting(),
+		set:  SetString,
+	},
 	{
 		name: config.WantUpdateNotification,
 		set:  SetBool,
@@ -129,6 +287,12 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "default-storageclass",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
 	{
 		name:        "heapster",
 		set:         SetBool,
@@ -141,12 +305,36 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "ingress-dns",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
 	{
 		name:        "registry-creds",
 		set:         SetBool,
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "auto-pause",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "cloud-creds",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        AddonProfileSetting,
+		set:         SetString,
+		validations: []setFn{IsValidAddonProfile},
+		callbacks:   []setFn{ApplyAddonProfile},
+	},
 	{
 		name: "hyperv-virtual-switch",
 		set:  SetString,
@@ -155,6 +343,42 @@ var settings = []Setting{
 		name: useVendoredDriver,
 		set:  SetBool,
 	},
+	{
+		name:        share.RelaySetting,
+		set:         SetString,
+		validations: []setFn{IsValidShareRelay},
+	},
+	{
+		name:        share.RelayKeySetting,
+		set:         SetString,
+		validations: []setFn{IsValidPath},
+	},
+	{
+		name:        share.RelayHostKeySetting,
+		set:         SetString,
+		validations: []setFn{IsValidSSHPublicKey},
+	},
+	{
+		name:        fleet.EndpointSetting,
+		set:         SetString,
+		validations: []setFn{IsValidFleetEndpoint},
+	},
+	{
+		name: fleet.TokenSetting,
+		set:  SetString,
+	},
+	{
+		name:        constants.CacheDirSetting,
+		set:         SetString,
+		validations: []setFn{IsGlobalSetting, IsAbsolutePath},
+		callbacks:   []setFn{RequiresMigrateDirsMsg},
+	},
+	{
+		name:        constants.StateDirSetting,
+		set:         SetString,
+		validations: []setFn{IsGlobalSetting, IsAbsolutePath},
+		callbacks:   []setFn{RequiresMigrateDirsMsg},
+	},
 }
 
 var ConfigCmd = &cobra.Command{
@@ -172,19 +396,28 @@ func configurableFields() string {
 	for _, s := range settings {
 		fields = append(fields, " * "+s.name)
 	}
+	fields = append(fields, " * "+assets.AddonValuePrefix+"<addon name>.<key> (template values for addon manifests)")
+	fields = append(fields, " * "+seed.SecretPrefix+"<namespace>/<name>, "+seed.ConfigMapPrefix+"<namespace>/<name> (KEY=file:PATH,KEY=env:VAR,... created on start)")
+	fields = append(fields, " * "+namespaces.Prefix+"<namespace>.labels, .quota, .role-bindings (KEY=VALUE,..., ROLE=user:NAME,... set up on start)")
+	fields = append(fields, " * "+dockerdaemon.Prefix+"<"+strings.Join(dockerdaemon.Fields, "|")+"> (daemon.json of the docker daemon of the VM, written on start)")
+	fields = append(fields, " * "+AddonSetPrefix+"<set name> (comma separated addons, enabled together with "+AddonProfileSetting+")")
 	return strings.Join(fields, "\n")
 }
 
-// WriteConfig writes a minikube config to the JSON file
+// WriteConfig writes a minikube config to the JSON file of the active profile
 func WriteConfig(m config.MinikubeConfig) error {
-	f, err := os.Create(constants.ConfigFile)
+	path := config.File()
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("Could not create directory for %s: %s", path, err)
+	}
+	f, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("Could not open file %s: %s", constants.ConfigFile, err)
+		return fmt.Errorf("Could not open file %s: %s", path, err)
 	}
 	defer f.Close()
 	err = encode(f, m)
 	if err != nil {
-		return fmt.Errorf("Error encoding config %s: %s", constants.ConfigFile, err)
+		return fmt.Errorf("Error encoding config %s: %s", path, err)
 	}
 	return nil
 }
@@ -18,7 +18,12 @@ package config
 
 import (
 	"bytes"
+	"os"
+	"reflect"
 	"testing"
+
+	"github.com/spf13/cobra"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 )
 
 type configTestCase struct {
@@ -70,3 +75,65 @@ func TestWriteConfig(t *testing.T) {
 		b.Reset()
 	}
 }
+
+func TestConfigViewEntries(t *testing.T) {
+	root := &cobra.Command{Use: "minikube"}
+	start := &cobra.Command{Use: "start"}
+	start.Flags().Int("memory", 2048, "")
+	start.Flags().Int("cpus", 2, "")
+	root.AddCommand(start)
+
+	os.Setenv("MINIKUBE_KUBERNETES_VERSION", "v1.5.3")
+	defer os.Unsetenv("MINIKUBE_KUBERNETES_VERSION")
+
+	global := pkgConfig.MinikubeConfig{"memory": 4096, "cpus": 4, "kubernetes-version": "v1.5.2"}
+	profile := pkgConfig.MinikubeConfig{"memory": 8192, "custom-key": "val"}
+	entries := configViewEntries(root, global, profile, "dev")
+
+	byName := map[string]ConfigViewEntry{}
+	for i, e := range entries {
+		if i > 0 && entries[i-1].Name > e.Name {
+			t.Errorf("Expected entries to be sorted, got %s before %s", entries[i-1].Name, e.Name)
+		}
+		byName[e.Name] = e
+	}
+	for name, expected := range map[string]ConfigViewEntry{
+		"memory":             {Name: "memory", Value: 8192, Default: "2048", Source: SourceProfile, Profile: "dev"},
+		"cpus":               {Name: "cpus", Value: 4, Default: "2", Source: SourceGlobal},
+		"custom-key":         {Name: "custom-key", Value: "val", Source: SourceProfile, Profile: "dev"},
+		"vm-driver":          {Name: "vm-driver", Source: SourceDefault},
+		"kubernetes-version": {Name: "kubernetes-version", Value: "v1.5.3", Source: SourceEnv},
+	} {
+		if e := byName[name]; !reflect.DeepEqual(e, expected) {
+			t.Errorf("Unexpected entry for %s: expected %+v, got %+v", name, expected, e)
+		}
+	}
+}
+
+func TestWriteConfigView(t *testing.T) {
+	entries := []ConfigViewEntry{{Name: "memory", Value: 4096, Default: "2048", Source: SourceGlobal}}
+	for output, expected := range map[string]string{
+		"json": `[
+    {
+        "name": "memory",
+        "value": 4096,
+        "default": "2048",
+        "source": "global"
+    }
+]
+`,
+		"yaml": `- default: "2048"
+  name: memory
+  source: global
+  value: 4096
+`,
+	} {
+		var b bytes.Buffer
+		if err := writeConfigView(&b, output, entries); err != nil {
+			t.Fatalf("Error writing %s: %s", output, err)
+		}
+		if b.String() != expected {
+			t.Errorf("Unexpected %s output, expected:\n%s\ngot:\n%s", output, expected, b.String())
+		}
+	}
+}
@@ -17,18 +17,44 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"text/template"
 
+	"github.com/ghodss/yaml"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
-var configViewFormat string
+var (
+	configViewFormat string
+	configViewOutput string
+)
+
+// Where the value of a setting comes from
+const (
+	SourceDefault = "default"
+	SourceGlobal  = "global"
+	SourceProfile = "profile"
+	SourceEnv     = "env"
+)
+
+// ConfigViewEntry describes a setting in the structured config view output.
+type ConfigViewEntry struct {
+	Name    string      `json:"name"`
+	Value   interface{} `json:"value"`
+	Default interface{} `json:"default,omitempty"`
+	Source  string      `json:"source"`
+	Profile string      `json:"profile,omitempty"`
+}
 
 type ConfigViewTemplate struct {
 	ConfigKey   string
@@ -40,7 +66,15 @@ var configViewCmd = &cobra.Command{
 	Short: "Display values currently set in the minikube config file",
 	Long:  "Display values currently set in the minikube config file.",
 	Run: func(cmd *cobra.Command, args []string) {
-		err := configView()
+		var err error
+		switch configViewOutput {
+		case "":
+			err = configView()
+		case "json", "yaml":
+			err = configViewStructured(os.Stdout, configViewOutput, cmd.Root())
+		default:
+			err = fmt.Errorf("Invalid output format %q, expected json or yaml", configViewOutput)
+		}
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -52,6 +86,8 @@ func init() {
 	configViewCmd.Flags().StringVar(&configViewFormat, "format", constants.DefaultConfigViewFormat,
 		`Go template format string for the config view output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd/config#ConfigViewTemplate`)
+	configViewCmd.Flags().StringVarP(&configViewOutput, "output", "o", "",
+		"Output format, one of: json|yaml. Unlike --format, this includes the settings left to their defaults and where each value comes from")
 	ConfigCmd.AddCommand(configViewCmd)
 }
 
@@ -75,3 +111,94 @@ func configView() error {
 	}
 	return nil
 }
+
+func configViewStructured(w io.Writer, output string, root *cobra.Command) error {
+	global, err := config.ReadFile(constants.ConfigFile)
+	if err != nil {
+		return err
+	}
+	profileName := config.ActiveProfile()
+	profile := config.MinikubeConfig{}
+	if profileName != "" {
+		if profile, err = config.ReadFile(config.ProfileConfigFile(profileName)); err != nil {
+			return err
+		}
+	}
+	return writeConfigView(w, output, configViewEntries(root, global, profile, profileName))
+}
+
+func writeConfigView(w io.Writer, output string, entries []ConfigViewEntry) error {
+	var b []byte
+	var err error
+	if output == "json" {
+		b, err = json.MarshalIndent(entries, "", "    ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(entries)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error encoding config as %s", output)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// configViewEntries lists the configurable settings along with the keys set in
+// the global and profile configs, sorted by name. Values overridden by
+// environment variables take precedence over the config files.
+func configViewEntries(root *cobra.Command, global, profile config.MinikubeConfig, profileName string) []ConfigViewEntry {
+	names := map[string]bool{}
+	for _, s := range settings {
+		names[s.name] = true
+	}
+	for _, m := range []config.MinikubeConfig{global, profile} {
+		for k := range m {
+			names[k] = true
+		}
+	}
+
+	entries := []ConfigViewEntry{}
+	for name := range names {
+		e := ConfigViewEntry{
+			Name:    name,
+			Default: settingDefault(root, name),
+		}
+		if v, ok := os.LookupEnv(config.EnvVar(name)); ok {
+			e.Value, e.Source = v, SourceEnv
+		} else if v, ok := profile[name]; ok {
+			e.Value, e.Source, e.Profile = v, SourceProfile, profileName
+		} else if v, ok := global[name]; ok {
+			e.Value, e.Source = v, SourceGlobal
+		} else {
+			e.Value, e.Source = e.Default, SourceDefault
+		}
+		entries = append(entries, e)
+	}
+	sort.Sort(configViewEntriesByName(entries))
+	return entries
+}
+
+// settingDefault returns the default of the flag with the same name as the
+// setting, or the viper default for settings which aren't flags.
+func settingDefault(root *cobra.Command, name string) interface{} {
+	if root != nil {
+		if f := root.PersistentFlags().Lookup(name); f != nil {
+			return f.DefValue
+		}
+		for _, c := range root.Commands() {
+			if f := c.Flags().Lookup(name); f != nil {
+				return f.DefValue
+			}
+		}
+	}
+	if viper.InConfig(name) {
+		return nil
+	}
+	return viper.Get(name)
+}
+
+type configViewEntriesByName []ConfigViewEntry
+
+func (e configViewEntriesByName) Len() int           { return len(e) }
+func (e configViewEntriesByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e configViewEntriesByName) Less(i, j int) bool { return e[i].Name < e[j].Name }
@@ -0,0 +1,118 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/kubernetes_versions"
+	"k8s.io/minikube/pkg/util"
+)
+
+var configDefaultsCmd = &cobra.Command{
+	Use:   "defaults PROPERTY_NAME",
+	Short: "Lists the possible values of PROPERTY_NAME",
+	Long: `Lists the possible values of PROPERTY_NAME, one per line (example: minikube config defaults vm-driver).
+The available Kubernetes versions are looked up online.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube config defaults PROPERTY_NAME")
+			os.Exit(1)
+		}
+		if err := printPossibleValues(os.Stdout, args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(configDefaultsCmd)
+}
+
+func printPossibleValues(w io.Writer, name string) error {
+	s, err := findSetting(name)
+	if err != nil {
+		return err
+	}
+	values, err := possibleValues(s)
+	if err != nil {
+		return err
+	}
+	if values == nil {
+		return errors.Errorf("%s accepts any value that passes its validation, there is no list of possible values", name)
+	}
+	for _, v := range values {
+		fmt.Fprintln(w, v)
+	}
+	return nil
+}
+
+// possibleValues returns the valid values of the setting: those of its
+// provider, or true and false for boolean settings such as addons, or nil
+// when they aren't known.
+func possibleValues(s Setting) ([]string, error) {
+	if s.possibleValues != nil {
+		return s.possibleValues()
+	}
+	if reflect.ValueOf(s.set).Pointer() == reflect.ValueOf(SetBool).Pointer() {
+		return []string{"true", "false"}, nil
+	}
+	return nil, nil
+}
+
+func supportedVMDrivers() ([]string, error) {
+	return constants.SupportedVMDrivers[:], nil
+}
+
+func containerRuntimes() ([]string, error) {
+	return constants.SupportedContainerRuntimes, nil
+}
+
+func kernelVariants() ([]string, error) {
+	return []string{constants.KernelVariantDefault, constants.KernelVariantRT}, nil
+}
+
+func cryptoModes() ([]string, error) {
+	return util.CryptoModes, nil
+}
+
+func addonApplyModes() ([]string, error) {
+	return []string{assets.ApplyModeSSH, assets.ApplyModeAPI}, nil
+}
+
+// kubernetesVersionsURL is where the available Kubernetes releases are listed
+var kubernetesVersionsURL = constants.KubernetesVersionGCSURL
+
+func kubernetesVersions() ([]string, error) {
+	releases, err := kubernetes_versions.GetK8sVersionsFromURL(kubernetesVersionsURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the available Kubernetes versions")
+	}
+	versions := make([]string, 0, len(releases))
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+	}
+	return versions, nil
+}
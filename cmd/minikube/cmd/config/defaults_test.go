@@ -0,0 +1,80 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrintPossibleValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"version": "v1.5.3"}, {"version": "v1.5.2"}]`)
+	}))
+	defer server.Close()
+	defer func(url string) { kubernetesVersionsURL = url }(kubernetesVersionsURL)
+	kubernetesVersionsURL = server.URL
+
+	var tcs = []struct {
+		name      string
+		expected  string
+		shouldErr bool
+	}{
+		{name: "kubernetes-version", expected: "v1.5.3\nv1.5.2\n"},
+		{name: "container-runtime", expected: "docker\nrkt\nremote\n"},
+		{name: "kernel-variant", expected: "default\nrt\n"},
+		{name: "crypto-mode", expected: "default\nfips\n"},
+		{name: "dashboard", expected: "true\nfalse\n"},
+		{name: "memory", shouldErr: true},
+		{name: "memry", shouldErr: true},
+	}
+	for _, test := range tcs {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := printPossibleValues(&b, test.name)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected values %q, got %q", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestPossibleValuesAreValid(t *testing.T) {
+	for _, s := range settings {
+		if s.possibleValues == nil || s.name == "kubernetes-version" {
+			continue
+		}
+		values, err := possibleValues(s)
+		if err != nil {
+			t.Fatalf("Unexpected error listing the values of %s: %s", s.name, err)
+		}
+		for _, v := range values {
+			if err := run(s.name, v, s.validations); err != nil {
+				t.Errorf("Possible value %s of %s is not valid: %s", v, s.name, err)
+			}
+		}
+	}
+}
@@ -34,6 +34,10 @@ var addonsDisableCmd = &cobra.Command{
 		}
 
 		addon := args[0]
+		if err := validateAddonName(addon); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		err := Set(addon, "false")
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
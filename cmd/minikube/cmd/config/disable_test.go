@@ -23,10 +23,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/docker/machine/libmachine/drivers"
-
-	"k8s.io/minikube/pkg/minikube/assets"
-	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/minikube/tests"
 )
 
@@ -59,31 +55,3 @@ func TestDisableValidAddonNoVM(t *testing.T) {
 		}
 	}
 }
-
-func TestDeleteAddonViaDriver(t *testing.T) {
-	s, _ := tests.NewSSHServer()
-	port, err := s.Start()
-	if err != nil {
-		t.Fatalf("Error starting ssh server: %s", err)
-	}
-
-	d := &tests.MockDriver{
-		Port: port,
-		BaseDriver: drivers.BaseDriver{
-			IPAddress:  "127.0.0.1",
-			SSHKeyPath: "",
-		},
-	}
-
-	dashboard := assets.Addons["dashboard"]
-	if err := deleteAddonViaDriver(dashboard, d); err != nil {
-		t.Fatalf("Unexpected error %s deleting addon", err)
-	}
-	// check command(s) were run
-	for _, addon := range dashboard.Assets {
-		expected, _ := ioutil.ReadFile(addon.GetAssetName())
-		if _, ok := s.Commands[sshutil.GetDeleteFileCommand(addon)]; !ok {
-			t.Fatalf("Error: Expected delete addon ssh command to be run: %s.", expected)
-		}
-	}
-}
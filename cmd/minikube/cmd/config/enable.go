@@ -19,21 +19,54 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cloudcreds"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+)
+
+var (
+	addonImages     string
+	addonRegistries string
+	addonProvider   string
+	addonSetList    string
+	addonWait       time.Duration
 )
 
 var addonsEnableCmd = &cobra.Command{
 	Use:   "enable ADDON_NAME",
 	Short: "Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list ",
-	Long:  "Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list ",
+	Long: `Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list
+
+With --set, enables several addons together (example: minikube addons enable --set ingress,heapster), then waits for their
+pods to be ready. If any addon fails to be enabled or to be ready within --wait-timeout, the addons it enabled are disabled again.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if addonSetList != "" {
+			enableAddonSet(args)
+			return
+		}
 		if len(args) != 1 {
-			fmt.Fprintln(os.Stderr, "usage: minikube addons enable ADDON_NAME")
+			fmt.Fprintln(os.Stderr, "usage: minikube addons enable ADDON_NAME | --set ADDON,ADDON,...")
 			os.Exit(1)
 		}
 
 		addon := args[0]
+		if err := validateAddonName(addon); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := setAddonImages(addon, addonImages, addonRegistries); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := setAddonProvider(addon, addonProvider); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		err := Set(addon, "true")
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
@@ -43,6 +76,101 @@ var addonsEnableCmd = &cobra.Command{
 	},
 }
 
+// enableAddonSet enables the addons of --set, all of them or none.
+func enableAddonSet(args []string) {
+	if len(args) != 0 || addonImages != "" || addonRegistries != "" || addonProvider != "" {
+		fmt.Fprintln(os.Stderr, "--set takes no addon name, nor --images, --registries or --provider which apply to a single addon")
+		os.Exit(1)
+	}
+	names, err := parseAddonList(addonSetList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := enableAddons(names, addonWait); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stdout, "%s were successfully enabled\n", strings.Join(names, ", "))
+}
+
+// parseImageOverrides parses a comma separated list of NAME=VALUE pairs
+func parseImageOverrides(addon *assets.Addon, overrides string) (map[string]string, error) {
+	m := map[string]string{}
+	if overrides == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(overrides, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, errors.Errorf("%s is not of the form NAME=VALUE", pair)
+		}
+		if _, ok := addon.Images[kv[0]]; !ok {
+			return nil, errors.Errorf("%s is not an image of the addon, valid names are: %v", kv[0], addon.ImageNames())
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// setAddonImages persists the image and registry overrides for the addon
+// so that they are applied whenever its manifests are copied into the VM.
+func setAddonImages(name, images, registries string) error {
+	if images == "" && registries == "" {
+		return nil
+	}
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return errors.Errorf("%s is not a valid addon", name)
+	}
+	imageOverrides, err := parseImageOverrides(addon, images)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing --images")
+	}
+	registryOverrides, err := parseImageOverrides(addon, registries)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing --registries")
+	}
+
+	config, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	prefix := assets.AddonValuePrefix + name + "."
+	for k, v := range imageOverrides {
+		config[prefix+assets.ImageValuePrefix+k] = v
+	}
+	for k, v := range registryOverrides {
+		config[prefix+assets.RegistryValuePrefix+k] = v
+	}
+	return WriteConfig(config)
+}
+
+// setAddonProvider persists the cloud provider whose credentials the
+// cloud-creds addon passes through.
+func setAddonProvider(name, provider string) error {
+	if provider == "" {
+		return nil
+	}
+	if name != cloudcreds.AddonName {
+		return errors.Errorf("--provider only applies to the %s addon", cloudcreds.AddonName)
+	}
+	if err := cloudcreds.ValidateProvider(provider); err != nil {
+		return err
+	}
+	config, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	config[assets.AddonValuePrefix+name+".provider"] = provider
+	return WriteConfig(config)
+}
+
 func init() {
+	addonsEnableCmd.Flags().StringVar(&addonImages, "images", "", "Images used by the addon instead of the defaults (format: NAME=REPOSITORY:TAG,...)")
+	addonsEnableCmd.Flags().StringVar(&addonProvider, "provider", "", fmt.Sprintf("The cloud provider whose credentials the %s addon passes through, one of %v", cloudcreds.AddonName, cloudcreds.Providers))
+	addonsEnableCmd.Flags().StringVar(&addonSetList, "set", "", "Addons to enable together, all of them or none (format: ADDON,ADDON,...)")
+	addonsEnableCmd.Flags().DurationVar(&addonWait, "wait-timeout", defaultAddonWaitTimeout, "How long to wait for the addons of --set to be ready before disabling them again, 0 to not wait")
+	addonsEnableCmd.Flags().StringVar(&addonRegistries, "registries", "", "Registries the addon images are pulled from instead of the defaults (format: NAME=REGISTRY,...)")
 	AddonsCmd.AddCommand(addonsEnableCmd)
 }
@@ -17,15 +17,13 @@ limitations under the License.
 package config
 
 import (
-	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
-	"github.com/docker/machine/libmachine/drivers"
-
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/tests"
 )
@@ -59,32 +57,47 @@ func TestEnableValidAddonNoVM(t *testing.T) {
 	}
 }
 
-func TestTransferAddonViaDriver(t *testing.T) {
-	s, _ := tests.NewSSHServer()
-	port, err := s.Start()
-	if err != nil {
-		t.Fatalf("Error starting ssh server: %s", err)
-	}
-
-	d := &tests.MockDriver{
-		Port: port,
-		BaseDriver: drivers.BaseDriver{
-			IPAddress:  "127.0.0.1",
-			SSHKeyPath: "",
+func TestParseImageOverrides(t *testing.T) {
+	ingress := assets.Addons["ingress"]
+	var tests = []struct {
+		overrides string
+		expected  map[string]string
+		shouldErr bool
+	}{
+		{
+			overrides: "",
+			expected:  map[string]string{},
+		},
+		{
+			overrides: "Controller=myreg.local/ingress:1.2",
+			expected:  map[string]string{"Controller": "myreg.local/ingress:1.2"},
+		},
+		{
+			overrides: "Controller=myreg.local,DefaultBackend=otherreg.local",
+			expected:  map[string]string{"Controller": "myreg.local", "DefaultBackend": "otherreg.local"},
+		},
+		{
+			overrides: "Controller",
+			shouldErr: true,
+		},
+		{
+			overrides: "Unknown=myreg.local",
+			shouldErr: true,
 		},
 	}
 
-	dashboard := assets.Addons["dashboard"]
-	if err := transferAddonViaDriver(dashboard, d); err != nil {
-		t.Fatalf("Unexpected error %s transferring addon", err)
-	}
-	// check contents
-	for _, addon := range dashboard.Assets {
-		expected, _ := ioutil.ReadFile(addon.GetAssetName())
-		transferred := s.Transfers.Bytes()
-		//test that custom addons are transferred properly
-		if !bytes.Contains(transferred, expected) {
-			t.Fatalf("Expected transfers to contain addon with content: %s. It was: %s", expected, transferred)
+	for _, test := range tests {
+		m, err := parseImageOverrides(ingress, test.overrides)
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error parsing %q: %s", test.overrides, err)
+			continue
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected error parsing %q but got none", test.overrides)
+			continue
+		}
+		if !reflect.DeepEqual(m, test.expected) && !test.shouldErr {
+			t.Errorf("Expected %v, got %v", test.expected, m)
 		}
 	}
 }
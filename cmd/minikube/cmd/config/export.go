@@ -0,0 +1,69 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+)
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Prints the minikube config, including which addons are enabled",
+	Long: `Prints the minikube config as JSON, including the enabled state of every addon, so that it can be shared and loaded with "minikube config import".
+When a profile is active, its values are merged with the global ones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube config export")
+			os.Exit(1)
+		}
+		if err := export(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(configExportCmd)
+}
+
+func export(w io.Writer) error {
+	m, err := pkgConfig.ReadConfig()
+	if err != nil {
+		return err
+	}
+	// Addons left to their default are exported too, the defaults could
+	// differ in the minikube version importing the config
+	for name, addon := range assets.Addons {
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			return err
+		}
+		m[name] = enabled
+	}
+	if err := encode(w, m); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
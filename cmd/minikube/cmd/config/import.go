@@ -0,0 +1,175 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+)
+
+var configImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Loads settings and enabled addons from a file written by \"minikube config export\"",
+	Long: `Loads settings and enabled addons from a file written by "minikube config export", or "-" for stdin.
+Every value is validated before any is written, and addons are enabled or disabled as in "minikube config set".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube config import FILE")
+			os.Exit(1)
+		}
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			r = f
+		}
+		if err := importConfig(r); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(configImportCmd)
+}
+
+// importedValue is a property read from an exported config.
+type importedValue struct {
+	setting Setting
+	name    string
+	value   string
+}
+
+func importConfig(r io.Reader) error {
+	values, err := readImport(r)
+	if err != nil {
+		return err
+	}
+
+	// Callbacks only run for the properties which change, importing the
+	// config again shouldn't enable every addon once more
+	var changed []importedValue
+	for _, v := range values {
+		current, err := currentValue(v.name)
+		if err != nil {
+			return err
+		}
+		if current != v.value {
+			changed = append(changed, v)
+		}
+	}
+
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := v.setting.set(m, v.name, v.value); err != nil {
+			return errors.Wrapf(err, "Error setting %s", v.name)
+		}
+	}
+	if err := WriteConfig(m); err != nil {
+		return err
+	}
+
+	printed := map[uintptr]bool{}
+	for _, v := range changed {
+		if err := runCallbacks(v.setting, v.name, v.value, printed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentValue returns the value of the property before the import, which
+// for addons is their enabled state even when left to the default.
+func currentValue(name string) (string, error) {
+	if addon, ok := assets.Addons[name]; ok {
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(enabled), nil
+	}
+	v, err := pkgConfig.Get(name)
+	if err != nil {
+		// The property isn't set
+		return "", nil
+	}
+	return v, nil
+}
+
+// readImport decodes and validates every property of an exported config.
+// Addons are sorted last, so that settings like addon-apply-mode are in
+// place before they are enabled.
+func readImport(r io.Reader) ([]importedValue, error) {
+	d := json.NewDecoder(r)
+	// Keep numbers as written, instead of formatting them back from floats
+	d.UseNumber()
+	var m map[string]interface{}
+	if err := d.Decode(&m); err != nil {
+		return nil, errors.Wrap(err, "Error decoding config")
+	}
+
+	var values []importedValue
+	var errs []error
+	for name, raw := range m {
+		s, err := findSetting(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		value := fmt.Sprintf("%v", raw)
+		if err := run(name, value, s.validations); err != nil {
+			errs = append(errs, errors.Wrapf(err, "Invalid value for %s", name))
+			continue
+		}
+		values = append(values, importedValue{s, name, value})
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%v", errs)
+	}
+	sort.Sort(importedValues(values))
+	return values, nil
+}
+
+type importedValues []importedValue
+
+func (v importedValues) Len() int      { return len(v) }
+func (v importedValues) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v importedValues) Less(i, j int) bool {
+	_, iAddon := assets.Addons[v[i].name]
+	_, jAddon := assets.Addons[v[j].name]
+	if iAddon != jAddon {
+		return jAddon
+	}
+	return v[i].name < v[j].name
+}
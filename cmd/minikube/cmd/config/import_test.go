@@ -0,0 +1,72 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReadImport(t *testing.T) {
+	var tcs = []struct {
+		description string
+		data        string
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "settings before addons",
+			data:        `{"dashboard": false, "memory": 4096, "addon-apply-mode": "api", "heapster": true}`,
+			expected:    []string{"addon-apply-mode=api", "memory=4096", "dashboard=false", "heapster=true"},
+		},
+		{
+			description: "invalid value",
+			data:        `{"memory": -1, "cpus": 2}`,
+			shouldErr:   true,
+		},
+		{
+			description: "unknown property",
+			data:        `{"not-a-setting": 1}`,
+			shouldErr:   true,
+		},
+		{
+			description: "not json",
+			data:        `memory: 4096`,
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			values, err := readImport(bytes.NewBufferString(test.data))
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			var actual []string
+			for _, v := range values {
+				actual = append(actual, v.name+"="+v.value)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected values %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -65,14 +65,14 @@ var addonsOpenCmd = &cobra.Command{
 		defer api.Close()
 
 		cluster.EnsureMinikubeRunningOrExit(api, 1)
-		addon, ok := assets.Addons[addonName] // validate addon input
-		if !ok {
-			fmt.Fprintln(os.Stderr, fmt.Sprintf(`addon '%s' is not a valid addon packaged with minikube.
+		if err := validateAddonName(addonName); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`%s
 To see the list of available addons run:
-minikube addons list`, addonName))
+minikube addons list`, err))
 			os.Exit(1)
 		}
-		ok, err = addon.IsEnabled()
+		addon := assets.Addons[addonName]
+		ok, err := addon.IsEnabled()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err.Error())
 			os.Exit(1)
@@ -101,8 +101,10 @@ You can add one by annotating a service with the label %s:%s
 		}
 		for i := range serviceList.Items {
 			svc := serviceList.Items[i].ObjectMeta.Name
-			service.WaitAndMaybeOpenService(api, namespace, svc, addonsURLTemplate, addonsURLMode, https)
-
+			if err := service.WaitAndMaybeOpenService(api, namespace, svc, addonsURLTemplate, addonsURLMode, https, service.PortSelector{}); err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening addon %s: %s\n", addonName, err)
+				os.Exit(1)
+			}
 		}
 	},
 }
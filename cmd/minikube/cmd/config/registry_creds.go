@@ -0,0 +1,99 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/registrycreds"
+)
+
+// enableRegistryCreds asks for the credentials of the registries, or offers
+// to reuse the ones recorded for the profile, and writes the secrets of the
+// registry-creds addon.
+func enableRegistryCreds() error {
+	posResponses := []string{"yes", "y"}
+	negResponses := []string{"no", "n"}
+
+	answers, recorded, err := registrycreds.Load()
+	if err != nil {
+		return err
+	}
+	if !recorded || !AskForYesNoConfirmation("\nUse the registry credentials recorded for this profile?", posResponses, negResponses) {
+		answers = askRegistryCreds(posResponses, negResponses)
+		if err := recordRegistryCreds(answers); err != nil {
+			return err
+		}
+	}
+	return registrycreds.Apply(answers)
+}
+
+// askRegistryCreds asks for the credentials of each registry the addon
+// supports, leaving empty the ones not used.
+func askRegistryCreds(posResponses, negResponses []string) registrycreds.Answers {
+	var a registrycreds.Answers
+	if AskForYesNoConfirmation("\nDo you want to enable AWS Elastic Container Registry?", posResponses, negResponses) {
+		a.AWSAccessKeyID = AskForStaticValue("-- Enter AWS Access Key ID: ")
+		a.AWSSecretAccessKey = AskForStaticValue("-- Enter AWS Secret Access Key: ")
+		a.AWSRegion = AskForStaticValue("-- Enter AWS Region: ")
+		a.AWSAccount = AskForStaticValue("-- Enter 12 digit AWS Account ID: ")
+	}
+
+	if AskForYesNoConfirmation("\nDo you want to enable Google Container Registry?", posResponses, negResponses) {
+		gcrPath := AskForStaticValue("-- Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json):")
+
+		// Read file from disk
+		dat, err := ioutil.ReadFile(gcrPath)
+		if err != nil {
+			fmt.Println("Could not read file for application_default_credentials.json")
+		} else {
+			a.GCRCredentials = string(dat)
+		}
+	}
+
+	if AskForYesNoConfirmation("\nDo you want to enable Docker Registry?", posResponses, negResponses) {
+		a.DockerServer = AskForStaticValue("-- Enter docker registry server url: ")
+		a.DockerUser = AskForStaticValue("-- Enter docker registry username: ")
+		a.DockerPassword = AskForStaticValue("-- Enter docker registry password: ")
+	}
+	return a
+}
+
+// recordRegistryCreds records the answers for the profile: the ones which
+// are not secret as addon values in its config, the others in a file only the
+// user can read.
+func recordRegistryCreds(a registrycreds.Answers) error {
+	config, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	prefix := assets.AddonValuePrefix + registrycreds.AddonName + "."
+	for k, v := range a.Values() {
+		if v == "" {
+			delete(config, prefix+k)
+			continue
+		}
+		config[prefix+k] = v
+	}
+	if err := WriteConfig(config); err != nil {
+		return err
+	}
+	return registrycreds.SaveCredentials(a)
+}
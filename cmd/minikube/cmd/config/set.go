@@ -58,12 +58,8 @@ func Set(name string, value string) error {
 		return err
 	}
 
-	// Set the value
-	config, err := pkgConfig.ReadConfig()
-	if err != nil {
-		return err
-	}
-	err = s.set(config, name, value)
+	// Check the value can be set before running the callbacks
+	err = s.set(pkgConfig.MinikubeConfig{}, name, value)
 	if err != nil {
 		return err
 	}
@@ -74,6 +70,15 @@ func Set(name string, value string) error {
 		return err
 	}
 
-	// Write the value
+	// Write the value, to the config as left by the callbacks, which may
+	// set other properties such as the addons of an addon profile
+	config, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	err = s.set(config, name, value)
+	if err != nil {
+		return err
+	}
 	return WriteConfig(config)
 }
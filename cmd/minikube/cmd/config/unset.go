@@ -19,22 +19,33 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 
+	"k8s.io/minikube/pkg/minikube/assets"
 	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 
 	"github.com/spf13/cobra"
 )
 
+var unsetAll bool
+
 var configUnsetCmd = &cobra.Command{
 	Use:   "unset PROPERTY_NAME",
 	Short: "unsets an individual value in a minikube config file",
-	Long:  "unsets PROPERTY_NAME from the minikube config file.  Can be overwritten by flags or environmental variables",
+	Long: `unsets PROPERTY_NAME from the minikube config file, reverting it to its default.  Can be overwritten by flags or environmental variables.
+With --all, every value in the config file is unset.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 1 {
-			fmt.Fprintln(os.Stdout, "usage: minikube config unset PROPERTY_NAME")
+		var err error
+		switch {
+		case unsetAll && len(args) == 0:
+			err = unsetAllProperties()
+		case !unsetAll && len(args) == 1:
+			err = unset(args[0])
+		default:
+			fmt.Fprintln(os.Stdout, "usage: minikube config unset PROPERTY_NAME | --all")
 			os.Exit(1)
 		}
-		err := unset(args[0])
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
 		}
@@ -42,14 +53,63 @@ var configUnsetCmd = &cobra.Command{
 }
 
 func init() {
+	configUnsetCmd.Flags().BoolVar(&unsetAll, "all", false, "Unset every value in the config file")
 	ConfigCmd.AddCommand(configUnsetCmd)
 }
 
 func unset(name string) error {
-	m, err := pkgConfig.ReadConfig()
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
 	if err != nil {
 		return err
 	}
+	if _, ok := m[name]; !ok {
+		return nil
+	}
 	delete(m, name)
-	return WriteConfig(m)
+	if err := WriteConfig(m); err != nil {
+		return err
+	}
+	return runUnsetCallbacks(name, map[uintptr]bool{})
+}
+
+func unsetAllProperties() error {
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	if err := WriteConfig(pkgConfig.MinikubeConfig{}); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Messages are only printed once when unsetting several properties
+	printed := map[uintptr]bool{}
+	for _, name := range names {
+		if err := runUnsetCallbacks(name, printed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runUnsetCallbacks runs the callbacks of the setting with its default value.
+func runUnsetCallbacks(name string, printed map[uintptr]bool) error {
+	s, err := findSetting(name)
+	if err != nil {
+		// Unknown properties can still be unset, there is nothing to run for them
+		return nil
+	}
+	return runCallbacks(s, name, defaultValue(name), printed)
+}
+
+// defaultValue returns the value a property has once unset, as passed to
+// its callbacks.
+func defaultValue(name string) string {
+	if addon, ok := assets.Addons[name]; ok {
+		return strconv.FormatBool(addon.EnabledByDefault())
+	}
+	return ""
 }
@@ -0,0 +1,47 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+func TestDefaultValue(t *testing.T) {
+	for name, expected := range map[string]string{
+		"dashboard":      "true",
+		"registry-creds": "false",
+		"memory":         "",
+	} {
+		if v := defaultValue(name); v != expected {
+			t.Errorf("Expected default value %q for %s, got %q", expected, name, v)
+		}
+	}
+}
+
+func TestIsMessageCallback(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		fn          setFn
+		expected    bool
+	}{
+		{"restart message", RequiresRestartMsg, true},
+		{"addon reenable message", RequiresAddonReenableMsg, true},
+		{"addon callback", EnableOrDisableAddon, false},
+	} {
+		if isMessageCallback(tc.fn) != tc.expected {
+			t.Errorf("Expected isMessageCallback to be %t for the %s", tc.expected, tc.description)
+		}
+	}
+}
@@ -18,19 +18,27 @@ package config
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 
-	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/addons"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cloudcreds"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/dockerdaemon"
 	"k8s.io/minikube/pkg/minikube/machine"
-	"k8s.io/minikube/pkg/minikube/service"
-	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/minikube/namespaces"
+	"k8s.io/minikube/pkg/minikube/registrycreds"
+	"k8s.io/minikube/pkg/minikube/seed"
+	"k8s.io/minikube/pkg/minikube/webhooks"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Runs all the validation or callback functions and collects errors
@@ -48,13 +56,115 @@ func run(name string, value string, fns []setFn) error {
 	return nil
 }
 
+// runCallbacks runs the callbacks of the setting, skipping the message
+// callbacks already in printed so that they are only printed once when
+// changing several properties.
+func runCallbacks(s Setting, name string, value string, printed map[uintptr]bool) error {
+	var callbacks []setFn
+	for _, fn := range s.callbacks {
+		if isMessageCallback(fn) {
+			p := reflect.ValueOf(fn).Pointer()
+			if printed[p] {
+				continue
+			}
+			printed[p] = true
+		}
+		callbacks = append(callbacks, fn)
+	}
+	return run(name, value, callbacks)
+}
+
+// isMessageCallback reports whether the callback only prints a message,
+// regardless of the property it is run for.
+func isMessageCallback(fn setFn) bool {
+	p := reflect.ValueOf(fn).Pointer()
+	return p == reflect.ValueOf(RequiresRestartMsg).Pointer() ||
+		p == reflect.ValueOf(RequiresAddonReenableMsg).Pointer() ||
+		p == reflect.ValueOf(RequiresStartMsg).Pointer()
+}
+
 func findSetting(name string) (Setting, error) {
 	for _, s := range settings {
 		if name == s.name {
 			return s, nil
 		}
 	}
-	return Setting{}, fmt.Errorf("Property name %s not found", name)
+	// Template values for addon manifests are not known ahead of time
+	if strings.HasPrefix(name, assets.AddonValuePrefix) {
+		return Setting{
+			name:        name,
+			set:         SetString,
+			validations: []setFn{IsValidAddonValue},
+			callbacks:   []setFn{RequiresAddonReenableMsg},
+		}, nil
+	}
+	if strings.HasPrefix(name, seed.SecretPrefix) || strings.HasPrefix(name, seed.ConfigMapPrefix) {
+		return Setting{
+			name:        name,
+			set:         SetString,
+			validations: []setFn{IsValidSeed},
+			callbacks:   []setFn{RequiresStartMsg},
+		}, nil
+	}
+	if strings.HasPrefix(name, namespaces.Prefix) {
+		return Setting{
+			name:        name,
+			set:         SetString,
+			validations: []setFn{IsValidNamespaceProperty},
+			callbacks:   []setFn{RequiresStartMsg},
+		}, nil
+	}
+	if strings.HasPrefix(name, dockerdaemon.Prefix) {
+		return Setting{
+			name:        name,
+			set:         SetString,
+			validations: []setFn{IsValidDockerDaemonSetting},
+			callbacks:   []setFn{RequiresStartMsg},
+		}, nil
+	}
+	if strings.HasPrefix(name, AddonSetPrefix) {
+		return Setting{
+			name:        name,
+			set:         SetString,
+			validations: []setFn{IsValidAddonSet},
+		}, nil
+	}
+	return Setting{}, fmt.Errorf("Property name %s not found%s", name, didYouMean(name, settingNames()))
+}
+
+func settingNames() []string {
+	names := make([]string, 0, len(settings))
+	for _, s := range settings {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func addonNames() []string {
+	names := make([]string, 0, len(assets.Addons))
+	for name := range assets.Addons {
+		names = append(names, name)
+	}
+	return names
+}
+
+// didYouMean returns a hint naming the candidates closest to the unknown
+// name, or an empty string if none is close.
+func didYouMean(name string, candidates []string) string {
+	suggestions := util.Suggestions(name, candidates)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, " or "))
+}
+
+// validateAddonName checks that the addon exists, suggesting the closest
+// addon names if it doesn't.
+func validateAddonName(name string) error {
+	if _, ok := assets.Addons[name]; ok {
+		return nil
+	}
+	return errors.Errorf("%s is not a valid addon%s", name, didYouMean(name, addonNames()))
 }
 
 // Set Functions
@@ -93,164 +203,101 @@ func EnableOrDisableAddon(name string, val string) error {
 
 	enable, err := strconv.ParseBool(val)
 	if err != nil {
-		errors.Wrapf(err, "error attempted to parse enabled/disable value addon %s", name)
+		return errors.Wrapf(err, "error attempted to parse enabled/disable value addon %s", name)
 	}
 
 	// allows for additional prompting of information when enabling addons
 	if enable {
 		switch name {
-		case "registry-creds":
-			posResponses := []string{"yes", "y"}
-			negResponses := []string{"no", "n"}
-
-			// Default values
-			awsAccessID := "changeme"
-			awsAccessKey := "changeme"
-			awsRegion := "changeme"
-			awsAccount := "changeme"
-			gcrApplicationDefaultCredentials := "changeme"
-			dockerServer := "changeme"
-			dockerUser := "changeme"
-			dockerPass := "changeme"
-
-			enableAWSECR := AskForYesNoConfirmation("\nDo you want to enable AWS Elastic Container Registry?", posResponses, negResponses)
-			if enableAWSECR {
-				awsAccessID = AskForStaticValue("-- Enter AWS Access Key ID: ")
-				awsAccessKey = AskForStaticValue("-- Enter AWS Secret Access Key: ")
-				awsRegion = AskForStaticValue("-- Enter AWS Region: ")
-				awsAccount = AskForStaticValue("-- Enter 12 digit AWS Account ID: ")
-			}
-
-			enableGCR := AskForYesNoConfirmation("\nDo you want to enable Google Container Registry?", posResponses, negResponses)
-			if enableGCR {
-				gcrPath := AskForStaticValue("-- Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json):")
-
-				// Read file from disk
-				dat, err := ioutil.ReadFile(gcrPath)
-
-				if err != nil {
-					fmt.Println("Could not read file for application_default_credentials.json")
-				} else {
-					gcrApplicationDefaultCredentials = string(dat)
-				}
-			}
-
-			enableDR := AskForYesNoConfirmation("\nDo you want to enable Docker Registry?", posResponses, negResponses)
-			if enableDR {
-				dockerServer = AskForStaticValue("-- Enter docker registry server url: ")
-				dockerUser = AskForStaticValue("-- Enter docker registry username: ")
-				dockerPass = AskForStaticValue("-- Enter docker registry password: ")
+		case registrycreds.AddonName:
+			if err := enableRegistryCreds(); err != nil {
+				fmt.Println(err)
 			}
-
-			// Create ECR Secret
-			err = service.CreateSecret(
-				"kube-system",
-				"registry-creds-ecr",
-				map[string]string{
-					"AWS_ACCESS_KEY_ID":     awsAccessID,
-					"AWS_SECRET_ACCESS_KEY": awsAccessKey,
-					"aws-account":           awsAccount,
-					"aws-region":            awsRegion,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "ecr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-ecr` secret")
-			}
-
-			// Create GCR Secret
-			err = service.CreateSecret(
-				"kube-system",
-				"registry-creds-gcr",
-				map[string]string{
-					"application_default_credentials.json": gcrApplicationDefaultCredentials,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "gcr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-gcr` secret")
-			}
-
-			// Create Docker Secret
-			err = service.CreateSecret(
-				"kube-system",
-				"registry-creds-dpr",
-				map[string]string{
-					"DOCKER_PRIVATE_REGISTRY_SERVER":   dockerServer,
-					"DOCKER_PRIVATE_REGISTRY_USER":     dockerUser,
-					"DOCKER_PRIVATE_REGISTRY_PASSWORD": dockerPass,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "dpr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-dpr` secret")
-			}
-
-			break
 		}
 	} else {
 		// Cleanup existing secrets
-		service.DeleteSecret("kube-system", "registry-creds-ecr")
-		service.DeleteSecret("kube-system", "registry-creds-gcr")
-		service.DeleteSecret("kube-system", "registry-creds-dpr")
+		registrycreds.Delete()
+	}
+
+	// The auto-pause proxy is started, and the kubeconfig pointed to it, by minikube start
+	if name == "auto-pause" {
+		fmt.Fprintln(os.Stdout, "The change will take effect the next time minikube is started")
+		return nil
 	}
 
-	//TODO(r2d4): config package should not reference API, pull this out
 	api, err := machine.NewAPIClient(GetClientType())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
-		os.Exit(1)
+		return errors.Wrap(err, "Error getting client")
 	}
 	defer api.Close()
-	cluster.EnsureMinikubeRunningOrExit(api, 0)
 
-	addon, _ := assets.Addons[name] // validation done prior
+	// The Secret is written from the host, there is nothing to copy into the VM
+	if name == cloudcreds.AddonName {
+		return setCloudCreds(api, enable)
+	}
+
+	if assets.ApplyViaAPI() {
+		err = addons.SetViaAPI(api, name, enable, os.Stdout)
+	} else {
+		err = addons.Set(api, name, enable)
+	}
+	if err == addons.ErrNotRunning {
+		fmt.Fprintln(os.Stdout, "minikube is not currently running, the change will take effect the next time it is started")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	host, err := cluster.CheckIfApiExistsAndLoad(api)
 	if enable {
-		if err = transferAddonViaDriver(addon, host.Driver); err != nil {
-			return errors.Wrapf(err, "Error transferring addon %s to VM", name)
-		}
-	} else {
-		if err = deleteAddonViaDriver(addon, host.Driver); err != nil {
-			return errors.Wrapf(err, "Error deleting addon %s from VM", name)
-		}
+		webhooks.Notify(webhooks.AddonEnabled, map[string]string{"addon": name}, os.Stderr)
 	}
 	return nil
 }
 
-func deleteAddonViaDriver(addon *assets.Addon, d drivers.Driver) error {
-	client, err := sshutil.NewSSHClient(d)
+// setCloudCreds writes the Secret of the cloud credentials and keeps it in
+// sync in the background, or stops syncing and deletes it.
+func setCloudCreds(api libmachine.API, enable bool) error {
+	provider, namespace, err := cloudcreds.Config()
+	if !enable {
+		if err := cloudcreds.StopSync(); err != nil {
+			return err
+		}
+		if running, _ := isRunning(api); running {
+			return cloudcreds.Remove(namespace)
+		}
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	if err := sshutil.DeleteAddon(addon, client); err != nil {
+	// The credentials are checked before the addon is enabled
+	data, err := cloudcreds.LocalHost().Read(provider)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func transferAddonViaDriver(addon *assets.Addon, d drivers.Driver) error {
-	client, err := sshutil.NewSSHClient(d)
+	running, err := isRunning(api)
 	if err != nil {
 		return err
 	}
-	if err := sshutil.TransferAddon(addon, client); err != nil {
+	if !running {
+		fmt.Fprintln(os.Stdout, "minikube is not currently running, the change will take effect the next time it is started")
+		return nil
+	}
+	if err := cloudcreds.Sync(namespace, provider, data); err != nil {
+		return err
+	}
+	if err := cloudcreds.StartSync(); err != nil {
 		return err
 	}
+	fmt.Fprintf(os.Stdout, "The %s credentials are in the secret %s/%s, refreshed as they change on the host\n", provider, namespace, cloudcreds.SecretName)
+	webhooks.Notify(webhooks.AddonEnabled, map[string]string{"addon": cloudcreds.AddonName}, os.Stderr)
 	return nil
 }
+
+func isRunning(api libmachine.API) (bool, error) {
+	s, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return false, errors.Wrap(err, "Error getting machine status")
+	}
+	return s == state.Running.String(), nil
+}
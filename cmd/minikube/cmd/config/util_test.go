@@ -35,6 +35,29 @@ func TestFindSettingNotFound(t *testing.T) {
 	}
 }
 
+func TestFindSettingSuggestions(t *testing.T) {
+	_, err := findSetting("memry")
+	if err == nil {
+		t.Fatalf("Shouldn't have found setting memry")
+	}
+	if expected := "Property name memry not found, did you mean memory?"; err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+}
+
+func TestValidateAddonName(t *testing.T) {
+	if err := validateAddonName("ingress"); err != nil {
+		t.Errorf("Unexpected error for a valid addon: %s", err)
+	}
+	err := validateAddonName("heapstr")
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown addon")
+	}
+	if expected := "heapstr is not a valid addon, did you mean heapster?"; err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+}
+
 func TestFindSetting(t *testing.T) {
 	s, err := findSetting("vm-driver")
 	if err != nil {
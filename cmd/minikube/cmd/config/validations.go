@@ -21,18 +21,37 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/banner"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/dns"
+	"k8s.io/minikube/pkg/minikube/dockerdaemon"
+	"k8s.io/minikube/pkg/minikube/fleet"
+	"k8s.io/minikube/pkg/minikube/integrations"
+	"k8s.io/minikube/pkg/minikube/namespaces"
+	"k8s.io/minikube/pkg/minikube/policy"
+	"k8s.io/minikube/pkg/minikube/seed"
+	"k8s.io/minikube/pkg/minikube/share"
+	"k8s.io/minikube/pkg/util"
 )
 
 func IsValidDriver(string, driver string) error {
 	for _, d := range constants.SupportedVMDrivers {
 		if driver == d {
-			return nil
+			p, err := policy.Load()
+			if err != nil {
+				return err
+			}
+			return p.CheckDriver(driver)
 		}
 	}
 	return fmt.Errorf("Driver %s is not supported", driver)
@@ -51,6 +70,13 @@ func IsValidDiskSize(name string, disksize string) error {
 	return nil
 }
 
+func IsValidSwapSize(name string, swapsize string) error {
+	if _, err := units.RAMInBytes(swapsize); err != nil {
+		return fmt.Errorf("Not valid swap size: %v", err)
+	}
+	return nil
+}
+
 func IsValidURL(name string, location string) error {
 	_, err := url.Parse(location)
 	if err != nil {
@@ -70,6 +96,37 @@ func IsPositive(name string, val string) error {
 	return nil
 }
 
+func IsNonNegative(name string, val string) error {
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("%s:%v", name, err)
+	}
+	if i < 0 {
+		return fmt.Errorf("%s must be >= 0", name)
+	}
+	return nil
+}
+
+func IsValidKernelVariant(name string, variant string) error {
+	if variant != constants.KernelVariantDefault && variant != constants.KernelVariantRT {
+		return errors.Errorf("%s is not a valid kernel variant, expected %s or %s", variant, constants.KernelVariantDefault, constants.KernelVariantRT)
+	}
+	return nil
+}
+
+func IsValidCryptoMode(name string, mode string) error {
+	return util.ValidateCryptoMode(mode)
+}
+
+func IsValidContainerRuntime(name string, runtime string) error {
+	for _, r := range constants.SupportedContainerRuntimes {
+		if runtime == r {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not a valid container runtime, expected one of %v", runtime, constants.SupportedContainerRuntimes)
+}
+
 func IsValidCIDR(name string, cidr string) error {
 	_, _, err := net.ParseCIDR(cidr)
 	if err != nil {
@@ -78,6 +135,40 @@ func IsValidCIDR(name string, cidr string) error {
 	return nil
 }
 
+// IsValidPodNetworkCIDR checks the pod network against the service network
+// of the config
+func IsValidPodNetworkCIDR(name string, cidr string) error {
+	services, _ := config.Get(config.ServiceClusterIPRangeFlag)
+	return util.ValidateClusterCIDRs(cidr, services)
+}
+
+// IsValidServiceClusterIPRange checks the service network against the pod
+// network of the config
+func IsValidServiceClusterIPRange(name string, cidr string) error {
+	pods, _ := config.Get("pod-network-cidr")
+	return util.ValidateClusterCIDRs(pods, cidr)
+}
+
+// IsValidHostOnlyCIDR checks the host-only network of the virtualbox driver
+func IsValidHostOnlyCIDR(name string, cidr string) error {
+	return cluster.ValidateHostOnlyCIDR(cidr)
+}
+
+func IsValidPort(name string, val string) error {
+	port, err := strconv.Atoi(val)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s must be a port between 1 and 65535", name)
+	}
+	return nil
+}
+
+func IsValidIP(name string, ip string) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("%s is not a valid IP address", ip)
+	}
+	return nil
+}
+
 func IsValidPath(name string, path string) error {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -86,9 +177,143 @@ func IsValidPath(name string, path string) error {
 	return nil
 }
 
+// IsAbsolutePath checks that the path is absolute, it need not exist yet.
+func IsAbsolutePath(name string, path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%s must be an absolute path", name)
+	}
+	return nil
+}
+
+// IsGlobalSetting checks that no profile is active, for the settings read
+// before the profiles are.
+func IsGlobalSetting(name string, val string) error {
+	if profile := config.ActiveProfile(); profile != "" {
+		return fmt.Errorf("%s can only be set in the global config, not in profile %s", name, profile)
+	}
+	return nil
+}
+
+func RequiresMigrateDirsMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "Run minikube migrate-dirs to move the existing files to the new directory")
+	return nil
+}
+
 func IsValidAddon(name string, val string) error {
-	if _, ok := assets.Addons[name]; ok {
+	if _, ok := assets.Addons[name]; !ok {
+		return errors.Errorf("Cannot enable/disable invalid addon %s%s", name, didYouMean(name, addonNames()))
+	}
+	// Disabling an addon the policy disables is always allowed
+	if enable, err := strconv.ParseBool(val); err != nil || !enable {
 		return nil
 	}
-	return errors.Errorf("Cannot enable/disable invalid addon %s", name)
+	p, err := policy.Load()
+	if err != nil {
+		return err
+	}
+	return p.CheckAddon(name)
+}
+
+// IsValidAddonValue checks that an addon template value property is of the form addon.<addon name>.<key>
+func IsValidAddonValue(name string, val string) error {
+	parts := strings.SplitN(strings.TrimPrefix(name, assets.AddonValuePrefix), ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return errors.Errorf("%s is not of the form %s<addon name>.<key>", name, assets.AddonValuePrefix)
+	}
+	if err := validateAddonName(parts[0]); err != nil {
+		return err
+	}
+	if name == dns.IngressDomainSetting {
+		return dns.ValidateName(val)
+	}
+	return nil
+}
+
+// IsValidSeed checks that a seeded Secret or ConfigMap property is of the
+// form seed.secret.<namespace>/<name> and maps keys to files or environment
+// variables.
+func IsValidSeed(name string, val string) error {
+	_, err := seed.Parse(name, val)
+	return err
+}
+
+// IsValidNamespaces checks the list of namespaces set up on start.
+func IsValidNamespaces(name string, val string) error {
+	_, err := namespaces.ParseList(val)
+	return err
+}
+
+// IsValidNamespaceProperty checks the labels, quota or role bindings of a
+// namespace.
+func IsValidNamespaceProperty(name string, val string) error {
+	return namespaces.Validate(name, val)
+}
+
+// IsValidDockerDaemonSetting checks a setting of the docker daemon of the VM.
+func IsValidDockerDaemonSetting(name string, val string) error {
+	return dockerdaemon.Validate(name, val)
+}
+
+func IsValidAddonApplyMode(name string, mode string) error {
+	if mode != assets.ApplyModeSSH && mode != assets.ApplyModeAPI {
+		return errors.Errorf("%s is not a valid addon apply mode, expected %s or %s", mode, assets.ApplyModeSSH, assets.ApplyModeAPI)
+	}
+	return nil
+}
+
+// IsValidIntegrations checks that the integrations, separated by commas, exist.
+func IsValidIntegrations(name string, val string) error {
+	for _, i := range integrations.Enabled(val) {
+		if _, err := integrations.Load(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsValidStartMessage checks that the template of the start message parses.
+func IsValidStartMessage(name string, tmpl string) error {
+	return banner.Validate(tmpl)
+}
+
+func IsValidReservedResources(name string, val string) error {
+	if err := cluster.ValidateReservedResources(val); err != nil {
+		return errors.Wrapf(err, "%s is not valid", name)
+	}
+	return nil
+}
+
+func IsValidEvictionThresholds(name string, val string) error {
+	if err := cluster.ValidateEvictionThresholds(val); err != nil {
+		return errors.Wrapf(err, "%s is not valid", name)
+	}
+	return nil
+}
+
+func IsValidShareRelay(name string, relay string) error {
+	if _, err := share.ParseRelay(relay, "", ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+func IsValidFleetEndpoint(name string, endpoint string) error {
+	return fleet.ValidateEndpoint(endpoint)
+}
+
+func IsValidSSHPublicKey(name string, key string) error {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+		return errors.Wrapf(err, "%s is not a valid SSH public key", name)
+	}
+	return nil
+}
+
+func RequiresAddonReenableMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "These changes will take effect the next time the addon is enabled")
+	return nil
+}
+
+func RequiresStartMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "These changes will take effect the next time minikube is started")
+	return nil
 }
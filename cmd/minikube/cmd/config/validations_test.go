@@ -94,3 +94,187 @@ func TestValidCIDR(t *testing.T) {
 
 	runValidations(t, tests, "cidr", IsValidCIDR)
 }
+
+func TestValidHostOnlyCIDR(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "192.168.99.1/24",
+			shouldErr: false,
+		},
+		{
+			value:     "192.168.99.0/24",
+			shouldErr: true,
+		},
+		{
+			value:     "fd00::1/64",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "host-only-cidr", IsValidHostOnlyCIDR)
+}
+
+func TestValidPort(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "8443",
+			shouldErr: false,
+		},
+		{
+			value:     "0",
+			shouldErr: true,
+		},
+		{
+			value:     "65536",
+			shouldErr: true,
+		},
+		{
+			value:     "https",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "apiserver-port", IsValidPort)
+}
+
+func TestValidIP(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "0.0.0.0",
+			shouldErr: false,
+		},
+		{
+			value:     "::1",
+			shouldErr: false,
+		},
+		{
+			value:     "192.168.1.300",
+			shouldErr: true,
+		},
+		{
+			value:     "localhost",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "apiserver-listen-address", IsValidIP)
+}
+
+func TestIsValidAddonValue(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "addon.ingress.backendReplicas",
+			shouldErr: false,
+		},
+		{
+			value:     "addon.ingress.",
+			shouldErr: true,
+		},
+		{
+			value:     "addon.ingress",
+			shouldErr: true,
+		},
+		{
+			value:     "addon.notanaddon.replicas",
+			shouldErr: true,
+		},
+	}
+
+	// The property name is what is being validated here
+	runValidations(t, tests, "", func(_, name string) error {
+		return IsValidAddonValue(name, "1")
+	})
+
+	domains := []validationTest{
+		{value: "test", shouldErr: false},
+		{value: "dev.local", shouldErr: false},
+		{value: "test/127.0.0.1", shouldErr: true},
+	}
+	runValidations(t, domains, "addon.ingress-dns.domain", IsValidAddonValue)
+}
+
+func TestIsValidReservedResources(t *testing.T) {
+	var tests = []validationTest{
+		{value: "cpu=100m,memory=100Mi", shouldErr: false},
+		{value: "memory=1Gi", shouldErr: false},
+		{value: "", shouldErr: false},
+		{value: "memory", shouldErr: true},
+		{value: "memory=lots", shouldErr: true},
+		{value: "gpu=1", shouldErr: true},
+	}
+
+	runValidations(t, tests, "system-reserved", IsValidReservedResources)
+}
+
+func TestIsValidEvictionThresholds(t *testing.T) {
+	var tests = []validationTest{
+		{value: "memory.available<100Mi,nodefs.available<10%", shouldErr: false},
+		{value: "imagefs.inodesFree<5%", shouldErr: false},
+		{value: "memory.available=100Mi", shouldErr: true},
+		{value: "memory.free<100Mi", shouldErr: true},
+		{value: "nodefs.available<ten%", shouldErr: true},
+	}
+
+	runValidations(t, tests, "eviction-hard", IsValidEvictionThresholds)
+}
+
+func TestIsValidKernelVariant(t *testing.T) {
+	var tests = []validationTest{
+		{value: "default", shouldErr: false},
+		{value: "rt", shouldErr: false},
+		{value: "lowlatency", shouldErr: true},
+	}
+
+	runValidations(t, tests, "kernel-variant", IsValidKernelVariant)
+}
+
+func TestIsValidCryptoMode(t *testing.T) {
+	var tests = []validationTest{
+		{value: "default", shouldErr: false},
+		{value: "fips", shouldErr: false},
+		{value: "FIPS", shouldErr: true},
+	}
+
+	runValidations(t, tests, "crypto-mode", IsValidCryptoMode)
+}
+
+func TestIsValidSSHPublicKey(t *testing.T) {
+	var tests = []validationTest{
+		{value: "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBKCw/QjPTin3sh9ruh/nx6WpMBTI+Ie3WwdUg30eirquxz+MMLVTU5dsHFFi0OOSKPL7q+1v/6CE00T3BrBO/DY= relay", shouldErr: false},
+		{value: "ssh-rsa notbase64", shouldErr: true},
+		{value: "", shouldErr: true},
+	}
+
+	runValidations(t, tests, "share-relay-host-key", IsValidSSHPublicKey)
+}
+
+func TestIsValidContainerRuntime(t *testing.T) {
+	var tests = []validationTest{
+		{value: "docker", shouldErr: false},
+		{value: "rkt", shouldErr: false},
+		{value: "containerd", shouldErr: true},
+	}
+
+	runValidations(t, tests, "container-runtime", IsValidContainerRuntime)
+}
+
+func TestIsAbsolutePath(t *testing.T) {
+	var tests = []validationTest{
+		{value: "/scratch/minikube", shouldErr: false},
+		{value: "scratch/minikube", shouldErr: true},
+		{value: "", shouldErr: true},
+	}
+
+	runValidations(t, tests, "cache-dir", IsAbsolutePath)
+}
+
+func TestIsValidIntegrations(t *testing.T) {
+	var tests = []validationTest{
+		{value: "lens", shouldErr: false},
+		{value: "lens, tilt", shouldErr: false},
+		{value: "", shouldErr: false},
+		{value: "lens,emacs", shouldErr: true},
+	}
+
+	runValidations(t, tests, "integrations", IsValidIntegrations)
+}
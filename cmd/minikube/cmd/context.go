@@ -0,0 +1,151 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var nsList bool
+
+var nsCmd = &cobra.Command{
+	Use:   "ns [NAMESPACE]",
+	Short: "Prints or sets the default namespace of the context of the profile.",
+	Long: `Prints the default namespace of the context of the profile in the kubeconfig, or sets it to NAMESPACE, which must exist in
+the cluster. kubectl commands run in the context without --namespace then act on it. The namespace is kept when
+minikube start sets up the context again.
+
+--list prints the namespaces of the cluster, which bash completes NAMESPACE with.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 || (nsList && len(args) != 0) {
+			fmt.Fprintln(os.Stderr, "usage: minikube ns [NAMESPACE | --list]")
+			os.Exit(1)
+		}
+		filename, context := kubeconfigPath(), config.KubeContext()
+		if nsList {
+			if err := listNamespaces(os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+		if len(args) == 0 {
+			ns, err := kubeconfig.Namespace(filename, context)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Println(ns)
+			return
+		}
+		client, err := service.GetClientsetForContext(context)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		if err := checkNamespace(client.Core(), args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := kubeconfig.SetNamespace(filename, context, args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Context %q now defaults to namespace %q\n", context, args[0])
+	},
+}
+
+var ctxCmd = &cobra.Command{
+	Use:   "ctx",
+	Short: "Makes the context of the profile the current context of the kubeconfig.",
+	Long: `Makes the context of the profile the current context of the kubeconfig, the one kubectl commands run in without
+--context, e.g. after switching to another cluster or starting minikube with --keep-context. The context is named
+after the profile, or minikube without one. It fails with --no-host-mutation.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube ctx")
+			os.Exit(1)
+		}
+		if err := hostmutation.Check(viper.GetBool(hostmutation.Setting), hostmutation.KubectlContext, "minikube ctx"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		context := config.KubeContext()
+		previous, err := kubeconfig.UseContext(kubeconfigPath(), context)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if previous != "" && previous != context {
+			fmt.Printf("Switched to context %q from %q\n", context, previous)
+			return
+		}
+		fmt.Printf("Switched to context %q\n", context)
+	},
+}
+
+// checkNamespace returns an error naming how to create the namespace when
+// it does not exist.
+func checkNamespace(client corev1.NamespacesGetter, name string) error {
+	_, err := client.Namespaces().Get(name)
+	if apierrors.IsNotFound(err) {
+		return errors.Errorf("Namespace %s does not exist, create it with: kubectl create namespace %s", name, name)
+	}
+	return errors.Wrapf(err, "Error getting namespace %s", name)
+}
+
+// listNamespaces writes the names of the namespaces of the cluster of the
+// context of the active profile, one per line.
+func listNamespaces(w io.Writer) error {
+	client, err := service.GetClientsetForContext(config.KubeContext())
+	if err != nil {
+		return errors.Wrap(err, "Error getting kubernetes client")
+	}
+	namespaces, err := client.Core().Namespaces().List(v1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Error listing the namespaces")
+	}
+	var names []string
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+func init() {
+	nsCmd.Flags().BoolVar(&nsList, "list", false, "Print the namespaces of the cluster")
+	RootCmd.AddCommand(nsCmd)
+	RootCmd.AddCommand(ctxCmd)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+type mockNamespacesGetter struct {
+	fake.FakeNamespaces
+	names map[string]bool
+}
+
+func (m *mockNamespacesGetter) Namespaces() corev1.NamespaceInterface {
+	return m
+}
+
+func (m *mockNamespacesGetter) Get(name string) (*v1.Namespace, error) {
+	if !m.names[name] {
+		return nil, apierrors.NewNotFound(unversioned.GroupResource{Resource: "namespaces"}, name)
+	}
+	return &v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name}}, nil
+}
+
+func TestCheckNamespace(t *testing.T) {
+	client := &mockNamespacesGetter{names: map[string]bool{"dev": true}}
+	if err := checkNamespace(client, "dev"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	err := checkNamespace(client, "prod")
+	if err == nil || !strings.Contains(err.Error(), "kubectl create namespace prod") {
+		t.Errorf("Expected an error telling how to create the namespace, got %v", err)
+	}
+}
+
+func TestBashCompletionNamespaces(t *testing.T) {
+	var b bytes.Buffer
+	if err := GenerateBashCompletion(&b, RootCmd); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{"__custom_func()", "minikube_ns)", "minikube ns --list"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected the completion to contain %q", expected)
+		}
+	}
+}
@@ -20,35 +20,169 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/addons"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/usage"
+)
+
+var (
+	deleteAll   bool
+	deletePurge bool
+	deleteYes   bool
 )
 
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Deletes a local kubernetes cluster.",
-	Long: `Deletes a local kubernetes cluster. This command deletes the VM, and removes all
-associated files.`,
+	Long: `Deletes a local kubernetes cluster. This command deletes the VM, and those of the
+nodes, and removes all associated files.
+
+With --all, every machine of the machines directory is deleted, including those left over, and
+the resources recorded for all the profiles are forgotten. Adding --purge also removes the
+minikube directories: the state, the config and the profiles, and the cache of ISOs and localkube
+binaries, leaving nothing behind. Both ask for confirmation unless --yes is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Deleting local Kubernetes cluster...")
+		if deletePurge && !deleteAll {
+			fmt.Fprintln(os.Stderr, "--purge can only be used with --all")
+			os.Exit(1)
+		}
 		api, err := machine.NewAPIClient(clientType)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
 			os.Exit(1)
 		}
 		defer api.Close()
+		if deleteAll {
+			deleteEverything(api)
+			return
+		}
+
+		fmt.Println("Deleting local Kubernetes cluster...")
+
+		if err := daemons.StopAll(); err != nil {
+			glog.Errorln("Error stopping minikube daemons: ", err)
+		}
+
+		nodes, err := cluster.ListNodes(api)
+		if err != nil {
+			glog.Errorln("Error listing nodes: ", err)
+		}
+		for _, n := range nodes {
+			if err := cluster.DeleteNode(api, n); err != nil {
+				fmt.Printf("Errors occurred deleting node %s: %s\n", n, err)
+			}
+		}
 
 		if err = cluster.DeleteHost(api); err != nil {
 			fmt.Println("Errors occurred deleting machine: ", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
 		}
+		// Every profile drives the same machine
+		if err := usage.Remove(constants.MachineName); err != nil {
+			glog.Errorln("Error removing the resources of the VM: ", err)
+		}
+		if err := addons.RemoveManifests(constants.MachineName); err != nil {
+			glog.Errorln("Error removing the manifests of the addons: ", err)
+		}
 		fmt.Println("Machine deleted.")
+		runHook(hooks.PostDelete, os.Stdout)
 	},
 }
 
+// deleteEverything deletes all the machines and forgets the resources of all
+// the profiles, then removes the minikube directories with --purge.
+func deleteEverything(api libmachine.API) {
+	machines, err := api.List()
+	if err != nil {
+		glog.Errorln("Error listing machines: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	var dirs []string
+	if deletePurge {
+		dirs = purgeDirs()
+	}
+	if len(machines) == 0 && len(dirs) == 0 {
+		fmt.Println("Nothing to delete.")
+		return
+	}
+	if !deleteYes {
+		for _, m := range machines {
+			fmt.Printf("Machine %s will be deleted.\n", m)
+		}
+		for _, d := range dirs {
+			fmt.Printf("Directory %s will be removed.\n", d)
+		}
+		if !configCmd.AskForYesNoConfirmation("Continue?", []string{"yes", "y"}, []string{"no", "n"}) {
+			fmt.Println("Nothing deleted.")
+			return
+		}
+	}
+
+	if err := daemons.StopAll(); err != nil {
+		glog.Errorln("Error stopping minikube daemons: ", err)
+	}
+	deleted, err := cluster.DeleteAllHosts(api)
+	for _, m := range deleted {
+		fmt.Printf("Machine %s deleted.\n", m)
+	}
+	if err != nil {
+		// The directories hold the state of the machines not deleted
+		fmt.Println("Errors occurred deleting machines: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	for _, m := range deleted {
+		if err := usage.Remove(m); err != nil {
+			glog.Errorln("Error removing the resources of the VM: ", err)
+		}
+		if err := addons.RemoveManifests(m); err != nil {
+			glog.Errorln("Error removing the manifests of the addons: ", err)
+		}
+	}
+
+	runHook(hooks.PostDelete, os.Stdout)
+
+	for _, d := range dirs {
+		if err := os.RemoveAll(d); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing %s: %s\n", d, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Directory %s removed.\n", d)
+	}
+}
+
+// purgeDirs returns the existing minikube directories, which may each be
+// relocated, the state one last as it may hold the others.
+func purgeDirs() []string {
+	var dirs []string
+	seen := map[string]bool{}
+	for _, d := range []string{
+		constants.GetPath(constants.CachePath),
+		constants.MakeMiniPath("profiles"),
+		constants.GetPath(constants.ConfigPath),
+		constants.GetPath(constants.StatePath),
+	} {
+		if _, err := os.Stat(d); err != nil || seen[d] {
+			continue
+		}
+		seen[d] = true
+		dirs = append(dirs, d)
+	}
+	return dirs
+}
+
 func init() {
+	deleteCmd.Flags().BoolVar(&deleteAll, "all", false, "Delete all the machines, including those of the nodes and any left over, and forget the resources of all the profiles")
+	deleteCmd.Flags().BoolVar(&deletePurge, "purge", false, "With --all, also remove the minikube directories: state, config, profiles and cache")
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Don't ask for confirmation")
 	RootCmd.AddCommand(deleteCmd)
 }
@@ -0,0 +1,120 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/compose"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	deployProject   string
+	deployNamespace string
+	deployNoBuild   bool
+)
+
+// deployCmd represents the deploy command
+var deployCmd = &cobra.Command{
+	Use:   "deploy COMPOSE_FILE",
+	Short: "Deploys the services of a docker-compose file to the cluster.",
+	Long: `Deploys the services of a docker-compose file to the cluster.
+
+A deployment is created for every service, and a NodePort service for the ones publishing ports.
+Images with a build context are built with the docker daemon of the minikube VM beforehand.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube deploy COMPOSE_FILE")
+			os.Exit(1)
+		}
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading compose file: %s\n", err)
+			os.Exit(1)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading compose file: %s\n", err)
+			os.Exit(1)
+		}
+		f, err := compose.Parse(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		dir := filepath.Dir(path)
+		project := deployProject
+		if project == "" {
+			project = filepath.Base(dir)
+		}
+
+		if !deployNoBuild {
+			api, err := machine.NewAPIClient(clientType)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+				os.Exit(1)
+			}
+			defer api.Close()
+			dockerEnv, err := cluster.GetHostDockerEnv(api)
+			if err != nil {
+				glog.Errorln("Error getting docker env: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			if err := compose.BuildImages(f, project, dir, dockerEnv); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+
+		objs, err := compose.Convert(f, project)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		if err := compose.Apply(client, deployNamespace, objs); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, name := range f.ServiceNames() {
+			fmt.Printf("Deployed %s\n", name)
+		}
+		if len(objs.Services) > 0 {
+			fmt.Printf("To access the published ports use: minikube service list -n %s\n", deployNamespace)
+		}
+	},
+}
+
+func init() {
+	deployCmd.Flags().StringVar(&deployProject, "project", "", "The project name, used to label the deployed objects and name built images. Defaults to the name of the directory of the compose file")
+	deployCmd.Flags().StringVarP(&deployNamespace, "namespace", "n", "default", "The namespace to deploy the services to")
+	deployCmd.Flags().BoolVar(&deployNoBuild, "no-build", false, "Don't build the images of the services, they must already exist in the VM")
+	RootCmd.AddCommand(deployCmd)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/dns"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	dnsQueryNamespace string
+	dnsQueryTimeout   time.Duration
+)
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Debugs the DNS of the cluster.",
+	Long:  "Debugs the service discovery of the cluster: logs the queries kube-dns answers, and resolves names as the pods do.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var dnsLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Enables or disables the logging of the DNS queries.",
+	Long: `Enables or disables the logging of the queries kube-dns answers. Once enabled, they are logged
+by the dnsmasq container of the kube-dns pods:
+
+  kubectl logs --namespace kube-system -l k8s-app=kube-dns -c dnsmasq -f`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var dnsLogEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Logs the queries kube-dns answers.",
+	Run: func(cmd *cobra.Command, args []string) {
+		setDNSLogQueries(true)
+		fmt.Println("DNS queries are logged, see them with: kubectl logs --namespace kube-system -l k8s-app=kube-dns -c dnsmasq -f")
+	},
+}
+
+var dnsLogDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stops logging the queries kube-dns answers.",
+	Run: func(cmd *cobra.Command, args []string) {
+		setDNSLogQueries(false)
+		fmt.Println("DNS queries are no longer logged")
+	},
+}
+
+var dnsQueryCmd = &cobra.Command{
+	Use:   "query NAME",
+	Short: "Resolves a name from inside the cluster.",
+	Long: `Resolves a name from a pod of the given namespace, with the same DNS settings as the other pods,
+printing the resolv.conf of the pod and the answer of the lookup. Names which aren't fully
+qualified are resolved relative to the namespace, e.g. my-svc or my-svc.other-namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube dns query NAME")
+			os.Exit(1)
+		}
+		if err := dns.ValidateName(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		ensureDNSEnabled()
+
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		output, err := dns.Query(client, args[0], dnsQueryNamespace, dnsQueryTimeout)
+		if output != "" {
+			fmt.Println(output)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+// ensureDNSEnabled exits unless the kube-dns addon is enabled.
+func ensureDNSEnabled() {
+	enabled, err := assets.Addons[dns.Addon].IsEnabled()
+	if err != nil {
+		glog.Errorln("Error getting the status of the kube-dns addon: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if !enabled {
+		fmt.Fprintln(os.Stderr, "The kube-dns addon is disabled, enable it with: minikube addons enable kube-dns")
+		os.Exit(1)
+	}
+}
+
+// setDNSLogQueries records whether the queries are logged in the kube-dns
+// addon values, applies the addon again and restarts its pods with the new
+// arguments.
+func setDNSLogQueries(enable bool) {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	defer api.Close()
+	cluster.EnsureMinikubeRunningOrExit(api, 1)
+	ensureDNSEnabled()
+
+	config, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		glog.Errorln("Error reading config: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if enable {
+		config[dns.LogQueriesSetting] = "true"
+	} else {
+		delete(config, dns.LogQueriesSetting)
+	}
+	if err := configCmd.WriteConfig(config); err != nil {
+		glog.Errorln("Error writing config: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	if err := applyDNSAddon(api); err != nil {
+		glog.Errorln("Error applying the kube-dns addon: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+}
+
+// applyDNSAddon updates the kube-dns replication controller right away,
+// rather than waiting for the addon-manager to pick up its new manifest,
+// before restarting the pods.
+func applyDNSAddon(api libmachine.API) error {
+	addon := assets.Addons[dns.Addon]
+	if !assets.ApplyViaAPI() {
+		if err := addons.Set(api, dns.Addon, true); err != nil {
+			return err
+		}
+	}
+	client, err := addons.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := addons.Apply(client, addon, ioutil.Discard); err != nil {
+		return err
+	}
+	clientset, err := service.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "Error getting kubernetes client")
+	}
+	return dns.Restart(clientset)
+}
+
+func init() {
+	dnsQueryCmd.Flags().StringVarP(&dnsQueryNamespace, "namespace", "n", "default", "The namespace of the pod resolving the name")
+	dnsQueryCmd.Flags().DurationVar(&dnsQueryTimeout, "timeout", 2*time.Minute, "How long to wait for the lookup, including pulling the image of the pod")
+	dnsLogCmd.AddCommand(dnsLogEnableCmd)
+	dnsLogCmd.AddCommand(dnsLogDisableCmd)
+	dnsCmd.AddCommand(dnsLogCmd)
+	dnsCmd.AddCommand(dnsQueryCmd)
+	RootCmd.AddCommand(dnsCmd)
+}
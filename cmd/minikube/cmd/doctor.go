@@ -0,0 +1,77 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/security"
+)
+
+var (
+	doctorSecurity bool
+	doctorFix      bool
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the host setup of minikube for problems.",
+	Long: `Checks the host setup of minikube for problems:
+
+  --security  keys, certs and kubeconfig which other users than their owner can access
+
+Everything is checked when no check is given. Exits with 1 if problems remain.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all := !doctorSecurity
+		problems := 0
+		if all || doctorSecurity {
+			findings, err := security.Audit(kubeconfigPath())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error auditing the permissions of the credentials: %s\n", err)
+				os.Exit(1)
+			}
+			if doctorFix {
+				if err := security.Fix(findings); err != nil {
+					fmt.Fprintf(os.Stderr, "Error restricting the permissions of the credentials: %s\n", err)
+					os.Exit(1)
+				}
+				for _, f := range findings {
+					fmt.Printf("Restricted %s to mode %04o\n", f.Path, f.Want)
+				}
+			} else {
+				for _, f := range findings {
+					fmt.Println(f)
+				}
+				problems += len(findings)
+			}
+		}
+		if problems > 0 {
+			fmt.Println("Run with --fix to fix the problems.")
+			os.Exit(1)
+		}
+		fmt.Println("No problems found.")
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorSecurity, "security", false, "Check the permissions of the credentials")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Fix the problems found")
+	RootCmd.AddCommand(doctorCmd)
+}
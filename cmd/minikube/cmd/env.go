@@ -21,8 +21,9 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
-	"strings"
+	"strconv"
 	"text/template"
 
 	"github.com/docker/machine/libmachine"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/proxy"
 )
 
 const (
@@ -115,8 +117,11 @@ var (
 	noProxy              bool
 	forceShell           string
 	unset                bool
+	refreshDaemon        bool
+	localhostDockerHost  bool
 	defaultShellDetector ShellDetector
 	defaultNoProxyGetter NoProxyGetter
+	proxyGetenv          = os.Getenv
 )
 
 type ShellDetector interface {
@@ -159,7 +164,12 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		UsageHint:        generateUsageHint(userShell),
 	}
 
-	if noProxy {
+	if localhostDockerHost {
+		shellCfg.DockerHost = "tcp://" + net.JoinHostPort("localhost", strconv.Itoa(constants.DockerDaemonPort))
+	}
+
+	// The docker client would otherwise reach the VM through the proxy
+	if noProxy || proxy.IsSet(proxyGetenv) {
 		host, err := api.Load(constants.MachineName)
 		if err != nil {
 			return nil, errors.Wrap(err, "Error getting IP")
@@ -173,41 +183,11 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		noProxyVar, noProxyValue := defaultNoProxyGetter.GetNoProxyVar()
 
 		// add the docker host to the no_proxy list idempotently
-		switch {
-		case noProxyValue == "":
-			noProxyValue = ip
-		case strings.Contains(noProxyValue, ip):
-		//ip already in no_proxy list, nothing to do
-		default:
-			noProxyValue = fmt.Sprintf("%s,%s", noProxyValue, ip)
-		}
-
 		shellCfg.NoProxyVar = noProxyVar
-		shellCfg.NoProxyValue = noProxyValue
+		shellCfg.NoProxyValue = proxy.NoProxy(noProxyValue, ip)
 	}
 
-	switch userShell {
-	case "fish":
-		shellCfg.Prefix = fishSetPfx
-		shellCfg.Suffix = fishSetSfx
-		shellCfg.Delimiter = fishSetDelim
-	case "powershell":
-		shellCfg.Prefix = psSetPfx
-		shellCfg.Suffix = psSetSfx
-		shellCfg.Delimiter = psSetDelim
-	case "cmd":
-		shellCfg.Prefix = cmdSetPfx
-		shellCfg.Suffix = cmdSetSfx
-		shellCfg.Delimiter = cmdSetDelim
-	case "emacs":
-		shellCfg.Prefix = emacsSetPfx
-		shellCfg.Suffix = emacsSetSfx
-		shellCfg.Delimiter = emacsSetDelim
-	default:
-		shellCfg.Prefix = bashSetPfx
-		shellCfg.Suffix = bashSetSfx
-		shellCfg.Delimiter = bashSetDelim
-	}
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shellSetSyntax(userShell)
 
 	return shellCfg, nil
 }
@@ -227,30 +207,41 @@ func shellCfgUnset() (*ShellConfig, error) {
 		shellCfg.NoProxyVar, shellCfg.NoProxyValue = defaultNoProxyGetter.GetNoProxyVar()
 	}
 
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shellUnsetSyntax(userShell)
+
+	return shellCfg, nil
+}
+
+// shellSetSyntax returns the prefix, suffix and delimiter used to set an environment variable in userShell
+func shellSetSyntax(userShell string) (string, string, string) {
 	switch userShell {
 	case "fish":
-		shellCfg.Prefix = fishUnsetPfx
-		shellCfg.Suffix = fishUnsetSfx
-		shellCfg.Delimiter = fishUnsetDelim
+		return fishSetPfx, fishSetSfx, fishSetDelim
 	case "powershell":
-		shellCfg.Prefix = psUnsetPfx
-		shellCfg.Suffix = psUnsetSfx
-		shellCfg.Delimiter = psUnsetDelim
+		return psSetPfx, psSetSfx, psSetDelim
 	case "cmd":
-		shellCfg.Prefix = cmdUnsetPfx
-		shellCfg.Suffix = cmdUnsetSfx
-		shellCfg.Delimiter = cmdUnsetDelim
+		return cmdSetPfx, cmdSetSfx, cmdSetDelim
 	case "emacs":
-		shellCfg.Prefix = emacsUnsetPfx
-		shellCfg.Suffix = emacsUnsetSfx
-		shellCfg.Delimiter = emacsUnsetDelim
+		return emacsSetPfx, emacsSetSfx, emacsSetDelim
 	default:
-		shellCfg.Prefix = bashUnsetPfx
-		shellCfg.Suffix = bashUnsetSfx
-		shellCfg.Delimiter = bashUnsetDelim
+		return bashSetPfx, bashSetSfx, bashSetDelim
 	}
+}
 
-	return shellCfg, nil
+// shellUnsetSyntax returns the prefix, suffix and delimiter used to unset an environment variable in userShell
+func shellUnsetSyntax(userShell string) (string, string, string) {
+	switch userShell {
+	case "fish":
+		return fishUnsetPfx, fishUnsetSfx, fishUnsetDelim
+	case "powershell":
+		return psUnsetPfx, psUnsetSfx, psUnsetDelim
+	case "cmd":
+		return cmdUnsetPfx, cmdUnsetSfx, cmdUnsetDelim
+	case "emacs":
+		return emacsUnsetPfx, emacsUnsetSfx, emacsUnsetDelim
+	default:
+		return bashUnsetPfx, bashUnsetSfx, bashUnsetDelim
+	}
 }
 
 func executeTemplateStdout(shellCfg *ShellConfig) error {
@@ -301,6 +292,23 @@ var dockerEnvCmd = &cobra.Command{
 				cmdUtil.MaybeReportErrorAndExit(err)
 			}
 		} else {
+			if refreshDaemon {
+				if err := cluster.RefreshDockerAuth(api); err != nil {
+					glog.Errorln("Error refreshing docker daemon certificates:", err)
+					cmdUtil.MaybeReportErrorAndExit(err)
+				}
+			}
+			if localhostDockerHost {
+				h, err := cluster.CheckIfApiExistsAndLoad(api)
+				if err != nil {
+					glog.Errorln("Error loading the minikube VM:", err)
+					cmdUtil.MaybeReportErrorAndExit(err)
+				}
+				if err := cluster.ForwardDockerPort(h); err != nil {
+					glog.Errorln("Error forwarding docker port:", err)
+					cmdUtil.MaybeReportErrorAndExit(err)
+				}
+			}
 			shellCfg, err = shellCfgSet(api)
 			if err != nil {
 				glog.Errorln("Error setting machine env variable(s):", err)
@@ -316,7 +324,9 @@ func init() {
 	RootCmd.AddCommand(dockerEnvCmd)
 	defaultShellDetector = &LibmachineShellDetector{}
 	defaultNoProxyGetter = &EnvNoProxyGetter{}
-	dockerEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add machine IP to NO_PROXY environment variable")
+	dockerEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add machine IP to NO_PROXY environment variable, which is done anyway when HTTP_PROXY or HTTPS_PROXY is set")
 	dockerEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
 	dockerEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset variables instead of setting them")
+	dockerEnvCmd.Flags().BoolVar(&refreshDaemon, "refresh-daemon", false, "Regenerate the docker daemon certificates for the current VM IP, use this if docker-env stopped working after a restart")
+	dockerEnvCmd.Flags().BoolVar(&localhostDockerHost, "localhost", false, "Point DOCKER_HOST at a localhost port forward that survives VM restarts (only supported with Virtualbox driver)")
 }
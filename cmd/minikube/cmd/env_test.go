@@ -80,6 +80,8 @@ func TestShellCfgSet(t *testing.T) {
 		expectedShellCfg *ShellConfig
 		shouldErr        bool
 		noProxyFlag      bool
+		localhostFlag    bool
+		httpProxy        string
 	}{
 		{
 			description: "no host specified",
@@ -232,6 +234,42 @@ func TestShellCfgSet(t *testing.T) {
 				NoProxyValue:     "0.0.0.0,127.0.0.1",
 			},
 		},
+		{
+			description:  "http proxy adds to no proxy list",
+			api:          defaultAPI,
+			shell:        "bash",
+			noProxyVar:   "NO_PROXY",
+			noProxyValue: "127.0.0.0/8",
+			httpProxy:    "http://proxy:3128",
+			expectedShellCfg: &ShellConfig{
+				DockerCertPath:   constants.MakeMiniPath("certs"),
+				DockerTLSVerify:  "1",
+				DockerHost:       "tcp://127.0.0.1:2376",
+				DockerAPIVersion: constants.DockerAPIVersion,
+				UsageHint:        usageHintMap["bash"],
+				Prefix:           bashSetPfx,
+				Suffix:           bashSetSfx,
+				Delimiter:        bashSetDelim,
+				NoProxyVar:       "NO_PROXY",
+				NoProxyValue:     "127.0.0.0/8",
+			},
+		},
+		{
+			description:   "localhost docker host",
+			api:           defaultAPI,
+			shell:         "bash",
+			localhostFlag: true,
+			expectedShellCfg: &ShellConfig{
+				DockerCertPath:   constants.MakeMiniPath("certs"),
+				DockerTLSVerify:  "1",
+				DockerHost:       "tcp://localhost:2376",
+				DockerAPIVersion: constants.DockerAPIVersion,
+				UsageHint:        usageHintMap["bash"],
+				Prefix:           bashSetPfx,
+				Suffix:           bashSetSfx,
+				Delimiter:        bashSetDelim,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -241,6 +279,13 @@ func TestShellCfgSet(t *testing.T) {
 			defaultShellDetector = &FakeShellDetector{test.shell}
 			defaultNoProxyGetter = &FakeNoProxyGetter{test.noProxyVar, test.noProxyValue}
 			noProxy = test.noProxyFlag
+			localhostDockerHost = test.localhostFlag
+			proxyGetenv = func(name string) string {
+				if name == "HTTP_PROXY" {
+					return test.httpProxy
+				}
+				return ""
+			}
 
 			shellCfg, err := shellCfgSet(test.api)
 			if !reflect.DeepEqual(shellCfg, test.expectedShellCfg) {
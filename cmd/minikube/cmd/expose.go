@@ -0,0 +1,173 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/docker/machine/libmachine/host"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/wireguard"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	exposeWireGuard bool
+	exposeDelete    bool
+	wireGuardPort   int
+	wireGuardSubnet string
+	exposeEndpoint  string
+	exposeOutput    string
+)
+
+// exposeCmd represents the expose command
+var exposeCmd = &cobra.Command{
+	Use:   "expose --wireguard",
+	Short: "Gives other machines secure access to the cluster.",
+	Long: `Gives other machines secure access to the cluster, e.g. to pair with a teammate on services running in minikube.
+
+With --wireguard, a WireGuard endpoint is configured in the minikube VM and the config of its client is written,
+to be used by the teammate with "wg-quick up". The client can reach the VM at the first address of the
+WireGuard subnet, on which NodePort services are available, and the service cluster IPs.
+
+The VM is usually only reachable from this machine: in that case forward the UDP port to the VM and set
+--endpoint to the address of this machine. Running the command again replaces the client, and
+--delete removes the endpoint.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !exposeWireGuard {
+			fmt.Fprintln(os.Stderr, "usage: minikube expose --wireguard")
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM:", err)
+			os.Exit(1)
+		}
+
+		if exposeDelete {
+			if err := cluster.RemoveWireGuard(h, wireGuardSubnet); err != nil {
+				glog.Errorln("Error removing the WireGuard endpoint:", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			fmt.Println("The WireGuard endpoint was removed.")
+			return
+		}
+
+		endpoint := exposeEndpoint
+		if endpoint == "" {
+			if endpoint, err = h.Driver.GetIP(); err != nil {
+				glog.Errorln("Error getting the IP of the minikube VM:", err)
+				os.Exit(1)
+			}
+		}
+		config, err := exposeViaWireGuard(h, endpoint)
+		if err != nil {
+			glog.Errorln("Error exposing the cluster over WireGuard:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := writeWireGuardConfig(config, exposeOutput); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+// exposeViaWireGuard configures the endpoint on the VM for a new client and
+// returns the config of the client.
+func exposeViaWireGuard(h *host.Host, endpoint string) (*wireguard.ClientConfig, error) {
+	key, err := wireguard.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	_, address, err := wireguard.Addresses(wireGuardSubnet)
+	if err != nil {
+		return nil, err
+	}
+	serverKey, err := cluster.ExposeWireGuard(h, wireGuardPort, wireGuardSubnet, key)
+	if err != nil {
+		return nil, err
+	}
+	return &wireguard.ClientConfig{
+		PrivateKey:      key,
+		Address:         address,
+		ServerPublicKey: serverKey,
+		Endpoint:        net.JoinHostPort(endpoint, strconv.Itoa(wireGuardPort)),
+		AllowedIPs:      wireGuardAllowedIPs(wireGuardSubnet),
+	}, nil
+}
+
+// wireGuardAllowedIPs returns the networks the client routes to the VM: the
+// WireGuard subnet and the service cluster IP range.
+func wireGuardAllowedIPs(subnet string) []string {
+	allowed := []string{subnet}
+	serviceCIDR := pkgConfig.ServiceClusterIPRange()
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceClusterIPRange
+	}
+	if _, services, err := net.ParseCIDR(serviceCIDR); err == nil {
+		allowed = append(allowed, services.String())
+	}
+	return allowed
+}
+
+// writeWireGuardConfig writes the client config to the file, readable only by
+// the user as it holds the private key of the client, or to stdout.
+func writeWireGuardConfig(config *wireguard.ClientConfig, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return errors.Wrapf(err, "Error creating %s", path)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := config.Write(w); err != nil {
+		return errors.Wrap(err, "Error writing the WireGuard client config")
+	}
+	if path != "" {
+		fmt.Printf("The WireGuard client config was written to %s, share it with your teammate to be used with: wg-quick up %s\n", path, path)
+	}
+	return nil
+}
+
+func init() {
+	exposeCmd.Flags().BoolVar(&exposeWireGuard, "wireguard", false, "Expose the cluster through a WireGuard endpoint in the VM")
+	exposeCmd.Flags().BoolVar(&exposeDelete, "delete", false, "Remove the endpoint instead of configuring it")
+	exposeCmd.Flags().IntVar(&wireGuardPort, "wireguard-port", wireguard.DefaultPort, "The UDP port the WireGuard endpoint listens on")
+	exposeCmd.Flags().StringVar(&wireGuardSubnet, "wireguard-subnet", wireguard.DefaultSubnet, "The subnet of the WireGuard tunnel, its first address is given to the VM and the second to the client")
+	exposeCmd.Flags().StringVar(&exposeEndpoint, "endpoint", "", "The address the client connects to, defaults to the IP of the VM")
+	exposeCmd.Flags().StringVarP(&exposeOutput, "output", "o", "", "The file to write the client config to, defaults to stdout")
+	RootCmd.AddCommand(exposeCmd)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/lan"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/webhooks"
+)
+
+var (
+	exposeAPILAN     bool
+	exposeAPIAddress string
+	exposeAPIOutput  string
+)
+
+// exposeAPICmd represents the expose-api command
+var exposeAPICmd = &cobra.Command{
+	Use:   "expose-api --lan",
+	Short: "Lets other machines on the local network run kubectl against the cluster.",
+	Long: `Lets other machines on the local network, e.g. a tablet or a second laptop, run kubectl against the cluster.
+
+With --lan, the certificate of the apiserver is generated again to be valid for the addresses of this
+machine on the local network, the apiserver is forwarded to all the addresses of this machine, on the
+port of the apiserver, and a kubeconfig embedding the credentials, with the server set to the address of
+this machine, is written for the other machine to use.
+
+Anyone holding that kubeconfig controls the cluster. Starting minikube again without
+--apiserver-listen-address stops the forward.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !exposeAPILAN {
+			fmt.Fprintln(os.Stderr, "usage: minikube expose-api --lan")
+			os.Exit(1)
+		}
+		hostIPs, err := lan.HostIPs()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		port := viper.GetInt(apiServerPort)
+		server, certIPs, err := lanServer(hostIPs, exposeAPIAddress, port)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM:", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Setting up certs...")
+		if err := cluster.SetupCerts(h.Driver, viper.GetString(apiServerName), certIPs...); err != nil {
+			glog.Errorln("Error configuring authentication: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := cluster.RestartLocalkube(h); err != nil {
+			glog.Errorln("Error restarting cluster components: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		var addresses []string
+		for _, ip := range certIPs {
+			addresses = append(addresses, ip.String())
+		}
+		webhooks.Notify(webhooks.CertRotated, map[string]string{"addresses": strings.Join(addresses, ",")}, os.Stderr)
+
+		fmt.Println("Starting apiserver proxy...")
+		if err := startAPIServerProxy("0.0.0.0", port); err != nil {
+			glog.Errorln("Error starting the apiserver proxy: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		output := exposeAPIOutput
+		if output == "" {
+			output = constants.MakeMiniPath("lan-kubeconfig")
+		}
+		kubeCfgSetup := &kubeconfig.KubeConfigSetup{
+			ClusterName:          constants.MinikubeContext,
+			ClusterServerAddress: server,
+			ClientCertificate:    constants.MakeMiniPath("apiserver.crt"),
+			ClientKey:            constants.MakeMiniPath("apiserver.key"),
+			CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+			EmbedCerts:           true,
+		}
+		kubeCfgSetup.SetKubeConfigFile(output)
+		if err := kubeconfig.SetupKubeConfig(kubeCfgSetup); err != nil {
+			glog.Errorln("Error writing the kubeconfig: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		fmt.Fprintf(os.Stderr, "WARNING: The apiserver is reachable on port %d by other machines. Anyone holding the credentials of the kubeconfig controls the cluster, make sure your firewall only lets trusted machines connect to that port.\n", port)
+		fmt.Printf("The apiserver is available at %s. Copy %s to the other machine and run:\n\n  kubectl --kubeconfig=%s get nodes\n", server, output, output)
+	},
+}
+
+// lanServer returns the URL of the apiserver forwarded to the address on the
+// local network, the first of hostIPs when empty, and the IPs the certificate
+// of the apiserver has to be valid for: the loopback one for the local
+// clients, and all of hostIPs and the address.
+func lanServer(hostIPs []net.IP, address string, port int) (string, []net.IP, error) {
+	certIPs := append([]net.IP{net.ParseIP("127.0.0.1")}, hostIPs...)
+	ip := hostIPs[0]
+	if address != "" {
+		if ip = net.ParseIP(address); ip == nil || ip.IsUnspecified() || ip.IsLoopback() {
+			return "", nil, errors.Errorf("--address must be an IP address of this machine on the local network, like %s, not %q", hostIPs[0], address)
+		}
+		found := false
+		for _, hostIP := range hostIPs {
+			found = found || hostIP.Equal(ip)
+		}
+		if !found {
+			certIPs = append(certIPs, ip)
+		}
+	}
+	return "https://" + net.JoinHostPort(ip.String(), strconv.Itoa(port)), certIPs, nil
+}
+
+func init() {
+	exposeAPICmd.Flags().BoolVar(&exposeAPILAN, "lan", false, "Expose the apiserver to the local network")
+	exposeAPICmd.Flags().StringVar(&exposeAPIAddress, "address", "", "The address of this machine the other machines connect to, defaults to the first one found on the local network")
+	exposeAPICmd.Flags().StringVarP(&exposeAPIOutput, "output", "o", "", "The file to write the kubeconfig of the other machines to, defaults to lan-kubeconfig in the minikube directory")
+	RootCmd.AddCommand(exposeAPICmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestLANServer(t *testing.T) {
+	hostIPs := []net.IP{net.ParseIP("192.168.1.23"), net.ParseIP("10.1.2.3")}
+	var tests = []struct {
+		address         string
+		expectedServer  string
+		expectedCertIPs []string
+		shouldErr       bool
+	}{
+		{
+			expectedServer:  "https://192.168.1.23:8443",
+			expectedCertIPs: []string{"127.0.0.1", "192.168.1.23", "10.1.2.3"},
+		},
+		{
+			address:         "10.1.2.3",
+			expectedServer:  "https://10.1.2.3:8443",
+			expectedCertIPs: []string{"127.0.0.1", "192.168.1.23", "10.1.2.3"},
+		},
+		{
+			address:         "203.0.113.7",
+			expectedServer:  "https://203.0.113.7:8443",
+			expectedCertIPs: []string{"127.0.0.1", "192.168.1.23", "10.1.2.3", "203.0.113.7"},
+		},
+		{address: "0.0.0.0", shouldErr: true},
+		{address: "127.0.0.1", shouldErr: true},
+		{address: "laptop.local", shouldErr: true},
+	}
+	for _, test := range tests {
+		server, certIPs, err := lanServer(hostIPs, test.address, 8443)
+		if err != nil {
+			if !test.shouldErr {
+				t.Errorf("Unexpected error for %q: %s", test.address, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Errorf("Expected an error for %q", test.address)
+			continue
+		}
+		if server != test.expectedServer {
+			t.Errorf("Expected server %s for %q, got %s", test.expectedServer, test.address, server)
+		}
+		var actual []string
+		for _, ip := range certIPs {
+			actual = append(actual, ip.String())
+		}
+		if !reflect.DeepEqual(actual, test.expectedCertIPs) {
+			t.Errorf("Expected cert IPs %v for %q, got %v", test.expectedCertIPs, test.address, actual)
+		}
+	}
+}
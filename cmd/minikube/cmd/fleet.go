@@ -0,0 +1,278 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/fleet"
+	"k8s.io/minikube/pkg/version"
+)
+
+const (
+	fleetReportDaemon     = "fleet-report"
+	defaultReportInterval = time.Minute
+)
+
+var (
+	fleetReportInterval time.Duration
+	fleetReportVersion  string
+	fleetListen         string
+	fleetTTL            time.Duration
+)
+
+// fleetCmd represents the fleet command
+var fleetCmd = &cobra.Command{
+	Use:   "fleet SUBCOMMAND",
+	Short: "Lists the clusters of a team, reported to a shared registry.",
+	Long: fmt.Sprintf(`Lists the clusters the minikube of each developer of a team reports to a shared registry, for the
+platform teams supporting them to see their local environments.
+
+The reporting mode is optional: once the URL of the registry is set with
+minikube config set %s URL, minikube start runs minikube fleet report in the background, which reports
+the host, the user, the profile, the status, the driver and the versions of the cluster every minute, until
+the cluster is deleted. The registry is run with minikube fleet serve, and requires the token set with
+minikube config set %s TOKEN when it is set, as the reporters do.`, fleet.EndpointSetting, fleet.TokenSetting),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var fleetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the clusters reported to the registry.",
+	Run: func(cmd *cobra.Command, args []string) {
+		endpoint, err := fleetEndpoint()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		clusters, err := fleet.List(endpoint, viper.GetString(fleet.TokenSetting))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printFleet(os.Stdout, clusters, time.Now())
+	},
+}
+
+var fleetReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports the cluster of the profile to the registry, at each interval.",
+	Long: `Reports the cluster of the profile to the registry, at once and then at each interval.
+
+minikube start runs it in the background when the registry is set, logging to the logs directory of minikube.
+The command runs until interrupted, or until the cluster is deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		endpoint, err := fleetEndpoint()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		unregister, err := daemons.Register(fleetDaemonName())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		ticker := time.NewTicker(fleetReportInterval)
+		defer ticker.Stop()
+		fmt.Printf("Reporting the cluster to %s every %s\n", endpoint, fleetReportInterval)
+		for {
+			if err := reportCluster(endpoint); err != nil {
+				// The registry may be back at the next report
+				glog.Errorln(err)
+			}
+			select {
+			case <-ticker.C:
+			case <-signals:
+				return
+			}
+		}
+	},
+}
+
+var fleetServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs the registry the clusters are reported to.",
+	Long: `Runs the registry the clusters are reported to, serving /clusters over http on the address. It keeps the
+clusters in memory, and lists a cluster until no report was received for the TTL. Serve it behind a proxy
+terminating TLS for the token not to be sent in clear.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		r := fleet.NewRegistry(viper.GetString(fleet.TokenSetting), fleetTTL)
+		if r.Token == "" {
+			fmt.Fprintf(os.Stderr, "WARNING: No token is set with minikube config set %s, anyone reaching %s can report clusters and list them.\n", fleet.TokenSetting, fleetListen)
+		}
+		fmt.Printf("Serving the registry of the clusters on %s\n", fleetListen)
+		if err := http.ListenAndServe(fleetListen, r); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func fleetEndpoint() (string, error) {
+	endpoint := viper.GetString(fleet.EndpointSetting)
+	if endpoint == "" {
+		return "", fmt.Errorf("No registry is configured, set one with: minikube config set %s URL", fleet.EndpointSetting)
+	}
+	return endpoint, nil
+}
+
+// fleetDaemonName is the name of the reporter of the profile, each profile
+// reports its cluster.
+func fleetDaemonName() string {
+	if p := pkgConfig.ActiveProfile(); p != "" {
+		return fleetReportDaemon + "-" + p
+	}
+	return fleetReportDaemon
+}
+
+func reportCluster(endpoint string) error {
+	c := fleet.Cluster{
+		Profile:           pkgConfig.ActiveProfile(),
+		KubernetesVersion: fleetReportVersion,
+		MinikubeVersion:   version.GetVersion(),
+	}
+	c.Host, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		c.User = u.Username
+	}
+	if err := withAPIClient(func(api libmachine.API) error {
+		return describeCluster(api, &c)
+	}); err != nil {
+		return errors.Wrap(err, "Error getting the status of the cluster")
+	}
+	return fleet.Report(endpoint, viper.GetString(fleet.TokenSetting), c)
+}
+
+// describeCluster fills in the status, the driver and the IP of the VM, and
+// the status of localkube when it runs.
+func describeCluster(api libmachine.API, c *fleet.Cluster) error {
+	status, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return err
+	}
+	c.Status = status
+	if exists, err := api.Exists(constants.MachineName); err != nil || !exists {
+		return err
+	}
+	h, err := api.Load(constants.MachineName)
+	if err != nil {
+		return err
+	}
+	c.Driver = h.DriverName
+	if status != state.Running.String() {
+		return nil
+	}
+	// The cluster is reported without what can't be told
+	if c.IP, err = h.Driver.GetIP(); err != nil {
+		glog.Errorln("Error getting the IP of the VM: ", err)
+	}
+	if c.Localkube, err = cluster.GetLocalkubeStatus(api); err != nil {
+		glog.Errorln("Error getting the status of localkube: ", err)
+	}
+	return nil
+}
+
+// startFleetReport runs minikube fleet report in the background for the
+// profile, replacing the one of a previous start.
+func startFleetReport(kubernetesVersion string) error {
+	if _, err := daemons.Stop(fleetDaemonName()); err != nil {
+		return err
+	}
+	logPath := constants.MakeMiniPath("logs", fleetDaemonName()+".log")
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening the log of the fleet report")
+	}
+	defer logFile.Close()
+	args := []string{"fleet", "report", "--kubernetes-version=" + kubernetesVersion}
+	if p := pkgConfig.ActiveProfile(); p != "" {
+		args = append(args, "--"+pkgConfig.ProfileFlag+"="+p)
+	}
+	reporter := exec.Command(os.Args[0], args...)
+	reporter.Stdout = logFile
+	reporter.Stderr = logFile
+	if err := reporter.Start(); err != nil {
+		return errors.Wrap(err, "Error starting the fleet report")
+	}
+	return reporter.Process.Release()
+}
+
+func printFleet(w io.Writer, clusters []fleet.Cluster, now time.Time) {
+	if len(clusters) == 0 {
+		fmt.Fprintln(w, "No cluster reported")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tUSER\tPROFILE\tSTATUS\tDRIVER\tKUBERNETES\tMINIKUBE\tIP\tREPORTED")
+	for _, c := range clusters {
+		profile := c.Profile
+		if profile == "" {
+			profile = "(default)"
+		}
+		status := c.Status
+		if c.Localkube != "" && c.Localkube != state.Running.String() {
+			status += " (localkube " + c.Localkube + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s ago\n", c.Host, c.User, profile, status,
+			orNone(c.Driver), orNone(c.KubernetesVersion), c.MinikubeVersion, orNone(c.IP), now.Sub(c.Reported).Truncate(time.Second))
+	}
+	tw.Flush()
+}
+
+// orNone formats unknown values as -
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	fleetReportCmd.Flags().DurationVar(&fleetReportInterval, "interval", defaultReportInterval, "The interval between the reports")
+	fleetReportCmd.Flags().StringVar(&fleetReportVersion, "kubernetes-version", "", "The version of Kubernetes of the cluster, as started")
+	fleetServeCmd.Flags().StringVar(&fleetListen, "listen", ":8421", "The address to serve the registry on")
+	fleetServeCmd.Flags().DurationVar(&fleetTTL, "ttl", fleet.DefaultTTL, "How long a cluster is listed after its last report")
+	fleetCmd.AddCommand(fleetListCmd)
+	fleetCmd.AddCommand(fleetReportCmd)
+	fleetCmd.AddCommand(fleetServeCmd)
+	RootCmd.AddCommand(fleetCmd)
+}
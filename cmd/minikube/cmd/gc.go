@@ -0,0 +1,197 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/gc"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var (
+	gcDryRun     bool
+	gcCategories = map[string]*bool{}
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Removes what minikube and the cluster no longer use.",
+	Long: `Removes what minikube and the cluster no longer use:
+
+  --images   dangling images in the minikube VM
+  --cache    ISOs and localkube binaries cached on the host, other than those of the current config
+  --drivers  machine directories left by the driver, e.g. after a failed start
+  --pods     completed pods and jobs, in all namespaces
+  --tunnels  registrations of port forwards and tunnels which exited without cleaning up, and the routes of the tunnels
+
+Everything is collected when no category is given. Images, pods and jobs are only collected while minikube is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		all := true
+		for _, selected := range gcCategories {
+			if *selected {
+				all = false
+			}
+		}
+		running := isMinikubeRunning(api)
+		collected := 0
+		for _, c := range gc.Categories {
+			if !all && !*gcCategories[c] {
+				continue
+			}
+			if (c == gc.Images || c == gc.Pods) && !running {
+				fmt.Printf("Skipping %s, minikube is not running.\n", c)
+				continue
+			}
+			items, err := collect(api, c, gcDryRun)
+			for _, item := range items {
+				if gcDryRun {
+					fmt.Printf("Would remove %s: %s\n", c, item)
+				} else {
+					fmt.Printf("Removed %s: %s\n", c, item)
+				}
+			}
+			collected += len(items)
+			if err != nil {
+				glog.Errorf("Error collecting %s: %s", c, err)
+				os.Exit(1)
+			}
+		}
+		if collected == 0 {
+			fmt.Println("Nothing to remove.")
+		}
+	},
+}
+
+func isMinikubeRunning(api libmachine.API) bool {
+	s, err := cluster.GetHostStatus(api)
+	return err == nil && s == state.Running.String()
+}
+
+// collect removes what is no longer used in the category and returns it, or
+// only returns it for a dry run.
+func collect(api libmachine.API, category string, dryRun bool) ([]string, error) {
+	switch category {
+	case gc.Images:
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			return nil, err
+		}
+		return cluster.PruneImages(h, dryRun)
+	case gc.Cache:
+		iso, err := isoForKernelVariant(viper.GetString(kernelVariant), viper.GetString(isoURL))
+		if err != nil {
+			return nil, err
+		}
+		stale, err := gc.StaleCacheFiles([]string{
+			pkgutil.DefaultDownloader{}.GetISOCacheFilepath(iso),
+			cluster.LocalkubeCacheFilepath(viper.GetString(kubernetesVersion)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return removePaths(stale, dryRun)
+	case gc.Drivers:
+		orphaned, err := gc.OrphanedMachines()
+		if err != nil {
+			return nil, err
+		}
+		return removePaths(orphaned, dryRun)
+	case gc.Pods:
+		client, err := service.GetClientset()
+		if err != nil {
+			return nil, err
+		}
+		pods, err := gc.CompletedPods(client.Core())
+		if err != nil {
+			return nil, err
+		}
+		jobs, err := gc.CompletedJobs(client.Batch())
+		if err != nil {
+			return nil, err
+		}
+		if !dryRun {
+			if err := gc.DeleteJobs(client.Batch(), jobs); err != nil {
+				return nil, err
+			}
+			if err := gc.DeletePods(client.Core(), pods); err != nil {
+				return nil, err
+			}
+		}
+		items := make([]string, 0, len(jobs)+len(pods))
+		for _, j := range jobs {
+			items = append(items, "job "+j)
+		}
+		for _, p := range pods {
+			items = append(items, "pod "+p)
+		}
+		return items, nil
+	case gc.Tunnels:
+		stale, err := daemons.Stale()
+		if err != nil {
+			return nil, err
+		}
+		var items []string
+		for _, d := range stale {
+			if !dryRun {
+				if err := tunnel.Cleanup(d); err != nil {
+					return items, err
+				}
+			}
+			items = append(items, d.Name)
+			for _, r := range d.Routes {
+				items = append(items, fmt.Sprintf("route to %s via %s", r.Network, r.Gateway))
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("Unknown category %s", category)
+}
+
+func removePaths(paths []string, dryRun bool) ([]string, error) {
+	if dryRun {
+		return paths, nil
+	}
+	return gc.RemovePaths(paths)
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Only list what would be removed")
+	for _, c := range gc.Categories {
+		gcCategories[c] = gcCmd.Flags().Bool(c, false, fmt.Sprintf("Remove the unused %s", c))
+	}
+	RootCmd.AddCommand(gcCmd)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/hooks"
+)
+
+// runHook runs the hook of the event set in the config, with the
+// environment of the plugins, and exits if it fails: before its command,
+// which must not run then, and after, for scripts to notice.
+func runHook(e hooks.Event, stdout io.Writer) {
+	command := viper.GetString(e.Setting())
+	if command == "" {
+		return
+	}
+	fmt.Fprintf(stdout, "Running the %s hook...\n", e)
+	if err := hooks.Run(e, command, pluginEnv().Vars(), stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
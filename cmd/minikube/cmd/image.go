@@ -0,0 +1,147 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/scan"
+)
+
+var (
+	scanScanner      string
+	scanScannerImage string
+	scanSeverity     string
+	scanExitCode     int
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image SUBCOMMAND",
+	Short: "Manages the images of the minikube VM.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var imageScanCmd = &cobra.Command{
+	Use:   "scan [IMAGE...]",
+	Short: "Scans images of the minikube VM for vulnerabilities.",
+	Long: `Scans images of the minikube VM for vulnerabilities, all the tagged images when none is given,
+e.g. those built with minikube docker-env, before pushing them.
+
+The scanner runs in a container of the VM, reaching its docker daemon through its socket, and
+keeps its vulnerability database on the disk of the VM. Scanners: ` + strings.Join(scan.Names(), ", ") + `.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		severities, err := scan.ParseSeverities(scanSeverity)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		scanner, err := scan.Get(scanScanner, scanScannerImage)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, image := range args {
+			if err := scan.ValidateImageName(image); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+
+		api := pauseAPIClient()
+		defer api.Close()
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		images := args
+		if len(images) == 0 {
+			all, err := cluster.ListImages(h)
+			if err != nil {
+				glog.Errorln("Error listing images: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			for _, image := range all {
+				if image != scanner.Image() {
+					images = append(images, image)
+				}
+			}
+		}
+
+		var findings []scan.Finding
+		failed := false
+		for _, image := range images {
+			fmt.Fprintf(os.Stderr, "Scanning %s...\n", image)
+			found, err := cluster.ScanImage(h, scanner, image, severities)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+				continue
+			}
+			findings = append(findings, found...)
+		}
+		scan.Sort(findings)
+		if err := printFindings(os.Stdout, findings); err != nil {
+			glog.Errorln("Error printing the findings: ", err)
+			os.Exit(1)
+		}
+		if failed {
+			os.Exit(1)
+		}
+		if len(findings) > 0 {
+			os.Exit(scanExitCode)
+		}
+	},
+}
+
+func printFindings(w io.Writer, findings []scan.Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "No vulnerabilities found.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tID\tSEVERITY\tPACKAGE\tINSTALLED\tFIXED")
+	for _, f := range findings {
+		fixed := f.Fixed
+		if fixed == "" {
+			fixed = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", f.Image, f.ID, f.Severity, f.Package, f.Installed, fixed)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	imageScanCmd.Flags().StringVar(&scanScanner, "scanner", "trivy", "The scanner to run, one of "+strings.Join(scan.Names(), ", "))
+	imageScanCmd.Flags().StringVar(&scanScannerImage, "scanner-image", "", "The image of the scanner, instead of its default image, e.g. from a local registry")
+	imageScanCmd.Flags().StringVar(&scanSeverity, "severity", strings.Join(scan.Severities, ","), "The comma separated severities of the vulnerabilities to report")
+	imageScanCmd.Flags().IntVar(&scanExitCode, "exit-code", 0, "The exit code when vulnerabilities are reported, to fail scripts vetting images")
+	imageCmd.AddCommand(imageScanCmd)
+	RootCmd.AddCommand(imageCmd)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/kubernetes/pkg/util/homedir"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/integrations"
+)
+
+// integrationsCmd represents the integrations command
+var integrationsCmd = &cobra.Command{
+	Use:   "integrations",
+	Short: "Lists the integrations writing the connection files of other tools after start.",
+	Long: fmt.Sprintf(`Lists the integrations writing the files other tools, like IDEs, Lens, Telepresence or Tilt,
+connect to the cluster with, and the path of their file. minikube start writes the files of the integrations
+enabled with, e.g. for the active profile:
+
+  minikube config set %[1]s lens,tilt
+
+An integration is replaced, or a new one added, by a directory named after it under ~/.minikube/integrations, holding the
+template of the path of its file in "path", and the template of its contents in "template". The templates
+are Go templates of the fields Profile, Context, Server, Kubeconfig, CertificateAuthority, ClientCertificate,
+ClientKey and Home.`, integrations.Setting),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := printIntegrations(os.Stdout, integrationConnection(kubeconfigPath(), ""), integrations.Enabled(viper.GetString(integrations.Setting))); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func printIntegrations(w io.Writer, c integrations.Connection, enabled []string) error {
+	names, err := integrations.List()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tENABLED\tPATH")
+	for _, name := range names {
+		status := "no"
+		for _, e := range enabled {
+			if e == name {
+				status = "yes"
+			}
+		}
+		path := "-"
+		if i, err := integrations.Load(name); err == nil {
+			if p, _, err := i.Render(c); err == nil {
+				path = p
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, status, path)
+	}
+	return tw.Flush()
+}
+
+// integrationConnection returns what the integrations are rendered with for
+// the context of the profile in the kubeconfig, pointing to the server.
+func integrationConnection(kubeConfigFile, server string) integrations.Connection {
+	return integrations.Connection{
+		Profile:              config.ActiveProfile(),
+		Context:              config.KubeContext(),
+		Server:               server,
+		Kubeconfig:           kubeConfigFile,
+		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+		ClientCertificate:    constants.MakeMiniPath("apiserver.crt"),
+		ClientKey:            constants.MakeMiniPath("apiserver.key"),
+		Home:                 homedir.HomeDir(),
+	}
+}
+
+// writeIntegrations writes the files of the integrations enabled in the
+// config, and returns their paths. Failures are only logged, as the cluster
+// is usable without them.
+func writeIntegrations(c integrations.Connection) []string {
+	var written []string
+	for _, name := range integrations.Enabled(viper.GetString(integrations.Setting)) {
+		i, err := integrations.Load(name)
+		if err == nil {
+			var path string
+			if path, err = i.Write(c); err == nil {
+				fmt.Printf("Wrote the %s integration to %s\n", name, path)
+				written = append(written, path)
+			}
+		}
+		if err != nil {
+			glog.Errorf("Error writing the %s integration: %s", name, err)
+		}
+	}
+	return written
+}
+
+func init() {
+	RootCmd.AddCommand(integrationsCmd)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/kubectl"
+)
+
+const kubectlEnvTmpl = `{{ .Prefix }}PATH{{ .Delimiter }}{{ .Path }}{{ .Suffix }}{{ .UsageHint }}`
+
+// KubectlShellConfig is the PATH printed by minikube kubectl env
+type KubectlShellConfig struct {
+	Prefix    string
+	Delimiter string
+	Suffix    string
+	Path      string
+	UsageHint string
+}
+
+var kubectlInstallVersion string
+
+// kubectlCmd represents the kubectl command
+var kubectlCmd = &cobra.Command{
+	Use:   "kubectl SUBCOMMAND",
+	Short: "Installs the kubectl matching the Kubernetes version of the profile.",
+	Long: `Installs the kubectl of the exact Kubernetes version of the cluster of the profile into the bin directory of
+minikube, with a shim running it, so that kubectl and the cluster are never skewed. Each profile has its shim,
+in its directory added to the PATH with minikube kubectl env.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var kubectlInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Installs the kubectl of the Kubernetes version of the profile, and its shim.",
+	Long: `Downloads the kubectl of the Kubernetes version of the profile, checks it runs as that version, and points
+the shim of the profile to it. The version is the one the cluster runs, or the one it is configured to start
+with when it is not running, unless set with --kubernetes-version. minikube start keeps the installed shim of
+the profile in step with the version of the cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		version := kubectlInstallVersion
+		if version == "" {
+			var err error
+			if err = withAPIClient(func(api libmachine.API) error {
+				version, err = profileKubernetesVersion(api)
+				return err
+			}); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		shim, err := installKubectl(version)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("kubectl %s is installed for the profile in %s\n", kubectl.Release(version), shim)
+		envCmd := "minikube kubectl env"
+		if p := pkgConfig.ActiveProfile(); p != "" {
+			envCmd += " -p " + p
+		}
+		fmt.Printf("Add it to your PATH with: eval $(%s)\n", envCmd)
+	},
+}
+
+var kubectlEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Sets up the PATH to use the kubectl of the profile.",
+	Long:  `Prints the PATH with the directory of the shims of the profile first, or without it with --unset.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		shellCfg, err := kubectlShellCfg(os.Getenv("PATH"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		tmpl := template.Must(template.New("kubectlEnvConfig").Parse(kubectlEnvTmpl))
+		tmpl.Execute(os.Stdout, shellCfg)
+	},
+}
+
+// profileKubernetesVersion returns the version the cluster of the profile
+// runs, or the one it starts with.
+func profileKubernetesVersion(api libmachine.API) (string, error) {
+	version := viper.GetString(kubernetesVersion)
+	status, err := cluster.GetHostStatus(api)
+	if err != nil || status != state.Running.String() {
+		return version, nil
+	}
+	h, err := api.Load(constants.MachineName)
+	if err != nil {
+		return "", err
+	}
+	running, err := cluster.GetKubernetesVersion(h)
+	if err != nil {
+		return "", err
+	}
+	if running != "" {
+		version = running
+	}
+	return version, nil
+}
+
+// installKubectl installs the kubectl of the version for the profile, and
+// returns the path of its shim.
+func installKubectl(version string) (string, error) {
+	if _, err := kubectl.Install(version); err != nil {
+		return "", err
+	}
+	return kubectl.WriteShim(pkgConfig.ActiveProfile(), version)
+}
+
+// updateKubectlShim installs the kubectl of the version the cluster started
+// with for the profile, when it has a shim of another version.
+func updateKubectlShim(version string) error {
+	installed, err := kubectl.ShimVersion(pkgConfig.ActiveProfile())
+	if err != nil || installed == "" || installed == kubectl.Release(version) {
+		return err
+	}
+	if kubectl.ValidateVersion(version) != nil {
+		// A localkube URI has no kubectl, the shim keeps the one installed
+		return nil
+	}
+	fmt.Printf("Installing kubectl %s for the profile...\n", kubectl.Release(version))
+	_, err = installKubectl(version)
+	return err
+}
+
+func kubectlShellCfg(path string) (*KubectlShellConfig, error) {
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+	dir := kubectl.ShimDir(pkgConfig.ActiveProfile())
+	shellCfg := &KubectlShellConfig{
+		Path:      kubectl.PathWith(path, dir),
+		UsageHint: strings.Replace(generateUsageHint(userShell), "docker-env", "kubectl env", -1),
+	}
+	if unset {
+		shellCfg.Path = kubectl.PathWithout(path, dir)
+	}
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shellSetSyntax(userShell)
+	return shellCfg, nil
+}
+
+func init() {
+	kubectlInstallCmd.Flags().StringVar(&kubectlInstallVersion, "kubernetes-version", "", "The version of kubectl to install, the Kubernetes version of the profile by default")
+	kubectlEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	kubectlEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Remove the directory of the shims from the PATH instead of adding it")
+	kubectlCmd.AddCommand(kubectlInstallCmd)
+	kubectlCmd.AddCommand(kubectlEnvCmd)
+	RootCmd.AddCommand(kubectlCmd)
+}
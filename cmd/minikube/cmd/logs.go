@@ -21,6 +21,8 @@ import (
 	"log"
 	"os"
 
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
@@ -28,14 +30,20 @@ import (
 )
 
 var (
-	follow bool
+	follow        bool
+	logsPod       string
+	logsNamespace string
+	logsContainer string
 )
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Gets the logs of the running localkube instance, used for debugging minikube, not user code.",
-	Long:  `Gets the logs of the running localkube instance, used for debugging minikube, not user code.`,
+	Long: `Gets the logs of the running localkube instance, used for debugging minikube, not user code.
+
+--pod prints the logs of a container of a pod instead, read from the files docker writes them to in the VM, which
+works when the apiserver is down.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		api, err := machine.NewAPIClient(clientType)
 		if err != nil {
@@ -43,6 +51,13 @@ var logsCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
+		if logsPod != "" {
+			if err := podLogs(api); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
 		s, err := cluster.GetHostLogs(api, follow)
 		if err != nil {
 			log.Println("Error getting machine logs:", err)
@@ -52,7 +67,27 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// podLogs prints the logs of the container of --pod.
+func podLogs(api libmachine.API) error {
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error getting the host")
+	}
+	runner := cluster.NewCommandRunner(h)
+	files, err := cluster.PodLogFiles(runner, logsNamespace, logsPod, logsContainer)
+	if err != nil {
+		return err
+	}
+	if follow {
+		return cluster.FollowPodLogs(h, os.Stdout, files)
+	}
+	return cluster.PodLogs(runner, os.Stdout, files)
+}
+
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
+	logsCmd.Flags().StringVar(&logsPod, "pod", "", "Print the logs of a container of the pod, read from the VM without the apiserver")
+	logsCmd.Flags().StringVarP(&logsNamespace, "namespace", "n", "", "The namespace of the pod, needed when pods of several namespaces have its name")
+	logsCmd.Flags().StringVarP(&logsContainer, "container", "c", "", "The container of the pod, needed when it has several")
 	RootCmd.AddCommand(logsCmd)
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/migrate"
+)
+
+var migrateDryRun bool
+
+// migrateDirsCmd represents the migrate-dirs command
+var migrateDirsCmd = &cobra.Command{
+	Use:   "migrate-dirs",
+	Short: "Moves the files of ~/.minikube to the relocated config, cache and state directories.",
+	Long: `Moves the files of ~/.minikube to the relocated config, cache and state directories.
+
+The directories are relocated by the MINIKUBE_CONFIG_DIR, MINIKUBE_CACHE_DIR and MINIKUBE_STATE_DIR
+environment variables, the cache-dir and state-dir config keys, or the XDG_CONFIG_HOME, XDG_CACHE_HOME
+and XDG_DATA_HOME base directories. An existing ~/.minikube keeps being used over the XDG base
+directories until it is migrated.
+
+The state cannot be moved while it holds machines, delete them with minikube delete first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kinds := []constants.PathKind{constants.ConfigPath, constants.CachePath, constants.StatePath}
+		// Plan everything first, as moving the config may move the keys
+		// relocating the other directories.
+		var moves []migrate.Move
+		failed := false
+		for _, kind := range kinds {
+			m, err := migrate.Plan(kind)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Not moving the %s: %s\n", kind, err)
+				failed = true
+				continue
+			}
+			moves = append(moves, m...)
+		}
+		if len(moves) == 0 {
+			fmt.Println("Nothing to move.")
+		}
+		if migrateDryRun {
+			for _, m := range moves {
+				fmt.Printf("Would move %s to %s\n", m.From, m.To)
+			}
+		} else {
+			done, err := migrate.Run(moves)
+			for _, m := range done {
+				fmt.Printf("Moved %s to %s\n", m.From, m.To)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error moving files: %s\n", err)
+				os.Exit(1)
+			}
+			migrate.RemoveEmpty()
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	migrateDirsCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Only print what would be moved")
+	RootCmd.AddCommand(migrateDirsCmd)
+}
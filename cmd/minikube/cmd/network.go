@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/netbench"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	benchmarkNamespace string
+	benchmarkDuration  time.Duration
+	benchmarkTimeout   time.Duration
+)
+
+// networkCmd represents the network command
+var networkCmd = &cobra.Command{
+	Use:   "network SUBCOMMAND",
+	Short: "Diagnoses the network of the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var networkBenchmarkCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Measures the network throughput between the host, the nodes and the pods.",
+	Long: `Measures the network throughput and latency from the host to the node, from the node to a pod, and
+from a pod of another node to that pod when the cluster has several nodes, and compares them with the
+throughput expected with the driver. A path much slower than expected usually is a misconfigured virtual
+NIC, e.g. an emulated one instead of a paravirtualized one.
+
+The host streams to the node over ssh. The other paths are measured with iperf3 pods, running the
+` + netbench.Image + ` image. It exits with status 1 when a path is slower than expected.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Measuring the network throughput, this takes about a minute...")
+		results, err := netbench.Run(client, h.Driver, benchmarkNamespace, benchmarkDuration, benchmarkTimeout)
+		if len(results) > 0 {
+			netbench.PrintResults(os.Stdout, results)
+		}
+		if err != nil {
+			glog.Errorln("Error running the benchmark: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		for _, r := range results {
+			if r.Slow() {
+				fmt.Fprintf(os.Stderr, "The %s throughput is lower than expected with the %s driver, check the network adapter of the VM.\n", r.Path, h.Driver.DriverName())
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	networkBenchmarkCmd.Flags().StringVarP(&benchmarkNamespace, "namespace", "n", "default", "The namespace of the iperf3 pods")
+	networkBenchmarkCmd.Flags().DurationVar(&benchmarkDuration, "duration", 10*time.Second, "How long each iperf3 client measures the throughput")
+	networkBenchmarkCmd.Flags().DurationVar(&benchmarkTimeout, "timeout", 2*time.Minute, "How long to wait for each iperf3 pod, including pulling its image")
+	networkCmd.AddCommand(networkBenchmarkCmd)
+	RootCmd.AddCommand(networkCmd)
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	nodeDeleteForce  bool
+	nodeDrainTimeout time.Duration
+)
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node SUBCOMMAND",
+	Short: "Manages the worker nodes of the cluster.",
+	Long: `Manages the worker nodes of the cluster, each in its own VM besides the minikube VM.
+
+The nodes are created with the VM settings of minikube start: driver, memory, cpus, disk size
+and ISO. They run the kubelet and the proxy of the Kubernetes version of the cluster, which
+needs to be the bundled version. Pods on different nodes can only reach each other through
+services unless a network plugin is configured.
+
+The additional control planes of minikube start --control-planes are listed as nodes cp02, cp03...
+They can be stopped and started to rehearse control plane failures, but not deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var nodeAddCmd = &cobra.Command{
+	Use:   "add [NAME]",
+	Short: "Adds a worker node to the cluster, named m02, m03... by default.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube node add [NAME]")
+			os.Exit(1)
+		}
+		api := nodeAPIClient()
+		defer api.Close()
+
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		} else {
+			nodes, err := cluster.ListNodes(api)
+			if err != nil {
+				glog.Errorln("Error listing nodes: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			name = cluster.NextNodeName(nodes)
+		}
+		if err := cluster.ValidateNodeName(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if cluster.IsControlPlaneNode(name) {
+			fmt.Fprintf(os.Stderr, "Node names like %s are reserved for the control planes, see minikube start --control-planes\n", name)
+			os.Exit(1)
+		}
+		if exists, err := cluster.NodeExists(api, name); err != nil || exists {
+			fmt.Fprintf(os.Stderr, "Node %s already exists, start it with minikube node start %s\n", name, name)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Adding node %s...\n", name)
+		startNode(api, name)
+		fmt.Printf("Node %s joined the cluster as %s.\n", name, constants.NodeMachineName(name))
+	},
+}
+
+var nodeStartCmd = &cobra.Command{
+	Use:   "start NAME",
+	Short: "Starts a stopped worker node and joins it to the cluster again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := nodeNameArg("start", args)
+		api := nodeAPIClient()
+		defer api.Close()
+		requireNode(api, name)
+
+		fmt.Printf("Starting node %s...\n", name)
+		startNode(api, name)
+		fmt.Printf("Node %s started.\n", name)
+	},
+}
+
+var nodeStopCmd = &cobra.Command{
+	Use:   "stop NAME",
+	Short: "Stops the VM of a worker node, leaving its files intact.",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := nodeNameArg("stop", args)
+		api := nodeAPIClient()
+		defer api.Close()
+		requireNode(api, name)
+
+		if err := cluster.StopNode(api, name); err != nil {
+			glog.Errorln("Error stopping node: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Node %s stopped.\n", name)
+	},
+}
+
+var nodeDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Drains a worker node, removes it from the cluster and deletes its VM.",
+	Long: `Drains a worker node, removes it from the cluster and deletes its VM.
+
+Draining marks the node unschedulable and deletes its pods, but those of daemon sets, so that
+their controllers recreate them on the other nodes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := nodeNameArg("delete", args)
+		if cluster.IsControlPlaneNode(name) {
+			fmt.Fprintf(os.Stderr, "Control plane %s cannot be deleted, stop it with minikube node stop %s or delete the cluster\n", name, name)
+			os.Exit(1)
+		}
+		api := nodeAPIClient()
+		defer api.Close()
+		requireNode(api, name)
+
+		if err := removeNode(api, name); err != nil {
+			if !nodeDeleteForce {
+				fmt.Fprintf(os.Stderr, "Error draining node %s: %s\nRun with --force to delete it anyway.\n", name, err)
+				os.Exit(1)
+			}
+			glog.Errorf("Error draining node %s, deleting it anyway: %s", name, err)
+		}
+		if err := cluster.DeleteNode(api, name); err != nil {
+			glog.Errorln("Error deleting node: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Node %s deleted.\n", name)
+	},
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes of the cluster, with the status and IP of their VM.",
+	Run: func(cmd *cobra.Command, args []string) {
+		api := nodeAPIClient()
+		defer api.Close()
+
+		if err := printNodes(os.Stdout, api); err != nil {
+			glog.Errorln("Error listing nodes: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	},
+}
+
+func nodeAPIClient() libmachine.API {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	return api
+}
+
+func nodeNameArg(command string, args []string) string {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: minikube node %s NAME\n", command)
+		os.Exit(1)
+	}
+	return args[0]
+}
+
+func requireNode(api libmachine.API, name string) {
+	exists, err := cluster.NodeExists(api, name)
+	if err != nil {
+		glog.Errorln("Error checking the node exists: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Node %s does not exist, see minikube node list\n", name)
+		os.Exit(1)
+	}
+}
+
+// startNode starts the VM of the node, creating it if needed, and joins it
+// to the cluster of the minikube VM, as a worker or as a control plane.
+func startNode(api libmachine.API, name string) {
+	if !isMinikubeRunning(api) {
+		fmt.Fprintln(os.Stderr, "minikube is not running, start it with minikube start first.")
+		os.Exit(1)
+	}
+	master, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		glog.Errorln("Error loading the minikube VM: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	masterIP, err := master.Driver.GetIP()
+	if err != nil {
+		glog.Errorln("Error getting the IP of the minikube VM: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	config, err := machineConfig()
+	if err != nil {
+		glog.Errorln("Error configuring the VM:", err)
+		os.Exit(1)
+	}
+	config.MachineName = constants.NodeMachineName(name)
+	// The address is the one of the minikube VM, the nodes get theirs leased
+	config.StaticIP = ""
+
+	var h *host.Host
+	start := func() (err error) {
+		h, err = cluster.StartHost(api, config)
+		if err != nil {
+			glog.Errorf("Error starting host: %s.\n\n Retrying.\n", err)
+		}
+		return err
+	}
+	if err := util.RetryAfter(5, start, 2*time.Second); err != nil {
+		glog.Errorln("Error starting host: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	if err := cluster.Unpause(h); err != nil {
+		glog.Errorln("Error unpausing the node: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		glog.Errorln("Error getting the IP of the node: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	cniName, plugin, err := networkConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	kubernetesConfig := cluster.KubernetesConfig{
+		KubernetesVersion:     viper.GetString(kubernetesVersion),
+		NodeIP:                ip,
+		APIServerName:         viper.GetString(apiServerName),
+		APIServerPort:         viper.GetInt(apiServerPort),
+		FeatureGates:          viper.GetString(featureGates),
+		ContainerRuntime:      viper.GetString(containerRuntime),
+		NetworkPlugin:         plugin,
+		PodNetworkCIDR:        viper.GetString(podNetworkCIDR),
+		ServiceClusterIPRange: viper.GetString(serviceClusterIPRange),
+		Reserved:              reservedResources(config.Memory),
+		ExtraOptions:          componentOptions(cniName),
+		CryptoMode:            util.GetCryptoMode(),
+		SeccompDefault:        viper.GetBool(seccompDefault),
+		SeccompProfilesDir:    viper.GetString(seccompProfiles),
+	}
+
+	controlPlane := cluster.IsControlPlaneNode(name)
+	if controlPlane {
+		kubernetesConfig.EtcdJoin = cluster.EtcdClientURL(masterIP)
+		kubernetesConfig.EtcdPeerURL = cluster.EtcdPeerURL(ip)
+	} else {
+		kubernetesConfig.Master = fmt.Sprintf("https://%s:%d", masterIP, kubernetesConfig.APIServerPort)
+	}
+	if err := cluster.CheckBundledOptions(kubernetesConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if controlPlane {
+		fmt.Println("Joining the control planes...")
+		err = cluster.JoinControlPlane(h, h.Driver, kubernetesConfig, config.MachineName)
+	} else {
+		fmt.Println("Joining the cluster...")
+		err = cluster.JoinCluster(h, h.Driver, kubernetesConfig, config.MachineName)
+	}
+	if err != nil {
+		glog.Errorln("Error joining the cluster: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+}
+
+// removeNode drains the Kubernetes node of the VM and removes it from the
+// cluster. Nothing is left to drain when minikube is not running.
+func removeNode(api libmachine.API, name string) error {
+	if !isMinikubeRunning(api) {
+		return nil
+	}
+	client, err := service.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the kubernetes client")
+	}
+	nodeName := constants.NodeMachineName(name)
+	fmt.Printf("Draining node %s...\n", name)
+	deleted, err := node.Drain(client.Core(), nodeName, nodeDrainTimeout)
+	for _, p := range deleted {
+		fmt.Printf("Deleted pod %s\n", p)
+	}
+	if err != nil {
+		return err
+	}
+	return node.Delete(client.Core(), nodeName)
+}
+
+func printNodes(w io.Writer, api libmachine.API) error {
+	nodes, err := cluster.ListNodes(api)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tROLE\tSTATUS\tIP")
+	status, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", constants.MachineName, "master", status, hostIP(api, constants.MachineName, status))
+	for _, n := range nodes {
+		status, err := cluster.GetNodeStatus(api, n)
+		if err != nil {
+			return err
+		}
+		role := "worker"
+		if cluster.IsControlPlaneNode(n) {
+			role = "control-plane"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", n, role, status, hostIP(api, constants.NodeMachineName(n), status))
+	}
+	return tw.Flush()
+}
+
+// hostIP returns the IP of the running machine, or "-".
+func hostIP(api libmachine.API, machineName, status string) string {
+	if status != state.Running.String() {
+		return "-"
+	}
+	h, err := api.Load(machineName)
+	if err != nil {
+		return "-"
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return "-"
+	}
+	return ip
+}
+
+func init() {
+	nodeDeleteCmd.Flags().BoolVar(&nodeDeleteForce, "force", false, "Delete the node even if it cannot be drained")
+	nodeDeleteCmd.Flags().DurationVar(&nodeDrainTimeout, "drain-timeout", 2*time.Minute, "How long to wait for the pods of the node to terminate")
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeCmd.AddCommand(nodeStartCmd)
+	nodeCmd.AddCommand(nodeStopCmd)
+	nodeCmd.AddCommand(nodeDeleteCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	RootCmd.AddCommand(nodeCmd)
+}
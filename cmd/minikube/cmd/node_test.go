@@ -0,0 +1,75 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestPrintNodes(t *testing.T) {
+	api := &tests.MockAPI{
+		Hosts: map[string]*host.Host{
+			constants.MachineName: {
+				Name:   constants.MachineName,
+				Driver: &tests.MockDriver{CurrentState: state.Running, BaseDriver: drivers.BaseDriver{IPAddress: "192.168.99.100"}},
+			},
+			constants.NodeMachineName("m03"): {
+				Name:   constants.NodeMachineName("m03"),
+				Driver: &tests.MockDriver{CurrentState: state.Stopped},
+			},
+			constants.NodeMachineName("m02"): {
+				Name:   constants.NodeMachineName("m02"),
+				Driver: &tests.MockDriver{CurrentState: state.Running, BaseDriver: drivers.BaseDriver{IPAddress: "192.168.99.101"}},
+			},
+			constants.NodeMachineName("cp02"): {
+				Name:   constants.NodeMachineName("cp02"),
+				Driver: &tests.MockDriver{CurrentState: state.Running, BaseDriver: drivers.BaseDriver{IPAddress: "192.168.99.102"}},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := printNodes(&b, api); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `NAME      ROLE           STATUS   IP
+minikube  master         Running  192.168.99.100
+cp02      control-plane  Running  192.168.99.102
+m02       worker         Running  192.168.99.101
+m03       worker         Stopped  -
+`
+	if b.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+
+	backends, err := controlPlaneAPIServers(api, constants.APIServerPort)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expectedBackends := []string{"192.168.99.100:8443", "192.168.99.102:8443"}
+	if !reflect.DeepEqual(backends, expectedBackends) {
+		t.Errorf("Expected the apiservers %v, got %v", expectedBackends, backends)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pauses the local kubernetes cluster, keeping the VM running.",
+	Long: `Pauses the local kubernetes cluster, freezing localkube and the containers of the minikube VM and of the nodes.
+The VMs keep running and the cluster keeps its state, but no longer uses the CPU. The cluster is resumed with the "unpause" command, or the "start" command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api := pauseAPIClient()
+		defer api.Close()
+		if err := pauseCluster(api); err != nil {
+			glog.Errorln("Error pausing the cluster: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Cluster paused.")
+	},
+}
+
+// unpauseCmd represents the unpause command
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause",
+	Short: "Resumes a local kubernetes cluster paused with the pause command.",
+	Long:  `Resumes localkube and the containers of the minikube VM and of the nodes, paused with the "pause" command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api := pauseAPIClient()
+		defer api.Close()
+		if err := unpauseCluster(api); err != nil {
+			glog.Errorln("Error unpausing the cluster: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Cluster unpaused.")
+	},
+}
+
+// pauseCluster pauses the running nodes, then the minikube VM. Errors pausing
+// a node are only printed.
+func pauseCluster(api libmachine.API) error {
+	nodes, err := cluster.ListNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing nodes: ", err)
+	}
+	for _, n := range nodes {
+		h := runningNode(api, n)
+		if h == nil {
+			continue
+		}
+		if err := cluster.Pause(h); err != nil {
+			fmt.Printf("Error pausing node %s: %s\n", n, err)
+		}
+	}
+
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	return cluster.Pause(h)
+}
+
+// unpauseCluster unpauses the minikube VM, then the running nodes.
+func unpauseCluster(api libmachine.API) error {
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	if err := cluster.Unpause(h); err != nil {
+		return err
+	}
+
+	nodes, err := cluster.ListNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing nodes: ", err)
+	}
+	for _, n := range nodes {
+		h := runningNode(api, n)
+		if h == nil {
+			continue
+		}
+		if err := cluster.Unpause(h); err != nil {
+			fmt.Printf("Error unpausing node %s: %s\n", n, err)
+		}
+	}
+	return nil
+}
+
+// pauseAPIClient returns the client of the machines, exiting unless the
+// minikube VM is running.
+func pauseAPIClient() libmachine.API {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	if !isMinikubeRunning(api) {
+		fmt.Fprintln(os.Stderr, "minikube is not running.")
+		os.Exit(1)
+	}
+	return api
+}
+
+// runningNode loads the VM of the node, or returns nil if it isn't running.
+func runningNode(api libmachine.API, node string) *host.Host {
+	if s, err := cluster.GetNodeStatus(api, node); err != nil || s != state.Running.String() {
+		return nil
+	}
+	h, err := cluster.LoadNode(api, node)
+	if err != nil {
+		glog.Errorln("Error loading node: ", err)
+		return nil
+	}
+	return h
+}
+
+func init() {
+	RootCmd.AddCommand(pauseCmd)
+	RootCmd.AddCommand(unpauseCmd)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/plugins"
+)
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin SUBCOMMAND",
+	Short: "Manages the plugins adding subcommands to minikube.",
+	Long: `Manages the plugins, the executables named minikube-<name> in the PATH, which minikube runs as
+"minikube <name>", with the arguments after the name. The first one in the PATH runs, and a builtin
+command of the same name runs instead of the plugin.
+
+The plugins are run with the environment of minikube, and:
+
+  MINIKUBE_PROFILE                the active profile, which minikube run by the plugin uses too
+  MINIKUBE_BIN                    the minikube running the plugin
+  MINIKUBE_KUBECONFIG             the kubeconfig of the cluster
+  MINIKUBE_CONTEXT                the context of the cluster in the kubeconfig
+  MINIKUBE_MACHINE_NAME           the name of the VM
+  MINIKUBE_MACHINE_IP             the address of the VM, when it is running, as the variables below
+  MINIKUBE_MACHINE_SSH_HOST       the host, port, user and private key to ssh into the VM with
+  MINIKUBE_MACHINE_SSH_PORT
+  MINIKUBE_MACHINE_SSH_USER
+  MINIKUBE_MACHINE_SSH_KEY`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the plugins in the PATH, and the ones which don't run as others have their name.",
+	Run: func(cmd *cobra.Command, args []string) {
+		printPlugins(os.Stdout, RootCmd, plugins.Find(os.Getenv("PATH")))
+	},
+}
+
+// builtinCommand returns the builtin command of the root command named
+// after the plugin, if any.
+func builtinCommand(root *cobra.Command, name string) *cobra.Command {
+	for _, c := range root.Commands() {
+		if !pluginCommands[c] && (c.Name() == name || c.HasAlias(name)) {
+			return c
+		}
+	}
+	return nil
+}
+
+// pluginCommands are the commands running plugins, which addPluginCommands
+// added.
+var pluginCommands = map[*cobra.Command]bool{}
+
+func printPlugins(w io.Writer, root *cobra.Command, found []plugins.Plugin) {
+	if len(found) == 0 {
+		fmt.Fprintf(w, "No plugin found, plugins are the executables named %s<name> in the PATH\n", plugins.Prefix)
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPATH\tSTATUS")
+	for _, p := range found {
+		status := "ok"
+		if p.ShadowedBy != "" {
+			status = "shadowed by " + p.ShadowedBy
+		} else if builtinCommand(root, p.Name) != nil {
+			status = "shadowed by the builtin command"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.Path, status)
+	}
+	tw.Flush()
+}
+
+// addPluginCommands adds a command running each plugin to the root command,
+// but for the plugins a builtin command or an earlier plugin shadows.
+func addPluginCommands(root *cobra.Command, found []plugins.Plugin) {
+	for _, p := range found {
+		if p.ShadowedBy != "" || builtinCommand(root, p.Name) != nil {
+			continue
+		}
+		p := p
+		c := &cobra.Command{
+			Use:   p.Name,
+			Short: fmt.Sprintf("Runs the plugin %s.", p.Path),
+			// The flags after the name are the plugin's
+			DisableFlagParsing: true,
+			Run: func(cmd *cobra.Command, _ []string) {
+				args, err := pluginArgs(root, os.Args[1:])
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				os.Exit(runPlugin(p, args))
+			},
+		}
+		pluginCommands[c] = true
+		root.AddCommand(c)
+	}
+}
+
+// pluginArgs parses the flags of minikube before the name of the plugin, as
+// cobra doesn't for a command without flag parsing, and returns the
+// arguments after the name.
+func pluginArgs(root *cobra.Command, args []string) ([]string, error) {
+	flags := pflag.NewFlagSet("minikube", pflag.ContinueOnError)
+	flags.AddFlagSet(root.PersistentFlags())
+	flags.AddFlagSet(pflag.CommandLine)
+	flags.SetInterspersed(false)
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() == 0 {
+		return nil, nil
+	}
+	return flags.Args()[1:], nil
+}
+
+// runPlugin runs the plugin with the arguments, and returns its exit code.
+func runPlugin(p plugins.Plugin, args []string) int {
+	c := exec.Command(p.Path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), pluginEnv().Vars()...)
+	err := c.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running the plugin %s: %s\n", p.Path, err)
+		return 1
+	}
+	return 0
+}
+
+// pluginEnv returns what the plugins are told, with the details of the VM
+// when it is running.
+func pluginEnv() plugins.Env {
+	env := plugins.Env{
+		Profile:    config.ActiveProfile(),
+		Kubeconfig: kubeconfigPath(),
+		Context:    config.KubeContext(),
+		Machine:    constants.MachineName,
+	}
+	if path, err := exec.LookPath(os.Args[0]); err == nil {
+		env.Binary, _ = filepath.Abs(path)
+	}
+
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		glog.Infof("Error getting the client of the machines: %s", err)
+		return env
+	}
+	defer api.Close()
+	h, err := api.Load(constants.MachineName)
+	if err != nil {
+		glog.Infof("Leaving out the details of the VM: %s", err)
+		return env
+	}
+	if s, err := h.Driver.GetState(); err != nil || s != state.Running {
+		return env
+	}
+	if env.IP, err = h.Driver.GetIP(); err != nil {
+		glog.Infof("Error getting the address of the VM: %s", err)
+	}
+	env.SSHHost, _ = h.Driver.GetSSHHostname()
+	env.SSHPort, _ = h.Driver.GetSSHPort()
+	env.SSHUser = h.Driver.GetSSHUsername()
+	env.SSHKey = h.Driver.GetSSHKeyPath()
+	return env
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	RootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/plugins"
+)
+
+func TestPluginArgs(t *testing.T) {
+	root := &cobra.Command{Use: "minikube"}
+	root.PersistentFlags().StringP("profile", "p", "", "")
+	for _, test := range []struct {
+		args, expected []string
+	}{
+		{[]string{"foo", "--bar", "-p", "x"}, []string{"--bar", "-p", "x"}},
+		{[]string{"-p", "dev", "foo", "baz"}, []string{"baz"}},
+		{[]string{"--profile=dev", "foo"}, []string{}},
+	} {
+		args, err := pluginArgs(root, test.args)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %v: %s", test.args, err)
+		}
+		if !reflect.DeepEqual(args, test.expected) {
+			t.Errorf("Expected %v for %v, got %v", test.expected, test.args, args)
+		}
+	}
+}
+
+func TestAddPluginCommands(t *testing.T) {
+	root := &cobra.Command{Use: "minikube"}
+	root.AddCommand(&cobra.Command{Use: "ip", Run: func(*cobra.Command, []string) {}})
+	found := []plugins.Plugin{
+		{Name: "ip", Path: "/bin/minikube-ip"},
+		{Name: "foo", Path: "/bin/minikube-foo"},
+		{Name: "foo", Path: "/usr/bin/minikube-foo", ShadowedBy: "/bin/minikube-foo"},
+	}
+	addPluginCommands(root, found)
+
+	var names []string
+	for _, c := range root.Commands() {
+		names = append(names, c.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"foo", "ip"}) {
+		t.Errorf("Expected the commands foo and ip, got %v", names)
+	}
+
+	var b bytes.Buffer
+	printPlugins(&b, root, found)
+	for _, expected := range []string{
+		"ip    /bin/minikube-ip       shadowed by the builtin command",
+		"foo   /bin/minikube-foo      ok",
+		"foo   /usr/bin/minikube-foo  shadowed by /bin/minikube-foo",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected %q in the list:\n%s", expected, b.String())
+		}
+	}
+}
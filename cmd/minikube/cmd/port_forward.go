@@ -0,0 +1,106 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var portForwardNamespace string
+
+// portForwardCmd represents the port-forward command
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward TARGET [LOCAL_PORT:]REMOTE_PORT... [TARGET [LOCAL_PORT:]REMOTE_PORT...]...",
+	Short: "Forwards local ports to pods or services in the cluster.",
+	Long: `Forwards local ports to pods or services in the cluster, e.g. minikube port-forward svc/frontend 8080:80 pod/db 5432.
+
+Targets are pods, given as NAME or pod/NAME, or services, given as svc/NAME. When the pod backing a target
+is restarted, a running pod is looked up and the ports are forwarded to it. The command runs until interrupted,
+or until the cluster is deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targets, err := portforward.ParseArgs(portForwardNamespace, args)
+		if err != nil || len(targets) == 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube port-forward TARGET [LOCAL_PORT:]REMOTE_PORT...")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(1)
+		}
+		config, err := service.GetClientConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+
+		unregister, err := daemons.Register("port-forward")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, len(targets))
+		for _, t := range targets {
+			wg.Add(1)
+			go func(t portforward.Target) {
+				defer wg.Done()
+				if err := portforward.NewForwarder(client.Core(), config, t, os.Stdout).Run(stop); err != nil {
+					errs <- err
+				}
+			}(t)
+		}
+		wg.Wait()
+		close(errs)
+		failed := false
+		for err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+		if failed {
+			unregister()
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	portForwardCmd.Flags().StringVarP(&portForwardNamespace, "namespace", "n", "default", "The namespace of the pods and services")
+	RootCmd.AddCommand(portForwardCmd)
+}
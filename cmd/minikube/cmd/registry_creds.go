@@ -0,0 +1,84 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/registrycreds"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var registryCredsNamespaces string
+
+var registryCredsCmd = &cobra.Command{
+	Use:   registrycreds.AddonName + " SUBCOMMAND",
+	Short: "Manages the pull secrets of the registry-creds addon.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var registryCredsPropagateCmd = &cobra.Command{
+	Use:   "propagate",
+	Short: "Copies the pull secrets of the registry-creds addon to namespaces, for their pods to pull with.",
+	Long: `Copies the pull secrets of the registry-creds addon from kube-system to the namespaces of --namespaces,
+and adds them to the imagePullSecrets of the default service account of each, so that the pods of the
+namespaces pull images from the configured registries without naming the secrets.
+
+--namespaces is a comma separated list of names, in which * matches any part of a name, e.g. '*' for all
+the namespaces or 'dev-*' for those starting with dev-. The secrets are copied as they are: run the command
+again for the namespaces created since, or once the addon renewed the short-lived ECR token.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube registry-creds propagate --namespaces=NAMESPACE,...")
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		namespaces, err := registrycreds.Propagate(client.Core(), strings.Split(registryCredsNamespaces, ","))
+		for _, ns := range namespaces {
+			fmt.Printf("Propagated the pull secrets to %s\n", ns)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	registryCredsPropagateCmd.Flags().StringVar(&registryCredsNamespaces, "namespaces", "default", "The namespaces to copy the pull secrets to, * matching any part of a name (format: NAMESPACE,...)")
+	registryCredsCmd.AddCommand(registryCredsPropagateCmd)
+	RootCmd.AddCommand(registryCredsCmd)
+}
@@ -20,6 +20,7 @@ import (
 	goflag "flag"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
@@ -33,8 +34,13 @@ import (
 	"k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/notify"
+	"k8s.io/minikube/pkg/minikube/plugins"
+	"k8s.io/minikube/pkg/minikube/policy"
+	"k8s.io/minikube/pkg/minikube/security"
+	pkgutil "k8s.io/minikube/pkg/util"
 )
 
 var dirs = [...]string{
@@ -52,6 +58,7 @@ var dirs = [...]string{
 const (
 	showLibmachineLogs = "show-libmachine-logs"
 	useVendoredDriver  = "use-vendored-driver"
+	allowInsecureKeys  = "allow-insecure-keys"
 )
 
 var (
@@ -72,12 +79,36 @@ var RootCmd = &cobra.Command{
 	Short: "Minikube is a tool for managing local Kubernetes clusters.",
 	Long:  `Minikube is a CLI tool that provisions and manages single-node Kubernetes clusters optimized for development workflows.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		p, err := policy.Load()
+		if err != nil {
+			glog.Exitln(err)
+		}
+		if err := p.CheckCommand(cmd.CommandPath()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
 		for _, path := range dirs {
-			if err := os.MkdirAll(path, 0777); err != nil {
+			mode := os.FileMode(0777)
+			if path == constants.MakeMiniPath("certs") {
+				mode = security.DirMode
+			}
+			if err := os.MkdirAll(path, mode); err != nil {
 				glog.Exitf("Error creating minikube directory: %s", err)
 			}
 		}
 
+		if mode := viper.GetString(pkgutil.CryptoModeSetting); mode != "" {
+			if err := pkgutil.SetCryptoMode(mode); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		// The downloads of minikube follow the crypto mode
+		if t, ok := http.DefaultTransport.(*http.Transport); ok {
+			t.TLSClientConfig = pkgutil.TLSConfig()
+		}
+
 		if viper.GetBool(showLibmachineLogs) {
 			fmt.Println(`
 --show-libmachine-logs is deprecated.
@@ -85,6 +116,11 @@ Please use --v=3 to show libmachine logs, and --v=7 for debug level libmachine l
 `)
 		}
 
+		// minikube doctor reports and fixes the permissions itself
+		if cmd.Name() != "doctor" && !viper.GetBool(allowInsecureKeys) {
+			checkKeyPermissions()
+		}
+
 		//TODO(r2d4): config should not reference API
 		clientType = configCmd.GetClientType()
 
@@ -99,10 +135,11 @@ Please use --v=3 to show libmachine logs, and --v=7 for debug level libmachine l
 			log.SetDebug(true)
 		}
 
-		if enableUpdateNotification {
+		// An offline start must not look up the latest release
+		if enableUpdateNotification && !viper.GetBool(offline) {
 			notify.MaybePrintUpdateTextFromGithub(os.Stderr)
 		}
-		if enableKubectlDownloadMsg {
+		if enableKubectlDownloadMsg && viper.GetBool(config.WantKubectlDownloadMsg) {
 			util.MaybePrintKubectlDownloadMsg(runtime.GOOS, os.Stderr)
 		}
 	},
@@ -111,11 +148,32 @@ Please use --v=3 to show libmachine logs, and --v=7 for debug level libmachine l
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	addPluginCommands(RootCmd, plugins.Find(os.Getenv("PATH")))
 	if err := RootCmd.Execute(); err != nil {
 		glog.Exitln(err)
 	}
 }
 
+// checkKeyPermissions exits if any user can read the private keys, as
+// they give full access to the cluster and the machines.
+func checkKeyPermissions() {
+	findings, err := security.Audit(kubeconfigPath())
+	if err != nil {
+		glog.Warningln("Error auditing the permissions of the credentials: ", err)
+		return
+	}
+	keys := security.WorldReadableKeys(findings)
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Refusing to use private keys which any user can read:")
+	for _, k := range keys {
+		fmt.Fprintf(os.Stderr, "\t%s\n", k)
+	}
+	fmt.Fprintf(os.Stderr, "Run \"minikube doctor --security --fix\" to restrict them, or pass --%s to use them anyway.\n", allowInsecureKeys)
+	os.Exit(1)
+}
+
 // Handle config values for flags used in external packages (e.g. glog)
 // by setting them directly, using values from viper when not passed in as args
 func setFlagsUsingViper() {
@@ -136,6 +194,9 @@ func setFlagsUsingViper() {
 func init() {
 	RootCmd.PersistentFlags().Bool(showLibmachineLogs, false, "Deprecated: To enable libmachine logs, set --v=3 or higher")
 	RootCmd.PersistentFlags().Bool(useVendoredDriver, false, "Use the vendored in drivers instead of RPC")
+	RootCmd.PersistentFlags().Bool(allowInsecureKeys, false, "Use the private keys even if any user can read them")
+	RootCmd.PersistentFlags().Bool(hostmutation.Setting, false, "Forbid changing the configuration of this machine: /etc/hosts, the routing table, the current kubectl context and the system trust store. Features needing them fail")
+	RootCmd.PersistentFlags().StringP(config.ProfileFlag, "p", "", "The config profile to use. Settings are read from the profile and then from the global config, and \"minikube config\" writes to the profile")
 	RootCmd.AddCommand(configCmd.ConfigCmd)
 	RootCmd.AddCommand(configCmd.AddonsCmd)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
@@ -156,14 +217,25 @@ func initConfig() {
 	if err != nil {
 		glog.Warningf("Error reading config file at %s: %s", configPath, err)
 	}
+	if profile := config.ActiveProfile(); profile != "" {
+		if err := config.ValidateProfileName(profile); err != nil {
+			glog.Exitln(err)
+		}
+		// Values of the profile take precedence over the global ones
+		profilePath := config.ProfileConfigFile(profile)
+		viper.SetConfigFile(profilePath)
+		if err := viper.MergeInConfig(); err != nil && !os.IsNotExist(err) {
+			glog.Warningf("Error reading config file at %s: %s", profilePath, err)
+		}
+	}
 	setupViper()
 }
 
 func setupViper() {
 	viper.SetEnvPrefix(constants.MinikubeEnvPrefix)
-	// Replaces '-' in flags with '_' in env variables
-	// e.g. iso-url => $ENVPREFIX_ISO_URL
-	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	// Replaces '-' and '.' in flags with '_' in env variables
+	// e.g. iso-url => $ENVPREFIX_ISO_URL, see config.EnvVar
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
 	viper.AutomaticEnv()
 
 	viper.SetDefault(config.WantUpdateNotification, true)
@@ -171,5 +243,18 @@ func setupViper() {
 	viper.SetDefault(config.WantReportError, false)
 	viper.SetDefault(config.WantReportErrorPrompt, true)
 	viper.SetDefault(config.WantKubectlDownloadMsg, true)
+	setPolicyDefaults()
 	setFlagsUsingViper()
 }
+
+// setPolicyDefaults sets the defaults of the policy of the machine, which the
+// config files, the environment and the flags override.
+func setPolicyDefaults() {
+	p, err := policy.Load()
+	if err != nil {
+		glog.Exitln(err)
+	}
+	for name, value := range p.Defaults {
+		viper.SetDefault(name, value)
+	}
+}
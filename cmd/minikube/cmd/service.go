@@ -35,13 +35,23 @@ var (
 	serviceURLMode     bool
 	serviceURLFormat   string
 	serviceURLTemplate *template.Template
+	servicePortName    string
+	servicePortNumber  int32
 )
 
 // serviceCmd represents the service command
 var serviceCmd = &cobra.Command{
 	Use:   "service [flags] SERVICE",
 	Short: "Gets the kubernetes URL(s) for the specified service in your local cluster",
-	Long:  `Gets the kubernetes URL(s) for the specified service in your local cluster.  In the case of multiple URLs they will be printed one at a time`,
+	Long: `Gets the kubernetes URL(s) for the specified service in your local cluster.  In the case of multiple URLs they will be printed one at a time.
+
+A service with several node ports has the URLs of all of them printed, with the names of the ports. Select the
+port to open in the browser with --port-name or --port-number. Ports named https or numbered 443 get https URLs.
+
+--format prints each URL from a Go template instead, for scripts composing their own endpoints: {{.Scheme}} is https
+for the https ports, or all of them with --https, and http otherwise, {{.IP}} is the IP of the VM, {{.Port}} the node
+port and {{.Name}} the name of the port. The output is printed as is, e.g. --format="{{.IP}}:{{.Port}}" --url prints
+192.168.99.100:30080.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		t, err := template.New("serviceURL").Parse(serviceURLFormat)
 		if err != nil {
@@ -68,7 +78,8 @@ var serviceCmd = &cobra.Command{
 		defer api.Close()
 
 		cluster.EnsureMinikubeRunningOrExit(api, 1)
-		err = service.WaitAndMaybeOpenService(api, namespace, svc, serviceURLTemplate, serviceURLMode, https)
+		selector := service.PortSelector{Name: servicePortName, Number: servicePortNumber}
+		err = service.WaitAndMaybeOpenService(api, namespace, svc, serviceURLTemplate, serviceURLMode, https, selector)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error opening service: %s\n", err)
 			os.Exit(1)
@@ -80,8 +91,10 @@ func init() {
 	serviceCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "The service namespace")
 	serviceCmd.Flags().BoolVar(&serviceURLMode, "url", false, "Display the kubernetes service URL in the CLI instead of opening it in the default browser")
 	serviceCmd.Flags().BoolVar(&https, "https", false, "Open the service URL with https instead of http")
+	serviceCmd.Flags().StringVar(&servicePortName, "port-name", "", "Only the port of the service with this name")
+	serviceCmd.Flags().Int32Var(&servicePortNumber, "port-number", 0, "Only the port of the service with this number (the port of the service, not its node port)")
 
-	serviceCmd.PersistentFlags().StringVar(&serviceURLFormat, "format", defaultServiceFormatTemplate, "Format to output service URL in.  This format will be applied to each url individually and they will be printed one at a time.")
+	serviceCmd.PersistentFlags().StringVar(&serviceURLFormat, "format", defaultServiceFormatTemplate, "Format to output service URL in, a Go template of the {{.Scheme}}, {{.IP}}, {{.Port}} and {{.Name}} of each port, e.g. \"{{.Scheme}}://{{.IP}}:{{.Port}}/api\" or \"{{.IP}}:{{.Port}}\".  This format will be applied to each url individually and they will be printed one at a time.")
 
 	RootCmd.AddCommand(serviceCmd)
 }
@@ -17,11 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/kubernetes/pkg/api/v1"
 
@@ -29,14 +32,24 @@ import (
 	"k8s.io/minikube/pkg/minikube/service"
 )
 
-var serviceListNamespace string
+var (
+	serviceListNamespace string
+	serviceListOutput    string
+)
 
 // serviceListCmd represents the service list command
 var serviceListCmd = &cobra.Command{
 	Use:   "list [flags]",
 	Short: "Lists the URLs for the services in your local cluster",
-	Long:  `Lists the URLs for the services in your local cluster`,
+	Long: `Lists the URLs for the services in your local cluster, those of all the namespaces unless -n is given.
+
+With -o json, prints a JSON array of the services instead of the table, each with its namespace, name and
+urls, the latter empty for the services without a node port.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if serviceListOutput != "" && serviceListOutput != "json" {
+			fmt.Fprintf(os.Stderr, "Invalid output format %q, expected json\n", serviceListOutput)
+			os.Exit(1)
+		}
 		api, err := machine.NewAPIClient(clientType)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
@@ -50,6 +63,14 @@ var serviceListCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if serviceListOutput == "json" {
+			if err := writeServiceListJSON(os.Stdout, serviceURLs); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		var data [][]string
 		for _, serviceURL := range serviceURLs {
 			if len(serviceURL.URLs) == 0 {
@@ -69,7 +90,26 @@ var serviceListCmd = &cobra.Command{
 	},
 }
 
+// writeServiceListJSON writes the services as a JSON array, with empty
+// arrays rather than nulls for the scripts reading it.
+func writeServiceListJSON(w io.Writer, serviceURLs service.ServiceURLs) error {
+	list := service.ServiceURLs{}
+	for _, s := range serviceURLs {
+		if s.URLs == nil {
+			s.URLs = []string{}
+		}
+		list = append(list, s)
+	}
+	b, err := json.MarshalIndent(list, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding the services as json")
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
 func init() {
 	serviceListCmd.Flags().StringVarP(&serviceListNamespace, "namespace", "n", v1.NamespaceAll, "The services namespace")
+	serviceListCmd.Flags().StringVarP(&serviceListOutput, "output", "o", "", "Output format, json for a JSON array instead of the table")
 	serviceCmd.AddCommand(serviceListCmd)
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+func TestWriteServiceListJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeServiceListJSON(&b, nil); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("Expected an empty array for no services, got %q", b.String())
+	}
+
+	b.Reset()
+	urls := service.ServiceURLs{
+		{Namespace: "default", Name: "kubernetes"},
+		{Namespace: "default", Name: "web", URLs: []string{"http://192.168.99.100:30080"}},
+	}
+	if err := writeServiceListJSON(&b, urls); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("Unexpected error decoding %s: %s", b.String(), err)
+	}
+	expected := []map[string]interface{}{
+		{"namespace": "default", "name": "kubernetes", "urls": []interface{}{}},
+		{"namespace": "default", "name": "web", "urls": []interface{}{"http://192.168.99.100:30080"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/kubernetes/pkg/util/homedir"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/share"
+)
+
+var (
+	shareNamespace string
+	shareUser      string
+	shareDuration  time.Duration
+)
+
+// shareCmd represents the share command
+var shareCmd = &cobra.Command{
+	Use:   "share [flags] SERVICE",
+	Short: "Shares a service at a temporary public URL, protected by basic auth.",
+	Long: `Shares a service at a temporary public URL, protected by basic auth, e.g. to demo work in progress.
+
+Sharing goes through a relay that has to be configured first: an SSH server allowing remote port forwarding
+on its public interfaces, e.g. an OpenSSH server with "GatewayPorts clientspecified", given as:
+
+  minikube config set share-relay ssh://user@relay.example.com
+  minikube config set share-relay-key ~/.ssh/relay_rsa
+  minikube config set share-relay-host-key "$(cat relay_host_key.pub)"
+
+The first port of the service is shared, over http, until --duration elapses or the command is interrupted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube share [flags] SERVICE")
+			os.Exit(1)
+		}
+		relay, err := configuredRelay()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		urls, err := service.GetServiceURLsForService(api, shareNamespace, args[0], template.Must(template.New("shareURL").Parse(defaultServiceFormatTemplate)), service.PortSelector{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting the URL of service %s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+		if len(urls) == 0 {
+			fmt.Fprintf(os.Stderr, "Service %s has no node port to share\n", args[0])
+			os.Exit(1)
+		}
+		target, err := url.Parse(urls[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing the URL of service %s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+
+		password, err := share.GeneratePassword()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		client, err := relay.Dial()
+		if err != nil {
+			glog.Errorln("Error sharing service:", err)
+			os.Exit(1)
+		}
+		defer client.Close()
+		l, publicURL, err := share.Listen(relay, client)
+		if err != nil {
+			glog.Errorln("Error sharing service:", err)
+			os.Exit(1)
+		}
+		defer l.Close()
+
+		fmt.Printf("Sharing service %s/%s at %s for %s\n", shareNamespace, args[0], publicURL, shareDuration)
+		fmt.Printf("User: %s\nPassword: %s\n", shareUser, password)
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- http.Serve(l, share.Handler(target, shareUser, password))
+		}()
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		select {
+		case <-signals:
+		case <-time.After(shareDuration):
+			fmt.Println("The share expired.")
+		case err := <-errs:
+			glog.Errorln("Error sharing service:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// configuredRelay returns the relay set in the minikube config.
+func configuredRelay() (*share.Relay, error) {
+	relay := viper.GetString(share.RelaySetting)
+	if relay == "" {
+		return nil, fmt.Errorf("No relay is configured, set one with: minikube config set %s ssh://user@host[:port]", share.RelaySetting)
+	}
+	keyFile := viper.GetString(share.RelayKeySetting)
+	if keyFile == "" {
+		keyFile = filepath.Join(homedir.HomeDir(), ".ssh", "id_rsa")
+	}
+	return share.ParseRelay(relay, keyFile, viper.GetString(share.RelayHostKeySetting))
+}
+
+func init() {
+	shareCmd.Flags().StringVarP(&shareNamespace, "namespace", "n", "default", "The service namespace")
+	shareCmd.Flags().StringVar(&shareUser, "user", "minikube", "The basic auth user, the password is generated")
+	shareCmd.Flags().DurationVar(&shareDuration, "duration", time.Hour, "How long the service is shared for")
+	RootCmd.AddCommand(shareCmd)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot SUBCOMMAND",
+	Short: "Manages snapshots of the etcd data of the cluster, to reset its objects without deleting it.",
+	Long: `Manages snapshots of the etcd data of the cluster, to reset its objects without deleting it.
+
+A snapshot holds the etcd data of the cluster, its objects, the ids of the images in the
+minikube VM and the Kubernetes version of the cluster. It is kept on the disk of the VM, so it works with every driver and is removed with
+minikube delete. Restoring a snapshot recreates the pods of the snapshot and removes the images
+pulled since, keeping the VM running.
+
+Nothing else on the disk of the VM is saved: the data of the persistent volumes and the other
+files of the VM are left as they are, and the images removed since the snapshot have to be pulled again.
+
+Snapshots can't be taken of a cluster with several control planes, whose etcd is clustered.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Takes a snapshot of the running cluster.",
+	Long: `Takes a snapshot of the running cluster. The apiserver is unavailable for the few seconds
+it takes to save the etcd data.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := snapshotNameArg("create", args)
+		api := pauseAPIClient()
+		defer api.Close()
+		h := snapshotHost(api)
+
+		fmt.Printf("Creating snapshot %s...\n", name)
+		if err := cluster.CreateSnapshot(h, name); err != nil {
+			glog.Errorln("Error creating snapshot: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Snapshot %s created.\n", name)
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Resets the cluster to the state of a snapshot.",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := snapshotNameArg("restore", args)
+		api := pauseAPIClient()
+		defer api.Close()
+		h := snapshotHost(api)
+
+		fmt.Printf("Restoring snapshot %s...\n", name)
+		if err := cluster.RestoreSnapshot(h, name); err != nil {
+			glog.Errorln("Error restoring snapshot: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Snapshot %s restored, the pods are being recreated.\n", name)
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a snapshot.",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := snapshotNameArg("delete", args)
+		api := pauseAPIClient()
+		defer api.Close()
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		if err := cluster.DeleteSnapshot(h, name); err != nil {
+			glog.Errorln("Error deleting snapshot: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Snapshot %s deleted.\n", name)
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		api := pauseAPIClient()
+		defer api.Close()
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		snapshots, err := cluster.ListSnapshots(h)
+		if err != nil {
+			glog.Errorln("Error listing snapshots: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := printSnapshots(os.Stdout, snapshots); err != nil {
+			glog.Errorln("Error listing snapshots: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	},
+}
+
+func snapshotNameArg(command string, args []string) string {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: minikube snapshot %s NAME\n", command)
+		os.Exit(1)
+	}
+	if err := cluster.ValidateSnapshotName(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return args[0]
+}
+
+// snapshotHost loads the minikube VM, exiting unless its state can be
+// snapshotted: the cluster must not be paused nor have several control planes.
+func snapshotHost(api libmachine.API) *host.Host {
+	controlPlanes, err := cluster.ListControlPlaneNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing control planes: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if len(controlPlanes) > 0 {
+		fmt.Fprintln(os.Stderr, "Snapshots are not supported with several control planes, whose etcd is clustered.")
+		os.Exit(1)
+	}
+	status, err := cluster.GetLocalkubeStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting the localkube status: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if status == state.Paused.String() {
+		fmt.Fprintln(os.Stderr, "The cluster is paused, resume it with minikube unpause first.")
+		os.Exit(1)
+	}
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		glog.Errorln("Error loading the minikube VM: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	return h
+}
+
+func printSnapshots(w io.Writer, snapshots []cluster.Snapshot) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCREATED")
+	for _, s := range snapshots {
+		fmt.Fprintf(tw, "%s\t%s\n", s.Name, s.Created.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	RootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+)
+
+var (
+	sshInstallList   bool
+	sshInstallRemove bool
+)
+
+// sshInstallCmd represents the ssh-install command
+var sshInstallCmd = &cobra.Command{
+	Use:   "ssh-install BUNDLE...",
+	Short: "Installs extra tools, such as tcpdump, strace or iperf3, into the minikube VM.",
+	Long: `Installs extra tools, such as tcpdump, strace or iperf3, into the minikube VM, which has no package manager.
+
+A bundle is a .tar.gz of a bin directory of static binaries. It is given by the path of its tarball, or by
+its name: the bundle is then taken from ~/.minikube/cache/guest-bundles, and downloaded there first unless
+--offline is set. The bundles are extracted to the persistent disk of the VM, so the tools are kept across
+restarts, and their binaries are linked into /usr/local/bin.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sshInstallList == (len(args) > 0) {
+			fmt.Fprintln(os.Stderr, "usage: minikube ssh-install BUNDLE... or minikube ssh-install --list")
+			os.Exit(1)
+		}
+		api := pauseAPIClient()
+		defer api.Close()
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		if sshInstallList {
+			tools, err := cluster.ListGuestTools(h)
+			if err != nil {
+				glog.Errorln("Error listing the guest tools: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			for _, tool := range tools {
+				fmt.Println(tool)
+			}
+			return
+		}
+
+		for _, bundle := range args {
+			name, err := cluster.GuestBundleName(bundle)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			if sshInstallRemove {
+				if err := cluster.RemoveGuestTool(h, name); err != nil {
+					glog.Errorln("Error removing the guest tool: ", err)
+					cmdUtil.MaybeReportErrorAndExit(err)
+				}
+				fmt.Printf("%s removed.\n", name)
+				continue
+			}
+			path, err := guestBundlePath(bundle, name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			if err := cluster.InstallGuestTool(h, h.Driver, name, path); err != nil {
+				glog.Errorln("Error installing the guest tool: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			fmt.Printf("%s installed.\n", name)
+		}
+	},
+}
+
+// guestBundlePath returns the path of the tarball of the bundle, given as a
+// path or as a name to take from the cache.
+func guestBundlePath(bundle, name string) (string, error) {
+	if strings.HasSuffix(bundle, ".tar.gz") {
+		if _, err := os.Stat(bundle); err != nil {
+			return "", errors.Wrapf(err, "Error reading the bundle %s", bundle)
+		}
+		return bundle, nil
+	}
+	cachePath := cluster.GuestBundleCacheFilepath(name)
+	if viper.GetBool(offline) {
+		if _, err := os.Stat(cachePath); err != nil {
+			return "", errors.Errorf("The %s bundle is not cached at %s, and can't be downloaded offline", name, cachePath)
+		}
+		return cachePath, nil
+	}
+	return cluster.CacheGuestBundle(name)
+}
+
+func init() {
+	sshInstallCmd.Flags().BoolVar(&sshInstallList, "list", false, "List the installed tools")
+	sshInstallCmd.Flags().BoolVar(&sshInstallRemove, "remove", false, "Remove the tools instead of installing them")
+	RootCmd.AddCommand(sshInstallCmd)
+}
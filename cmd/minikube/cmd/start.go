@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -25,16 +26,41 @@ import (
 	"time"
 
 	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	rbacv1alpha1 "k8s.io/client-go/kubernetes/typed/rbac/v1alpha1"
 
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/banner"
+	"k8s.io/minikube/pkg/minikube/cloudcreds"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/cni"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/dockerdaemon"
+	"k8s.io/minikube/pkg/minikube/fleet"
+	"k8s.io/minikube/pkg/minikube/hooks"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/namespaces"
+	"k8s.io/minikube/pkg/minikube/policy"
+	"k8s.io/minikube/pkg/minikube/preflight"
+	"k8s.io/minikube/pkg/minikube/proxy"
+	"k8s.io/minikube/pkg/minikube/registrycreds"
+	"k8s.io/minikube/pkg/minikube/security"
+	"k8s.io/minikube/pkg/minikube/seed"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/usage"
+	"k8s.io/minikube/pkg/minikube/webhooks"
 	"k8s.io/minikube/pkg/util"
 	pkgutil "k8s.io/minikube/pkg/util"
 )
@@ -49,11 +75,44 @@ const (
 	hostOnlyCIDR          = "host-only-cidr"
 	containerRuntime      = "container-runtime"
 	networkPlugin         = "network-plugin"
+	cniFlag               = "cni"
+	podNetworkCIDR        = "pod-network-cidr"
+	serviceClusterIPRange = pkgConfig.ServiceClusterIPRangeFlag
 	hypervVirtualSwitch   = "hyperv-virtual-switch"
+	hypervUseExtSwitch    = "hyperv-use-external-switch"
+	hypervDisableDynMem   = "hyperv-disable-dynamic-memory"
 	kvmNetwork            = "kvm-network"
+	staticIP              = "static-ip"
 	keepContext           = "keep-context"
 	featureGates          = "feature-gates"
 	apiServerName         = "apiserver-name"
+	apiServerPort         = "apiserver-port"
+	apiServerListenAddr   = "apiserver-listen-address"
+	guestFeatures         = "guest-features"
+	systemReserved        = "system-reserved"
+	kubeReserved          = "kube-reserved"
+	evictionHard          = "eviction-hard"
+	enableSwap            = "enable-swap"
+	hugepages             = "hugepages"
+	kernelVariant         = "kernel-variant"
+	controlPlanes         = "control-planes"
+	highAvailability      = "ha"
+	seccompDefault        = "seccomp-default"
+	seccompProfiles       = "seccomp-profiles"
+	dryRun                = "dry-run"
+	preload               = "preload"
+	downloadOnly          = "download-only"
+	offline               = "offline"
+	summary               = "summary"
+	sshIPAddress          = "ssh-ip-address"
+	sshUser               = "ssh-user"
+	sshKey                = "ssh-key"
+	sshPort               = "ssh-port"
+	nfsShare              = "nfs-share"
+	nfsSharesRoot         = "nfs-shares-root"
+	qemuNetwork           = "qemu-network"
+	socketVMnetClient     = "socket-vmnet-client-path"
+	socketVMnetPath       = "socket-vmnet-path"
 )
 
 var (
@@ -68,12 +127,27 @@ var (
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Starts a local kubernetes cluster.",
-	Long: `Starts a local kubernetes cluster using Virtualbox. This command
-assumes you already have Virtualbox installed.`,
+	Long: `Starts a local kubernetes cluster, in a VM created with the driver set with --vm-driver. When none
+is set, minikube picks the best driver installed on this host, and records it in the config of the profile.`,
 	Run: runStart,
 }
 
 func runStart(cmd *cobra.Command, args []string) {
+	if viper.GetBool(dryRun) {
+		os.Exit(dryRunStart(cmd.Flags().Changed(keepContext)))
+	}
+	if viper.GetBool(downloadOnly) {
+		os.Exit(downloadOnlyStart())
+	}
+	if err := validateSummaryFormat(viper.GetString(summary)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	keepCurrentContext, err := keepKubectlContext(cmd.Flags().Changed(keepContext))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	fmt.Println("Starting local Kubernetes cluster...")
 	api, err := machine.NewAPIClient(clientType)
 	if err != nil {
@@ -82,31 +156,119 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 	defer api.Close()
 
-	diskSize := viper.GetString(humanReadableDiskSize)
-	diskSizeMB := calculateDiskSizeInMB(diskSize)
+	if err := selectDriver(api); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	if diskSizeMB < constants.MinimumDiskSizeMB {
-		err := fmt.Errorf("Disk Size %dMB (%s) is too small, the minimum disk size is %dMB", diskSizeMB, diskSize, constants.MinimumDiskSizeMB)
-		glog.Errorln("Error parsing disk size:", err)
+	swapSizeMB, err := calculateSwapSizeInMB(viper.GetString(enableSwap))
+	if err != nil {
+		glog.Errorln("Error parsing swap size:", err)
 		os.Exit(1)
 	}
 
-	config := cluster.MachineConfig{
-		MinikubeISO:         viper.GetString(isoURL),
-		Memory:              viper.GetInt(memory),
-		CPUs:                viper.GetInt(cpus),
-		DiskSize:            diskSizeMB,
-		VMDriver:            viper.GetString(vmDriver),
-		DockerEnv:           dockerEnv,
-		DockerOpt:           dockerOpt,
-		InsecureRegistry:    insecureRegistry,
-		RegistryMirror:      registryMirror,
-		HostOnlyCIDR:        viper.GetString(hostOnlyCIDR),
-		HypervVirtualSwitch: viper.GetString(hypervVirtualSwitch),
-		KvmNetwork:          viper.GetString(kvmNetwork),
-		Downloader:          pkgutil.DefaultDownloader{},
+	config, err := machineConfig()
+	if err != nil {
+		glog.Errorln("Error configuring the VM:", err)
+		os.Exit(1)
+	}
+	if _, added := proxy.DockerEnv(dockerEnv, os.Getenv); len(added) > 0 && config.VMDriver != constants.DriverNone {
+		fmt.Printf("Passing %s to the docker daemon of the VM.\n", strings.Join(added, ", "))
+	}
+
+	hugepageCount := viper.GetInt(hugepages)
+	if hugepageCount < 0 || hugepageCount*constants.HugepageSizeMB > viper.GetInt(memory)/2 {
+		glog.Errorf("Invalid number of hugepages %d, they can use at most half of the VM memory", hugepageCount)
+		os.Exit(1)
+	}
+
+	onMachine := config.VMDriver == constants.DriverNone
+	if onMachine {
+		if err := validateNoneDriver(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if config.VMDriver == constants.DriverSSH {
+		if err := validateSSHDriver(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if config.VMDriver == "virtualbox" {
+		// The driver fails deep into the creation of the VM otherwise
+		if err := cluster.CheckHostOnlyCIDR(config.HostOnlyCIDR); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := util.ValidateClusterCIDRs(viper.GetString(podNetworkCIDR), viper.GetString(serviceClusterIPRange)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
+	if viper.GetBool(offline) {
+		vmExists, err := api.Exists(constants.MachineName)
+		if err != nil {
+			glog.Errorln("Error checking the VM exists: ", err)
+			os.Exit(1)
+		}
+		kubernetesConfig := cluster.KubernetesConfig{
+			KubernetesVersion: viper.GetString(kubernetesVersion),
+			ContainerRuntime:  viper.GetString(containerRuntime),
+		}
+		iso := config.MinikubeISO
+		if !constants.UsesISO(config.VMDriver) {
+			iso = ""
+		}
+		missing, err := missingArtifacts(iso, kubernetesConfig, viper.GetBool(preload), vmExists)
+		if err != nil {
+			glog.Errorln("Error checking the cache: ", err)
+			os.Exit(1)
+		}
+		if len(missing) > 0 {
+			fmt.Fprintln(os.Stderr, offlineError(missing))
+			os.Exit(1)
+		}
+	}
+
+	if err := cluster.ValidateKubernetesVersion(viper.GetString(kubernetesVersion)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	apiserverPort, listenAddress := viper.GetInt(apiServerPort), viper.GetString(apiServerListenAddr)
+	exposed, err := validateAPIServerEndpoint(listenAddress, apiserverPort)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if exposed {
+		fmt.Fprintf(os.Stderr, "WARNING: The apiserver will be reachable on %s port %d by other machines. Anyone holding the credentials of the kubeconfig controls the cluster, make sure your firewall only lets trusted machines connect to that port.\n", listenAddress, apiserverPort)
+	}
+
+	allocation := usage.Allocation{CPUs: config.CPUs, MemoryMB: config.Memory, DiskMB: config.DiskSize}
+	if err := checkHostResources(constants.MachineName, allocation); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	exists, err := api.Exists(constants.MachineName)
+	if err != nil {
+		glog.Errorln("Error checking the VM exists: ", err)
+		os.Exit(1)
+	}
+	existingControlPlanes, err := cluster.ListControlPlaneNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing the control planes: ", err)
+		os.Exit(1)
+	}
+	controlPlaneCount, err := numControlPlanes(viper.GetInt(controlPlanes), viper.GetBool(highAvailability), exists, existingControlPlanes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	runHook(hooks.PreStart, os.Stdout)
 	fmt.Println("Starting VM...")
 	var host *host.Host
 	start := func() (err error) {
@@ -122,65 +284,249 @@ func runStart(cmd *cobra.Command, args []string) {
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
 
+	runner := cluster.NewCommandRunner(host)
+
+	// Restarting localkube leaves the containers of a paused cluster frozen
+	if err := cluster.Unpause(runner); err != nil {
+		glog.Errorln("Error unpausing the cluster: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	if err := usage.Record(constants.MachineName, allocation); err != nil {
+		glog.Errorln("Error recording the resources of the VM: ", err)
+	}
+
 	ip, err := host.Driver.GetIP()
 	if err != nil {
 		glog.Errorln("Error starting host: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
+	if config.VMDriver != constants.DriverNone && proxy.IsSet(os.Getenv) {
+		noProxyVar, noProxyValue := EnvNoProxyGetter{}.GetNoProxyVar()
+		if !proxy.Excludes(noProxyValue, ip) {
+			fmt.Fprintf(os.Stderr, "WARNING: %s doesn't exclude the IP of the VM, %s, from the proxy, which may fail to reach it. Set %s=%s\n", noProxyVar, ip, noProxyVar, proxy.NoProxy(noProxyValue, ip))
+		}
+	}
+	cniName, plugin, err := networkConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	kubernetesConfig := cluster.KubernetesConfig{
-		KubernetesVersion: viper.GetString(kubernetesVersion),
-		NodeIP:            ip,
-		APIServerName:     viper.GetString(apiServerName),
-		FeatureGates:      viper.GetString(featureGates),
-		ContainerRuntime:  viper.GetString(containerRuntime),
-		NetworkPlugin:     viper.GetString(networkPlugin),
-		ExtraOptions:      extraOptions,
+		KubernetesVersion:     viper.GetString(kubernetesVersion),
+		NodeIP:                ip,
+		APIServerName:         viper.GetString(apiServerName),
+		APIServerPort:         apiserverPort,
+		FeatureGates:          viper.GetString(featureGates),
+		ContainerRuntime:      viper.GetString(containerRuntime),
+		NetworkPlugin:         plugin,
+		CNI:                   cniName,
+		PodNetworkCIDR:        viper.GetString(podNetworkCIDR),
+		ServiceClusterIPRange: viper.GetString(serviceClusterIPRange),
+		Reserved:              reservedResources(config.Memory),
+		SwapEnabled:           swapSizeMB > 0,
+		ExtraOptions:          componentOptions(cniName),
+		CryptoMode:            util.GetCryptoMode(),
+		SeccompDefault:        viper.GetBool(seccompDefault),
+		SeccompProfilesDir:    viper.GetString(seccompProfiles),
+	}
+	// The control planes reach each other's etcd, and clients reach their
+	// apiservers through the local proxy, as they do with a listen address
+	autoPause, autoPauseInterval, err := autoPauseConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var certIPs []net.IP
+	if controlPlaneCount > 1 {
+		kubernetesConfig.EtcdPeerURL = cluster.EtcdPeerURL(ip)
+	}
+	proxied := controlPlaneCount > 1 || listenAddress != ""
+	if proxied || autoPause {
+		certIPs = append(certIPs, net.ParseIP("127.0.0.1"))
+	}
+	if ip := net.ParseIP(listenAddress); ip != nil && !ip.IsLoopback() && !ip.IsUnspecified() {
+		certIPs = append(certIPs, ip)
+	}
+	if kubernetesConfig.ServiceClusterIPRange != "" {
+		// The pods reach the apiserver on the first address of the service network
+		apiserverIP, _, _ := util.ServiceIPs(kubernetesConfig.ServiceClusterIPRange)
+		certIPs = append(certIPs, apiserverIP)
+	}
+
+	if err := cluster.CheckBundledOptions(kubernetesConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	runningVersion, err := cluster.GetKubernetesVersion(runner)
+	if err != nil {
+		glog.Errorln("Error getting the Kubernetes version of the cluster: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	versionChange, err := cluster.CheckVersionChange(runningVersion, kubernetesConfig.KubernetesVersion)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if versionChange {
+		fmt.Printf("Changing the Kubernetes version of the cluster from %s to %s...\n", runningVersion, kubernetesConfig.KubernetesVersion)
+		// The etcd data is kept, but can't be read by a previous version once
+		// migrated, so keep a copy to go back to with a restore
+		if controlPlaneCount == 1 {
+			name := "pre-upgrade-" + time.Now().Format("20060102-150405")
+			if err := cluster.CreateSnapshot(runner, name); err != nil {
+				glog.Errorln("Error taking a snapshot before upgrading: ", err)
+			} else {
+				fmt.Printf("Took snapshot %s of the cluster before upgrading.\n", name)
+			}
+		}
+	}
+
+	if features := viper.GetStringSlice(guestFeatures); len(features) > 0 {
+		fmt.Println("Enabling guest features...")
+		if err := cluster.EnableGuestFeatures(host, features); err != nil {
+			glog.Errorln("Error enabling guest features: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	}
+
+	// The none driver leaves the swap, the hugepages and the images of this
+	// machine alone
+	if !onMachine {
+		if err := cluster.ConfigureSwap(host, swapSizeMB); err != nil {
+			glog.Errorln("Error configuring swap: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		if hugepageCount > 0 {
+			fmt.Println("Allocating hugepages...")
+		}
+		allocated, err := cluster.ConfigureHugepages(host, hugepageCount)
+		if err != nil {
+			glog.Errorln("Error allocating hugepages: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if allocated < hugepageCount {
+			fmt.Fprintf(os.Stderr, "Only %d of %d hugepages could be allocated, restarting the VM with \"minikube stop\" and \"minikube start\" may allocate all of them.\n", allocated, hugepageCount)
+		}
+
+		// The links into the PATH of the tools of minikube ssh-install are
+		// lost when the VM restarts
+		if err := cluster.LinkGuestTools(host); err != nil {
+			glog.Errorln("Error linking the guest tools: ", err)
+		}
+	}
+	// Before kube-dns starts, which reads the alias when it does
+	if hostIP, err := cluster.HostAliasIP(host); err != nil {
+		glog.Errorf("Error getting the IP of the host, %s won't resolve: %s", constants.HostAlias, err)
+	} else if err := cluster.ConfigureHostAlias(runner, hostIP, !onMachine); err != nil {
+		glog.Errorln("Error setting up the alias of the host: ", err)
+	}
+
+	changes := startSummary{
+		Profile:           pkgConfig.ActiveProfile(),
+		VMCreated:         !exists,
+		KubernetesVersion: kubernetesConfig.KubernetesVersion,
+	}
+	if versionChange {
+		changes.UpgradedFrom = runningVersion
+	}
+	if !onMachine {
+		if viper.GetBool(preload) {
+			changes.ImagesPreloaded = preloadImages(host, kubernetesConfig)
+		}
+		changes.ImagesLoaded = loadCachedImages(host, kubernetesConfig.ContainerRuntime)
 	}
 
 	fmt.Println("SSH-ing files into VM...")
-	if err := cluster.UpdateCluster(host, host.Driver, kubernetesConfig); err != nil {
+	if err := cluster.UpdateCluster(runner, host.Driver, kubernetesConfig); err != nil {
 		glog.Errorln("Error updating cluster: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
 
 	fmt.Println("Setting up certs...")
-	if err := cluster.SetupCerts(host.Driver, kubernetesConfig.APIServerName); err != nil {
+	if err := cluster.SetupCerts(host.Driver, kubernetesConfig.APIServerName, certIPs...); err != nil {
 		glog.Errorln("Error configuring authentication: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
 
 	fmt.Println("Starting cluster components...")
-	if err := cluster.StartCluster(host, kubernetesConfig); err != nil {
+	if err := cluster.StartCluster(runner, kubernetesConfig); err != nil {
+		err = explainClusterError(runner, config, err)
 		glog.Errorln("Error starting cluster: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
 
+	if err := cluster.RecordKubernetesVersion(runner, kubernetesConfig.KubernetesVersion); err != nil {
+		glog.Errorln("Error recording the Kubernetes version of the cluster: ", err)
+	}
+
 	fmt.Println("Connecting to cluster...")
 	kubeHost, err := host.Driver.GetURL()
 	if err != nil {
 		glog.Errorln("Error connecting to cluster: ", err)
 	}
 	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
-	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(constants.APIServerPort), -1)
+	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(apiserverPort), -1)
+
+	for i := 2; i <= controlPlaneCount; i++ {
+		name := cluster.ControlPlaneNodeName(i)
+		fmt.Printf("Starting control plane %s...\n", name)
+		startNode(api, name)
+	}
+	if proxied {
+		address := listenAddress
+		if address == "" {
+			address = "127.0.0.1"
+		}
+		fmt.Println("Starting apiserver proxy...")
+		if err := startAPIServerProxy(address, apiserverPort); err != nil {
+			glog.Errorln("Error starting the apiserver proxy: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		kubeHost = "https://" + net.JoinHostPort(dialAddress(address), strconv.Itoa(apiserverPort))
+		changes.PortsForwarded = append(changes.PortsForwarded, net.JoinHostPort(address, strconv.Itoa(apiserverPort))+" to the apiserver")
+	} else if _, err := daemons.Stop(apiserverProxyDaemon); err != nil {
+		glog.Errorln("Error stopping the apiserver proxy: ", err)
+	}
+
+	// The kubelets of the running workers get the version by joining them
+	// again, the stopped ones when they are started
+	if versionChange {
+		rejoinWorkers(api)
+	}
+
+	if autoPause {
+		fmt.Println("Starting auto-pause...")
+		if err := startAutoPause(strings.TrimPrefix(kubeHost, "https://"), autoPauseInterval); err != nil {
+			glog.Errorln("Error starting auto-pause: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		kubeHost = fmt.Sprintf("https://127.0.0.1:%d", constants.AutoPausePort)
+		changes.PortsForwarded = append(changes.PortsForwarded, fmt.Sprintf("127.0.0.1:%d to the apiserver, pausing the cluster when idle", constants.AutoPausePort))
+	} else if _, err := daemons.Stop(autoPauseDaemon); err != nil {
+		glog.Errorln("Error stopping auto-pause: ", err)
+	}
+
+	if viper.GetString(fleet.EndpointSetting) != "" {
+		// The cluster runs regardless of the registry
+		if err := startFleetReport(kubernetesConfig.KubernetesVersion); err != nil {
+			glog.Errorln("Error starting the fleet report: ", err)
+		}
+	}
 
 	fmt.Println("Setting up kubeconfig...")
 	// setup kubeconfig
 
-	kubeConfigEnv := os.Getenv(constants.KubeconfigEnvVar)
-	var kubeConfigFile string
-	if kubeConfigEnv == "" {
-		kubeConfigFile = constants.KubeconfigPath
-	} else {
-		kubeConfigFile = filepath.SplitList(kubeConfigEnv)[0]
-	}
+	kubeConfigFile := kubeconfigPath()
 
 	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
-		ClusterName:          constants.MinikubeContext,
+		ClusterName:          pkgConfig.KubeContext(),
 		ClusterServerAddress: kubeHost,
 		ClientCertificate:    constants.MakeMiniPath("apiserver.crt"),
 		ClientKey:            constants.MakeMiniPath("apiserver.key"),
 		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
-		KeepContext:          viper.GetBool(keepContext),
+		KeepContext:          keepCurrentContext,
 	}
 	kubeCfgSetup.SetKubeConfigFile(kubeConfigFile)
 
@@ -189,11 +535,450 @@ func runStart(cmd *cobra.Command, args []string) {
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
 
+	findings, err := security.Audit(kubeConfigFile)
+	if err == nil {
+		err = security.Fix(findings)
+	}
+	if err != nil {
+		glog.Errorln("Error restricting the permissions of the credentials: ", err)
+	}
+	changes.FilesWritten = writeIntegrations(integrationConnection(kubeConfigFile, kubeHost))
+	changes.Kubeconfig, changes.Context, changes.CurrentContext = kubeConfigFile, kubeCfgSetup.ClusterName, !kubeCfgSetup.KeepContext
+	changes.AddonsEnabled = enabledAddons()
+
+	if enabled, _ := assets.Addons[cloudcreds.AddonName].IsEnabled(); enabled {
+		// The workloads not using the credentials run without them
+		if err := cloudcreds.StartSync(); err != nil {
+			glog.Errorln("Error starting the sync of the cloud credentials: ", err)
+		}
+	} else if err := cloudcreds.StopSync(); err != nil {
+		glog.Errorln("Error stopping the sync of the cloud credentials: ", err)
+	}
+	// kubectl keeps working with the version it had
+	if err := updateKubectlShim(kubernetesConfig.KubernetesVersion); err != nil {
+		glog.Errorln("Error updating the kubectl of the profile: ", err)
+	}
+
+	if assets.ApplyViaAPI() {
+		fmt.Println("Applying addons...")
+		if err := addons.ApplyEnabled(os.Stdout); err != nil {
+			err = explainClusterError(runner, config, err)
+			glog.Errorln("Error applying addons: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	}
+	// The VM may be a new one, enabling an addon next compares with what was just applied
+	if err := addons.RecordEnabled(constants.MachineName); err != nil {
+		glog.Errorln("Error recording the manifests of the addons: ", err)
+	}
+	if enabled, _ := assets.Addons[registrycreds.AddonName].IsEnabled(); enabled {
+		// A new cluster gets the secrets from the answers given when enabling the addon
+		if err := replayRegistryCreds(); err != nil {
+			glog.Errorln("Error writing the secrets of the registry credentials: ", err)
+		}
+	}
+	if err := setUpNamespaces(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error setting up the namespaces:", explainClusterError(runner, config, err))
+	}
+	if err := seedObjects(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error seeding the cluster:", err)
+	}
+
 	if kubeCfgSetup.KeepContext {
 		fmt.Printf("The local Kubernetes cluster has started. The kubectl context has not been altered, kubectl will require \"--context=%s\" to use the local Kubernetes cluster.\n", kubeCfgSetup.ClusterName)
 	} else {
 		fmt.Println("Kubectl is now configured to use the cluster.")
 	}
+	if err := printStartSummary(os.Stdout, changes, viper.GetString(summary)); err != nil {
+		glog.Errorln("Error printing the summary: ", err)
+	}
+	info := banner.Info{
+		Profile:           changes.Profile,
+		Driver:            config.VMDriver,
+		IP:                ip,
+		KubernetesVersion: changes.KubernetesVersion,
+		Context:           changes.Context,
+		Kubeconfig:        changes.Kubeconfig,
+		Server:            kubeHost,
+		Addons:            changes.AddonsEnabled,
+	}
+	webhooks.Notify(webhooks.Started, map[string]string{
+		"driver":             config.VMDriver,
+		"ip":                 ip,
+		"kubernetes-version": changes.KubernetesVersion,
+	}, os.Stderr)
+	// The message is only a hint, the cluster is usable without it. The JSON
+	// summary is left alone for the scripts parsing it.
+	if viper.GetString(summary) != summaryJSON {
+		if err := banner.Print(os.Stdout, viper.GetString(banner.Setting), info); err != nil {
+			glog.Errorln("Error printing the start message: ", err)
+		}
+		runHook(hooks.PostStart, os.Stdout)
+	} else {
+		runHook(hooks.PostStart, os.Stderr)
+	}
+	if usesDockerEnv(config.VMDriver, kubernetesConfig.ContainerRuntime) {
+		if r := preflight.DockerClient(constants.DockerAPIVersion); r.Status == preflight.Warning {
+			fmt.Fprintln(os.Stderr, "WARNING:", r.Message)
+		}
+	}
+}
+
+// componentOptions returns the extra options of the components, with those
+// the CNI and the settings of the docker daemon need.
+func componentOptions(cniName string) util.ExtraOptionSlice {
+	options := append(extraOptions, cni.ExtraOptions(cniName, viper.GetString(podNetworkCIDR))...)
+	// machineConfig fails first on settings which don't load
+	if settings, err := dockerdaemon.Load(); err == nil {
+		options = append(options, settings.ExtraOptions()...)
+	}
+	return options
+}
+
+// explainClusterError returns the error of the cluster failing to start or
+// to respond, as a cluster.OOMKillError when the OOM killer killed the
+// control plane, saving what helps sizing the VM to the logs of minikube.
+func explainClusterError(runner cluster.CommandRunner, config cluster.MachineConfig, err error) error {
+	killed, oomErr := cluster.OOMKilled(runner)
+	if oomErr != nil {
+		glog.Errorln("Error checking whether the OOM killer killed the control plane: ", oomErr)
+	}
+	if len(killed) == 0 {
+		return err
+	}
+	glog.Errorln("The control plane was OOM killed: ", err)
+	oom := &cluster.OOMKillError{Processes: killed, VMDriver: config.VMDriver, MemoryMB: config.Memory}
+	path := constants.MakeMiniPath("logs", "oom-"+time.Now().Format("20060102-150405")+".log")
+	if err := writeOOMDump(runner, path); err != nil {
+		glog.Errorln("Error saving the state of the OOM killed control plane: ", err)
+	} else {
+		oom.DumpPath = path
+	}
+	return oom
+}
+
+func writeOOMDump(runner cluster.CommandRunner, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return cluster.WriteOOMDump(runner, f)
+}
+
+// usesDockerEnv returns whether minikube docker-env can point the docker
+// client of the host to the daemon of the cluster, which the none driver
+// already shares with it.
+func usesDockerEnv(driver, runtime string) bool {
+	return driver != constants.DriverNone && (runtime == "" || runtime == "docker")
+}
+
+// keepKubectlContext returns whether start keeps the current kubectl context.
+// The strict mode keeps it, and fails an explicit --keep-context=false.
+func keepKubectlContext(explicit bool) (bool, error) {
+	keep, strict := viper.GetBool(keepContext), viper.GetBool(hostmutation.Setting)
+	if keep || !strict {
+		return keep, nil
+	}
+	if explicit {
+		return false, hostmutation.Check(strict, hostmutation.KubectlContext, "--keep-context=false")
+	}
+	return true, nil
+}
+
+// rejoinWorkers joins the running worker nodes to the cluster again, with
+// the Kubernetes version of the cluster.
+func rejoinWorkers(api libmachine.API) {
+	nodes, err := cluster.ListNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing nodes: ", err)
+		return
+	}
+	for _, n := range nodes {
+		if cluster.IsControlPlaneNode(n) || runningNode(api, n) == nil {
+			continue
+		}
+		fmt.Printf("Joining node %s again...\n", n)
+		startNode(api, n)
+	}
+}
+
+// networkConfig returns the CNI of the cluster, if any, and the network
+// plugin of the kubelet, which is the one of CNIs when there is one.
+func networkConfig() (string, string, error) {
+	selected, plugin := viper.GetString(cniFlag), viper.GetString(networkPlugin)
+	if selected == "" && plugin != "" {
+		return "", plugin, nil
+	}
+	name := cni.Resolve(selected, viper.GetString(containerRuntime))
+	if name == "" {
+		return "", plugin, nil
+	}
+	if err := cni.Validate(name); err != nil {
+		return "", "", err
+	}
+	if plugin != "" && plugin != cni.NetworkPlugin {
+		return "", "", errors.Errorf("--%s runs the kubelet with --%s=%s, not %s", cniFlag, networkPlugin, cni.NetworkPlugin, plugin)
+	}
+	return name, cni.NetworkPlugin, nil
+}
+
+// replayRegistryCreds writes the secrets of the registry-creds addon from the
+// answers recorded for the profile, if any, without asking again.
+func replayRegistryCreds() error {
+	answers, recorded, err := registrycreds.Load()
+	if err != nil || !recorded {
+		return err
+	}
+	return util.RetryAfter(5, func() error { return registrycreds.Apply(answers) }, 2*time.Second)
+}
+
+// namespacesClient sets up the namespaces with the core and rbac clients
+type namespacesClient struct {
+	corev1.CoreV1Interface
+	rbacv1alpha1.RbacV1alpha1Interface
+}
+
+// setUpNamespaces creates the namespaces declared in the config with their
+// labels, quota and role bindings.
+func setUpNamespaces() error {
+	declared, err := namespaces.Load()
+	if err != nil || len(declared) == 0 {
+		return err
+	}
+	client, err := service.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the kubernetes client")
+	}
+	return namespaces.Apply(namespacesClient{client.Core(), client.Rbac()}, declared, os.Stdout)
+}
+
+// seedObjects creates the Secrets and ConfigMaps declared in the config from
+// the files and environment variables of the host.
+func seedObjects() error {
+	objects, err := seed.Load()
+	if err != nil || len(objects) == 0 {
+		return err
+	}
+	client, err := service.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the kubernetes client")
+	}
+	return seed.Apply(client.Core(), seed.LocalHost(), objects, os.Stdout)
+}
+
+// preloadImages extracts the preload tarball of the Kubernetes version into
+// the container runtime of the VM, downloading it first unless offline. Failures
+// are only logged, localkube pulls the images it misses. It returns whether
+// the images were preloaded.
+func preloadImages(h *host.Host, config cluster.KubernetesConfig) bool {
+	found := cluster.IsPreloadCached(config.KubernetesVersion, config.ContainerRuntime)
+	if !viper.GetBool(offline) {
+		var err error
+		if found, err = cluster.CachePreload(config.KubernetesVersion, config.ContainerRuntime); err != nil {
+			glog.Errorln("Error downloading the preloaded images: ", err)
+			return false
+		}
+	}
+	if !found {
+		return false
+	}
+	preloaded, err := cluster.PreloadImages(h, h.Driver, config.KubernetesVersion, config.ContainerRuntime)
+	if err != nil {
+		glog.Errorln("Error preloading images: ", err)
+	} else if preloaded {
+		fmt.Println("Preloaded the images of the cluster components.")
+	}
+	return preloaded
+}
+
+// validateAPIServerEndpoint checks the port of the apiserver and the host
+// address it is forwarded to, if any, returning whether other machines can
+// reach it.
+func validateAPIServerEndpoint(address string, port int) (bool, error) {
+	if port < 1 || port > 65535 {
+		return false, errors.Errorf("--%s must be between 1 and 65535, not %d", apiServerPort, port)
+	}
+	if address == "" {
+		return false, nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false, errors.Errorf("--%s must be an IP address, like 127.0.0.1 or 0.0.0.0, not %q", apiServerListenAddr, address)
+	}
+	return !ip.IsLoopback(), nil
+}
+
+// numControlPlanes returns the number of control planes to run, at least
+// those the cluster already has. A cluster created with a single control
+// plane cannot get more, as its etcd is not reachable by other members.
+func numControlPlanes(requested int, ha, exists bool, existing []string) (int, error) {
+	if ha && requested < 3 {
+		requested = 3
+	}
+	if requested < 1 {
+		return 0, fmt.Errorf("Invalid number of control planes %d, there is at least one", requested)
+	}
+	if !exists {
+		return requested, nil
+	}
+	if len(existing) == 0 && requested > 1 {
+		return 0, errors.New("The cluster was created with a single control plane, delete it with minikube delete to start it with several")
+	}
+	if len(existing)+1 > requested {
+		return len(existing) + 1, nil
+	}
+	return requested, nil
+}
+
+// kubeconfigPath returns the kubeconfig minikube writes its context to.
+func kubeconfigPath() string {
+	kubeConfigEnv := os.Getenv(constants.KubeconfigEnvVar)
+	if kubeConfigEnv == "" {
+		return constants.KubeconfigPath
+	}
+	return filepath.SplitList(kubeConfigEnv)[0]
+}
+
+// machineConfig returns the config of the VM from the flags and the config
+// file, it is shared by the nodes added with minikube node.
+func machineConfig() (cluster.MachineConfig, error) {
+	diskSize := viper.GetString(humanReadableDiskSize)
+	diskSizeMB := calculateDiskSizeInMB(diskSize)
+	if diskSizeMB < constants.MinimumDiskSizeMB {
+		return cluster.MachineConfig{}, fmt.Errorf("Disk Size %dMB (%s) is too small, the minimum disk size is %dMB", diskSizeMB, diskSize, constants.MinimumDiskSizeMB)
+	}
+
+	iso, err := isoForKernelVariant(viper.GetString(kernelVariant), viper.GetString(isoURL))
+	if err != nil {
+		return cluster.MachineConfig{}, errors.Wrap(err, "Error selecting the iso")
+	}
+
+	p, err := policy.Load()
+	if err != nil {
+		return cluster.MachineConfig{}, err
+	}
+	if err := p.CheckDriver(viper.GetString(vmDriver)); err != nil {
+		return cluster.MachineConfig{}, err
+	}
+	if err := p.CheckResources(viper.GetInt(cpus), viper.GetInt(memory), diskSizeMB); err != nil {
+		return cluster.MachineConfig{}, err
+	}
+	if ip := viper.GetString(staticIP); ip != "" {
+		if err := cluster.ValidateStaticIP(viper.GetString(vmDriver), ip, viper.GetString(hostOnlyCIDR)); err != nil {
+			return cluster.MachineConfig{}, err
+		}
+	}
+	daemonSettings, err := dockerdaemon.Load()
+	if err != nil {
+		return cluster.MachineConfig{}, err
+	}
+	if len(daemonSettings) > 0 && viper.GetString(vmDriver) == constants.DriverNone {
+		return cluster.MachineConfig{}, errors.Errorf("The %s settings don't apply to the %s driver, which uses the docker daemon of this machine as it is", dockerdaemon.Prefix+"*", constants.DriverNone)
+	}
+	if err := daemonSettings.CheckFlags(dockerOpt); err != nil {
+		return cluster.MachineConfig{}, err
+	}
+	if viper.GetString(vmDriver) != constants.DriverNone {
+		daemonSettings = daemonSettings.WithLogRotation(dockerOpt)
+	}
+	daemonConfig, err := daemonSettings.Render()
+	if err != nil {
+		return cluster.MachineConfig{}, err
+	}
+
+	env := dockerEnv
+	if viper.GetString(vmDriver) != constants.DriverNone {
+		env, _ = proxy.DockerEnv(dockerEnv, os.Getenv)
+	}
+
+	return cluster.MachineConfig{
+		MinikubeISO:         iso,
+		Memory:              viper.GetInt(memory),
+		CPUs:                viper.GetInt(cpus),
+		DiskSize:            diskSizeMB,
+		VMDriver:            viper.GetString(vmDriver),
+		DockerEnv:           env,
+		DockerOpt:           dockerOpt,
+		DockerDaemonConfig:  daemonConfig,
+		InsecureRegistry:    insecureRegistry,
+		RegistryMirror:      p.RegistryMirrors(registryMirror),
+		HostOnlyCIDR:        viper.GetString(hostOnlyCIDR),
+		HypervVirtualSwitch: viper.GetString(hypervVirtualSwitch),
+		HypervUseExtSwitch:  viper.GetBool(hypervUseExtSwitch),
+		HypervDisableDynMem: viper.GetBool(hypervDisableDynMem),
+		KvmNetwork:          viper.GetString(kvmNetwork),
+		StaticIP:            viper.GetString(staticIP),
+		Downloader:          pkgutil.DefaultDownloader{},
+		SSHIPAddress:        viper.GetString(sshIPAddress),
+		SSHUser:             viper.GetString(sshUser),
+		SSHKey:              sshKeyPath(),
+		SSHPort:             viper.GetInt(sshPort),
+		NFSShare:            viper.GetStringSlice(nfsShare),
+		NFSSharesRoot:       viper.GetString(nfsSharesRoot),
+		QemuNetwork:         viper.GetString(qemuNetwork),
+		SocketVMnetClient:   viper.GetString(socketVMnetClient),
+		SocketVMnetPath:     viper.GetString(socketVMnetPath),
+	}, nil
+}
+
+// reservedResources returns the configured reservations, falling back to
+// the defaults for the VM memory for the ones left unset.
+func reservedResources(memoryMB int) cluster.ReservedResources {
+	r := cluster.DefaultReservedResources(memoryMB)
+	if s := viper.GetString(systemReserved); s != "" {
+		r.SystemReserved = s
+	}
+	if s := viper.GetString(kubeReserved); s != "" {
+		r.KubeReserved = s
+	}
+	if s := viper.GetString(evictionHard); s != "" {
+		r.EvictionHard = s
+	}
+	return r
+}
+
+// checkHostResources fails if starting the VM of the machine with the
+// allocation would exceed the limits set across all VMs, and warns if it
+// would oversubscribe the host.
+func checkHostResources(machine string, allocation usage.Allocation) error {
+	allocations, err := usage.List()
+	if err != nil {
+		return err
+	}
+	// The new allocation replaces the one recorded at the last start
+	allocations[machine] = allocation
+	total := usage.Total(allocations)
+	limits, err := resourceLimits()
+	if err != nil {
+		return err
+	}
+	if err := usage.CheckLimits(total, limits); err != nil {
+		return err
+	}
+	for _, w := range usage.Oversubscribed(total, usage.HostCapacity()) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	return nil
+}
+
+// resourceLimits returns the limits across all VMs set in the config.
+func resourceLimits() (usage.Allocation, error) {
+	limits := usage.Allocation{
+		CPUs:     viper.GetInt(usage.MaxCPUsSetting),
+		MemoryMB: viper.GetInt(usage.MaxMemorySetting),
+	}
+	if s := viper.GetString(usage.MaxDiskSizeSetting); s != "" {
+		size, err := units.FromHumanSize(s)
+		if err != nil {
+			return limits, errors.Wrapf(err, "Invalid %s", usage.MaxDiskSizeSetting)
+		}
+		limits.DiskMB = int(size / units.MB)
+	}
+	return limits, nil
 }
 
 func calculateDiskSizeInMB(humanReadableDiskSize string) int {
@@ -204,25 +989,89 @@ func calculateDiskSizeInMB(humanReadableDiskSize string) int {
 	return int(diskSize / units.MB)
 }
 
+// isoForKernelVariant returns the iso to boot the VM from for the kernel
+// variant. An iso set explicitly with --iso-url is always used as is.
+func isoForKernelVariant(variant, iso string) (string, error) {
+	switch variant {
+	case constants.KernelVariantDefault:
+		return iso, nil
+	case constants.KernelVariantRT:
+		if iso == constants.DefaultIsoUrl {
+			return constants.DefaultRTIsoUrl, nil
+		}
+		return iso, nil
+	}
+	return "", errors.Errorf("Unknown kernel variant %s, expected %s or %s", variant, constants.KernelVariantDefault, constants.KernelVariantRT)
+}
+
+// calculateSwapSizeInMB parses the --enable-swap size, an empty size
+// meaning swap is disabled.
+func calculateSwapSizeInMB(humanReadableSwapSize string) (int, error) {
+	if humanReadableSwapSize == "" {
+		return 0, nil
+	}
+	swapSize, err := units.RAMInBytes(humanReadableSwapSize)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid swap size %s", humanReadableSwapSize)
+	}
+	if swapSize < units.MiB {
+		return 0, errors.Errorf("Swap size %s is too small, the minimum is 1m", humanReadableSwapSize)
+	}
+	return int(swapSize / units.MiB), nil
+}
+
 func init() {
 	startCmd.Flags().Bool(keepContext, constants.DefaultKeepContext, "This will keep the existing kubectl context and will create a minikube context.")
 	startCmd.Flags().String(isoURL, constants.DefaultIsoUrl, "Location of the minikube iso")
-	startCmd.Flags().String(vmDriver, constants.DefaultVMDriver, fmt.Sprintf("VM driver is one of: %v", constants.SupportedVMDrivers))
+	startCmd.Flags().String(vmDriver, "", fmt.Sprintf("VM driver is one of: %v, the best one of this host when not set", constants.SupportedVMDrivers))
 	startCmd.Flags().Int(memory, constants.DefaultMemory, "Amount of RAM allocated to the minikube VM")
 	startCmd.Flags().Int(cpus, constants.DefaultCPUS, "Number of CPUs allocated to the minikube VM")
 	startCmd.Flags().String(humanReadableDiskSize, constants.DefaultDiskSize, "Disk size allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g)")
-	startCmd.Flags().String(hostOnlyCIDR, "192.168.99.1/24", "The CIDR to be used for the minikube VM (only supported with Virtualbox driver)")
+	startCmd.Flags().String(hostOnlyCIDR, "192.168.99.1/24", "The CIDR to be used for the minikube VM, the address of the host in the host-only network and its prefix (only supported with Virtualbox driver)")
 	startCmd.Flags().String(hypervVirtualSwitch, "", "The hyperv virtual switch name. Defaults to first found. (only supported with HyperV driver)")
-	startCmd.Flags().String(kvmNetwork, "default", "The KVM network name. (only supported with KVM driver)")
+	startCmd.Flags().Bool(hypervUseExtSwitch, false, "Attach the VM to an external virtual switch, the one named by --hyperv-virtual-switch or the first found, created on the first physical adapter which is up when there is none (only supported with HyperV driver)")
+	startCmd.Flags().Bool(hypervDisableDynMem, false, "Disable dynamic memory, giving the VM all its memory from the start (only supported with HyperV driver)")
+	startCmd.Flags().String(kvmNetwork, "default", "The KVM network name. (only supported with the kvm and kvm2 drivers)")
+	startCmd.Flags().String(staticIP, "", "The address of the VM on its network with the host, kept across restarts, e.g. 192.168.39.10 (only supported with the kvm2 and virtualbox drivers)")
+	startCmd.Flags().String(sshIPAddress, "", "The IP address of the machine to install the cluster on (only supported with ssh driver)")
+	startCmd.Flags().String(sshUser, "root", "The user to log in to the machine as, with passwordless sudo (only supported with ssh driver)")
+	startCmd.Flags().String(sshKey, "", "The private key to log in to the machine with, defaults to ~/.ssh/id_rsa (only supported with ssh driver)")
+	startCmd.Flags().Int(sshPort, 22, "The port sshd of the machine listens on (only supported with ssh driver)")
+	startCmd.Flags().StringSlice(nfsShare, nil, "Directories of the host to share with the VM over NFS, e.g. /Users (only supported with hyperkit driver)")
+	startCmd.Flags().String(nfsSharesRoot, "/nfsshares", "Where the NFS shares are mounted in the VM (only supported with hyperkit driver)")
+	startCmd.Flags().String(qemuNetwork, "user", "The network of the VM: user, reached through the ports forwarded from localhost, or socket_vmnet on macOS (only supported with qemu driver)")
+	startCmd.Flags().String(socketVMnetClient, "/opt/socket_vmnet/bin/socket_vmnet_client", "The client of socket_vmnet running qemu (only supported with qemu driver)")
+	startCmd.Flags().String(socketVMnetPath, "/var/run/socket_vmnet", "The socket the daemon of socket_vmnet listens on (only supported with qemu driver)")
 	startCmd.Flags().StringArrayVar(&dockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&dockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().Int(apiServerPort, constants.APIServerPort, "The port the apiserver listens on in the VM, and on the host when it is forwarded")
+	startCmd.Flags().String(apiServerListenAddr, "", "The host address the apiserver is forwarded to, e.g. 127.0.0.1, or 0.0.0.0 for other machines to reach it. When empty, clients connect to the VM")
 	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon")
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
-	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3) \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
+	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3), a newer version upgrading an existing cluster \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
 	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
+	startCmd.Flags().String(cniFlag, "", fmt.Sprintf("The CNI to network the pods with, one of %s or a path to its manifest. Defaults to %s for the rkt and remote container runtimes", strings.Join(cni.Names, ", "), cni.Bridge))
+	startCmd.Flags().String(podNetworkCIDR, "", fmt.Sprintf("The network the pods get their addresses from, e.g. 172.16.0.0/16, when the default one collides with a network of the host. Defaults to %s with a CNI", cni.PodCIDR))
+	startCmd.Flags().String(serviceClusterIPRange, "", fmt.Sprintf("The network the services get their cluster IPs from, the apiserver its first address and the cluster DNS its tenth, e.g. 172.17.0.0/16. Defaults to %s", util.DefaultServiceClusterIPRange))
 	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	startCmd.Flags().StringSlice(guestFeatures, nil, fmt.Sprintf("Optional features to enable in the minikube VM, one or more of: %v", cluster.GuestFeatures()))
+	startCmd.Flags().String(systemReserved, "", "Resources reserved for the VM system daemons, defaults depend on --memory (format: cpu=<quantity>,memory=<quantity>)")
+	startCmd.Flags().String(kubeReserved, "", "Resources reserved for the kubernetes components, defaults depend on --memory (format: cpu=<quantity>,memory=<quantity>)")
+	startCmd.Flags().String(evictionHard, "", "Thresholds below which the kubelet evicts pods, defaults depend on --memory (ex: memory.available<100Mi,nodefs.available<10%)")
+	startCmd.Flags().String(enableSwap, "", "Size of a swapfile to create in the minikube VM, swap is disabled when empty (format: <number>[<unit>], where unit = k, m or g)")
+	startCmd.Flags().Int(hugepages, 0, fmt.Sprintf("Number of %dMB hugepages to allocate in the minikube VM, mounted at /dev/hugepages", constants.HugepageSizeMB))
+	startCmd.Flags().String(kernelVariant, constants.KernelVariantDefault, fmt.Sprintf("The kernel of the minikube VM, one of: %s, %s (preempt-rt). Only applied when the VM is created", constants.KernelVariantDefault, constants.KernelVariantRT))
+	startCmd.Flags().Int(controlPlanes, 1, "Number of control planes, each in its own VM running an etcd member and an apiserver, which are load balanced on a local port. A cluster created with one cannot get more")
+	startCmd.Flags().Bool(highAvailability, false, "Start three control planes, as with --control-planes=3")
+	startCmd.Flags().Bool(seccompDefault, false, "Default the seccomp profile of pods without one to the default profile of the container runtime, instead of unconfined")
+	startCmd.Flags().String(seccompProfiles, "", "A directory of seccomp profiles, copied into the VM at each start, which pods can load with the localhost/<profile> annotation")
+	startCmd.Flags().Bool(preload, true, "Load the images of the Kubernetes version from a preload tarball, downloaded once, into a VM without images, rather than pulling them")
+	startCmd.Flags().String(summary, summaryText, fmt.Sprintf("Format of the summary of what start changed on this machine and in the cluster, one of: %s, %s, %s", summaryText, summaryJSON, summaryNone))
+	startCmd.Flags().Bool(offline, false, "Start without network access from the cache, skipping the lookups of releases and updates, and failing with the list of the artifacts which are not cached")
+	startCmd.Flags().Bool(downloadOnly, false, "Download the ISO, localkube, the preload tarball and the images of the cluster into the cache, and exit without creating the VM. Caching the images needs a docker daemon on this machine")
+	startCmd.Flags().Bool(dryRun, false, "Check the driver, virtualization, resources, network and config, and print what would be created, without creating anything")
 	startCmd.Flags().Var(&extraOptions, "extra-config",
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
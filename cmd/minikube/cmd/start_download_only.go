@@ -0,0 +1,137 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/docker/machine/libmachine/host"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// downloadOnlyStart caches the ISO, the localkube binary, the preload tarball
+// and the images minikube start needs, without creating the VM. It returns
+// the exit code of start.
+func downloadOnlyStart() int {
+	if viper.GetString(vmDriver) == "" {
+		if _, err := detectDriver(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	config, err := machineConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	version := viper.GetString(kubernetesVersion)
+	if err := cluster.ValidateKubernetesVersion(version); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	kubernetesConfig := cluster.KubernetesConfig{
+		KubernetesVersion: version,
+		ContainerRuntime:  viper.GetString(containerRuntime),
+	}
+
+	if constants.UsesISO(config.VMDriver) {
+		if err := config.Downloader.CacheMinikubeISOFromURL(config.MinikubeISO); err != nil {
+			glog.Errorln("Error caching the ISO: ", err)
+			return 1
+		}
+	}
+	if err := cluster.CacheLocalkube(kubernetesConfig); err != nil {
+		glog.Errorln("Error caching localkube: ", err)
+		return 1
+	}
+	if viper.GetBool(preload) {
+		if _, err := cluster.CachePreload(version, kubernetesConfig.ContainerRuntime); err != nil {
+			glog.Errorln("Error caching the preloaded images: ", err)
+			return 1
+		}
+	}
+	if cachesImages(kubernetesConfig.ContainerRuntime) {
+		images, err := requiredImages()
+		if err != nil {
+			glog.Errorln("Error listing the images of the cluster: ", err)
+			return 1
+		}
+		if err := cluster.CacheImages(images); err != nil {
+			glog.Errorln("Error caching images: ", err)
+			return 1
+		}
+	}
+
+	fmt.Printf("Everything minikube start needs is cached in %s.\n", constants.GetPath(constants.CachePath))
+	return 0
+}
+
+// cachesImages reports whether the images are cached for the container
+// runtime: they are saved by docker, and loaded into docker.
+func cachesImages(runtime string) bool {
+	return runtime == "" || runtime == "docker"
+}
+
+// requiredImages returns the images the cluster pulls: the infrastructure
+// container of the pods, and the images of the enabled addons.
+func requiredImages() ([]string, error) {
+	images := map[string]bool{constants.PauseImage: true}
+	for _, name := range enabledAddons() {
+		referenced, err := assets.Addons[name].ReferencedImages()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing the images of addon %s", name)
+		}
+		for _, image := range referenced {
+			images[image] = true
+		}
+	}
+	var sorted []string
+	for image := range images {
+		sorted = append(sorted, image)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// loadCachedImages loads the images of the cluster which are cached into the
+// VM, rather than having them pulled, and returns them. Failures are only
+// logged, the images are pulled then.
+func loadCachedImages(h *host.Host, runtime string) []string {
+	if !cachesImages(runtime) {
+		return nil
+	}
+	images, err := requiredImages()
+	if err != nil {
+		glog.Errorln("Error listing the images of the cluster: ", err)
+		return nil
+	}
+	loaded, err := cluster.LoadCachedImages(h, h.Driver, images)
+	if err != nil {
+		glog.Errorln("Error loading the cached images: ", err)
+	}
+	if len(loaded) > 0 {
+		fmt.Printf("Loaded %d images from the cache.\n", len(loaded))
+	}
+	return loaded
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/policy"
+	"k8s.io/minikube/pkg/minikube/preflight"
+)
+
+// rankDrivers probes the drivers of this platform allowed by the policy, and
+// returns them the best first.
+func rankDrivers() ([]preflight.Candidate, error) {
+	p, err := policy.Load()
+	if err != nil {
+		return nil, err
+	}
+	var allowed []string
+	for _, d := range constants.SupportedVMDrivers {
+		if p.CheckDriver(d) == nil {
+			allowed = append(allowed, d)
+		}
+	}
+	return preflight.Rank(allowed), nil
+}
+
+// detectDriver picks the best driver of this host when none is configured,
+// for this command only, and returns the healthy ones, the best first.
+func detectDriver() ([]string, error) {
+	ranked, err := rankDrivers()
+	if err != nil {
+		return nil, err
+	}
+	driver, err := preflight.Pick(ranked)
+	if err != nil {
+		return nil, err
+	}
+	var healthy []string
+	for _, c := range ranked {
+		if c.Healthy() {
+			healthy = append(healthy, c.Driver)
+		}
+	}
+	viper.Set(vmDriver, driver)
+	return healthy, nil
+}
+
+// selectDriver picks the driver when none is configured: the one of the
+// existing VM, or the best one of this host, and records it in the config of
+// the profile so the next starts use the same.
+func selectDriver(api libmachine.API) error {
+	if viper.GetString(vmDriver) != "" {
+		return nil
+	}
+	var driver string
+	exists, err := api.Exists(constants.MachineName)
+	if err != nil {
+		return errors.Wrap(err, "Error checking the VM exists")
+	}
+	if exists {
+		h, err := api.Load(constants.MachineName)
+		if err != nil {
+			return errors.Wrap(err, "Error loading the VM")
+		}
+		driver = h.DriverName
+	} else {
+		healthy, err := detectDriver()
+		if err != nil {
+			return err
+		}
+		driver = viper.GetString(vmDriver)
+		fmt.Printf("No driver set, using %s, the best of the drivers usable on this host: %s. Set --vm-driver to use another one.\n",
+			driver, strings.Join(healthy, ", "))
+	}
+
+	m, err := pkgConfig.ReadFile(pkgConfig.File())
+	if err != nil {
+		return err
+	}
+	m[vmDriver] = driver
+	if err := configCmd.WriteConfig(m); err != nil {
+		return errors.Wrap(err, "Error recording the driver")
+	}
+	viper.Set(vmDriver, driver)
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/preflight"
+	"k8s.io/minikube/pkg/minikube/usage"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+// dryRunStart checks what minikube start needs and prints what it would
+// create, without changing anything. It returns the exit code of start.
+func dryRunStart(explicitKeepContext bool) int {
+	var report preflight.Report
+	var plan []string
+
+	if viper.GetString(vmDriver) == "" {
+		healthy, err := detectDriver()
+		report = append(report, preflight.Check("driver detection", err,
+			fmt.Sprintf("%s, the best of the drivers usable on this host: %s", viper.GetString(vmDriver), strings.Join(healthy, ", "))))
+		if err == nil {
+			plan = append(plan, fmt.Sprintf("record the %s driver in the config", viper.GetString(vmDriver)))
+		}
+	}
+
+	config, err := machineConfig()
+	report = append(report, preflight.Check("machine config and policy", err, "valid"))
+	_, err = calculateSwapSizeInMB(viper.GetString(enableSwap))
+	report = append(report, preflight.Check("swap", err, "valid"))
+	hugepageCount := viper.GetInt(hugepages)
+	err = nil
+	if hugepageCount < 0 || hugepageCount*constants.HugepageSizeMB > viper.GetInt(memory)/2 {
+		err = errors.Errorf("%d hugepages are invalid, they can use at most half of the VM memory", hugepageCount)
+	}
+	report = append(report, preflight.Check("hugepages", err, "valid"))
+	version := viper.GetString(kubernetesVersion)
+	err = cluster.ValidateKubernetesVersion(version)
+	report = append(report, preflight.Check("kubernetes version", err, version))
+
+	apiserverPort, listenAddress := viper.GetInt(apiServerPort), viper.GetString(apiServerListenAddr)
+	exposed, err := validateAPIServerEndpoint(listenAddress, apiserverPort)
+	report = append(report, preflight.Check("apiserver endpoint", err, "valid"))
+	err = pkgutil.ValidateClusterCIDRs(viper.GetString(podNetworkCIDR), viper.GetString(serviceClusterIPRange))
+	report = append(report, preflight.Check("cluster networks", err, "valid"))
+	if exposed {
+		report = append(report, preflight.Result{Check: "apiserver endpoint", Status: preflight.Warning,
+			Message: fmt.Sprintf("other machines will reach the apiserver on %s port %d", listenAddress, apiserverPort)})
+	}
+
+	driver := viper.GetString(vmDriver)
+	report = append(report, preflight.Driver(driver))
+	if constants.IsContainerDriver(driver) {
+		report = append(report, preflight.Result{Check: "virtualization", Status: preflight.Skipped, Message: "the node runs as a container"})
+	} else if driver == constants.DriverNone {
+		report = append(report, preflight.Check(constants.DriverNone+" driver", validateNoneDriver(), "runs as root"))
+		report = append(report, preflight.Result{Check: "virtualization", Status: preflight.Skipped, Message: "the cluster runs on this machine"})
+	} else if driver == constants.DriverSSH {
+		report = append(report, preflight.Check(constants.DriverSSH+" driver", validateSSHDriver(), "address and key set"))
+		report = append(report, preflight.Result{Check: "virtualization", Status: preflight.Skipped, Message: "the cluster runs on an existing machine"})
+	} else {
+		report = append(report, preflight.Virtualization(driver))
+	}
+
+	allocation := usage.Allocation{CPUs: config.CPUs, MemoryMB: config.Memory, DiskMB: config.DiskSize}
+	allocations, err := usage.List()
+	if err == nil {
+		allocations[constants.MachineName] = allocation
+		var limits usage.Allocation
+		if limits, err = resourceLimits(); err == nil {
+			report = append(report, preflight.Resources(allocation, usage.Total(allocations), limits, usage.HostCapacity())...)
+		}
+	}
+	if err != nil {
+		report = append(report, preflight.Check("resources", err, ""))
+	}
+
+	var vmNetwork string
+	if driver == "virtualbox" {
+		report = append(report, preflight.Check("host-only network", cluster.CheckHostOnlyCIDR(viper.GetString(hostOnlyCIDR)), viper.GetString(hostOnlyCIDR)))
+		// The VM gets an address of the host-only network
+		if _, n, err := net.ParseCIDR(viper.GetString(hostOnlyCIDR)); err == nil {
+			vmNetwork = n.String()
+		}
+	}
+	report = append(report, preflight.Proxy(os.Getenv, vmNetwork)...)
+	if usesDockerEnv(driver, viper.GetString(containerRuntime)) {
+		report = append(report, preflight.DockerClient(constants.DockerAPIVersion))
+	}
+
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		report = append(report, preflight.Check("machines", err, ""))
+		return printDryRun(report, plan)
+	}
+	defer api.Close()
+	exists, err := api.Exists(constants.MachineName)
+	if err != nil {
+		report = append(report, preflight.Check("machines", err, ""))
+		return printDryRun(report, plan)
+	}
+	if exists {
+		status, err := cluster.GetHostStatus(api)
+		if err != nil {
+			status = "unknown"
+		}
+		plan = append(plan, fmt.Sprintf("start the existing VM %s (%s), keeping its disk and driver", constants.MachineName, status))
+	} else if driver == constants.DriverNone {
+		plan = append(plan, "install localkube on this machine as a systemd unit, using its docker daemon")
+	} else if driver == constants.DriverSSH {
+		plan = append(plan, fmt.Sprintf("install docker and localkube on %s@%s over ssh, as systemd units of the machine",
+			config.SSHUser, net.JoinHostPort(config.SSHIPAddress, strconv.Itoa(config.SSHPort))))
+	} else if constants.IsContainerDriver(driver) {
+		plan = append(plan, fmt.Sprintf("run the node %s as a %s container of %s: %d CPUs and %dMB of memory",
+			constants.MachineName, driver, constants.NodeImage, config.CPUs, config.Memory))
+	} else {
+		iso := config.MinikubeISO
+		if (pkgutil.DefaultDownloader{}).ShouldCacheMinikubeISO(iso) {
+			report = append(report, preflight.Download("iso", iso))
+		}
+		plan = append(plan, fmt.Sprintf("create the VM %s with the %s driver: %d CPUs, %dMB of memory and %dMB of disk, booting %s",
+			constants.MachineName, driver, config.CPUs, config.Memory, config.DiskSize, config.MinikubeISO))
+	}
+
+	existingControlPlanes, err := cluster.ListControlPlaneNodes(api)
+	if err != nil {
+		report = append(report, preflight.Check("control planes", err, ""))
+	}
+	controlPlaneCount, err := numControlPlanes(viper.GetInt(controlPlanes), viper.GetBool(highAvailability), exists, existingControlPlanes)
+	report = append(report, preflight.Check("control planes", err, fmt.Sprintf("%d", controlPlaneCount)))
+	for i := 2; i <= controlPlaneCount; i++ {
+		plan = append(plan, fmt.Sprintf("start the control plane %s in its own VM", cluster.ControlPlaneNodeName(i)))
+	}
+
+	if version == constants.DefaultKubernetesVersion {
+		plan = append(plan, fmt.Sprintf("run Kubernetes %s with the localkube bundled with minikube", version))
+	} else {
+		plan = append(plan, fmt.Sprintf("run Kubernetes %s with the localkube from %s", version, localkubeSource(version)))
+	}
+	runtime := viper.GetString(containerRuntime)
+	if viper.GetBool(preload) && cluster.PreloadSupported(version, runtime) {
+		if path := cluster.PreloadCacheFilepath(version, runtime); pathExists(path) {
+			plan = append(plan, fmt.Sprintf("preload the images of a VM without images from %s", path))
+		} else {
+			plan = append(plan, fmt.Sprintf("preload the images of a VM without images from %s, if published", cluster.PreloadURL(version, runtime)))
+		}
+	}
+	plan = append(plan, fmt.Sprintf("have the apiserver listen on port %d of the VM", apiserverPort))
+
+	if controlPlaneCount > 1 || listenAddress != "" {
+		address := listenAddress
+		if address == "" {
+			address = "127.0.0.1"
+		}
+		if running, _ := daemons.IsRunning(apiserverProxyDaemon); running {
+			report = append(report, preflight.Result{Check: "apiserver proxy", Status: preflight.OK,
+				Message: fmt.Sprintf("the apiserver proxy of the previous start on port %d is replaced", apiserverPort)})
+		} else {
+			report = append(report, preflight.Port("apiserver proxy", address, apiserverPort))
+		}
+		plan = append(plan, fmt.Sprintf("forward the apiserver to %s", net.JoinHostPort(address, fmt.Sprintf("%d", apiserverPort))))
+	}
+
+	if addons := enabledAddons(); len(addons) > 0 {
+		plan = append(plan, "enable the addons "+strings.Join(addons, ", "))
+	}
+	context := "set the minikube context in " + kubeconfigPath()
+	keep, err := keepKubectlContext(explicitKeepContext)
+	report = append(report, preflight.Check("kubectl context", err, "valid"))
+	if err == nil && !keep {
+		context += " and make it the current context"
+	}
+	plan = append(plan, context)
+	return printDryRun(report, plan)
+}
+
+// localkubeSource returns where the localkube binary of the version comes
+// from: the cache, or the URL it is downloaded from.
+func localkubeSource(version string) string {
+	if path := cluster.LocalkubeCacheFilepath(version); pathExists(path) {
+		return path
+	}
+	u, err := pkgutil.GetLocalkubeDownloadURL(version, constants.LocalkubeLinuxFilename)
+	if err != nil {
+		return version
+	}
+	return u
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// enabledAddons returns the names of the addons enabled in the config.
+func enabledAddons() []string {
+	var names []string
+	for name, addon := range assets.Addons {
+		if enabled, err := addon.IsEnabled(); err == nil && enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printDryRun(report preflight.Report, plan []string) int {
+	fmt.Println("Checks:")
+	report.Print(os.Stdout)
+	if len(plan) > 0 {
+		fmt.Println("\nminikube start would:")
+		for _, p := range plan {
+			fmt.Printf("  - %s\n", p)
+		}
+	}
+	if report.Failed() {
+		fmt.Println("\nSome checks failed, minikube start would fail.")
+		return 1
+	}
+	return 0
+}
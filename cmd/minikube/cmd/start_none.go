@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
+)
+
+// geteuid returns the user minikube runs as, it is replaced in tests.
+var geteuid = os.Geteuid
+
+// validateNoneDriver checks that the none driver can install the cluster on
+// this machine: it must run as root, and the flags configuring the VM, which
+// would change the configuration of the machine, are refused.
+func validateNoneDriver() error {
+	if err := hostmutation.Check(viper.GetBool(hostmutation.Setting), hostmutation.SystemServices, "The none driver"); err != nil {
+		return err
+	}
+	if geteuid() != 0 {
+		return errors.New("The none driver installs Kubernetes on this machine, run minikube start as root, e.g. with sudo -E")
+	}
+	return validateNoVMFlags(constants.DriverNone)
+}
+
+// validateNoVMFlags refuses the flags configuring the minikube VM, and
+// several control planes, for the drivers installing the cluster on a
+// machine minikube doesn't create.
+func validateNoVMFlags(driver string) error {
+	var vmFlag string
+	switch {
+	case viper.GetString(enableSwap) != "":
+		vmFlag = enableSwap
+	case viper.GetInt(hugepages) > 0:
+		vmFlag = hugepages
+	case len(viper.GetStringSlice(guestFeatures)) > 0:
+		vmFlag = guestFeatures
+	case viper.GetString(kernelVariant) != constants.KernelVariantDefault:
+		vmFlag = kernelVariant
+	}
+	if vmFlag != "" {
+		return errors.Errorf("--%s configures the minikube VM, and is not supported with the %s driver", vmFlag, driver)
+	}
+	if viper.GetInt(controlPlanes) > 1 || viper.GetBool(highAvailability) {
+		return errors.Errorf("Several control planes are not supported with the %s driver", driver)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+// missingArtifacts returns the artifacts start would download for the
+// config which are not cached. A VM which exists already has its ISO and its
+// images, and the container drivers need no ISO.
+func missingArtifacts(isoURL string, config cluster.KubernetesConfig, usePreload, vmExists bool) ([]string, error) {
+	var missing []string
+	downloader := pkgutil.DefaultDownloader{}
+	if isoURL != "" && !vmExists && downloader.ShouldCacheMinikubeISO(isoURL) {
+		missing = append(missing, fmt.Sprintf("the minikube ISO %s, cached at %s", isoURL, downloader.GetISOCacheFilepath(isoURL)))
+	}
+	if !cluster.IsLocalkubeCached(config) {
+		missing = append(missing, fmt.Sprintf("localkube %s, cached at %s", config.KubernetesVersion, cluster.LocalkubeCacheFilepath(config.KubernetesVersion)))
+	}
+	if vmExists || !cachesImages(config.ContainerRuntime) {
+		return missing, nil
+	}
+	// The preload tarball has the images of the cluster components and of
+	// the default addons
+	if usePreload && cluster.IsPreloadCached(config.KubernetesVersion, config.ContainerRuntime) {
+		return missing, nil
+	}
+	images, err := requiredImages()
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if !cluster.IsImageCached(image) {
+			missing = append(missing, fmt.Sprintf("the image %s, cached at %s", image, cluster.ImageCacheFilepath(image)))
+		}
+	}
+	return missing, nil
+}
+
+// offlineError returns the error of an offline start missing the artifacts.
+func offlineError(missing []string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Can't start offline, %d artifacts are not cached:\n", len(missing))
+	for _, m := range missing {
+		fmt.Fprintf(&b, "  - %s\n", m)
+	}
+	fmt.Fprintf(&b, "Run \"minikube start --%s\" with the same flags on a machine with network access, and copy its cache directory to this one.", downloadOnly)
+	return errors.New(b.String())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/kubernetes/pkg/util/homedir"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// validateSSHDriver checks that the ssh driver was given the address of the
+// machine and a key to log in with. The flags configuring the VM are refused,
+// as the machine isn't one minikube creates.
+func validateSSHDriver() error {
+	address := viper.GetString(sshIPAddress)
+	if address == "" {
+		return errors.Errorf("The %s driver installs Kubernetes on an existing machine, set its address with --%s", constants.DriverSSH, sshIPAddress)
+	}
+	if net.ParseIP(address) == nil {
+		return errors.Errorf("Invalid --%s %q, expected an IP address", sshIPAddress, address)
+	}
+	if port := viper.GetInt(sshPort); port <= 0 || port > 65535 {
+		return errors.Errorf("Invalid --%s %d", sshPort, port)
+	}
+	if _, err := os.Stat(sshKeyPath()); err != nil {
+		return errors.Wrapf(err, "Error reading the key of --%s", sshKey)
+	}
+	return validateNoVMFlags(constants.DriverSSH)
+}
+
+// sshKeyPath returns the key of --ssh-key, or the default key of the user.
+func sshKeyPath() string {
+	if key := viper.GetString(sshKey); key != "" {
+		return key
+	}
+	return filepath.Join(homedir.HomeDir(), ".ssh", "id_rsa")
+}
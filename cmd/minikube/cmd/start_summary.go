@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The formats of the summary printed at the end of start
+const (
+	summaryText = "text"
+	summaryJSON = "json"
+	summaryNone = "none"
+)
+
+// startSummary is what a start changed on the host and in the cluster.
+type startSummary struct {
+	Profile           string   `json:"profile"`
+	VMCreated         bool     `json:"vmCreated"`
+	KubernetesVersion string   `json:"kubernetesVersion"`
+	UpgradedFrom      string   `json:"upgradedFrom,omitempty"`
+	Kubeconfig        string   `json:"kubeconfig"`
+	Context           string   `json:"context"`
+	CurrentContext    bool     `json:"currentContext"`
+	AddonsEnabled     []string `json:"addonsEnabled"`
+	ImagesPreloaded   bool     `json:"imagesPreloaded"`
+	ImagesLoaded      []string `json:"imagesLoaded"`
+	PortsForwarded    []string `json:"portsForwarded"`
+	FilesWritten      []string `json:"filesWritten"`
+}
+
+func validateSummaryFormat(format string) error {
+	switch format {
+	case summaryText, summaryJSON, summaryNone:
+		return nil
+	}
+	return errors.Errorf("Invalid --%s %q, expected one of: %s, %s, %s", summary, format, summaryText, summaryJSON, summaryNone)
+}
+
+// printStartSummary prints the summary in the format, as a list of the
+// changes, or a JSON document with every field.
+func printStartSummary(w io.Writer, s startSummary, format string) error {
+	switch format {
+	case summaryNone:
+		return nil
+	case summaryJSON:
+		b, err := json.MarshalIndent(s, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "Error encoding the summary")
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	var changes []string
+	if s.VMCreated {
+		changes = append(changes, fmt.Sprintf("created the VM of profile %s, running Kubernetes %s", s.Profile, s.KubernetesVersion))
+	}
+	if s.UpgradedFrom != "" {
+		changes = append(changes, fmt.Sprintf("changed the Kubernetes version from %s to %s", s.UpgradedFrom, s.KubernetesVersion))
+	}
+	context := fmt.Sprintf("wrote the context %s to %s", s.Context, s.Kubeconfig)
+	if s.CurrentContext {
+		context += ", and made it the current context"
+	}
+	changes = append(changes, context)
+	if len(s.AddonsEnabled) > 0 {
+		changes = append(changes, "enabled the addons "+strings.Join(s.AddonsEnabled, ", "))
+	}
+	if s.ImagesPreloaded {
+		changes = append(changes, "preloaded the images of the cluster components into the VM")
+	}
+	if len(s.ImagesLoaded) > 0 {
+		changes = append(changes, "loaded the cached images "+strings.Join(s.ImagesLoaded, ", ")+" into the VM")
+	}
+	for _, port := range s.PortsForwarded {
+		changes = append(changes, "forwarded "+port)
+	}
+	for _, file := range s.FilesWritten {
+		changes = append(changes, "wrote "+file)
+	}
+
+	fmt.Fprintln(w, "Changes made by start:")
+	for _, c := range changes {
+		fmt.Fprintf(w, "  - %s\n", c)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestIsoForKernelVariant(t *testing.T) {
+	custom := "file:///tmp/minikube.iso"
+	var tcs = []struct {
+		variant   string
+		iso       string
+		expected  string
+		shouldErr bool
+	}{
+		{constants.KernelVariantDefault, constants.DefaultIsoUrl, constants.DefaultIsoUrl, false},
+		{constants.KernelVariantRT, constants.DefaultIsoUrl, constants.DefaultRTIsoUrl, false},
+		{constants.KernelVariantRT, custom, custom, false},
+		{"lowlatency", constants.DefaultIsoUrl, "", true},
+	}
+
+	for _, test := range tcs {
+		iso, err := isoForKernelVariant(test.variant, test.iso)
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error for %s: %s", test.variant, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected error for %s but got none", test.variant)
+		}
+		if iso != test.expected {
+			t.Errorf("Expected iso %s for %s, got %s", test.expected, test.variant, iso)
+		}
+	}
+}
+
+func TestNumControlPlanes(t *testing.T) {
+	var tcs = []struct {
+		requested int
+		ha        bool
+		exists    bool
+		existing  []string
+		expected  int
+		shouldErr bool
+	}{
+		{1, false, false, nil, 1, false},
+		{1, true, false, nil, 3, false},
+		{5, true, false, nil, 5, false},
+		{0, false, false, nil, 0, true},
+		{1, false, true, nil, 1, false},
+		{3, false, true, nil, 0, true},
+		{1, false, true, []string{"cp02", "cp03"}, 3, false},
+		{5, false, true, []string{"cp02", "cp03"}, 5, false},
+	}
+
+	for _, test := range tcs {
+		actual, err := numControlPlanes(test.requested, test.ha, test.exists, test.existing)
+		if err != nil && !test.shouldErr {
+			t.Errorf("%+v: Unexpected error: %s", test, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("%+v: Expected error, got none", test)
+		}
+		if actual != test.expected {
+			t.Errorf("%+v: Expected %d control planes, got %d", test, test.expected, actual)
+		}
+	}
+}
+
+func TestValidateAPIServerEndpoint(t *testing.T) {
+	var tcs = []struct {
+		address   string
+		port      int
+		exposed   bool
+		shouldErr bool
+	}{
+		{"", constants.APIServerPort, false, false},
+		{"127.0.0.1", 9443, false, false},
+		{"::1", 9443, false, false},
+		{"0.0.0.0", 9443, true, false},
+		{"192.168.1.10", 9443, true, false},
+		{"localhost", 9443, false, true},
+		{"", 0, false, true},
+		{"", 70000, false, true},
+	}
+
+	for _, test := range tcs {
+		exposed, err := validateAPIServerEndpoint(test.address, test.port)
+		if err != nil && !test.shouldErr {
+			t.Errorf("%+v: Unexpected error: %s", test, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("%+v: Expected error, got none", test)
+		}
+		if exposed != test.exposed {
+			t.Errorf("%+v: Expected exposed %t, got %t", test, test.exposed, exposed)
+		}
+	}
+}
+
+func TestMissingArtifacts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	config := cluster.KubernetesConfig{KubernetesVersion: "v1.6.4"}
+	preloadPath := cluster.PreloadCacheFilepath(config.KubernetesVersion, "")
+	os.MkdirAll(filepath.Dir(preloadPath), 0755)
+	ioutil.WriteFile(preloadPath, []byte("preloaded images"), 0644)
+
+	missing, err := missingArtifacts(constants.DefaultIsoUrl, config, true, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(missing) != 2 || !strings.Contains(missing[0], "ISO") || !strings.Contains(missing[1], "localkube v1.6.4") {
+		t.Errorf("Expected the ISO and localkube to be missing, got %v", missing)
+	}
+
+	missing, err = missingArtifacts(constants.DefaultIsoUrl, config, true, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(missing) != 1 {
+		t.Errorf("Expected only localkube to be missing for an existing VM, got %v", missing)
+	}
+
+	ioutil.WriteFile(cluster.LocalkubeCacheFilepath(config.KubernetesVersion), []byte("localkube"), 0755)
+	missing, err = missingArtifacts("file:///tmp/minikube.iso", config, true, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("Expected nothing to be missing, got %v", missing)
+	}
+
+	if err := offlineError([]string{"localkube v1.6.4"}); !strings.Contains(err.Error(), "- localkube v1.6.4") {
+		t.Errorf("Expected the error to list the missing artifacts, got %s", err)
+	}
+}
+
+func TestPrintStartSummary(t *testing.T) {
+	s := startSummary{
+		Profile:           "minikube",
+		VMCreated:         true,
+		KubernetesVersion: "v1.6.4",
+		Kubeconfig:        "/home/user/.kube/config",
+		Context:           "minikube",
+		CurrentContext:    true,
+		AddonsEnabled:     []string{"dashboard", "kube-dns"},
+		PortsForwarded:    []string{"127.0.0.1:8443 to the apiserver"},
+	}
+
+	var b bytes.Buffer
+	if err := printStartSummary(&b, s, summaryText); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		"created the VM of profile minikube",
+		"wrote the context minikube to /home/user/.kube/config, and made it the current context",
+		"enabled the addons dashboard, kube-dns",
+		"forwarded 127.0.0.1:8443 to the apiserver",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected the summary to contain %q:\n%s", expected, b.String())
+		}
+	}
+	if strings.Contains(b.String(), "images") {
+		t.Errorf("Expected no images in the summary:\n%s", b.String())
+	}
+
+	b.Reset()
+	if err := printStartSummary(&b, s, summaryJSON); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var decoded startSummary
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected a JSON summary, got %s: %s", b.String(), err)
+	}
+	if decoded.Context != "minikube" || !decoded.VMCreated || len(decoded.AddonsEnabled) != 2 {
+		t.Errorf("Unexpected summary %+v", decoded)
+	}
+
+	b.Reset()
+	printStartSummary(&b, s, summaryNone)
+	if b.Len() != 0 {
+		t.Errorf("Expected no summary, got %s", b.String())
+	}
+	if err := validateSummaryFormat("yaml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestKeepKubectlContext(t *testing.T) {
+	defer viper.Reset()
+	var tcs = []struct {
+		keep      bool
+		strict    bool
+		explicit  bool
+		expected  bool
+		shouldErr bool
+	}{
+		{keep: false, strict: false, expected: false},
+		{keep: true, strict: false, expected: true},
+		{keep: false, strict: true, expected: true},
+		{keep: false, strict: true, explicit: true, shouldErr: true},
+		{keep: true, strict: true, explicit: true, expected: true},
+	}
+	for _, test := range tcs {
+		viper.Set(keepContext, test.keep)
+		viper.Set(hostmutation.Setting, test.strict)
+		keep, err := keepKubectlContext(test.explicit)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %+v: %v", test, err)
+		}
+		if err == nil && keep != test.expected {
+			t.Errorf("Expected %t for %+v, got %t", test.expected, test, keep)
+		}
+	}
+}
+
+func TestValidateNoneDriver(t *testing.T) {
+	defer viper.Reset()
+	orig := geteuid
+	defer func() { geteuid = orig }()
+
+	var tcs = []struct {
+		description string
+		euid        int
+		settings    map[string]interface{}
+		shouldErr   bool
+	}{
+		{description: "root", euid: 0},
+		{description: "not root", euid: 1000, shouldErr: true},
+		{description: "swap", settings: map[string]interface{}{enableSwap: "1g"}, shouldErr: true},
+		{description: "hugepages", settings: map[string]interface{}{hugepages: 8}, shouldErr: true},
+		{description: "rt kernel", settings: map[string]interface{}{kernelVariant: constants.KernelVariantRT}, shouldErr: true},
+		{description: "ha", settings: map[string]interface{}{highAvailability: true}, shouldErr: true},
+		{description: "strict", settings: map[string]interface{}{hostmutation.Setting: true}, shouldErr: true},
+	}
+	for _, test := range tcs {
+		viper.Reset()
+		viper.Set(kernelVariant, constants.KernelVariantDefault)
+		for k, v := range test.settings {
+			viper.Set(k, v)
+		}
+		geteuid = func() int { return test.euid }
+		err := validateNoneDriver()
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s: %v", test.description, err)
+		}
+	}
+}
+
+func TestValidateSSHDriver(t *testing.T) {
+	defer viper.Reset()
+	key, err := ioutil.TempFile("", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(key.Name())
+	key.Close()
+
+	var tcs = []struct {
+		description string
+		settings    map[string]interface{}
+		shouldErr   bool
+	}{
+		{description: "address and key", settings: map[string]interface{}{sshIPAddress: "192.168.1.20", sshKey: key.Name()}},
+		{description: "no address", settings: map[string]interface{}{sshKey: key.Name()}, shouldErr: true},
+		{description: "hostname", settings: map[string]interface{}{sshIPAddress: "spare.local", sshKey: key.Name()}, shouldErr: true},
+		{description: "missing key", settings: map[string]interface{}{sshIPAddress: "192.168.1.20", sshKey: key.Name() + ".missing"}, shouldErr: true},
+		{description: "invalid port", settings: map[string]interface{}{sshIPAddress: "192.168.1.20", sshKey: key.Name(), sshPort: 0}, shouldErr: true},
+		{description: "hugepages", settings: map[string]interface{}{sshIPAddress: "192.168.1.20", sshKey: key.Name(), hugepages: 8}, shouldErr: true},
+	}
+	for _, test := range tcs {
+		viper.Reset()
+		viper.Set(kernelVariant, constants.KernelVariantDefault)
+		viper.Set(sshPort, 22)
+		for k, v := range test.settings {
+			viper.Set(k, v)
+		}
+		err := validateSSHDriver()
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s: %v", test.description, err)
+		}
+	}
+}
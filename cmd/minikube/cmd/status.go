@@ -35,6 +35,8 @@ var statusFormat string
 type Status struct {
 	MinikubeStatus  string
 	LocalkubeStatus string
+	// WatchdogStatus is the last restart of localkube by the watchdog, if any
+	WatchdogStatus string
 }
 
 // statusCmd represents the status command
@@ -55,14 +57,18 @@ var statusCmd = &cobra.Command{
 			cmdUtil.MaybeReportErrorAndExit(err)
 		}
 		ls := "N/A"
+		ws := ""
 		if ms == state.Running.String() {
 			ls, err = cluster.GetLocalkubeStatus(api)
+			if err == nil {
+				ws, err = cluster.GetWatchdogStatus(api)
+			}
 		}
 		if err != nil {
 			glog.Errorln("Error getting machine status:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
 		}
-		status := Status{ms, ls}
+		status := Status{ms, ls, ws}
 
 		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
@@ -19,11 +19,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"time"
 
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/webhooks"
+)
+
+const scheduledStopDaemon = "scheduled-stop"
+
+var (
+	stopSchedule    time.Duration
+	cancelScheduled bool
+	// scheduledWait is set for the background process of a scheduled stop
+	scheduledWait time.Duration
 )
 
 // stopCmd represents the stop command
@@ -31,24 +49,132 @@ var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stops a running local kubernetes cluster.",
 	Long: `Stops a local kubernetes cluster running in Virtualbox. This command stops the VM
-itself, leaving all files intact. The cluster can be started again with the "start" command.`,
+itself, and those of the nodes, leaving all files intact. The cluster can be started again with the "start" command.
+
+With --schedule, the cluster is stopped by a background process once the duration elapsed, and the command returns
+right away. Scheduling again replaces the scheduled stop, and --cancel-scheduled cancels it.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Stopping local Kubernetes cluster...")
-		api, err := machine.NewAPIClient(clientType)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		if scheduledWait > 0 {
+			runScheduledStop(scheduledWait)
+			return
+		}
+		if stopSchedule < 0 {
+			fmt.Fprintln(os.Stderr, "The schedule must be a positive duration, e.g. --schedule=2h")
+			os.Exit(1)
+		}
+		if stopSchedule > 0 && cancelScheduled {
+			fmt.Fprintln(os.Stderr, "--schedule and --cancel-scheduled cannot be used together")
 			os.Exit(1)
 		}
-		defer api.Close()
 
-		if err = cluster.StopHost(api); err != nil {
-			fmt.Println("Error stopping machine: ", err)
-			cmdUtil.MaybeReportErrorAndExit(err)
+		// A stop supersedes the stop scheduled before it
+		canceled, err := daemons.Stop(scheduledStopDaemon)
+		if err != nil {
+			glog.Errorln("Error canceling the scheduled stop: ", err)
+			os.Exit(1)
+		}
+		switch {
+		case cancelScheduled:
+			if canceled {
+				fmt.Println("Scheduled stop canceled.")
+			} else {
+				fmt.Println("No stop is scheduled.")
+			}
+		case stopSchedule > 0:
+			if !isRunning() {
+				fmt.Fprintln(os.Stderr, "minikube is not running.")
+				os.Exit(1)
+			}
+			if err := scheduleStop(stopSchedule); err != nil {
+				glog.Errorln("Error scheduling the stop: ", err)
+				os.Exit(1)
+			}
+			fmt.Printf("The cluster will be stopped in %s, at %s.\n", stopSchedule, time.Now().Add(stopSchedule).Format("Mon 15:04"))
+		default:
+			stopCluster()
 		}
-		fmt.Println("Machine stopped.")
 	},
 }
 
+// stopCluster stops the nodes, then the minikube VM.
+func stopCluster() {
+	runHook(hooks.PreStop, os.Stdout)
+	fmt.Println("Stopping local Kubernetes cluster...")
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	defer api.Close()
+
+	nodes, err := cluster.ListNodes(api)
+	if err != nil {
+		glog.Errorln("Error listing nodes: ", err)
+	}
+	for _, n := range nodes {
+		if s, err := cluster.GetNodeStatus(api, n); err != nil || s != state.Running.String() {
+			continue
+		}
+		if err := cluster.StopNode(api, n); err != nil {
+			fmt.Printf("Error stopping node %s: %s\n", n, err)
+		}
+	}
+
+	if err = cluster.StopHost(api); err != nil {
+		fmt.Println("Error stopping machine: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	fmt.Println("Machine stopped.")
+	webhooks.Notify(webhooks.Stopped, nil, os.Stderr)
+}
+
+func isRunning() bool {
+	api, err := machine.NewAPIClient(clientType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	defer api.Close()
+	return isMinikubeRunning(api)
+}
+
+// scheduleStop runs minikube stop in the background, with the flags of the
+// current command, to stop the cluster once wait elapsed.
+func scheduleStop(wait time.Duration) error {
+	logPath := constants.MakeMiniPath("logs", scheduledStopDaemon+".log")
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening the log of the scheduled stop")
+	}
+	defer logFile.Close()
+	args := append(os.Args[1:], fmt.Sprintf("--scheduled-wait=%s", wait))
+	stop := exec.Command(os.Args[0], args...)
+	stop.Stdout = logFile
+	stop.Stderr = logFile
+	if err := stop.Start(); err != nil {
+		return errors.Wrap(err, "Error starting the scheduled stop")
+	}
+	return stop.Process.Release()
+}
+
+// runScheduledStop stops the cluster once wait elapsed, unless the scheduled
+// stop is canceled meanwhile, which terminates the process.
+func runScheduledStop(wait time.Duration) {
+	unregister, err := daemons.Register(scheduledStopDaemon)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Stopping the cluster at %s\n", time.Now().Add(wait).Format(time.RFC1123))
+	time.Sleep(wait)
+	unregister()
+	stopCluster()
+}
+
 func init() {
+	stopCmd.Flags().DurationVar(&stopSchedule, "schedule", 0, "Stop the cluster in the background once the duration elapsed, e.g. 2h")
+	stopCmd.Flags().BoolVar(&cancelScheduled, "cancel-scheduled", false, "Cancel the scheduled stop")
+	stopCmd.Flags().DurationVar(&scheduledWait, "scheduled-wait", 0, "")
+	stopCmd.Flags().MarkHidden("scheduled-wait")
 	RootCmd.AddCommand(stopCmd)
 }
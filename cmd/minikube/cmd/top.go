@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/top"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+var (
+	topInterval time.Duration
+	topOnce     bool
+	topMaxPods  int
+)
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Displays the resource usage of the minikube VM, cluster and addons.",
+	Long: `Displays the disk and memory usage of the minikube VM, the CPU and memory usage of the node and its pods
+as reported by cadvisor, and the health of the enabled addons, refreshing until interrupted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+
+		if topOnce {
+			if err := top.Render(os.Stdout, snapshot(api, client), topMaxPods); err != nil {
+				glog.Errorln("Error rendering resource usage: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			return
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		ticker := time.NewTicker(topInterval)
+		defer ticker.Stop()
+		for {
+			s := snapshot(api, client)
+			fmt.Print(clearScreen)
+			fmt.Printf("Every %s, press Ctrl-C to exit\n\n", topInterval)
+			if err := top.Render(os.Stdout, s, topMaxPods); err != nil {
+				glog.Errorln("Error rendering resource usage: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			select {
+			case <-ticker.C:
+			case <-interrupt:
+				return
+			}
+		}
+	},
+}
+
+// snapshot collects the resource usage, recording the parts which could not be collected
+func snapshot(api libmachine.API, client kubernetes.Interface) *top.Snapshot {
+	s := &top.Snapshot{}
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err == nil {
+		s.VM, err = top.GetVMUsage(h)
+	}
+	if err != nil {
+		s.Errors = append(s.Errors, err)
+	}
+	if s.Node, s.Pods, err = top.GetClusterUsage(client); err != nil {
+		s.Errors = append(s.Errors, err)
+	}
+	if s.Addons, err = top.GetAddonHealth(client); err != nil {
+		s.Errors = append(s.Errors, err)
+	}
+	return s
+}
+
+func init() {
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "The interval between refreshes")
+	topCmd.Flags().BoolVar(&topOnce, "once", false, "Display the resource usage once and exit instead of refreshing")
+	topCmd.Flags().IntVar(&topMaxPods, "max-pods", 20, "The maximum number of pods displayed, using the most CPU")
+	RootCmd.AddCommand(topCmd)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/hostmutation"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+var tunnelCleanup bool
+
+// tunnelCmd represents the tunnel command
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Routes the service network of the cluster through the minikube VM.",
+	Long: `Routes the service network of the cluster through the minikube VM, so that the cluster IPs of the services
+can be reached from this machine. Changing the routing table needs sudo, or an administrator prompt on windows.
+The command runs until interrupted, or until the cluster is deleted, and then removes the route.
+
+The route is recorded along with the process, so that a tunnel which was killed doesn't leave it behind:
+--cleanup removes the routes of the tunnels which exited, and their registrations, before starting the tunnel,
+as minikube gc --tunnels does.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube tunnel [--cleanup]")
+			os.Exit(1)
+		}
+		if err := hostmutation.Check(viper.GetBool(hostmutation.Setting), hostmutation.RoutingTable, "minikube tunnel"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if tunnelCleanup {
+			if err := cleanupTunnels(os.Stdout); err != nil {
+				glog.Errorln("Error removing the routes of the tunnels which exited: ", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+		}
+		running, err := daemons.IsRunning(tunnel.DaemonName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if running {
+			fmt.Fprintln(os.Stderr, "A tunnel is already running.")
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		h, err := cluster.CheckIfApiExistsAndLoad(api)
+		if err != nil {
+			glog.Errorln("Error loading the minikube VM:", err)
+			os.Exit(1)
+		}
+		if h.Driver.DriverName() == constants.DriverNone {
+			api.Close()
+			fmt.Println("The cluster runs on this machine with the none driver, its cluster IPs are reachable without a tunnel.")
+			return
+		}
+		ip, err := h.Driver.GetIP()
+		api.Close()
+		if err != nil {
+			glog.Errorln("Error getting the IP of the minikube VM:", err)
+			os.Exit(1)
+		}
+		route, err := tunnel.ServiceRoute(pkgConfig.ServiceClusterIPRange(), ip)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		unregister, err := daemons.Register(tunnel.DaemonName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer unregister()
+		if err := daemons.RecordRoutes(tunnel.DaemonName, []daemons.Route{route}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			unregister()
+			os.Exit(1)
+		}
+		if err := tunnel.AddRoute(route); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			unregister()
+			os.Exit(1)
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		fmt.Printf("Routing %s through %s, interrupt to remove the route.\n", route.Network, route.Gateway)
+		<-signals
+
+		if err := tunnel.DeleteRoute(route); err != nil {
+			// The registration is kept for the route to be removed later
+			fmt.Fprintf(os.Stderr, "%s\nRemove the route with minikube tunnel --cleanup.\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("The route to %s was removed.\n", route.Network)
+	},
+}
+
+// cleanupTunnels removes the routes of the daemons which exited, and their
+// registrations, reporting them to out.
+func cleanupTunnels(out io.Writer) error {
+	stale, err := daemons.Stale()
+	if err != nil {
+		return err
+	}
+	for _, d := range stale {
+		if err := tunnel.Cleanup(d); err != nil {
+			return err
+		}
+		for _, r := range d.Routes {
+			fmt.Fprintf(out, "Removed the route to %s via %s of %s, which exited.\n", r.Network, r.Gateway, d.Name)
+		}
+	}
+	return nil
+}
+
+func init() {
+	tunnelCmd.Flags().BoolVar(&tunnelCleanup, "cleanup", false, "Remove the routes left behind by the tunnels which exited before starting")
+	RootCmd.AddCommand(tunnelCmd)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/usage"
+)
+
+// usageCmd represents the usage command
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Lists the host resources each VM is configured with.",
+	Long: `Lists the host resources each VM is configured with, as of its last start, along with their total, the
+limits set with max-cpus, max-memory and max-disk-size, and the capacity of the host. The profiles share the VM
+of the minikube machine, which is counted once.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		allocations, err := usage.List()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		limits, err := resourceLimits()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printUsage(os.Stdout, allocations, limits, usage.HostCapacity())
+	},
+}
+
+func printUsage(w io.Writer, allocations map[string]usage.Allocation, limits, capacity usage.Allocation) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MACHINE\tCPUS\tMEMORY\tDISK")
+	for _, m := range usage.Machines(allocations) {
+		printAllocation(tw, m, allocations[m])
+	}
+	printAllocation(tw, "TOTAL", usage.Total(allocations))
+	printAllocation(tw, "LIMIT", limits)
+	printAllocation(tw, "HOST", capacity)
+	tw.Flush()
+}
+
+func printAllocation(w io.Writer, name string, a usage.Allocation) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, orDash(a.CPUs, ""), orDash(a.MemoryMB, "MB"), orDash(a.DiskMB, "MB"))
+}
+
+// orDash formats unset or unknown amounts as -
+func orDash(n int, unit string) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.Itoa(n) + unit
+}
+
+func init() {
+	RootCmd.AddCommand(usageCmd)
+}
@@ -18,16 +18,26 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
 
+var versionComponents bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version of minikube.",
-	Long:  `Print the version of minikube.`,
+	Long: `Print the version of minikube.
+
+With --components, also print the bundled Kubernetes version and the crypto posture: the crypto mode,
+whether minikube is a FIPS build, and the algorithms of the generated certs and of TLS.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Explicitly disable update checking for the version command
 		enableUpdateNotification = false
@@ -37,9 +47,26 @@ var versionCmd = &cobra.Command{
 	Run: func(command *cobra.Command, args []string) {
 
 		fmt.Println("minikube version:", version.GetVersion())
+		if versionComponents {
+			printComponents(os.Stdout, util.GetCryptoPosture())
+		}
 	},
 }
 
+func printComponents(w io.Writer, posture util.CryptoPosture) {
+	fmt.Fprintln(w, "kubernetes version:", constants.DefaultKubernetesVersion)
+	fmt.Fprintln(w, "crypto mode:", posture.Mode)
+	fmt.Fprintln(w, "fips build:", posture.FIPSBuild)
+	fmt.Fprintln(w, "cert keys:", posture.KeyAlgorithm)
+	fmt.Fprintln(w, "tls version:", posture.TLSVersion)
+	suites := "default"
+	if len(posture.CipherSuites) > 0 {
+		suites = strings.Join(posture.CipherSuites, ",")
+	}
+	fmt.Fprintln(w, "tls cipher suites:", suites)
+}
+
 func init() {
+	versionCmd.Flags().BoolVar(&versionComponents, "components", false, "Also print the versions of the components and the crypto posture")
 	RootCmd.AddCommand(versionCmd)
 }
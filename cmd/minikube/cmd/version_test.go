@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestPrintComponents(t *testing.T) {
+	var tests = []struct {
+		posture  util.CryptoPosture
+		expected string
+	}{
+		{
+			posture: util.CryptoPosture{Mode: "default", KeyAlgorithm: "RSA 2048", TLSVersion: "1.2 or later"},
+			expected: `kubernetes version: ` + constants.DefaultKubernetesVersion + `
+crypto mode: default
+fips build: false
+cert keys: RSA 2048
+tls version: 1.2 or later
+tls cipher suites: default
+`,
+		},
+		{
+			posture: util.CryptoPosture{
+				Mode:         "fips",
+				FIPSBuild:    true,
+				KeyAlgorithm: "RSA 3072 with SHA-256",
+				TLSVersion:   "1.2 or later",
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			},
+			expected: `kubernetes version: ` + constants.DefaultKubernetesVersion + `
+crypto mode: fips
+fips build: true
+cert keys: RSA 3072 with SHA-256
+tls version: 1.2 or later
+tls cipher suites: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+`,
+		},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		printComponents(&b, test.posture)
+		if b.String() != test.expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", test.expected, b.String())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/webhooks"
+)
+
+var (
+	webhookEvents []string
+	webhookSecret string
+)
+
+// webhookCmd represents the webhook command
+var webhookCmd = &cobra.Command{
+	Use:   "webhook SUBCOMMAND",
+	Short: "Manages the webhooks notified of the lifecycle events of the clusters.",
+	Long: fmt.Sprintf(`Manages the webhooks minikube posts the lifecycle events of the clusters of this machine to, for all the
+profiles, so chat bots and dashboards can keep track of the clusters of a team. The events are:
+
+  %s            the cluster started, by minikube start
+  %s            the cluster stopped, by minikube stop or its scheduled stop
+  %s      an addon was enabled, with the addon in the details
+  %s       the certificate of the apiserver was generated again for new addresses
+
+The events are posted as JSON, with the event, the time, the profile, the host and the user running
+minikube, and the details of the event. The %s header holds the event, and with a secret, the
+%s header holds sha256= and the hex HMAC-SHA256 of the body with the secret. A webhook which
+fails, or doesn't answer within 5 seconds, is reported as a warning, and the command goes on.`,
+		webhooks.Started, webhooks.Stopped, webhooks.AddonEnabled, webhooks.CertRotated, webhooks.EventHeader, webhooks.SignatureHeader),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var webhookAddCmd = &cobra.Command{
+	Use:   "add URL",
+	Short: "Registers the URL to post the events to, replacing its webhook if it is registered.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube webhook add URL [--events=started,stopped] [--secret=SECRET]")
+			os.Exit(1)
+		}
+		w := webhooks.Webhook{URL: args[0], Secret: webhookSecret}
+		for _, e := range webhookEvents {
+			w.Events = append(w.Events, webhooks.Event(e))
+		}
+		if err := webhooks.Add(w); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("The events will be posted to %s.\n", w.URL)
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the webhooks and their events.",
+	Run: func(cmd *cobra.Command, args []string) {
+		hooks, err := webhooks.List()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printWebhooks(os.Stdout, hooks)
+	},
+}
+
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove URL",
+	Short: "Unregisters the webhook of the URL.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube webhook remove URL")
+			os.Exit(1)
+		}
+		removed, err := webhooks.Remove(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Fprintf(os.Stderr, "No webhook is registered for %s\n", args[0])
+			os.Exit(1)
+		}
+		fmt.Printf("The events won't be posted to %s anymore.\n", args[0])
+	},
+}
+
+// printWebhooks lists the webhooks, without their secrets.
+func printWebhooks(w io.Writer, hooks []webhooks.Webhook) {
+	if len(hooks) == 0 {
+		fmt.Fprintln(w, "No webhook registered, add one with minikube webhook add URL")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "URL\tEVENTS\tSIGNED")
+	for _, h := range hooks {
+		events := "all"
+		if len(h.Events) > 0 {
+			var names []string
+			for _, e := range h.Events {
+				names = append(names, string(e))
+			}
+			events = strings.Join(names, ",")
+		}
+		signed := "no"
+		if h.Secret != "" {
+			signed = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", h.URL, events, signed)
+	}
+	tw.Flush()
+}
+
+func init() {
+	webhookAddCmd.Flags().StringSliceVar(&webhookEvents, "events", nil, "The events posted to the webhook, all of them when empty")
+	webhookAddCmd.Flags().StringVar(&webhookSecret, "secret", "", "The secret signing the events, for the webhook to check they come from minikube")
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	RootCmd.AddCommand(webhookCmd)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/scheduling"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var whyPendingNamespace string
+
+// whyPendingCmd represents the why-pending command
+var whyPendingCmd = &cobra.Command{
+	Use:   "why-pending POD",
+	Short: "Explains why a pod is pending.",
+	Long: `Explains why a pod is pending, gathering what the scheduler considers:
+
+  - the warnings of the events of the pod, like those of failed scheduling
+  - the claims of its volumes which don't exist or aren't bound
+  - for each node: whether it is ready and schedulable, the labels of the node selector it lacks,
+    the taints the pod doesn't tolerate, the CPU, memory and pods it has too few of once the
+    requests of its pods are subtracted, and the host ports already taken
+
+Once the pod is scheduled, the reasons its containers are waiting are given instead, e.g. an
+image which can't be pulled.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube why-pending POD")
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient(clientType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		client, err := service.GetClientset()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubernetes client: %s\n", err)
+			os.Exit(1)
+		}
+		pod, err := client.Core().Pods(whyPendingNamespace).Get(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting pod %s in namespace %s: %s\n", args[0], whyPendingNamespace, err)
+			os.Exit(1)
+		}
+		c, err := schedulingCluster(client, pod)
+		if err != nil {
+			glog.Errorln("Error gathering the state of the cluster: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		for _, r := range scheduling.Explain(pod, c) {
+			fmt.Println(r)
+		}
+	},
+}
+
+// schedulingCluster gathers what the scheduler considers to place the pod.
+func schedulingCluster(client *kubernetes.Clientset, pod *v1.Pod) (scheduling.Cluster, error) {
+	var c scheduling.Cluster
+	nodes, err := client.Core().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return c, errors.Wrap(err, "Error listing nodes")
+	}
+	c.Nodes = nodes.Items
+	pods, err := client.Core().Pods(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return c, errors.Wrap(err, "Error listing pods")
+	}
+	c.Pods = pods.Items
+	events, err := client.Core().Events(pod.Namespace).List(v1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=Pod,involvedObject.name=%s", pod.Name),
+	})
+	if err != nil {
+		return c, errors.Wrap(err, "Error listing the events of the pod")
+	}
+	c.Events = events.Items
+	claims, err := client.Core().PersistentVolumeClaims(pod.Namespace).List(v1.ListOptions{})
+	if err != nil {
+		return c, errors.Wrap(err, "Error listing claims")
+	}
+	c.Claims = map[string]v1.PersistentVolumeClaim{}
+	for _, claim := range claims.Items {
+		c.Claims[claim.Name] = claim
+	}
+	return c, nil
+}
+
+func init() {
+	whyPendingCmd.Flags().StringVarP(&whyPendingNamespace, "namespace", "n", "default", "The namespace of the pod")
+	RootCmd.AddCommand(whyPendingCmd)
+}
@@ -0,0 +1,32 @@
+################################################################################
+#
+# buildkit-bin
+#
+################################################################################
+
+BUILDKIT_BIN_VERSION = 0.8.1
+BUILDKIT_BIN_SITE = https://github.com/moby/buildkit/releases/download/v$(BUILDKIT_BIN_VERSION)
+BUILDKIT_BIN_SOURCE = buildkit-v$(BUILDKIT_BIN_VERSION).linux-amd64.tar.gz
+
+define BUILDKIT_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/buildctl \
+		$(TARGET_DIR)/usr/bin/buildctl
+
+	$(INSTALL) -D -m 0755 \
+		$(@D)/buildkitd \
+		$(TARGET_DIR)/usr/bin/buildkitd
+
+	$(INSTALL) -D -m 0755 \
+		$(@D)/buildkit-runc \
+		$(TARGET_DIR)/usr/bin/buildkit-runc
+endef
+
+# buildkitd is not enabled by default, minikube buildctl-env starts it on demand
+define BUILDKIT_BIN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -D -m 644 \
+		$(BR2_EXTERNAL)/package/buildkit-bin/buildkit.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/buildkit.service
+endef
+
+$(eval $(generic-package))
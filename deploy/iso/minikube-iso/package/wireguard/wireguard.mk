@@ -0,0 +1,27 @@
+################################################################################
+#
+# WireGuard kernel module and wg tool
+#
+################################################################################
+
+WIREGUARD_VERSION = 0.0.20170223
+WIREGUARD_SITE = https://git.zx2c4.com/WireGuard/snapshot
+WIREGUARD_SOURCE = WireGuard-$(WIREGUARD_VERSION).tar.xz
+WIREGUARD_LICENSE = GPLv2
+WIREGUARD_LICENSE_FILES = COPYING
+WIREGUARD_DEPENDENCIES = libmnl
+
+WIREGUARD_MODULE_SUBDIRS = src
+
+define WIREGUARD_BUILD_CMDS
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/src/tools
+endef
+
+define WIREGUARD_INSTALL_TARGET_CMDS
+	$(INSTALL) -Dm755 \
+		$(@D)/src/tools/wg \
+		$(TARGET_DIR)/usr/bin/wg
+endef
+
+$(eval $(kernel-module))
+$(eval $(generic-package))
@@ -0,0 +1,63 @@
+#!/bin/bash
+
+# Copyright 2016 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+# This script builds the preload tarball of the images of a Kubernetes version
+# for a container runtime, which minikube start extracts into the storage of
+# the runtime of a new VM instead of pulling the images.
+#
+# It starts a throwaway cluster with its own state directory and kubeconfig,
+# waits for the kube-system pods to run, and archives the storage of the
+# runtime, writing out/preloaded-images-k8s-$K8S_VERSION-$RUNTIME.tar.gz and
+# its sha256.
+
+# The script expects the following env variables:
+# K8S_VERSION: the Kubernetes version, e.g. v1.6.4
+# RUNTIME: the container runtime, docker or rkt (default: docker)
+# MINIKUBE: the minikube binary (default: out/minikube)
+# EXTRA_BUILD_ARGS: additional flags to pass into minikube start
+
+set -e
+
+RUNTIME=${RUNTIME:-docker}
+MINIKUBE=${MINIKUBE:-out/minikube}
+case $RUNTIME in
+  docker) STORAGE=/var/lib/docker; STOP="sudo systemctl stop localkube docker" ;;
+  rkt) STORAGE=/var/lib/rkt; STOP="sudo systemctl stop localkube" ;;
+  *) echo "No preload for the container runtime $RUNTIME"; exit 1 ;;
+esac
+NAME=preloaded-images-k8s-${K8S_VERSION}-${RUNTIME}.tar.gz
+
+export MINIKUBE_STATE_DIR=$(mktemp -d)
+export KUBECONFIG=$MINIKUBE_STATE_DIR/kubeconfig
+trap '"$MINIKUBE" delete || true; rm -rf "$MINIKUBE_STATE_DIR"' EXIT
+
+"$MINIKUBE" start --kubernetes-version="$K8S_VERSION" --container-runtime="$RUNTIME" --preload=false $EXTRA_BUILD_ARGS
+
+# The images are pulled once the kube-system pods run
+for i in $(seq 60); do
+  pods=$(kubectl get pods --namespace=kube-system --no-headers 2>/dev/null || true)
+  if [ -n "$pods" ] && ! echo "$pods" | awk '{print $3}' | grep -qv Running; then
+    break
+  fi
+  sleep 10
+done
+
+"$MINIKUBE" ssh "$STOP && sudo tar -C $STORAGE -czf /tmp/$NAME ."
+mkdir -p out
+scp -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null \
+  -i "$MINIKUBE_STATE_DIR/machines/minikube/id_rsa" "docker@$("$MINIKUBE" ip):/tmp/$NAME" out/
+openssl sha256 "out/$NAME" | awk '{print $2}' > "out/$NAME.sha256"
@@ -0,0 +1,272 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package container is a libmachine driver running the node of the cluster
+// as a privileged container of the container engine of the host, docker or
+// podman, instead of a VM, for machines without virtualization, like cloud CI
+// runners.
+package container
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// The user the node image lets in with the key of the machine
+const sshUser = "docker"
+
+// Driver runs the node as a container named after the machine. The
+// container keeps /var in a volume of the same name, so the images and the
+// state of localkube survive a restart.
+type Driver struct {
+	*drivers.BaseDriver
+
+	// Binary is the client of the container engine, docker or podman
+	Binary string
+	// Image is the image of the node, which runs systemd, sshd and docker
+	Image  string
+	Memory int
+	CPU    int
+	// Rootless is set for podman run by a user other than root, whose
+	// containers are not reachable at their IP: the ports of the apiserver
+	// and of docker are published on localhost instead
+	Rootless bool
+}
+
+// NewDriver returns a driver managing the node with the client of the
+// container engine.
+func NewDriver(binary, machineName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: machineName,
+			StorePath:   storePath,
+			SSHUser:     sshUser,
+		},
+		Binary:   binary,
+		Rootless: binary == "podman" && os.Geteuid() > 0,
+	}
+}
+
+// isNotFound reports whether the error is the container engine not finding
+// the container or volume, which docker and podman word differently.
+func isNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "no such")
+}
+
+// runCommand runs the client of the container engine, it is replaced in
+// tests.
+var runCommand = func(stdin io.Reader, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, errors.Wrapf(err, "%s %s: %s", name, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func (d *Driver) run(args ...string) (string, error) {
+	out, err := runCommand(nil, d.Binary, args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+// DriverName returns the name of the driver, which is the client of the
+// container engine.
+func (d *Driver) DriverName() string {
+	return d.Binary
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that the container engine is running.
+func (d *Driver) PreCreateCheck() error {
+	if _, err := d.run("version"); err != nil {
+		return errors.Wrapf(err, "The %s driver needs %s installed and running", d.Binary, d.Binary)
+	}
+	return nil
+}
+
+// Create runs the container of the node, and lets the key of the machine in.
+func (d *Driver) Create() error {
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "Error generating the ssh key")
+	}
+	if _, err := d.run(d.runArgs()...); err != nil {
+		return errors.Wrap(err, "Error running the node container")
+	}
+
+	key, err := ioutil.ReadFile(d.GetSSHKeyPath() + ".pub")
+	if err != nil {
+		return errors.Wrap(err, "Error reading the public ssh key")
+	}
+	authorize := fmt.Sprintf("mkdir -p /home/%[1]s/.ssh && cat >> /home/%[1]s/.ssh/authorized_keys && chown -R %[1]s /home/%[1]s/.ssh && chmod 600 /home/%[1]s/.ssh/authorized_keys", sshUser)
+	if out, err := runCommand(bytes.NewReader(key), d.Binary, "exec", "-i", d.MachineName, "sh", "-c", authorize); err != nil {
+		return errors.Wrapf(err, "Error authorizing the ssh key: %s", out)
+	}
+	return nil
+}
+
+// runArgs returns the arguments running the container of the node. systemd
+// needs the cgroups and tmpfs mounts, and docker in the node a /var which is
+// not on an overlay filesystem. Rootless podman can't limit the resources of
+// the container on most hosts, which don't delegate the cgroups to the user.
+func (d *Driver) runArgs() []string {
+	args := []string{"run", "--detach", "--privileged",
+		"--name", d.MachineName,
+		"--hostname", d.MachineName,
+		"--security-opt", "seccomp=unconfined",
+		"--tmpfs", "/run",
+		"--tmpfs", "/tmp",
+		"--volume", "/sys/fs/cgroup:/sys/fs/cgroup:ro",
+		"--volume", "/lib/modules:/lib/modules:ro",
+		"--volume", d.MachineName + ":/var",
+		"--publish", "127.0.0.1::22",
+	}
+	if d.Rootless {
+		return append(args,
+			"--publish", fmt.Sprintf("127.0.0.1:%[1]d:%[1]d", constants.APIServerPort),
+			"--publish", fmt.Sprintf("127.0.0.1:%[1]d:%[1]d", constants.DockerDaemonPort),
+			d.Image)
+	}
+	if d.Memory > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", d.Memory))
+	}
+	if d.CPU > 0 {
+		args = append(args, "--cpus", strconv.Itoa(d.CPU))
+	}
+	return append(args, d.Image)
+}
+
+// GetSSHHostname returns the address sshd is published on.
+func (d *Driver) GetSSHHostname() (string, error) {
+	return "127.0.0.1", nil
+}
+
+// GetSSHPort returns the port sshd is published on, which changes each time
+// the container starts.
+func (d *Driver) GetSSHPort() (int, error) {
+	out, err := d.run("port", d.MachineName, "22/tcp")
+	if err != nil {
+		return 0, errors.Wrap(err, "Error getting the ssh port of the node")
+	}
+	// The port of each address it is published on, one per line
+	_, port, err := net.SplitHostPort(strings.Split(out, "\n")[0])
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error parsing the ssh port %q", out)
+	}
+	return strconv.Atoi(port)
+}
+
+// GetIP returns the address of the container on the network of the
+// container engine, or localhost for rootless podman.
+func (d *Driver) GetIP() (string, error) {
+	if d.Rootless {
+		return "127.0.0.1", nil
+	}
+	out, err := d.run("inspect", "--format", "{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}", d.MachineName)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting the IP of the node")
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", errors.New("The node has no IP, is it running?")
+	}
+	return fields[0], nil
+}
+
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(constants.DockerDaemonPort))), nil
+}
+
+func (d *Driver) GetState() (state.State, error) {
+	out, err := d.run("inspect", "--format", "{{.State.Status}}", d.MachineName)
+	if err != nil {
+		if isNotFound(err) {
+			return state.None, nil
+		}
+		return state.Error, errors.Wrap(err, "Error getting the state of the node")
+	}
+	switch out {
+	case "running":
+		return state.Running, nil
+	case "paused":
+		return state.Paused, nil
+	case "restarting":
+		return state.Starting, nil
+	case "removing":
+		return state.Stopping, nil
+	case "configured", "created", "exited", "stopped", "dead":
+		return state.Stopped, nil
+	}
+	return state.None, nil
+}
+
+func (d *Driver) Start() error {
+	_, err := d.run("start", d.MachineName)
+	return err
+}
+
+func (d *Driver) Stop() error {
+	_, err := d.run("stop", d.MachineName)
+	return err
+}
+
+func (d *Driver) Restart() error {
+	_, err := d.run("restart", d.MachineName)
+	return err
+}
+
+func (d *Driver) Kill() error {
+	_, err := d.run("kill", d.MachineName)
+	return err
+}
+
+// Remove removes the container and its volume.
+func (d *Driver) Remove() error {
+	if _, err := d.run("rm", "--force", "--volumes", d.MachineName); err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "Error removing the node container")
+	}
+	if _, err := d.run("volume", "rm", d.MachineName); err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "Error removing the volume of the node")
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+// fakeEngine answers the commands of the driver with the outputs of their
+// arguments, and records them.
+type fakeEngine struct {
+	outputs  map[string]string
+	errors   map[string]string
+	commands []string
+}
+
+func (f *fakeEngine) run(stdin io.Reader, name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(args, " ")
+	f.commands = append(f.commands, name+" "+cmd)
+	if msg, ok := f.errors[cmd]; ok {
+		return nil, fmt.Errorf("%s %s: %s", name, cmd, msg)
+	}
+	return []byte(f.outputs[cmd]), nil
+}
+
+func withFakeEngine(f *fakeEngine) func() {
+	orig := runCommand
+	runCommand = f.run
+	return func() { runCommand = orig }
+}
+
+func TestGetSSHPort(t *testing.T) {
+	f := &fakeEngine{outputs: map[string]string{"port minikube 22/tcp": "127.0.0.1:32768\n"}}
+	defer withFakeEngine(f)()
+
+	port, err := NewDriver("docker", "minikube", "/tmp").GetSSHPort()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if port != 32768 {
+		t.Errorf("Expected port 32768, got %d", port)
+	}
+}
+
+func TestGetIP(t *testing.T) {
+	f := &fakeEngine{outputs: map[string]string{}}
+	defer withFakeEngine(f)()
+	d := NewDriver("docker", "minikube", "/tmp")
+
+	f.outputs["inspect --format {{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}} minikube"] = "172.17.0.2 \n"
+	ip, err := d.GetIP()
+	if err != nil || ip != "172.17.0.2" {
+		t.Errorf("Expected 172.17.0.2, got %s, %v", ip, err)
+	}
+	url, err := d.GetURL()
+	if err != nil || url != "tcp://172.17.0.2:2376" {
+		t.Errorf("Expected tcp://172.17.0.2:2376, got %s, %v", url, err)
+	}
+
+	f.outputs["inspect --format {{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}} minikube"] = " \n"
+	if _, err := d.GetIP(); err == nil {
+		t.Errorf("Expected an error for a node without an IP")
+	}
+}
+
+func TestGetState(t *testing.T) {
+	var tcs = []struct {
+		status   string
+		err      string
+		expected state.State
+	}{
+		{status: "running", expected: state.Running},
+		{status: "exited", expected: state.Stopped},
+		{status: "created", expected: state.Stopped},
+		{status: "paused", expected: state.Paused},
+		{status: "configured", expected: state.Stopped},
+		{err: "Error: No such object: minikube", expected: state.None},
+		{err: "Error: no such container minikube", expected: state.None},
+	}
+	for _, test := range tcs {
+		f := &fakeEngine{outputs: map[string]string{}, errors: map[string]string{}}
+		if test.err != "" {
+			f.errors["inspect --format {{.State.Status}} minikube"] = test.err
+		} else {
+			f.outputs["inspect --format {{.State.Status}} minikube"] = test.status + "\n"
+		}
+		restore := withFakeEngine(f)
+		s, err := NewDriver("docker", "minikube", "/tmp").GetState()
+		restore()
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", test.status, err)
+		}
+		if s != test.expected {
+			t.Errorf("Expected %s for %q, got %s", test.expected, test.status, s)
+		}
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	d := NewDriver("docker", "minikube", "/tmp")
+	d.Image = "gcr.io/k8s-minikube/node:v1.0.7"
+	d.Memory = 2048
+	d.CPU = 2
+	args := strings.Join(d.runArgs(), " ")
+	for _, expected := range []string{"run --detach --privileged --name minikube", "--volume minikube:/var", "--publish 127.0.0.1::22", "--memory 2048m", "--cpus 2"} {
+		if !strings.Contains(args, expected) {
+			t.Errorf("Expected the arguments to contain %q: %s", expected, args)
+		}
+	}
+	if !strings.HasSuffix(args, " "+d.Image) {
+		t.Errorf("Expected the image last: %s", args)
+	}
+}
+
+func TestRunArgsRootless(t *testing.T) {
+	d := NewDriver("podman", "minikube", "/tmp")
+	d.Image = "gcr.io/k8s-minikube/node:v1.0.7"
+	d.Memory = 2048
+	d.Rootless = true
+	args := strings.Join(d.runArgs(), " ")
+	for _, expected := range []string{"--publish 127.0.0.1::22", "--publish 127.0.0.1:8443:8443", "--publish 127.0.0.1:2376:2376"} {
+		if !strings.Contains(args, expected) {
+			t.Errorf("Expected the arguments to contain %q: %s", expected, args)
+		}
+	}
+	if strings.Contains(args, "--memory") {
+		t.Errorf("Expected no resource limits for rootless podman: %s", args)
+	}
+
+	ip, err := d.GetIP()
+	if err != nil || ip != "127.0.0.1" {
+		t.Errorf("Expected 127.0.0.1 for rootless podman, got %s, %v", ip, err)
+	}
+}
+
+func TestRemoveMissingNode(t *testing.T) {
+	f := &fakeEngine{errors: map[string]string{
+		"rm --force --volumes minikube": "Error: No such container: minikube",
+		"volume rm minikube":            "Error: No such volume: minikube",
+	}}
+	defer withFakeEngine(f)()
+
+	if err := NewDriver("docker", "minikube", "/tmp").Remove(); err != nil {
+		t.Errorf("Expected removing a missing node to succeed: %s", err)
+	}
+}
+
+func TestMarshalDriver(t *testing.T) {
+	d := NewDriver("docker", "minikube", "/tmp")
+	d.Image = "gcr.io/k8s-minikube/node:v1.0.7"
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	loaded := NewDriver("", "", "")
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if loaded.DriverName() != "docker" || loaded.GetMachineName() != "minikube" || loaded.Image != d.Image || loaded.GetSSHUsername() != "docker" {
+		t.Errorf("Unexpected driver %+v", loaded)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drivers holds what the libmachine drivers of minikube booting the
+// minikube ISO share.
+package drivers
+
+import (
+	"os"
+
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/pkg/errors"
+)
+
+// CreateRawDisk makes the raw disk of the VM at diskPath, of sizeMB, which
+// starts with the public key of the machine key at keyPath in a tar the ISO
+// formats the disk after reading.
+func CreateRawDisk(keyPath, diskPath string, sizeMB int) error {
+	tar, err := mcnutils.MakeDiskImage(keyPath + ".pub")
+	if err != nil {
+		return errors.Wrap(err, "Error making the disk image")
+	}
+	f, err := os.OpenFile(diskPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error creating the disk")
+	}
+	defer f.Close()
+	if _, err := f.Write(tar.Bytes()); err != nil {
+		return errors.Wrap(err, "Error writing the disk")
+	}
+	// The disk is sparse, it only takes the space the VM writes
+	if err := f.Truncate(int64(sizeMB) * 1024 * 1024); err != nil {
+		return errors.Wrap(err, "Error resizing the disk")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/machine/libmachine/ssh"
+)
+
+func TestCreateRawDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyPath, diskPath := filepath.Join(dir, "id_rsa"), filepath.Join(dir, "minikube.rawdisk")
+	if err := ssh.GenerateSSHKey(keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CreateRawDisk(keyPath, diskPath, 20); err != nil {
+		t.Fatalf("Unexpected error creating the disk: %s", err)
+	}
+	info, err := os.Stat(diskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 20*1024*1024 {
+		t.Errorf("Expected a disk of 20MB, got %d bytes", info.Size())
+	}
+	f, err := os.Open(diskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if h, err := tar.NewReader(f).Next(); err != nil || h.Name == "" {
+		t.Errorf("Expected the disk to start with the tar holding the key, got %v, %v", h, err)
+	}
+}
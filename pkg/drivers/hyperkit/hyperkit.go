@@ -0,0 +1,297 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hyperkit is a libmachine driver running the minikube VM with
+// hyperkit, the hypervisor of Docker for Mac, on the Hypervisor framework of
+// macOS. The VM is on vmnet, the network macOS shares with its VMs, which
+// needs root: the driver is the docker-machine-driver-hyperkit plugin, owned
+// by root and setuid.
+package hyperkit
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/vmnet"
+)
+
+// DriverName is the name of the driver
+const DriverName = "hyperkit"
+
+// DefaultNFSSharesRoot is where the NFS shares are mounted in the VM
+const DefaultNFSSharesRoot = "/nfsshares"
+
+// The files of the VM in the machine directory
+const (
+	isoFile    = "boot2docker.iso"
+	kernelFile = "bzImage"
+	initrdFile = "initrd"
+	pidFile    = "hyperkit.pid"
+	logFile    = "hyperkit.log"
+)
+
+// The processes and user of the driver, they are replaced in tests.
+var (
+	startProcess = func(name string, args []string, logPath string) error {
+		log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return errors.Wrapf(err, "Error opening %s", logPath)
+		}
+		defer log.Close()
+		cmd := exec.Command(name, args...)
+		cmd.Stdout, cmd.Stderr = log, log
+		detach(cmd)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+	runSSHCommand = drivers.RunSSHCommandFromDriver
+	geteuid       = os.Geteuid
+)
+
+// Driver runs the VM as a hyperkit process, booting the kernel of the ISO.
+type Driver struct {
+	*pkgdrivers.VMProcess
+
+	// Hyperkit is the hyperkit binary
+	Hyperkit string
+	// Cmdline is the command line of the kernel
+	Cmdline string
+	// UUID identifies the VM to vmnet, which derives its MAC address from it,
+	// read from the DHCP leases at the first start
+	UUID string
+	// NFSShares are the directories of the host mounted in the VM over NFS,
+	// under NFSSharesRoot
+	NFSShares     []string
+	NFSSharesRoot string
+}
+
+// NewDriver returns a driver for the VM of the machine.
+func NewDriver(machineName, storePath string) *Driver {
+	return &Driver{
+		VMProcess:     pkgdrivers.NewVMProcess(machineName, storePath, pidFile, "hyperkit"),
+		Hyperkit:      "hyperkit",
+		Cmdline:       "loglevel=3 user=docker console=ttyS0 console=tty0 noembed nomodeset norestore waitusb=10 base host=" + machineName,
+		NFSSharesRoot: DefaultNFSSharesRoot,
+	}
+}
+
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that the driver runs as root, which vmnet needs, and
+// that hyperkit is installed.
+func (d *Driver) PreCreateCheck() error {
+	if geteuid() != 0 {
+		return errors.New(`docker-machine-driver-hyperkit needs to run as root for vmnet, make it setuid root:
+	sudo chown root:wheel $(which docker-machine-driver-hyperkit)
+	sudo chmod u+s $(which docker-machine-driver-hyperkit)`)
+	}
+	if _, err := exec.LookPath(d.Hyperkit); err != nil {
+		return errors.Wrapf(err, "The hyperkit driver needs %s, which comes with Docker for Mac", d.Hyperkit)
+	}
+	return nil
+}
+
+// Create copies the ISO and extracts its kernel, makes the disk holding the
+// key of the machine, and starts the VM.
+func (d *Driver) Create() error {
+	b2dutils := mcnutils.NewB2dUtils(d.StorePath)
+	if err := b2dutils.CopyIsoToMachineDir(d.Boot2DockerURL, d.MachineName); err != nil {
+		return errors.Wrap(err, "Error copying the ISO")
+	}
+	// hyperkit boots the kernel rather than the ISO, which the bsdtar of macOS
+	// can read
+	if _, err := pkgdrivers.RunCommand("tar", "-xf", d.ResolveStorePath(isoFile), "-C", d.ResolveStorePath("."),
+		"--strip-components", "1", "boot/"+kernelFile, "boot/"+initrdFile); err != nil {
+		return errors.Wrap(err, "Error extracting the kernel of the ISO")
+	}
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "Error generating the ssh key")
+	}
+	if err := pkgdrivers.CreateRawDisk(d.GetSSHKeyPath(), d.DiskPath(), d.DiskSize); err != nil {
+		return err
+	}
+	// minikube, running as the user, reads the key and deletes the files
+	if err := chownToUser(d.ResolveStorePath(isoFile), d.ResolveStorePath(kernelFile), d.ResolveStorePath(initrdFile),
+		d.GetSSHKeyPath(), d.GetSSHKeyPath()+".pub", d.DiskPath()); err != nil {
+		return err
+	}
+	if d.UUID == "" {
+		uuid, err := randomUUID()
+		if err != nil {
+			return err
+		}
+		d.UUID = uuid
+	}
+	return d.Start()
+}
+
+// chownToUser gives the files the setuid driver created to the user running
+// it.
+func chownToUser(paths ...string) error {
+	if geteuid() != 0 || os.Getuid() == 0 {
+		return nil
+	}
+	for _, p := range paths {
+		if err := os.Chown(p, os.Getuid(), os.Getgid()); err != nil {
+			return errors.Wrapf(err, "Error changing the owner of %s", p)
+		}
+	}
+	return nil
+}
+
+// randomUUID returns a random version 4 UUID.
+func randomUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Error generating the UUID of the VM")
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// args returns the arguments of hyperkit running the VM.
+func (d *Driver) args() []string {
+	return []string{
+		"-A", "-u",
+		"-F", d.PidPath(),
+		"-c", strconv.Itoa(d.CPU),
+		"-m", fmt.Sprintf("%dM", d.Memory),
+		"-s", "0:0,hostbridge",
+		"-s", "31,lpc",
+		"-s", "1:0,virtio-net",
+		"-U", d.UUID,
+		"-s", "2:0,virtio-blk," + d.DiskPath(),
+		"-s", "3,ahci-cd," + d.ResolveStorePath(isoFile),
+		"-s", "4,virtio-rnd",
+		"-l", fmt.Sprintf("com1,autopty=%s,log=%s", d.ResolveStorePath("tty"), d.ResolveStorePath("console-ring")),
+		"-f", fmt.Sprintf("kexec,%s,%s,%s", d.ResolveStorePath(kernelFile), d.ResolveStorePath(initrdFile), d.Cmdline),
+	}
+}
+
+// leftoverProcesses returns the hyperkit processes running the disk of the
+// VM, whether or not the pid file still names them.
+func (d *Driver) leftoverProcesses() ([]int, error) {
+	out, err := pkgdrivers.RunCommand("ps", "-ax", "-o", "pid=,command=")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing the processes")
+	}
+	var pids []int
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.Contains(fields[1], "hyperkit") || !strings.Contains(line, d.DiskPath()) {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[0]); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// Start runs hyperkit, waits for the VM to get its address, and mounts the
+// NFS shares.
+func (d *Driver) Start() error {
+	if err := d.CheckStopped(); err != nil {
+		return err
+	}
+	if err := startProcess(d.Hyperkit, d.args(), d.ResolveStorePath(logFile)); err != nil {
+		return errors.Wrapf(err, "Error running %s, see %s", d.Hyperkit, d.ResolveStorePath(logFile))
+	}
+	if err := d.WaitForLease(); err != nil {
+		return err
+	}
+	if len(d.NFSShares) == 0 {
+		return nil
+	}
+	return d.mountNFSShares()
+}
+
+// mountNFSShares exports the shares to the VM and mounts them in the VM.
+func (d *Driver) mountNFSShares() error {
+	err := writeExports(func(exports string) string {
+		return exportsWith(exports, d.MachineName, d.IPAddress, d.NFSShares, os.Getuid(), os.Getgid())
+	})
+	if err != nil {
+		return err
+	}
+	host, err := vmnet.HostIP(d.IPAddress)
+	if err != nil {
+		return err
+	}
+	if err := drivers.WaitForSSH(d); err != nil {
+		return err
+	}
+	for _, share := range d.NFSShares {
+		if _, err := runSSHCommand(d, mountCommand(host, d.NFSSharesRoot, share)); err != nil {
+			return errors.Wrapf(err, "Error mounting the NFS share %s", share)
+		}
+	}
+	return nil
+}
+
+// Stop powers the VM off, as hyperkit does on SIGTERM.
+func (d *Driver) Stop() error {
+	return d.StopWith(func(pid int) error {
+		return pkgdrivers.Signal(pid, syscall.SIGTERM)
+	})
+}
+
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+// Remove kills the hyperkit processes left running the VM, and removes its
+// NFS exports. The disk and the ISO go with the directory of the machine.
+func (d *Driver) Remove() error {
+	pids, err := d.leftoverProcesses()
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		glog.Infof("Killing the hyperkit process %d", pid)
+		if err := pkgdrivers.Signal(pid, syscall.SIGKILL); err != nil {
+			return errors.Wrapf(err, "Error killing the hyperkit process %d", pid)
+		}
+	}
+	return writeExports(func(exports string) string {
+		return exportsWithout(exports, d.MachineName)
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hyperkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/vmnet"
+)
+
+const leases = `{
+	name=minikube
+	ip_address=192.168.64.2
+	hw_address=1,a:b2:3:d4:e5:f6
+	identifier=1,a:b2:3:d4:e5:f6
+	lease=0x5a000000
+}
+{
+	name=minikube
+	ip_address=192.168.64.5
+	hw_address=1,12:34:56:78:9a:bc
+	identifier=1,12:34:56:78:9a:bc
+	lease=0x5b000000
+}
+{
+	name=other
+	ip_address=192.168.64.3
+	hw_address=1,de:ad:be:ef:0:1
+	lease=0x5c000000
+}
+`
+
+func TestExports(t *testing.T) {
+	existing := "/Volumes/data -alldirs 10.0.0.2\n"
+	exports := exportsWith(existing, "minikube", "192.168.64.5", []string{"/Users"}, 501, 20)
+	expected := existing + "# minikube minikube begin\n\"/Users\" -alldirs -mapall=501:20 192.168.64.5\n# minikube minikube end\n"
+	if exports != expected {
+		t.Errorf("Expected exports\n%s\ngot\n%s", expected, exports)
+	}
+	// A restart replaces the exports of the last one
+	if again := exportsWith(exports, "minikube", "192.168.64.6", []string{"/Users"}, 501, 20); strings.Contains(again, "192.168.64.5") {
+		t.Errorf("Expected the exports of the last start to be replaced, got\n%s", again)
+	}
+	if without := exportsWithout(exports, "minikube"); without != existing {
+		t.Errorf("Expected the exports of the machine to be removed, got\n%s", without)
+	}
+}
+
+func TestMountCommand(t *testing.T) {
+	expected := "sudo mkdir -p /nfsshares/Users && sudo mount -t nfs -o noacl,async 192.168.64.1:/Users /nfsshares/Users"
+	if cmd := mountCommand("192.168.64.1", DefaultNFSSharesRoot, "/Users"); cmd != expected {
+		t.Errorf("Expected %q, got %q", expected, cmd)
+	}
+}
+
+// fakeHost runs the commands with their outputs, records them and the
+// signals, and has hyperkit write its pid file when started.
+type fakeHost struct {
+	outputs  map[string]string
+	commands []string
+	signals  map[int]syscall.Signal
+	started  []string
+}
+
+func (f *fakeHost) run(name string, args ...string) (string, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, cmd)
+	out, ok := f.outputs[cmd]
+	if !ok && name == "ps" {
+		return "", fmt.Errorf("exit status 1")
+	}
+	return out, nil
+}
+
+func (f *fakeHost) start(name string, args []string, logPath string) error {
+	f.started = args
+	f.outputs["ps -p 42 -o command="] = "/usr/local/bin/hyperkit -A -u\n"
+	return ioutil.WriteFile(args[3], []byte("42\n"), 0644)
+}
+
+func (f *fakeHost) signal(pid int, sig syscall.Signal) error {
+	f.signals[pid] = sig
+	return nil
+}
+
+func withFakeHost(t *testing.T, f *fakeHost) (string, func()) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "machines", "minikube"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "leases"), []byte(leases), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origRun, origStart, origSignal, origLeases, origExports := pkgdrivers.RunCommand, startProcess, pkgdrivers.Signal, vmnet.LeasesPath, exportsPath
+	pkgdrivers.RunCommand, startProcess, pkgdrivers.Signal = f.run, f.start, f.signal
+	vmnet.LeasesPath, exportsPath = filepath.Join(dir, "leases"), filepath.Join(dir, "exports")
+	return dir, func() {
+		pkgdrivers.RunCommand, startProcess, pkgdrivers.Signal, vmnet.LeasesPath, exportsPath = origRun, origStart, origSignal, origLeases, origExports
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	f := &fakeHost{outputs: map[string]string{}, signals: map[int]syscall.Signal{}}
+	dir, cleanup := withFakeHost(t, f)
+	defer cleanup()
+	d := NewDriver("minikube", dir)
+	d.UUID = "c4f4c2b8-5ea2-4a7c-a4e1-3b1f0d7e5a10"
+
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped before the start, got %s, %v", s, err)
+	}
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the VM: %s", err)
+	}
+	if d.IPAddress != "192.168.64.5" || d.MAC != "12:34:56:78:9a:bc" {
+		t.Errorf("Expected the latest lease of the machine, got %s, %s", d.IPAddress, d.MAC)
+	}
+	if !reflect.DeepEqual(f.started[:2], []string{"-A", "-u"}) || !strings.Contains(strings.Join(f.started, " "), "-U "+d.UUID) {
+		t.Errorf("Unexpected arguments of hyperkit %v", f.started)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Errorf("Expected Running, got %s, %v", s, err)
+	}
+	if err := d.Start(); err == nil {
+		t.Errorf("Expected an error starting a running VM")
+	}
+
+	// A pid reused by another process is not the VM
+	f.outputs["ps -p 42 -o command="] = "/usr/sbin/cupsd\n"
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped for a reused pid, got %s, %v", s, err)
+	}
+	if err := d.Stop(); err != nil || len(f.signals) != 0 {
+		t.Errorf("Expected stopping a stopped VM to do nothing, got %v, %v", f.signals, err)
+	}
+}
+
+func TestRemoveKillsLeftovers(t *testing.T) {
+	f := &fakeHost{outputs: map[string]string{}, signals: map[int]syscall.Signal{}}
+	dir, cleanup := withFakeHost(t, f)
+	defer cleanup()
+	d := NewDriver("minikube", dir)
+	f.outputs["ps -ax -o pid=,command="] = fmt.Sprintf(
+		"    1 /sbin/launchd\n"+
+			"   42 /usr/local/bin/hyperkit -A -u -s 2:0,virtio-blk,%s\n"+
+			"   43 /usr/local/bin/hyperkit -A -u -s 2:0,virtio-blk,/other/other.rawdisk\n"+
+			"   44 vim %s\n", d.DiskPath(), d.DiskPath())
+	exports := "/Volumes/data -alldirs 10.0.0.2\n# minikube minikube begin\n\"/Users\" -alldirs -mapall=501:20 192.168.64.5\n# minikube minikube end\n"
+	if err := ioutil.WriteFile(exportsPath, []byte(exports), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Remove(); err != nil {
+		t.Fatalf("Unexpected error removing the VM: %s", err)
+	}
+	if !reflect.DeepEqual(f.signals, map[int]syscall.Signal{42: syscall.SIGKILL}) {
+		t.Errorf("Expected the hyperkit process of the VM to be killed, got %v", f.signals)
+	}
+	data, err := ioutil.ReadFile(exportsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "/Volumes/data -alldirs 10.0.0.2\n" {
+		t.Errorf("Expected the exports of the machine to be removed, got\n%s", data)
+	}
+	if f.commands[len(f.commands)-1] != "nfsd restart" {
+		t.Errorf("Expected nfsd to reload the exports, ran %v", f.commands)
+	}
+}
+
+func TestPreCreateCheckNeedsRoot(t *testing.T) {
+	orig := geteuid
+	geteuid = func() int { return 501 }
+	defer func() { geteuid = orig }()
+	if err := NewDriver("minikube", "/tmp").PreCreateCheck(); err == nil || !strings.Contains(err.Error(), "chmod u+s") {
+		t.Errorf("Expected an error explaining how to make the driver setuid, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hyperkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+// exportsPath is the exports file of nfsd, it is replaced in tests.
+var exportsPath = "/etc/exports"
+
+// exportsMarkers return the comments around the exports of the machine,
+// which delete removes.
+func exportsMarkers(machine string) (string, string) {
+	return fmt.Sprintf("# minikube %s begin", machine), fmt.Sprintf("# minikube %s end", machine)
+}
+
+// exportsWithout returns the exports without the ones of the machine.
+func exportsWithout(exports, machine string) string {
+	begin, end := exportsMarkers(machine)
+	var lines []string
+	inBlock := false
+	for _, line := range strings.Split(exports, "\n") {
+		switch {
+		case line == begin:
+			inBlock = true
+		case line == end:
+			inBlock = false
+		case !inBlock:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// exportsWith returns the exports with the shares of the machine, exported
+// to the VM only and mapping all its users to the user of the host, replacing
+// the ones of the last start.
+func exportsWith(exports, machine, vmIP string, shares []string, uid, gid int) string {
+	exports = strings.TrimRight(exportsWithout(exports, machine), "\n")
+	begin, end := exportsMarkers(machine)
+	lines := []string{begin}
+	for _, share := range shares {
+		lines = append(lines, fmt.Sprintf("%q -alldirs -mapall=%d:%d %s", share, uid, gid, vmIP))
+	}
+	lines = append(lines, end)
+	if exports != "" {
+		lines = append([]string{exports}, lines...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// writeExports rewrites the exports file with the function of its contents,
+// and has nfsd reload it.
+func writeExports(update func(string) string) error {
+	data, err := ioutil.ReadFile(exportsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Error reading %s", exportsPath)
+	}
+	updated := update(string(data))
+	if updated == string(data) {
+		return nil
+	}
+	if err := ioutil.WriteFile(exportsPath, []byte(updated), 0644); err != nil {
+		return errors.Wrapf(err, "Error writing %s", exportsPath)
+	}
+	if _, err := pkgdrivers.RunCommand("nfsd", "restart"); err != nil {
+		return errors.Wrap(err, "Error restarting nfsd")
+	}
+	return nil
+}
+
+// mountPath returns where the share is mounted in the VM.
+func mountPath(root, share string) string {
+	return path.Join(root, share)
+}
+
+// mountCommand returns the command mounting the share of the host in the VM.
+func mountCommand(host, root, share string) string {
+	target := mountPath(root, share)
+	return fmt.Sprintf("sudo mkdir -p %s && sudo mount -t nfs -o noacl,async %s:%s %s", target, host, share, target)
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kvm2 is a libmachine driver running the minikube VM with KVM,
+// through libvirt. It is built into minikube and into the
+// docker-machine-driver-kvm2 plugin, and manages libvirt with virsh, so it
+// needs no cgo bindings of libvirt.
+package kvm2
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// DriverName is the name of the driver
+const DriverName = "kvm2"
+
+// The user the minikube ISO lets in with the key of the machine
+const sshUser = "docker"
+
+// DefaultPrivateNetwork is the network of libvirt the VMs reach each other
+// and the host on, which the driver creates when it is missing.
+const DefaultPrivateNetwork = "minikube-net"
+
+// How long Start waits for the VM to get an address
+var (
+	ipAttempts      = 60
+	ipRetryInterval = 2 * time.Second
+)
+
+// Driver runs the VM as a domain of libvirt named after the machine.
+type Driver struct {
+	*drivers.BaseDriver
+
+	Memory int
+	CPU    int
+	// DiskSize is the size of the disk in MB
+	DiskSize       int
+	Boot2DockerURL string
+	// Network is the network of libvirt giving the VM access to the internet
+	Network string
+	// PrivateNetwork is the network of the VM with the host
+	PrivateNetwork string
+	// MAC and PrivateMAC are the addresses of the interfaces of the VM on
+	// Network and PrivateNetwork, generated when the VM is created
+	MAC        string
+	PrivateMAC string
+	// StaticIP is the address of the VM on PrivateNetwork, the one leased at
+	// its first start when empty. It is reserved for PrivateMAC, so the VM
+	// keeps it across restarts.
+	StaticIP string
+	ISO      string
+	DiskPath string
+}
+
+// NewDriver returns a driver for the VM of the machine.
+func NewDriver(machineName, storePath string) *Driver {
+	d := &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: machineName,
+			StorePath:   storePath,
+			SSHUser:     sshUser,
+		},
+		Memory:         constants.DefaultMemory,
+		CPU:            constants.DefaultCPUS,
+		Network:        "default",
+		PrivateNetwork: DefaultPrivateNetwork,
+	}
+	d.ISO = d.ResolveStorePath("boot2docker.iso")
+	d.DiskPath = d.ResolveStorePath(machineName + ".rawdisk")
+	return d
+}
+
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that libvirt is running and the network giving the
+// VM access to the internet exists.
+func (d *Driver) PreCreateCheck() error {
+	if _, err := runVirsh("version"); err != nil {
+		return errors.Wrap(err, "The kvm2 driver needs libvirt installed and running, and the user in its group")
+	}
+	if _, err := runVirsh("net-info", d.Network); err != nil {
+		return errors.Wrapf(err, "The network %s of libvirt was not found, set another one with --kvm-network", d.Network)
+	}
+	return nil
+}
+
+// Create copies the ISO, makes the disk holding the key of the machine,
+// creates the private network if it is missing, and defines and starts the
+// VM.
+func (d *Driver) Create() error {
+	b2dutils := mcnutils.NewB2dUtils(d.StorePath)
+	if err := b2dutils.CopyIsoToMachineDir(d.Boot2DockerURL, d.MachineName); err != nil {
+		return errors.Wrap(err, "Error copying the ISO")
+	}
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "Error generating the ssh key")
+	}
+	if err := pkgdrivers.CreateRawDisk(d.GetSSHKeyPath(), d.DiskPath, d.DiskSize); err != nil {
+		return err
+	}
+
+	var err error
+	if d.MAC == "" {
+		if d.MAC, err = randomMAC(); err != nil {
+			return err
+		}
+	}
+	if d.PrivateMAC == "" {
+		if d.PrivateMAC, err = randomMAC(); err != nil {
+			return err
+		}
+	}
+	if err := d.ensureNetworks(); err != nil {
+		return err
+	}
+	xml, err := render("domain", domainTmpl, d)
+	if err != nil {
+		return err
+	}
+	if err := define("define", d.ResolveStorePath(d.MachineName+".xml"), xml); err != nil {
+		return errors.Wrap(err, "Error defining the VM")
+	}
+	return d.Start()
+}
+
+// ensureNetworks starts the networks of the VM, creating the private one if
+// it is missing.
+func (d *Driver) ensureNetworks() error {
+	if err := ensureNetwork(d.Network, "", d.ResolveStorePath(".")); err != nil {
+		return err
+	}
+	return ensureNetwork(d.PrivateNetwork, privateNetworkTmpl, d.ResolveStorePath("."))
+}
+
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+// GetIP returns the address the private network leased to the VM.
+func (d *Driver) GetIP() (string, error) {
+	s, err := d.GetState()
+	if err != nil {
+		return "", err
+	}
+	if s != state.Running {
+		return "", errors.New("The VM is not running")
+	}
+	out, err := runVirsh("net-dhcp-leases", d.PrivateNetwork, "--mac", d.PrivateMAC)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting the leases of the private network")
+	}
+	ip := parseLease(out, d.PrivateMAC)
+	if ip == "" {
+		return "", errors.New("The VM has no address on the private network yet")
+	}
+	return ip, nil
+}
+
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(constants.DockerDaemonPort))), nil
+}
+
+func (d *Driver) GetState() (state.State, error) {
+	out, err := runVirsh("domstate", d.MachineName)
+	if err != nil {
+		if isNotFound(err) {
+			return state.None, nil
+		}
+		return state.Error, errors.Wrap(err, "Error getting the state of the VM")
+	}
+	switch strings.TrimSpace(out) {
+	case "running", "idle":
+		return state.Running, nil
+	case "paused", "pmsuspended":
+		return state.Paused, nil
+	case "in shutdown":
+		return state.Stopping, nil
+	case "shut off", "crashed":
+		return state.Stopped, nil
+	}
+	return state.None, nil
+}
+
+// Start starts the networks and the VM, and waits for the VM to get its
+// address.
+func (d *Driver) Start() error {
+	if err := d.ensureNetworks(); err != nil {
+		return err
+	}
+	if d.StaticIP != "" {
+		if err := reserveAddress(d.PrivateNetwork, d.PrivateMAC, d.StaticIP); err != nil {
+			return err
+		}
+	}
+	if _, err := runVirsh("start", d.MachineName); err != nil {
+		return errors.Wrap(err, "Error starting the VM")
+	}
+	for i := 0; i < ipAttempts; i++ {
+		ip, err := d.GetIP()
+		if err == nil {
+			d.IPAddress = ip
+			if d.StaticIP == "" {
+				// The lease would expire while the VM is stopped
+				if err := reserveAddress(d.PrivateNetwork, d.PrivateMAC, ip); err != nil {
+					return err
+				}
+				d.StaticIP = ip
+			}
+			return nil
+		}
+		glog.Infof("Waiting for the VM to get an address: %s", err)
+		time.Sleep(ipRetryInterval)
+	}
+	return errors.Errorf("The VM didn't get an address on %s, is the DHCP server of the network running?", d.PrivateNetwork)
+}
+
+// Stop shuts the VM down, as a power button would.
+func (d *Driver) Stop() error {
+	_, err := runVirsh("shutdown", d.MachineName)
+	return err
+}
+
+func (d *Driver) Restart() error {
+	if _, err := runVirsh("destroy", d.MachineName); err != nil {
+		return err
+	}
+	return d.Start()
+}
+
+func (d *Driver) Kill() error {
+	_, err := runVirsh("destroy", d.MachineName)
+	return err
+}
+
+// Remove stops and undefines the VM, and removes the private network once no
+// VM uses it anymore. The disk and the ISO go with the directory of the
+// machine.
+func (d *Driver) Remove() error {
+	if s, err := d.GetState(); err == nil && s != state.Stopped && s != state.None {
+		if _, err := runVirsh("destroy", d.MachineName); err != nil && !isNotFound(err) {
+			return errors.Wrap(err, "Error stopping the VM")
+		}
+	}
+	if _, err := runVirsh("undefine", d.MachineName); err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "Error undefining the VM")
+	}
+	// The reservation goes with the network, when no other VM uses it
+	if err := releaseAddress(d.PrivateNetwork, d.PrivateMAC); err != nil {
+		glog.Warningln(err)
+	}
+
+	users, err := networkUsers(d.PrivateNetwork)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+	// A network which is not active can't be destroyed
+	if _, err := runVirsh("net-destroy", d.PrivateNetwork); err != nil && !isNotFound(err) && !strings.Contains(err.Error(), "not active") {
+		return errors.Wrapf(err, "Error stopping the network %s", d.PrivateNetwork)
+	}
+	if _, err := runVirsh("net-undefine", d.PrivateNetwork); err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "Error undefining the network %s", d.PrivateNetwork)
+	}
+	return nil
+}
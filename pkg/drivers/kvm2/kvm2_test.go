@@ -0,0 +1,225 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+// fakeVirsh answers the commands with their outputs or errors, and records
+// them.
+type fakeVirsh struct {
+	outputs  map[string]string
+	errors   map[string]string
+	commands []string
+}
+
+func (f *fakeVirsh) run(args ...string) (string, error) {
+	cmd := strings.Join(args, " ")
+	f.commands = append(f.commands, cmd)
+	if msg, ok := f.errors[cmd]; ok {
+		return msg, fmt.Errorf("virsh %s: %s", cmd, msg)
+	}
+	return f.outputs[cmd], nil
+}
+
+func (f *fakeVirsh) ran(cmd string) bool {
+	for _, c := range f.commands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+func withFakeVirsh(f *fakeVirsh) func() {
+	orig := runVirsh
+	runVirsh = f.run
+	return func() { runVirsh = orig }
+}
+
+const leases = ` Expiry Time          MAC address        Protocol  IP address                Hostname        Client ID or DUID
+-------------------------------------------------------------------------------------------------------------------
+ 2017-06-01 10:00:00  52:54:00:aa:bb:cc  ipv4      192.168.39.12/24          minikube        -
+`
+
+func TestParseLease(t *testing.T) {
+	if ip := parseLease(leases, "52:54:00:AA:BB:CC"); ip != "192.168.39.12" {
+		t.Errorf("Expected 192.168.39.12, got %q", ip)
+	}
+	if ip := parseLease(leases, "52:54:00:00:00:01"); ip != "" {
+		t.Errorf("Expected no address for another MAC, got %q", ip)
+	}
+}
+
+func TestRandomMAC(t *testing.T) {
+	mac, err := randomMAC()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^52:54:00(:[0-9a-f]{2}){3}$`).MatchString(mac) {
+		t.Errorf("Unexpected MAC address %s", mac)
+	}
+}
+
+func TestGetState(t *testing.T) {
+	f := &fakeVirsh{outputs: map[string]string{}, errors: map[string]string{}}
+	defer withFakeVirsh(f)()
+	d := NewDriver("minikube", "/tmp")
+
+	for out, expected := range map[string]state.State{
+		"running\n":     state.Running,
+		"shut off\n":    state.Stopped,
+		"paused\n":      state.Paused,
+		"in shutdown\n": state.Stopping,
+	} {
+		f.outputs["domstate minikube"] = out
+		if s, err := d.GetState(); err != nil || s != expected {
+			t.Errorf("Expected %s for %q, got %s, %v", expected, out, s, err)
+		}
+	}
+	f.errors["domstate minikube"] = "error: failed to get domain 'minikube'"
+	if s, err := d.GetState(); err != nil || s != state.None {
+		t.Errorf("Expected None for a missing VM, got %s, %v", s, err)
+	}
+}
+
+func TestGetIP(t *testing.T) {
+	f := &fakeVirsh{outputs: map[string]string{
+		"domstate minikube": "running\n",
+		"net-dhcp-leases minikube-net --mac 52:54:00:aa:bb:cc": leases,
+	}}
+	defer withFakeVirsh(f)()
+	d := NewDriver("minikube", "/tmp")
+	d.PrivateMAC = "52:54:00:aa:bb:cc"
+
+	if ip, err := d.GetIP(); err != nil || ip != "192.168.39.12" {
+		t.Errorf("Expected 192.168.39.12, got %q, %v", ip, err)
+	}
+	f.outputs["domstate minikube"] = "shut off\n"
+	if _, err := d.GetIP(); err == nil {
+		t.Errorf("Expected an error for a stopped VM")
+	}
+}
+
+func TestStartReservesAddress(t *testing.T) {
+	active := "Name:           default\nActive:         yes\n"
+	f := &fakeVirsh{
+		outputs: map[string]string{
+			"net-info default":      active,
+			"net-info minikube-net": active,
+			"domstate minikube":     "running\n",
+			"net-dhcp-leases minikube-net --mac 52:54:00:aa:bb:cc": leases,
+		},
+		errors: map[string]string{
+			"net-update minikube-net delete ip-dhcp-host <host mac='52:54:00:aa:bb:cc'/> --live --config": "error: Requested operation is not valid: couldn't locate a matching dhcp host entry in network 'minikube-net'",
+		},
+	}
+	defer withFakeVirsh(f)()
+	d := NewDriver("minikube", "/tmp")
+	d.PrivateMAC = "52:54:00:aa:bb:cc"
+
+	// The address leased at the first start is kept
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the VM: %s", err)
+	}
+	reserve := "net-update minikube-net add-last ip-dhcp-host <host mac='52:54:00:aa:bb:cc' ip='192.168.39.12'/> --live --config"
+	if !f.ran(reserve) || d.StaticIP != "192.168.39.12" || d.IPAddress != "192.168.39.12" {
+		t.Errorf("Expected the leased address to be reserved, got %s, ran %v", d.StaticIP, f.commands)
+	}
+
+	// A static address is reserved before the VM starts
+	f.commands = nil
+	d.StaticIP = "192.168.39.50"
+	f.outputs["net-dhcp-leases minikube-net --mac 52:54:00:aa:bb:cc"] = strings.Replace(leases, "192.168.39.12", "192.168.39.50", -1)
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the VM: %s", err)
+	}
+	if len(f.commands) < 5 || f.commands[3] != strings.Replace(reserve, "192.168.39.12", "192.168.39.50", -1) || f.commands[4] != "start minikube" {
+		t.Errorf("Expected the static address to be reserved before the start, ran %v", f.commands)
+	}
+	if d.IPAddress != "192.168.39.50" {
+		t.Errorf("Expected the VM to get its static address, got %s", d.IPAddress)
+	}
+}
+
+func TestEnsureNetworkCreatesPrivateNetwork(t *testing.T) {
+	f := &fakeVirsh{
+		outputs: map[string]string{"net-info default": "Name:           default\nActive:         yes\n"},
+		errors:  map[string]string{"net-info minikube-net": "error: failed to get network 'minikube-net'"},
+	}
+	defer withFakeVirsh(f)()
+	storePath, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(storePath)
+	if err := os.MkdirAll(filepath.Join(storePath, "machines", "minikube"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDriver("minikube", storePath)
+
+	if err := d.ensureNetworks(); err != nil {
+		t.Fatalf("Unexpected error ensuring the networks: %s", err)
+	}
+	if f.ran("net-start default") {
+		t.Errorf("Expected the active network to be left alone, ran %v", f.commands)
+	}
+	for _, cmd := range []string{"net-autostart minikube-net", "net-start minikube-net"} {
+		if !f.ran(cmd) {
+			t.Errorf("Expected %q to run, ran %v", cmd, f.commands)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	f := &fakeVirsh{outputs: map[string]string{
+		"domstate minikube": "running\n",
+		"list --all --name": "other\n",
+		"domiflist other": " Interface  Type       Source     Model       MAC\n" +
+			"-------------------------------------------------------\n" +
+			" vnet0      network    default    virtio      52:54:00:00:00:01\n",
+	}}
+	defer withFakeVirsh(f)()
+
+	if err := NewDriver("minikube", "/tmp").Remove(); err != nil {
+		t.Fatalf("Unexpected error removing the VM: %s", err)
+	}
+	for _, cmd := range []string{"destroy minikube", "undefine minikube", "net-destroy minikube-net", "net-undefine minikube-net"} {
+		if !f.ran(cmd) {
+			t.Errorf("Expected %q to run, ran %v", cmd, f.commands)
+		}
+	}
+
+	// Another VM still uses the private network
+	f.commands = nil
+	f.outputs["domiflist other"] += " vnet1      network    minikube-net virtio    52:54:00:00:00:02\n"
+	if err := NewDriver("minikube", "/tmp").Remove(); err != nil {
+		t.Fatalf("Unexpected error removing the VM: %s", err)
+	}
+	if f.ran("net-undefine minikube-net") {
+		t.Errorf("Expected the private network used by another VM to be kept, ran %v", f.commands)
+	}
+}
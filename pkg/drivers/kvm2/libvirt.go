@@ -0,0 +1,263 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// connectionURI is the libvirt daemon of the system, which can create
+// networks, rather than the session daemon of the user.
+const connectionURI = "qemu:///system"
+
+// privateNetworkTmpl is the private network of the VMs, whose DHCP server
+// gives each VM the same address for its MAC address. The host is its
+// gateway, 192.168.39.1.
+const privateNetworkTmpl = `<network>
+  <name>{{.}}</name>
+  <dns enable='no'/>
+  <ip address='192.168.39.1' netmask='255.255.255.0'>
+    <dhcp>
+      <range start='192.168.39.2' end='192.168.39.254'/>
+    </dhcp>
+  </ip>
+</network>
+`
+
+// PrivateNetworkCIDR is the address of the host on the private network, and
+// its prefix.
+const PrivateNetworkCIDR = "192.168.39.1/24"
+
+// domainTmpl is the definition of the VM, booting the ISO with its disk, on
+// the network giving it access to the internet and the private network.
+const domainTmpl = `<domain type='kvm'>
+  <name>{{.MachineName}}</name>
+  <memory unit='MB'>{{.Memory}}</memory>
+  <vcpu>{{.CPU}}</vcpu>
+  <features>
+    <acpi/>
+    <apic/>
+    <pae/>
+  </features>
+  <cpu mode='host-passthrough'/>
+  <os>
+    <type>hvm</type>
+    <boot dev='cdrom'/>
+    <boot dev='hd'/>
+    <bootmenu enable='no'/>
+  </os>
+  <devices>
+    <disk type='file' device='cdrom'>
+      <source file='{{.ISO}}'/>
+      <target dev='hdc' bus='scsi'/>
+      <readonly/>
+    </disk>
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='raw' cache='default' io='threads'/>
+      <source file='{{.DiskPath}}'/>
+      <target dev='hda' bus='virtio'/>
+    </disk>
+    <interface type='network'>
+      <source network='{{.Network}}'/>
+      <mac address='{{.MAC}}'/>
+      <model type='virtio'/>
+    </interface>
+    <interface type='network'>
+      <source network='{{.PrivateNetwork}}'/>
+      <mac address='{{.PrivateMAC}}'/>
+      <model type='virtio'/>
+    </interface>
+    <serial type='pty'>
+      <target port='0'/>
+    </serial>
+    <console type='pty'>
+      <target type='serial' port='0'/>
+    </console>
+    <rng model='virtio'>
+      <backend model='random'>/dev/random</backend>
+    </rng>
+  </devices>
+</domain>
+`
+
+// runVirsh runs virsh against the libvirt daemon of the system, it is
+// replaced in tests.
+var runVirsh = func(args ...string) (string, error) {
+	args = append([]string{"--connect", connectionURI}, args...)
+	out, err := exec.Command("virsh", args...).CombinedOutput()
+	if err != nil {
+		return string(out), errors.Wrapf(err, "virsh %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// isNotFound reports whether the error is libvirt not finding the domain or
+// the network.
+func isNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "failed to get")
+}
+
+// render returns the template executed with the data.
+func render(name, tmpl string, data interface{}) ([]byte, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the %s template", name)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, errors.Wrapf(err, "Error rendering the %s template", name)
+	}
+	return b.Bytes(), nil
+}
+
+// define defines the domain or the network of the XML, saved to the path as
+// virsh reads the definitions from files.
+func define(command, path string, xml []byte) error {
+	if err := ioutil.WriteFile(path, xml, 0644); err != nil {
+		return errors.Wrapf(err, "Error writing %s", path)
+	}
+	_, err := runVirsh(command, path)
+	return err
+}
+
+// randomMAC returns a locally administered MAC address with the prefix of
+// qemu. It is generated once per VM and kept in its config, so the DHCP
+// server gives the VM the same address at each start.
+func randomMAC() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Error generating a MAC address")
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", b[0], b[1], b[2]), nil
+}
+
+// parseLease returns the address the DHCP server leased to the MAC address,
+// from the output of virsh net-dhcp-leases, or "" if it has none yet.
+func parseLease(out, mac string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		for i, f := range fields {
+			if !strings.EqualFold(f, mac) || i+2 >= len(fields) {
+				continue
+			}
+			// The MAC address is followed by the protocol and the address
+			return strings.Split(fields[i+2], "/")[0]
+		}
+	}
+	return ""
+}
+
+// ensureNetwork defines the network from the template if it doesn't exist,
+// and starts it if it isn't active.
+func ensureNetwork(name, tmpl, dir string) error {
+	out, err := runVirsh("net-info", name)
+	if err != nil {
+		if !isNotFound(err) || tmpl == "" {
+			return errors.Wrapf(err, "Error getting the network %s", name)
+		}
+		xml, err := render("network", tmpl, name)
+		if err != nil {
+			return err
+		}
+		if err := define("net-define", filepath.Join(dir, name+".xml"), xml); err != nil {
+			return errors.Wrapf(err, "Error defining the network %s", name)
+		}
+		if _, err := runVirsh("net-autostart", name); err != nil {
+			return errors.Wrapf(err, "Error setting the network %s to start with the host", name)
+		}
+		out = ""
+	}
+	if networkActive(out) {
+		return nil
+	}
+	if _, err := runVirsh("net-start", name); err != nil {
+		return errors.Wrapf(err, "Error starting the network %s", name)
+	}
+	return nil
+}
+
+// networkActive reports whether the output of virsh net-info shows an active
+// network.
+func networkActive(out string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "Active:" {
+			return fields[1] == "yes"
+		}
+	}
+	return false
+}
+
+// networkUsers returns the domains with an interface on the network.
+func networkUsers(network string) ([]string, error) {
+	out, err := runVirsh("list", "--all", "--name")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing the domains")
+	}
+	var users []string
+	for _, domain := range strings.Fields(out) {
+		ifaces, err := runVirsh("domiflist", domain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing the interfaces of %s", domain)
+		}
+		for _, line := range strings.Split(ifaces, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && fields[1] == "network" && fields[2] == network {
+				users = append(users, domain)
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+// reserveAddress makes the DHCP server of the network give the address to the
+// MAC address, replacing the reservation of the MAC address if any. A live
+// network and its definition are both updated.
+func reserveAddress(network, mac, ip string) error {
+	if err := releaseAddress(network, mac); err != nil {
+		return err
+	}
+	entry := fmt.Sprintf("<host mac='%s' ip='%s'/>", mac, ip)
+	if _, err := runVirsh("net-update", network, "add-last", "ip-dhcp-host", entry, "--live", "--config"); err != nil {
+		return errors.Wrapf(err, "Error reserving %s on the network %s", ip, network)
+	}
+	return nil
+}
+
+// releaseAddress removes the reservation of the MAC address, if any.
+func releaseAddress(network, mac string) error {
+	if mac == "" {
+		return nil
+	}
+	entry := fmt.Sprintf("<host mac='%s'/>", mac)
+	_, err := runVirsh("net-update", network, "delete", "ip-dhcp-host", entry, "--live", "--config")
+	if err != nil && !isNotFound(err) && !strings.Contains(err.Error(), "couldn't locate a matching dhcp host entry") {
+		return errors.Wrapf(err, "Error releasing the address of %s on the network %s", mac, network)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package none is a libmachine driver running the cluster on the machine
+// minikube runs on, without a VM, for machines which are already isolated,
+// like CI VMs. localkube runs as a systemd unit of the machine.
+package none
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/lan"
+)
+
+// DriverName is the name of the driver
+const DriverName = constants.DriverNone
+
+// Driver manages the localkube unit of the machine.
+type Driver struct {
+	*drivers.BaseDriver
+}
+
+// NewDriver returns a driver for the machine minikube runs on.
+func NewDriver(machineName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: machineName,
+			StorePath:   storePath,
+		},
+	}
+}
+
+// runCommand runs the command on the machine, it is replaced in tests.
+var runCommand = func(name string, args ...string) ([]byte, error) {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return out, errors.Wrapf(err, "%s %s: %s", name, strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// geteuid returns the user minikube runs as, it is replaced in tests.
+var geteuid = os.Geteuid
+
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that minikube runs as root on a machine managed by
+// systemd, as localkube is installed as a systemd unit of the machine.
+func (d *Driver) PreCreateCheck() error {
+	if geteuid() != 0 {
+		return errors.New("The none driver installs Kubernetes on this machine, and must be run as root, e.g. with sudo -E")
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return errors.New("The none driver needs a machine managed by systemd, systemctl was not found")
+	}
+	return nil
+}
+
+// Create does nothing, start installs localkube on the machine.
+func (d *Driver) Create() error {
+	return nil
+}
+
+// GetIP returns the address of the machine on the local network.
+func (d *Driver) GetIP() (string, error) {
+	ips, err := lan.HostIPs()
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting the addresses of the machine")
+	}
+	if len(ips) == 0 {
+		return "", errors.New("The machine has no address on the local network")
+	}
+	return ips[0].String(), nil
+}
+
+func (d *Driver) GetSSHHostname() (string, error) {
+	return "", fmt.Errorf("the %s driver doesn't use ssh", DriverName)
+}
+
+func (d *Driver) GetSSHPort() (int, error) {
+	return 0, fmt.Errorf("the %s driver doesn't use ssh", DriverName)
+}
+
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(constants.DockerDaemonPort))), nil
+}
+
+// GetState returns whether localkube is running: the machine itself always
+// is.
+func (d *Driver) GetState() (state.State, error) {
+	if _, err := runCommand("systemctl", "is-active", "-q", "localkube"); err != nil {
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+// Start does nothing, start starts localkube once it is updated.
+func (d *Driver) Start() error {
+	return nil
+}
+
+// Stop stops localkube and its watchdog.
+func (d *Driver) Stop() error {
+	return d.systemctl("stop", "localkube-watchdog.timer", "localkube")
+}
+
+func (d *Driver) Restart() error {
+	return d.systemctl("restart", "localkube")
+}
+
+func (d *Driver) Kill() error {
+	return d.systemctl("kill", "localkube")
+}
+
+// Remove stops localkube and deletes the files start installed, with the
+// state of the cluster.
+func (d *Driver) Remove() error {
+	if err := d.systemctl("disable", "--now", "localkube-watchdog.timer", "localkube"); err != nil {
+		return err
+	}
+	for _, p := range constants.LocalkubeInstalledPaths {
+		if err := os.RemoveAll(p); err != nil {
+			return errors.Wrapf(err, "Error removing %s", p)
+		}
+	}
+	if _, err := runCommand("systemctl", "daemon-reload"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// systemctl runs the command on the units, ignoring the units which are not
+// installed yet or anymore.
+func (d *Driver) systemctl(command string, units ...string) error {
+	if _, err := runCommand("systemctl", append([]string{command}, units...)...); err != nil && !strings.Contains(err.Error(), "not loaded") && !strings.Contains(err.Error(), "does not exist") {
+		return errors.Wrapf(err, "Error running systemctl %s", command)
+	}
+	return nil
+}
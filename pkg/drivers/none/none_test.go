@@ -0,0 +1,87 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package none
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+// fakeSystemctl answers the commands with the errors of their arguments, and
+// records them.
+type fakeSystemctl struct {
+	errors   map[string]string
+	commands []string
+}
+
+func (f *fakeSystemctl) run(name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(args, " ")
+	f.commands = append(f.commands, name+" "+cmd)
+	if msg, ok := f.errors[cmd]; ok {
+		return []byte(msg), fmt.Errorf("%s %s: %s", name, cmd, msg)
+	}
+	return nil, nil
+}
+
+func withFakeSystemctl(f *fakeSystemctl) func() {
+	orig := runCommand
+	runCommand = f.run
+	return func() { runCommand = orig }
+}
+
+func TestGetState(t *testing.T) {
+	f := &fakeSystemctl{errors: map[string]string{}}
+	defer withFakeSystemctl(f)()
+	d := NewDriver("minikube", "/tmp")
+
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Errorf("Expected Running for an active localkube, got %s, %v", s, err)
+	}
+	f.errors["is-active -q localkube"] = "inactive"
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped for an inactive localkube, got %s, %v", s, err)
+	}
+}
+
+func TestStopNotInstalled(t *testing.T) {
+	f := &fakeSystemctl{errors: map[string]string{
+		"stop localkube-watchdog.timer localkube": "Failed to stop localkube.service: Unit localkube.service not loaded.",
+	}}
+	defer withFakeSystemctl(f)()
+
+	if err := NewDriver("minikube", "/tmp").Stop(); err != nil {
+		t.Errorf("Expected stopping a machine without localkube to succeed: %s", err)
+	}
+
+	f.errors["stop localkube-watchdog.timer localkube"] = "Access denied"
+	if err := NewDriver("minikube", "/tmp").Stop(); err == nil {
+		t.Errorf("Expected an error when systemctl fails")
+	}
+}
+
+func TestPreCreateCheckNeedsRoot(t *testing.T) {
+	orig := geteuid
+	geteuid = func() int { return 1000 }
+	defer func() { geteuid = orig }()
+
+	if err := NewDriver("minikube", "/tmp").PreCreateCheck(); err == nil {
+		t.Errorf("Expected an error when not run as root")
+	}
+}
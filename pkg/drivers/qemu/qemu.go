@@ -0,0 +1,346 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package qemu is a libmachine driver running the minikube VM with qemu, for
+// the hosts without VirtualBox or another hypervisor minikube drives, like
+// Apple Silicon Macs or Linux machines without libvirt. qemu accelerates the
+// VM with the Hypervisor framework of macOS or KVM when the host runs x86_64,
+// as the ISO does, and emulates it otherwise.
+//
+// The VM is either on the user mode network of qemu, which needs no
+// privileges and forwards the ports of ssh, docker and the apiserver from
+// localhost, or on vmnet through socket_vmnet on macOS, where it gets an
+// address the host and the other VMs reach.
+package qemu
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/vmnet"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// DriverName is the name of the driver
+const DriverName = "qemu"
+
+// The networks of the VM
+const (
+	// NetworkUser is the user mode network of qemu, reached through the
+	// ports forwarded from localhost
+	NetworkUser = "user"
+	// NetworkSocketVMnet is vmnet, which socket_vmnet attaches the VM to
+	NetworkSocketVMnet = "socket_vmnet"
+)
+
+// Where the install of socket_vmnet puts its client and the socket of its
+// daemon
+const (
+	DefaultSocketVMnetClient = "/opt/socket_vmnet/bin/socket_vmnet_client"
+	DefaultSocketVMnetPath   = "/var/run/socket_vmnet"
+)
+
+// userNetworkHostIP is the address of the host on the user mode network
+const userNetworkHostIP = "10.0.2.2"
+
+// The files of the VM in the machine directory
+const (
+	isoFile     = "boot2docker.iso"
+	pidFile     = "qemu.pid"
+	monitorFile = "monitor"
+	consoleFile = "console.log"
+)
+
+// The monitor, ports and host of the driver, they are replaced in tests.
+var (
+	// monitorCommand runs the command in the monitor of qemu listening on
+	// the socket
+	monitorCommand = func(path, command string) error {
+		conn, err := net.DialTimeout("unix", path, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = fmt.Fprintf(conn, "%s\n", command)
+		return err
+	}
+	freePort = func() (int, error) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return 0, err
+		}
+		defer l.Close()
+		return l.Addr().(*net.TCPAddr).Port, nil
+	}
+	kvmAvailable = func() bool {
+		f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+		if err != nil {
+			return false
+		}
+		f.Close()
+		return true
+	}
+	goos   = runtime.GOOS
+	goarch = runtime.GOARCH
+)
+
+// Driver runs the VM as a qemu process, booting the ISO.
+type Driver struct {
+	*pkgdrivers.VMProcess
+
+	// Program is the qemu binary of the architecture of the ISO
+	Program string
+	// Network is NetworkUser or NetworkSocketVMnet
+	Network string
+	// SocketVMnetClient runs qemu connected to the daemon of socket_vmnet
+	// listening on SocketVMnetPath
+	SocketVMnetClient string
+	SocketVMnetPath   string
+}
+
+// NewDriver returns a driver for the VM of the machine.
+func NewDriver(machineName, storePath string) *Driver {
+	return &Driver{
+		VMProcess:         pkgdrivers.NewVMProcess(machineName, storePath, pidFile, "qemu-system"),
+		Program:           "qemu-system-x86_64",
+		Network:           NetworkUser,
+		SocketVMnetClient: DefaultSocketVMnetClient,
+		SocketVMnetPath:   DefaultSocketVMnetPath,
+	}
+}
+
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that qemu is installed, and socket_vmnet when the VM
+// is on vmnet.
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath(d.Program); err != nil {
+		return errors.Wrapf(err, "The qemu driver needs %s, which comes with qemu", d.Program)
+	}
+	switch d.Network {
+	case NetworkUser:
+		return nil
+	case NetworkSocketVMnet:
+		if goos != "darwin" {
+			return errors.Errorf("The %s network is only supported on macOS, use the %s network", NetworkSocketVMnet, NetworkUser)
+		}
+		if _, err := exec.LookPath(d.SocketVMnetClient); err != nil {
+			return errors.Wrapf(err, "The %s network needs socket_vmnet installed, see https://github.com/lima-vm/socket_vmnet", NetworkSocketVMnet)
+		}
+		if _, err := os.Stat(d.SocketVMnetPath); err != nil {
+			return errors.Wrap(err, "The daemon of socket_vmnet is not running")
+		}
+		return nil
+	}
+	return errors.Errorf("Unknown network %q of the qemu driver, use %s or %s", d.Network, NetworkUser, NetworkSocketVMnet)
+}
+
+// Create copies the ISO, makes the disk holding the key of the machine, and
+// starts the VM.
+func (d *Driver) Create() error {
+	b2dutils := mcnutils.NewB2dUtils(d.StorePath)
+	if err := b2dutils.CopyIsoToMachineDir(d.Boot2DockerURL, d.MachineName); err != nil {
+		return errors.Wrap(err, "Error copying the ISO")
+	}
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "Error generating the ssh key")
+	}
+	if err := pkgdrivers.CreateRawDisk(d.GetSSHKeyPath(), d.DiskPath(), d.DiskSize); err != nil {
+		return err
+	}
+
+	var err error
+	if d.MAC == "" {
+		if d.MAC, err = randomMAC(); err != nil {
+			return err
+		}
+	}
+	if d.Network == NetworkUser {
+		if d.SSHPort, err = freePort(); err != nil {
+			return errors.Wrap(err, "Error finding a port to forward ssh from")
+		}
+	}
+	return d.Start()
+}
+
+// randomMAC returns a random address in the range of qemu.
+func randomMAC() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Error generating a MAC address")
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", b[0], b[1], b[2]), nil
+}
+
+// accelerator returns the accelerator of qemu: the hypervisor of the host
+// when it runs x86_64, as the ISO does, and the emulation of tcg otherwise.
+func accelerator() string {
+	switch {
+	case goarch != "amd64":
+		return "tcg"
+	case goos == "darwin":
+		return "hvf"
+	case goos == "linux" && kvmAvailable():
+		return "kvm"
+	}
+	return "tcg"
+}
+
+// netdev returns the network backend of the VM. The user mode network
+// forwards the ports of ssh, the apiserver and docker from localhost; the
+// client of socket_vmnet passes its connection to the daemon as the fd 3.
+func (d *Driver) netdev() string {
+	if d.Network == NetworkSocketVMnet {
+		return "socket,id=net0,fd=3"
+	}
+	return fmt.Sprintf("user,id=net0,hostfwd=tcp:127.0.0.1:%d-:22,hostfwd=tcp:127.0.0.1:%[2]d-:%[2]d,hostfwd=tcp:127.0.0.1:%[3]d-:%[3]d",
+		d.SSHPort, constants.APIServerPort, constants.DockerDaemonPort)
+}
+
+// command returns the command running the VM, which qemu daemonizes once it
+// runs.
+func (d *Driver) command() (string, []string) {
+	accel := accelerator()
+	cpu := "host"
+	if accel == "tcg" {
+		cpu = "max"
+	}
+	args := []string{
+		"-name", d.MachineName,
+		"-machine", "q35,accel=" + accel,
+		"-cpu", cpu,
+		"-smp", strconv.Itoa(d.CPU),
+		"-m", fmt.Sprintf("%dM", d.Memory),
+		"-boot", "d",
+		"-cdrom", d.ResolveStorePath(isoFile),
+		"-drive", fmt.Sprintf("file=%s,format=raw,if=virtio", d.DiskPath()),
+		"-netdev", d.netdev(),
+		"-device", "virtio-net-pci,netdev=net0,mac=" + d.MAC,
+		"-device", "virtio-rng-pci",
+		"-serial", "file:" + d.ResolveStorePath(consoleFile),
+		"-display", "none",
+		"-monitor", fmt.Sprintf("unix:%s,server,nowait", d.ResolveStorePath(monitorFile)),
+		"-pidfile", d.PidPath(),
+		"-daemonize",
+	}
+	if d.Network == NetworkSocketVMnet {
+		return d.SocketVMnetClient, append([]string{d.SocketVMnetPath, d.Program}, args...)
+	}
+	return d.Program, args
+}
+
+// GetSSHHostname returns the address of the VM, or localhost which its port
+// of ssh is forwarded from on the user mode network.
+func (d *Driver) GetSSHHostname() (string, error) {
+	if d.Network == NetworkUser {
+		return "127.0.0.1", nil
+	}
+	return d.GetIP()
+}
+
+// GetIP returns the address vmnet leased to the VM, or localhost on the user
+// mode network.
+func (d *Driver) GetIP() (string, error) {
+	if d.Network != NetworkUser {
+		return d.VMProcess.GetIP()
+	}
+	s, err := d.GetState()
+	if err != nil {
+		return "", err
+	}
+	if s != state.Running {
+		return "", errors.New("The VM is not running")
+	}
+	return "127.0.0.1", nil
+}
+
+// HostIP returns the address the VM reaches the host at.
+func (d *Driver) HostIP() (string, error) {
+	if d.Network == NetworkUser {
+		return userNetworkHostIP, nil
+	}
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return vmnet.HostIP(ip)
+}
+
+// GetURL returns the address of docker at the address of GetIP, which the
+// one of VMProcess doesn't call.
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return pkgdrivers.DockerURL(ip), nil
+}
+
+// Start runs qemu, and on vmnet waits for the VM to get its address.
+func (d *Driver) Start() error {
+	if err := d.CheckStopped(); err != nil {
+		return err
+	}
+	name, args := d.command()
+	if _, err := pkgdrivers.RunCommand(name, args...); err != nil {
+		return errors.Wrapf(err, "Error running %s", d.Program)
+	}
+	if d.Network == NetworkUser {
+		return nil
+	}
+	return d.WaitForLease()
+}
+
+// Stop powers the VM off through the monitor of qemu, as the power button
+// would.
+func (d *Driver) Stop() error {
+	return d.StopWith(func(int) error {
+		return monitorCommand(d.ResolveStorePath(monitorFile), "system_powerdown")
+	})
+}
+
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+// Remove kills qemu if it is running. The disk and the ISO go with the
+// directory of the machine.
+func (d *Driver) Remove() error {
+	return d.Kill()
+}
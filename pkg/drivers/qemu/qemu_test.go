@@ -0,0 +1,240 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/vmnet"
+)
+
+const leases = `{
+	name=minikube
+	ip_address=192.168.105.3
+	hw_address=1,52:54:0:12:34:56
+	lease=0x5b000000
+}
+`
+
+func TestAccelerator(t *testing.T) {
+	origOS, origArch, origKVM := goos, goarch, kvmAvailable
+	defer func() { goos, goarch, kvmAvailable = origOS, origArch, origKVM }()
+	kvm := true
+	kvmAvailable = func() bool { return kvm }
+
+	for _, test := range []struct {
+		goos, goarch string
+		kvm          bool
+		expected     string
+	}{
+		{"darwin", "amd64", false, "hvf"},
+		{"darwin", "arm64", false, "tcg"},
+		{"linux", "amd64", true, "kvm"},
+		{"linux", "amd64", false, "tcg"},
+		{"linux", "arm64", true, "tcg"},
+	} {
+		goos, goarch, kvm = test.goos, test.goarch, test.kvm
+		if accel := accelerator(); accel != test.expected {
+			t.Errorf("Expected the accelerator %s on %s/%s, got %s", test.expected, test.goos, test.goarch, accel)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	d := NewDriver("minikube", "/home/user/.minikube")
+	d.MAC = "52:54:00:12:34:56"
+	d.SSHPort = 40022
+
+	name, args := d.command()
+	cmd := strings.Join(args, " ")
+	if name != "qemu-system-x86_64" || !strings.HasSuffix(cmd, "-daemonize") {
+		t.Errorf("Expected qemu to run daemonized, got %s %s", name, cmd)
+	}
+	for _, expected := range []string{
+		"hostfwd=tcp:127.0.0.1:40022-:22",
+		"hostfwd=tcp:127.0.0.1:8443-:8443",
+		"hostfwd=tcp:127.0.0.1:2376-:2376",
+		"virtio-net-pci,netdev=net0,mac=52:54:00:12:34:56",
+		"-pidfile /home/user/.minikube/machines/minikube/qemu.pid",
+	} {
+		if !strings.Contains(cmd, expected) {
+			t.Errorf("Expected %s in the arguments of qemu, got %s", expected, cmd)
+		}
+	}
+
+	d.Network = NetworkSocketVMnet
+	name, args = d.command()
+	if name != DefaultSocketVMnetClient || !reflect.DeepEqual(args[:2], []string{DefaultSocketVMnetPath, "qemu-system-x86_64"}) {
+		t.Errorf("Expected qemu to run with the client of socket_vmnet, got %s %v", name, args)
+	}
+	if cmd := strings.Join(args, " "); !strings.Contains(cmd, "-netdev socket,id=net0,fd=3") || strings.Contains(cmd, "hostfwd") {
+		t.Errorf("Expected the network of socket_vmnet, got %s", cmd)
+	}
+}
+
+func TestPreCreateCheck(t *testing.T) {
+	origOS := goos
+	defer func() { goos = origOS }()
+	d := NewDriver("minikube", "/tmp")
+	// The check of the network follows the one of qemu
+	d.Program = "sh"
+
+	d.Network = "bridge"
+	if err := d.PreCreateCheck(); err == nil || !strings.Contains(err.Error(), "Unknown network") {
+		t.Errorf("Expected an error for an unknown network, got %v", err)
+	}
+	goos, d.Network = "linux", NetworkSocketVMnet
+	if err := d.PreCreateCheck(); err == nil || !strings.Contains(err.Error(), "only supported on macOS") {
+		t.Errorf("Expected an error for socket_vmnet on Linux, got %v", err)
+	}
+	d.Network = NetworkUser
+	if err := d.PreCreateCheck(); err != nil {
+		t.Errorf("Unexpected error for the user mode network: %s", err)
+	}
+}
+
+// fakeHost runs the commands with their outputs, records them, the commands
+// of the monitor and the signals, and has qemu write its pid file when run.
+type fakeHost struct {
+	outputs  map[string]string
+	commands []string
+	monitor  []string
+	signals  map[int]syscall.Signal
+}
+
+func (f *fakeHost) run(name string, args ...string) (string, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, cmd)
+	for i, arg := range args {
+		if arg == "-pidfile" {
+			f.outputs["ps -p 42 -o command="] = "qemu-system-x86_64 -name minikube\n"
+			return "", ioutil.WriteFile(args[i+1], []byte("42\n"), 0644)
+		}
+	}
+	out, ok := f.outputs[cmd]
+	if !ok && name == "ps" {
+		return "", fmt.Errorf("exit status 1")
+	}
+	return out, nil
+}
+
+func (f *fakeHost) monitorCommand(path, command string) error {
+	f.monitor = append(f.monitor, command)
+	// The VM powers off
+	delete(f.outputs, "ps -p 42 -o command=")
+	return nil
+}
+
+func (f *fakeHost) signal(pid int, sig syscall.Signal) error {
+	f.signals[pid] = sig
+	return nil
+}
+
+func withFakeHost(t *testing.T, f *fakeHost) (string, func()) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "machines", "minikube"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "leases"), []byte(leases), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origRun, origMonitor, origSignal, origLeases := pkgdrivers.RunCommand, monitorCommand, pkgdrivers.Signal, vmnet.LeasesPath
+	pkgdrivers.RunCommand, monitorCommand, pkgdrivers.Signal = f.run, f.monitorCommand, f.signal
+	vmnet.LeasesPath = filepath.Join(dir, "leases")
+	return dir, func() {
+		pkgdrivers.RunCommand, monitorCommand, pkgdrivers.Signal, vmnet.LeasesPath = origRun, origMonitor, origSignal, origLeases
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	f := &fakeHost{outputs: map[string]string{}, signals: map[int]syscall.Signal{}}
+	dir, cleanup := withFakeHost(t, f)
+	defer cleanup()
+	d := NewDriver("minikube", dir)
+	d.MAC = "52:54:00:12:34:56"
+	d.Network = NetworkSocketVMnet
+
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped before the start, got %s, %v", s, err)
+	}
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the VM: %s", err)
+	}
+	if d.IPAddress != "192.168.105.3" {
+		t.Errorf("Expected the address leased to the MAC address of the VM, got %s", d.IPAddress)
+	}
+	if ip, err := d.HostIP(); err != nil || ip != "192.168.105.1" {
+		t.Errorf("Expected the host at 192.168.105.1, got %s, %v", ip, err)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Errorf("Expected Running, got %s, %v", s, err)
+	}
+	if err := d.Start(); err == nil {
+		t.Errorf("Expected an error starting a running VM")
+	}
+
+	if err := d.Stop(); err != nil {
+		t.Fatalf("Unexpected error stopping the VM: %s", err)
+	}
+	if !reflect.DeepEqual(f.monitor, []string{"system_powerdown"}) || len(f.signals) != 0 {
+		t.Errorf("Expected the VM to be powered off through the monitor, got %v, %v", f.monitor, f.signals)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped after the stop, got %s, %v", s, err)
+	}
+}
+
+func TestUserNetwork(t *testing.T) {
+	f := &fakeHost{outputs: map[string]string{}, signals: map[int]syscall.Signal{}}
+	dir, cleanup := withFakeHost(t, f)
+	defer cleanup()
+	d := NewDriver("minikube", dir)
+	d.SSHPort = 40022
+
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the VM: %s", err)
+	}
+	if ip, err := d.GetIP(); err != nil || ip != "127.0.0.1" {
+		t.Errorf("Expected the VM at localhost, got %s, %v", ip, err)
+	}
+	if port, err := d.GetSSHPort(); err != nil || port != 40022 {
+		t.Errorf("Expected ssh forwarded from the port 40022, got %d, %v", port, err)
+	}
+	if ip, err := d.HostIP(); err != nil || ip != "10.0.2.2" {
+		t.Errorf("Expected the host at 10.0.2.2, got %s, %v", ip, err)
+	}
+
+	if err := d.Remove(); err != nil {
+		t.Fatalf("Unexpected error removing the VM: %s", err)
+	}
+	if !reflect.DeepEqual(f.signals, map[int]syscall.Signal{42: syscall.SIGKILL}) {
+		t.Errorf("Expected qemu to be killed, got %v", f.signals)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ssh is a libmachine driver installing the cluster over ssh on a
+// Linux machine which exists already, like a spare machine of the local
+// network. minikube neither creates nor powers the machine: starting and
+// stopping it starts and stops localkube, and deleting it removes what start
+// installed.
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// DriverName is the name of the driver
+const DriverName = constants.DriverSSH
+
+// Driver manages localkube on the machine over ssh.
+type Driver struct {
+	*drivers.BaseDriver
+	// SSHKey is the private key of the user, copied to the machine directory
+	// when the machine is created
+	SSHKey string
+	// Stopped records that localkube was stopped, as the machine itself keeps
+	// running
+	Stopped bool
+}
+
+// NewDriver returns a driver for the machine at the address.
+func NewDriver(machineName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: machineName,
+			StorePath:   storePath,
+		},
+	}
+}
+
+// The ssh commands and connections of the driver, they are replaced in tests.
+var (
+	runSSHCommand = drivers.RunSSHCommandFromDriver
+	waitForSSH    = drivers.WaitForSSH
+	dial          = func(address string) error {
+		conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+)
+
+func (d *Driver) DriverName() string {
+	return DriverName
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return nil
+}
+
+func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
+	return nil
+}
+
+// PreCreateCheck checks that the address and the key of the machine were
+// given.
+func (d *Driver) PreCreateCheck() error {
+	if d.IPAddress == "" {
+		return errors.New("The ssh driver needs the address of the machine, set with --ssh-ip-address")
+	}
+	if d.SSHKey == "" {
+		return errors.New("The ssh driver needs the private key of the user, set with --ssh-key")
+	}
+	if _, err := os.Stat(d.SSHKey); err != nil {
+		return errors.Wrapf(err, "Error reading the ssh key %s", d.SSHKey)
+	}
+	return nil
+}
+
+// Create copies the key of the user to the machine directory, as the other
+// machines keep theirs, and waits for the machine to accept it. libmachine
+// then provisions docker on the machine.
+func (d *Driver) Create() error {
+	d.SSHKeyPath = d.ResolveStorePath("id_rsa")
+	if err := mcnutils.CopyFile(d.SSHKey, d.SSHKeyPath); err != nil {
+		return errors.Wrapf(err, "Error copying the ssh key %s", d.SSHKey)
+	}
+	// ssh refuses the keys other users can read
+	if err := os.Chmod(d.SSHKeyPath, 0600); err != nil {
+		return errors.Wrap(err, "Error setting the permissions of the ssh key")
+	}
+	if err := waitForSSH(d); err != nil {
+		return errors.Wrapf(err, "Error connecting to %s@%s", d.GetSSHUsername(), d.IPAddress)
+	}
+	return nil
+}
+
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(constants.DockerDaemonPort))), nil
+}
+
+// GetState returns Stopped once localkube was stopped, or the machine can't
+// be reached, and Running otherwise.
+func (d *Driver) GetState() (state.State, error) {
+	if d.Stopped {
+		return state.Stopped, nil
+	}
+	if err := d.reachable(); err != nil {
+		glog.Infoln(err)
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+// Start checks that the machine can be reached, start starts localkube once
+// it is updated.
+func (d *Driver) Start() error {
+	if err := d.reachable(); err != nil {
+		return errors.Wrap(err, "minikube can't power on the machine of the ssh driver")
+	}
+	d.Stopped = false
+	return nil
+}
+
+// Stop stops localkube and its watchdog.
+func (d *Driver) Stop() error {
+	if err := d.systemctl("stop", "localkube-watchdog.timer", "localkube"); err != nil {
+		return err
+	}
+	d.Stopped = true
+	return nil
+}
+
+func (d *Driver) Restart() error {
+	d.Stopped = false
+	return d.systemctl("restart", "localkube")
+}
+
+func (d *Driver) Kill() error {
+	if err := d.systemctl("kill", "localkube"); err != nil {
+		return err
+	}
+	d.Stopped = true
+	return nil
+}
+
+// Remove stops localkube and deletes the files start installed, with the
+// state of the cluster. A machine which can't be reached is left as it is,
+// for minikube delete to forget it.
+func (d *Driver) Remove() error {
+	if err := d.reachable(); err != nil {
+		glog.Warningf("Leaving the files of the cluster on the machine: %s", err)
+		return nil
+	}
+	if err := d.systemctl("disable", "--now", "localkube-watchdog.timer", "localkube"); err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("sudo rm -rf %s && sudo systemctl daemon-reload", strings.Join(constants.LocalkubeInstalledPaths, " "))
+	if _, err := runSSHCommand(d, cmd); err != nil {
+		return errors.Wrap(err, "Error removing the files of the cluster")
+	}
+	return nil
+}
+
+// reachable returns an error when sshd of the machine doesn't accept
+// connections.
+func (d *Driver) reachable() error {
+	ip, err := d.GetIP()
+	if err != nil {
+		return err
+	}
+	port, err := d.GetSSHPort()
+	if err != nil {
+		return err
+	}
+	address := net.JoinHostPort(ip, strconv.Itoa(port))
+	if err := dial(address); err != nil {
+		return errors.Wrapf(err, "The machine %s can't be reached", address)
+	}
+	return nil
+}
+
+// systemctl runs the command on the units of the machine, ignoring the units
+// which are not installed yet or anymore.
+func (d *Driver) systemctl(command string, units ...string) error {
+	cmd := fmt.Sprintf("sudo systemctl %s %s", command, strings.Join(units, " "))
+	if _, err := runSSHCommand(d, cmd); err != nil && !strings.Contains(err.Error(), "not loaded") && !strings.Contains(err.Error(), "does not exist") {
+		return errors.Wrapf(err, "Error running systemctl %s", command)
+	}
+	return nil
+}
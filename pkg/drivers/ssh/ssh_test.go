@@ -0,0 +1,170 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/state"
+)
+
+// fakeMachine answers the ssh commands with their errors, records them, and
+// is reachable unless down.
+type fakeMachine struct {
+	errors   map[string]string
+	commands []string
+	down     bool
+}
+
+func (f *fakeMachine) run(d drivers.Driver, cmd string) (string, error) {
+	f.commands = append(f.commands, cmd)
+	if msg, ok := f.errors[cmd]; ok {
+		return "", fmt.Errorf("%s: %s", cmd, msg)
+	}
+	return "", nil
+}
+
+func (f *fakeMachine) dial(address string) error {
+	if f.down {
+		return fmt.Errorf("dial tcp %s: connection refused", address)
+	}
+	return nil
+}
+
+func withFakeMachine(f *fakeMachine) func() {
+	origRun, origDial := runSSHCommand, dial
+	runSSHCommand, dial = f.run, f.dial
+	return func() { runSSHCommand, dial = origRun, origDial }
+}
+
+func newTestDriver() *Driver {
+	d := NewDriver("minikube", "/tmp")
+	d.IPAddress = "192.168.1.20"
+	return d
+}
+
+func TestGetState(t *testing.T) {
+	f := &fakeMachine{errors: map[string]string{}}
+	defer withFakeMachine(f)()
+	d := newTestDriver()
+
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Errorf("Expected Running for a reachable machine, got %s, %v", s, err)
+	}
+	if err := d.Stop(); err != nil {
+		t.Fatalf("Unexpected error stopping the machine: %s", err)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped once localkube is stopped, got %s, %v", s, err)
+	}
+	if err := d.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the machine: %s", err)
+	}
+	f.down = true
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Errorf("Expected Stopped for an unreachable machine, got %s, %v", s, err)
+	}
+	if err := d.Start(); err == nil {
+		t.Errorf("Expected an error starting an unreachable machine")
+	}
+}
+
+func TestStopNotInstalled(t *testing.T) {
+	f := &fakeMachine{errors: map[string]string{
+		"sudo systemctl stop localkube-watchdog.timer localkube": "Failed to stop localkube.service: Unit localkube.service not loaded.",
+	}}
+	defer withFakeMachine(f)()
+
+	if err := newTestDriver().Stop(); err != nil {
+		t.Errorf("Expected stopping a machine without localkube to succeed: %s", err)
+	}
+
+	f.errors["sudo systemctl stop localkube-watchdog.timer localkube"] = "sudo: a password is required"
+	if err := newTestDriver().Stop(); err == nil {
+		t.Errorf("Expected an error when systemctl fails")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	f := &fakeMachine{errors: map[string]string{}}
+	defer withFakeMachine(f)()
+
+	if err := newTestDriver().Remove(); err != nil {
+		t.Fatalf("Unexpected error removing the machine: %s", err)
+	}
+	if len(f.commands) != 2 || !strings.Contains(f.commands[1], "sudo rm -rf /usr/local/bin/localkube") {
+		t.Errorf("Expected localkube to be disabled and its files removed, got %v", f.commands)
+	}
+
+	f.commands = nil
+	f.down = true
+	if err := newTestDriver().Remove(); err != nil {
+		t.Errorf("Expected an unreachable machine to be forgotten, got %s", err)
+	}
+	if len(f.commands) != 0 {
+		t.Errorf("Expected no command on an unreachable machine, got %v", f.commands)
+	}
+}
+
+func TestCreateCopiesKey(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	key := filepath.Join(tempDir, "key")
+	if err := ioutil.WriteFile(key, []byte("private key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "machines", "minikube"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	orig := waitForSSH
+	waitForSSH = func(drivers.Driver) error { return nil }
+	defer func() { waitForSSH = orig }()
+
+	d := NewDriver("minikube", tempDir)
+	d.IPAddress = "192.168.1.20"
+	d.SSHKey = key
+	if err := d.PreCreateCheck(); err != nil {
+		t.Fatalf("Unexpected error checking the machine: %s", err)
+	}
+	if err := d.Create(); err != nil {
+		t.Fatalf("Unexpected error creating the machine: %s", err)
+	}
+	info, err := os.Stat(d.GetSSHKeyPath())
+	if err != nil {
+		t.Fatalf("Expected the key to be copied: %s", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the key to be readable by the user only, got %s", info.Mode())
+	}
+}
+
+func TestPreCreateCheckNeedsAddress(t *testing.T) {
+	d := NewDriver("minikube", "/tmp")
+	d.SSHKey = "/tmp/key"
+	if err := d.PreCreateCheck(); err == nil {
+		t.Errorf("Expected an error without the address of the machine")
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/vmnet"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// The user the minikube ISO lets in with the key of the machine
+const sshUser = "docker"
+
+// How long a VM process waits for the VM to get an address, and to power off
+var (
+	IPAttempts    = 60
+	StopAttempts  = 30
+	RetryInterval = 2 * time.Second
+)
+
+// The commands and processes of the drivers, they are replaced in tests.
+var (
+	// RunCommand runs the command, the error has its output
+	RunCommand = func(name string, args ...string) (string, error) {
+		out, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return string(out), errors.Wrapf(err, "%s %s: %s", name, strings.Join(args, " "), strings.TrimSpace(string(out)))
+		}
+		return string(out), nil
+	}
+	Signal = func(pid int, sig syscall.Signal) error {
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return p.Signal(sig)
+	}
+)
+
+// VMProcess is a VM the driver runs as a process of the host, which writes
+// its pid in a file of the machine directory, and which is on vmnet unless
+// the driver says otherwise. The hyperkit and qemu drivers embed it, and add
+// the command line of the VM and how to power it off.
+type VMProcess struct {
+	*drivers.BaseDriver
+
+	Memory int
+	CPU    int
+	// DiskSize is the size of the disk in MB
+	DiskSize       int
+	Boot2DockerURL string
+	// MAC is the address of the VM on vmnet, which finds its lease
+	MAC string
+
+	// pidFile is the name of the pid file in the machine directory, and
+	// program is in the command of the process, unlike a reused pid
+	pidFile string
+	program string
+}
+
+// NewVMProcess returns the VM of the machine, run by program writing its pid
+// into pidFile.
+func NewVMProcess(machineName, storePath, pidFile, program string) *VMProcess {
+	return &VMProcess{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: machineName,
+			StorePath:   storePath,
+			SSHUser:     sshUser,
+		},
+		Memory:  constants.DefaultMemory,
+		CPU:     constants.DefaultCPUS,
+		pidFile: pidFile,
+		program: program,
+	}
+}
+
+// DiskPath returns the raw disk of the VM.
+func (v *VMProcess) DiskPath() string {
+	return v.ResolveStorePath(v.MachineName + ".rawdisk")
+}
+
+// PidPath returns the pid file the process of the VM writes.
+func (v *VMProcess) PidPath() string {
+	return v.ResolveStorePath(v.pidFile)
+}
+
+// Pid returns the process of the VM, or 0 if the VM isn't running. A pid
+// file left by a VM which crashed, or whose pid was reused, is ignored.
+func (v *VMProcess) Pid() (int, error) {
+	data, err := ioutil.ReadFile(v.PidPath())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "Error reading the pid file of the VM")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, errors.Wrap(err, "Invalid pid file of the VM")
+	}
+	out, err := RunCommand("ps", "-p", strconv.Itoa(pid), "-o", "command=")
+	if err != nil || !strings.Contains(out, v.program) {
+		return 0, nil
+	}
+	return pid, nil
+}
+
+func (v *VMProcess) GetSSHHostname() (string, error) {
+	return v.GetIP()
+}
+
+// GetIP returns the address vmnet leased to the VM.
+func (v *VMProcess) GetIP() (string, error) {
+	s, err := v.GetState()
+	if err != nil {
+		return "", err
+	}
+	if s != state.Running {
+		return "", errors.New("The VM is not running")
+	}
+	if v.IPAddress != "" {
+		return v.IPAddress, nil
+	}
+	l, err := vmnet.ReadLease(v.MachineName, v.MAC)
+	if err != nil {
+		return "", err
+	}
+	return l.IP, nil
+}
+
+func (v *VMProcess) GetURL() (string, error) {
+	ip, err := v.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return DockerURL(ip), nil
+}
+
+// DockerURL returns the URL of the docker daemon of the VM at the address.
+func DockerURL(ip string) string {
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(constants.DockerDaemonPort)))
+}
+
+func (v *VMProcess) GetState() (state.State, error) {
+	pid, err := v.Pid()
+	if err != nil {
+		return state.Error, err
+	}
+	if pid == 0 {
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+// CheckStopped returns an error if the VM is already running, and otherwise
+// removes the pid file of its last run, before the driver starts it.
+func (v *VMProcess) CheckStopped() error {
+	pid, err := v.Pid()
+	if err != nil {
+		return err
+	}
+	if pid != 0 {
+		return errors.Errorf("The VM is already running as the process %d", pid)
+	}
+	os.Remove(v.PidPath())
+	return nil
+}
+
+// WaitForLease waits for the VM to get its address on vmnet, once the driver
+// started it.
+func (v *VMProcess) WaitForLease() error {
+	// The address of the last start may have been leased to another VM
+	v.IPAddress = ""
+	for i := 0; ; i++ {
+		l, err := vmnet.ReadLease(v.MachineName, v.MAC)
+		if err == nil {
+			v.IPAddress, v.MAC = l.IP, l.MAC
+			return nil
+		}
+		if i == IPAttempts {
+			return errors.Wrap(err, "The VM didn't get an address on vmnet")
+		}
+		glog.Infof("Waiting for the VM to get an address: %s", err)
+		time.Sleep(RetryInterval)
+	}
+}
+
+// StopWith powers the VM off with powerOff, and waits for its process to
+// exit.
+func (v *VMProcess) StopWith(powerOff func(pid int) error) error {
+	pid, err := v.Pid()
+	if err != nil || pid == 0 {
+		return err
+	}
+	if err := powerOff(pid); err != nil {
+		return errors.Wrap(err, "Error stopping the VM")
+	}
+	for i := 0; i < StopAttempts; i++ {
+		if pid, err := v.Pid(); err != nil || pid == 0 {
+			return err
+		}
+		time.Sleep(RetryInterval)
+	}
+	return errors.New("The VM didn't power off, kill it with minikube delete")
+}
+
+// Kill kills the process of the VM.
+func (v *VMProcess) Kill() error {
+	pid, err := v.Pid()
+	if err != nil || pid == 0 {
+		return err
+	}
+	return Signal(pid, syscall.SIGKILL)
+}
+
+// Restart stops the VM of the driver, then starts it.
+func Restart(d drivers.Driver) error {
+	if err := d.Stop(); err != nil {
+		return err
+	}
+	return d.Start()
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vmnet reads the addresses macOS gives the VMs on vmnet, the
+// network of the Hypervisor framework, which the hyperkit driver and the
+// qemu driver with socket_vmnet attach their VMs to.
+package vmnet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LeasesPath is the file of the DHCP server of macOS, which gives the VMs
+// their addresses on vmnet. It is replaced in tests.
+var LeasesPath = "/var/db/dhcpd_leases"
+
+// Lease is an address the DHCP server leased.
+type Lease struct {
+	Name   string
+	IP     string
+	MAC    string
+	Expiry int64
+}
+
+// ParseLeases returns the leases of the file, blocks of key=value lines in
+// braces.
+func ParseLeases(data string) []Lease {
+	var leases []Lease
+	var l *Lease
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "{":
+			l = &Lease{}
+		case line == "}":
+			if l != nil {
+				leases = append(leases, *l)
+			}
+			l = nil
+		case l != nil:
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "name":
+				l.Name = kv[1]
+			case "ip_address":
+				l.IP = kv[1]
+			case "hw_address":
+				// The hardware type precedes the address, 1 for ethernet
+				l.MAC = NormalizeMAC(kv[1][strings.Index(kv[1], ",")+1:])
+			case "lease":
+				l.Expiry, _ = strconv.ParseInt(strings.TrimPrefix(kv[1], "0x"), 16, 64)
+			}
+		}
+	}
+	return leases
+}
+
+// NormalizeMAC returns the address with two digits per byte, as the leases
+// file drops the leading zeros.
+func NormalizeMAC(mac string) string {
+	parts := strings.Split(strings.ToLower(mac), ":")
+	for i, p := range parts {
+		if len(p) == 1 {
+			parts[i] = "0" + p
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// FindLease returns the lease of the MAC address, or before vmnet's address
+// of the VM is known, the latest lease of the host name the ISO sends, which
+// is the name of the machine.
+func FindLease(leases []Lease, name, mac string) (Lease, bool) {
+	var found Lease
+	ok := false
+	for _, l := range leases {
+		if mac != "" {
+			if l.MAC == NormalizeMAC(mac) {
+				return l, true
+			}
+			continue
+		}
+		if l.Name == name && (!ok || l.Expiry > found.Expiry) {
+			found, ok = l, true
+		}
+	}
+	return found, ok
+}
+
+// ReadLease returns the lease of the VM from the leases file.
+func ReadLease(name, mac string) (Lease, error) {
+	data, err := ioutil.ReadFile(LeasesPath)
+	if err != nil {
+		return Lease{}, errors.Wrapf(err, "Error reading the DHCP leases %s", LeasesPath)
+	}
+	l, ok := FindLease(ParseLeases(string(data)), name, mac)
+	if !ok {
+		return Lease{}, fmt.Errorf("The VM has no address on vmnet yet")
+	}
+	return l, nil
+}
+
+// HostIP returns the address of the host on vmnet, the first address of the
+// network of the VM.
+func HostIP(vmIP string) (string, error) {
+	ip := net.ParseIP(vmIP).To4()
+	if ip == nil {
+		return "", errors.Errorf("Invalid address of the VM %q", vmIP)
+	}
+	return net.IPv4(ip[0], ip[1], ip[2], 1).String(), nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmnet
+
+import "testing"
+
+const leases = `{
+	name=minikube
+	ip_address=192.168.64.2
+	hw_address=1,a:b2:3:d4:e5:f6
+	identifier=1,a:b2:3:d4:e5:f6
+	lease=0x5a000000
+}
+{
+	name=minikube
+	ip_address=192.168.64.5
+	hw_address=1,12:34:56:78:9a:bc
+	identifier=1,12:34:56:78:9a:bc
+	lease=0x5b000000
+}
+{
+	name=other
+	ip_address=192.168.64.3
+	hw_address=1,de:ad:be:ef:0:1
+	lease=0x5c000000
+}
+`
+
+func TestFindLease(t *testing.T) {
+	l := ParseLeases(leases)
+	if len(l) != 3 {
+		t.Fatalf("Expected 3 leases, got %v", l)
+	}
+	if found, ok := FindLease(l, "minikube", ""); !ok || found.IP != "192.168.64.5" {
+		t.Errorf("Expected the latest lease of the name, got %v", found)
+	}
+	if found, ok := FindLease(l, "minikube", "0a:b2:03:d4:e5:f6"); !ok || found.IP != "192.168.64.2" {
+		t.Errorf("Expected the lease of the MAC address, got %v", found)
+	}
+	if _, ok := FindLease(l, "minikube", "00:00:00:00:00:01"); ok {
+		t.Errorf("Expected no lease for an unknown MAC address")
+	}
+}
+
+func TestHostIP(t *testing.T) {
+	if ip, err := HostIP("192.168.64.5"); err != nil || ip != "192.168.64.1" {
+		t.Errorf("Expected 192.168.64.1, got %s, %v", ip, err)
+	}
+	if _, err := HostIP("minikube"); err == nil {
+		t.Errorf("Expected an error for an invalid address")
+	}
+}
@@ -55,6 +55,17 @@ func StartAPIServer(lk LocalkubeServer) func() error {
 
 	config.GenericServerRunOptions.RuntimeConfig = lk.RuntimeConfig
 
+	// The pod security policy created by localkube defaults the seccomp profile
+	if lk.SeccompDefault {
+		config.GenericServerRunOptions.AdmissionControl += ",PodSecurityPolicy"
+		runtimeConfig := map[string]string{}
+		for k, v := range lk.RuntimeConfig {
+			runtimeConfig[k] = v
+		}
+		runtimeConfig["extensions/v1beta1/podsecuritypolicy"] = "true"
+		config.GenericServerRunOptions.RuntimeConfig = runtimeConfig
+	}
+
 	lk.SetExtraConfigForComponent("apiserver", &config)
 
 	return func() error {
@@ -40,6 +40,7 @@ func StartControllerManagerServer(lk LocalkubeServer) func() error {
 	config.VolumeConfiguration.EnableDynamicProvisioning = true
 	config.ServiceAccountKeyFile = lk.GetPrivateKeyCertPath()
 	config.RootCAFile = lk.GetCAPublicKeyCertPath()
+	config.ClusterCIDR = lk.PodNetworkCIDR
 
 	lk.SetExtraConfigForComponent("controller-manager", &config)
 
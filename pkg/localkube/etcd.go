@@ -21,16 +21,25 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/coreos/etcd/client"
 	"github.com/coreos/etcd/etcdserver"
 	"github.com/coreos/etcd/etcdserver/api/v2http"
 	"github.com/coreos/etcd/pkg/transport"
 	"github.com/coreos/etcd/pkg/types"
+	"github.com/coreos/etcd/wal"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"k8s.io/minikube/pkg/util"
 )
 
 const (
 	EtcdName = "etcd"
+
+	etcdJoinTimeout = 30 * time.Second
 )
 
 var (
@@ -46,6 +55,19 @@ type EtcdServer struct {
 	*etcdserver.EtcdServer
 	config          *etcdserver.ServerConfig
 	clientListeners []net.Listener
+	// peerListeners are only created for a cluster of several members
+	peerListeners []net.Listener
+	servePeers    bool
+}
+
+// GetEtcdName returns the name of the etcd member, which is unique in the
+// cluster as the additional control planes are named after their peer URL.
+func (lk LocalkubeServer) GetEtcdName() string {
+	if !lk.IsJoiningControlPlane() {
+		return "kubeetcd"
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(lk.EtcdPeerURL, "http://"), "https://")
+	return "kubeetcd-" + strings.NewReplacer(".", "-", ":", "-").Replace(name)
 }
 
 // NewEtcd creates a new default etcd Server using 'dataDir' for persistence. Panics if could not be configured.
@@ -80,13 +102,78 @@ func (lk LocalkubeServer) NewEtcd(clientURLStrs, peerURLStrs []string, name, dat
 		ElectionTicks: 10,
 	}
 
+	// The member is only added to the cluster the first time it starts,
+	// afterwards it finds the cluster in its data directory
+	if lk.IsJoiningControlPlane() && !wal.Exist(config.WALDir()) {
+		var urlsMap types.URLsMap
+		join := func() (err error) {
+			urlsMap, err = joinEtcdCluster(lk.EtcdJoin, name, peerURLStrs[0])
+			return err
+		}
+		// The member joining before may still be starting
+		if err := util.RetryAfter(20, join, 3*time.Second); err != nil {
+			return nil, err
+		}
+		config.InitialPeerURLsMap = urlsMap
+		config.NewCluster = false
+	}
+
 	lk.SetExtraConfigForComponent(EtcdName, &config)
 
 	return &EtcdServer{
-		config: config,
+		config:     config,
+		servePeers: lk.EtcdPeerURL != "",
 	}, nil
 }
 
+// joinEtcdCluster adds a member advertising peerURL to the etcd cluster at
+// the client URL endpoint, and returns the peer URLs of all its members.
+// Adding the member again, e.g. after a failed start, is not an error.
+func joinEtcdCluster(endpoint, name, peerURL string) (types.URLsMap, error) {
+	c, err := client.New(client.Config{Endpoints: []string{endpoint}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating etcd client")
+	}
+	members := client.NewMembersAPI(c)
+	ctx, cancel := context.WithTimeout(context.Background(), etcdJoinTimeout)
+	defer cancel()
+
+	_, addErr := members.Add(ctx, peerURL)
+	list, err := members.List(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing the members of the etcd cluster at %s", endpoint)
+	}
+	return clusterPeerURLs(list, name, peerURL, addErr)
+}
+
+// clusterPeerURLs maps the names of the members to their peer URLs, the
+// member which has not started yet being the joining one.
+func clusterPeerURLs(members []client.Member, name, peerURL string, addErr error) (types.URLsMap, error) {
+	urlsMap := types.URLsMap{}
+	added := false
+	for _, m := range members {
+		memberName := m.Name
+		for _, u := range m.PeerURLs {
+			if u == peerURL {
+				memberName = name
+				added = true
+			}
+		}
+		if memberName == "" {
+			return nil, fmt.Errorf("etcd member %s has not started yet, the members need to join one at a time", m.ID)
+		}
+		urls, err := types.NewURLs(m.PeerURLs)
+		if err != nil {
+			return nil, err
+		}
+		urlsMap[memberName] = urls
+	}
+	if !added {
+		return nil, errors.Wrapf(addErr, "Error adding %s to the etcd cluster", peerURL)
+	}
+	return urlsMap, nil
+}
+
 // Starts starts the etcd server and listening for client connections
 func (e *EtcdServer) Start() {
 	var err error
@@ -98,6 +185,9 @@ func (e *EtcdServer) Start() {
 
 	// create client listeners
 	e.clientListeners = createListenersOrPanic(e.config.ClientURLs)
+	if e.servePeers {
+		e.peerListeners = createListenersOrPanic(listenURLs(e.config.PeerURLs))
+	}
 
 	// start etcd
 	e.EtcdServer.Start()
@@ -113,6 +203,18 @@ func (e *EtcdServer) Start() {
 			panic(srv.Serve(l))
 		}(l)
 	}
+
+	// setup peer listeners
+	ph := v2http.NewPeerHandler(e.EtcdServer)
+	for _, l := range e.peerListeners {
+		go func(l net.Listener) {
+			srv := &http.Server{
+				Handler:     ph,
+				ReadTimeout: 5 * time.Minute,
+			}
+			panic(srv.Serve(l))
+		}(l)
+	}
 }
 
 // Stop closes all connections and stops the Etcd server
@@ -124,6 +226,9 @@ func (e *EtcdServer) Stop() {
 	for _, l := range e.clientListeners {
 		l.Close()
 	}
+	for _, l := range e.peerListeners {
+		l.Close()
+	}
 }
 
 // Name returns the servers unique name
@@ -136,6 +241,21 @@ func (e *EtcdServer) requestTimeout() time.Duration {
 	return 5*time.Second + 2*time.Duration(e.config.ElectionTicks)*time.Duration(e.config.TickMs)*time.Millisecond
 }
 
+// listenURLs returns the URLs on all the interfaces with the ports of the
+// advertised urls.
+func listenURLs(urls types.URLs) types.URLs {
+	var listen types.URLs
+	for _, u := range urls {
+		l := u
+		_, port, err := net.SplitHostPort(u.Host)
+		if err == nil {
+			l.Host = net.JoinHostPort("0.0.0.0", port)
+		}
+		listen = append(listen, l)
+	}
+	return listen
+}
+
 func createListenersOrPanic(urls types.URLs) (listeners []net.Listener) {
 	for _, url := range urls {
 		l, err := net.Listen("tcp", url.Host)
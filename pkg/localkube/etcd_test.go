@@ -0,0 +1,103 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coreos/etcd/client"
+)
+
+func TestClusterPeerURLs(t *testing.T) {
+	primary := client.Member{ID: "1", Name: "kubeetcd", PeerURLs: []string{"http://192.168.99.100:2380"}}
+	joining := client.Member{ID: "2", PeerURLs: []string{"http://192.168.99.101:2380"}}
+	other := client.Member{ID: "3", PeerURLs: []string{"http://192.168.99.102:2380"}}
+	addErr := errors.New("member exists")
+
+	var tests = []struct {
+		description string
+		members     []client.Member
+		addErr      error
+		expected    map[string]string
+		shouldErr   bool
+	}{
+		{
+			description: "added",
+			members:     []client.Member{primary, joining},
+			expected: map[string]string{
+				"kubeetcd":     "http://192.168.99.100:2380",
+				"kubeetcd-new": "http://192.168.99.101:2380",
+			},
+		},
+		{
+			description: "added by a previous start",
+			members:     []client.Member{primary, joining},
+			addErr:      addErr,
+			expected: map[string]string{
+				"kubeetcd":     "http://192.168.99.100:2380",
+				"kubeetcd-new": "http://192.168.99.101:2380",
+			},
+		},
+		{
+			description: "not added",
+			members:     []client.Member{primary},
+			addErr:      addErr,
+			shouldErr:   true,
+		},
+		{
+			description: "another member is joining",
+			members:     []client.Member{primary, joining, other},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		urlsMap, err := clusterPeerURLs(test.members, "kubeetcd-new", "http://192.168.99.101:2380", test.addErr)
+		if err != nil && !test.shouldErr {
+			t.Errorf("%s: Unexpected error: %s", test.description, err)
+			continue
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("%s: Expected error, got none", test.description)
+			continue
+		}
+		if test.shouldErr {
+			continue
+		}
+		if len(urlsMap) != len(test.expected) {
+			t.Errorf("%s: Expected %v, got %v", test.description, test.expected, urlsMap)
+		}
+		for name, url := range test.expected {
+			if urlsMap[name].String() != url {
+				t.Errorf("%s: Expected %s for %s, got %s", test.description, url, name, urlsMap[name])
+			}
+		}
+	}
+}
+
+func TestGetEtcdName(t *testing.T) {
+	lk := LocalkubeServer{EtcdPeerURL: "http://192.168.99.100:2380"}
+	if name := lk.GetEtcdName(); name != "kubeetcd" {
+		t.Errorf("Expected kubeetcd for the first control plane, got %s", name)
+	}
+	lk.EtcdJoin = "http://192.168.99.100:2379"
+	lk.EtcdPeerURL = "http://192.168.99.101:2380"
+	if name := lk.GetEtcdName(); name != "kubeetcd-192-168-99-101-2380" {
+		t.Errorf("Expected kubeetcd-192-168-99-101-2380, got %s", name)
+	}
+}
@@ -30,18 +30,27 @@ func StartKubeletServer(lk LocalkubeServer) func() error {
 
 	// Master details
 	config.APIServerList = []string{lk.GetAPIServerInsecureURL()}
+	if lk.IsWorker() {
+		config.APIServerList = []string{lk.Master}
+		config.KubeConfig.Set(lk.GetWorkerKubeconfigPath())
+		config.RequireKubeConfig = true
+	}
 
 	// Set containerized based on the flag
 	config.Containerized = lk.Containerized
 
 	config.AllowPrivileged = true
 	config.PodManifestPath = "/etc/kubernetes/manifests"
+	config.SeccompProfileRoot = lk.GetSeccompProfileRoot()
 
 	// Networking
 	config.ClusterDomain = lk.DNSDomain
 	config.ClusterDNS = lk.DNSIP.String()
 	// For kubenet plugin.
 	config.PodCIDR = "10.180.1.0/24"
+	if lk.PodNetworkCIDR != "" {
+		config.PodCIDR = lk.PodNetworkCIDR
+	}
 
 	config.NodeIP = lk.NodeIP.String()
 
@@ -46,6 +46,7 @@ type LocalkubeServer struct {
 	DNSIP                    net.IP
 	LocalkubeDirectory       string
 	ServiceClusterIPRange    net.IPNet
+	PodNetworkCIDR           string
 	APIServerAddress         net.IP
 	APIServerPort            int
 	APIServerInsecureAddress net.IP
@@ -60,6 +61,11 @@ type LocalkubeServer struct {
 	NetworkPlugin            string
 	FeatureGates             string
 	ExtraConfig              util.ExtraOptionSlice
+	Master                   string
+	EtcdPeerURL              string
+	EtcdJoin                 string
+	CryptoMode               string
+	SeccompDefault           bool
 }
 
 func (lk *LocalkubeServer) AddServer(server Server) {
@@ -74,6 +80,12 @@ func (lk LocalkubeServer) GetDNSDataDirectory() string {
 	return path.Join(lk.LocalkubeDirectory, "dns")
 }
 
+// GetSeccompProfileRoot returns the directory of the seccomp profiles which
+// pods can load with the localhost/<profile> annotation.
+func (lk LocalkubeServer) GetSeccompProfileRoot() string {
+	return path.Join(lk.LocalkubeDirectory, "seccomp")
+}
+
 func (lk LocalkubeServer) GetCertificateDirectory() string {
 	return path.Join(lk.LocalkubeDirectory, "certs")
 }
@@ -90,6 +102,18 @@ func (lk LocalkubeServer) GetCAPublicKeyCertPath() string {
 	return path.Join(lk.GetCertificateDirectory(), "ca.crt")
 }
 
+// IsWorker reports whether localkube joins the cluster of another
+// localkube, only running the kubelet and the proxy.
+func (lk LocalkubeServer) IsWorker() bool {
+	return lk.Master != ""
+}
+
+// IsJoiningControlPlane reports whether localkube is an additional control
+// plane, joining the etcd cluster of another localkube.
+func (lk LocalkubeServer) IsJoiningControlPlane() bool {
+	return lk.EtcdJoin != ""
+}
+
 func (lk LocalkubeServer) GetAPIServerSecureURL() string {
 	return fmt.Sprintf("https://%s:%d", lk.APIServerAddress.String(), lk.APIServerPort)
 }
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
 	"k8s.io/minikube/pkg/minikube/tests"
 )
 
@@ -121,3 +122,30 @@ func TestShouldNotGenerateCerts(t *testing.T) {
 		t.Fatalf("IPs match, we should not generate.")
 	}
 }
+
+func TestWriteWorkerKubeconfig(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	lk := LocalkubeServer{
+		LocalkubeDirectory: tempDir,
+		Master:             "https://192.168.99.100:8443",
+	}
+	if err := lk.WriteWorkerKubeconfig(); err != nil {
+		t.Fatalf("Unexpected error writing the kubeconfig: %s", err)
+	}
+	cfg, err := clientcmd.LoadFromFile(lk.GetWorkerKubeconfigPath())
+	if err != nil {
+		t.Fatalf("Error loading the kubeconfig: %s", err)
+	}
+	cluster := cfg.Clusters[cfg.Contexts[cfg.CurrentContext].Cluster]
+	if cluster.Server != lk.Master {
+		t.Errorf("Expected server %s, got %s", lk.Master, cluster.Server)
+	}
+	if cluster.CertificateAuthority != lk.GetCAPublicKeyCertPath() {
+		t.Errorf("Expected CA %s, got %s", lk.GetCAPublicKeyCertPath(), cluster.CertificateAuthority)
+	}
+	if user := cfg.AuthInfos[cfg.Contexts[cfg.CurrentContext].AuthInfo]; user.ClientKey != lk.GetPrivateKeyCertPath() {
+		t.Errorf("Expected client key %s, got %s", lk.GetPrivateKeyCertPath(), user.ClientKey)
+	}
+}
@@ -37,8 +37,14 @@ func StartProxyServer(lk LocalkubeServer) func() error {
 
 	// master details
 	config.Master = lk.GetAPIServerInsecureURL()
+	if lk.IsWorker() {
+		config.Master = lk.Master
+		config.Kubeconfig = lk.GetWorkerKubeconfigPath()
+	}
 
 	config.Mode = componentconfig.ProxyModeIPTables
+	// Traffic to services from outside of the pod network is masqueraded
+	config.ClusterCIDR = lk.PodNetworkCIDR
 
 	// defaults
 	config.OOMScoreAdj = &OOMScoreAdj
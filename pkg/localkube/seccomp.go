@@ -0,0 +1,102 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	seccompDefaultPolicyName = "minikube-seccomp-default"
+	// The default seccomp profile of docker
+	runtimeDefaultSeccompProfile = "docker/default"
+)
+
+// allCapabilities lets the pod security policy allow any capability, which
+// it can't express otherwise
+var allCapabilities = []v1.Capability{
+	"AUDIT_CONTROL", "AUDIT_READ", "AUDIT_WRITE", "BLOCK_SUSPEND", "CHOWN",
+	"DAC_OVERRIDE", "DAC_READ_SEARCH", "FOWNER", "FSETID", "IPC_LOCK",
+	"IPC_OWNER", "KILL", "LEASE", "LINUX_IMMUTABLE", "MAC_ADMIN",
+	"MAC_OVERRIDE", "MKNOD", "NET_ADMIN", "NET_BIND_SERVICE", "NET_BROADCAST",
+	"NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYSLOG",
+	"SYS_ADMIN", "SYS_BOOT", "SYS_CHROOT", "SYS_MODULE", "SYS_NICE",
+	"SYS_PACCT", "SYS_PTRACE", "SYS_RAWIO", "SYS_RESOURCE", "SYS_TIME",
+	"SYS_TTY_CONFIG", "WAKE_ALARM",
+}
+
+func (lk LocalkubeServer) NewSeccompDefaultServer() Server {
+	return NewSimpleServer("seccomp-default", serverInterval, StartSeccompDefault(lk))
+}
+
+// StartSeccompDefault keeps the pod security policy defaulting the seccomp
+// profile of pods, creating it again if it gets deleted. Pods are rejected
+// by the PodSecurityPolicy admission plugin until it exists.
+func StartSeccompDefault(lk LocalkubeServer) func() error {
+	config := rest.Config{Host: lk.GetAPIServerInsecureURL()}
+	return func() error {
+		clientset, err := kubernetes.NewForConfig(&config)
+		if err != nil {
+			return errors.Wrap(err, "Error creating client")
+		}
+		policies := clientset.Extensions().PodSecurityPolicies()
+		for {
+			_, err := policies.Get(seccompDefaultPolicyName)
+			if apierrors.IsNotFound(err) {
+				_, err = policies.Create(seccompDefaultPolicy())
+			}
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return errors.Wrap(err, "Error creating the pod security policy")
+			}
+			time.Sleep(time.Minute)
+		}
+	}
+}
+
+// seccompDefaultPolicy returns a pod security policy allowing any pod, which
+// sets the seccomp profile of the container runtime on those without one.
+func seccompDefaultPolicy() *v1beta1.PodSecurityPolicy {
+	return &v1beta1.PodSecurityPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name: seccompDefaultPolicyName,
+			Annotations: map[string]string{
+				"seccomp.security.alpha.kubernetes.io/defaultProfileName":  runtimeDefaultSeccompProfile,
+				"seccomp.security.alpha.kubernetes.io/allowedProfileNames": "*",
+			},
+		},
+		Spec: v1beta1.PodSecurityPolicySpec{
+			Privileged:          true,
+			AllowedCapabilities: allCapabilities,
+			Volumes:             []v1beta1.FSType{v1beta1.All},
+			HostNetwork:         true,
+			HostPorts:           []v1beta1.HostPortRange{{Min: 0, Max: 65535}},
+			HostPID:             true,
+			HostIPC:             true,
+			SELinux:             v1beta1.SELinuxStrategyOptions{Rule: v1beta1.SELinuxStrategyRunAsAny},
+			RunAsUser:           v1beta1.RunAsUserStrategyOptions{Rule: v1beta1.RunAsUserStrategyRunAsAny},
+			SupplementalGroups:  v1beta1.SupplementalGroupsStrategyOptions{Rule: v1beta1.SupplementalGroupsStrategyRunAsAny},
+			FSGroup:             v1beta1.FSGroupStrategyOptions{Rule: v1beta1.FSGroupStrategyRunAsAny},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"testing"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestSeccompDefaultPolicy(t *testing.T) {
+	p := seccompDefaultPolicy()
+	if profile := p.Annotations["seccomp.security.alpha.kubernetes.io/defaultProfileName"]; profile != "docker/default" {
+		t.Errorf("Expected the pods to default to the docker profile, got %q", profile)
+	}
+	if allowed := p.Annotations["seccomp.security.alpha.kubernetes.io/allowedProfileNames"]; allowed != "*" {
+		t.Errorf("Expected any profile to be allowed, got %q", allowed)
+	}
+	if !p.Spec.Privileged || !p.Spec.HostNetwork {
+		t.Errorf("Expected privileged pods on the host network to be allowed")
+	}
+	capabilities := map[v1.Capability]bool{}
+	for _, c := range p.Spec.AllowedCapabilities {
+		capabilities[c] = true
+	}
+	for _, c := range []v1.Capability{"NET_ADMIN", "SYS_ADMIN", "SYS_PTRACE"} {
+		if !capabilities[c] {
+			t.Errorf("Expected capability %s to be allowed", c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"os"
+	"path"
+	"text/template"
+)
+
+var workerKubeconfigTemplate = template.Must(template.New("kubeconfig").Parse(`apiVersion: v1
+kind: Config
+clusters:
+- name: localkube
+  cluster:
+    server: {{.Master}}
+    certificate-authority: {{.CACert}}
+users:
+- name: localkube
+  user:
+    client-certificate: {{.ClientCert}}
+    client-key: {{.ClientKey}}
+contexts:
+- name: localkube
+  context:
+    cluster: localkube
+    user: localkube
+current-context: localkube
+`))
+
+func (lk LocalkubeServer) GetWorkerKubeconfigPath() string {
+	return path.Join(lk.LocalkubeDirectory, "kubeconfig")
+}
+
+// WriteWorkerKubeconfig writes the kubeconfig the kubelet and the proxy of
+// a worker connect to the master with. They authenticate with the
+// certificate of the apiserver, which is also valid for clients.
+func (lk LocalkubeServer) WriteWorkerKubeconfig() error {
+	f, err := os.OpenFile(lk.GetWorkerKubeconfigPath(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Master     string
+		CACert     string
+		ClientCert string
+		ClientKey  string
+	}{
+		Master:     lk.Master,
+		CACert:     lk.GetCAPublicKeyCertPath(),
+		ClientCert: lk.GetPublicKeyCertPath(),
+		ClientKey:  lk.GetPrivateKeyCertPath(),
+	}
+	if err := workerKubeconfigTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
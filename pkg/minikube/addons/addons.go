@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package addons manages the addons of a running minikube VM.
+package addons
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ErrNotRunning is returned when the addons of a minikube VM which is not
+// running are changed. They are applied the next time minikube is started.
+var ErrNotRunning = errors.New("minikube is not currently running")
+
+// Set enables or disables the addon with the given name in the minikube VM
+// managed by api. Only the files whose contents in the VM differ are
+// transferred.
+func Set(api libmachine.API, name string, enable bool) error {
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return errors.Errorf("%s is not a valid addon", name)
+	}
+	if err := checkRunning(api); err != nil {
+		return err
+	}
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	m := loadManifests()
+	if !enable {
+		if err := Delete(addon, host.Driver); err != nil {
+			return errors.Wrapf(err, "Error deleting addon %s from VM", name)
+		}
+		return m.forget(name)
+	}
+	files, err := addon.GetAssets()
+	if err != nil {
+		return errors.Wrap(err, "Error getting addon assets")
+	}
+	if err := cluster.CopyFiles(host.Driver, files); err != nil {
+		return errors.Wrapf(err, "Error transferring addon %s to VM", name)
+	}
+	return m.record(name, files)
+}
+
+// SetViaAPI enables or disables the addon with the given name by applying or
+// deleting its objects through the apiserver rather than copying its files
+// into the VM, and reports the changed objects to out. Only the objects of
+// the files which changed since the addon was last enabled in the cluster
+// are applied.
+func SetViaAPI(api libmachine.API, name string, enable bool, out io.Writer) error {
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return errors.Errorf("%s is not a valid addon", name)
+	}
+	if err := checkRunning(api); err != nil {
+		return err
+	}
+	m := loadManifests()
+	var files, changedFiles []assets.CopyableFile
+	if enable {
+		var err error
+		if files, err = addon.GetAssets(); err != nil {
+			return errors.Wrap(err, "Error getting addon assets")
+		}
+		if changedFiles = changed(files, m[name]); len(changedFiles) == 0 {
+			glog.Infof("Addon %s is unchanged, skipping", name)
+			return nil
+		}
+	}
+	client, err := NewClient()
+	if err != nil {
+		return err
+	}
+	if !enable {
+		if err := Remove(client, addon, out); err != nil {
+			return err
+		}
+		return m.forget(name)
+	}
+	if err := ApplyFiles(client, changedFiles, out); err != nil {
+		return err
+	}
+	return m.record(name, files)
+}
+
+// ApplyEnabled applies all the enabled addons through the apiserver, retrying
+// while the apiserver is starting up.
+func ApplyEnabled(out io.Writer) error {
+	client, err := NewClient()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(assets.Addons))
+	for name := range assets.Addons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addon := assets.Addons[name]
+		if enabled, err := addon.IsEnabled(); err != nil || !enabled {
+			continue
+		}
+		apply := func() error {
+			return Apply(client, addon, out)
+		}
+		if err := util.RetryAfter(20, apply, 3*time.Second); err != nil {
+			return errors.Wrapf(err, "Error applying addon %s", name)
+		}
+	}
+	return nil
+}
+
+// NewClient returns a client for the apiserver of the context of the active
+// profile, regardless of the current kubectl context.
+func NewClient() (rest.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: config.KubeContext()}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating kubeConfig")
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating kubernetes client")
+	}
+	return client.Core().RESTClient(), nil
+}
+
+func checkRunning(api libmachine.API) error {
+	s, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return errors.Wrap(err, "Error getting machine status")
+	}
+	if s != state.Running.String() {
+		return ErrNotRunning
+	}
+	return nil
+}
+
+// Transfer copies the files of the addon into the VM
+func Transfer(addon *assets.Addon, d drivers.Driver) error {
+	files, err := addon.GetAssets()
+	if err != nil {
+		return errors.Wrap(err, "Error getting addon assets")
+	}
+	return cluster.CopyFiles(d, files)
+}
+
+// Delete removes the files of the addon from the VM
+func Delete(addon *assets.Addon, d drivers.Driver) error {
+	return cluster.DeleteFiles(d, addon.GetTargets())
+}
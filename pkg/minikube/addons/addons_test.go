@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestSetUnknownAddon(t *testing.T) {
+	api := tests.NewMockAPI()
+	if err := Set(api, "InvalidAddon", true); err == nil {
+		t.Fatalf("Set did not return error for unknown addon")
+	}
+}
+
+func TestSetNotRunning(t *testing.T) {
+	api := tests.NewMockAPI()
+	if err := Set(api, "dashboard", true); err != ErrNotRunning {
+		t.Fatalf("Expected %v when minikube is not running, got %v", ErrNotRunning, err)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+
+	dashboard := assets.Addons["dashboard"]
+	if err := Transfer(dashboard, d); err != nil {
+		t.Fatalf("Unexpected error %s transferring addon", err)
+	}
+	// check contents
+	for _, addon := range dashboard.Assets {
+		expected, _ := ioutil.ReadFile(addon.GetAssetName())
+		transferred := s.Transfers.Bytes()
+		//test that custom addons are transferred properly
+		if !bytes.Contains(transferred, expected) {
+			t.Fatalf("Expected transfers to contain addon with content: %s. It was: %s", expected, transferred)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+
+	dashboard := assets.Addons["dashboard"]
+	if err := Delete(dashboard, d); err != nil {
+		t.Fatalf("Unexpected error %s deleting addon", err)
+	}
+	// check command(s) were run
+	for _, addon := range dashboard.Assets {
+		expected, _ := ioutil.ReadFile(addon.GetAssetName())
+		if _, ok := s.Commands[sshutil.GetDeleteFileCommand(addon)]; !ok {
+			t.Fatalf("Error: Expected delete addon ssh command to be run: %s.", expected)
+		}
+	}
+}
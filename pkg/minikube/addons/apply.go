@@ -0,0 +1,250 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// resource describes how objects of a kind are served by the apiserver
+type resource struct {
+	name       string
+	namespaced bool
+}
+
+// resources are the kinds which can be applied, keyed by apiVersion and kind
+var resources = map[string]resource{
+	"v1/ConfigMap":                        {"configmaps", true},
+	"v1/Namespace":                        {"namespaces", false},
+	"v1/Pod":                              {"pods", true},
+	"v1/ReplicationController":            {"replicationcontrollers", true},
+	"v1/Secret":                           {"secrets", true},
+	"v1/Service":                          {"services", true},
+	"v1/ServiceAccount":                   {"serviceaccounts", true},
+	"extensions/v1beta1/DaemonSet":        {"daemonsets", true},
+	"extensions/v1beta1/Deployment":       {"deployments", true},
+	"extensions/v1beta1/Ingress":          {"ingresses", true},
+	"storage.k8s.io/v1beta1/StorageClass": {"storageclasses", false},
+}
+
+// object is a manifest decoded from yaml
+type object struct {
+	data      map[string]interface{}
+	kind      string
+	name      string
+	namespace string
+	resource  resource
+	group     string
+}
+
+func newObject(manifest []byte) (*object, error) {
+	j, err := yaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing manifest")
+	}
+	o := &object{}
+	if err := json.Unmarshal(j, &o.data); err != nil {
+		return nil, errors.Wrap(err, "Error parsing manifest")
+	}
+	apiVersion, _ := o.data["apiVersion"].(string)
+	o.kind, _ = o.data["kind"].(string)
+	metadata, _ := o.data["metadata"].(map[string]interface{})
+	o.name, _ = metadata["name"].(string)
+	o.namespace, _ = metadata["namespace"].(string)
+	if o.namespace == "" {
+		o.namespace = "default"
+	}
+	r, ok := resources[apiVersion+"/"+o.kind]
+	if !ok {
+		return nil, errors.Errorf("unsupported kind %s/%s", apiVersion, o.kind)
+	}
+	if o.name == "" {
+		return nil, errors.Errorf("%s has no name", o.kind)
+	}
+	o.resource = r
+	o.group = path.Join("/apis", apiVersion)
+	if apiVersion == "v1" {
+		o.group = "/api/v1"
+	}
+	return o, nil
+}
+
+// collectionPath returns the path objects of this kind are created at
+func (o *object) collectionPath() string {
+	if o.resource.namespaced {
+		return path.Join(o.group, "namespaces", o.namespace, o.resource.name)
+	}
+	return path.Join(o.group, o.resource.name)
+}
+
+func (o *object) path() string {
+	return path.Join(o.collectionPath(), o.name)
+}
+
+func (o *object) String() string {
+	return fmt.Sprintf("%s/%s", o.resource.name, o.name)
+}
+
+// preserve copies the fields set by the apiserver which can not be changed
+// on update from the existing object.
+func (o *object) preserve(existing map[string]interface{}) {
+	metadata, _ := o.data["metadata"].(map[string]interface{})
+	existingMetadata, _ := existing["metadata"].(map[string]interface{})
+	if metadata != nil && existingMetadata != nil {
+		metadata["resourceVersion"] = existingMetadata["resourceVersion"]
+	}
+	if o.kind != "Service" {
+		return
+	}
+	spec, _ := o.data["spec"].(map[string]interface{})
+	existingSpec, _ := existing["spec"].(map[string]interface{})
+	if spec == nil || existingSpec == nil {
+		return
+	}
+	spec["clusterIP"] = existingSpec["clusterIP"]
+	ports, _ := spec["ports"].([]interface{})
+	existingPorts, _ := existingSpec["ports"].([]interface{})
+	for _, p := range ports {
+		port, _ := p.(map[string]interface{})
+		if port == nil || port["nodePort"] != nil {
+			continue
+		}
+		for _, e := range existingPorts {
+			existingPort, _ := e.(map[string]interface{})
+			if existingPort != nil && existingPort["port"] == port["port"] {
+				port["nodePort"] = existingPort["nodePort"]
+			}
+		}
+	}
+}
+
+func addonObjects(addon *assets.Addon) ([]*object, error) {
+	manifests, err := addon.Manifests()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting addon manifests")
+	}
+	return manifestObjects(manifests)
+}
+
+// fileObjects returns the objects of the manifests of the files.
+func fileObjects(files []assets.CopyableFile) ([]*object, error) {
+	var manifests [][]byte
+	for _, f := range files {
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading %s", f.GetTargetName())
+		}
+		manifests = append(manifests, assets.SplitManifests(data)...)
+	}
+	return manifestObjects(manifests)
+}
+
+func manifestObjects(manifests [][]byte) ([]*object, error) {
+	var objects []*object
+	for _, m := range manifests {
+		o, err := newObject(m)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
+
+// Apply creates the objects of the addon through the apiserver, updating
+// the ones which already exist, and reports each of them to out.
+func Apply(client rest.Interface, addon *assets.Addon, out io.Writer) error {
+	objects, err := addonObjects(addon)
+	if err != nil {
+		return err
+	}
+	return apply(client, objects, out)
+}
+
+// ApplyFiles applies the objects of the manifests of the files, as Apply
+// does for a whole addon.
+func ApplyFiles(client rest.Interface, files []assets.CopyableFile, out io.Writer) error {
+	objects, err := fileObjects(files)
+	if err != nil {
+		return err
+	}
+	return apply(client, objects, out)
+}
+
+func apply(client rest.Interface, objects []*object, out io.Writer) error {
+	for _, o := range objects {
+		raw, err := client.Get().AbsPath(o.path()).Do().Raw()
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Error getting %s", o)
+			}
+			body, err := json.Marshal(o.data)
+			if err != nil {
+				return err
+			}
+			if err := client.Post().AbsPath(o.collectionPath()).Body(body).Do().Error(); err != nil {
+				return errors.Wrapf(err, "Error creating %s", o)
+			}
+			fmt.Fprintf(out, "%s created\n", o)
+			continue
+		}
+		var existing map[string]interface{}
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return errors.Wrapf(err, "Error parsing %s", o)
+		}
+		o.preserve(existing)
+		body, err := json.Marshal(o.data)
+		if err != nil {
+			return err
+		}
+		if err := client.Put().AbsPath(o.path()).Body(body).Do().Error(); err != nil {
+			return errors.Wrapf(err, "Error updating %s", o)
+		}
+		fmt.Fprintf(out, "%s configured\n", o)
+	}
+	return nil
+}
+
+// Remove deletes the objects of the addon through the apiserver, and reports each of them to out.
+func Remove(client rest.Interface, addon *assets.Addon, out io.Writer) error {
+	objects, err := addonObjects(addon)
+	if err != nil {
+		return err
+	}
+	// Delete the pods of replication controllers and deployments along with them
+	options := []byte(`{"kind":"DeleteOptions","apiVersion":"v1","orphanDependents":false}`)
+	for _, o := range objects {
+		if err := client.Delete().AbsPath(o.path()).Body(options).Do().Error(); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "Error deleting %s", o)
+		}
+		fmt.Fprintf(out, "%s deleted\n", o)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+const testManifest = `apiVersion: v1
+kind: Service
+metadata:
+  name: test-svc
+  namespace: kube-system
+spec:
+  type: NodePort
+  ports:
+  - port: 80
+---
+apiVersion: storage.k8s.io/v1beta1
+kind: StorageClass
+metadata:
+  name: standard
+`
+
+// fakeAPIServer stores the objects posted to it by path
+type fakeAPIServer struct {
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	var obj map[string]interface{}
+	if r.Method == "POST" || r.Method == "PUT" {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &obj)
+	}
+	switch r.Method {
+	case "GET", "PUT", "DELETE":
+		existing, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+			return
+		}
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(existing)
+		case "PUT":
+			s.objects[r.URL.Path] = obj
+			json.NewEncoder(w).Encode(obj)
+		case "DELETE":
+			delete(s.objects, r.URL.Path)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+		}
+	case "POST":
+		metadata := obj["metadata"].(map[string]interface{})
+		metadata["resourceVersion"] = "1"
+		if spec, ok := obj["spec"].(map[string]interface{}); ok && obj["kind"] == "Service" {
+			spec["clusterIP"] = "10.0.0.10"
+		}
+		s.objects[path.Join(r.URL.Path, metadata["name"].(string))] = obj
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(obj)
+	}
+}
+
+func TestApplyAndRemove(t *testing.T) {
+	server := &fakeAPIServer{objects: map[string]map[string]interface{}{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: ts.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	client := clientset.Core().RESTClient()
+	addon := assets.NewAddon([]*assets.MemoryAsset{
+		assets.NewMemoryAssetFromBytes([]byte(testManifest), "/etc/kubernetes/addons", "test.yaml", "0640"),
+	}, false, "test")
+
+	var out bytes.Buffer
+	if err := Apply(client, addon, &out); err != nil {
+		t.Fatalf("Unexpected error applying addon: %s", err)
+	}
+	svcPath := "/api/v1/namespaces/kube-system/services/test-svc"
+	if _, ok := server.objects[svcPath]; !ok {
+		t.Fatalf("Expected service to be created at %s, objects: %v", svcPath, server.objects)
+	}
+	if _, ok := server.objects["/apis/storage.k8s.io/v1beta1/storageclasses/standard"]; !ok {
+		t.Fatalf("Expected storage class to be created, objects: %v", server.objects)
+	}
+
+	// Applying again updates the objects, keeping the cluster IP
+	if err := Apply(client, addon, &out); err != nil {
+		t.Fatalf("Unexpected error applying addon again: %s", err)
+	}
+	spec := server.objects[svcPath]["spec"].(map[string]interface{})
+	if spec["clusterIP"] != "10.0.0.10" {
+		t.Errorf("Expected cluster IP to be preserved, got %v", spec["clusterIP"])
+	}
+	expected := "services/test-svc created\nstorageclasses/standard created\nservices/test-svc configured\nstorageclasses/standard configured\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := Remove(client, addon, &out); err != nil {
+		t.Fatalf("Unexpected error removing addon: %s", err)
+	}
+	if len(server.objects) != 0 {
+		t.Errorf("Expected all objects to be deleted, got %v", server.objects)
+	}
+	if !strings.Contains(out.String(), "services/test-svc deleted") {
+		t.Errorf("Expected deletion to be reported, got %q", out.String())
+	}
+}
+
+func TestNewObjectUnsupportedKind(t *testing.T) {
+	if _, err := newObject([]byte("apiVersion: v1\nkind: Unknown\nmetadata:\n  name: test\n")); err == nil {
+		t.Fatalf("Expected error for unsupported kind")
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const manifestsFile = "addons.json"
+
+// Manifests records the sha256 of the rendered files of the addons applied
+// through the apiserver to the cluster of a machine, by addon and by path in
+// the VM, so that enabling an addon again only applies the files which changed.
+// Files copied into the VM are compared with the ones already there instead.
+type Manifests map[string]map[string]string
+
+// manifestsPath returns the file recording the manifests of the machine, in
+// its directory, which deleting the machine removes. Every profile drives the
+// same machine, so that disabling an addon in any of them forgets it.
+func manifestsPath(machine string) string {
+	return constants.MakeMiniPath("machines", machine, manifestsFile)
+}
+
+// LoadManifests returns the manifests recorded for the machine, none if
+// nothing was recorded yet.
+func LoadManifests(machine string) (Manifests, error) {
+	m := Manifests{}
+	path := manifestsPath(machine)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading %s", path)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing %s", path)
+	}
+	return m, nil
+}
+
+// Save records the manifests of the machine.
+func (m Manifests) Save(machine string) error {
+	path := manifestsPath(machine)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "Error creating directory of %s", path)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "Error recording the addons of machine %q", machine)
+	}
+	return nil
+}
+
+// RemoveManifests forgets the manifests of the machine, once its VM is
+// deleted.
+func RemoveManifests(machine string) error {
+	if err := os.Remove(manifestsPath(machine)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Error removing the addons of machine %q", machine)
+	}
+	return nil
+}
+
+// loadManifests returns the manifests of the minikube machine, none if they
+// can't be read, in which case the addons are applied in full.
+func loadManifests() Manifests {
+	m, err := LoadManifests(constants.MachineName)
+	if err != nil {
+		glog.Warningln("Error loading the manifests of the addons, applying them in full: ", err)
+		return Manifests{}
+	}
+	return m
+}
+
+// record saves the files of the addon as applied to the minikube machine.
+func (m Manifests) record(name string, files []assets.CopyableFile) error {
+	m[name] = checksums(files)
+	return m.Save(constants.MachineName)
+}
+
+// forget drops the addon from the manifests of the minikube machine, once it
+// is disabled, so that enabling it again applies all its files.
+func (m Manifests) forget(name string) error {
+	if _, ok := m[name]; !ok {
+		return nil
+	}
+	delete(m, name)
+	return m.Save(constants.MachineName)
+}
+
+// RecordEnabled records the manifests of all the enabled addons of the
+// machine, replacing the previous ones, once minikube start applied them.
+func RecordEnabled(machine string) error {
+	m := Manifests{}
+	for name, addon := range assets.Addons {
+		if enabled, err := addon.IsEnabled(); err != nil || !enabled {
+			continue
+		}
+		files, err := addon.GetAssets()
+		if err != nil {
+			return errors.Wrapf(err, "Error getting assets of addon %s", name)
+		}
+		m[name] = checksums(files)
+	}
+	return m.Save(machine)
+}
+
+// checksums returns the sha256 of the files, by path in the VM.
+func checksums(files []assets.CopyableFile) map[string]string {
+	sums := map[string]string{}
+	for _, f := range files {
+		sums[filepath.Join(f.GetTargetDir(), f.GetTargetName())] = f.GetChecksum()
+	}
+	return sums
+}
+
+// changed returns the files whose contents differ from the recorded ones.
+func changed(files []assets.CopyableFile, recorded map[string]string) []assets.CopyableFile {
+	var changed []assets.CopyableFile
+	for _, f := range files {
+		sum := f.GetChecksum()
+		if sum == "" || recorded[filepath.Join(f.GetTargetDir(), f.GetTargetName())] != sum {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
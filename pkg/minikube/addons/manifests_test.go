@@ -0,0 +1,96 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestManifests(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetFromBytes([]byte("kind: Service"), "/etc/kubernetes/addons", "svc.yaml", "0640"),
+		assets.NewMemoryAssetFromBytes([]byte("kind: Deployment"), "/etc/kubernetes/addons", "deploy.yaml", "0640"),
+	}
+	m, err := LoadManifests(constants.MachineName)
+	if err != nil {
+		t.Fatalf("Unexpected error loading manifests which were not recorded: %s", err)
+	}
+	if c := changed(files, m["example"]); len(c) != 2 {
+		t.Errorf("Expected all the files of an addon never applied to change, got %d", len(c))
+	}
+
+	m["example"] = checksums(files)
+	if err := m.Save(constants.MachineName); err != nil {
+		t.Fatalf("Unexpected error saving manifests: %s", err)
+	}
+	if m, err = LoadManifests(constants.MachineName); err != nil {
+		t.Fatalf("Unexpected error loading manifests: %s", err)
+	}
+	if c := changed(files, m["example"]); len(c) != 0 {
+		t.Errorf("Expected no file to change, got %d", len(c))
+	}
+	files[1] = assets.NewMemoryAssetFromBytes([]byte("kind: Deployment\nreplicas: 2"), "/etc/kubernetes/addons", "deploy.yaml", "0640")
+	if c := changed(files, m["example"]); len(c) != 1 || c[0].GetTargetName() != "deploy.yaml" {
+		t.Errorf("Expected only deploy.yaml to change, got %v", c)
+	}
+	if m, err := LoadManifests(constants.NodeMachineName("m02")); err != nil || len(m) != 0 {
+		t.Errorf("Expected another machine to have no manifests, got %v, %v", m, err)
+	}
+
+	if err := RemoveManifests(constants.MachineName); err != nil {
+		t.Fatalf("Unexpected error removing manifests: %s", err)
+	}
+	if err := RemoveManifests(constants.MachineName); err != nil {
+		t.Fatalf("Unexpected error removing manifests twice: %s", err)
+	}
+	if m, err := LoadManifests(constants.MachineName); err != nil || len(m) != 0 {
+		t.Errorf("Expected the removed manifests to be empty, got %v, %v", m, err)
+	}
+}
+
+func TestManifestsSharedByProfiles(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetFromBytes([]byte("kind: Service"), "/etc/kubernetes/addons", "svc.yaml", "0640"),
+	}
+	if err := loadManifests().record("example", files); err != nil {
+		t.Fatalf("Unexpected error recording manifests: %s", err)
+	}
+	// Every profile drives the same machine, disabling the addon in another
+	// profile must apply it again when enabled in the default one
+	viper.Set(config.ProfileFlag, "dev")
+	if err := loadManifests().forget("example"); err != nil {
+		t.Fatalf("Unexpected error forgetting manifests: %s", err)
+	}
+	viper.Set(config.ProfileFlag, "")
+	if c := changed(files, loadManifests()["example"]); len(c) != 1 {
+		t.Errorf("Expected the files of the addon disabled in another profile to change, got %v", c)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// readyInterval is the interval between the checks of WaitReady
+var readyInterval = 2 * time.Second
+
+// Ready returns nil once the pods of the workloads of the addon are running
+// and ready, or an error naming the first workload which isn't: a pod, the
+// replicas of a replication controller or deployment, or one pod of a daemon
+// set.
+func Ready(client rest.Interface, addon *assets.Addon) error {
+	objects, err := addonObjects(addon)
+	if err != nil {
+		return err
+	}
+	for _, o := range objects {
+		var ready, want int
+		switch o.kind {
+		case "Pod":
+			ready, want, err = podReady(client, o)
+		case "ReplicationController", "Deployment", "DaemonSet":
+			ready, want, err = replicasReady(client, o)
+		default:
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Error getting the pods of %s", o)
+		}
+		if ready < want {
+			return errors.Errorf("%s has %d of %d pods ready", o, ready, want)
+		}
+	}
+	return nil
+}
+
+// WaitReady waits until the addon is Ready, for up to the timeout.
+func WaitReady(client rest.Interface, addon *assets.Addon, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := Ready(client, addon)
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(readyInterval)
+	}
+}
+
+func podReady(client rest.Interface, o *object) (int, int, error) {
+	raw, err := client.Get().AbsPath(o.path()).Do().Raw()
+	if err != nil {
+		return 0, 1, err
+	}
+	var pod v1.Pod
+	if err := json.Unmarshal(raw, &pod); err != nil {
+		return 0, 1, err
+	}
+	if isPodReady(pod) {
+		return 1, 1, nil
+	}
+	return 0, 1, nil
+}
+
+// replicasReady counts the ready pods with the labels of the template of the
+// workload, against its replicas.
+func replicasReady(client rest.Interface, o *object) (int, int, error) {
+	spec, _ := o.data["spec"].(map[string]interface{})
+	want := 1
+	if replicas, ok := spec["replicas"].(float64); ok && o.kind != "DaemonSet" {
+		want = int(replicas)
+	}
+	template, _ := spec["template"].(map[string]interface{})
+	metadata, _ := template["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	if len(labels) == 0 {
+		return 0, want, errors.New("no labels in the pod template")
+	}
+	var selector []string
+	for k, v := range labels {
+		selector = append(selector, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(selector)
+
+	raw, err := client.Get().AbsPath("/api/v1/namespaces", o.namespace, "pods").
+		Param("labelSelector", strings.Join(selector, ",")).Do().Raw()
+	if err != nil {
+		return 0, want, err
+	}
+	var pods v1.PodList
+	if err := json.Unmarshal(raw, &pods); err != nil {
+		return 0, want, err
+	}
+	ready := 0
+	for _, pod := range pods.Items {
+		if isPodReady(pod) {
+			ready++
+		}
+	}
+	return ready, want, nil
+}
+
+func isPodReady(pod v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
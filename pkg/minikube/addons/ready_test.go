@@ -0,0 +1,87 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+const testWorkload = `apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: web
+  namespace: kube-system
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: kube-system
+`
+
+const readyPod = `{"metadata":{"name":"%s"},"status":{"phase":"Running","conditions":[{"type":"Ready","status":"%s"}]}}`
+
+func TestReady(t *testing.T) {
+	var pods []string
+	var selector string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selector = r.URL.Query().Get("labelSelector")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"kind":"PodList","apiVersion":"v1","items":[%s]}`, strings.Join(pods, ","))
+	}))
+	defer ts.Close()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: ts.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	client := clientset.Core().RESTClient()
+	addon := assets.NewAddon([]*assets.MemoryAsset{
+		assets.NewMemoryAssetFromBytes([]byte(testWorkload), "/etc/kubernetes/addons", "web.yaml", "0640"),
+	}, false, "web")
+
+	pods = []string{fmt.Sprintf(readyPod, "web-1", "True"), fmt.Sprintf(readyPod, "web-2", "False")}
+	if err := Ready(client, addon); err == nil || !strings.Contains(err.Error(), "1 of 2 pods ready") {
+		t.Errorf("Expected the replication controller to have 1 of 2 pods ready, got %v", err)
+	}
+	if selector != "app=web" {
+		t.Errorf("Expected the pods to be selected by the labels of the template, got %q", selector)
+	}
+	readyInterval = time.Millisecond
+	if err := WaitReady(client, addon, 10*time.Millisecond); err == nil {
+		t.Errorf("Expected the wait to time out")
+	}
+
+	pods[1] = fmt.Sprintf(readyPod, "web-2", "True")
+	if err := WaitReady(client, addon, time.Second); err != nil {
+		t.Errorf("Unexpected error waiting for the ready pods: %s", err)
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admission tests manifests against the admission chain of the
+// apiserver, for minikube admission test. The apiserver has no dry-run, so the
+// objects are created in scratch namespaces, neutralized so that no pod runs,
+// and the objects it returns are compared with those sent.
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/pkg/api"
+	"k8s.io/client-go/pkg/util/yaml"
+)
+
+// Verdicts of the admission chain
+const (
+	Admitted = "admitted"
+	Mutated  = "mutated"
+	Rejected = "rejected"
+	Skipped  = "skipped"
+)
+
+// NeutralNodeName is the node of the pods tested, which doesn't exist so that
+// they are never run.
+const NeutralNodeName = "minikube-admission-test"
+
+// Object is an object of a manifest, as decoded from JSON.
+type Object map[string]interface{}
+
+func (o Object) field(path ...string) interface{} {
+	var v interface{} = map[string]interface{}(o)
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
+
+func (o Object) stringField(path ...string) string {
+	s, _ := o.field(path...).(string)
+	return s
+}
+
+// setField sets the field, creating the maps on its path.
+func (o Object) setField(value interface{}, path ...string) {
+	m := map[string]interface{}(o)
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+func (o Object) APIVersion() string { return o.stringField("apiVersion") }
+func (o Object) Kind() string       { return o.stringField("kind") }
+
+// Name returns the name of the object, or its generateName.
+func (o Object) Name() string {
+	if name := o.stringField("metadata", "name"); name != "" {
+		return name
+	}
+	return o.stringField("metadata", "generateName")
+}
+
+// Namespace returns the namespace of the object, default when not set.
+func (o Object) Namespace() string {
+	if ns := o.stringField("metadata", "namespace"); ns != "" {
+		return ns
+	}
+	return api.NamespaceDefault
+}
+
+// Result is the verdict of the admission chain on an object.
+type Result struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Verdict   string
+	// Mutations are the fields changed by the admission chain, when mutated
+	Mutations []string
+	// Message is the reason of the rejection or of skipping the object
+	Message string
+}
+
+// Decode decodes the objects of a YAML or JSON manifest, of several documents
+// or of a List.
+func Decode(r io.Reader) ([]Object, error) {
+	var objects []Object
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var o Object
+		if err := d.Decode(&o); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "Error decoding the manifest")
+		}
+		if len(o) == 0 {
+			continue
+		}
+		if strings.HasSuffix(o.Kind(), "List") {
+			items, _ := o["items"].([]interface{})
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					objects = append(objects, Object(m))
+				}
+			}
+			continue
+		}
+		if o.APIVersion() == "" || o.Kind() == "" {
+			return nil, fmt.Errorf("Object %q has no apiVersion or kind", o.Name())
+		}
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
+
+// Neutralize changes the object so that creating it doesn't run pods: pods
+// are bound to a node which doesn't exist and controllers have no replicas.
+// Admission plugins don't look at those fields.
+func Neutralize(o Object) {
+	switch o.Kind() {
+	case "Pod":
+		if o.stringField("spec", "nodeName") == "" {
+			o.setField(NeutralNodeName, "spec", "nodeName")
+		}
+	case "Deployment", "ReplicaSet", "ReplicationController", "StatefulSet", "PetSet":
+		o.setField(0, "spec", "replicas")
+	case "Job":
+		o.setField(0, "spec", "parallelism")
+	case "DaemonSet":
+		o.setField(map[string]interface{}{"kubernetes.io/hostname": NeutralNodeName}, "spec", "template", "spec", "nodeSelector")
+	case "ScheduledJob", "CronJob":
+		o.setField(true, "spec", "suspend")
+	}
+}
+
+// Expected returns the object as the apiserver would store it without
+// admission, with the defaults of its kind, so that they aren't reported as
+// mutations. Objects of unknown kinds are returned as they are.
+func Expected(o Object) Object {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return o
+	}
+	// Decoding again makes the fields set by Neutralize JSON values too
+	var raw Object
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return o
+	}
+	typed, _, err := api.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return raw
+	}
+	api.Scheme.Default(typed)
+	if data, err = json.Marshal(typed); err != nil {
+		return raw
+	}
+	var expected Object
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return raw
+	}
+	return expected
+}
+
+// ignoredFields are set by the apiserver whatever the admission chain.
+var ignoredFields = map[string]bool{
+	"status":                     true,
+	"metadata.uid":               true,
+	"metadata.selfLink":          true,
+	"metadata.resourceVersion":   true,
+	"metadata.creationTimestamp": true,
+	"metadata.generation":        true,
+	"metadata.namespace":         true,
+	"metadata.name":              true,
+	"spec.clusterIP":             true,
+}
+
+// Mutations returns the fields of the admitted object which differ from the
+// expected one, sorted, as "+ field: value" when added, "- field" when
+// removed and "~ field: old -> new" when changed.
+func Mutations(expected, admitted Object) []string {
+	var mutations []string
+	diff("", map[string]interface{}(expected), map[string]interface{}(admitted), &mutations)
+	sort.Strings(mutations)
+	return mutations
+}
+
+func diff(path string, expected, admitted interface{}, mutations *[]string) {
+	if ignoredFields[path] || reflect.DeepEqual(expected, admitted) {
+		return
+	}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		if a, ok := admitted.(map[string]interface{}); ok {
+			for k, v := range e {
+				diff(join(path, k), v, a[k], mutations)
+			}
+			for k, v := range a {
+				if _, ok := e[k]; !ok {
+					diff(join(path, k), nil, v, mutations)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := admitted.([]interface{}); ok {
+			for i := 0; i < len(e) || i < len(a); i++ {
+				var ev, av interface{}
+				if i < len(e) {
+					ev = e[i]
+				}
+				if i < len(a) {
+					av = a[i]
+				}
+				diff(fmt.Sprintf("%s[%d]", path, i), ev, av, mutations)
+			}
+			return
+		}
+	}
+	switch {
+	case expected == nil:
+		*mutations = append(*mutations, fmt.Sprintf("+ %s: %s", path, format(admitted)))
+	case admitted == nil:
+		*mutations = append(*mutations, fmt.Sprintf("- %s", path))
+	default:
+		*mutations = append(*mutations, fmt.Sprintf("~ %s: %s -> %s", path, format(expected), format(admitted)))
+	}
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func format(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "k8s.io/client-go/pkg/api/install"
+)
+
+const manifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: dev
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.11
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: extensions/v1beta1
+  kind: Deployment
+  metadata:
+    name: api
+  spec:
+    replicas: 3
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: dev
+`
+
+func TestDecode(t *testing.T) {
+	objects, err := Decode(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var got []string
+	for _, o := range objects {
+		got = append(got, o.Kind()+"/"+o.Namespace()+"/"+o.Name())
+	}
+	expected := []string{"Pod/dev/web", "Deployment/default/api", "Namespace/default/dev"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if _, err := Decode(strings.NewReader("metadata:\n  name: web\n")); err == nil {
+		t.Errorf("Expected an error for an object without a kind")
+	}
+}
+
+func TestNeutralize(t *testing.T) {
+	objects, err := Decode(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	pod, deployment := objects[0], objects[1]
+	Neutralize(pod)
+	Neutralize(deployment)
+	if n := pod.stringField("spec", "nodeName"); n != NeutralNodeName {
+		t.Errorf("Expected the pod to be bound to %s, got %q", NeutralNodeName, n)
+	}
+	if r := deployment.field("spec", "replicas"); r != 0 {
+		t.Errorf("Expected no replicas, got %v", r)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	objects, err := Decode(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	pod := objects[0]
+	Neutralize(pod)
+	expected := Expected(pod)
+
+	// The defaults of the pod aren't mutations
+	data, _ := json.Marshal(expected)
+	var admitted Object
+	json.Unmarshal(data, &admitted)
+	admitted.setField("2017-03-01T00:00:00Z", "metadata", "creationTimestamp")
+	admitted.setField(map[string]interface{}{"phase": "Pending"}, "status")
+	if m := Mutations(expected, admitted); len(m) != 0 {
+		t.Errorf("Expected no mutations, got %v", m)
+	}
+	if p := expected.stringField("spec", "restartPolicy"); p != "Always" {
+		t.Errorf("Expected the default restart policy, got %q", p)
+	}
+
+	// Those of the service account and limit ranger admissions are
+	admitted.setField("default", "spec", "serviceAccountName")
+	containers := admitted.field("spec", "containers").([]interface{})
+	containers[0].(map[string]interface{})["resources"] = map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m"}}
+	containers[0].(map[string]interface{})["image"] = "registry.local/nginx:1.11"
+	delete(admitted["metadata"].(map[string]interface{}), "name")
+	m := Mutations(expected, admitted)
+	want := []string{
+		`+ spec.containers[0].resources.requests: {"cpu":"100m"}`,
+		`+ spec.serviceAccountName: "default"`,
+		`~ spec.containers[0].image: "nginx:1.11" -> "registry.local/nginx:1.11"`,
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Expected %v, got %v", want, m)
+	}
+}
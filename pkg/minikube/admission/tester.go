@@ -0,0 +1,221 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/util"
+)
+
+// scratchNamespacePrefix is the prefix of the names generated for the scratch
+// namespaces.
+const scratchNamespacePrefix = "admission-test-"
+
+// Tester tests objects against the admission chain of the apiserver. It
+// creates a scratch namespace for each namespace of the objects, with copies
+// of its limit ranges, resource quotas and service accounts, so that the
+// objects meet the same policies. Cleanup deletes them.
+type Tester struct {
+	client *kubernetes.Clientset
+	// resources are the resources of the apiserver by apiVersion and kind
+	resources map[string]unversioned.APIResource
+	// scratch are the scratch namespaces by namespace of the objects
+	scratch map[string]string
+}
+
+// NewTester discovers the resources of the apiserver.
+func NewTester(client *kubernetes.Clientset) (*Tester, error) {
+	lists, err := client.Discovery().ServerResources()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error discovering the resources of the apiserver")
+	}
+	resources := map[string]unversioned.APIResource{}
+	for groupVersion, list := range lists {
+		for _, r := range list.APIResources {
+			// Subresources, like pods/status, share the kind of their resource
+			if !strings.Contains(r.Name, "/") {
+				resources[groupVersion+"/"+r.Kind] = r
+			}
+		}
+	}
+	return &Tester{client: client, resources: resources, scratch: map[string]string{}}, nil
+}
+
+// Test creates the object in the scratch namespace of its namespace and
+// returns the verdict of the admission chain. An error is returned when the
+// apiserver can't be reached, not when the object is rejected.
+func (t *Tester) Test(o Object) (Result, error) {
+	result := Result{Kind: o.Kind(), Name: o.Name(), Namespace: o.Namespace()}
+	resource, ok := t.resources[o.APIVersion()+"/"+o.Kind()]
+	if !ok {
+		result.Verdict = Skipped
+		result.Message = fmt.Sprintf("the apiserver has no kind %s in %s", o.Kind(), o.APIVersion())
+		return result, nil
+	}
+	if !resource.Namespaced {
+		result.Verdict = Skipped
+		result.Message = "cluster-scoped objects can't be created in a scratch namespace"
+		result.Namespace = ""
+		return result, nil
+	}
+
+	ns, err := t.scratchNamespace(o.Namespace())
+	if err != nil {
+		return result, err
+	}
+	o.setField(ns, "metadata", "namespace")
+	Neutralize(o)
+	body, err := json.Marshal(o)
+	if err != nil {
+		return result, errors.Wrap(err, "Error encoding the object")
+	}
+
+	prefix := "/apis"
+	if o.APIVersion() == "v1" {
+		prefix = "/api"
+	}
+	out, err := t.client.Core().RESTClient().Post().
+		AbsPath(path.Join(prefix, o.APIVersion(), "namespaces", ns, resource.Name)).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw()
+	if err != nil {
+		if _, ok := err.(apierrors.APIStatus); !ok {
+			return result, errors.Wrapf(err, "Error creating %s %s", o.Kind(), o.Name())
+		}
+		result.Verdict = Rejected
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	var admitted Object
+	if err := json.Unmarshal(out, &admitted); err != nil {
+		return result, errors.Wrapf(err, "Error decoding the admitted %s %s", o.Kind(), o.Name())
+	}
+	result.Mutations = Mutations(Expected(o), admitted)
+	result.Verdict = Admitted
+	if len(result.Mutations) > 0 {
+		result.Verdict = Mutated
+	}
+	return result, nil
+}
+
+// scratchNamespace returns the scratch namespace of the namespace, creating
+// it with copies of its policies and service accounts when needed.
+func (t *Tester) scratchNamespace(namespace string) (string, error) {
+	if ns, ok := t.scratch[namespace]; ok {
+		return ns, nil
+	}
+	core := t.client.Core()
+	created, err := core.Namespaces().Create(&v1.Namespace{ObjectMeta: v1.ObjectMeta{GenerateName: scratchNamespacePrefix}})
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating a scratch namespace")
+	}
+	ns := created.Name
+	t.scratch[namespace] = ns
+
+	limitRanges, err := core.LimitRanges(namespace).List(v1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Error listing the limit ranges of %s", namespace)
+	}
+	for _, l := range limitRanges.Items {
+		l.ObjectMeta = copyMeta(l.ObjectMeta, ns)
+		if _, err := core.LimitRanges(ns).Create(&l); err != nil {
+			return "", errors.Wrapf(err, "Error copying limit range %s", l.Name)
+		}
+	}
+	quotas, err := core.ResourceQuotas(namespace).List(v1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Error listing the resource quotas of %s", namespace)
+	}
+	for _, q := range quotas.Items {
+		q.ObjectMeta = copyMeta(q.ObjectMeta, ns)
+		q.Status = v1.ResourceQuotaStatus{}
+		if _, err := core.ResourceQuotas(ns).Create(&q); err != nil {
+			return "", errors.Wrapf(err, "Error copying resource quota %s", q.Name)
+		}
+	}
+	accounts, err := core.ServiceAccounts(namespace).List(v1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Error listing the service accounts of %s", namespace)
+	}
+	names := []string{"default"}
+	for _, a := range accounts.Items {
+		if a.Name == "default" {
+			continue
+		}
+		a.ObjectMeta = copyMeta(a.ObjectMeta, ns)
+		a.Secrets = nil
+		if _, err := core.ServiceAccounts(ns).Create(&a); err != nil {
+			return "", errors.Wrapf(err, "Error copying service account %s", a.Name)
+		}
+		names = append(names, a.Name)
+	}
+
+	// The service account admission rejects pods until the token of their
+	// service account is created
+	tokens := func() error {
+		for _, name := range names {
+			a, err := core.ServiceAccounts(ns).Get(name)
+			if err != nil {
+				return err
+			}
+			if len(a.Secrets) == 0 {
+				return fmt.Errorf("No token for service account %s yet", name)
+			}
+		}
+		return nil
+	}
+	if err := util.RetryAfter(30, tokens, time.Second); err != nil {
+		return "", errors.Wrap(err, "Error waiting for the tokens of the service accounts")
+	}
+	return ns, nil
+}
+
+func copyMeta(meta v1.ObjectMeta, namespace string) v1.ObjectMeta {
+	return v1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+// Cleanup deletes the scratch namespaces, along with the objects tested.
+func (t *Tester) Cleanup() error {
+	var failed []string
+	for _, ns := range t.scratch {
+		if err := t.client.Core().Namespaces().Delete(ns, nil); err != nil && !apierrors.IsNotFound(err) {
+			failed = append(failed, ns)
+		}
+	}
+	t.scratch = map[string]string{}
+	if len(failed) > 0 {
+		return fmt.Errorf("Error deleting the scratch namespaces %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
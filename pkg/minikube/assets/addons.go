@@ -17,18 +17,49 @@ limitations under the License.
 package assets
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/policy"
 	"k8s.io/minikube/pkg/util"
 )
 
+// AddonValuePrefix prefixes the config properties holding template values
+// for addon manifests, e.g. addon.ingress.backendReplicas
+const AddonValuePrefix = "addon."
+
+// AddonApplyModeSetting selects how addons are applied: with "ssh", the default,
+// their manifests are copied into the VM for the addon-manager to pick up,
+// with "api" they are applied directly through the apiserver.
+const AddonApplyModeSetting = "addon-apply-mode"
+
+// Addon apply modes
+const (
+	ApplyModeSSH = "ssh"
+	ApplyModeAPI = "api"
+)
+
+// ApplyViaAPI returns whether addons are applied through the apiserver
+func ApplyViaAPI() bool {
+	mode, err := config.GetWithEnv(AddonApplyModeSetting)
+	return err == nil && mode == ApplyModeAPI
+}
+
 type Addon struct {
-	Assets    []*MemoryAsset
+	Assets []*MemoryAsset
+	// Images maps the names of the addon images to their default repository and
+	// tag, Registries to the registry they are pulled from
+	Images     map[string]string
+	Registries map[string]string
+	// defaults returns the template values used when they are not set
+	defaults  func() map[string]string
+	chart     *HelmChart
 	enabled   bool
 	addonName string
 }
@@ -42,8 +73,127 @@ func NewAddon(assets []*MemoryAsset, enabled bool, addonName string) *Addon {
 	return a
 }
 
+// withImages sets the images referenced by the addon manifests, which can be
+// overridden through the images and registries addon values.
+func (a *Addon) withImages(images, registries map[string]string) *Addon {
+	a.Images = images
+	a.Registries = registries
+	return a
+}
+
+// withDefaults sets the template values of the addon which depend on the
+// cluster, used unless they are set in the config.
+func (a *Addon) withDefaults(defaults func() map[string]string) *Addon {
+	a.defaults = defaults
+	return a
+}
+
+// Values returns the template values set for the addon in the minikube config,
+// keyed by the part of the property name following AddonValuePrefix and the addon name.
+func (a *Addon) Values() (map[string]string, error) {
+	m, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	prefix := AddonValuePrefix + a.addonName + "."
+	values := map[string]string{}
+	if a.defaults != nil {
+		for k, v := range a.defaults() {
+			values[k] = v
+		}
+	}
+	for k, v := range m {
+		if strings.HasPrefix(k, prefix) {
+			values[strings.TrimPrefix(k, prefix)] = fmt.Sprintf("%v", v)
+		}
+	}
+	return values, nil
+}
+
+// NewHelmAddon creates an addon whose manifests are rendered from a helm chart
+// instead of being bundled with minikube.
+func NewHelmAddon(chart *HelmChart, enabled bool, addonName string) *Addon {
+	return &Addon{
+		chart:     chart,
+		enabled:   enabled,
+		addonName: addonName,
+	}
+}
+
+// GetAssets returns the files which make up the addon.
+// For addons backed by a helm chart this renders the chart.
+func (a *Addon) GetAssets() ([]CopyableFile, error) {
+	assets, err := a.renderAssets()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]CopyableFile, 0, len(assets))
+	for _, f := range assets {
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// GetTargets returns the files the addon writes into the VM, without rendering
+// them, for them to be deleted. For addons backed by a helm chart this is the
+// manifest the chart is rendered into, so that helm isn't run again.
+func (a *Addon) GetTargets() []CopyableFile {
+	if a.chart != nil {
+		return []CopyableFile{a.chartTarget(nil)}
+	}
+	files := make([]CopyableFile, 0, len(a.Assets))
+	for _, f := range a.Assets {
+		files = append(files, f)
+	}
+	return files
+}
+
+// chartTarget returns the asset holding the rendered chart of the addon.
+func (a *Addon) chartTarget(data []byte) *MemoryAsset {
+	return NewMemoryAssetFromBytes(data, constants.AddonsPath, a.addonName+"-chart.yaml", "0640")
+}
+
+func (a *Addon) renderAssets() ([]*MemoryAsset, error) {
+	if a.chart == nil {
+		values, err := a.Values()
+		if err != nil {
+			return nil, err
+		}
+		overrides := a.imageOverrides(values)
+		assets := make([]*MemoryAsset, 0, len(a.Assets))
+		for _, f := range a.Assets {
+			if isTemplate(f) {
+				if f, err = renderTemplate(f, values); err != nil {
+					return nil, err
+				}
+			}
+			assets = append(assets, rewriteImages(f, overrides))
+		}
+		return assets, nil
+	}
+	data, err := a.chart.Render(a.addonName)
+	if err != nil {
+		return nil, err
+	}
+	return []*MemoryAsset{a.chartTarget(data)}, nil
+}
+
+// EnabledByDefault returns whether the addon is enabled when it isn't set in the config.
+func (a *Addon) EnabledByDefault() bool {
+	return a.enabled
+}
+
+// IsEnabled returns whether the addon is enabled in the config, or by
+// default. The addons the policy of the machine disables never are.
 func (a *Addon) IsEnabled() (bool, error) {
-	addonStatusText, err := config.Get(a.addonName)
+	p, err := policy.Load()
+	if err != nil {
+		return false, err
+	}
+	if p.AddonDisabled(a.addonName) {
+		return false, nil
+	}
+	addonStatusText, err := config.GetWithEnv(a.addonName)
 	if err == nil {
 		addonStatus, err := strconv.ParseBool(addonStatusText)
 		if err != nil {
@@ -54,6 +204,17 @@ func (a *Addon) IsEnabled() (bool, error) {
 	return a.enabled, nil
 }
 
+// kubeDNSDefaults gives the service of kube-dns the address the kubelets
+// point the pods to, in the service network of the cluster.
+func kubeDNSDefaults() map[string]string {
+	_, dns, err := util.ServiceIPs(config.ServiceClusterIPRange())
+	if err != nil {
+		glog.Errorln("Error getting the cluster IP of kube-dns: ", err)
+		_, dns, _ = util.ServiceIPs("")
+	}
+	return map[string]string{"clusterIP": dns.String()}
+}
+
 var Addons = map[string]*Addon{
 	"addon-manager": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
@@ -61,7 +222,11 @@ var Addons = map[string]*Addon{
 			"/etc/kubernetes/manifests/",
 			"addon-manager.yaml",
 			"0640"),
-	}, true, "addon-manager"),
+	}, true, "addon-manager").withImages(map[string]string{
+		"AddonManager": "google-containers/kube-addon-manager:v6.3",
+	}, map[string]string{
+		"AddonManager": "gcr.io",
+	}),
 	"dashboard": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/dashboard/dashboard-rc.yaml",
@@ -73,7 +238,11 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"dashboard-svc.yaml",
 			"0640"),
-	}, true, "dashboard"),
+	}, true, "dashboard").withImages(map[string]string{
+		"Dashboard": "google_containers/kubernetes-dashboard-amd64:v1.5.1",
+	}, map[string]string{
+		"Dashboard": "gcr.io",
+	}),
 	"default-storageclass": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/storageclass/storageclass.yaml",
@@ -83,16 +252,24 @@ var Addons = map[string]*Addon{
 	}, true, "default-storageclass"),
 	"kube-dns": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
-			"deploy/addons/kube-dns/kube-dns-rc.yaml",
+			"deploy/addons/kube-dns/kube-dns-rc.yaml.tmpl",
 			constants.AddonsPath,
 			"kube-dns-rc.yaml",
 			"0640"),
 		NewMemoryAsset(
-			"deploy/addons/kube-dns/kube-dns-svc.yaml",
+			"deploy/addons/kube-dns/kube-dns-svc.yaml.tmpl",
 			constants.AddonsPath,
 			"kube-dns-svc.yaml",
 			"0640"),
-	}, true, "kube-dns"),
+	}, true, "kube-dns").withImages(map[string]string{
+		"KubeDNS":     "google_containers/kubedns-amd64:1.9",
+		"DNSMasq":     "google_containers/kube-dnsmasq-amd64:1.4",
+		"ExecHealthz": "google_containers/exechealthz-amd64:1.2",
+	}, map[string]string{
+		"KubeDNS":     "gcr.io",
+		"DNSMasq":     "gcr.io",
+		"ExecHealthz": "gcr.io",
+	}).withDefaults(kubeDNSDefaults),
 	"heapster": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/heapster/influxGrafana-rc.yaml",
@@ -119,7 +296,14 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"heapster-svc.yaml",
 			"0640"),
-	}, false, "heapster"),
+	}, false, "heapster").withImages(map[string]string{
+		"InfluxDB": "kubernetes/heapster_influxdb:v0.6",
+		"Grafana":  "google_containers/heapster_grafana:v2.6.0-2",
+		"Heapster": "google_containers/heapster:v1.3.0",
+	}, map[string]string{
+		"Grafana":  "gcr.io",
+		"Heapster": "gcr.io",
+	}),
 	"ingress": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/ingress/ingress-configmap.yaml",
@@ -127,23 +311,47 @@ var Addons = map[string]*Addon{
 			"ingress-configmap.yaml",
 			"0640"),
 		NewMemoryAsset(
-			"deploy/addons/ingress/ingress-rc.yaml",
+			"deploy/addons/ingress/ingress-rc.yaml.tmpl",
 			constants.AddonsPath,
 			"ingress-rc.yaml",
 			"0640"),
 		NewMemoryAsset(
-			"deploy/addons/ingress/ingress-svc.yaml",
+			"deploy/addons/ingress/ingress-svc.yaml.tmpl",
 			constants.AddonsPath,
 			"ingress-svc.yaml",
 			"0640"),
-	}, false, "ingress"),
+	}, false, "ingress").withImages(map[string]string{
+		"DefaultBackend": "google_containers/defaultbackend:1.0",
+		"Controller":     "google_containers/nginx-ingress-controller:0.9.0-beta.3",
+	}, map[string]string{
+		"DefaultBackend": "gcr.io",
+		"Controller":     "gcr.io",
+	}),
+	"ingress-dns": NewAddon([]*MemoryAsset{
+		NewMemoryAsset(
+			"deploy/addons/ingress-dns/ingress-dns-rc.yaml.tmpl",
+			constants.AddonsPath,
+			"ingress-dns-rc.yaml",
+			"0640"),
+	}, false, "ingress-dns").withImages(map[string]string{
+		"DNSMasq": "google_containers/kube-dnsmasq-amd64:1.4",
+	}, map[string]string{
+		"DNSMasq": "gcr.io",
+	}),
 	"registry-creds": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/registry-creds/registry-creds-rc.yaml",
 			constants.AddonsPath,
 			"registry-creds-rc.yaml",
 			"0640"),
-	}, false, "registry-creds"),
+	}, false, "registry-creds").withImages(map[string]string{
+		"RegistryCreds": "upmcenterprises/registry-creds:1.7",
+	}, nil),
+	// The auto-pause proxy runs on the host, started by minikube start
+	"auto-pause": NewAddon(nil, false, "auto-pause"),
+	// The Secret of the cloud credentials is kept in sync by minikube
+	// cloud-creds, run on the host by minikube start
+	"cloud-creds": NewAddon(nil, false, "cloud-creds"),
 }
 
 func AddMinikubeAddonsDirToAssets(assetList *[]CopyableFile) {
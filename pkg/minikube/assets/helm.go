@@ -0,0 +1,67 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// HelmChart is a reference to a helm chart which is rendered into
+// manifests on the host when the addon backed by it is enabled.
+type HelmChart struct {
+	// Chart is the chart reference passed to helm, e.g. stable/cert-manager
+	Chart     string
+	Version   string
+	Namespace string
+	Values    map[string]string
+}
+
+// args returns the arguments for rendering the chart with `helm template`.
+func (c *HelmChart) args(releaseName string) []string {
+	args := []string{"template", releaseName, c.Chart}
+	if c.Namespace != "" {
+		args = append(args, "--namespace", c.Namespace)
+	}
+	if c.Version != "" {
+		args = append(args, "--version", c.Version)
+	}
+	keys := make([]string, 0, len(c.Values))
+	for k := range c.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%s", k, c.Values[k]))
+	}
+	return args
+}
+
+// Render renders the chart into a single manifest using the helm binary on the host.
+func (c *HelmChart) Render(releaseName string) ([]byte, error) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		return nil, errors.Wrapf(err, "helm is required to render chart %s", c.Chart)
+	}
+	out, err := exec.Command("helm", c.args(releaseName)...).Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error rendering chart %s", c.Chart)
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestHelmChartArgs(t *testing.T) {
+	c := &HelmChart{
+		Chart:     "stable/cert-manager",
+		Version:   "0.1.0",
+		Namespace: "kube-system",
+		Values: map[string]string{
+			"replicaCount": "2",
+			"image.tag":    "v0.1.0",
+		},
+	}
+	expected := []string{
+		"template", "cert-manager", "stable/cert-manager",
+		"--namespace", "kube-system",
+		"--version", "0.1.0",
+		"--set", "image.tag=v0.1.0",
+		"--set", "replicaCount=2",
+	}
+	if args := c.args("cert-manager"); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Expected args %v, got %v", expected, args)
+	}
+}
+
+// fakeHelm puts a helm printing its arguments first in the PATH, or no helm
+// at all when script is empty.
+func fakeHelm(t *testing.T, script string) func() {
+	dir, err := ioutil.TempDir("", "helm")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	if script != "" {
+		if err := ioutil.WriteFile(filepath.Join(dir, "helm"), []byte(script), 0755); err != nil {
+			t.Fatalf("Error writing the fake helm: %s", err)
+		}
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir)
+	return func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestHelmAddon(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake helm is a shell script")
+	}
+	addon := NewHelmAddon(&HelmChart{Chart: "stable/cert-manager", Version: "0.1.0"}, false, "cert-manager")
+	target := filepath.Join(constants.AddonsPath, "cert-manager-chart.yaml")
+
+	defer fakeHelm(t, "#!/bin/sh\necho \"# $@\"\n")()
+	files, err := addon.GetAssets()
+	if err != nil {
+		t.Fatalf("Unexpected error rendering the chart: %s", err)
+	}
+	if len(files) != 1 || filepath.Join(files[0].GetTargetDir(), files[0].GetTargetName()) != target {
+		t.Fatalf("Expected the chart to be rendered into %s, got %v", target, files)
+	}
+	data, err := ioutil.ReadAll(files[0])
+	if err != nil {
+		t.Fatalf("Error reading the rendered chart: %s", err)
+	}
+	if expected := "# template cert-manager stable/cert-manager --version 0.1.0\n"; string(data) != expected {
+		t.Errorf("Expected the output of helm %q, got %q", expected, data)
+	}
+
+	defer fakeHelm(t, "#!/bin/sh\nexit 1\n")()
+	if _, err := addon.GetAssets(); err == nil {
+		t.Errorf("Expected an error when helm fails")
+	}
+
+	// Deleting the addon doesn't need helm
+	defer fakeHelm(t, "")()
+	if _, err := addon.GetAssets(); err == nil {
+		t.Errorf("Expected an error without helm")
+	}
+	targets := addon.GetTargets()
+	if len(targets) != 1 || filepath.Join(targets[0].GetTargetDir(), targets[0].GetTargetName()) != target {
+		t.Errorf("Expected the target %s, got %v", target, targets)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Addon values with these prefixes override the repository and registry of the
+// addon image with the given name, e.g. addon.ingress.images.Controller
+const (
+	ImageValuePrefix    = "images."
+	RegistryValuePrefix = "registries."
+)
+
+// hasRegistry returns whether the image reference starts with a registry host,
+// which like for docker is a first component with a dot or a port, or localhost.
+func hasRegistry(image string) bool {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return false
+	}
+	host := image[:i]
+	return strings.ContainsAny(host, ".:") || host == "localhost"
+}
+
+// joinImage prefixes the image with the registry, unless the image already
+// names its registry.
+func joinImage(registry, image string) string {
+	if registry == "" || hasRegistry(image) {
+		return image
+	}
+	return registry + "/" + image
+}
+
+// ImageNames returns the names of the addon images which can be overridden.
+func (a *Addon) ImageNames() []string {
+	names := make([]string, 0, len(a.Images))
+	for name := range a.Images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// imageOverrides maps the default references of the addon images to the
+// references resulting from the image and registry overrides in values.
+func (a *Addon) imageOverrides(values map[string]string) map[string]string {
+	overrides := map[string]string{}
+	for name, image := range a.Images {
+		registry := a.Registries[name]
+		newImage, newRegistry := image, registry
+		if v, ok := values[ImageValuePrefix+name]; ok {
+			newImage = v
+		}
+		if v, ok := values[RegistryValuePrefix+name]; ok {
+			newRegistry = v
+		}
+		if newImage != image || newRegistry != registry {
+			overrides[joinImage(registry, image)] = joinImage(newRegistry, newImage)
+		}
+	}
+	return overrides
+}
+
+// rewriteImages replaces the image references in the asset according to overrides.
+func rewriteImages(f *MemoryAsset, overrides map[string]string) *MemoryAsset {
+	if len(overrides) == 0 {
+		return f
+	}
+	data := f.data
+	for old, new := range overrides {
+		data = bytes.Replace(data, []byte(old), []byte(new), -1)
+	}
+	return NewMemoryAssetFromBytes(data, f.TargetDir, f.TargetName, f.Permissions)
+}
+
+// Manifests returns the yaml documents making up the addon, after applying
+// the addon values and image overrides.
+func (a *Addon) Manifests() ([][]byte, error) {
+	assets, err := a.renderAssets()
+	if err != nil {
+		return nil, err
+	}
+	var manifests [][]byte
+	for _, f := range assets {
+		manifests = append(manifests, SplitManifests(f.data)...)
+	}
+	return manifests, nil
+}
+
+// SplitManifests returns the non-empty yaml documents of the data.
+func SplitManifests(data []byte) [][]byte {
+	var manifests [][]byte
+	for _, doc := range bytes.Split(data, []byte("\n---")) {
+		if len(bytes.TrimSpace(doc)) > 0 {
+			manifests = append(manifests, doc)
+		}
+	}
+	return manifests
+}
+
+// ReferencedImages returns the container images referenced by the addon
+// manifests, after applying the addon values and image overrides.
+func (a *Addon) ReferencedImages() ([]string, error) {
+	manifests, err := a.Manifests()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, doc := range manifests {
+		var manifest interface{}
+		if err := yaml.Unmarshal(doc, &manifest); err != nil {
+			return nil, errors.Wrap(err, "Error parsing manifest")
+		}
+		collectImages(manifest, seen)
+	}
+	images := make([]string, 0, len(seen))
+	for image := range seen {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// collectImages walks a parsed manifest, adding the images of all the
+// containers and init containers found in pod specs to images.
+func collectImages(node interface{}, images map[string]bool) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			if k == "containers" || k == "initContainers" {
+				if containers, ok := v.([]interface{}); ok {
+					for _, c := range containers {
+						if c, ok := c.(map[interface{}]interface{}); ok {
+							if image, ok := c["image"].(string); ok {
+								images[image] = true
+							}
+						}
+					}
+				}
+			}
+			collectImages(v, images)
+		}
+	case []interface{}:
+		for _, v := range n {
+			collectImages(v, images)
+		}
+	}
+}
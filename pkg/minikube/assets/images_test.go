@@ -0,0 +1,110 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestRewriteImages(t *testing.T) {
+	a := NewAddon(nil, false, "test").withImages(map[string]string{
+		"Controller": "google_containers/controller:1.0",
+	}, map[string]string{
+		"Controller": "gcr.io",
+	})
+	f := NewMemoryAssetFromBytes([]byte("image: gcr.io/google_containers/controller:1.0"), "/etc/kubernetes/addons", "rc.yaml", "0640")
+
+	var tests = []struct {
+		values   map[string]string
+		expected string
+	}{
+		{
+			values:   map[string]string{},
+			expected: "image: gcr.io/google_containers/controller:1.0",
+		},
+		{
+			values:   map[string]string{"images.Controller": "controller:1.2"},
+			expected: "image: gcr.io/controller:1.2",
+		},
+		{
+			values:   map[string]string{"registries.Controller": "myreg.local"},
+			expected: "image: myreg.local/google_containers/controller:1.0",
+		},
+		{
+			values: map[string]string{
+				"images.Controller":     "ingress:1.2",
+				"registries.Controller": "myreg.local",
+			},
+			expected: "image: myreg.local/ingress:1.2",
+		},
+		{
+			values: map[string]string{
+				"images.Controller":     "myreg.local/ingress:1.2",
+				"registries.Controller": "myreg.local",
+			},
+			expected: "image: myreg.local/ingress:1.2",
+		},
+		{
+			values:   map[string]string{"images.Controller": "localhost:5000/ingress:1.2"},
+			expected: "image: localhost:5000/ingress:1.2",
+		},
+	}
+
+	for _, test := range tests {
+		rewritten := rewriteImages(f, a.imageOverrides(test.values))
+		b, _ := ioutil.ReadAll(rewritten)
+		if string(b) != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, string(b))
+		}
+	}
+}
+
+func TestReferencedImages(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: ReplicationController
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.26
+      containers:
+      - name: controller
+        image: gcr.io/google_containers/controller:1.0
+---
+apiVersion: v1
+kind: Pod
+spec:
+  containers:
+  - name: sidecar
+    image: busybox:1.26
+`
+	a := NewAddon([]*MemoryAsset{
+		NewMemoryAssetFromBytes([]byte(manifest), "/etc/kubernetes/addons", "rc.yaml", "0640"),
+	}, false, "test")
+
+	images, err := a.ReferencedImages()
+	if err != nil {
+		t.Fatalf("Unexpected error listing images: %s", err)
+	}
+	expected := []string{"busybox:1.26", "gcr.io/google_containers/controller:1.0"}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("Expected images %v, got %v", expected, images)
+	}
+}
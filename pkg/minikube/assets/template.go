@@ -0,0 +1,78 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Assets with this suffix are rendered as go templates before being copied
+const templateSuffix = ".tmpl"
+
+var templateFuncs = template.FuncMap{
+	// default returns def when val is unset, e.g. {{ default "1" .Values.replicas }}
+	"default": func(def, val string) string {
+		if val == "" {
+			return def
+		}
+		return val
+	},
+	// quote returns val as a double-quoted yaml string, e.g. clusterIP: {{ quote .Values.clusterIP }},
+	// so that the value can't change the structure of the manifest
+	"quote": func(val string) string {
+		return strconv.Quote(val)
+	},
+	// int fails the rendering when val is not an integer, e.g. replicas: {{ int .Values.replicas }}
+	"int": func(val string) (int, error) {
+		return strconv.Atoi(val)
+	},
+}
+
+func isTemplate(f *MemoryAsset) bool {
+	return strings.HasSuffix(f.AssetName, templateSuffix)
+}
+
+// renderTemplate executes the asset as a template, exposing values as .Values.
+// Values with a newline or another control character are rejected, they could
+// add lines to the manifest.
+func renderTemplate(f *MemoryAsset, values map[string]string) (*MemoryAsset, error) {
+	for k, v := range values {
+		if strings.IndexFunc(v, unicode.IsControl) >= 0 {
+			return nil, errors.Errorf("The value of %s for template %s contains a newline or a control character", k, f.AssetName)
+		}
+	}
+	t, err := template.New(f.AssetName).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(f.data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing template %s", f.AssetName)
+	}
+	var buf bytes.Buffer
+	data := struct {
+		Values map[string]string
+	}{
+		Values: values,
+	}
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, errors.Wrapf(err, "Error executing template %s", f.AssetName)
+	}
+	return NewMemoryAssetFromBytes(buf.Bytes(), f.TargetDir, f.TargetName, f.Permissions), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	f := NewMemoryAssetFromBytes([]byte(`replicas: {{ default "1" .Values.replicas | int }}
+name: {{ default "a" .Values.name | quote }}`), "/etc/kubernetes/addons", "rc.yaml", "0640")
+	f.AssetName = "rc.yaml.tmpl"
+
+	var tests = []struct {
+		values   map[string]string
+		expected string
+	}{
+		{
+			values:   map[string]string{},
+			expected: "replicas: 1\nname: \"a\"",
+		},
+		{
+			values:   map[string]string{"replicas": "3"},
+			expected: "replicas: 3\nname: \"a\"",
+		},
+		{
+			values:   map[string]string{"replicas": "3", "name": "a: b # c"},
+			expected: "replicas: 3\nname: \"a: b # c\"",
+		},
+	}
+
+	for _, test := range tests {
+		rendered, err := renderTemplate(f, test.values)
+		if err != nil {
+			t.Fatalf("Unexpected error rendering template: %s", err)
+		}
+		b, _ := ioutil.ReadAll(rendered)
+		if string(b) != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, string(b))
+		}
+		if rendered.GetTargetName() != "rc.yaml" {
+			t.Errorf("Expected target name rc.yaml, got %s", rendered.GetTargetName())
+		}
+	}
+}
+
+func TestRenderTemplateRejectsValues(t *testing.T) {
+	f := NewMemoryAssetFromBytes([]byte(`replicas: {{ int .Values.replicas }}
+name: {{ quote .Values.name }}`), "/etc/kubernetes/addons", "rc.yaml", "0640")
+	f.AssetName = "rc.yaml.tmpl"
+
+	for _, values := range []map[string]string{
+		{"replicas": "1", "name": "a\nkind: Secret"},
+		{"replicas": "1", "name": "a\rb"},
+		{"replicas": "1\nhostNetwork: true", "name": "a"},
+		{"replicas": "one", "name": "a"},
+	} {
+		if _, err := renderTemplate(f, values); err == nil {
+			t.Errorf("Expected an error rendering %v", values)
+		}
+	}
+}
+
+func TestKubeDNSDefaults(t *testing.T) {
+	defer viper.Reset()
+	for cidr, expected := range map[string]string{
+		"":              "10.0.0.10",
+		"172.17.0.0/16": "172.17.0.10",
+	} {
+		viper.Set(config.ServiceClusterIPRangeFlag, cidr)
+		if actual := kubeDNSDefaults()["clusterIP"]; actual != expected {
+			t.Errorf("Expected the cluster IP %s for %q, got %s", expected, cidr, actual)
+		}
+	}
+}
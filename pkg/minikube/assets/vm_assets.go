@@ -18,8 +18,12 @@ package assets
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -31,6 +35,7 @@ type CopyableFile interface {
 	GetTargetDir() string
 	GetTargetName() string
 	GetPermissions() string
+	GetChecksum() string
 }
 
 type BaseAsset struct {
@@ -93,6 +98,21 @@ func (f *FileAsset) GetLength() int {
 	return int(fi.Size())
 }
 
+// GetChecksum returns the hex encoded sha256 of the file contents, or an
+// empty string if the file can't be read.
+func (f *FileAsset) GetChecksum() string {
+	file, err := os.Open(f.AssetName)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (f *FileAsset) Read(p []byte) (int, error) {
 	if f.reader == nil {
 		return 0, errors.New("Error attempting FileAsset.Read, FileAsset.reader uninitialized")
@@ -117,6 +137,22 @@ func NewMemoryAsset(assetName, targetDir, targetName, permissions string) *Memor
 	return m
 }
 
+// NewMemoryAssetFromBytes creates an asset from data generated at runtime
+// rather than from a file bundled into the binary.
+func NewMemoryAssetFromBytes(data []byte, targetDir, targetName, permissions string) *MemoryAsset {
+	return &MemoryAsset{
+		BaseAsset{
+			data:        data,
+			reader:      bytes.NewReader(data),
+			Length:      len(data),
+			AssetName:   targetName,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+	}
+}
+
 func (m *MemoryAsset) loadData() error {
 	contents, err := Asset(m.AssetName)
 	if err != nil {
@@ -132,6 +168,38 @@ func (m *MemoryAsset) GetLength() int {
 	return m.Length
 }
 
+// GetChecksum returns the hex encoded sha256 of the asset contents.
+func (m *MemoryAsset) GetChecksum() string {
+	sum := sha256.Sum256(m.data)
+	return hex.EncodeToString(sum[:])
+}
+
 func (m *MemoryAsset) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
+
+// CopyFileLocal writes the file to its target on this machine, for the none
+// driver which runs the cluster on it.
+func CopyFileLocal(f CopyableFile) error {
+	perm, err := strconv.ParseUint(f.GetPermissions(), 8, 32)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing the permissions of %s", f.GetAssetName())
+	}
+	if err := os.MkdirAll(f.GetTargetDir(), 0755); err != nil {
+		return errors.Wrapf(err, "Error creating %s", f.GetTargetDir())
+	}
+	// Removed first for the permissions to be reset, as they are in the VM
+	target := filepath.Join(f.GetTargetDir(), f.GetTargetName())
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Error removing %s", target)
+	}
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(perm))
+	if err != nil {
+		return errors.Wrapf(err, "Error creating %s", target)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, f); err != nil {
+		return errors.Wrapf(err, "Error writing %s", target)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autopause pauses the cluster while the apiserver is idle, resuming
+// it when a client connects again.
+package autopause
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Listener wraps the listener of a proxy to the apiserver. The cluster is
+// unpaused before a connection is handed out, and paused by PauseIfIdle once
+// no connection was open for the idle interval.
+type Listener struct {
+	net.Listener
+	idle    time.Duration
+	pause   func() error
+	unpause func() error
+
+	mu         sync.Mutex
+	open       int
+	lastActive time.Time
+	paused     bool
+}
+
+// NewListener returns the listener wrapping l, for a running cluster.
+func NewListener(l net.Listener, idle time.Duration, pause, unpause func() error) *Listener {
+	return &Listener{
+		Listener:   l,
+		idle:       idle,
+		pause:      pause,
+		unpause:    unpause,
+		lastActive: time.Now(),
+	}
+}
+
+// Accept waits for the next connection, and unpauses the cluster if needed
+// before returning it.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.paused {
+		if err := l.unpause(); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "Error unpausing the cluster")
+		}
+		l.paused = false
+	}
+	l.open++
+	return &trackedConn{Conn: conn, l: l}, nil
+}
+
+// PauseIfIdle pauses the cluster if no connection was open for the idle
+// interval, and reports whether it did.
+func (l *Listener) PauseIfIdle(now time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.paused || l.open > 0 || now.Sub(l.lastActive) < l.idle {
+		return false, nil
+	}
+	if err := l.pause(); err != nil {
+		return false, errors.Wrap(err, "Error pausing the cluster")
+	}
+	l.paused = true
+	return true, nil
+}
+
+func (l *Listener) closed() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.open--
+	l.lastActive = time.Now()
+}
+
+// trackedConn lets the listener know when the connection is closed.
+type trackedConn struct {
+	net.Conn
+	l    *Listener
+	once sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.l.closed)
+	return c.Conn.Close()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autopause
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	var pauses, unpauses int
+	l := NewListener(inner, time.Minute,
+		func() error { pauses++; return nil },
+		func() error { unpauses++; return nil })
+	defer l.Close()
+
+	accept := func() net.Conn {
+		client, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("Error connecting: %s", err)
+		}
+		defer client.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			t.Fatalf("Unexpected error accepting: %s", err)
+		}
+		return conn
+	}
+
+	if paused, _ := l.PauseIfIdle(time.Now()); paused {
+		t.Fatalf("Expected the cluster not to be paused before the idle interval")
+	}
+	conn := accept()
+	if paused, _ := l.PauseIfIdle(time.Now().Add(time.Hour)); paused {
+		t.Fatalf("Expected the cluster not to be paused while a connection is open")
+	}
+	conn.Close()
+	conn.Close()
+	if paused, _ := l.PauseIfIdle(time.Now()); paused {
+		t.Fatalf("Expected the idle interval to restart once the connection closed")
+	}
+	if paused, err := l.PauseIfIdle(time.Now().Add(time.Hour)); err != nil || !paused {
+		t.Fatalf("Expected the idle cluster to be paused, got %t, %v", paused, err)
+	}
+	if paused, _ := l.PauseIfIdle(time.Now().Add(2 * time.Hour)); paused {
+		t.Fatalf("Expected the paused cluster not to be paused again")
+	}
+
+	accept().Close()
+	accept().Close()
+	if pauses != 1 || unpauses != 1 {
+		t.Errorf("Expected the cluster to be paused and unpaused once, got %d and %d", pauses, unpauses)
+	}
+}
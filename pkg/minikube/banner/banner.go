@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package banner renders the message printed at the end of minikube start,
+// which teams wrapping minikube customize with their own next steps, links
+// and follow-up commands.
+package banner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// Setting is the config setting holding the template of the message, which
+// can differ per profile. An empty template prints no message.
+const Setting = "start-message"
+
+// Info is what the message is rendered with.
+type Info struct {
+	// Profile is the active config profile, empty for the global config
+	Profile string
+	// Driver is the driver of the VM
+	Driver string
+	// IP is the address of the VM
+	IP                string
+	KubernetesVersion string
+	// Context is the kubeconfig context of the cluster
+	Context string
+	// Kubeconfig is the kubeconfig file holding the context
+	Kubeconfig string
+	// Server is the URL of the apiserver
+	Server string
+	// Addons are the enabled addons
+	Addons []string
+}
+
+// funcs are the functions the template can call besides the builtins.
+var funcs = template.FuncMap{
+	"join": strings.Join,
+}
+
+func parse(tmpl string) (*template.Template, error) {
+	t, err := template.New(Setting).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the template of %s", Setting)
+	}
+	return t, nil
+}
+
+// Validate checks that the template parses.
+func Validate(tmpl string) error {
+	_, err := parse(tmpl)
+	return err
+}
+
+// Print renders the template with the info to w, ending it with a newline.
+func Print(w io.Writer, tmpl string, info Info) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return nil
+	}
+	t, err := parse(tmpl)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, info); err != nil {
+		return errors.Wrapf(err, "Error rendering the template of %s", Setting)
+	}
+	msg := b.String()
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	_, err = fmt.Fprint(w, msg)
+	return err
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package banner
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	info := Info{
+		Profile:           "team",
+		Driver:            "virtualbox",
+		IP:                "192.168.99.100",
+		KubernetesVersion: "v1.5.3",
+		Context:           "minikube",
+		Server:            "https://192.168.99.100:8443",
+		Addons:            []string{"dashboard", "ingress"},
+	}
+	var tcs = []struct {
+		description string
+		tmpl        string
+		expected    string
+		shouldErr   bool
+	}{
+		{description: "empty", tmpl: "", expected: ""},
+		{description: "blank", tmpl: "  \n", expected: ""},
+		{
+			description: "fields",
+			tmpl:        "Next: run ./bootstrap.sh {{.Context}} against {{.Server}}",
+			expected:    "Next: run ./bootstrap.sh minikube against https://192.168.99.100:8443\n",
+		},
+		{
+			description: "join",
+			tmpl:        "Addons: {{join .Addons \", \"}}\nDocs: https://wiki.example.com/minikube\n",
+			expected:    "Addons: dashboard, ingress\nDocs: https://wiki.example.com/minikube\n",
+		},
+		{description: "unknown field", tmpl: "{{.Token}}", shouldErr: true},
+		{description: "invalid", tmpl: "{{.Context", shouldErr: true},
+	}
+	for _, test := range tcs {
+		var b bytes.Buffer
+		err := Print(&b, test.tmpl, info)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s: %v", test.description, err)
+			continue
+		}
+		if !test.shouldErr && b.String() != test.expected {
+			t.Errorf("Expected %q for %s, got %q", test.expected, test.description, b.String())
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("{{if .Addons}}{{join .Addons \",\"}}{{end}}"); err != nil {
+		t.Errorf("Unexpected error validating a template: %s", err)
+	}
+	if err := Validate("{{range}}"); err == nil {
+		t.Errorf("Expected an error for an invalid template")
+	}
+}
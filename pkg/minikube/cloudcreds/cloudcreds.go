@@ -0,0 +1,237 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudcreds passes the cloud credentials of the host to the
+// workloads of the cluster, in a well-known Secret kept in sync with them, so
+// that locally developed workloads can call the APIs of the cloud.
+package cloudcreds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/kubernetes/pkg/util/homedir"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+const (
+	// AddonName is the name of the addon, and of the daemon syncing the Secret
+	AddonName = "cloud-creds"
+	// SecretName is the name of the Secret holding the credentials
+	SecretName = "cloud-creds"
+	// DefaultNamespace is the namespace of the Secret, unless the namespace
+	// value of the addon is set
+	DefaultNamespace = "default"
+)
+
+// The providers whose credentials are passed through
+const (
+	GCP   = "gcp"
+	AWS   = "aws"
+	Azure = "azure"
+)
+
+// Providers are the valid values of the provider of the addon.
+var Providers = []string{GCP, AWS, Azure}
+
+// ValidateProvider checks that the provider is known.
+func ValidateProvider(provider string) error {
+	for _, p := range Providers {
+		if p == provider {
+			return nil
+		}
+	}
+	return errors.Errorf("Unknown provider %q, the providers are %v", provider, Providers)
+}
+
+// Config returns the provider and the namespace of the Secret set for the
+// addon.
+func Config() (provider, namespace string, err error) {
+	values, err := assets.Addons[AddonName].Values()
+	if err != nil {
+		return "", "", err
+	}
+	namespace = values["namespace"]
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	provider = values["provider"]
+	if provider == "" {
+		return "", namespace, errors.Errorf("No provider is set, enable the addon with minikube addons enable %s --provider=gcp|aws|azure", AddonName)
+	}
+	return provider, namespace, ValidateProvider(provider)
+}
+
+// Host is where the credentials are read from: the environment and the home
+// directory of the user running minikube.
+type Host struct {
+	Getenv func(string) string
+	Home   string
+}
+
+// LocalHost returns the host minikube runs on.
+func LocalHost() Host {
+	return Host{Getenv: os.Getenv, Home: homedir.HomeDir()}
+}
+
+// awsEnv are the variables of the AWS SDKs passed through when set
+var awsEnv = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE"}
+
+// azureEnv are the variables of the service principal of the Azure SDKs,
+// the first three are required
+var azureEnv = []string{"AZURE_CLIENT_ID", "AZURE_TENANT_ID", "AZURE_CLIENT_SECRET", "AZURE_SUBSCRIPTION_ID"}
+
+// Read returns the data of the Secret for the provider, read from the host:
+//
+// gcp: credentials.json, the application default credentials of gcloud, and
+// GOOGLE_CLOUD_PROJECT when set.
+// aws: the credentials and config files of the AWS CLI, and the variables of
+// the AWS SDKs which are set.
+// azure: the service principal in the AZURE_* variables of the Azure SDKs.
+func (h Host) Read(provider string) (map[string]string, error) {
+	data := map[string]string{}
+	switch provider {
+	case GCP:
+		path := h.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		if path == "" {
+			path = filepath.Join(h.Home, ".config", "gcloud", "application_default_credentials.json")
+			if appData := h.Getenv("APPDATA"); appData != "" {
+				path = filepath.Join(appData, "gcloud", "application_default_credentials.json")
+			}
+		}
+		creds, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading the application default credentials, create them with gcloud auth application-default login")
+		}
+		data["credentials.json"] = string(creds)
+		if project := h.Getenv("GOOGLE_CLOUD_PROJECT"); project != "" {
+			data["GOOGLE_CLOUD_PROJECT"] = project
+		}
+	case AWS:
+		files := map[string]string{
+			"credentials": h.awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials"),
+			"config":      h.awsFile("AWS_CONFIG_FILE", "config"),
+		}
+		for key, path := range files {
+			content, err := ioutil.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error reading %s", path)
+			}
+			data[key] = string(content)
+		}
+		for _, v := range awsEnv {
+			if value := h.Getenv(v); value != "" {
+				data[v] = value
+			}
+		}
+		if data["credentials"] == "" && data["AWS_ACCESS_KEY_ID"] == "" {
+			return nil, errors.New("No AWS credentials found, in ~/.aws/credentials nor AWS_ACCESS_KEY_ID, configure them with aws configure")
+		}
+	case Azure:
+		for i, v := range azureEnv {
+			value := h.Getenv(v)
+			if value == "" && i < 3 {
+				return nil, errors.Errorf("%s is not set, the Azure credentials are the service principal in AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_CLIENT_SECRET", v)
+			}
+			if value != "" {
+				data[v] = value
+			}
+		}
+	default:
+		return nil, ValidateProvider(provider)
+	}
+	return data, nil
+}
+
+func (h Host) awsFile(env, name string) string {
+	if path := h.Getenv(env); path != "" {
+		return path
+	}
+	return filepath.Join(h.Home, ".aws", name)
+}
+
+// Hash returns a digest of the data, to tell whether the credentials changed.
+func Hash(data map[string]string) string {
+	var keys []string
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sync writes the data to the Secret, replacing the previous credentials.
+func Sync(namespace, provider string, data map[string]string) error {
+	labels := map[string]string{
+		"app":                           AddonName,
+		"cloud":                         provider,
+		"kubernetes.io/minikube-addons": AddonName,
+	}
+	if err := service.CreateSecret(namespace, SecretName, data, labels); err != nil {
+		return errors.Wrapf(err, "Error writing the secret %s/%s", namespace, SecretName)
+	}
+	return nil
+}
+
+// Remove deletes the Secret.
+func Remove(namespace string) error {
+	return service.DeleteSecret(namespace, SecretName)
+}
+
+// StartSync runs minikube cloud-creds in the background, replacing the one
+// of a previous start, to keep the Secret in sync with the credentials of the
+// host.
+func StartSync() error {
+	if err := StopSync(); err != nil {
+		return err
+	}
+	logPath := constants.MakeMiniPath("logs", AddonName+".log")
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening the log of cloud-creds")
+	}
+	defer logFile.Close()
+	sync := exec.Command(os.Args[0], AddonName)
+	sync.Stdout = logFile
+	sync.Stderr = logFile
+	if err := sync.Start(); err != nil {
+		return errors.Wrap(err, "Error starting cloud-creds")
+	}
+	return sync.Process.Release()
+}
+
+// StopSync stops the background sync of the credentials, if any.
+func StopSync() error {
+	_, err := daemons.Stop(AddonName)
+	return err
+}
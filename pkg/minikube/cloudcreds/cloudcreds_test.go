@@ -0,0 +1,115 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudcreds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	home, err := ioutil.TempDir("", "cloudcreds")
+	if err != nil {
+		t.Fatalf("Error creating the home: %s", err)
+	}
+	defer os.RemoveAll(home)
+	for path, content := range map[string]string{
+		".config/gcloud/application_default_credentials.json": `{"type": "authorized_user"}`,
+		".aws/credentials": "[default]\naws_access_key_id = AKIA\n",
+	} {
+		path = filepath.Join(home, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Error creating %s: %s", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", path, err)
+		}
+	}
+
+	var tests = []struct {
+		provider string
+		env      map[string]string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			provider: GCP,
+			env:      map[string]string{"GOOGLE_CLOUD_PROJECT": "dev"},
+			expected: map[string]string{"credentials.json": `{"type": "authorized_user"}`, "GOOGLE_CLOUD_PROJECT": "dev"},
+		},
+		{
+			provider: GCP,
+			env:      map[string]string{"GOOGLE_APPLICATION_CREDENTIALS": filepath.Join(home, "missing.json")},
+			err:      true,
+		},
+		{
+			provider: AWS,
+			env:      map[string]string{"AWS_REGION": "eu-west-1"},
+			expected: map[string]string{"credentials": "[default]\naws_access_key_id = AKIA\n", "AWS_REGION": "eu-west-1"},
+		},
+		{
+			provider: AWS,
+			env:      map[string]string{"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(home, "missing")},
+			err:      true,
+		},
+		{
+			provider: Azure,
+			env:      map[string]string{"AZURE_CLIENT_ID": "id", "AZURE_TENANT_ID": "tenant", "AZURE_CLIENT_SECRET": "secret"},
+			expected: map[string]string{"AZURE_CLIENT_ID": "id", "AZURE_TENANT_ID": "tenant", "AZURE_CLIENT_SECRET": "secret"},
+		},
+		{
+			provider: Azure,
+			env:      map[string]string{"AZURE_CLIENT_ID": "id"},
+			err:      true,
+		},
+		{
+			provider: "digitalocean",
+			err:      true,
+		},
+	}
+	for _, test := range tests {
+		env := test.env
+		h := Host{Getenv: func(k string) string { return env[k] }, Home: home}
+		data, err := h.Read(test.provider)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected an error reading %s with %v, got %v", test.provider, test.env, data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error reading %s with %v: %s", test.provider, test.env, err)
+			continue
+		}
+		if !reflect.DeepEqual(data, test.expected) {
+			t.Errorf("Expected %v reading %s, got %v", test.expected, test.provider, data)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	a := Hash(map[string]string{"a": "b", "c": "d"})
+	if a != Hash(map[string]string{"c": "d", "a": "b"}) {
+		t.Errorf("Expected the hash not to depend on the order of the keys")
+	}
+	if a == Hash(map[string]string{"a": "bc", "": "d"}) || a == Hash(map[string]string{"a": "b", "c": "e"}) {
+		t.Errorf("Expected other data to have another hash")
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io"
+	"os/exec"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+// LoadDockerImage loads the image archive read from r, as buildctl exports
+// it with --output type=docker, into the docker daemon of the host VM, where
+// the cluster runs its images: buildkit keeps the images it builds in its own
+// store.
+func LoadDockerImage(api libmachine.API, r io.Reader) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	if h.Driver.DriverName() == constants.DriverNone {
+		cmd := exec.Command("/bin/bash", "-c", loadDockerImageCommand)
+		cmd.Stdin = r
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "Error loading the image: %s", out)
+		}
+		return nil
+	}
+	client, err := sshutil.NewSSHClient(h.Driver)
+	if err != nil {
+		return errors.Wrap(err, "Error creating new ssh client")
+	}
+	if out, err := sshutil.RunCommandWithInput(client, loadDockerImageCommand, r); err != nil {
+		return errors.Wrapf(err, "Error loading the image: %s", out)
+	}
+	return nil
+}
@@ -22,7 +22,12 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,9 +40,14 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 
+	"k8s.io/minikube/pkg/drivers/container"
+	"k8s.io/minikube/pkg/drivers/none"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	sshdriver "k8s.io/minikube/pkg/drivers/ssh"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/constants"
-	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -45,7 +55,10 @@ var (
 	certs = []string{"ca.crt", "ca.key", "apiserver.crt", "apiserver.key"}
 )
 
-const fileScheme = "file"
+const (
+	fileScheme            = "file"
+	dockerPortForwardName = "docker"
+)
 
 //This init function is used to set the logtostderr variable to false so that INFO level log info does not clutter the CLI
 //INFO lvl logging is displayed due to the kubernetes api calling flag.Set("logtostderr", "true") in its init()
@@ -56,16 +69,37 @@ func init() {
 
 // StartHost starts a host VM.
 func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
-	exists, err := api.Exists(constants.MachineName)
+	name := config.machineName()
+	exists, err := api.Exists(name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error checking if host exists: %s", constants.MachineName)
+		return nil, errors.Wrapf(err, "Error checking if host exists: %s", name)
 	}
 	if !exists {
-		return createHost(api, config)
+		h, err := createHost(api, config)
+		if err != nil {
+			return h, err
+		}
+		// The provisioner started the docker daemon without its config
+		if config.DockerDaemonConfig != "" {
+			if err := configureDockerDaemon(h, config, true); err != nil {
+				return nil, err
+			}
+		}
+		if !guestStaticIP(config) {
+			return h, nil
+		}
+		if err := configureStaticIP(h, config); err != nil {
+			return nil, err
+		}
+		// The certificates of the docker daemon were made for the leased address
+		if err := h.ConfigureAuth(); err != nil {
+			return nil, &util.RetriableError{Err: errors.Wrap(err, "Error configuring auth on host")}
+		}
+		return h, nil
 	}
 
 	glog.Infoln("Machine exists!")
-	h, err := api.Load(constants.MachineName)
+	h, err := api.Load(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading existing host. Please try running [minikube delete], then run [minikube start] again.")
 	}
@@ -85,50 +119,115 @@ func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 		}
 	}
 
+	// The none driver uses the docker daemon of this machine as it is
+	if h.Driver.DriverName() == constants.DriverNone {
+		return h, nil
+	}
+	if err := configureStaticIP(h, config); err != nil {
+		return nil, err
+	}
+	if err := updateProxyEnv(api, h, config); err != nil {
+		return nil, err
+	}
+	// Configuring the auth restarts the docker daemon, which reads it
+	if err := configureDockerDaemon(h, config, false); err != nil {
+		return nil, err
+	}
 	if err := h.ConfigureAuth(); err != nil {
 		return nil, &util.RetriableError{Err: errors.Wrap(err, "Error configuring auth on host")}
 	}
 	return h, nil
 }
 
+// updateProxyEnv sets the proxies of the docker daemon of the existing host
+// to those of the config, which configuring the auth applies.
+func updateProxyEnv(api libmachine.API, h *host.Host, config MachineConfig) error {
+	if h.HostOptions == nil || h.HostOptions.EngineOptions == nil {
+		return nil
+	}
+	env := proxy.MergeEnv(h.HostOptions.EngineOptions.Env, config.DockerEnv)
+	if reflect.DeepEqual(env, h.HostOptions.EngineOptions.Env) {
+		return nil
+	}
+	h.HostOptions.EngineOptions.Env = env
+	return errors.Wrap(api.Save(h), "Error saving the proxies of the host")
+}
+
 // StopHost stops the host VM.
 func StopHost(api libmachine.API) error {
-	host, err := api.Load(constants.MachineName)
+	return stopHost(api, constants.MachineName)
+}
+
+func stopHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error loading host: %s", constants.MachineName)
+		return errors.Wrapf(err, "Error loading host: %s", name)
 	}
 	if err := host.Stop(); err != nil {
-		return errors.Wrapf(err, "Error stopping host: %s", constants.MachineName)
+		return errors.Wrapf(err, "Error stopping host: %s", name)
+	}
+	// The ssh driver records that the machine is stopped
+	if err := api.Save(host); err != nil {
+		return errors.Wrapf(err, "Error saving host: %s", name)
 	}
 	return nil
 }
 
 // DeleteHost deletes the host VM.
 func DeleteHost(api libmachine.API) error {
-	host, err := api.Load(constants.MachineName)
+	return deleteHost(api, constants.MachineName)
+}
+
+func deleteHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error deleting host: %s", constants.MachineName)
+		return errors.Wrapf(err, "Error deleting host: %s", name)
 	}
 	m := util.MultiError{}
 	m.Collect(host.Driver.Remove())
-	m.Collect(api.Remove(constants.MachineName))
+	m.Collect(api.Remove(name))
 	return m.ToError()
 }
 
+// DeleteAllHosts deletes every machine in the machines directory: the VM, those
+// of the nodes, and any machine left over. It returns the names of the
+// machines deleted, sorted, going on after errors.
+func DeleteAllHosts(api libmachine.API) ([]string, error) {
+	names, err := api.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing machines")
+	}
+	sort.Strings(names)
+	var deleted []string
+	m := util.MultiError{}
+	for _, name := range names {
+		if err := deleteHost(api, name); err != nil {
+			m.Collect(err)
+			continue
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, m.ToError()
+}
+
 // GetHostStatus gets the status of the host VM.
 func GetHostStatus(api libmachine.API) (string, error) {
+	return getHostStatus(api, constants.MachineName)
+}
+
+func getHostStatus(api libmachine.API, name string) (string, error) {
 	dne := "Does Not Exist"
-	exists, err := api.Exists(constants.MachineName)
+	exists, err := api.Exists(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error checking that api exists for: %s", constants.MachineName)
+		return "", errors.Wrapf(err, "Error checking that api exists for: %s", name)
 	}
 	if !exists {
 		return dne, nil
 	}
 
-	host, err := api.Load(constants.MachineName)
+	host, err := api.Load(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error loading api for: %s", constants.MachineName)
+		return "", errors.Wrapf(err, "Error loading api for: %s", name)
 	}
 
 	s, err := host.Driver.GetState()
@@ -147,7 +246,7 @@ func GetLocalkubeStatus(api libmachine.API) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	s, err := h.RunSSHCommand(localkubeStatusCommand)
+	s, err := NewCommandRunner(h).RunSSHCommand(localkubeStatusCommand)
 	if err != nil {
 		return "", err
 	}
@@ -156,11 +255,31 @@ func GetLocalkubeStatus(api libmachine.API) (string, error) {
 		return state.Running.String(), nil
 	} else if state.Stopped.String() == s {
 		return state.Stopped.String(), nil
+	} else if state.Paused.String() == s {
+		return state.Paused.String(), nil
 	} else {
 		return "", fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
 	}
 }
 
+// GetWatchdogStatus returns the last restart of localkube performed by the
+// watchdog since the cluster was started, along with hints on its cause.
+func GetWatchdogStatus(api libmachine.API) (string, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return "", err
+	}
+	return getWatchdogStatus(NewCommandRunner(h))
+}
+
+func getWatchdogStatus(h sshAble) (string, error) {
+	s, err := h.RunSSHCommand(watchdogStatusCommand)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting watchdog status")
+	}
+	return strings.TrimSpace(s), nil
+}
+
 type sshAble interface {
 	RunSSHCommand(string) (string, error)
 }
@@ -180,50 +299,99 @@ func StartCluster(h sshAble, kubernetesConfig KubernetesConfig) error {
 	return nil
 }
 
+// RestartLocalkube restarts localkube in the VM, e.g. for the apiserver to
+// serve certs set up again.
+func RestartLocalkube(h sshAble) error {
+	if out, err := h.RunSSHCommand(restartLocalkubeCommand); err != nil {
+		return errors.Wrapf(err, "Error restarting localkube: %s", out)
+	}
+	return nil
+}
+
 func UpdateCluster(h sshAble, d drivers.Driver, config KubernetesConfig) error {
 	copyableFiles := []assets.CopyableFile{}
-	var localkubeFile assets.CopyableFile
-	var err error
 
 	//add url/file/bundled localkube to file list
-	if localkubeURIWasSpecified(config) {
-		lCacher := localkubeCacher{config}
-		localkubeFile, err = lCacher.fetchLocalkubeFromURI()
-		if err != nil {
-			return errors.Wrap(err, "Error updating localkube from uri")
-		}
-
-	} else {
-		localkubeFile = assets.NewMemoryAsset("out/localkube", "/usr/local/bin", "localkube", "0777")
+	localkubeFile, err := localkubeAsset(config)
+	if err != nil {
+		return err
 	}
 	copyableFiles = append(copyableFiles, localkubeFile)
 
 	// add addons to file list
 	// custom addons
 	assets.AddMinikubeAddonsDirToAssets(&copyableFiles)
-	// bundled addons
-	for _, addonBundle := range assets.Addons {
-		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				copyableFiles = append(copyableFiles, addon)
+	// bundled addons, unless they are applied through the apiserver once it is up
+	if !assets.ApplyViaAPI() {
+		for _, addonBundle := range assets.Addons {
+			if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
+				addonFiles, err := addonBundle.GetAssets()
+				if err != nil {
+					return errors.Wrap(err, "Error getting addon assets")
+				}
+				copyableFiles = append(copyableFiles, addonFiles...)
+			} else if err != nil {
+				return err
 			}
-		} else if err != nil {
-			return err
 		}
 	}
 
-	// transfer files to vm via SSH
-	client, err := sshutil.NewSSHClient(d)
+	cniFile, err := cni.Asset(config.CNI, config.PodNetworkCIDR)
 	if err != nil {
-		return errors.Wrap(err, "Error creating new ssh client")
+		return err
+	}
+	if cniFile != nil {
+		copyableFiles = append(copyableFiles, cniFile)
 	}
 
-	for _, f := range copyableFiles {
-		if err := sshutil.TransferFile(f, client); err != nil {
+	if config.SeccompProfilesDir != "" {
+		profiles, err := seccompProfileAssets(config.SeccompProfilesDir)
+		if err != nil {
 			return err
 		}
+		copyableFiles = append(copyableFiles, profiles...)
 	}
-	return nil
+
+	return CopyFiles(d, copyableFiles)
+}
+
+// seccompProfileAssets returns the files of the directory, and of its
+// subdirectories, to be copied into the seccomp profile root of the kubelet.
+func seccompProfileAssets(dir string) ([]assets.CopyableFile, error) {
+	var files []assets.CopyableFile
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		f, err := assets.NewFileAsset(p, path.Join(constants.SeccompProfileRoot, filepath.ToSlash(rel)), info.Name(), "0644")
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the seccomp profiles in %s", dir)
+	}
+	return files, nil
+}
+
+// localkubeAsset returns the localkube binary of the Kubernetes version,
+// fetched from its url or bundled with minikube.
+func localkubeAsset(config KubernetesConfig) (assets.CopyableFile, error) {
+	if localkubeURIWasSpecified(config) {
+		lCacher := localkubeCacher{config}
+		localkubeFile, err := lCacher.fetchLocalkubeFromURI()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error updating localkube from uri")
+		}
+		return localkubeFile, nil
+	}
+	return assets.NewMemoryAsset("out/localkube", "/usr/local/bin", "localkube", "0777"), nil
 }
 
 func localkubeURIWasSpecified(config KubernetesConfig) bool {
@@ -232,7 +400,8 @@ func localkubeURIWasSpecified(config KubernetesConfig) bool {
 }
 
 // SetupCerts gets the generated credentials required to talk to the APIServer.
-func SetupCerts(d drivers.Driver, apiServerName string) error {
+// The certificate of the apiserver is also valid for the extraIPs.
+func SetupCerts(d drivers.Driver, apiServerName string, extraIPs ...net.IP) error {
 	localPath := constants.GetMinipath()
 	ipStr, err := d.GetIP()
 	if err != nil {
@@ -245,10 +414,15 @@ func SetupCerts(d drivers.Driver, apiServerName string) error {
 	caKey := filepath.Join(localPath, "ca.key")
 	publicPath := filepath.Join(localPath, "apiserver.crt")
 	privatePath := filepath.Join(localPath, "apiserver.key")
-	if err := GenerateCerts(caCert, caKey, publicPath, privatePath, ip, apiServerName); err != nil {
+	if err := GenerateCerts(caCert, caKey, publicPath, privatePath, ip, apiServerName, extraIPs...); err != nil {
 		return errors.Wrap(err, "Error generating certs")
 	}
+	return transferCerts(d, certs)
+}
 
+// transferCerts copies the certs from the minikube dir to the VM.
+func transferCerts(d drivers.Driver, certs []string) error {
+	localPath := constants.GetMinipath()
 	copyableFiles := []assets.CopyableFile{}
 
 	for _, cert := range certs {
@@ -264,18 +438,7 @@ func SetupCerts(d drivers.Driver, apiServerName string) error {
 		copyableFiles = append(copyableFiles, certFile)
 	}
 
-	// transfer files to vm via SSH
-	client, err := sshutil.NewSSHClient(d)
-	if err != nil {
-		return errors.Wrap(err, "Error creating new ssh client")
-	}
-
-	for _, f := range copyableFiles {
-		if err := sshutil.TransferFile(f, client); err != nil {
-			return err
-		}
-	}
-	return nil
+	return CopyFiles(d, copyableFiles)
 }
 
 func engineOptions(config MachineConfig) *engine.Options {
@@ -290,7 +453,7 @@ func engineOptions(config MachineConfig) *engine.Options {
 }
 
 func createVirtualboxHost(config MachineConfig) drivers.Driver {
-	d := virtualbox.NewDriver(constants.MachineName, constants.GetMinipath())
+	d := virtualbox.NewDriver(config.machineName(), constants.GetMinipath())
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.Memory = config.Memory
 	d.CPU = config.CPUs
@@ -299,11 +462,56 @@ func createVirtualboxHost(config MachineConfig) drivers.Driver {
 	return d
 }
 
+func createContainerHost(config MachineConfig) drivers.Driver {
+	d := container.NewDriver(config.VMDriver, config.machineName(), constants.GetMinipath())
+	d.Image = constants.NodeImage
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	return d
+}
+
+func createSSHHost(config MachineConfig) drivers.Driver {
+	d := sshdriver.NewDriver(config.machineName(), constants.GetMinipath())
+	d.IPAddress = config.SSHIPAddress
+	d.SSHUser = config.SSHUser
+	d.SSHKey = config.SSHKey
+	d.SSHPort = config.SSHPort
+	return d
+}
+
+func createQemuHost(config MachineConfig) drivers.Driver {
+	d := qemu.NewDriver(config.machineName(), constants.GetMinipath())
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	d.DiskSize = config.DiskSize
+	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
+	d.Network = config.QemuNetwork
+	d.SocketVMnetClient = config.SocketVMnetClient
+	d.SocketVMnetPath = config.SocketVMnetPath
+	return d
+}
+
+// qemuHostIP returns the address the VM of the qemu driver reaches the host
+// at, which depends on its network.
+func qemuHostIP(h *host.Host) (net.IP, error) {
+	d, ok := h.Driver.(*qemu.Driver)
+	if !ok {
+		return nil, errors.Errorf("Unexpected driver %T of the qemu VM", h.Driver)
+	}
+	ip, err := d.HostIP()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the address of the host")
+	}
+	return net.ParseIP(ip), nil
+}
+
 func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 	var driver interface{}
 
-	if err := config.Downloader.CacheMinikubeISOFromURL(config.MinikubeISO); err != nil {
-		return nil, errors.Wrap(err, "Error attempting to cache minikube ISO from URL")
+	if constants.UsesISO(config.VMDriver) {
+		if err := config.Downloader.CacheMinikubeISOFromURL(config.MinikubeISO); err != nil {
+			return nil, errors.Wrap(err, "Error attempting to cache minikube ISO from URL")
+		}
 	}
 
 	switch config.VMDriver {
@@ -313,10 +521,22 @@ func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 		driver = createVMwareFusionHost(config)
 	case "kvm":
 		driver = createKVMHost(config)
+	case "kvm2":
+		driver = createKVM2Host(config)
 	case "xhyve":
 		driver = createXhyveHost(config)
+	case "hyperkit":
+		driver = createHyperkitHost(config)
 	case "hyperv":
 		driver = createHypervHost(config)
+	case "qemu":
+		driver = createQemuHost(config)
+	case "docker", "podman":
+		driver = createContainerHost(config)
+	case constants.DriverNone:
+		driver = none.NewDriver(config.machineName(), constants.GetMinipath())
+	case constants.DriverSSH:
+		driver = createSSHHost(config)
 	default:
 		glog.Exitf("Unsupported driver: %s\n", config.VMDriver)
 	}
@@ -353,13 +573,16 @@ func GetHostDockerEnv(api libmachine.API) (map[string]string, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Error checking that api exists and loading it")
 	}
+	if host.Driver.DriverName() == constants.DriverNone {
+		return nil, errors.Errorf("The %s driver uses the docker daemon of this machine, which docker already reaches", constants.DriverNone)
+	}
 	ip, err := host.Driver.GetIP()
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting ip from host")
 	}
 
 	tcpPrefix := "tcp://"
-	port := "2376"
+	port := strconv.Itoa(constants.DockerDaemonPort)
 
 	envMap := map[string]string{
 		"DOCKER_TLS_VERIFY": "1",
@@ -369,6 +592,62 @@ func GetHostDockerEnv(api libmachine.API) (map[string]string, error) {
 	return envMap, nil
 }
 
+// GetHostBuildkitEnv starts the buildkit daemon in the host VM and returns
+// the env variables needed to use it through buildctl. The daemon serves TLS
+// with the certificates of the docker daemon, buildctl authenticates with the
+// same client certificates as docker with docker-env.
+func GetHostBuildkitEnv(api libmachine.API) (map[string]string, error) {
+	host, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	if _, err := host.RunSSHCommand(startBuildkitCommand); err != nil {
+		return nil, errors.Wrap(err, "Error starting buildkit daemon")
+	}
+	ip, err := host.Driver.GetIP()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting ip from host")
+	}
+	envMap := map[string]string{
+		"BUILDKIT_HOST": "tcp://" + net.JoinHostPort(ip, strconv.Itoa(constants.BuildkitPort)),
+	}
+	return envMap, nil
+}
+
+// RefreshDockerAuth regenerates the docker daemon certificates for the current IP of the host VM,
+// so that clients configured through docker-env can reconnect. ConfigureAuth stops the daemon
+// while it copies the certificates and starts it again.
+func RefreshDockerAuth(api libmachine.API) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	if err := h.ConfigureAuth(); err != nil {
+		return errors.Wrap(err, "Error configuring auth on host")
+	}
+	if err := api.Save(h); err != nil {
+		return errors.Wrap(err, "Error saving host")
+	}
+	return nil
+}
+
+// ForwardDockerPort forwards localhost:2376 to the docker daemon of the host VM.
+// Unlike the VM IP, the forwarded address stays the same across restarts.
+// Only the virtualbox driver supports forwarding ports.
+func ForwardDockerPort(h *host.Host) error {
+	if h.DriverName != "virtualbox" {
+		return errors.Errorf("Forwarding the docker port is not supported by the %s driver, only by virtualbox", h.DriverName)
+	}
+	port := constants.DockerDaemonPort
+	rule := fmt.Sprintf("%s,tcp,127.0.0.1,%d,,%d", dockerPortForwardName, port, port)
+	// Remove a stale rule first, the rule may not exist so the error is ignored
+	exec.Command("VBoxManage", "controlvm", h.Name, "natpf1", "delete", dockerPortForwardName).Run()
+	if out, err := exec.Command("VBoxManage", "controlvm", h.Name, "natpf1", rule).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "Error forwarding docker port of %s: %s", h.Name, out)
+	}
+	return nil
+}
+
 // GetHostLogs gets the localkube logs of the host VM.
 // If follow is specified, it will tail the logs
 func GetHostLogs(api libmachine.API, follow bool) (string, error) {
@@ -380,6 +659,11 @@ func GetHostLogs(api libmachine.API, follow bool) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "Error getting logs command")
 	}
+	if follow && h.Driver.DriverName() == constants.DriverNone {
+		cmd := exec.Command("/bin/bash", "-c", logsCommand)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		return "", cmd.Run()
+	}
 	if follow {
 		c, err := h.CreateSSHClient()
 		if err != nil {
@@ -391,7 +675,7 @@ func GetHostLogs(api libmachine.API, follow bool) (string, error) {
 		}
 		return "", err
 	}
-	s, err := h.RunSSHCommand(logsCommand)
+	s, err := NewCommandRunner(h).RunSSHCommand(logsCommand)
 	if err != nil {
 		return "", err
 	}
@@ -436,6 +720,9 @@ func CreateSSHShell(api libmachine.API, args []string) error {
 	if err != nil {
 		return errors.Wrap(err, "Error checking if api exist and loading it")
 	}
+	if host.Driver.DriverName() == constants.DriverNone {
+		return errors.Errorf("The %s driver runs the cluster on this machine, run the command directly instead", constants.DriverNone)
+	}
 
 	currentState, err := host.Driver.GetState()
 	if err != nil {
@@ -23,11 +23,12 @@ import (
 	"github.com/docker/machine/drivers/vmwarefusion"
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/host"
+	"k8s.io/minikube/pkg/drivers/hyperkit"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
 func createVMwareFusionHost(config MachineConfig) drivers.Driver {
-	d := vmwarefusion.NewDriver(constants.MachineName, constants.GetMinipath()).(*vmwarefusion.Driver)
+	d := vmwarefusion.NewDriver(config.machineName(), constants.GetMinipath()).(*vmwarefusion.Driver)
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.Memory = config.Memory
 	d.CPU = config.CPUs
@@ -59,25 +60,41 @@ type xhyveDriver struct {
 func createXhyveHost(config MachineConfig) *xhyveDriver {
 	return &xhyveDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: constants.MachineName,
+			MachineName: config.machineName(),
 			StorePath:   constants.GetMinipath(),
 		},
 		Memory:         config.Memory,
 		CPU:            config.CPUs,
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
-		BootCmd:        "loglevel=3 user=docker console=ttyS0 console=tty0 noembed nomodeset norestore waitusb=10 base host=" + constants.MachineName,
+		BootCmd:        "loglevel=3 user=docker console=ttyS0 console=tty0 noembed nomodeset norestore waitusb=10 base host=" + config.machineName(),
 		DiskSize:       int64(config.DiskSize),
 		Virtio9p:       true,
 		Virtio9pFolder: "/Users",
 	}
 }
 
+func createHyperkitHost(config MachineConfig) *hyperkit.Driver {
+	d := hyperkit.NewDriver(config.machineName(), constants.GetMinipath())
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	d.DiskSize = config.DiskSize
+	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
+	d.NFSShares = config.NFSShare
+	d.NFSSharesRoot = config.NFSSharesRoot
+	return d
+}
+
 func getVMHostIP(host *host.Host) (net.IP, error) {
 	switch host.DriverName {
 	case "virtualbox":
 		return net.ParseIP("10.0.2.2"), nil
 	case "xhyve":
 		return net.ParseIP("10.0.2.2"), nil
+	case "hyperkit":
+		// The host on the default network of vmnet
+		return net.ParseIP("192.168.64.1"), nil
+	case "qemu":
+		return qemuHostIP(host)
 	default:
 		return []byte{}, errors.New("Error, attempted to get host ip address for unsupported driver")
 	}
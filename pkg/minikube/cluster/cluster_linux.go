@@ -24,6 +24,7 @@ import (
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/host"
+	"k8s.io/minikube/pkg/drivers/kvm2"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -45,7 +46,7 @@ type kvmDriver struct {
 func createKVMHost(config MachineConfig) *kvmDriver {
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: constants.MachineName,
+			MachineName: config.machineName(),
 			StorePath:   constants.GetMinipath(),
 		},
 		Memory:         config.Memory,
@@ -54,19 +55,34 @@ func createKVMHost(config MachineConfig) *kvmDriver {
 		PrivateNetwork: "docker-machines",
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
 		DiskSize:       config.DiskSize,
-		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", constants.MachineName, fmt.Sprintf("%s.img", constants.MachineName)),
-		ISO:            filepath.Join(constants.GetMinipath(), "machines", constants.MachineName, "boot2docker.iso"),
+		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", config.machineName(), fmt.Sprintf("%s.img", config.machineName())),
+		ISO:            filepath.Join(constants.GetMinipath(), "machines", config.machineName(), "boot2docker.iso"),
 		CacheMode:      "default",
 		IOMode:         "threads",
 	}
 }
 
+func createKVM2Host(config MachineConfig) *kvm2.Driver {
+	d := kvm2.NewDriver(config.machineName(), constants.GetMinipath())
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	d.DiskSize = config.DiskSize
+	d.Network = config.KvmNetwork
+	d.StaticIP = config.StaticIP
+	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
+	return d
+}
+
 func getVMHostIP(host *host.Host) (net.IP, error) {
 	switch host.DriverName {
 	case "virtualbox":
 		return net.ParseIP("10.0.2.2"), nil
 	case "kvm":
 		return net.ParseIP("192.168.42.1"), nil
+	case "kvm2":
+		return net.ParseIP("192.168.39.1"), nil
+	case "qemu":
+		return qemuHostIP(host)
 	default:
 		return []byte{}, errors.New("Error, attempted to get host ip address for unsupported driver")
 	}
@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -250,6 +251,28 @@ func TestStartHostConfig(t *testing.T) {
 
 }
 
+func TestStartHostExistsProxyEnv(t *testing.T) {
+	api := tests.NewMockAPI()
+	config := defaultMachineConfig
+	config.DockerEnv = []string{"FOO=BAR", "HTTP_PROXY=http://old:3128"}
+	if _, err := createHost(api, config); err != nil {
+		t.Fatalf("Error creating host: %v", err)
+	}
+
+	md := &tests.MockDetector{Provisioner: &tests.MockProvisioner{}}
+	provision.SetDetector(md)
+
+	config.DockerEnv = []string{"HTTPS_PROXY=http://new:3128"}
+	h, err := StartHost(api, config)
+	if err != nil {
+		t.Fatalf("Error starting host: %v", err)
+	}
+	expected := []string{"FOO=BAR", "HTTPS_PROXY=http://new:3128"}
+	if !reflect.DeepEqual(h.HostOptions.EngineOptions.Env, expected) {
+		t.Fatalf("Expected docker env %v, got %v", expected, h.HostOptions.EngineOptions.Env)
+	}
+}
+
 func TestStopHostError(t *testing.T) {
 	api := tests.NewMockAPI()
 	if err := StopHost(api); err == nil {
@@ -277,6 +300,35 @@ func TestDeleteHost(t *testing.T) {
 	}
 }
 
+func TestDeleteAllHosts(t *testing.T) {
+	api := tests.NewMockAPI()
+	for _, name := range []string{constants.MachineName, constants.NodeMachineName("m02"), "leftover"} {
+		api.Hosts[name] = &host.Host{Name: name, Driver: &tests.MockDriver{}}
+	}
+
+	deleted, err := DeleteAllHosts(api)
+	if err != nil {
+		t.Fatalf("Unexpected error deleting hosts: %s", err)
+	}
+	expected := []string{"leftover", constants.MachineName, constants.NodeMachineName("m02")}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Expected %v to be deleted, got %v", expected, deleted)
+	}
+	if len(api.Hosts) != 0 {
+		t.Errorf("Expected no machine left, got %v", api.Hosts)
+	}
+
+	api.Hosts["broken"] = &host.Host{Name: "broken", Driver: &tests.MockDriver{RemoveError: true}}
+	api.Hosts["fine"] = &host.Host{Name: "fine", Driver: &tests.MockDriver{}}
+	deleted, err = DeleteAllHosts(api)
+	if err == nil {
+		t.Errorf("Expected an error deleting a broken host")
+	}
+	if !reflect.DeepEqual(deleted, []string{"fine"}) {
+		t.Errorf("Expected the other hosts to be deleted, got %v", deleted)
+	}
+}
+
 func TestDeleteHostErrorDeletingVM(t *testing.T) {
 	api := tests.NewMockAPI()
 	h, _ := createHost(api, defaultMachineConfig)
@@ -377,6 +429,13 @@ func TestGetLocalkubeStatus(t *testing.T) {
 		t.Fatalf("Error getting localkube status: %s", err)
 	}
 
+	s.SetCommandToOutput(map[string]string{
+		localkubeStatusCommand: state.Paused.String(),
+	})
+	if status, err := GetLocalkubeStatus(api); err != nil || status != state.Paused.String() {
+		t.Fatalf("Expected localkube to be paused, got %s: %v", status, err)
+	}
+
 	s.SetCommandToOutput(map[string]string{
 		localkubeStatusCommand: "Bad Output",
 	})
@@ -385,6 +444,19 @@ func TestGetLocalkubeStatus(t *testing.T) {
 	}
 }
 
+func TestGetWatchdogStatus(t *testing.T) {
+	h := tests.NewMockHost()
+	expected := "2017-03-01T10:00:00Z restarted localkube, unhealthy: apiserver"
+	h.CommandOutput[watchdogStatusCommand] = expected + "\n"
+	s, err := getWatchdogStatus(h)
+	if err != nil {
+		t.Fatalf("Unexpected error getting watchdog status: %s", err)
+	}
+	if s != expected {
+		t.Fatalf("Expected status %q, got %q", expected, s)
+	}
+}
+
 func TestSetupCerts(t *testing.T) {
 	s, _ := tests.NewSSHServer()
 	port, err := s.Start()
@@ -740,3 +812,63 @@ func TestUpdateCustomAddons(t *testing.T) {
 		t.Fatalf("Custom addon not copied. Expected transfers to contain custom addon with content: %s. It was: %s", testContent2, transferred)
 	}
 }
+
+func TestSeccompProfileAssets(t *testing.T) {
+	dir := tests.MakeTempDir()
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "team"), 0755); err != nil {
+		t.Fatalf("Error creating dir: %s", err)
+	}
+	for _, p := range []string{"audit.json", filepath.Join("team", "strict.json")} {
+		if err := ioutil.WriteFile(filepath.Join(dir, p), []byte(`{"defaultAction": "SCMP_ACT_LOG"}`), 0644); err != nil {
+			t.Fatalf("Error writing profile: %s", err)
+		}
+	}
+
+	files, err := seccompProfileAssets(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var targets []string
+	for _, f := range files {
+		targets = append(targets, filepath.ToSlash(filepath.Join(f.GetTargetDir(), f.GetTargetName())))
+	}
+	expected := []string{
+		constants.SeccompProfileRoot + "/audit.json",
+		constants.SeccompProfileRoot + "/team/strict.json",
+	}
+	if strings.Join(targets, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected profiles to be copied to %v, got %v", expected, targets)
+	}
+
+	if _, err := seccompProfileAssets(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
+
+func TestRestartLocalkube(t *testing.T) {
+	h := tests.NewMockHost()
+	if err := RestartLocalkube(h); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[restartLocalkubeCommand] != 1 {
+		t.Errorf("Expected the restart command to run once, got %v", h.Commands)
+	}
+
+	h = tests.NewMockHost()
+	h.Error = "ssh failed"
+	if err := RestartLocalkube(h); err == nil {
+		t.Errorf("Expected an error when the command fails")
+	}
+}
+
+func TestForwardDockerPortUnsupportedDriver(t *testing.T) {
+	h := &host.Host{Name: "minikube", DriverName: "kvm", Driver: &tests.MockDriver{}}
+	err := ForwardDockerPort(h)
+	if err == nil {
+		t.Fatalf("Expected an error forwarding the docker port with the kvm driver")
+	}
+	if !strings.Contains(err.Error(), "not supported by the kvm driver") {
+		t.Errorf("Expected an unsupported driver error, got %s", err)
+	}
+}
@@ -30,9 +30,11 @@ import (
 )
 
 func createHypervHost(config MachineConfig) drivers.Driver {
-	d := hyperv.NewDriver(constants.MachineName, constants.GetMinipath())
+	d := hyperv.NewDriver(config.machineName(), constants.GetMinipath())
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.VSwitch = config.HypervVirtualSwitch
+	d.UseExternalSwitch = config.HypervUseExtSwitch
+	d.DisableDynamicMemory = config.HypervDisableDynMem
 	d.MemSize = config.Memory
 	d.CPU = config.CPUs
 	d.DiskSize = int(config.DiskSize)
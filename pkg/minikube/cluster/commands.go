@@ -25,6 +25,7 @@ import (
 	"text/template"
 
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Kill any running instances.
@@ -56,12 +57,96 @@ ExecReload=/bin/kill -s HUP $MAINPID
 WantedBy=multi-user.target
 `
 
+// localkubeWatchdogScript restarts localkube once the apiserver or etcd failed
+// their health checks several times in a row, and records the likely causes.
+var localkubeWatchdogScript = fmt.Sprintf(`#!/bin/bash
+STATUS_FILE=%s
+FAILURES_FILE=$(dirname $STATUS_FILE)/failures
+MAX_FAILURES=3
+mkdir -p $(dirname $STATUS_FILE)
+
+# Leave localkube alone while it is stopped or starting up
+systemctl is-active -q localkube || exit 0
+
+unhealthy=""
+curl -sf --max-time 5 http://127.0.0.1:8080/healthz >/dev/null || unhealthy="$unhealthy apiserver"
+curl -sf --max-time 5 http://127.0.0.1:2379/health >/dev/null || unhealthy="$unhealthy etcd"
+if [ -z "$unhealthy" ]; then
+  echo 0 > $FAILURES_FILE
+  exit 0
+fi
+
+failures=$(( $(cat $FAILURES_FILE 2>/dev/null || echo 0) + 1 ))
+echo $failures > $FAILURES_FILE
+[ $failures -lt $MAX_FAILURES ] && exit 0
+
+hints=""
+if dmesg | grep -qiE "(out of memory|killed process).*localkube"; then
+  hints="$hints, localkube was killed by the OOM killer (try a larger --memory)"
+fi
+usage=$(df /var/lib/localkube | awk 'NR==2 {print $5}' | tr -d %%)
+if [ "${usage:-0}" -ge 95 ]; then
+  hints="$hints, /var/lib/localkube is ${usage}%% full"
+fi
+if command -v openssl >/dev/null && [ -f /var/lib/localkube/certs/apiserver.crt ]; then
+  openssl x509 -checkend 0 -noout -in /var/lib/localkube/certs/apiserver.crt >/dev/null || hints="$hints, the apiserver certificate has expired"
+fi
+
+echo "$(date -u +%%Y-%%m-%%dT%%H:%%M:%%SZ) restarted localkube, unhealthy:$unhealthy${hints:+ (${hints#, })}" > $STATUS_FILE
+echo 0 > $FAILURES_FILE
+systemctl restart localkube
+`, constants.LocalkubeWatchdogStatusPath)
+
+var localkubeWatchdogService = fmt.Sprintf(`[Unit]
+Description=Localkube watchdog
+After=localkube.service
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, constants.LocalkubeWatchdogPath)
+
+var localkubeWatchdogTimer = `[Unit]
+Description=Run the localkube watchdog periodically
+
+[Timer]
+OnActiveSec=1min
+OnUnitActiveSec=30s
+
+[Install]
+WantedBy=timers.target
+`
+
+// watchdogCommand installs the watchdog and clears the status of a previous run
+var watchdogCommand = fmt.Sprintf(`
+sudo mkdir -p $(dirname %[1]s)
+sudo rm -f %[1]s
+cat <<'EOF' | sudo tee %[2]s >/dev/null
+%[3]sEOF
+sudo chmod +x %[2]s
+cat <<'EOF' | sudo tee /usr/lib/systemd/system/localkube-watchdog.service >/dev/null
+%[4]sEOF
+cat <<'EOF' | sudo tee /usr/lib/systemd/system/localkube-watchdog.timer >/dev/null
+%[5]sEOF
+`, constants.LocalkubeWatchdogStatusPath, constants.LocalkubeWatchdogPath,
+	localkubeWatchdogScript, localkubeWatchdogService, localkubeWatchdogTimer)
+
+// restartLocalkubeCommand restarts localkube, whose apiserver loads its
+// certs when it starts
+const restartLocalkubeCommand = "sudo systemctl restart localkube"
+
+// watchdogStatusCommand prints the last restart performed by the watchdog, if any
+var watchdogStatusCommand = fmt.Sprintf("cat %s 2>/dev/null || true", constants.LocalkubeWatchdogStatusPath)
+
 var startCommandTemplate = `
 if which systemctl 2>&1 1>/dev/null; then
   {{.StartCommandSystemd}}
+  {{.WatchdogCommand}}
   sudo systemctl daemon-reload
   sudo systemctl enable localkube.service
   sudo systemctl restart localkube.service || true
+  sudo systemctl enable localkube-watchdog.timer
+  sudo systemctl restart localkube-watchdog.timer
 else
   sudo killall localkube || true
   {{.StartCommandB2D}}
@@ -86,9 +171,11 @@ func GetStartCommand(kubernetesConfig KubernetesConfig) (string, error) {
 	data := struct {
 		StartCommandB2D     string
 		StartCommandSystemd string
+		WatchdogCommand     string
 	}{
 		StartCommandB2D:     startCommandB2D,
 		StartCommandSystemd: startCommandSystemd,
+		WatchdogCommand:     watchdogCommand,
 	}
 	if err := t.Execute(&buf, data); err != nil {
 		return "", err
@@ -125,7 +212,9 @@ func GetStartCommandSystemd(kubernetesConfig KubernetesConfig, localkubeStartCmd
 	data := struct {
 		LocalkubeStartCmd string
 	}{
-		LocalkubeStartCmd: localkubeStartCmd,
+		// systemd expands specifiers starting with a '%', and the unit is
+		// written from a double quoted string
+		LocalkubeStartCmd: strings.NewReplacer("%", "%%", `"`, `\"`).Replace(localkubeStartCmd),
 	}
 	if err := t.Execute(&buf, data); err != nil {
 		return "", err
@@ -150,14 +239,67 @@ func GenLocalkubeStartCmd(kubernetesConfig KubernetesConfig) (string, error) {
 		flagVals = append(flagVals, "--network-plugin="+kubernetesConfig.NetworkPlugin)
 	}
 
+	if kubernetesConfig.PodNetworkCIDR != "" {
+		flagVals = append(flagVals, "--pod-network-cidr="+kubernetesConfig.PodNetworkCIDR)
+	}
+
+	if kubernetesConfig.ServiceClusterIPRange != "" {
+		// The cluster DNS moves into the range with the apiserver
+		_, dnsIP, err := util.ServiceIPs(kubernetesConfig.ServiceClusterIPRange)
+		if err != nil {
+			return "", err
+		}
+		flagVals = append(flagVals, "--service-cluster-ip-range="+kubernetesConfig.ServiceClusterIPRange, "--dns-ip="+dnsIP.String())
+	}
+
 	if kubernetesConfig.FeatureGates != "" {
 		flagVals = append(flagVals, "--feature-gates="+kubernetesConfig.FeatureGates)
 	}
 
+	if kubernetesConfig.Master != "" {
+		flagVals = append(flagVals, "--master="+kubernetesConfig.Master)
+	}
+
+	if kubernetesConfig.CryptoMode != "" && kubernetesConfig.CryptoMode != util.CryptoModeDefault {
+		flagVals = append(flagVals, "--crypto-mode="+kubernetesConfig.CryptoMode)
+	}
+
+	if kubernetesConfig.SeccompDefault {
+		flagVals = append(flagVals, "--seccomp-default")
+	}
+
+	if kubernetesConfig.EtcdPeerURL != "" {
+		flagVals = append(flagVals, "--etcd-peer-url="+kubernetesConfig.EtcdPeerURL)
+	}
+
+	if kubernetesConfig.EtcdJoin != "" {
+		flagVals = append(flagVals, "--etcd-join="+kubernetesConfig.EtcdJoin)
+	}
+
 	if kubernetesConfig.APIServerName != constants.APIServerName {
 		flagVals = append(flagVals, "--apiserver-name="+kubernetesConfig.APIServerName)
 	}
 
+	if kubernetesConfig.APIServerPort != 0 && kubernetesConfig.APIServerPort != constants.APIServerPort {
+		flagVals = append(flagVals, fmt.Sprintf("--apiserver-port=%d", kubernetesConfig.APIServerPort))
+	}
+
+	// Reservations go before the extra options, so those can still override them.
+	// They are quoted since eviction thresholds contain a '<'.
+	for _, r := range []struct{ key, val string }{
+		{"SystemReserved", kubernetesConfig.Reserved.SystemReserved},
+		{"KubeReserved", kubernetesConfig.Reserved.KubeReserved},
+		{"EvictionHard", kubernetesConfig.Reserved.EvictionHard},
+	} {
+		if r.val != "" {
+			flagVals = append(flagVals, fmt.Sprintf("\"--extra-config=kubelet.%s=%s\"", r.key, r.val))
+		}
+	}
+
+	if kubernetesConfig.SwapEnabled && swapNeedsFailSwapOff(kubernetesConfig.KubernetesVersion) {
+		flagVals = append(flagVals, "--extra-config=kubelet.FailSwapOn=false")
+	}
+
 	for _, e := range kubernetesConfig.ExtraOptions {
 		flagVals = append(flagVals, fmt.Sprintf("--extra-config=%s", e.String()))
 	}
@@ -206,7 +348,7 @@ func GetLogsCommand(follow bool) (string, error) {
 	}{
 		RemoteLocalkubeErrPath: constants.RemoteLocalKubeErrPath,
 		RemoteLocalkubeOutPath: constants.RemoteLocalKubeOutPath,
-		Flags: strings.Join(flags, " "),
+		Flags:                  strings.Join(flags, " "),
 	}
 	if err := t.Execute(&buf, data); err != nil {
 		return "", err
@@ -215,7 +357,9 @@ func GetLogsCommand(follow bool) (string, error) {
 }
 
 var localkubeStatusCommand = fmt.Sprintf(`
-if which systemctl 2>&1 1>/dev/null; then
+if [ -f %s ]; then
+  echo "Paused"
+elif which systemctl 2>&1 1>/dev/null; then
   sudo systemctl is-active localkube 2>&1 1>/dev/null && echo "Running" || echo "Stopped"
 else
   if ps $(cat %s) 2>&1 1>/dev/null; then
@@ -224,7 +368,35 @@ else
     echo "Stopped"
   fi
 fi
-`, constants.LocalkubePIDPath)
+`, constants.LocalkubePausedPath, constants.LocalkubePIDPath)
+
+// pauseCommand freezes localkube and then the containers, keeping the VM up.
+// The watchdog is stopped first, as it would restart the unresponsive
+// localkube.
+var pauseCommand = fmt.Sprintf(`
+if which systemctl 2>&1 1>/dev/null; then
+  sudo systemctl stop localkube-watchdog.timer || true
+fi
+sudo killall -STOP localkube || true
+for id in $(docker ps --quiet --filter status=running); do
+  docker pause $id >/dev/null
+done
+sudo touch %s
+`, constants.LocalkubePausedPath)
+
+// unpauseCommand resumes the containers, then localkube and its watchdog. It
+// does nothing when nothing is paused.
+var unpauseCommand = fmt.Sprintf(`
+[ -f %[1]s ] || exit 0
+for id in $(docker ps --quiet --filter status=paused); do
+  docker unpause $id >/dev/null
+done
+sudo killall -CONT localkube || true
+if which systemctl 2>&1 1>/dev/null; then
+  sudo systemctl start localkube-watchdog.timer || true
+fi
+sudo rm -f %[1]s
+`, constants.LocalkubePausedPath)
 
 func GetMount9pCommand(ip net.IP) string {
 	return fmt.Sprintf(`
@@ -232,3 +404,283 @@ sudo mkdir /mount-9p;
 sudo mount -t 9p -o trans=tcp -o port=5640 %s /mount-9p;
 sudo chmod 775 /mount-9p;`, ip)
 }
+
+const startBuildkitCommand = "sudo systemctl start buildkit"
+
+// loadDockerImageCommand loads the image archive of its input into the docker
+// daemon of the VM.
+const loadDockerImageCommand = "docker load"
+
+// enableBinfmtCommand registers qemu-user-static binfmt handlers so that images
+// built for foreign architectures can be run in the VM.
+const enableBinfmtCommand = `
+if ! grep -qs binfmt_misc /proc/mounts; then
+  sudo mount -t binfmt_misc binfmt_misc /proc/sys/fs/binfmt_misc
+fi
+sudo docker run --rm --privileged multiarch/qemu-user-static --reset -p yes
+`
+
+// enableSELinuxCommand puts SELinux in enforcing mode and has docker label the
+// containers, restarting it with --selinux-enabled if needed. The kernel of
+// the VM must have been built with SELinux.
+const enableSELinuxCommand = `
+if [ ! -d /sys/fs/selinux ] || ! which setenforce >/dev/null 2>&1; then
+  echo "SELinux is not supported by the minikube ISO, a newer minikube ISO is needed"; exit 1
+fi
+sudo setenforce 1
+if ! docker info 2>/dev/null | grep -qw selinux; then
+  sudo sed -i 's#^ExecStart=/usr/bin/docker daemon #&--selinux-enabled #' /etc/systemd/system/docker.service
+  sudo systemctl daemon-reload
+  sudo systemctl restart docker
+fi
+`
+
+// appArmorProfilesPath is on the persistent disk, for the profiles of the
+// user to be loaded again after a restart.
+const appArmorProfilesPath = "/var/lib/localkube/apparmor"
+
+// enableAppArmorCommand loads the AppArmor profiles of the VM and of
+// appArmorProfilesPath, so that pods can be confined by them. Docker confines
+// the other containers with its default profile once AppArmor is enabled.
+var enableAppArmorCommand = fmt.Sprintf(`
+if [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" != "Y" ] || ! which apparmor_parser >/dev/null 2>&1; then
+  echo "AppArmor is not supported by the minikube ISO, a newer minikube ISO is needed"; exit 1
+fi
+sudo mkdir -p %[1]s
+for profile in /etc/apparmor.d/* %[1]s/*; do
+  [ -f $profile ] || continue
+  sudo apparmor_parser --replace --write-cache $profile || exit 1
+done
+if ! docker info 2>/dev/null | grep -qw apparmor; then
+  sudo systemctl restart docker
+fi
+`, appArmorProfilesPath)
+
+// loadModulesCommand returns the command loading the kernel modules, failing
+// with a hint when the minikube ISO doesn't include them.
+func loadModulesCommand(modules ...string) string {
+	var cmds []string
+	for _, m := range modules {
+		cmds = append(cmds, fmt.Sprintf(`sudo modprobe %[1]s || { echo "kernel module %[1]s is not available, a newer minikube ISO is needed"; exit 1; }`, m))
+	}
+	return strings.Join(cmds, "\n")
+}
+
+// swapfilePath is on the persistent disk so the swapfile survives restarts.
+const swapfilePath = "/mnt/sda1/swapfile"
+
+// GetEnableSwapCommand returns the command (re)creating a swapfile of the
+// given size if needed, and turning it on.
+func GetEnableSwapCommand(sizeMB int) string {
+	return fmt.Sprintf(`
+if [ "$(stat -c %%s %[1]s 2>/dev/null)" != "%[2]d" ]; then
+  sudo swapoff %[1]s 2>/dev/null || true
+  sudo rm -f %[1]s
+  sudo dd if=/dev/zero of=%[1]s bs=1M count=%[3]d
+  sudo chmod 600 %[1]s
+  sudo mkswap %[1]s
+fi
+grep -qs "^%[1]s " /proc/swaps || sudo swapon %[1]s
+`, swapfilePath, int64(sizeMB)*1024*1024, sizeMB)
+}
+
+// disableSwapCommand turns off and removes a swapfile left by a previous start.
+var disableSwapCommand = fmt.Sprintf(`
+if [ -f %[1]s ]; then
+  sudo swapoff %[1]s 2>/dev/null || true
+  sudo rm -f %[1]s
+fi
+`, swapfilePath)
+
+// GetHugepagesCommand returns the command allocating the hugepages and
+// mounting hugetlbfs, printing the number of pages actually allocated.
+func GetHugepagesCommand(count int) string {
+	return fmt.Sprintf(`
+sudo sysctl -q -w vm.nr_hugepages=%d
+if [ %d -gt 0 ] && ! grep -qs hugetlbfs /proc/mounts; then
+  sudo mkdir -p /dev/hugepages
+  sudo mount -t hugetlbfs nodev /dev/hugepages
+fi
+cat /proc/sys/vm/nr_hugepages
+`, count, count)
+}
+
+// wireGuardKeyPath is on the persistent disk so that clients configured
+// earlier still match the endpoint after a restart.
+const wireGuardKeyPath = "/mnt/sda1/var/lib/wireguard/private.key"
+
+// GetWireGuardUpCommand returns the command (re)creating the wg0 interface
+// with the given peer, masquerading its traffic so it can reach the cluster,
+// and printing the public key of the VM.
+func GetWireGuardUpCommand(port int, address, subnet, peerPublicKey, peerAddress string) string {
+	return loadModulesCommand("wireguard") + fmt.Sprintf(`
+if [ ! -f %[1]s ]; then
+  sudo mkdir -p $(dirname %[1]s)
+  sudo sh -c 'umask 077; wg genkey > %[1]s'
+fi
+sudo ip link del wg0 2>/dev/null || true
+sudo ip link add wg0 type wireguard
+sudo ip addr add %[3]s dev wg0
+sudo wg set wg0 listen-port %[2]d private-key %[1]s peer %[5]s allowed-ips %[6]s
+sudo ip link set wg0 up
+sudo sysctl -q -w net.ipv4.ip_forward=1
+sudo iptables -t nat -C POSTROUTING -s %[4]s ! -o wg0 -j MASQUERADE 2>/dev/null || sudo iptables -t nat -A POSTROUTING -s %[4]s ! -o wg0 -j MASQUERADE
+sudo wg show wg0 public-key
+`, wireGuardKeyPath, port, address, subnet, peerPublicKey, peerAddress)
+}
+
+// GetWireGuardDownCommand returns the command removing the wg0 interface and
+// the masquerading of the subnet.
+func GetWireGuardDownCommand(subnet string) string {
+	return fmt.Sprintf(`
+sudo ip link del wg0 2>/dev/null || true
+sudo iptables -t nat -D POSTROUTING -s %s ! -o wg0 -j MASQUERADE 2>/dev/null || true
+`, subnet)
+}
+
+// GetPruneImagesCommand returns the command removing the dangling images in
+// the VM, or only listing them for a dry run, and printing the ids of those
+// removed.
+func GetPruneImagesCommand(dryRun bool) string {
+	if dryRun {
+		return "docker images --filter dangling=true --quiet --no-trunc"
+	}
+	// Images still used by stopped containers can't be removed, and are skipped
+	return `
+for id in $(docker images --filter dangling=true --quiet --no-trunc); do
+  docker rmi $id >/dev/null 2>&1 && echo $id
+done
+true
+`
+}
+
+// GetSnapshotCreateCommand returns the command saving the etcd data, the
+// ids of the images and the Kubernetes version to the snapshot directory. localkube is stopped meanwhile
+// so that the etcd data is consistent, and started again whatever happens.
+// The snapshot is written to a hidden directory renamed once complete, which
+// is removed if the command fails, so that a failed snapshot doesn't take the name.
+func GetSnapshotCreateCommand(name string) string {
+	return fmt.Sprintf(`
+set -e
+dir=%[1]s/%[2]s
+tmp=%[1]s/.%[2]s.partial
+[ ! -d $dir ] || { echo "snapshot %[2]s already exists"; exit 1; }
+sudo systemctl is-active -q localkube || { echo "localkube is not running"; exit 1; }
+sudo rm -rf $tmp
+sudo mkdir -p $tmp
+sudo systemctl stop localkube-watchdog.timer localkube
+trap 'sudo rm -rf $tmp; sudo systemctl start localkube localkube-watchdog.timer' EXIT
+docker images --quiet --no-trunc | sort -u | sudo tee $tmp/images >/dev/null
+sudo tar -C /var/lib/localkube -czf $tmp/etcd.tar.gz etcd
+sudo cp %[3]s $tmp/version 2>/dev/null || true
+sudo mv $tmp $dir
+`, constants.SnapshotsPath, name, constants.LocalkubeVersionPath)
+}
+
+// GetSnapshotRestoreCommand returns the command replacing the etcd data with
+// that of the snapshot. The containers of the pods are removed for the kubelet
+// to recreate those of the snapshot, and the images pulled since are removed.
+func GetSnapshotRestoreCommand(name string) string {
+	return fmt.Sprintf(`
+set -e
+dir=%[1]s/%[2]s
+[ -f $dir/etcd.tar.gz ] || { echo "snapshot %[2]s does not exist"; exit 1; }
+sudo systemctl stop localkube-watchdog.timer localkube
+trap 'sudo systemctl start localkube localkube-watchdog.timer' EXIT
+sudo rm -rf /var/lib/localkube/etcd
+sudo tar -C /var/lib/localkube -xzf $dir/etcd.tar.gz
+[ ! -f $dir/version ] || sudo cp $dir/version %[3]s
+docker ps --all --quiet --filter name=k8s_ | xargs -r docker rm -f >/dev/null
+for id in $(docker images --quiet --no-trunc | sort -u); do
+  grep -qx $id $dir/images || docker rmi -f $id >/dev/null 2>&1 || true
+done
+`, constants.SnapshotsPath, name, constants.LocalkubeVersionPath)
+}
+
+// GetSnapshotDeleteCommand returns the command removing the snapshot directory.
+func GetSnapshotDeleteCommand(name string) string {
+	return fmt.Sprintf(`
+[ -d %[1]s/%[2]s ] || { echo "snapshot %[2]s does not exist"; exit 1; }
+sudo rm -rf %[1]s/%[2]s
+`, constants.SnapshotsPath, name)
+}
+
+// snapshotListCommand prints the name and creation time, in seconds since the
+// epoch, of each snapshot.
+var snapshotListCommand = fmt.Sprintf(`
+for f in %s/*/etcd.tar.gz; do
+  [ -f $f ] && echo "$(basename $(dirname $f)) $(stat -c %%Y $f)"
+done
+true
+`, constants.SnapshotsPath)
+
+// listImagesCommand prints the tagged images of the docker daemon.
+const listImagesCommand = `docker images --filter dangling=false --format '{{.Repository}}:{{.Tag}}'`
+
+// GetPreloadExtractCommand returns the command extracting the preload tarball
+// copied to the VM into the storage of the container runtime. Its daemon is
+// stopped meanwhile and started again whatever happens.
+func GetPreloadExtractCommand(storage preloadStorage) string {
+	cleanup := fmt.Sprintf("sudo rm -f %s", preloadVMPath)
+	stop := ""
+	if storage.Service != "" {
+		cleanup += "; sudo systemctl start " + storage.Service
+		stop = "sudo systemctl stop " + storage.Service
+	}
+	return fmt.Sprintf(`
+set -e
+trap '%s' EXIT
+%s
+sudo mkdir -p %[4]s
+sudo tar -C %[4]s -xzf %[3]s
+`, cleanup, stop, preloadVMPath, storage.Dir)
+}
+
+// GetImageExistsCommand returns the command failing when the docker daemon of
+// the VM does not have the image.
+func GetImageExistsCommand(image string) string {
+	return fmt.Sprintf("docker inspect --type=image %s >/dev/null 2>&1", image)
+}
+
+// GetImageLoadCommand returns the command loading the image saved to the file
+// of the VM into its docker daemon, and removing the file.
+func GetImageLoadCommand(file string) string {
+	return fmt.Sprintf("docker load -i %[1]s; status=$?; sudo rm -f %[1]s; exit $status", file)
+}
+
+// GetGuestToolInstallCommand returns the command extracting the bundle of the
+// guest tool copied to the VM to the persistent disk, replacing a previous
+// install, and linking its binaries into the PATH.
+func GetGuestToolInstallCommand(name, bundle string) string {
+	return fmt.Sprintf(`
+set -e
+trap 'sudo rm -f %[1]s' EXIT
+sudo rm -rf %[2]s/%[3]s
+sudo mkdir -p %[2]s/%[3]s
+sudo tar -C %[2]s/%[3]s -xzf %[1]s
+%[4]s
+`, bundle, constants.GuestToolsPath, name, guestToolsLinkCommand)
+}
+
+// guestToolsLinkCommand links the binaries of the installed guest tools into
+// /usr/local/bin.
+var guestToolsLinkCommand = fmt.Sprintf(`
+for f in %s/*/bin/*; do
+  if [ -f "$f" ]; then sudo ln -sf "$f" /usr/local/bin/; fi
+done
+`, constants.GuestToolsPath)
+
+// guestToolsListCommand prints the names of the installed guest tools.
+var guestToolsListCommand = fmt.Sprintf("ls -1 %s 2>/dev/null || true", constants.GuestToolsPath)
+
+// GetGuestToolRemoveCommand returns the command removing the guest tool and
+// the links to its binaries.
+func GetGuestToolRemoveCommand(name string) string {
+	return fmt.Sprintf(`
+set -e
+for f in %[1]s/%[2]s/bin/*; do
+  if [ -f "$f" ]; then sudo rm -f /usr/local/bin/$(basename "$f"); fi
+done
+sudo rm -rf %[1]s/%[2]s
+`, constants.GuestToolsPath, name)
+}
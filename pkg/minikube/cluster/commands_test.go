@@ -22,6 +22,7 @@ import (
 	"strings"
 	"testing"
 
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -63,6 +64,92 @@ func TestGetStartCommandExtraOptions(t *testing.T) {
 	}
 }
 
+func TestGetStartCommandCryptoMode(t *testing.T) {
+	for mode, expected := range map[string]bool{
+		"":                     false,
+		util.CryptoModeDefault: false,
+		util.CryptoModeFIPS:    true,
+	} {
+		startCommand, err := GetStartCommand(KubernetesConfig{CryptoMode: mode})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if actual := strings.Contains(startCommand, "--crypto-mode=fips"); actual != expected {
+			t.Errorf("Crypto mode %q: expected --crypto-mode=fips %t, got %s", mode, expected, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandSeccompDefault(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		startCommand, err := GetStartCommand(KubernetesConfig{SeccompDefault: enabled})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if actual := strings.Contains(startCommand, "--seccomp-default"); actual != enabled {
+			t.Errorf("Expected --seccomp-default %t, got %s", enabled, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandClusterCIDRs(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if strings.Contains(startCommand, "--pod-network-cidr") || strings.Contains(startCommand, "--service-cluster-ip-range") {
+		t.Errorf("Expected the default networks, got %s", startCommand)
+	}
+	startCommand, err = GetStartCommand(KubernetesConfig{PodNetworkCIDR: "172.16.0.0/16", ServiceClusterIPRange: "172.17.0.0/16"})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, expected := range []string{"--pod-network-cidr=172.16.0.0/16", "--service-cluster-ip-range=172.17.0.0/16", "--dns-ip=172.17.0.10"} {
+		if !strings.Contains(startCommand, expected) {
+			t.Errorf("Expected %s in the start command, got %s", expected, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandAPIServerPort(t *testing.T) {
+	var tests = []struct {
+		port     int
+		expected string
+	}{
+		{0, ""},
+		{constants.APIServerPort, ""},
+		{9443, "--apiserver-port=9443"},
+	}
+	for _, test := range tests {
+		startCommand, err := GetStartCommand(KubernetesConfig{APIServerPort: test.port})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if actual := strings.Contains(startCommand, "--apiserver-port"); actual != (test.expected != "") {
+			t.Errorf("Unexpected --apiserver-port for port %d: %s", test.port, startCommand)
+		}
+		if test.expected != "" && !strings.Contains(startCommand, test.expected) {
+			t.Errorf("Expected %s in the start command, got %s", test.expected, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandWatchdog(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, expected := range []string{
+		"tee " + constants.LocalkubeWatchdogPath,
+		"sudo systemctl enable localkube-watchdog.timer",
+		"rm -f " + constants.LocalkubeWatchdogStatusPath,
+	} {
+		if !strings.Contains(startCommand, expected) {
+			t.Fatalf("Expected start command to contain: %s. Got: %s", expected, startCommand)
+		}
+	}
+}
+
 func flagMapToSetFlags(flagMap map[string]string) {
 	for flag, val := range flagMap {
 		gflag.Set(flag, val)
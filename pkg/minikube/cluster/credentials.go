@@ -28,14 +28,15 @@ var (
 	internalIP = net.ParseIP(util.DefaultServiceClusterIP)
 )
 
-func GenerateCerts(caCert, caKey, pub, priv string, ip net.IP, name string) error {
-	if !(util.CanReadFile(caCert) && util.CanReadFile(caKey)) {
+func GenerateCerts(caCert, caKey, pub, priv string, ip net.IP, name string, extraIPs ...net.IP) error {
+	// A CA generated before the crypto mode was restricted is replaced
+	if !(util.CanReadFile(caCert) && util.CanReadFile(caKey)) || !util.KeyMeetsCryptoMode(caKey) {
 		if err := util.GenerateCACert(caCert, caKey, name); err != nil {
 			return errors.Wrap(err, "Error generating certificate")
 		}
 	}
 
-	ips := []net.IP{ip, internalIP}
+	ips := append([]net.IP{ip, internalIP}, extraIPs...)
 	if err := util.GenerateSignedCert(pub, priv, ips, util.GetAlternateDNS(util.DefaultDNSDomain), caCert, caKey); err != nil {
 		return errors.Wrap(err, "Error generating signed cert")
 	}
@@ -0,0 +1,73 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// dockerDaemonConfigPath is the config of the docker daemon of the VM, which
+// the ISO loses when the VM restarts
+const dockerDaemonConfigPath = "/etc/docker/daemon.json"
+
+// dockerDaemonConfigCommand returns the command writing the config of the
+// docker daemon, or removing it when there is none, then restarting the
+// daemon if restart is set.
+func dockerDaemonConfigCommand(daemonConfig string, restart bool) string {
+	cmd := fmt.Sprintf("sudo rm -f %s", dockerDaemonConfigPath)
+	if daemonConfig != "" {
+		cmd = fmt.Sprintf("sudo mkdir -p %s && printf %%s %q | sudo tee %s >/dev/null", path.Dir(dockerDaemonConfigPath), daemonConfig, dockerDaemonConfigPath)
+	}
+	if restart {
+		cmd += " && sudo systemctl restart docker"
+	}
+	return cmd
+}
+
+// dockerDaemonConfigRecord is where the config written into the machine is
+// kept, for it to be removed once none is set
+func dockerDaemonConfigRecord(machineName string) string {
+	return constants.MakeMiniPath("machines", machineName, "daemon.json")
+}
+
+// configureDockerDaemon writes the config of the docker daemon of the VM,
+// which the daemon reads when it restarts, or removes the one written before
+// when none is set.
+func configureDockerDaemon(h sshAble, config MachineConfig, restart bool) error {
+	if config.VMDriver == constants.DriverNone {
+		return nil
+	}
+	record := dockerDaemonConfigRecord(config.machineName())
+	if config.DockerDaemonConfig == "" {
+		if _, err := os.Stat(record); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	if out, err := h.RunSSHCommand(dockerDaemonConfigCommand(config.DockerDaemonConfig, restart)); err != nil {
+		return errors.Wrapf(err, "Error writing the config of the docker daemon: %s", out)
+	}
+	if config.DockerDaemonConfig == "" {
+		return os.Remove(record)
+	}
+	return ioutil.WriteFile(record, []byte(config.DockerDaemonConfig), 0644)
+}
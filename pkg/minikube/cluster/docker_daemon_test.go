@@ -0,0 +1,67 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestConfigureDockerDaemon(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	config := MachineConfig{VMDriver: "virtualbox", DockerDaemonConfig: "{\n  \"live-restore\": true\n}\n"}
+	record := dockerDaemonConfigRecord(config.machineName())
+	if err := os.MkdirAll(filepath.Dir(record), 0755); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// Nothing was written yet
+	h := tests.NewMockHost()
+	if err := configureDockerDaemon(h, MachineConfig{VMDriver: "virtualbox"}, false); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(h.Commands) != 0 {
+		t.Errorf("Expected no command to be run, got %v", h.Commands)
+	}
+
+	write := dockerDaemonConfigCommand(config.DockerDaemonConfig, true)
+	if err := configureDockerDaemon(h, config, true); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[write] != 1 {
+		t.Errorf("Expected command to be run: %s", write)
+	}
+	if _, err := os.Stat(record); err != nil {
+		t.Errorf("Expected the config to be recorded: %s", err)
+	}
+
+	remove := dockerDaemonConfigCommand("", false)
+	config.DockerDaemonConfig = ""
+	if err := configureDockerDaemon(h, config, false); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[remove] != 1 {
+		t.Errorf("Expected command to be run: %s", remove)
+	}
+	if _, err := os.Stat(record); !os.IsNotExist(err) {
+		t.Errorf("Expected the record to be removed, got %v", err)
+	}
+}
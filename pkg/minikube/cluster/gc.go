@@ -0,0 +1,40 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PruneImages removes the dangling images in the VM, those no longer tagged
+// nor used by a container, and returns their ids. With dryRun, the images
+// are only listed.
+func PruneImages(h sshAble, dryRun bool) ([]string, error) {
+	out, err := h.RunSSHCommand(GetPruneImagesCommand(dryRun))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error pruning images: %s", out)
+	}
+	var ids []string
+	for _, id := range strings.Split(out, "\n") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
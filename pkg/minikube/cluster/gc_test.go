@@ -0,0 +1,45 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestPruneImages(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[GetPruneImagesCommand(false)] = "sha256:aaa\nsha256:bbb\n"
+	ids, err := PruneImages(h, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"sha256:aaa", "sha256:bbb"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected removed images %v, got %v", expected, ids)
+	}
+
+	h = tests.NewMockHost()
+	ids, err = PruneImages(h, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(ids) != 0 || h.Commands[GetPruneImagesCommand(true)] != 1 {
+		t.Errorf("Expected the dangling images to only be listed, got %v", ids)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// guestFeatures maps the optional guest features to the command enabling them in the VM.
+var guestFeatures = map[string]string{
+	"binfmt":    enableBinfmtCommand,
+	"sctp":      loadModulesCommand("sctp"),
+	"ipvs":      loadModulesCommand("ip_vs", "ip_vs_rr", "ip_vs_wrr", "ip_vs_sh", "nf_conntrack_ipv4"),
+	"wireguard": loadModulesCommand("wireguard"),
+	"selinux":   enableSELinuxCommand,
+	"apparmor":  enableAppArmorCommand,
+}
+
+// exclusiveGuestFeatures are the guest features of which only one can be
+// enabled, the kernel running a single major security module.
+var exclusiveGuestFeatures = []string{"selinux", "apparmor"}
+
+// GuestFeatures returns the names of the optional guest features which can be enabled.
+func GuestFeatures() []string {
+	names := make([]string, 0, len(guestFeatures))
+	for name := range guestFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnableGuestFeatures enables the given optional features in the VM.
+func EnableGuestFeatures(h sshAble, features []string) error {
+	var exclusive []string
+	for _, feature := range features {
+		for _, e := range exclusiveGuestFeatures {
+			if feature == e {
+				exclusive = append(exclusive, feature)
+			}
+		}
+	}
+	if len(exclusive) > 1 {
+		return errors.Errorf("guest features %v cannot be enabled together", exclusive)
+	}
+	for _, feature := range features {
+		cmd, ok := guestFeatures[feature]
+		if !ok {
+			return errors.Errorf("unknown guest feature %q, supported features are: %v", feature, GuestFeatures())
+		}
+		glog.Infoln("Enabling guest feature", feature)
+		if out, err := h.RunSSHCommand(cmd); err != nil {
+			return errors.Wrapf(err, "Error enabling guest feature %s: %s", feature, out)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestEnableGuestFeatures(t *testing.T) {
+	var tcs = []struct {
+		description string
+		features    []string
+		shouldErr   bool
+	}{
+		{
+			description: "no features",
+		},
+		{
+			description: "binfmt",
+			features:    []string{"binfmt"},
+		},
+		{
+			description: "networking modules",
+			features:    []string{"sctp", "ipvs", "wireguard"},
+		},
+		{
+			description: "selinux",
+			features:    []string{"selinux"},
+		},
+		{
+			description: "apparmor",
+			features:    []string{"apparmor", "binfmt"},
+		},
+		{
+			description: "unknown feature",
+			features:    []string{"binfmt", "unknown"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			h := tests.NewMockHost()
+			err := EnableGuestFeatures(h, test.features)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			for _, f := range test.features {
+				if cmd, ok := guestFeatures[f]; ok && h.Commands[cmd] != 1 {
+					t.Errorf("Expected command for guest feature %s to be run", f)
+				}
+			}
+		})
+	}
+}
+
+func TestEnableExclusiveGuestFeatures(t *testing.T) {
+	h := tests.NewMockHost()
+	if err := EnableGuestFeatures(h, []string{"binfmt", "selinux", "apparmor"}); err == nil {
+		t.Fatalf("Expected an error enabling both SELinux and AppArmor")
+	}
+	if len(h.Commands) != 0 {
+		t.Errorf("Expected no feature to be enabled, got %v", h.Commands)
+	}
+}
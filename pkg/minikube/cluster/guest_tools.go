@@ -0,0 +1,138 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	download "github.com/jimmidyson/go-download"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+// guestBundleSuffix is the extension of the bundles of guest tools, tarballs
+// of a bin directory of static binaries.
+const guestBundleSuffix = ".tar.gz"
+
+var validGuestToolName = regexp.MustCompile(`^[a-zA-Z0-9][-_.a-zA-Z0-9]*$`)
+
+// GuestBundleName returns the name of the guest tool of a bundle, given as a
+// name or the path of its tarball.
+func GuestBundleName(bundle string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(bundle), guestBundleSuffix)
+	if err := validateGuestToolName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func validateGuestToolName(name string) error {
+	if !validGuestToolName.MatchString(name) {
+		return fmt.Errorf("%q is not a valid guest tool name, it must consist of letters, digits, '-', '_' or '.', and start with a letter or digit", name)
+	}
+	return nil
+}
+
+// GuestBundleCacheFilepath returns where the bundle of the guest tool is
+// cached.
+func GuestBundleCacheFilepath(name string) string {
+	return constants.MakeMiniPath("cache", "guest-bundles", name+guestBundleSuffix)
+}
+
+// CacheGuestBundle downloads the published bundle of the guest tool, unless
+// it is cached already, and returns its path.
+func CacheGuestBundle(name string) (string, error) {
+	cachePath := GuestBundleCacheFilepath(name)
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+	url := constants.GuestBundleURLPrefix + name + guestBundleSuffix
+	opts := download.FileOptions{
+		Mkdirs: download.MkdirAll,
+		Options: download.Options{
+			Checksum:     url + constants.ShaSuffix,
+			ChecksumHash: crypto.SHA256,
+			ProgressBars: &download.ProgressBarOptions{
+				MaxWidth: 80,
+			},
+		},
+	}
+	fmt.Printf("Downloading the %s bundle\n", name)
+	if err := download.ToFile(url, cachePath, opts); err != nil {
+		return "", errors.Wrapf(err, "Error downloading the %s bundle from %s", name, url)
+	}
+	return cachePath, nil
+}
+
+// InstallGuestTool copies the bundle of the guest tool to the VM, extracts it
+// to the persistent disk, and links its binaries into the PATH.
+func InstallGuestTool(h sshAble, d drivers.Driver, name, bundle string) error {
+	vmPath := path.Join("/tmp", name+guestBundleSuffix)
+	f, err := assets.NewFileAsset(bundle, path.Dir(vmPath), path.Base(vmPath), "0644")
+	if err != nil {
+		return errors.Wrapf(err, "Error opening the bundle %s", bundle)
+	}
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return errors.Wrap(err, "Error creating new ssh client")
+	}
+	if err := sshutil.TransferFile(f, client); err != nil {
+		return errors.Wrapf(err, "Error copying the %s bundle to the VM", name)
+	}
+	if out, err := h.RunSSHCommand(GetGuestToolInstallCommand(name, vmPath)); err != nil {
+		return errors.Wrapf(err, "Error installing %s: %s", name, out)
+	}
+	return nil
+}
+
+// LinkGuestTools links the binaries of the installed guest tools into the
+// PATH, which doesn't survive a restart of the VM.
+func LinkGuestTools(h sshAble) error {
+	if out, err := h.RunSSHCommand(guestToolsLinkCommand); err != nil {
+		return errors.Wrapf(err, "Error linking the guest tools: %s", out)
+	}
+	return nil
+}
+
+// ListGuestTools returns the names of the installed guest tools.
+func ListGuestTools(h sshAble) ([]string, error) {
+	out, err := h.RunSSHCommand(guestToolsListCommand)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing the guest tools: %s", out)
+	}
+	return strings.Fields(out), nil
+}
+
+// RemoveGuestTool removes the guest tool and the links to its binaries.
+func RemoveGuestTool(h sshAble, name string) error {
+	if err := validateGuestToolName(name); err != nil {
+		return err
+	}
+	if out, err := h.RunSSHCommand(GetGuestToolRemoveCommand(name)); err != nil {
+		return errors.Wrapf(err, "Error removing %s: %s", name, out)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestGuestBundleName(t *testing.T) {
+	var tests = []struct {
+		bundle    string
+		name      string
+		shouldErr bool
+	}{
+		{bundle: "tcpdump", name: "tcpdump"},
+		{bundle: "/tmp/bundles/iperf3.tar.gz", name: "iperf3"},
+		{bundle: "strace-4.15.tar.gz", name: "strace-4.15"},
+		{bundle: ".hidden", shouldErr: true},
+		{bundle: "tools;rm", shouldErr: true},
+	}
+	for _, test := range tests {
+		name, err := GuestBundleName(test.bundle)
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error for %q: %s", test.bundle, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected an error for %q", test.bundle)
+		}
+		if name != test.name {
+			t.Errorf("Expected %q for %q, got %q", test.name, test.bundle, name)
+		}
+	}
+}
+
+func TestListGuestTools(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[guestToolsListCommand] = "iperf3\ntcpdump\n"
+
+	tools, err := ListGuestTools(h)
+	if err != nil {
+		t.Fatalf("Unexpected error listing the guest tools: %s", err)
+	}
+	if expected := []string{"iperf3", "tcpdump"}; !reflect.DeepEqual(tools, expected) {
+		t.Errorf("Expected %v, got %v", expected, tools)
+	}
+}
+
+func TestRemoveGuestTool(t *testing.T) {
+	h := tests.NewMockHost()
+	if err := RemoveGuestTool(h, "tcpdump"); err != nil {
+		t.Fatalf("Unexpected error removing the guest tool: %s", err)
+	}
+	if _, ok := h.Commands[GetGuestToolRemoveCommand("tcpdump")]; !ok {
+		t.Errorf("Expected the remove command to run, got %v", h.Commands)
+	}
+	if err := RemoveGuestTool(h, "../localkube"); err == nil {
+		t.Errorf("Expected an error removing an invalid name")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"path"
+
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// HostAliasIP returns the IP the VM and the pods reach the host at: its
+// address on the network of the VM, which depends on the driver, or the IP
+// of this machine for the none driver.
+func HostAliasIP(h *host.Host) (net.IP, error) {
+	if h.DriverName != constants.DriverNone {
+		return getVMHostIP(h)
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the IP of this machine")
+	}
+	return net.ParseIP(ip), nil
+}
+
+// GetHostAliasCommand returns the command making constants.HostAlias resolve
+// to the IP in the cluster DNS, which answers from the hosts files of
+// constants.HostAliasesDir, and with etcHosts in the /etc/hosts of the VM.
+func GetHostAliasCommand(ip net.IP, etcHosts bool) string {
+	entry := fmt.Sprintf("%s\t%s", ip, constants.HostAlias)
+	cmd := fmt.Sprintf("sudo mkdir -p %s && printf '%%s\\n' '%s' | sudo tee %s >/dev/null",
+		constants.HostAliasesDir, entry, path.Join(constants.HostAliasesDir, "minikube"))
+	if etcHosts {
+		cmd += fmt.Sprintf(" && sudo sed -i '/[[:space:]]%s$/d' /etc/hosts && printf '%%s\\n' '%s' | sudo tee -a /etc/hosts >/dev/null",
+			constants.HostAlias, entry)
+	}
+	return cmd
+}
+
+// ConfigureHostAlias makes constants.HostAlias resolve to the IP of the host.
+// It is set again on each start, the VM losing its /etc/hosts when it
+// restarts and the IP of the host changing with its network.
+func ConfigureHostAlias(h sshAble, ip net.IP, etcHosts bool) error {
+	if out, err := h.RunSSHCommand(GetHostAliasCommand(ip, etcHosts)); err != nil {
+		return errors.Wrapf(err, "Error setting up %s: %s", constants.HostAlias, out)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestConfigureHostAlias(t *testing.T) {
+	ip := net.ParseIP("10.0.2.2")
+	h := tests.NewMockHost()
+	if err := ConfigureHostAlias(h, ip, true); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	cmd := GetHostAliasCommand(ip, true)
+	if h.Commands[cmd] != 1 {
+		t.Errorf("Expected the alias to be set up")
+	}
+	for _, s := range []string{"'10.0.2.2\thost.minikube.internal'", "/var/lib/localkube/hosts/minikube", "/etc/hosts"} {
+		if !strings.Contains(cmd, s) {
+			t.Errorf("Expected %q in %q", s, cmd)
+		}
+	}
+	if cmd := GetHostAliasCommand(ip, false); strings.Contains(cmd, "/etc/hosts") {
+		t.Errorf("Expected /etc/hosts to be left alone, got %q", cmd)
+	}
+
+	h = tests.NewMockHost()
+	h.Error = "tee: /var/lib/localkube/hosts/minikube: Read-only file system"
+	if err := ConfigureHostAlias(h, ip, false); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
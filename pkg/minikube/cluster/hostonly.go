@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateHostOnlyCIDR checks the CIDR of the host-only network of the
+// virtualbox driver: the IPv4 address of the host in the network, and its
+// prefix, e.g. 192.168.99.1/24.
+func ValidateHostOnlyCIDR(cidr string) error {
+	ip, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing the host-only CIDR")
+	}
+	if ip.To4() == nil {
+		return errors.Errorf("The host-only network %s is not an IPv4 network", cidr)
+	}
+	if ones, _ := n.Mask.Size(); ones > 30 {
+		return errors.Errorf("The host-only network %s is too small for the VM, use a prefix of at most /30", cidr)
+	}
+	broadcast := make(net.IP, len(n.IP))
+	for i := range n.IP {
+		broadcast[i] = n.IP[i] | ^n.Mask[i]
+	}
+	if ip.Equal(n.IP) || ip.Equal(broadcast) {
+		return errors.Errorf("%s is the address of the network, use the address of the host in it, e.g. %s", cidr, hostOnlyExample(n))
+	}
+	return nil
+}
+
+func hostOnlyExample(n *net.IPNet) string {
+	ip := make(net.IP, len(n.IP))
+	copy(ip, n.IP)
+	ip[len(ip)-1]++
+	ones, _ := n.Mask.Size()
+	return fmt.Sprintf("%s/%d", ip, ones)
+}
+
+// CheckHostOnlyCIDR checks that the host-only network does not overlap the
+// networks of the interfaces of the host, e.g. the one of a VPN, which the
+// traffic to the VM would be routed to. The host-only adapters of virtualbox
+// are left out, the driver reuses the one of the network.
+func CheckHostOnlyCIDR(cidr string) error {
+	if err := ValidateHostOnlyCIDR(cidr); err != nil {
+		return err
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return errors.Wrap(err, "Error listing the interfaces of the host")
+	}
+	addrs := map[string][]net.Addr{}
+	for _, iface := range ifaces {
+		if isHostOnlyAdapter(iface.Name) {
+			continue
+		}
+		if addrs[iface.Name], err = iface.Addrs(); err != nil {
+			return errors.Wrapf(err, "Error listing the addresses of %s", iface.Name)
+		}
+	}
+	return hostOnlyCollision(cidr, addrs)
+}
+
+// isHostOnlyAdapter tells the host-only adapters of virtualbox by their names,
+// vboxnetN on linux and macOS, "VirtualBox Host-Only Ethernet Adapter" on
+// windows.
+func isHostOnlyAdapter(name string) bool {
+	return strings.HasPrefix(name, "vboxnet") || strings.Contains(name, "VirtualBox Host-Only")
+}
+
+func hostOnlyCollision(cidr string, addrs map[string][]net.Addr) error {
+	_, hostOnly, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range addrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, addr := range addrs[name] {
+			n, ok := addr.(*net.IPNet)
+			if !ok || n.IP.To4() == nil || n.IP.IsLoopback() {
+				continue
+			}
+			if hostOnly.Contains(n.IP) || n.Contains(hostOnly.IP) {
+				return errors.Errorf("The host-only network %s overlaps the network %s of the interface %s, set another one with --host-only-cidr or minikube config set host-only-cidr", cidr, n, name)
+			}
+		}
+	}
+	return nil
+}
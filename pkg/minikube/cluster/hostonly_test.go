@@ -0,0 +1,71 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestValidateHostOnlyCIDR(t *testing.T) {
+	for cidr, expected := range map[string]string{
+		"192.168.99.1/24": "",
+		"10.254.0.1/16":   "",
+		"172.30.5.9/30":   "",
+		"192.168.99.1":    "Error parsing",
+		"fd00::1/64":      "not an IPv4 network",
+		"192.168.99.1/31": "too small",
+		"192.168.99.0/24": "e.g. 192.168.99.1/24",
+		"10.0.0.3/30":     "is the address of the network",
+	} {
+		err := ValidateHostOnlyCIDR(cidr)
+		if expected == "" && err != nil {
+			t.Errorf("Unexpected error for %s: %s", cidr, err)
+		}
+		if expected != "" && (err == nil || !strings.Contains(err.Error(), expected)) {
+			t.Errorf("Expected an error containing %q for %s, got %v", expected, cidr, err)
+		}
+	}
+}
+
+func TestHostOnlyCollision(t *testing.T) {
+	ipNet := func(cidr string) net.Addr {
+		ip, n, _ := net.ParseCIDR(cidr)
+		return &net.IPNet{IP: ip, Mask: n.Mask}
+	}
+	addrs := map[string][]net.Addr{
+		"lo":   {ipNet("127.0.0.1/8")},
+		"eth0": {ipNet("192.168.1.20/24"), ipNet("fe80::1/64")},
+		"tun0": {ipNet("10.8.0.6/16")},
+	}
+	for cidr, collides := range map[string]bool{
+		"192.168.99.1/24": false,
+		"10.8.99.1/24":    true,
+		"10.0.0.1/8":      true,
+		"192.168.1.1/24":  true,
+		"127.0.10.1/24":   false,
+	} {
+		err := hostOnlyCollision(cidr, addrs)
+		if collides && (err == nil || !strings.Contains(err.Error(), "overlaps")) {
+			t.Errorf("Expected %s to overlap the networks of the host, got %v", cidr, err)
+		}
+		if !collides && err != nil {
+			t.Errorf("Unexpected error for %s: %s", cidr, err)
+		}
+	}
+}
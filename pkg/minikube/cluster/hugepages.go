@@ -0,0 +1,42 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// ConfigureHugepages allocates the given number of hugepages in the VM and
+// returns how many could be allocated, which can be less when the VM memory
+// is fragmented.
+func ConfigureHugepages(h sshAble, count int) (int, error) {
+	glog.Infof("Allocating %d hugepages of %dMB", count, constants.HugepageSizeMB)
+	out, err := h.RunSSHCommand(GetHugepagesCommand(count))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error allocating hugepages: %s", out)
+	}
+	allocated, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error parsing the number of allocated hugepages: %s", out)
+	}
+	return allocated, nil
+}
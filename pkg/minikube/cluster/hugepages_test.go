@@ -0,0 +1,65 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestConfigureHugepages(t *testing.T) {
+	var tcs = []struct {
+		description string
+		output      string
+		expected    int
+		shouldErr   bool
+	}{
+		{
+			description: "all allocated",
+			output:      "512\n",
+			expected:    512,
+		},
+		{
+			description: "partially allocated",
+			output:      "100\n",
+			expected:    100,
+		},
+		{
+			description: "unexpected output",
+			output:      "sysctl: permission denied\n",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			h := tests.NewMockHost()
+			h.CommandOutput[GetHugepagesCommand(512)] = test.output
+			allocated, err := ConfigureHugepages(h, 512)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if allocated != test.expected {
+				t.Errorf("Expected %d hugepages to be allocated, got %d", test.expected, allocated)
+			}
+		})
+	}
+}
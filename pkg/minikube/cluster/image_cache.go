@@ -0,0 +1,122 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+// imageVMDir is where the cached images are copied to in the VM, before
+// being loaded.
+const imageVMDir = "/tmp/cached-images"
+
+var imageFileReplacer = strings.NewReplacer("/", "_", ":", "_", "@", "_")
+
+// ImageCacheFilepath returns where the image is cached, as saved by docker.
+func ImageCacheFilepath(image string) string {
+	return constants.MakeMiniPath("cache", "images", imageFileReplacer.Replace(image)+".tar")
+}
+
+// IsImageCached reports whether the image is in the cache.
+func IsImageCached(image string) bool {
+	_, err := os.Stat(ImageCacheFilepath(image))
+	return err == nil
+}
+
+// runDocker runs the docker client of the host, it is replaced in tests.
+var runDocker = func(args ...string) ([]byte, error) {
+	return exec.Command("docker", args...).CombinedOutput()
+}
+
+// CacheImages pulls the images which are not cached yet with the docker
+// daemon of the host and saves them to the cache.
+func CacheImages(images []string) error {
+	for _, image := range images {
+		if IsImageCached(image) {
+			continue
+		}
+		fmt.Printf("Caching image %s\n", image)
+		if out, err := runDocker("pull", image); err != nil {
+			return errors.Wrapf(err, "Error pulling image %s, caching images needs a docker daemon on this machine: %s", image, out)
+		}
+		cachePath := ImageCacheFilepath(image)
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+			return errors.Wrap(err, "Error creating the image cache")
+		}
+		// Saved to a temporary file first, so that an interrupted save is not
+		// taken for a cached image
+		if out, err := runDocker("save", "-o", cachePath+".tmp", image); err != nil {
+			os.Remove(cachePath + ".tmp")
+			return errors.Wrapf(err, "Error saving image %s: %s", image, out)
+		}
+		if err := os.Rename(cachePath+".tmp", cachePath); err != nil {
+			return errors.Wrapf(err, "Error caching image %s", image)
+		}
+	}
+	return nil
+}
+
+// LoadCachedImages loads the cached images missing from the docker daemon of
+// the VM into it, and returns them.
+func LoadCachedImages(h sshAble, d drivers.Driver, images []string) ([]string, error) {
+	var missing []string
+	for _, image := range images {
+		if !IsImageCached(image) {
+			continue
+		}
+		if _, err := h.RunSSHCommand(GetImageExistsCommand(image)); err != nil {
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating new ssh client")
+	}
+	var loaded []string
+	for _, image := range missing {
+		cachePath := ImageCacheFilepath(image)
+		f, err := assets.NewFileAsset(cachePath, imageVMDir, filepath.Base(cachePath), "0644")
+		if err != nil {
+			return loaded, err
+		}
+		if err := sshutil.TransferFile(f, client); err != nil {
+			return loaded, errors.Wrapf(err, "Error copying image %s to the VM", image)
+		}
+		if out, err := h.RunSSHCommand(GetImageLoadCommand(path.Join(imageVMDir, filepath.Base(cachePath)))); err != nil {
+			return loaded, errors.Wrapf(err, "Error loading image %s: %s", image, out)
+		}
+		glog.Infof("Loaded image %s from the cache", image)
+		loaded = append(loaded, image)
+	}
+	return loaded, nil
+}
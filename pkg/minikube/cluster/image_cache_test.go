@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestImageCacheFilepath(t *testing.T) {
+	path := ImageCacheFilepath("gcr.io/google_containers/pause-amd64:3.0")
+	if filepath.Base(path) != "gcr.io_google_containers_pause-amd64_3.0.tar" {
+		t.Errorf("Unexpected image cache path %s", path)
+	}
+	if filepath.Dir(path) != constants.MakeMiniPath("cache", "images") {
+		t.Errorf("Expected the image to be cached under the cache directory, got %s", path)
+	}
+}
+
+func TestCacheImages(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	var calls []string
+	defer func(run func(...string) ([]byte, error)) { runDocker = run }(runDocker)
+	runDocker = func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "save" {
+			return nil, ioutil.WriteFile(args[2], []byte("image"), 0644)
+		}
+		return nil, nil
+	}
+
+	images := []string{"gcr.io/google_containers/pause-amd64:3.0"}
+	if err := CacheImages(images); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !IsImageCached(images[0]) {
+		t.Fatalf("Expected %s to be cached", images[0])
+	}
+	if len(calls) != 2 || calls[0] != "pull "+images[0] {
+		t.Errorf("Expected the image to be pulled and saved, got %v", calls)
+	}
+
+	calls = nil
+	if err := CacheImages(images); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected a cached image not to be pulled again, got %v", calls)
+	}
+}
+
+func TestCacheImagesPullError(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	defer func(run func(...string) ([]byte, error)) { runDocker = run }(runDocker)
+	runDocker = func(args ...string) ([]byte, error) {
+		return []byte("Cannot connect to the Docker daemon"), errors.New("exit status 1")
+	}
+
+	if err := CacheImages([]string{"busybox"}); err == nil {
+		t.Fatalf("Expected an error without a docker daemon")
+	}
+	if IsImageCached("busybox") {
+		t.Errorf("Expected the image not to be cached")
+	}
+}
+
+func TestLoadCachedImagesSkipsPresentImages(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	cachePath := ImageCacheFilepath("busybox")
+	os.MkdirAll(filepath.Dir(cachePath), 0755)
+	ioutil.WriteFile(cachePath, []byte("image"), 0644)
+
+	h := tests.NewMockHost()
+	loaded, err := LoadCachedImages(h, &tests.MockDriver{}, []string{"busybox", "alpine"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(loaded) != 0 {
+		t.Errorf("Expected no image to be loaded, got %v", loaded)
+	}
+	if _, ok := h.Commands[GetImageExistsCommand("busybox")]; !ok {
+		t.Errorf("Expected the cached image to be looked up in the VM")
+	}
+	if _, ok := h.Commands[GetImageExistsCommand("alpine")]; ok {
+		t.Errorf("Expected an image which is not cached not to be looked up")
+	}
+}
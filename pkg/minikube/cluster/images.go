@@ -0,0 +1,60 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/scan"
+)
+
+// ListImages returns the tagged images of the docker daemon of the VM, sorted.
+func ListImages(h sshAble) ([]string, error) {
+	out, err := h.RunSSHCommand(listImagesCommand)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing images: %s", out)
+	}
+	var images []string
+	for _, image := range strings.Split(out, "\n") {
+		image = strings.TrimSpace(image)
+		if image == "" || strings.Contains(image, "<none>") {
+			continue
+		}
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// ScanImage scans the image of the VM with the scanner, and returns the
+// findings of the severities.
+func ScanImage(h sshAble, s scan.Scanner, image string, severities []string) ([]scan.Finding, error) {
+	if err := scan.ValidateImageName(image); err != nil {
+		return nil, err
+	}
+	out, err := h.RunSSHCommand(s.Command(image, severities))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error scanning image %s: %s", image, out)
+	}
+	findings, err := s.Parse(image, out)
+	if err != nil {
+		return nil, err
+	}
+	return scan.Filter(findings, severities), nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/scan"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestListImages(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[listImagesCommand] = "redis:3.2\nbusybox:latest\n<none>:<none>\nlocalhost:5000/app:<none>\n"
+	images, err := ListImages(h)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"busybox:latest", "redis:3.2"}; !reflect.DeepEqual(images, expected) {
+		t.Errorf("Expected %v, got %v", expected, images)
+	}
+}
+
+func TestScanImage(t *testing.T) {
+	s, err := scan.Get("trivy", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	severities := []string{"HIGH", "CRITICAL"}
+	h := tests.NewMockHost()
+	h.CommandOutput[s.Command("redis:3.2", severities)] = `{"Results": [{"Vulnerabilities": [
+	  {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+	  {"VulnerabilityID": "CVE-2", "Severity": "LOW"}
+	]}]}`
+	findings, err := ScanImage(h, s, "redis:3.2", severities)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(findings) != 1 || findings[0].ID != "CVE-1" || findings[0].Image != "redis:3.2" {
+		t.Errorf("Expected only the critical finding of redis:3.2, got %v", findings)
+	}
+
+	h = tests.NewMockHost()
+	if _, err := ScanImage(h, s, "$(reboot)", severities); err == nil {
+		t.Errorf("Expected an error for an invalid image")
+	}
+	if len(h.Commands) != 0 {
+		t.Errorf("Expected no command to run, got %v", h.Commands)
+	}
+}
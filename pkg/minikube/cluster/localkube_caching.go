@@ -37,8 +37,14 @@ type localkubeCacher struct {
 }
 
 func (l *localkubeCacher) getLocalkubeCacheFilepath() string {
-	return filepath.Join(constants.GetMinipath(), "cache", "localkube",
-		filepath.Base(url.QueryEscape("localkube-"+l.k8sConf.KubernetesVersion)))
+	return LocalkubeCacheFilepath(l.k8sConf.KubernetesVersion)
+}
+
+// LocalkubeCacheFilepath returns where the localkube binary of the
+// Kubernetes version is cached.
+func LocalkubeCacheFilepath(kubernetesVersion string) string {
+	return constants.MakeMiniPath("cache", "localkube",
+		filepath.Base(url.QueryEscape("localkube-"+kubernetesVersion)))
 }
 
 func (l *localkubeCacher) isLocalkubeCached() bool {
@@ -99,3 +105,25 @@ func (l *localkubeCacher) genLocalkubeFileFromFile() (assets.CopyableFile, error
 	}
 	return localkubeFile, nil
 }
+
+// CacheLocalkube downloads the localkube binary of the Kubernetes version,
+// given as a version or a URL, unless it is bundled with minikube, given as a
+// file or cached already.
+func CacheLocalkube(config KubernetesConfig) error {
+	if IsLocalkubeCached(config) {
+		return nil
+	}
+	l := localkubeCacher{config}
+	return l.downloadAndCacheLocalkube()
+}
+
+// IsLocalkubeCached reports whether the localkube binary of the Kubernetes
+// version is available without downloading it: bundled with minikube, given
+// as a file, or cached.
+func IsLocalkubeCached(config KubernetesConfig) bool {
+	if !localkubeURIWasSpecified(config) || strings.HasPrefix(config.KubernetesVersion, fileScheme+"://") {
+		return true
+	}
+	l := localkubeCacher{config}
+	return l.isLocalkubeCached()
+}
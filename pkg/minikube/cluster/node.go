@@ -0,0 +1,202 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/util"
+)
+
+// workerCerts are the certs a worker node authenticates to the apiserver with,
+// and the additional control planes serve the apiserver with.
+var workerCerts = []string{"ca.crt", "apiserver.crt", "apiserver.key"}
+
+var validNodeName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// The additional control planes are the nodes named cp02, cp03...
+var controlPlaneNodeName = regexp.MustCompile(`^cp[0-9]{2,}$`)
+
+// ValidateNodeName checks that the name can be used in the name of the VM and
+// of the Kubernetes node.
+func ValidateNodeName(name string) error {
+	if !validNodeName.MatchString(name) {
+		return fmt.Errorf("%q is not a valid node name, it must consist of lower case letters, digits or '-', and start and end with a letter or digit", name)
+	}
+	return nil
+}
+
+// NextNodeName returns the first of m02, m03... which is not a node yet.
+func NextNodeName(nodes []string) string {
+	taken := map[string]bool{}
+	for _, n := range nodes {
+		taken[n] = true
+	}
+	for i := 2; ; i++ {
+		if name := fmt.Sprintf("m%02d", i); !taken[name] {
+			return name
+		}
+	}
+}
+
+// ControlPlaneNodeName returns the name of the i-th control plane, the
+// minikube VM being the first.
+func ControlPlaneNodeName(i int) string {
+	return fmt.Sprintf("cp%02d", i)
+}
+
+// IsControlPlaneNode reports whether the node is an additional control plane
+// rather than a worker.
+func IsControlPlaneNode(node string) bool {
+	return controlPlaneNodeName.MatchString(node)
+}
+
+// ListControlPlaneNodes returns the additional control planes among the
+// nodes.
+func ListControlPlaneNodes(api libmachine.API) ([]string, error) {
+	nodes, err := ListNodes(api)
+	if err != nil {
+		return nil, err
+	}
+	var controlPlanes []string
+	for _, n := range nodes {
+		if IsControlPlaneNode(n) {
+			controlPlanes = append(controlPlanes, n)
+		}
+	}
+	return controlPlanes, nil
+}
+
+// EtcdPeerURL returns the URL etcd on the VM with the IP advertises to the
+// other control planes.
+func EtcdPeerURL(ip string) string {
+	return fmt.Sprintf("http://%s:%d", ip, constants.EtcdPeerPort)
+}
+
+// EtcdClientURL returns the URL of etcd on the VM with the IP.
+func EtcdClientURL(ip string) string {
+	return fmt.Sprintf("http://%s:%d", ip, constants.EtcdClientPort)
+}
+
+// ListNodes returns the names of the nodes added to the cluster, besides the
+// minikube VM, sorted.
+func ListNodes(api libmachine.API) ([]string, error) {
+	machines, err := api.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing machines")
+	}
+	prefix := constants.NodeMachineName("")
+	var nodes []string
+	for _, m := range machines {
+		if strings.HasPrefix(m, prefix) {
+			nodes = append(nodes, strings.TrimPrefix(m, prefix))
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// NodeExists reports whether the VM of the node exists.
+func NodeExists(api libmachine.API, node string) (bool, error) {
+	return api.Exists(constants.NodeMachineName(node))
+}
+
+// LoadNode loads the VM of the node.
+func LoadNode(api libmachine.API, node string) (*host.Host, error) {
+	h, err := api.Load(constants.NodeMachineName(node))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error loading node %s", node)
+	}
+	return h, nil
+}
+
+// GetNodeStatus gets the status of the VM of the node.
+func GetNodeStatus(api libmachine.API, node string) (string, error) {
+	return getHostStatus(api, constants.NodeMachineName(node))
+}
+
+// StopNode stops the VM of the node.
+func StopNode(api libmachine.API, node string) error {
+	return stopHost(api, constants.NodeMachineName(node))
+}
+
+// DeleteNode deletes the VM of the node.
+func DeleteNode(api libmachine.API, node string) error {
+	return deleteHost(api, constants.NodeMachineName(node))
+}
+
+// JoinCluster starts localkube on the VM of a node as a worker of the
+// apiserver at config.Master, registering the Kubernetes node nodeName.
+func JoinCluster(h sshAble, d drivers.Driver, config KubernetesConfig, nodeName string) error {
+	if config.Master == "" {
+		return errors.New("The apiserver to join is not set")
+	}
+	return joinCluster(h, d, config, nodeName)
+}
+
+// JoinControlPlane starts localkube on the VM of a node as an additional
+// control plane, whose etcd joins the etcd cluster at config.EtcdJoin.
+// The control planes share the certs of the apiserver.
+func JoinControlPlane(h sshAble, d drivers.Driver, config KubernetesConfig, nodeName string) error {
+	if config.EtcdJoin == "" || config.EtcdPeerURL == "" {
+		return errors.New("The etcd cluster to join is not set")
+	}
+	return joinCluster(h, d, config, nodeName)
+}
+
+func joinCluster(h sshAble, d drivers.Driver, config KubernetesConfig, nodeName string) error {
+	localkubeFile, err := localkubeAsset(config)
+	if err != nil {
+		return err
+	}
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return errors.Wrap(err, "Error creating new ssh client")
+	}
+	if err := sshutil.TransferFile(localkubeFile, client); err != nil {
+		return errors.Wrap(err, "Error transferring localkube")
+	}
+	if err := transferCerts(d, workerCerts); err != nil {
+		return errors.Wrap(err, "Error transferring certs")
+	}
+	if config.SeccompProfilesDir != "" {
+		profiles, err := seccompProfileAssets(config.SeccompProfilesDir)
+		if err != nil {
+			return err
+		}
+		if err := sshutil.TransferFiles(profiles, client); err != nil {
+			return errors.Wrap(err, "Error transferring the seccomp profiles")
+		}
+	}
+
+	// The kubelet registers the node by hostname, which the VMs may share
+	config.ExtraOptions = append(util.ExtraOptionSlice{}, config.ExtraOptions...)
+	config.ExtraOptions = append(config.ExtraOptions,
+		util.ExtraOption{Component: "kubelet", Key: "HostnameOverride", Value: nodeName},
+		util.ExtraOption{Component: "proxy", Key: "HostnameOverride", Value: nodeName},
+	)
+	return StartCluster(h, config)
+}
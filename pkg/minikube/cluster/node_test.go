@@ -0,0 +1,238 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/provision"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestValidateNodeName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"m02":      true,
+		"worker-1": true,
+		"Worker":   false,
+		"-m02":     false,
+		"m02.dev":  false,
+		"":         false,
+	} {
+		if err := ValidateNodeName(name); (err == nil) != valid {
+			t.Errorf("ValidateNodeName(%q): expected valid %t, got error %v", name, valid, err)
+		}
+	}
+}
+
+func TestIsControlPlaneNode(t *testing.T) {
+	for name, controlPlane := range map[string]bool{
+		"cp02":  true,
+		"cp123": true,
+		"cp2":   false,
+		"cpu01": false,
+		"m02":   false,
+	} {
+		if actual := IsControlPlaneNode(name); actual != controlPlane {
+			t.Errorf("IsControlPlaneNode(%q): expected %t, got %t", name, controlPlane, actual)
+		}
+	}
+	if name := ControlPlaneNodeName(2); !IsControlPlaneNode(name) {
+		t.Errorf("Expected %s to be a control plane", name)
+	}
+}
+
+func TestNextNodeName(t *testing.T) {
+	var tests = []struct {
+		nodes    []string
+		expected string
+	}{
+		{nil, "m02"},
+		{[]string{"m02", "m03"}, "m04"},
+		{[]string{"m03", "gpu"}, "m02"},
+	}
+	for _, test := range tests {
+		if actual := NextNodeName(test.nodes); actual != test.expected {
+			t.Errorf("NextNodeName(%v): expected %s, got %s", test.nodes, test.expected, actual)
+		}
+	}
+}
+
+func TestNodeHosts(t *testing.T) {
+	api := tests.NewMockAPI()
+	provision.SetDetector(&tests.MockDetector{Provisioner: &tests.MockProvisioner{}})
+
+	if _, err := StartHost(api, defaultMachineConfig); err != nil {
+		t.Fatalf("Error starting host: %s", err)
+	}
+	for _, node := range []string{"m03", "m02"} {
+		config := defaultMachineConfig
+		config.MachineName = constants.NodeMachineName(node)
+		h, err := StartHost(api, config)
+		if err != nil {
+			t.Fatalf("Error starting node %s: %s", node, err)
+		}
+		if h.Name != constants.NodeMachineName(node) {
+			t.Errorf("Node created with incorrect name: %s", h.Name)
+		}
+	}
+
+	nodes, err := ListNodes(api)
+	if err != nil {
+		t.Fatalf("Error listing nodes: %s", err)
+	}
+	if expected := []string{"m02", "m03"}; !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("Expected nodes %v, got %v", expected, nodes)
+	}
+
+	if err := StopNode(api, "m02"); err != nil {
+		t.Fatalf("Error stopping node: %s", err)
+	}
+	if s, _ := GetNodeStatus(api, "m02"); s != "Stopped" {
+		t.Errorf("Expected the node to be stopped, got %s", s)
+	}
+	if s, _ := GetHostStatus(api); s != "Running" {
+		t.Errorf("Expected the minikube VM to keep running, got %s", s)
+	}
+
+	if err := DeleteNode(api, "m02"); err != nil {
+		t.Fatalf("Error deleting node: %s", err)
+	}
+	if exists, _ := NodeExists(api, "m02"); exists {
+		t.Error("Expected the node to be deleted")
+	}
+	if exists, _ := api.Exists(constants.MachineName); !exists {
+		t.Error("Expected the minikube VM to be kept")
+	}
+}
+
+func TestJoinCluster(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	for _, cert := range certs {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, cert), []byte("contents of "+cert), 0600); err != nil {
+			t.Fatalf("Error writing cert: %s", err)
+		}
+	}
+
+	server := httptest.NewServer(&K8sVersionHandlerCorrect{})
+	defer server.Close()
+
+	h := tests.NewMockHost()
+	config := KubernetesConfig{
+		KubernetesVersion: server.URL,
+		Master:            "https://192.168.99.100:8443",
+	}
+	if err := JoinCluster(h, d, config, "minikube-m02"); err != nil {
+		t.Fatalf("Error joining the cluster: %s", err)
+	}
+
+	transferred := s.Transfers.Bytes()
+	if !bytes.Contains(transferred, []byte(testLocalkubeBin)) {
+		t.Error("Expected localkube to be transferred")
+	}
+	for _, cert := range workerCerts {
+		if !bytes.Contains(transferred, []byte("contents of "+cert)) {
+			t.Errorf("Expected %s to be transferred", cert)
+		}
+	}
+	if bytes.Contains(transferred, []byte("contents of ca.key")) {
+		t.Error("Expected the CA key not to be transferred to a worker")
+	}
+
+	var started bool
+	for cmd := range h.Commands {
+		if strings.Contains(cmd, "--master=https://192.168.99.100:8443") &&
+			strings.Contains(cmd, "--extra-config=kubelet.HostnameOverride=minikube-m02") {
+			started = true
+		}
+	}
+	if !started {
+		t.Errorf("Expected localkube to be started as a worker, commands run: %v", h.Commands)
+	}
+}
+
+func TestJoinControlPlane(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	for _, cert := range certs {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, cert), []byte("contents of "+cert), 0600); err != nil {
+			t.Fatalf("Error writing cert: %s", err)
+		}
+	}
+
+	server := httptest.NewServer(&K8sVersionHandlerCorrect{})
+	defer server.Close()
+
+	h := tests.NewMockHost()
+	config := KubernetesConfig{KubernetesVersion: server.URL}
+	if err := JoinControlPlane(h, d, config, "minikube-cp02"); err == nil {
+		t.Fatal("Expected an error without the etcd cluster to join")
+	}
+
+	config.EtcdJoin = EtcdClientURL("192.168.99.100")
+	config.EtcdPeerURL = EtcdPeerURL("192.168.99.101")
+	if err := JoinControlPlane(h, d, config, "minikube-cp02"); err != nil {
+		t.Fatalf("Error joining the control planes: %s", err)
+	}
+
+	var started bool
+	for cmd := range h.Commands {
+		if strings.Contains(cmd, "--etcd-join=http://192.168.99.100:2379") &&
+			strings.Contains(cmd, "--etcd-peer-url=http://192.168.99.101:2380") &&
+			!strings.Contains(cmd, "--master=") {
+			started = true
+		}
+	}
+	if !started {
+		t.Errorf("Expected localkube to be started as a control plane, commands run: %v", h.Commands)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// controlPlaneProcesses are the processes running the apiserver and etcd:
+// localkube, or the components themselves
+var controlPlaneProcesses = map[string]bool{"localkube": true, "kube-apiserver": true, "etcd": true}
+
+// oomKillPattern matches the lines of the kernel log of the OOM killer
+// killing a process, e.g. "Killed process 1234 (localkube) total-vm:..."
+var oomKillPattern = regexp.MustCompile(`[Kk]illed process [0-9]+ \(([^)]+)\)`)
+
+// GetKernelLogCommand returns the command printing the kernel log.
+func GetKernelLogCommand() string {
+	return "sudo dmesg"
+}
+
+// OOMKilled returns the processes of the control plane the OOM killer killed
+// since the machine booted.
+func OOMKilled(h sshAble) ([]string, error) {
+	out, err := h.RunSSHCommand(GetKernelLogCommand())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the kernel log: %s", out)
+	}
+	var killed []string
+	seen := map[string]bool{}
+	for _, m := range oomKillPattern.FindAllStringSubmatch(out, -1) {
+		if controlPlaneProcesses[m[1]] && !seen[m[1]] {
+			killed = append(killed, m[1])
+			seen[m[1]] = true
+		}
+	}
+	return killed, nil
+}
+
+// oomDumpSections are what WriteOOMDump collects, the journal of localkube
+// falling back to its log files when there is no systemd
+var oomDumpSections = []struct {
+	Title   string
+	Command string
+}{
+	{"kernel log", "sudo dmesg | tail -n 300"},
+	{"localkube", fmt.Sprintf("sudo journalctl -u localkube --no-pager -n 300 2>/dev/null || sudo tail -n 300 %s %s", constants.RemoteLocalKubeErrPath, constants.RemoteLocalKubeOutPath)},
+	{"memory", "cat /proc/meminfo"},
+	{"memory cgroup of localkube", "cd /sys/fs/cgroup/memory/system.slice/localkube.service && grep . memory.limit_in_bytes memory.max_usage_in_bytes memory.failcnt memory.stat"},
+	{"memory cgroup of the pods", "cd /sys/fs/cgroup/memory/kubepods 2>/dev/null || cd /sys/fs/cgroup/memory/docker; grep . memory.max_usage_in_bytes memory.failcnt memory.stat"},
+}
+
+// WriteOOMDump writes to w what helps finding out why the OOM killer killed
+// the control plane: the end of the kernel log and of the logs of localkube,
+// and the memory use of the machine and of the cgroups. The sections failing
+// to be collected say so.
+func WriteOOMDump(h sshAble, w io.Writer) error {
+	for _, s := range oomDumpSections {
+		out, err := h.RunSSHCommand(s.Command)
+		if err != nil {
+			out = fmt.Sprintf("Error collecting the %s: %s\n%s", s.Title, err, out)
+		}
+		if _, err := fmt.Fprintf(w, "==> %s <==\n%s\n", s.Title, strings.TrimRight(out, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OOMKillError is the error of the control plane failing because the OOM
+// killer killed it, which calls for more memory rather than for the usual
+// debugging of the apiserver.
+type OOMKillError struct {
+	// Processes are the processes of the control plane which were killed
+	Processes []string
+	VMDriver  string
+	// MemoryMB is the memory of the VM
+	MemoryMB int
+	// DumpPath is the file WriteOOMDump wrote to, if any
+	DumpPath string
+}
+
+func (e *OOMKillError) Error() string {
+	msg := fmt.Sprintf("The OOM killer killed %s, which runs the apiserver and etcd: ", strings.Join(e.Processes, ", "))
+	if e.VMDriver == constants.DriverNone {
+		msg += "free memory on this machine by stopping other programs, or run fewer workloads in the cluster"
+	} else {
+		msg += fmt.Sprintf("the VM has %dMB of memory, recreate it with more, e.g. minikube delete && minikube start --memory %d, or run fewer workloads in the cluster", e.MemoryMB, 2*e.MemoryMB)
+	}
+	if e.DumpPath != "" {
+		msg += fmt.Sprintf(". The kernel log, the logs of localkube and the memory use were saved to %s", e.DumpPath)
+	}
+	return msg
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestOOMKilled(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[GetKernelLogCommand()] = `[ 1203.120001] Out of memory: Kill process 2510 (localkube) score 612 or sacrifice child
+[ 1203.120512] Killed process 2510 (localkube) total-vm:1912340kB, anon-rss:1204560kB, file-rss:0kB
+[ 1290.410021] Memory cgroup out of memory: Kill process 3321 (java) score 998 or sacrifice child
+[ 1290.410123] Killed process 3321 (java) total-vm:3012340kB, anon-rss:504560kB, file-rss:0kB
+[ 1403.520512] Killed process 4410 (localkube) total-vm:1812340kB, anon-rss:1104560kB, file-rss:0kB
+`
+	killed, err := OOMKilled(h)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"localkube"}; !reflect.DeepEqual(killed, expected) {
+		t.Errorf("Expected %v to be killed, got %v", expected, killed)
+	}
+
+	h = tests.NewMockHost()
+	h.CommandOutput[GetKernelLogCommand()] = "[ 1290.410123] Killed process 3321 (java) total-vm:3012340kB\n"
+	if killed, err := OOMKilled(h); err != nil || len(killed) != 0 {
+		t.Errorf("Expected the control plane not to be killed, got %v, %v", killed, err)
+	}
+}
+
+func TestWriteOOMDump(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[oomDumpSections[2].Command] = "MemTotal:        2048000 kB\n"
+	var b bytes.Buffer
+	if err := WriteOOMDump(h, &b); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, s := range oomDumpSections {
+		if h.Commands[s.Command] != 1 {
+			t.Errorf("Expected the %s to be collected", s.Title)
+		}
+	}
+	if !strings.Contains(b.String(), "==> memory <==\nMemTotal:        2048000 kB\n") {
+		t.Errorf("Expected the memory in the dump, got %q", b.String())
+	}
+}
+
+func TestOOMKillError(t *testing.T) {
+	err := &OOMKillError{Processes: []string{"localkube"}, VMDriver: "virtualbox", MemoryMB: 2048, DumpPath: "/tmp/oom.log"}
+	for _, s := range []string{"killed localkube", "--memory 4096", "/tmp/oom.log"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("Expected %q in %q", s, err.Error())
+		}
+	}
+	err = &OOMKillError{Processes: []string{"localkube"}, VMDriver: "none"}
+	if strings.Contains(err.Error(), "--memory") {
+		t.Errorf("Expected no advice on the memory of the VM for the none driver, got %q", err.Error())
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import "github.com/pkg/errors"
+
+// Pause freezes localkube and the containers of the host, freeing the CPU
+// while keeping the VM and the state of the cluster. Use Unpause to resume.
+func Pause(h sshAble) error {
+	if out, err := h.RunSSHCommand(pauseCommand); err != nil {
+		return errors.Wrapf(err, "Error pausing the cluster: %s", out)
+	}
+	return nil
+}
+
+// Unpause resumes what Pause froze. It does nothing if the host isn't paused.
+func Unpause(h sshAble) error {
+	if out, err := h.RunSSHCommand(unpauseCommand); err != nil {
+		return errors.Wrapf(err, "Error unpausing the cluster: %s", out)
+	}
+	return nil
+}
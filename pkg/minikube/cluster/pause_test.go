@@ -0,0 +1,47 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestPause(t *testing.T) {
+	h := tests.NewMockHost()
+	if err := Pause(h); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[pauseCommand] != 1 {
+		t.Errorf("Expected the pause command to run once, got %v", h.Commands)
+	}
+
+	h = tests.NewMockHost()
+	if err := Unpause(h); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[unpauseCommand] != 1 {
+		t.Errorf("Expected the unpause command to run once, got %v", h.Commands)
+	}
+
+	h = tests.NewMockHost()
+	h.Error = "ssh failed"
+	if err := Pause(h); err == nil {
+		t.Errorf("Expected an error when the command fails")
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// containerIDLength is the length of the ids of docker containers
+const containerIDLength = 64
+
+// containerLog is a log file the kubelet links in constants.ContainerLogsDir,
+// named <pod>_<namespace>_<container>-<id>.log
+type containerLog struct {
+	Pod       string
+	Namespace string
+	Container string
+}
+
+// parseContainerLog parses the name of a log file of a container.
+func parseContainerLog(name string) (containerLog, bool) {
+	name = strings.TrimSuffix(name, ".log")
+	if len(name) < containerIDLength+1 || name[len(name)-containerIDLength-1] != '-' {
+		return containerLog{}, false
+	}
+	parts := strings.SplitN(name[:len(name)-containerIDLength-1], "_", 3)
+	if len(parts) != 3 {
+		return containerLog{}, false
+	}
+	return containerLog{Pod: parts[0], Namespace: parts[1], Container: parts[2]}, true
+}
+
+// GetListContainerLogsCommand returns the command listing the log files of
+// the containers.
+func GetListContainerLogsCommand() string {
+	return "sudo ls -1 " + constants.ContainerLogsDir
+}
+
+// PodLogFiles returns the log files of the container of the pod, one per
+// time it was started, which the kubelet writes whether or not the apiserver
+// runs. An empty namespace or container matches any, as long as only one
+// does.
+func PodLogFiles(h sshAble, namespace, pod, container string) ([]string, error) {
+	out, err := h.RunSSHCommand(GetListContainerLogsCommand())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing the logs of the containers: %s", out)
+	}
+	var files []string
+	namespaces, containers := map[string]bool{}, map[string]bool{}
+	for _, name := range strings.Split(out, "\n") {
+		name = strings.TrimSpace(name)
+		l, ok := parseContainerLog(name)
+		if !ok || l.Pod != pod || (namespace != "" && l.Namespace != namespace) || (container != "" && l.Container != container) {
+			continue
+		}
+		files = append(files, path.Join(constants.ContainerLogsDir, name))
+		namespaces[l.Namespace], containers[l.Container] = true, true
+	}
+	switch {
+	case len(files) == 0:
+		return nil, errors.Errorf("No logs of pod %s found in %s, check the name of the pod and that its containers were started", pod, constants.ContainerLogsDir)
+	case len(namespaces) > 1:
+		return nil, errors.Errorf("Pods named %s run in the namespaces %s, choose one with --namespace", pod, sortedKeys(namespaces))
+	case len(containers) > 1:
+		return nil, errors.Errorf("Pod %s has the containers %s, choose one with --container", pod, sortedKeys(containers))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func sortedKeys(m map[string]bool) string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// logLine is a line of the json-file log driver of docker
+type logLine struct {
+	Log  string    `json:"log"`
+	Time time.Time `json:"time"`
+}
+
+// decodeLogs calls emit with the lines of the logs read from r. Lines which
+// aren't json are kept as they are, with the time of the line before.
+func decodeLogs(r io.Reader, emit func(logLine) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var last time.Time
+	for scanner.Scan() {
+		l := logLine{}
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			l = logLine{Log: scanner.Text() + "\n", Time: last}
+		}
+		last = l.Time
+		if err := emit(l); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// PodLogs writes the logs in the log files of a container to w, the lines
+// of its successive runs in the order they were written.
+func PodLogs(h sshAble, w io.Writer, files []string) error {
+	out, err := h.RunSSHCommand("sudo cat " + strings.Join(files, " "))
+	if err != nil {
+		return errors.Wrapf(err, "Error reading the logs: %s", out)
+	}
+	var lines []logLine
+	if err := decodeLogs(strings.NewReader(out), func(l logLine) error {
+		lines = append(lines, l)
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "Error decoding the logs")
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	for _, l := range lines {
+		if _, err := io.WriteString(w, l.Log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FollowPodLogs writes the logs in the log files of a container to w, and
+// then the lines appended to them, until interrupted.
+func FollowPodLogs(h *host.Host, w io.Writer, files []string) error {
+	cmd := "sudo tail -q -n +1 -F " + strings.Join(files, " ")
+	write := func(l logLine) error {
+		_, err := io.WriteString(w, l.Log)
+		return err
+	}
+	if h.Driver.DriverName() == constants.DriverNone {
+		c := exec.Command("/bin/bash", "-c", cmd)
+		stdout, err := c.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := c.Start(); err != nil {
+			return errors.Wrap(err, "Error following the logs")
+		}
+		if err := decodeLogs(stdout, write); err != nil {
+			return err
+		}
+		return c.Wait()
+	}
+	c, err := h.CreateSSHClient()
+	if err != nil {
+		return errors.Wrap(err, "Error creating ssh client")
+	}
+	stdout, stderr, err := c.Start(cmd)
+	if err != nil {
+		return errors.Wrap(err, "Error following the logs")
+	}
+	// tail reports the files it reopens once docker rotated them
+	go io.Copy(ioutil.Discard, stderr)
+	if err := decodeLogs(stdout, write); err != nil {
+		return err
+	}
+	if err := c.Wait(); err != nil {
+		return errors.Wrap(err, "Error following the logs")
+	}
+	return nil
+}
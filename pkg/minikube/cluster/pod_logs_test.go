@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+var (
+	id1 = strings.Repeat("a", containerIDLength)
+	id2 = strings.Repeat("b", containerIDLength)
+
+	containerLogs = strings.Join([]string{
+		"kube-dns-v20-x7k2p_kube-system_kubedns-" + id1 + ".log",
+		"kube-dns-v20-x7k2p_kube-system_dnsmasq-" + id1 + ".log",
+		"kube-addon-manager-minikube_kube-system_kube-addon-manager-" + id1 + ".log",
+		"kube-addon-manager-minikube_kube-system_kube-addon-manager-" + id2 + ".log",
+		"web_default_nginx-" + id1 + ".log",
+		"web_staging_nginx-" + id2 + ".log",
+		"not-a-container.log",
+	}, "\n")
+)
+
+func TestPodLogFiles(t *testing.T) {
+	var tcs = []struct {
+		namespace, pod, container string
+		expected                  []string
+		shouldErr                 bool
+	}{
+		{
+			pod: "kube-addon-manager-minikube",
+			expected: []string{
+				"/var/log/containers/kube-addon-manager-minikube_kube-system_kube-addon-manager-" + id1 + ".log",
+				"/var/log/containers/kube-addon-manager-minikube_kube-system_kube-addon-manager-" + id2 + ".log",
+			},
+		},
+		{
+			pod:       "kube-dns-v20-x7k2p",
+			container: "dnsmasq",
+			expected:  []string{"/var/log/containers/kube-dns-v20-x7k2p_kube-system_dnsmasq-" + id1 + ".log"},
+		},
+		{
+			namespace: "staging",
+			pod:       "web",
+			expected:  []string{"/var/log/containers/web_staging_nginx-" + id2 + ".log"},
+		},
+		{pod: "kube-dns-v20-x7k2p", shouldErr: true},
+		{pod: "web", shouldErr: true},
+		{pod: "kube-dns", shouldErr: true},
+	}
+	for _, test := range tcs {
+		h := tests.NewMockHost()
+		h.CommandOutput[GetListContainerLogsCommand()] = containerLogs
+		files, err := PodLogFiles(h, test.namespace, test.pod, test.container)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s: %v", test.pod, err)
+			continue
+		}
+		if !reflect.DeepEqual(files, test.expected) {
+			t.Errorf("Expected %v for %s, got %v", test.expected, test.pod, files)
+		}
+	}
+}
+
+func TestPodLogs(t *testing.T) {
+	files := []string{"/var/log/containers/a.log", "/var/log/containers/b.log"}
+	h := tests.NewMockHost()
+	h.CommandOutput["sudo cat /var/log/containers/a.log /var/log/containers/b.log"] = strings.Join([]string{
+		`{"log":"second\n","stream":"stderr","time":"2017-06-01T10:00:02.000000001Z"}`,
+		`not json`,
+		`{"log":"fourth\n","stream":"stdout","time":"2017-06-01T10:00:04Z"}`,
+		`{"log":"first\n","stream":"stdout","time":"2017-06-01T10:00:01Z"}`,
+		`{"log":"third\n","stream":"stdout","time":"2017-06-01T10:00:03Z"}`,
+	}, "\n")
+	var b bytes.Buffer
+	if err := PodLogs(h, &b, files); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := "first\nsecond\nnot json\nthird\nfourth\n"; b.String() != expected {
+		t.Errorf("Expected logs %q, got %q", expected, b.String())
+	}
+}
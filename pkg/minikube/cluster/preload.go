@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/golang/glog"
+	download "github.com/jimmidyson/go-download"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+// preloadVMPath is where the preload tarball is copied to in the VM, before
+// being extracted.
+const preloadVMPath = "/tmp/preloaded-images.tar.gz"
+
+// preloadStorage is the storage of a container runtime in the VM, which a
+// preload tarball is extracted into.
+type preloadStorage struct {
+	// Dir is the directory of the storage
+	Dir string
+	// Service is the daemon of the runtime, stopped while extracting
+	Service string
+	// ListImages prints the images the runtime has, if any
+	ListImages string
+}
+
+// preloadStorages are the storages of the container runtimes preload
+// tarballs are published for.
+var preloadStorages = map[string]preloadStorage{
+	"docker": {Dir: "/var/lib/docker", Service: "docker", ListImages: "docker images --quiet"},
+	"rkt":    {Dir: "/var/lib/rkt", ListImages: "sudo rkt image list --no-legend --fields=id 2>/dev/null"},
+}
+
+// preloadRuntime returns the container runtime of the preload, docker when
+// none is set.
+func preloadRuntime(runtime string) string {
+	if runtime == "" {
+		return "docker"
+	}
+	return runtime
+}
+
+// PreloadName returns the name of the preload tarball of the images of the
+// Kubernetes version for the container runtime.
+func PreloadName(kubernetesVersion, runtime string) string {
+	return fmt.Sprintf("preloaded-images-k8s-%s-%s.tar.gz", kubernetesVersion, preloadRuntime(runtime))
+}
+
+// PreloadCacheFilepath returns where the preload tarball of the Kubernetes
+// version and container runtime is cached.
+func PreloadCacheFilepath(kubernetesVersion, runtime string) string {
+	return constants.MakeMiniPath("cache", "preloaded-tarball", PreloadName(kubernetesVersion, runtime))
+}
+
+// PreloadURL returns where the preload tarball of the Kubernetes version and
+// container runtime is published.
+func PreloadURL(kubernetesVersion, runtime string) string {
+	return constants.PreloadURLPrefix + PreloadName(kubernetesVersion, runtime)
+}
+
+// PreloadSupported reports whether preload tarballs can exist for the
+// Kubernetes version and container runtime: localkube URIs and the remote
+// runtime have none.
+func PreloadSupported(kubernetesVersion, runtime string) bool {
+	if strings.Contains(kubernetesVersion, "://") {
+		return false
+	}
+	_, ok := preloadStorages[preloadRuntime(runtime)]
+	return ok
+}
+
+// IsPreloadCached reports whether the preload tarball of the Kubernetes
+// version and container runtime is cached.
+func IsPreloadCached(kubernetesVersion, runtime string) bool {
+	_, err := os.Stat(PreloadCacheFilepath(kubernetesVersion, runtime))
+	return err == nil
+}
+
+// CachePreload downloads the preload tarball of the Kubernetes version and
+// container runtime, unless it is cached already, and returns whether there
+// is one: not every version is published.
+func CachePreload(kubernetesVersion, runtime string) (bool, error) {
+	if !PreloadSupported(kubernetesVersion, runtime) {
+		return false, nil
+	}
+	if IsPreloadCached(kubernetesVersion, runtime) {
+		return true, nil
+	}
+	cachePath := PreloadCacheFilepath(kubernetesVersion, runtime)
+
+	url := PreloadURL(kubernetesVersion, runtime)
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Head(url)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error checking for the preload tarball %s", url)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		glog.Infof("No preload tarball at %s", url)
+		return false, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return false, errors.Errorf("Error checking for the preload tarball %s: %s", url, resp.Status)
+	}
+
+	opts := download.FileOptions{
+		Mkdirs: download.MkdirAll,
+		Options: download.Options{
+			Checksum:     url + constants.ShaSuffix,
+			ChecksumHash: crypto.SHA256,
+			ProgressBars: &download.ProgressBarOptions{
+				MaxWidth: 80,
+			},
+		},
+	}
+	fmt.Println("Downloading preloaded images")
+	if err := download.ToFile(url, cachePath, opts); err != nil {
+		return false, errors.Wrap(err, "Error downloading the preload tarball")
+	}
+	return true, nil
+}
+
+// PreloadImages extracts the cached preload tarball of the Kubernetes version
+// into the storage of the container runtime of the VM, before localkube
+// starts pulling the images, and returns whether it did. A runtime which has
+// images already, e.g. on a restart, is left as it is.
+func PreloadImages(h sshAble, d drivers.Driver, kubernetesVersion, runtime string) (bool, error) {
+	storage, ok := preloadStorages[preloadRuntime(runtime)]
+	if !ok {
+		return false, nil
+	}
+	out, err := h.RunSSHCommand(storage.ListImages)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error listing the images of the VM: %s", out)
+	}
+	if strings.TrimSpace(out) != "" {
+		glog.Infoln("The container runtime has images already, not preloading them")
+		return false, nil
+	}
+
+	f, err := assets.NewFileAsset(PreloadCacheFilepath(kubernetesVersion, runtime), path.Dir(preloadVMPath), path.Base(preloadVMPath), "0644")
+	if err != nil {
+		return false, err
+	}
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return false, errors.Wrap(err, "Error creating new ssh client")
+	}
+	if err := sshutil.TransferFile(f, client); err != nil {
+		return false, errors.Wrap(err, "Error copying the preload tarball to the VM")
+	}
+
+	if out, err := h.RunSSHCommand(GetPreloadExtractCommand(storage)); err != nil {
+		return false, errors.Wrapf(err, "Error extracting the preload tarball: %s", out)
+	}
+	return true, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestPreloadSupported(t *testing.T) {
+	var tcs = []struct {
+		version   string
+		runtime   string
+		supported bool
+	}{
+		{"v1.6.4", "", true},
+		{"v1.6.4", "docker", true},
+		{"v1.6.4", "rkt", true},
+		{"v1.6.4", "remote", false},
+		{"https://example.com/localkube", "", false},
+		{"file:///tmp/localkube", "docker", false},
+	}
+	for _, test := range tcs {
+		if supported := PreloadSupported(test.version, test.runtime); supported != test.supported {
+			t.Errorf("Expected PreloadSupported(%q, %q) to be %t", test.version, test.runtime, test.supported)
+		}
+	}
+	if name := PreloadName("v1.6.4", ""); name != "preloaded-images-k8s-v1.6.4-docker.tar.gz" {
+		t.Errorf("Unexpected preload name %s", name)
+	}
+}
+
+func TestCachePreload(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	tarball := []byte("preloaded images")
+	sum := sha256.Sum256(tarball)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + PreloadName("v1.6.4", "docker"):
+			w.Write(tarball)
+		case "/" + PreloadName("v1.6.4", "docker") + constants.ShaSuffix:
+			w.Write([]byte(hex.EncodeToString(sum[:])))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func(prefix string) { constants.PreloadURLPrefix = prefix }(constants.PreloadURLPrefix)
+	constants.PreloadURLPrefix = server.URL + "/"
+
+	found, err := CachePreload("v1.6.4", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !found {
+		t.Fatalf("Expected the preload to be found")
+	}
+	cached, err := ioutil.ReadFile(PreloadCacheFilepath("v1.6.4", "docker"))
+	if err != nil {
+		t.Fatalf("Expected the preload to be cached: %s", err)
+	}
+	if string(cached) != string(tarball) {
+		t.Errorf("Expected %q to be cached, got %q", tarball, cached)
+	}
+
+	found, err = CachePreload("v1.7.0", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if found {
+		t.Errorf("Expected no preload for an unpublished version")
+	}
+}
+
+func TestPreloadImagesSkipsRuntimeWithImages(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[preloadStorages["docker"].ListImages] = "sha256:0123456789ab\n"
+	preloaded, err := PreloadImages(h, &tests.MockDriver{}, "v1.6.4", "docker")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if preloaded {
+		t.Errorf("Expected the images not to be preloaded")
+	}
+	for cmd := range h.Commands {
+		if strings.Contains(cmd, "tar ") {
+			t.Errorf("Expected nothing to be extracted, got %s", cmd)
+		}
+	}
+}
+
+func TestGetPreloadExtractCommand(t *testing.T) {
+	cmd := GetPreloadExtractCommand(preloadStorages["docker"])
+	for _, expected := range []string{"sudo systemctl stop docker", "sudo systemctl start docker", "sudo tar -C /var/lib/docker -xzf " + preloadVMPath} {
+		if !strings.Contains(cmd, expected) {
+			t.Errorf("Expected the command to contain %q:\n%s", expected, cmd)
+		}
+	}
+	if cmd := GetPreloadExtractCommand(preloadStorages["rkt"]); strings.Contains(cmd, "systemctl") {
+		t.Errorf("Expected no daemon to be stopped for rkt:\n%s", cmd)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/pkg/api/resource"
+)
+
+// ReservedResources holds the kubelet settings which keep enough of the VM
+// for the system and kubernetes daemons, and decide when pods get evicted.
+// Each field uses the format of the matching kubelet flag.
+type ReservedResources struct {
+	SystemReserved string
+	KubeReserved   string
+	EvictionHard   string
+}
+
+// reservedBySize lists the defaults for VMs with up to maxMemoryMB of memory,
+// ordered by size. The last entry is used for anything larger.
+var reservedBySize = []struct {
+	maxMemoryMB int
+	reserved    ReservedResources
+}{
+	{
+		maxMemoryMB: 2048,
+		reserved: ReservedResources{
+			SystemReserved: "cpu=100m,memory=100Mi",
+			KubeReserved:   "cpu=100m,memory=150Mi",
+			EvictionHard:   "memory.available<50Mi,nodefs.available<5%",
+		},
+	},
+	{
+		maxMemoryMB: 4096,
+		reserved: ReservedResources{
+			SystemReserved: "cpu=100m,memory=200Mi",
+			KubeReserved:   "cpu=200m,memory=250Mi",
+			EvictionHard:   "memory.available<100Mi,nodefs.available<10%",
+		},
+	},
+	{
+		reserved: ReservedResources{
+			SystemReserved: "cpu=200m,memory=500Mi",
+			KubeReserved:   "cpu=300m,memory=500Mi",
+			EvictionHard:   "memory.available<300Mi,nodefs.available<10%",
+		},
+	},
+}
+
+// DefaultReservedResources returns the reserved resources and eviction
+// thresholds tuned for a VM with the given amount of memory.
+func DefaultReservedResources(memoryMB int) ReservedResources {
+	for _, s := range reservedBySize {
+		if memoryMB <= s.maxMemoryMB {
+			return s.reserved
+		}
+	}
+	return reservedBySize[len(reservedBySize)-1].reserved
+}
+
+// ValidateReservedResources checks a list of resource reservations,
+// e.g. "cpu=100m,memory=100Mi".
+func ValidateReservedResources(s string) error {
+	for _, r := range splitList(s) {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("%s is not of the form RESOURCE=QUANTITY", r)
+		}
+		if parts[0] != "cpu" && parts[0] != "memory" {
+			return errors.Errorf("%s is not a reservable resource, expected cpu or memory", parts[0])
+		}
+		if _, err := resource.ParseQuantity(parts[1]); err != nil {
+			return errors.Wrapf(err, "Error parsing quantity for %s", parts[0])
+		}
+	}
+	return nil
+}
+
+// ValidateEvictionThresholds checks a list of hard eviction thresholds,
+// e.g. "memory.available<100Mi,nodefs.available<10%".
+func ValidateEvictionThresholds(s string) error {
+	for _, t := range splitList(s) {
+		parts := strings.SplitN(t, "<", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("%s is not of the form SIGNAL<QUANTITY", t)
+		}
+		switch parts[0] {
+		case "memory.available", "nodefs.available", "nodefs.inodesFree", "imagefs.available", "imagefs.inodesFree":
+		default:
+			return errors.Errorf("%s is not a valid eviction signal", parts[0])
+		}
+		if strings.HasSuffix(parts[1], "%") {
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(parts[1], "%"), 64); err != nil {
+				return errors.Wrapf(err, "Error parsing percentage for %s", parts[0])
+			}
+			continue
+		}
+		if _, err := resource.ParseQuantity(parts[1]); err != nil {
+			return errors.Wrapf(err, "Error parsing quantity for %s", parts[0])
+		}
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, i := range strings.Split(s, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			items = append(items, i)
+		}
+	}
+	return items
+}
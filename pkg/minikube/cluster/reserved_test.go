@@ -0,0 +1,63 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultReservedResources(t *testing.T) {
+	for _, tc := range []struct {
+		memoryMB int
+		expected ReservedResources
+	}{
+		{1024, reservedBySize[0].reserved},
+		{2048, reservedBySize[0].reserved},
+		{4096, reservedBySize[1].reserved},
+		{16384, reservedBySize[2].reserved},
+	} {
+		if r := DefaultReservedResources(tc.memoryMB); r != tc.expected {
+			t.Errorf("Unexpected reservations for %dMB: expected %+v, got %+v", tc.memoryMB, tc.expected, r)
+		}
+	}
+}
+
+func TestGetStartCommandReserved(t *testing.T) {
+	k := KubernetesConfig{
+		Reserved: ReservedResources{
+			SystemReserved: "memory=100Mi",
+			EvictionHard:   "nodefs.available<10%",
+		},
+	}
+	startCommand, err := GetStartCommand(k)
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, expected := range []string{
+		`"--extra-config=kubelet.SystemReserved=memory=100Mi"`,
+		`"--extra-config=kubelet.EvictionHard=nodefs.available<10%"`,
+		`\"--extra-config=kubelet.EvictionHard=nodefs.available<10%%\"`,
+	} {
+		if !strings.Contains(startCommand, expected) {
+			t.Fatalf("Expected start command to contain: %s. Got: %s", expected, startCommand)
+		}
+	}
+	if strings.Contains(startCommand, "KubeReserved") {
+		t.Fatalf("Expected start command not to set unset reservations. Got: %s", startCommand)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+// CommandRunner runs the commands of the cluster on its machine.
+type CommandRunner interface {
+	RunSSHCommand(string) (string, error)
+}
+
+// NewCommandRunner returns the runner of the commands on the machine of the
+// host: the host itself over ssh, or a shell of this machine for the none
+// driver.
+func NewCommandRunner(h *host.Host) CommandRunner {
+	if h.Driver.DriverName() == constants.DriverNone {
+		return execRunner{}
+	}
+	return h
+}
+
+// execRunner runs the commands on this machine.
+type execRunner struct{}
+
+func (execRunner) RunSSHCommand(cmd string) (string, error) {
+	out, err := exec.Command("/bin/bash", "-c", cmd).CombinedOutput()
+	return string(out), err
+}
+
+// CopyFiles copies the files to the machine of the driver: over ssh, skipping
+// the ones which are unchanged, or on this machine for the none driver.
+func CopyFiles(d drivers.Driver, files []assets.CopyableFile) error {
+	if d.DriverName() == constants.DriverNone {
+		for _, f := range files {
+			if err := assets.CopyFileLocal(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return errors.Wrap(err, "Error creating new ssh client")
+	}
+	return sshutil.TransferFiles(files, client)
+}
+
+// DeleteFiles deletes the files from the machine of the driver.
+func DeleteFiles(d drivers.Driver, files []assets.CopyableFile) error {
+	if d.DriverName() == constants.DriverNone {
+		for _, f := range files {
+			if err := os.Remove(filepath.Join(f.GetTargetDir(), f.GetTargetName())); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "Error deleting %s", f.GetTargetName())
+			}
+		}
+		return nil
+	}
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return errors.Wrap(err, "Error creating new ssh client")
+	}
+	for _, f := range files {
+		if err := sshutil.DeleteFile(f, client); err != nil {
+			return errors.Wrapf(err, "Error deleting %s", f.GetTargetName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var validSnapshotName = regexp.MustCompile(`^[a-zA-Z0-9][-_.a-zA-Z0-9]*$`)
+
+// Snapshot is a snapshot of the cluster state kept in the minikube VM.
+type Snapshot struct {
+	Name    string
+	Created time.Time
+}
+
+// ValidateSnapshotName checks that the name can be used as a directory name
+// in the VM.
+func ValidateSnapshotName(name string) error {
+	if !validSnapshotName.MatchString(name) {
+		return fmt.Errorf("%q is not a valid snapshot name, it must consist of letters, digits, '-', '_' or '.', and start with a letter or digit", name)
+	}
+	return nil
+}
+
+// CreateSnapshot saves the etcd data and the images of the host under the
+// name. localkube is stopped while the etcd data is saved.
+func CreateSnapshot(h sshAble, name string) error {
+	if err := ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if out, err := h.RunSSHCommand(GetSnapshotCreateCommand(name)); err != nil {
+		return errors.Wrapf(err, "Error creating snapshot %s: %s", name, out)
+	}
+	return nil
+}
+
+// RestoreSnapshot resets the cluster to the state saved by CreateSnapshot.
+// The pods are recreated from the restored etcd data, and the images pulled
+// since the snapshot are removed.
+func RestoreSnapshot(h sshAble, name string) error {
+	if err := ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if out, err := h.RunSSHCommand(GetSnapshotRestoreCommand(name)); err != nil {
+		return errors.Wrapf(err, "Error restoring snapshot %s: %s", name, out)
+	}
+	return nil
+}
+
+// DeleteSnapshot removes the snapshot from the host.
+func DeleteSnapshot(h sshAble, name string) error {
+	if err := ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if out, err := h.RunSSHCommand(GetSnapshotDeleteCommand(name)); err != nil {
+		return errors.Wrapf(err, "Error deleting snapshot %s: %s", name, out)
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of the host sorted by name.
+func ListSnapshots(h sshAble) ([]Snapshot, error) {
+	out, err := h.RunSSHCommand(snapshotListCommand)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing snapshots: %s", out)
+	}
+	var snapshots []Snapshot
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		secs, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing the creation time of snapshot %s", fields[0])
+		}
+		snapshots = append(snapshots, Snapshot{Name: fields[0], Created: time.Unix(secs, 0)})
+	}
+	sort.Sort(snapshotsByName(snapshots))
+	return snapshots, nil
+}
+
+type snapshotsByName []Snapshot
+
+func (s snapshotsByName) Len() int           { return len(s) }
+func (s snapshotsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+func (s snapshotsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,88 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestSnapshotCommands(t *testing.T) {
+	h := tests.NewMockHost()
+	if err := CreateSnapshot(h, "baseline"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := RestoreSnapshot(h, "baseline"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := DeleteSnapshot(h, "baseline"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, cmd := range []string{
+		GetSnapshotCreateCommand("baseline"),
+		GetSnapshotRestoreCommand("baseline"),
+		GetSnapshotDeleteCommand("baseline"),
+	} {
+		if h.Commands[cmd] != 1 {
+			t.Errorf("Expected command to run:\n%s", cmd)
+		}
+	}
+
+	for _, name := range []string{"", "../etc", "a b", "-rf"} {
+		h = tests.NewMockHost()
+		if err := CreateSnapshot(h, name); err == nil {
+			t.Errorf("Expected an error for snapshot name %q", name)
+		}
+		if len(h.Commands) != 0 {
+			t.Errorf("Expected no command to run for snapshot name %q, got %v", name, h.Commands)
+		}
+	}
+
+	h = tests.NewMockHost()
+	h.Error = "snapshot baseline does not exist"
+	if err := RestoreSnapshot(h, "baseline"); err == nil {
+		t.Errorf("Expected an error when the command fails")
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[snapshotListCommand] = "clean 1490000000\nbaseline 1480000000\n"
+	snapshots, err := ListSnapshots(h)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []Snapshot{
+		{Name: "baseline", Created: time.Unix(1480000000, 0)},
+		{Name: "clean", Created: time.Unix(1490000000, 0)},
+	}
+	if len(snapshots) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, snapshots)
+	}
+	for i := range expected {
+		if snapshots[i].Name != expected[i].Name || !snapshots[i].Created.Equal(expected[i].Created) {
+			t.Errorf("Expected %v, got %v", expected[i], snapshots[i])
+		}
+	}
+
+	h.CommandOutput[snapshotListCommand] = "\n"
+	if snapshots, err := ListSnapshots(h); err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots, got %v, %v", snapshots, err)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kvm2"
+)
+
+// StaticIPDrivers are the drivers which can give the VM the address set with
+// --static-ip: kvm2 reserves it on the DHCP server of its private network,
+// and the network of the VM of virtualbox is configured with it.
+var StaticIPDrivers = []string{"kvm2", "virtualbox"}
+
+// staticIPNetworkPath is the config of systemd-networkd in the VM setting the
+// address of the interface on the host-only network, which takes precedence
+// over the DHCP configs of the ISO.
+const staticIPNetworkPath = "/etc/systemd/network/05-minikube-static.network"
+
+// staticIPNetwork returns the CIDR of the network of the VM with the host the
+// address is on, for the driver.
+func staticIPNetwork(driver, hostOnlyCIDR string) (string, error) {
+	switch driver {
+	case "kvm2":
+		return kvm2.PrivateNetworkCIDR, nil
+	case "virtualbox":
+		return hostOnlyCIDR, nil
+	}
+	return "", errors.Errorf("The %s driver can't set the address of the VM, --static-ip is supported by the %s drivers", driver, strings.Join(StaticIPDrivers, " and "))
+}
+
+// ValidateStaticIP checks that the driver can give the VM the address, and
+// that it is an address of the network of the VM with the host, other than
+// the one of the host.
+func ValidateStaticIP(driver, ip, hostOnlyCIDR string) error {
+	addr := net.ParseIP(ip).To4()
+	if addr == nil {
+		return errors.Errorf("%q is not an IPv4 address", ip)
+	}
+	cidr, err := staticIPNetwork(driver, hostOnlyCIDR)
+	if err != nil {
+		return err
+	}
+	host, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing the network of the VM %s", cidr)
+	}
+	broadcast := make(net.IP, len(n.IP))
+	for i := range n.IP {
+		broadcast[i] = n.IP[i] | ^n.Mask[i]
+	}
+	if !n.Contains(addr) || addr.Equal(n.IP) || addr.Equal(broadcast.To4()) {
+		return errors.Errorf("%s is not an address of the network %s of the VM with the host", ip, n)
+	}
+	if addr.Equal(host) {
+		return errors.Errorf("%s is the address of the host on the network %s", ip, n)
+	}
+	return nil
+}
+
+// GetStaticIPCommand returns the command configuring the interface of the VM
+// on the host-only network with the address, as the ISO would lease another
+// one at each start.
+func GetStaticIPCommand(ip, hostOnlyCIDR string) (string, error) {
+	_, n, err := net.ParseCIDR(hostOnlyCIDR)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error parsing the host-only network %s", hostOnlyCIDR)
+	}
+	ones, _ := n.Mask.Size()
+	network := fmt.Sprintf("[Match]\nName=eth1\n\n[Network]\nAddress=%s/%d\n", ip, ones)
+	// printf expands the newlines of the config, which has no other escape
+	configure := fmt.Sprintf("printf %q | sudo tee %s >/dev/null && sudo ip -4 addr flush dev eth1 && sudo systemctl restart systemd-networkd",
+		network, staticIPNetworkPath)
+	// A running VM which has the address is left alone
+	return fmt.Sprintf("ip -4 addr show dev eth1 | grep -q ' inet %s/%d ' || (%s)", ip, ones, configure), nil
+}
+
+// guestStaticIP reports whether the address of the VM is configured in the
+// VM, the other drivers set it themselves.
+func guestStaticIP(config MachineConfig) bool {
+	return config.StaticIP != "" && config.VMDriver == "virtualbox"
+}
+
+// configureStaticIP gives the VM of virtualbox its address, at each start as
+// the config of the VM is not kept across reboots.
+func configureStaticIP(h sshAble, config MachineConfig) error {
+	if !guestStaticIP(config) {
+		return nil
+	}
+	cmd, err := GetStaticIPCommand(config.StaticIP, config.HostOnlyCIDR)
+	if err != nil {
+		return err
+	}
+	if out, err := h.RunSSHCommand(cmd); err != nil {
+		return errors.Wrapf(err, "Error setting the address of the VM: %s", out)
+	}
+	return nil
+}
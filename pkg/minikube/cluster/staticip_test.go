@@ -0,0 +1,56 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateStaticIP(t *testing.T) {
+	for _, tc := range []struct {
+		driver, ip string
+		expected   string
+	}{
+		{"kvm2", "192.168.39.10", ""},
+		{"virtualbox", "192.168.99.50", ""},
+		{"virtualbox", "192.168.39.10", "is not an address of the network 192.168.99.0/24"},
+		{"virtualbox", "192.168.99.1", "is the address of the host"},
+		{"kvm2", "192.168.39.255", "is not an address of the network"},
+		{"kvm2", "fd00::10", "is not an IPv4 address"},
+		{"hyperkit", "192.168.64.10", "The hyperkit driver can't set the address of the VM"},
+	} {
+		err := ValidateStaticIP(tc.driver, tc.ip, "192.168.99.1/24")
+		if tc.expected == "" && err != nil {
+			t.Errorf("Unexpected error for %s with %s: %s", tc.ip, tc.driver, err)
+		}
+		if tc.expected != "" && (err == nil || !strings.Contains(err.Error(), tc.expected)) {
+			t.Errorf("Expected an error containing %q for %s with %s, got %v", tc.expected, tc.ip, tc.driver, err)
+		}
+	}
+}
+
+func TestStaticIPCommand(t *testing.T) {
+	cmd, err := GetStaticIPCommand("192.168.99.50", "192.168.99.1/24")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `ip -4 addr show dev eth1 | grep -q ' inet 192.168.99.50/24 ' || (printf "[Match]\nName=eth1\n\n[Network]\nAddress=192.168.99.50/24\n" | sudo tee /etc/systemd/network/05-minikube-static.network >/dev/null && sudo ip -4 addr flush dev eth1 && sudo systemctl restart systemd-networkd)`
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
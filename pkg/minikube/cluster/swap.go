@@ -0,0 +1,55 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/version"
+)
+
+// kubeletFailSwapOnVersion is the first version where the kubelet refuses to
+// start when swap is on, unless FailSwapOn is turned off.
+var kubeletFailSwapOnVersion = semver.MustParse("1.8.0")
+
+// ConfigureSwap creates and turns on a swapfile of the given size in the VM,
+// or removes the swapfile if the size is 0.
+func ConfigureSwap(h sshAble, sizeMB int) error {
+	cmd := disableSwapCommand
+	if sizeMB > 0 {
+		glog.Infof("Enabling %dMB of swap", sizeMB)
+		cmd = GetEnableSwapCommand(sizeMB)
+	}
+	if out, err := h.RunSSHCommand(cmd); err != nil {
+		return errors.Wrapf(err, "Error configuring swap: %s", out)
+	}
+	return nil
+}
+
+// swapNeedsFailSwapOff reports whether the kubelet of the kubernetes version
+// has to be told not to fail when swap is on. Versions which can't be parsed,
+// like localkube URIs, are assumed to need it.
+func swapNeedsFailSwapOff(kubernetesVersion string) bool {
+	v, err := semver.Make(strings.TrimPrefix(kubernetesVersion, version.VersionPrefix))
+	if err != nil {
+		return true
+	}
+	return v.GTE(kubeletFailSwapOnVersion)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestConfigureSwap(t *testing.T) {
+	var tcs = []struct {
+		description string
+		sizeMB      int
+		expected    string
+	}{
+		{
+			description: "enable",
+			sizeMB:      1024,
+			expected:    GetEnableSwapCommand(1024),
+		},
+		{
+			description: "disable",
+			expected:    disableSwapCommand,
+		},
+	}
+
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			h := tests.NewMockHost()
+			if err := ConfigureSwap(h, test.sizeMB); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if h.Commands[test.expected] != 1 {
+				t.Errorf("Expected command to be run: %s", test.expected)
+			}
+		})
+	}
+}
+
+func TestGetEnableSwapCommand(t *testing.T) {
+	cmd := GetEnableSwapCommand(512)
+	for _, expected := range []string{
+		`!= "536870912"`,
+		"count=512",
+		"sudo swapon " + swapfilePath,
+	} {
+		if !strings.Contains(cmd, expected) {
+			t.Errorf("Expected swap command to contain %s. Got: %s", expected, cmd)
+		}
+	}
+}
+
+func TestGetStartCommandSwap(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{"v1.5.3", false},
+		{"v1.7.9+build", false},
+		{"v1.8.0", true},
+		{"v1.9.1", true},
+		{"https://example.com/localkube", true},
+	} {
+		startCommand, err := GetStartCommand(KubernetesConfig{KubernetesVersion: tc.version, SwapEnabled: true})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if strings.Contains(startCommand, "kubelet.FailSwapOn=false") != tc.expected {
+			t.Errorf("Expected FailSwapOn to be turned off for %s: %t. Got: %s", tc.version, tc.expected, startCommand)
+		}
+	}
+}
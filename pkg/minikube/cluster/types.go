@@ -16,7 +16,10 @@ limitations under the License.
 
 package cluster
 
-import "k8s.io/minikube/pkg/util"
+import (
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
 
 // MachineConfig contains the parameters used to start a cluster.
 type MachineConfig struct {
@@ -30,9 +33,30 @@ type MachineConfig struct {
 	RegistryMirror      []string
 	HostOnlyCIDR        string // Only used by the virtualbox driver
 	HypervVirtualSwitch string
+	HypervUseExtSwitch  bool   // Only used by the hyperv driver
+	HypervDisableDynMem bool   // Only used by the hyperv driver
 	KvmNetwork          string // Only used by the KVM driver
+	StaticIP            string // Only used by the kvm2 and virtualbox drivers
 	Downloader          util.ISODownloader
 	DockerOpt           []string // Each entry is formatted as KEY=VALUE.
+	DockerDaemonConfig  string   // The daemon.json of the docker daemon, empty for its defaults
+	MachineName         string   // Defaults to the minikube VM, see constants.NodeMachineName for the other nodes
+	SSHIPAddress        string   // Only used by the ssh driver
+	SSHUser             string   // Only used by the ssh driver
+	SSHKey              string   // Only used by the ssh driver
+	SSHPort             int      // Only used by the ssh driver
+	NFSShare            []string // Only used by the hyperkit driver
+	NFSSharesRoot       string   // Only used by the hyperkit driver
+	QemuNetwork         string   // Only used by the qemu driver
+	SocketVMnetClient   string   // Only used by the qemu driver
+	SocketVMnetPath     string   // Only used by the qemu driver
+}
+
+func (c MachineConfig) machineName() string {
+	if c.MachineName == "" {
+		return constants.MachineName
+	}
+	return c.MachineName
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -40,8 +64,24 @@ type KubernetesConfig struct {
 	KubernetesVersion string
 	NodeIP            string
 	APIServerName     string
+	APIServerPort     int
 	ContainerRuntime  string
 	NetworkPlugin     string
-	FeatureGates      string
-	ExtraOptions      util.ExtraOptionSlice
+	CNI               string // The CNI deployed into the cluster, or a path to its manifest, if any
+	// PodNetworkCIDR and ServiceClusterIPRange are the networks of the pods
+	// and services, the defaults when empty
+	PodNetworkCIDR        string
+	ServiceClusterIPRange string
+	FeatureGates          string
+	Reserved              ReservedResources
+	SwapEnabled           bool
+	ExtraOptions          util.ExtraOptionSlice
+	Master                string // The apiserver URL to join as a worker node, if any
+	EtcdPeerURL           string // The URL etcd advertises to the other control planes, if any
+	EtcdJoin              string // The etcd client URL to join as an additional control plane, if any
+	CryptoMode            string
+	// SeccompDefault defaults the seccomp profile of pods to the one of the
+	// container runtime, SeccompProfilesDir holds profiles for pods to load
+	SeccompDefault     bool
+	SeccompProfilesDir string
 }
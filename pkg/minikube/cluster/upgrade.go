@@ -0,0 +1,140 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var kubernetesVersionCommand = fmt.Sprintf("cat %s 2>/dev/null || true", constants.LocalkubeVersionPath)
+
+// GetKubernetesVersion returns the Kubernetes version, or localkube URI, the
+// cluster of the host was last started with, or an empty string if unknown.
+func GetKubernetesVersion(h sshAble) (string, error) {
+	out, err := h.RunSSHCommand(kubernetesVersionCommand)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error reading the Kubernetes version of the cluster: %s", out)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RecordKubernetesVersion records the version the cluster of the host is
+// started with, for GetKubernetesVersion.
+func RecordKubernetesVersion(h sshAble, kubernetesVersion string) error {
+	if out, err := h.RunSSHCommand(GetRecordKubernetesVersionCommand(kubernetesVersion)); err != nil {
+		return errors.Wrapf(err, "Error recording the Kubernetes version of the cluster: %s", out)
+	}
+	return nil
+}
+
+// GetRecordKubernetesVersionCommand returns the command writing the version
+// to the version file.
+func GetRecordKubernetesVersionCommand(kubernetesVersion string) string {
+	return fmt.Sprintf("printf '%%s\\n' %q | sudo tee %s >/dev/null", kubernetesVersion, constants.LocalkubeVersionPath)
+}
+
+// CheckVersionChange returns whether starting a cluster running a version
+// with another one changes its version. Downgrades to a previous minor
+// version are refused, as the etcd data written by the newer apiserver may
+// not be read by the older one, while patch versions share their storage.
+// Versions which are localkube URIs can't be compared, and changing them is
+// allowed.
+func CheckVersionChange(running, requested string) (bool, error) {
+	if running == "" || running == requested {
+		return false, nil
+	}
+	from, err := semver.Make(strings.TrimPrefix(running, version.VersionPrefix))
+	if err != nil {
+		return true, nil
+	}
+	to, err := semver.Make(strings.TrimPrefix(requested, version.VersionPrefix))
+	if err != nil {
+		return true, nil
+	}
+	if to.LT(from) && (to.Major != from.Major || to.Minor != from.Minor) {
+		return false, fmt.Errorf("The cluster runs Kubernetes %s, downgrading it to %s is not supported, delete it first with minikube delete", running, requested)
+	}
+	return !to.EQ(from), nil
+}
+
+// minimumKubernetesVersion is the first version localkube was released for.
+var minimumKubernetesVersion = semver.MustParse("1.3.0")
+
+// ValidateKubernetesVersion checks that the version is the bundled one, the
+// URI of a localkube binary, or a version localkube was released for. The
+// releases are only looked up when the version isn't cached.
+func ValidateKubernetesVersion(kubernetesVersion string) error {
+	if kubernetesVersion == constants.DefaultKubernetesVersion {
+		return nil
+	}
+	if u, err := url.Parse(kubernetesVersion); err == nil && u.IsAbs() {
+		return nil
+	}
+	v, err := semver.Make(strings.TrimPrefix(kubernetesVersion, version.VersionPrefix))
+	if err != nil {
+		return fmt.Errorf("%q is neither a Kubernetes version, like %s, nor the URI of a localkube binary", kubernetesVersion, constants.DefaultKubernetesVersion)
+	}
+	if v.LT(minimumKubernetesVersion) {
+		return fmt.Errorf("Kubernetes %s is not supported, the oldest version is v%s", kubernetesVersion, minimumKubernetesVersion)
+	}
+	if _, err := os.Stat(LocalkubeCacheFilepath(kubernetesVersion)); err == nil {
+		return nil
+	}
+	if _, err := util.GetLocalkubeDownloadURL(kubernetesVersion, constants.LocalkubeLinuxFilename); err != nil {
+		return errors.Wrapf(err, "Kubernetes %s can't be run, see minikube get-k8s-versions", kubernetesVersion)
+	}
+	return nil
+}
+
+// CheckBundledOptions checks that the options of the config which localkube
+// is started with are supported by its version. The localkube binaries of the
+// other versions were released before those options, which only the one
+// bundled with minikube has.
+func CheckBundledOptions(config KubernetesConfig) error {
+	if !localkubeURIWasSpecified(config) {
+		return nil
+	}
+	var options []string
+	if config.Master != "" {
+		options = append(options, "nodes")
+	}
+	if config.EtcdPeerURL != "" || config.EtcdJoin != "" {
+		options = append(options, "several control planes")
+	}
+	if config.CryptoMode != "" && config.CryptoMode != util.CryptoModeDefault {
+		options = append(options, "the "+config.CryptoMode+" crypto mode")
+	}
+	if config.SeccompDefault {
+		options = append(options, "--seccomp-default")
+	}
+	if config.PodNetworkCIDR != "" {
+		options = append(options, "--pod-network-cidr")
+	}
+	if len(options) > 0 {
+		return fmt.Errorf("%s need the Kubernetes version bundled with minikube, %s, not %s", strings.Join(options, ", "), constants.DefaultKubernetesVersion, config.KubernetesVersion)
+	}
+	return nil
+}
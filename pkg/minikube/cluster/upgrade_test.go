@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestCheckVersionChange(t *testing.T) {
+	var tests = []struct {
+		running   string
+		requested string
+		upgrade   bool
+		err       bool
+	}{
+		{running: "", requested: "v1.5.3"},
+		{running: "v1.5.3", requested: "v1.5.3"},
+		{running: "v1.5.2", requested: "v1.5.3", upgrade: true},
+		{running: "v1.5.3", requested: "v1.6.0-beta.1", upgrade: true},
+		{running: "v1.5.3", requested: "v1.5.2", upgrade: true},
+		{running: "v1.6.0", requested: "v1.5.3", err: true},
+		{running: "v1.5.3", requested: "file:///tmp/localkube", upgrade: true},
+		{running: "file:///tmp/localkube", requested: "v1.5.3", upgrade: true},
+	}
+	for _, test := range tests {
+		upgrade, err := CheckVersionChange(test.running, test.requested)
+		if (err != nil) != test.err {
+			t.Errorf("%s to %s: expected error %t, got %v", test.running, test.requested, test.err, err)
+		}
+		if upgrade != test.upgrade {
+			t.Errorf("%s to %s: expected upgrade %t, got %t", test.running, test.requested, test.upgrade, upgrade)
+		}
+	}
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	h := tests.NewMockHost()
+	h.CommandOutput[kubernetesVersionCommand] = "v1.5.2\n"
+	v, err := GetKubernetesVersion(h)
+	if err != nil || v != "v1.5.2" {
+		t.Errorf("Expected v1.5.2, got %q, %v", v, err)
+	}
+
+	if err := RecordKubernetesVersion(h, "v1.5.3"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[GetRecordKubernetesVersionCommand("v1.5.3")] != 1 {
+		t.Errorf("Expected the version to be recorded, got %v", h.Commands)
+	}
+}
+
+func TestValidateKubernetesVersion(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	cached := LocalkubeCacheFilepath("v1.4.0")
+	if err := os.MkdirAll(filepath.Dir(cached), 0777); err != nil {
+		t.Fatalf("Error creating the cache: %s", err)
+	}
+	if err := ioutil.WriteFile(cached, []byte("localkube"), 0644); err != nil {
+		t.Fatalf("Error caching localkube: %s", err)
+	}
+
+	var tests = []struct {
+		version string
+		err     string
+	}{
+		{version: constants.DefaultKubernetesVersion},
+		{version: "https://example.com/localkube"},
+		{version: "file:///tmp/localkube"},
+		{version: "v1.4.0"},
+		{version: "latest", err: "neither a Kubernetes version"},
+		{version: "v1.2.4", err: "not supported"},
+	}
+	for _, test := range tests {
+		err := ValidateKubernetesVersion(test.version)
+		if test.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.version, err)
+		}
+		if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%s: expected an error containing %q, got %v", test.version, test.err, err)
+		}
+	}
+}
+
+func TestCheckBundledOptions(t *testing.T) {
+	bundled := KubernetesConfig{KubernetesVersion: constants.DefaultKubernetesVersion, SeccompDefault: true, Master: "https://192.168.99.100:8443"}
+	if err := CheckBundledOptions(bundled); err != nil {
+		t.Errorf("Unexpected error with the bundled version: %s", err)
+	}
+	if err := CheckBundledOptions(KubernetesConfig{KubernetesVersion: "v1.4.0", CryptoMode: util.CryptoModeDefault}); err != nil {
+		t.Errorf("Unexpected error without bundled options: %s", err)
+	}
+	err := CheckBundledOptions(KubernetesConfig{KubernetesVersion: "v1.4.0", EtcdPeerURL: "https://192.168.99.100:2380", SeccompDefault: true})
+	if err == nil || !strings.Contains(err.Error(), "several control planes, --seccomp-default need") {
+		t.Errorf("Expected an error naming the options, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/wireguard"
+)
+
+// ExposeWireGuard configures a WireGuard endpoint on the VM listening on the
+// port, with the client as its only peer, and returns the public key of the
+// endpoint. The VM and the client are given the first two addresses of subnet.
+func ExposeWireGuard(h sshAble, port int, subnet string, client wireguard.Key) (wireguard.Key, error) {
+	server, peer, err := wireguard.Addresses(subnet)
+	if err != nil {
+		return wireguard.Key{}, err
+	}
+	glog.Infof("Exposing a WireGuard endpoint on port %d with address %s", port, server)
+	out, err := h.RunSSHCommand(GetWireGuardUpCommand(port, server.String(), subnet, client.PublicKey().String(), peer.String()))
+	if err != nil {
+		return wireguard.Key{}, errors.Wrapf(err, "Error configuring WireGuard: %s", out)
+	}
+	return wireguard.ParseKey(out)
+}
+
+// RemoveWireGuard removes the WireGuard endpoint from the VM.
+func RemoveWireGuard(h sshAble, subnet string) error {
+	if out, err := h.RunSSHCommand(GetWireGuardDownCommand(subnet)); err != nil {
+		return errors.Wrapf(err, "Error removing WireGuard: %s", out)
+	}
+	return nil
+}
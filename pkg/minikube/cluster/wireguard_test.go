@@ -0,0 +1,53 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/minikube/wireguard"
+)
+
+func TestExposeWireGuard(t *testing.T) {
+	client, err := wireguard.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	server, err := wireguard.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	h := tests.NewMockHost()
+	cmd := GetWireGuardUpCommand(51820, "10.99.0.1/24", "10.99.0.0/24", client.PublicKey().String(), "10.99.0.2/32")
+	h.CommandOutput[cmd] = server.PublicKey().String() + "\n"
+	key, err := ExposeWireGuard(h, 51820, "10.99.0.0/24", client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if h.Commands[cmd] != 1 {
+		t.Errorf("Expected the WireGuard endpoint to be configured")
+	}
+	if key != server.PublicKey() {
+		t.Errorf("Expected the public key of the VM %s, got %s", server.PublicKey(), key)
+	}
+
+	if _, err := ExposeWireGuard(tests.NewMockHost(), 51820, "10.99.0.0/31", client); err == nil {
+		t.Errorf("Expected an error for a subnet too small")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cni selects the CNI the pods of the cluster are networked with,
+// and provides the manifest deploying it.
+package cni
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	download "github.com/jimmidyson/go-download"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// Bridge connects the pods to a bridge of the VM, with the CNI config and
+	// plugins of the ISO
+	Bridge  = "bridge"
+	Calico  = "calico"
+	Cilium  = "cilium"
+	Flannel = "flannel"
+
+	// NetworkPlugin is the network plugin of the kubelet for any CNI
+	NetworkPlugin = "cni"
+	// PodCIDR is the network the pods of the deployed CNIs get their
+	// addresses from, away from those of the VM drivers, unless another is set
+	PodCIDR = "10.244.0.0/16"
+
+	manifestFile = "cni.yaml"
+	// clusterServiceLabel is the label of the objects the addon manager
+	// applies
+	clusterServiceLabel = "kubernetes.io/cluster-service"
+)
+
+// Names are the CNIs minikube knows, a path to a manifest deploys another
+var Names = []string{Bridge, Calico, Cilium, Flannel}
+
+// manifestURLs are the manifests of the CNIs deployed into the cluster, of
+// the versions supporting the bundled Kubernetes version
+var manifestURLs = map[string]string{
+	Calico:  "https://docs.projectcalico.org/v2.6/getting-started/kubernetes/installation/hosted/kubeadm/1.6/calico.yaml",
+	Cilium:  "https://raw.githubusercontent.com/cilium/cilium/v1.0/examples/kubernetes/1.7/cilium.yaml",
+	Flannel: "https://raw.githubusercontent.com/coreos/flannel/v0.9.1/Documentation/kube-flannel.yml",
+}
+
+// Resolve returns the CNI of the cluster: the one selected, or the bridge for
+// the container runtimes which don't network the pods themselves.
+func Resolve(selected, containerRuntime string) string {
+	if selected != "" {
+		return selected
+	}
+	if containerRuntime == "rkt" || containerRuntime == "remote" {
+		return Bridge
+	}
+	return ""
+}
+
+// Validate checks that the CNI is one minikube knows, or a manifest.
+func Validate(name string) error {
+	for _, n := range Names {
+		if name == n {
+			return nil
+		}
+	}
+	if _, err := os.Stat(name); err != nil {
+		return errors.Errorf("%s is not a CNI, one of %s, nor a manifest: %s", name, strings.Join(Names, ", "), err)
+	}
+	return nil
+}
+
+// ExtraOptions returns the options of the components the CNI needs: flannel
+// takes the networks of the pods of each node from the controller manager,
+// out of the pod network, PodCIDR when empty.
+func ExtraOptions(name, podCIDR string) util.ExtraOptionSlice {
+	if name != Flannel {
+		return nil
+	}
+	return util.ExtraOptionSlice{
+		{Component: "controller-manager", Key: "AllocateNodeCIDRs", Value: "true"},
+		{Component: "controller-manager", Key: "ClusterCIDR", Value: orDefault(podCIDR)},
+	}
+}
+
+func orDefault(podCIDR string) string {
+	if podCIDR == "" {
+		return PodCIDR
+	}
+	return podCIDR
+}
+
+// manifestCIDRs are the pod networks of the manifests of the CNIs, replaced
+// with the one of the cluster
+var manifestCIDRs = map[string]string{
+	// The pool of the manifest overlaps the host-only networks of the drivers
+	Calico:  "192.168.0.0/16",
+	Flannel: PodCIDR,
+}
+
+// cachePath returns where the manifest of the CNI is kept once downloaded.
+func cachePath(name string) string {
+	return constants.MakeMiniPath("cache", "cni", name+".yaml")
+}
+
+// Asset returns the manifest deploying the CNI, copied into the addons
+// directory of the VM for the addon manager to apply it, or nil for the
+// bridge, configured by the ISO. The manifests of the CNIs minikube knows
+// are downloaded once, and get the pod network, PodCIDR when empty.
+func Asset(name, podCIDR string) (assets.CopyableFile, error) {
+	if name == "" || name == Bridge {
+		return nil, nil
+	}
+	path := name
+	if url, ok := manifestURLs[name]; ok {
+		path = cachePath(name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Printf("Downloading the %s manifest\n", name)
+			opts := download.FileOptions{Mkdirs: download.MkdirAll}
+			if err := download.ToFile(url, path, opts); err != nil {
+				return nil, errors.Wrapf(err, "Error downloading the %s manifest", name)
+			}
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the manifest of %s", name)
+	}
+	if cidr, ok := manifestCIDRs[name]; ok {
+		data = []byte(strings.Replace(string(data), cidr, orDefault(podCIDR), -1))
+	}
+	data, err = label(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the manifest of %s", name)
+	}
+	return assets.NewMemoryAssetFromBytes(data, constants.AddonsPath, manifestFile, "0640"), nil
+}
+
+// label adds the label of the objects the addon manager applies to those of
+// the manifest.
+func label(data []byte) ([]byte, error) {
+	var docs []string
+	for _, doc := range assets.SplitManifests(data) {
+		var o map[string]interface{}
+		if err := yaml.Unmarshal(doc, &o); err != nil {
+			return nil, err
+		}
+		if len(o) == 0 {
+			continue
+		}
+		metadata, _ := o["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+			o["metadata"] = metadata
+		}
+		labels, _ := metadata["labels"].(map[string]interface{})
+		if labels == nil {
+			labels = map[string]interface{}{}
+			metadata["labels"] = labels
+		}
+		labels[clusterServiceLabel] = "true"
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(b))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestResolve(t *testing.T) {
+	var tcs = []struct {
+		selected, runtime, expected string
+	}{
+		{"", "", ""},
+		{"", "docker", ""},
+		{"", "rkt", Bridge},
+		{"", "remote", Bridge},
+		{Calico, "rkt", Calico},
+		{Flannel, "", Flannel},
+	}
+	for _, test := range tcs {
+		if actual := Resolve(test.selected, test.runtime); actual != test.expected {
+			t.Errorf("Expected %q for %q with the %q runtime, got %q", test.expected, test.selected, test.runtime, actual)
+		}
+	}
+}
+
+const testManifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: net
+  namespace: kube-system
+---
+apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: net
+  namespace: kube-system
+  labels:
+    k8s-app: net
+`
+
+func TestAsset(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if err := Validate("weave"); err == nil {
+		t.Errorf("Expected an error for a CNI which is neither known nor a manifest")
+	}
+	if f, err := Asset(Bridge, ""); err != nil || f != nil {
+		t.Errorf("Expected no manifest for the bridge, got %v, %v", f, err)
+	}
+
+	path := filepath.Join(tempDir, "net.yaml")
+	if err := ioutil.WriteFile(path, []byte(testManifest), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := Validate(path); err != nil {
+		t.Errorf("Unexpected error for a manifest: %s", err)
+	}
+	f, err := Asset(path, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if f.GetTargetDir() != constants.AddonsPath {
+		t.Errorf("Expected the manifest to be copied to %s, got %s", constants.AddonsPath, f.GetTargetDir())
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	docs := assets.SplitManifests(data)
+	if len(docs) != 2 {
+		t.Fatalf("Expected the 2 objects of the manifest, got %d", len(docs))
+	}
+	for _, doc := range docs {
+		var o struct {
+			Metadata struct {
+				Labels map[string]string
+			}
+		}
+		if err := yaml.Unmarshal(doc, &o); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if o.Metadata.Labels[clusterServiceLabel] != "true" {
+			t.Errorf("Expected the object to be applied by the addon manager, got %s", doc)
+		}
+	}
+	if !strings.Contains(string(docs[1]), "k8s-app: net") {
+		t.Errorf("Expected the labels of the object to be kept, got %s", docs[1])
+	}
+}
+
+func TestAssetPodCIDR(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	path := cachePath(Flannel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	manifest := testManifest + `data:
+  net-conf.json: '{"Network": "10.244.0.0/16"}'
+`
+	if err := ioutil.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	f, err := Asset(Flannel, "172.16.0.0/16")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(string(data), "172.16.0.0/16") || strings.Contains(string(data), PodCIDR) {
+		t.Errorf("Expected the pod network of the manifest to be replaced, got %s", data)
+	}
+}
+
+func TestExtraOptions(t *testing.T) {
+	if len(ExtraOptions(Calico, "")) != 0 {
+		t.Errorf("Expected no option for calico")
+	}
+	opts := ExtraOptions(Flannel, "")
+	if len(opts) != 2 || opts[1].Value != PodCIDR {
+		t.Errorf("Expected flannel to have the controller manager allocate the pod networks, got %v", opts)
+	}
+	if opts := ExtraOptions(Flannel, "172.16.0.0/16"); opts[1].Value != "172.16.0.0/16" {
+		t.Errorf("Expected the pod network of the cluster, got %v", opts)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// File is the subset of a docker-compose file which can be deployed to the cluster
+type File struct {
+	Version  string
+	Services map[string]Service
+}
+
+// Service is a service of a docker-compose file
+type Service struct {
+	Image       string
+	Build       Build
+	Command     StringOrList
+	Entrypoint  StringOrList
+	Environment Environment
+	Ports       []string
+	Deploy      struct {
+		Replicas *int32
+	}
+}
+
+// Build describes how the image of a service is built
+type Build struct {
+	Context    string
+	Dockerfile string
+}
+
+// UnmarshalYAML accepts both the short form, a path to the build context,
+// and the long form of the build section.
+func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var context string
+	if err := unmarshal(&context); err == nil {
+		b.Context = context
+		return nil
+	}
+	var build struct {
+		Context    string
+		Dockerfile string
+	}
+	if err := unmarshal(&build); err != nil {
+		return err
+	}
+	b.Context, b.Dockerfile = build.Context, build.Dockerfile
+	return nil
+}
+
+// StringOrList is a command given either as a string or as a list of arguments
+type StringOrList []string
+
+func (s *StringOrList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err == nil {
+		*s = strings.Fields(str)
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Environment holds the environment variables of a service, given either
+// as a map or as a list of KEY=VALUE entries
+type Environment map[string]string
+
+func (e *Environment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*e = Environment{}
+		for _, kv := range list {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) == 2 {
+				(*e)[parts[0]] = parts[1]
+			} else {
+				(*e)[parts[0]] = ""
+			}
+		}
+		return nil
+	}
+	var m map[string]interface{}
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	*e = Environment{}
+	for k, v := range m {
+		if v == nil {
+			(*e)[k] = ""
+		} else {
+			(*e)[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return nil
+}
+
+// Parse parses a docker-compose file
+func Parse(data []byte) (*File, error) {
+	f := &File{}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, errors.Wrap(err, "Error parsing compose file")
+	}
+	if len(f.Services) == 0 {
+		return nil, errors.New("compose file does not define any services, only version 2 and later files are supported")
+	}
+	for name, s := range f.Services {
+		if s.Image == "" && s.Build.Context == "" {
+			return nil, errors.Errorf("service %s must specify an image or a build context", name)
+		}
+	}
+	return f, nil
+}
+
+// ServiceNames returns the names of the services in the file, sorted
+func (f *File) ServiceNames() []string {
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImageName returns the image used by the service.
+// Images built from the compose file are named after the project and service when not set.
+func (s Service) ImageName(project, name string) string {
+	if s.Image != "" {
+		return s.Image
+	}
+	return fmt.Sprintf("%s_%s", project, name)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+const testComposeFile = `
+version: "3"
+services:
+  web:
+    build: ./web
+    ports:
+      - "8080:80"
+      - "127.0.0.1:5353:53/udp"
+    environment:
+      - DEBUG=1
+      - EMPTY
+    command: run --verbose
+  db:
+    image: postgres:9.6
+    environment:
+      POSTGRES_PASSWORD: secret
+    deploy:
+      replicas: 2
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testComposeFile))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing compose file: %s", err)
+	}
+	if !reflect.DeepEqual(f.ServiceNames(), []string{"db", "web"}) {
+		t.Fatalf("Unexpected services %v", f.ServiceNames())
+	}
+	web := f.Services["web"]
+	if web.Build.Context != "./web" {
+		t.Errorf("Expected build context ./web, got %s", web.Build.Context)
+	}
+	if !reflect.DeepEqual(web.Environment, Environment{"DEBUG": "1", "EMPTY": ""}) {
+		t.Errorf("Unexpected environment %v", web.Environment)
+	}
+	if !reflect.DeepEqual(web.Command, StringOrList{"run", "--verbose"}) {
+		t.Errorf("Unexpected command %v", web.Command)
+	}
+	db := f.Services["db"]
+	if !reflect.DeepEqual(db.Environment, Environment{"POSTGRES_PASSWORD": "secret"}) {
+		t.Errorf("Unexpected environment %v", db.Environment)
+	}
+	if db.Deploy.Replicas == nil || *db.Deploy.Replicas != 2 {
+		t.Errorf("Expected 2 replicas, got %v", db.Deploy.Replicas)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"web:\n  image: nginx\n",
+		"services:\n  web:\n    ports: [80]\n",
+		"services: [",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected error parsing %q", data)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	f, err := Parse([]byte(testComposeFile))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing compose file: %s", err)
+	}
+	objs, err := Convert(f, "app")
+	if err != nil {
+		t.Fatalf("Unexpected error converting compose file: %s", err)
+	}
+	if len(objs.Deployments) != 2 {
+		t.Fatalf("Expected 2 deployments, got %d", len(objs.Deployments))
+	}
+	if len(objs.Services) != 1 {
+		t.Fatalf("Expected 1 service for the published ports, got %d", len(objs.Services))
+	}
+
+	web := objs.Deployments[1].Spec.Template.Spec.Containers[0]
+	if web.Image != "app_web" {
+		t.Errorf("Expected built image app_web, got %s", web.Image)
+	}
+	if web.ImagePullPolicy != v1.PullIfNotPresent {
+		t.Errorf("Expected pull policy %s for built image, got %s", v1.PullIfNotPresent, web.ImagePullPolicy)
+	}
+	svc := objs.Services[0]
+	if svc.Spec.Type != v1.ServiceTypeNodePort {
+		t.Errorf("Expected NodePort service, got %s", svc.Spec.Type)
+	}
+	expectedPorts := []struct {
+		port     int32
+		protocol v1.Protocol
+	}{
+		{80, v1.ProtocolTCP},
+		{53, v1.ProtocolUDP},
+	}
+	for i, p := range expectedPorts {
+		if svc.Spec.Ports[i].Port != p.port || svc.Spec.Ports[i].Protocol != p.protocol {
+			t.Errorf("Expected port %d/%s, got %v", p.port, p.protocol, svc.Spec.Ports[i])
+		}
+	}
+
+	db := objs.Deployments[0]
+	if db.Spec.Template.Spec.Containers[0].Image != "postgres:9.6" {
+		t.Errorf("Expected image postgres:9.6, got %s", db.Spec.Template.Spec.Containers[0].Image)
+	}
+	if db.Labels[ProjectLabel] != "app" {
+		t.Errorf("Expected project label app, got %s", db.Labels[ProjectLabel])
+	}
+}
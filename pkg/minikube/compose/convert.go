@@ -0,0 +1,156 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	"k8s.io/client-go/pkg/util/intstr"
+)
+
+const (
+	// ProjectLabel is set on all the objects deployed from a compose file
+	ProjectLabel = "minikube.k8s.io/compose-project"
+	serviceLabel = "minikube.k8s.io/compose-service"
+)
+
+// Objects are the kubernetes objects generated from a compose file
+type Objects struct {
+	Deployments []*v1beta1.Deployment
+	Services    []*v1.Service
+}
+
+// Convert generates a deployment for every service of the compose file, and a
+// NodePort service for the ones publishing ports.
+func Convert(f *File, project string) (*Objects, error) {
+	objs := &Objects{}
+	for _, name := range f.ServiceNames() {
+		s := f.Services[name]
+		labels := map[string]string{
+			ProjectLabel: project,
+			serviceLabel: name,
+		}
+		ports, err := parsePorts(s.Ports)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing ports of service %s", name)
+		}
+		objs.Deployments = append(objs.Deployments, deployment(name, project, s, labels, ports))
+		if len(ports) > 0 {
+			objs.Services = append(objs.Services, service(name, labels, ports))
+		}
+	}
+	return objs, nil
+}
+
+func deployment(name, project string, s Service, labels map[string]string, ports []v1.ServicePort) *v1beta1.Deployment {
+	container := v1.Container{
+		Name:    name,
+		Image:   s.ImageName(project, name),
+		Command: s.Entrypoint,
+		Args:    s.Command,
+	}
+	// Built images only exist in the docker daemon of the VM
+	if s.Build.Context != "" {
+		container.ImagePullPolicy = v1.PullIfNotPresent
+	}
+	for _, k := range sortedKeys(s.Environment) {
+		container.Env = append(container.Env, v1.EnvVar{Name: k, Value: s.Environment[k]})
+	}
+	for _, p := range ports {
+		container.Ports = append(container.Ports, v1.ContainerPort{
+			ContainerPort: p.TargetPort.IntVal,
+			Protocol:      p.Protocol,
+		})
+	}
+	return &v1beta1.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: v1beta1.DeploymentSpec{
+			Replicas: s.Deploy.Replicas,
+			Selector: &unversioned.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+func service(name string, labels map[string]string, ports []v1.ServicePort) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeNodePort,
+			Selector: labels,
+			Ports:    ports,
+		},
+	}
+}
+
+// parsePorts parses compose port mappings of the form
+// [[HOST_IP:]HOST_PORT:]CONTAINER_PORT[/PROTOCOL]. As the services are exposed
+// on node ports, the host part is ignored.
+func parsePorts(specs []string) ([]v1.ServicePort, error) {
+	var ports []v1.ServicePort
+	for _, spec := range specs {
+		protocol := v1.ProtocolTCP
+		if i := strings.Index(spec, "/"); i >= 0 {
+			switch strings.ToLower(spec[i+1:]) {
+			case "tcp":
+			case "udp":
+				protocol = v1.ProtocolUDP
+			default:
+				return nil, errors.Errorf("unsupported protocol in port %s", spec)
+			}
+			spec = spec[:i]
+		}
+		parts := strings.Split(spec, ":")
+		port, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid port %s", spec)
+		}
+		ports = append(ports, v1.ServicePort{
+			Name:       strings.ToLower(string(protocol)) + "-" + strconv.Itoa(port),
+			Port:       int32(port),
+			TargetPort: intstr.FromInt(port),
+			Protocol:   protocol,
+		})
+	}
+	return ports, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+)
+
+// BuildImages builds the images of the services with a build context using
+// the docker daemon described by dockerEnv. Relative build contexts are
+// resolved against dir, the directory of the compose file.
+func BuildImages(f *File, project, dir string, dockerEnv map[string]string) error {
+	env := os.Environ()
+	for k, v := range dockerEnv {
+		env = append(env, k+"="+v)
+	}
+	for _, name := range f.ServiceNames() {
+		s := f.Services[name]
+		if s.Build.Context == "" {
+			continue
+		}
+		args := buildArgs(s, project, name, dir)
+		glog.Infof("Running docker %v", args)
+		cmd := exec.Command("docker", args...)
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "Error building image for service %s", name)
+		}
+	}
+	return nil
+}
+
+func buildArgs(s Service, project, name, dir string) []string {
+	context := s.Build.Context
+	if !filepath.IsAbs(context) {
+		context = filepath.Join(dir, context)
+	}
+	args := []string{"build", "-t", s.ImageName(project, name)}
+	if s.Build.Dockerfile != "" {
+		args = append(args, "-f", filepath.Join(context, s.Build.Dockerfile))
+	}
+	return append(args, context)
+}
+
+// Apply creates the objects in the namespace, updating the ones which already exist.
+func Apply(client kubernetes.Interface, namespace string, objs *Objects) error {
+	deployments := client.Extensions().Deployments(namespace)
+	for _, d := range objs.Deployments {
+		existing, err := deployments.Get(d.Name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Error getting deployment %s", d.Name)
+			}
+			if _, err := deployments.Create(d); err != nil {
+				return errors.Wrapf(err, "Error creating deployment %s", d.Name)
+			}
+			continue
+		}
+		d.ResourceVersion = existing.ResourceVersion
+		if _, err := deployments.Update(d); err != nil {
+			return errors.Wrapf(err, "Error updating deployment %s", d.Name)
+		}
+	}
+
+	services := client.Core().Services(namespace)
+	for _, s := range objs.Services {
+		existing, err := services.Get(s.Name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Error getting service %s", s.Name)
+			}
+			if _, err := services.Create(s); err != nil {
+				return errors.Wrapf(err, "Error creating service %s", s.Name)
+			}
+			continue
+		}
+		// The cluster IP can not be changed, and the node ports are kept stable
+		s.ResourceVersion = existing.ResourceVersion
+		s.Spec.ClusterIP = existing.Spec.ClusterIP
+		for i := range s.Spec.Ports {
+			for _, p := range existing.Spec.Ports {
+				if p.Name == s.Spec.Ports[i].Name {
+					s.Spec.Ports[i].NodePort = p.NodePort
+				}
+			}
+		}
+		if _, err := services.Update(s); err != nil {
+			return errors.Wrapf(err, "Error updating service %s", s.Name)
+		}
+	}
+	return nil
+}
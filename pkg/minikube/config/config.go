@@ -22,7 +22,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
 
+	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -34,6 +37,15 @@ const (
 	WantKubectlDownloadMsg    = "WantKubectlDownloadMsg"
 )
 
+// ProfileFlag is the flag and viper key selecting the active config profile.
+const ProfileFlag = "profile"
+
+// ServiceClusterIPRangeFlag is the flag and viper key of the network the
+// services of the cluster get their cluster IPs from.
+const ServiceClusterIPRangeFlag = "service-cluster-ip-range"
+
+var validProfileName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
 type MinikubeConfig map[string]interface{}
 
 func Get(name string) (string, error) {
@@ -52,18 +64,103 @@ func get(name string, config MinikubeConfig) (string, error) {
 	}
 }
 
-// ReadConfig reads in the JSON minikube config
+// envKeyReplacer maps setting names to the suffix of their environment
+// variable, e.g. iso-url => ISO_URL
+var envKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+// EnvVar returns the name of the environment variable overriding the setting.
+func EnvVar(name string) string {
+	return constants.MinikubeEnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(name))
+}
+
+// GetWithEnv returns the value of the setting from its environment variable
+// if it is set, falling back to the minikube config.
+func GetWithEnv(name string) (string, error) {
+	if val, ok := os.LookupEnv(EnvVar(name)); ok {
+		return val, nil
+	}
+	return Get(name)
+}
+
+// ActiveProfile returns the name of the profile selected with --profile,
+// or an empty string when only the global config is used.
+func ActiveProfile() string {
+	return viper.GetString(ProfileFlag)
+}
+
+// KubeContext returns the name of the kubeconfig context of the active
+// profile: the name of the profile, or minikube for the global config.
+func KubeContext() string {
+	if profile := ActiveProfile(); profile != "" {
+		return profile
+	}
+	return constants.MinikubeContext
+}
+
+// ServiceClusterIPRange returns the service network set on start or in the
+// config, or an empty string for the default one.
+func ServiceClusterIPRange() string {
+	return viper.GetString(ServiceClusterIPRangeFlag)
+}
+
+// ValidateProfileName checks that the name can be used as a directory name.
+func ValidateProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("%q is not a valid profile name, it must start with a letter or digit and contain only letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// ProfileConfigFile returns the path of the config file of the profile.
+func ProfileConfigFile(name string) string {
+	return constants.MakeMiniPath("profiles", name, "config.json")
+}
+
+// File returns the config file that settings are written to: the one of the
+// active profile, or the global one.
+func File() string {
+	if p := ActiveProfile(); p != "" {
+		return ProfileConfigFile(p)
+	}
+	return constants.ConfigFile
+}
+
+// ReadConfig reads in the JSON minikube config, with the values of the
+// active profile taking precedence over the global ones
 func ReadConfig() (MinikubeConfig, error) {
-	f, err := os.Open(constants.ConfigFile)
+	m, err := ReadFile(constants.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if p := ActiveProfile(); p != "" {
+		profile, err := ReadFile(ProfileConfigFile(p))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range profile {
+			m[k] = v
+		}
+	}
+	return m, nil
+}
+
+// ReadFile reads in a single JSON minikube config file, returning an empty
+// config if it doesn't exist
+func ReadFile(path string) (MinikubeConfig, error) {
+	f, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return make(map[string]interface{}), nil
 		}
-		return nil, fmt.Errorf("Could not open file %s: %s", constants.ConfigFile, err)
+		return nil, fmt.Errorf("Could not open file %s: %s", path, err)
 	}
+	defer f.Close()
 	m, err := decode(f)
 	if err != nil {
-		return nil, fmt.Errorf("Could not decode config %s: %s", constants.ConfigFile, err)
+		return nil, fmt.Errorf("Could not decode config %s: %s", path, err)
+	}
+	if m == nil {
+		m = make(map[string]interface{})
 	}
 
 	return m, nil
@@ -18,8 +18,12 @@ package config
 
 import (
 	"bytes"
+	"os"
 	"reflect"
 	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
 )
 
 type configTestCase struct {
@@ -99,3 +103,71 @@ func TestGet(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateProfileName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"minikube":   true,
+		"dev-1.7_a":  true,
+		"":           false,
+		"-dev":       false,
+		"../etc":     false,
+		"team/dev":   false,
+		"with space": false,
+	} {
+		if err := ValidateProfileName(name); (err == nil) != valid {
+			t.Errorf("Expected %q to be valid: %t, got error: %v", name, valid, err)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	defer viper.Reset()
+
+	if f := File(); f != constants.ConfigFile {
+		t.Errorf("Expected the global config file %s without a profile, got %s", constants.ConfigFile, f)
+	}
+	viper.Set(ProfileFlag, "dev")
+	expected := constants.MakeMiniPath("profiles", "dev", "config.json")
+	if f := File(); f != expected {
+		t.Errorf("Expected the profile config file %s, got %s", expected, f)
+	}
+}
+
+func TestKubeContext(t *testing.T) {
+	defer viper.Reset()
+
+	if c := KubeContext(); c != constants.MinikubeContext {
+		t.Errorf("Expected the context %s without a profile, got %s", constants.MinikubeContext, c)
+	}
+	viper.Set(ProfileFlag, "dev")
+	if c := KubeContext(); c != "dev" {
+		t.Errorf("Expected the context of the profile dev, got %s", c)
+	}
+}
+
+func TestEnvVar(t *testing.T) {
+	for name, expected := range map[string]string{
+		"iso-url":                 "MINIKUBE_ISO_URL",
+		"dashboard":               "MINIKUBE_DASHBOARD",
+		"log_dir":                 "MINIKUBE_LOG_DIR",
+		WantUpdateNotification:    "MINIKUBE_WANTUPDATENOTIFICATION",
+		"addon.heapster.Replicas": "MINIKUBE_ADDON_HEAPSTER_REPLICAS",
+	} {
+		if v := EnvVar(name); v != expected {
+			t.Errorf("Expected the environment variable of %s to be %s, got %s", name, expected, v)
+		}
+	}
+}
+
+func TestGetWithEnv(t *testing.T) {
+	defer os.Unsetenv("MINIKUBE_DASHBOARD")
+
+	os.Setenv("MINIKUBE_DASHBOARD", "false")
+	val, err := GetWithEnv("dashboard")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if val != "false" {
+		t.Errorf("Expected the environment variable to override the config, got %s", val)
+	}
+}
@@ -18,7 +18,6 @@ package constants
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
@@ -30,23 +29,32 @@ import (
 // MachineName is the name to use for the VM.
 const MachineName = "minikube"
 
+// NodeMachineName is the name to use for the VM of another node of the cluster.
+func NodeMachineName(node string) string {
+	return MachineName + "-" + node
+}
+
 // APIServerPort is the port that the API server should listen on.
 const (
 	APIServerPort = 8443
 	APIServerName = "minikubeCA"
 )
 
+// AutoPausePort is the local port of the auto-pause proxy to the apiserver.
+const AutoPausePort = 8444
+
+// The ports etcd serves its clients and the other members of its cluster on.
+const (
+	EtcdClientPort = 2379
+	EtcdPeerPort   = 2380
+)
+
 const MinikubeHome = "MINIKUBE_HOME"
 
-// Minipath is the path to the user's minikube dir
+// Minipath is the path to the user's minikube dir, where the state of
+// minikube is kept. See GetPath for how it is relocated.
 func GetMinipath() string {
-	if os.Getenv(MinikubeHome) == "" {
-		return DefaultMinipath
-	}
-	if filepath.Base(os.Getenv(MinikubeHome)) == ".minikube" {
-		return os.Getenv(MinikubeHome)
-	}
-	return filepath.Join(os.Getenv(MinikubeHome), ".minikube")
+	return GetPath(StatePath)
 }
 
 var DefaultMinipath = filepath.Join(homedir.HomeDir(), ".minikube")
@@ -64,8 +72,23 @@ const MinikubeContext = "minikube"
 const MinikubeEnvPrefix = "MINIKUBE"
 
 // MakeMiniPath is a utility to calculate a relative path to our directory.
+// Paths under config, profiles and cache follow the relocation of the config
+// and cache directories.
 func MakeMiniPath(fileName ...string) string {
 	args := []string{GetMinipath()}
+	if len(fileName) > 0 {
+		switch fileName[0] {
+		case "config":
+			args = []string{GetPath(ConfigPath)}
+			fileName = fileName[1:]
+		case "cache":
+			args = []string{GetPath(CachePath)}
+			fileName = fileName[1:]
+		case "profiles":
+			args = []string{profilesPath()}
+			fileName = fileName[1:]
+		}
+	}
 	args = append(args, fileName...)
 	return filepath.Join(args...)
 }
@@ -85,7 +108,8 @@ const (
 	MinimumDiskSizeMB   = 2000
 	DefaultVMDriver     = "virtualbox"
 	DefaultStatusFormat = "minikubeVM: {{.MinikubeStatus}}\n" +
-		"localkube: {{.LocalkubeStatus}}\n"
+		"localkube: {{.LocalkubeStatus}}\n" +
+		"{{if .WatchdogStatus}}watchdog: {{.WatchdogStatus}}\n{{end}}"
 	DefaultAddonListFormat    = "- {{.AddonName}}: {{.AddonStatus}}\n"
 	DefaultConfigViewFormat   = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
 	GithubMinikubeReleasesURL = "https://storage.googleapis.com/minikube/releases.json"
@@ -95,6 +119,55 @@ const (
 var DefaultIsoUrl = fmt.Sprintf("https://storage.googleapis.com/%s/minikube-%s.iso", minikubeVersion.GetIsoPath(), minikubeVersion.GetIsoVersion())
 var DefaultIsoShaUrl = DefaultIsoUrl + ShaSuffix
 
+// DefaultRTIsoUrl is the iso with a preempt-rt kernel, for latency sensitive workloads.
+var DefaultRTIsoUrl = fmt.Sprintf("https://storage.googleapis.com/%s/minikube-rt-%s.iso", minikubeVersion.GetIsoPath(), minikubeVersion.GetIsoVersion())
+
+// Kernel variants which can be selected with --kernel-variant
+const (
+	KernelVariantDefault = "default"
+	KernelVariantRT      = "rt"
+)
+
+// ContainerDrivers run the node as a container of the host instead of a VM,
+// from NodeImage rather than the ISO.
+var ContainerDrivers = []string{"docker", "podman"}
+
+// NodeImage is the image of the node of the container drivers.
+var NodeImage = fmt.Sprintf("gcr.io/k8s-minikube/node:%s", minikubeVersion.GetIsoVersion())
+
+// IsContainerDriver reports whether the driver runs the node as a container.
+func IsContainerDriver(driver string) bool {
+	for _, d := range ContainerDrivers {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// DriverNone runs the cluster on this machine itself, without a VM, for
+// machines which are already isolated, like CI VMs.
+const DriverNone = "none"
+
+// DriverSSH installs the cluster over ssh on a Linux machine which exists
+// already, like a spare machine of the local network.
+const DriverSSH = "ssh"
+
+// UsesISO reports whether the driver boots a VM from the minikube ISO.
+func UsesISO(driver string) bool {
+	return driver != DriverNone && driver != DriverSSH && !IsContainerDriver(driver)
+}
+
+// SupportedContainerRuntimes are the values of the kubelet --container-runtime flag
+var SupportedContainerRuntimes = []string{"docker", "rkt", "remote"}
+
+// PauseImage is the infrastructure container of the pods, which the kubelet
+// of localkube pulls.
+const PauseImage = "gcr.io/google_containers/pause-amd64:3.0"
+
+// HugepageSizeMB is the size of the hugepages allocated in the VM.
+const HugepageSizeMB = 2
+
 var DefaultKubernetesVersion = version.Get().GitVersion
 
 var ConfigFilePath = MakeMiniPath("config")
@@ -103,6 +176,20 @@ var ConfigFile = MakeMiniPath("config", "config.json")
 var LocalkubeDownloadURLPrefix = "https://storage.googleapis.com/minikube/k8sReleases/"
 var LocalkubeLinuxFilename = "localkube-linux-amd64"
 
+// PreloadURLPrefix is where the preload tarballs of the images of each
+// Kubernetes version and container runtime are published.
+var PreloadURLPrefix = "https://storage.googleapis.com/minikube-preloaded-tarballs/"
+
+// GuestBundleURLPrefix is where the bundles of the tools minikube ssh-install
+// layers into the VM are published.
+var GuestBundleURLPrefix = "https://storage.googleapis.com/minikube-guest-bundles/"
+
+// DockerDaemonPort is the port the Docker daemon in the minikube VM listens on.
+const DockerDaemonPort = 2376
+
+// BuildkitPort is the port the buildkit daemon in the minikube VM listens on.
+const BuildkitPort = 1234
+
 // DockerAPIVersion is the API version implemented by Docker running in the minikube VM.
 const DockerAPIVersion = "1.23"
 
@@ -114,14 +201,61 @@ const (
 	RemoteLocalKubeErrPath = "/var/lib/localkube/localkube.err"
 	RemoteLocalKubeOutPath = "/var/lib/localkube/localkube.out"
 	LocalkubePIDPath       = "/var/run/localkube.pid"
+	// LocalkubePausedPath marks localkube and its containers as paused, until
+	// unpaused or the VM restarts
+	LocalkubePausedPath = "/var/run/localkube.paused"
+	// LocalkubeVersionPath records the Kubernetes version the cluster was
+	// last started with, to detect upgrades
+	LocalkubeVersionPath = "/var/lib/localkube/version"
+	// ContainerLogsDir is where the kubelet links the log files of the
+	// containers of the pods
+	ContainerLogsDir = "/var/log/containers"
+	// HostAliasesDir holds the hosts files the cluster DNS also answers from
+	HostAliasesDir = "/var/lib/localkube/hosts"
 )
 
+// HostAlias resolves to the IP of the host in the VM and in the pods
+const HostAlias = "host.minikube.internal"
+
 const (
 	LocalkubeServicePath = "/usr/lib/systemd/system/localkube.service"
 	LocalkubeRunning     = "active"
 	LocalkubeStopped     = "inactive"
 )
 
+// SeccompProfileRoot is the directory of the seccomp profiles which pods can
+// load with the localhost/<profile> annotation.
+const SeccompProfileRoot = "/var/lib/localkube/seccomp"
+
+// SnapshotsPath is the directory of the snapshots of minikube snapshot, one
+// directory per snapshot with its etcd data and the ids of its images.
+const SnapshotsPath = "/var/lib/localkube/snapshots"
+
+// ScannerCachePath keeps the databases of the scanners of minikube image scan.
+const ScannerCachePath = "/var/lib/localkube/scanner-cache"
+
+// GuestToolsPath is where minikube ssh-install extracts the bundles of the
+// guest tools, one directory per tool with its binaries under bin.
+const GuestToolsPath = "/var/lib/localkube/guest-tools"
+
+// The watchdog restarting localkube when the apiserver or etcd stop responding
+const (
+	LocalkubeWatchdogPath       = "/usr/local/bin/localkube-watchdog"
+	LocalkubeWatchdogStatusPath = "/var/lib/localkube/watchdog/status"
+)
+
+// LocalkubeInstalledPaths are the files start installs on the machines which
+// minikube doesn't create, which deleting them removes.
+var LocalkubeInstalledPaths = []string{
+	"/usr/local/bin/localkube",
+	LocalkubeServicePath,
+	LocalkubeWatchdogPath,
+	"/usr/lib/systemd/system/localkube-watchdog.service",
+	"/usr/lib/systemd/system/localkube-watchdog.timer",
+	AddonsPath,
+	"/var/lib/localkube",
+}
+
 const (
 	DefaultUfsAddress  = ":5640"
 	DefaultUfsDebugLvl = 0
@@ -21,5 +21,8 @@ package constants
 var SupportedVMDrivers = [...]string{
 	"virtualbox",
 	"xhyve",
+	"hyperkit",
+	"qemu",
 	"vmwarefusion",
+	"ssh",
 }
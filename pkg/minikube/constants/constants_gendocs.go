@@ -22,6 +22,13 @@ var SupportedVMDrivers = [...]string{
 	"virtualbox",
 	"vmwarefusion",
 	"kvm",
+	"kvm2",
 	"xhyve",
+	"hyperkit",
 	"hyperv",
+	"qemu",
+	"docker",
+	"podman",
+	"none",
+	"ssh",
 }
@@ -21,4 +21,10 @@ package constants
 var SupportedVMDrivers = [...]string{
 	"virtualbox",
 	"kvm",
+	"kvm2",
+	"qemu",
+	"docker",
+	"podman",
+	"none",
+	"ssh",
 }
@@ -21,4 +21,5 @@ package constants
 var SupportedVMDrivers = [...]string{
 	"virtualbox",
 	"hyperv",
+	"ssh",
 }
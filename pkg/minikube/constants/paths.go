@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// PathKind is one of the directories minikube keeps its files in, which can
+// each be relocated.
+type PathKind string
+
+const (
+	// ConfigPath holds config.json and the profiles
+	ConfigPath PathKind = "config"
+	// CachePath holds the downloaded ISOs and localkube binaries
+	CachePath PathKind = "cache"
+	// StatePath holds the machines, certs, logs and everything else
+	StatePath PathKind = "state"
+)
+
+// Environment variables relocating the config, cache and state directories.
+const (
+	MinikubeConfigDir = "MINIKUBE_CONFIG_DIR"
+	MinikubeCacheDir  = "MINIKUBE_CACHE_DIR"
+	MinikubeStateDir  = "MINIKUBE_STATE_DIR"
+)
+
+// Config keys relocating the cache and state directories. The config
+// directory itself can only be relocated by the environment.
+const (
+	CacheDirSetting = "cache-dir"
+	StateDirSetting = "state-dir"
+)
+
+var dirEnvVars = map[PathKind]string{
+	ConfigPath: MinikubeConfigDir,
+	CachePath:  MinikubeCacheDir,
+	StatePath:  MinikubeStateDir,
+}
+
+var dirSettings = map[PathKind]string{
+	CachePath: CacheDirSetting,
+	StatePath: StateDirSetting,
+}
+
+var xdgEnvVars = map[PathKind]string{
+	ConfigPath: "XDG_CONFIG_HOME",
+	CachePath:  "XDG_CACHE_HOME",
+	StatePath:  "XDG_DATA_HOME",
+}
+
+// GetPath returns the directory of the kind, relocated if configured.
+//
+// The directory is, in order of precedence: the MINIKUBE_*_DIR environment
+// variable, the cache-dir or state-dir config key, the directory under
+// MINIKUBE_HOME if set, minikube under the XDG base directory if its
+// variable is set, and else the directory in the ~/.minikube layout.
+//
+// An existing ~/.minikube layout keeps being used over the XDG base
+// directories until it is migrated, so that setting XDG_*_HOME does not
+// hide the files of an existing cluster.
+func GetPath(kind PathKind) string {
+	dir, explicit := relocatedPath(kind)
+	if dir == "" {
+		return LegacyPath(kind)
+	}
+	if !explicit && !exists(dir) && exists(LegacyPath(kind)) {
+		return LegacyPath(kind)
+	}
+	return dir
+}
+
+// RelocatedPath returns where the directory of the kind is relocated to, or
+// "" if it is kept in the ~/.minikube layout.
+func RelocatedPath(kind PathKind) string {
+	dir, _ := relocatedPath(kind)
+	if dir != "" && filepath.Clean(dir) == filepath.Clean(LegacyPath(kind)) {
+		return ""
+	}
+	return dir
+}
+
+// relocatedPath also returns whether the relocation was asked for
+// explicitly, rather than implied by the XDG base directories.
+func relocatedPath(kind PathKind) (string, bool) {
+	if dir := os.Getenv(dirEnvVars[kind]); dir != "" {
+		return dir, true
+	}
+	if key, ok := dirSettings[kind]; ok {
+		if dir := configuredDir(key); dir != "" {
+			return dir, true
+		}
+	}
+	if os.Getenv(MinikubeHome) != "" {
+		return "", false
+	}
+	if base := os.Getenv(xdgEnvVars[kind]); base != "" {
+		return filepath.Join(base, "minikube"), false
+	}
+	return "", false
+}
+
+// LegacyPath returns the directory of the kind in the ~/.minikube layout,
+// where MINIKUBE_HOME replaces the home directory.
+func LegacyPath(kind PathKind) string {
+	root := DefaultMinipath
+	if home := os.Getenv(MinikubeHome); home != "" {
+		root = home
+		if filepath.Base(home) != ".minikube" {
+			root = filepath.Join(home, ".minikube")
+		}
+	}
+	switch kind {
+	case ConfigPath:
+		return filepath.Join(root, "config")
+	case CachePath:
+		return filepath.Join(root, "cache")
+	}
+	return root
+}
+
+// profilesPath returns the directory of the profiles, which moves along
+// with the config directory.
+func profilesPath() string {
+	config := GetPath(ConfigPath)
+	if config == LegacyPath(ConfigPath) {
+		return filepath.Join(LegacyPath(StatePath), "profiles")
+	}
+	return filepath.Join(config, "profiles")
+}
+
+// configuredDir reads a directory setting straight from config.json, as the
+// config package depends on the paths resolved here.
+func configuredDir(key string) string {
+	data, err := ioutil.ReadFile(filepath.Join(GetPath(ConfigPath), "config.json"))
+	if err != nil {
+		return ""
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ""
+	}
+	dir, _ := m[key].(string)
+	return dir
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
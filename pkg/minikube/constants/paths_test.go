@@ -0,0 +1,146 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "minikube-paths")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	legacy := filepath.Join(tempDir, "home", ".minikube")
+	defer func(d string) { DefaultMinipath = d }(DefaultMinipath)
+	DefaultMinipath = legacy
+
+	vars := []string{MinikubeHome, MinikubeConfigDir, MinikubeCacheDir, MinikubeStateDir, "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME"}
+	for _, v := range vars {
+		defer os.Setenv(v, os.Getenv(v))
+	}
+
+	var tests = []struct {
+		description string
+		env         map[string]string
+		legacyDirs  []string
+		config      string
+		kind        PathKind
+		expected    string
+	}{
+		{
+			description: "legacy state",
+			kind:        StatePath,
+			expected:    legacy,
+		},
+		{
+			description: "legacy cache",
+			kind:        CachePath,
+			expected:    filepath.Join(legacy, "cache"),
+		},
+		{
+			description: "minikube home",
+			env:         map[string]string{MinikubeHome: filepath.Join(tempDir, "mkhome")},
+			kind:        ConfigPath,
+			expected:    filepath.Join(tempDir, "mkhome", ".minikube", "config"),
+		},
+		{
+			description: "environment overrides minikube home",
+			env:         map[string]string{MinikubeHome: filepath.Join(tempDir, "mkhome"), MinikubeCacheDir: "/scratch/minikube"},
+			kind:        CachePath,
+			expected:    "/scratch/minikube",
+		},
+		{
+			description: "xdg base directory",
+			env:         map[string]string{"XDG_DATA_HOME": filepath.Join(tempDir, "data")},
+			kind:        StatePath,
+			expected:    filepath.Join(tempDir, "data", "minikube"),
+		},
+		{
+			description: "xdg base directory ignored under minikube home",
+			env:         map[string]string{MinikubeHome: filepath.Join(tempDir, "mkhome"), "XDG_CACHE_HOME": filepath.Join(tempDir, "cache")},
+			kind:        CachePath,
+			expected:    filepath.Join(tempDir, "mkhome", ".minikube", "cache"),
+		},
+		{
+			description: "existing layout kept until migrated",
+			env:         map[string]string{"XDG_CACHE_HOME": filepath.Join(tempDir, "cache")},
+			legacyDirs:  []string{"cache"},
+			kind:        CachePath,
+			expected:    filepath.Join(legacy, "cache"),
+		},
+		{
+			description: "config key",
+			legacyDirs:  []string{"config"},
+			config:      `{"state-dir": "/scratch/state"}`,
+			kind:        StatePath,
+			expected:    "/scratch/state",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			for _, v := range vars {
+				os.Unsetenv(v)
+			}
+			for k, v := range test.env {
+				os.Setenv(k, v)
+			}
+			os.RemoveAll(legacy)
+			for _, d := range test.legacyDirs {
+				if err := os.MkdirAll(filepath.Join(legacy, d), 0755); err != nil {
+					t.Fatalf("Error creating dir: %s", err)
+				}
+			}
+			if test.config != "" {
+				if err := ioutil.WriteFile(filepath.Join(legacy, "config", "config.json"), []byte(test.config), 0644); err != nil {
+					t.Fatalf("Error writing config: %s", err)
+				}
+			}
+			if actual := GetPath(test.kind); actual != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMakeMiniPath(t *testing.T) {
+	defer os.Setenv(MinikubeHome, os.Getenv(MinikubeHome))
+	defer os.Setenv(MinikubeConfigDir, os.Getenv(MinikubeConfigDir))
+	os.Setenv(MinikubeHome, "/home/user")
+	os.Setenv(MinikubeConfigDir, "/dotfiles/minikube")
+
+	var tests = []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{"certs"}, "/home/user/.minikube/certs"},
+		{[]string{"cache", "iso"}, "/home/user/.minikube/cache/iso"},
+		{[]string{"config", "config.json"}, "/dotfiles/minikube/config.json"},
+		{[]string{"profiles", "dev"}, "/dotfiles/minikube/profiles/dev"},
+	}
+	for _, test := range tests {
+		if actual := MakeMiniPath(test.path...); actual != filepath.FromSlash(test.expected) {
+			t.Errorf("MakeMiniPath(%v): expected %s, got %s", test.path, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package daemons keeps track of the long running minikube processes, such as
+// port forwards, so that they can be stopped when the cluster is deleted.
+package daemons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Daemon is a registered minikube process
+type Daemon struct {
+	Name string
+	PID  int
+	Args []string
+	// Routes are the routes the daemon added to the routing table of the
+	// host, which are left behind if it is killed
+	Routes []Route `json:",omitempty"`
+}
+
+// Route is a route of the host, to Network through the Gateway address
+type Route struct {
+	Network string
+	Gateway string
+}
+
+func daemonsDir() string {
+	return constants.MakeMiniPath("daemons")
+}
+
+func daemonPath(name string) string {
+	return filepath.Join(daemonsDir(), name+".json")
+}
+
+// Register records the current process as the daemon with the given name,
+// which is made unique by appending the process id. The returned function
+// removes the registration and should be called when the process exits.
+func Register(name string) (func(), error) {
+	d := Daemon{
+		Name: fmt.Sprintf("%s-%d", name, os.Getpid()),
+		PID:  os.Getpid(),
+		Args: os.Args,
+	}
+	if err := os.MkdirAll(daemonsDir(), 0755); err != nil {
+		return nil, errors.Wrap(err, "Error creating daemons directory")
+	}
+	if err := save(d); err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := os.Remove(daemonPath(d.Name)); err != nil {
+			glog.Infof("Error unregistering daemon %s: %s", d.Name, err)
+		}
+	}, nil
+}
+
+// RecordRoutes records the routes the current process, registered with the
+// name, adds to the host, for them to be removed if it is killed. They are
+// recorded before being added, so that none is missed.
+func RecordRoutes(name string, routes []Route) error {
+	path := daemonPath(fmt.Sprintf("%s-%d", name, os.Getpid()))
+	d, err := load(path)
+	if err != nil {
+		return err
+	}
+	d.Routes = routes
+	return save(d)
+}
+
+func save(d Daemon) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(daemonPath(d.Name), data, 0644); err != nil {
+		return errors.Wrapf(err, "Error registering daemon %s", d.Name)
+	}
+	return nil
+}
+
+func load(path string) (Daemon, error) {
+	var d Daemon
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return d, errors.Wrapf(err, "Error reading daemon %s", filepath.Base(path))
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return d, errors.Wrapf(err, "Error parsing daemon %s", filepath.Base(path))
+	}
+	return d, nil
+}
+
+// List returns the registered daemons
+func List() ([]Daemon, error) {
+	files, err := ioutil.ReadDir(daemonsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "Error reading daemons directory")
+	}
+	var daemons []Daemon
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		d, err := load(filepath.Join(daemonsDir(), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		daemons = append(daemons, d)
+	}
+	return daemons, nil
+}
+
+// StopAll terminates all the registered daemons and removes their registrations.
+// Daemons which already exited are just unregistered.
+func StopAll() error {
+	daemons, err := List()
+	if err != nil {
+		return err
+	}
+	for _, d := range daemons {
+		if err := stop(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop terminates the daemons registered with the name and removes their
+// registrations. It reports whether any of them was running.
+func Stop(name string) (bool, error) {
+	daemons, err := List()
+	if err != nil {
+		return false, err
+	}
+	stopped := false
+	for _, d := range daemons {
+		if d.Name != fmt.Sprintf("%s-%d", name, d.PID) {
+			continue
+		}
+		if isRunning(d.PID) {
+			stopped = true
+		}
+		if err := stop(d); err != nil {
+			return stopped, err
+		}
+	}
+	return stopped, nil
+}
+
+func stop(d Daemon) error {
+	if p, err := os.FindProcess(d.PID); err == nil {
+		// SIGTERM lets the daemon clean up, but is not supported on windows
+		if err := p.Signal(syscall.SIGTERM); err != nil {
+			if err := p.Kill(); err != nil {
+				glog.Infof("Error stopping daemon %s: %s", d.Name, err)
+			}
+		}
+	}
+	return Unregister(d)
+}
+
+// Stale returns the registered daemons whose process exited without
+// unregistering, e.g. because it was killed.
+func Stale() ([]Daemon, error) {
+	daemons, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var stale []Daemon
+	for _, d := range daemons {
+		if !isRunning(d.PID) {
+			stale = append(stale, d)
+		}
+	}
+	return stale, nil
+}
+
+// IsRunning reports whether a daemon registered with the name is running.
+func IsRunning(name string) (bool, error) {
+	daemons, err := List()
+	if err != nil {
+		return false, err
+	}
+	for _, d := range daemons {
+		if d.Name == fmt.Sprintf("%s-%d", name, d.PID) && isRunning(d.PID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Unregister removes the registration of the daemon.
+func Unregister(d Daemon) error {
+	if err := os.Remove(daemonPath(d.Name)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Error unregistering daemon %s", d.Name)
+	}
+	return nil
+}
+
+func isRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for running processes on windows, where
+	// signal 0 is not supported
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestRegister(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	unregister, err := Register("port-forward")
+	if err != nil {
+		t.Fatalf("Unexpected error registering daemon: %s", err)
+	}
+	daemons, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing daemons: %s", err)
+	}
+	if len(daemons) != 1 || daemons[0].PID != os.Getpid() {
+		t.Fatalf("Expected the test process to be registered, got %v", daemons)
+	}
+
+	unregister()
+	daemons, err = List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing daemons: %s", err)
+	}
+	if len(daemons) != 0 {
+		t.Fatalf("Expected no daemons after unregistering, got %v", daemons)
+	}
+}
+
+func TestRecordRoutes(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if err := RecordRoutes("tunnel", []Route{{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}}); err == nil {
+		t.Errorf("Expected an error recording the routes of an unregistered daemon")
+	}
+	unregister, err := Register("tunnel")
+	if err != nil {
+		t.Fatalf("Unexpected error registering daemon: %s", err)
+	}
+	defer unregister()
+	routes := []Route{{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}}
+	if err := RecordRoutes("tunnel", routes); err != nil {
+		t.Fatalf("Unexpected error recording the routes: %s", err)
+	}
+	daemons, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing daemons: %s", err)
+	}
+	if len(daemons) != 1 || !reflect.DeepEqual(daemons[0].Routes, routes) {
+		t.Fatalf("Expected the routes %v to be recorded, got %v", routes, daemons)
+	}
+}
+
+func TestStale(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	unregister, err := Register("port-forward")
+	if err != nil {
+		t.Fatalf("Unexpected error registering daemon: %s", err)
+	}
+	defer unregister()
+	exited := Daemon{Name: "tunnel-1", PID: 1 << 22}
+	data, _ := json.Marshal(exited)
+	if err := ioutil.WriteFile(daemonPath(exited.Name), data, 0644); err != nil {
+		t.Fatalf("Unexpected error writing daemon: %s", err)
+	}
+
+	stale, err := Stale()
+	if err != nil {
+		t.Fatalf("Unexpected error listing stale daemons: %s", err)
+	}
+	if len(stale) != 1 || stale[0].Name != exited.Name {
+		t.Fatalf("Expected only the exited daemon to be stale, got %v", stale)
+	}
+	if err := Unregister(stale[0]); err != nil {
+		t.Fatalf("Unexpected error unregistering daemon: %s", err)
+	}
+	daemons, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing daemons: %s", err)
+	}
+	if len(daemons) != 1 || daemons[0].PID != os.Getpid() {
+		t.Errorf("Expected only the running daemon to be left, got %v", daemons)
+	}
+}
+
+func TestIsRunning(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	exited := Daemon{Name: fmt.Sprintf("apiserver-proxy-%d", 1<<22), PID: 1 << 22}
+	data, _ := json.Marshal(exited)
+	if err := os.MkdirAll(daemonsDir(), 0755); err != nil {
+		t.Fatalf("Unexpected error creating daemons dir: %s", err)
+	}
+	if err := ioutil.WriteFile(daemonPath(exited.Name), data, 0644); err != nil {
+		t.Fatalf("Unexpected error writing daemon: %s", err)
+	}
+	if running, err := IsRunning("apiserver-proxy"); err != nil || running {
+		t.Fatalf("Expected the exited daemon not to be running, got %t, %v", running, err)
+	}
+
+	unregister, err := Register("apiserver-proxy")
+	if err != nil {
+		t.Fatalf("Unexpected error registering daemon: %s", err)
+	}
+	defer unregister()
+	if running, err := IsRunning("apiserver-proxy"); err != nil || !running {
+		t.Fatalf("Expected the daemon to be running, got %t, %v", running, err)
+	}
+	if running, err := IsRunning("apiserver"); err != nil || running {
+		t.Fatalf("Expected no daemon named apiserver to be running, got %t, %v", running, err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	if err := os.MkdirAll(daemonsDir(), 0755); err != nil {
+		t.Fatalf("Unexpected error creating daemons dir: %s", err)
+	}
+
+	sleep := exec.Command("sleep", "60")
+	if err := sleep.Start(); err != nil {
+		t.Skipf("Unable to start a process to stop: %s", err)
+	}
+	for _, d := range []Daemon{
+		{Name: fmt.Sprintf("scheduled-stop-%d", sleep.Process.Pid), PID: sleep.Process.Pid},
+		{Name: fmt.Sprintf("port-forward-%d", 1<<22), PID: 1 << 22},
+	} {
+		data, _ := json.Marshal(d)
+		if err := ioutil.WriteFile(daemonPath(d.Name), data, 0644); err != nil {
+			t.Fatalf("Unexpected error writing daemon: %s", err)
+		}
+	}
+
+	if stopped, err := Stop("port-forward"); err != nil || stopped {
+		t.Fatalf("Expected the exited daemon not to be stopped, got %t, %v", stopped, err)
+	}
+	if stopped, err := Stop("scheduled-stop"); err != nil || !stopped {
+		t.Fatalf("Expected the daemon to be stopped, got %t, %v", stopped, err)
+	}
+	if err := sleep.Wait(); err == nil {
+		t.Errorf("Expected the process of the daemon to be terminated")
+	}
+	daemons, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing daemons: %s", err)
+	}
+	if len(daemons) != 0 {
+		t.Errorf("Expected the daemons to be unregistered, got %v", daemons)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns helps debugging the service discovery of the cluster, by
+// logging the queries kube-dns answers and resolving names from a pod.
+package dns
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// Addon is the addon running the cluster DNS
+	Addon = "kube-dns"
+	// LogQueriesValue is the kube-dns addon value making dnsmasq log the
+	// queries it answers
+	LogQueriesValue = "logQueries"
+	// Selector selects the pods of the cluster DNS
+	Selector = "k8s-app=kube-dns"
+	// QueryImage runs the lookups
+	QueryImage = "busybox:1.26"
+)
+
+// LogQueriesSetting is the config property enabling the logging of the queries
+var LogQueriesSetting = assets.AddonValuePrefix + Addon + "." + LogQueriesValue
+
+const (
+	// IngressAddon is the addon answering for the names of a domain with the
+	// IP of minikube, for the host to reach the hosts of the Ingresses
+	IngressAddon = "ingress-dns"
+	// DomainValue is the ingress-dns addon value holding the domain
+	DomainValue = "domain"
+)
+
+// IngressDomainSetting is the config property setting the domain of ingress-dns
+var IngressDomainSetting = assets.AddonValuePrefix + IngressAddon + "." + DomainValue
+
+// The lookup runs in a shell so that the resolv.conf of the pod, with the
+// search domains names are resolved in, is printed first.
+const queryScript = `cat /etc/resolv.conf; echo; nslookup "$0"`
+
+// names can be resolved: a dot separated list of labels, optionally fully qualified
+var nameRe = regexp.MustCompile(`^([a-zA-Z0-9_]([-a-zA-Z0-9_]*[a-zA-Z0-9])?)(\.[a-zA-Z0-9_]([-a-zA-Z0-9_]*[a-zA-Z0-9])?)*\.?$`)
+
+// ValidateName checks that name is a DNS name which can be looked up.
+func ValidateName(name string) error {
+	if len(name) > 253 || !nameRe.MatchString(name) {
+		return errors.Errorf("%q is not a valid DNS name", name)
+	}
+	return nil
+}
+
+// QueryPod returns the pod looking name up in namespace, with the DNS
+// settings of the pods of the cluster.
+func QueryPod(name, namespace string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: "dns-query-",
+			Namespace:    namespace,
+			Labels:       map[string]string{"app": "minikube-dns-query"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:            "query",
+				Image:           QueryImage,
+				ImagePullPolicy: v1.PullIfNotPresent,
+				Command:         []string{"sh", "-c", queryScript, name},
+			}},
+			RestartPolicy: v1.RestartPolicyNever,
+			DNSPolicy:     v1.DNSClusterFirst,
+		},
+	}
+}
+
+// Query looks name up from a pod in namespace, as the pods of the cluster
+// would, and returns the output of the lookup. The pod is deleted once done.
+func Query(client kubernetes.Interface, name, namespace string, timeout time.Duration) (string, error) {
+	pods := client.Core().Pods(namespace)
+	pod, err := pods.Create(QueryPod(name, namespace))
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating the query pod")
+	}
+	defer pods.Delete(pod.Name, &v1.DeleteOptions{})
+
+	done := func() error {
+		p, err := pods.Get(pod.Name)
+		if err != nil {
+			return err
+		}
+		pod = p
+		if p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed {
+			return errors.Errorf("pod %s is %s", p.Name, p.Status.Phase)
+		}
+		return nil
+	}
+	if err := util.RetryAfter(int(timeout/time.Second), done, time.Second); err != nil {
+		return "", errors.Wrap(err, "Timed out waiting for the query pod")
+	}
+
+	logs, err := pods.GetLogs(pod.Name, &v1.PodLogOptions{}).DoRaw()
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting the output of the query pod")
+	}
+	output := strings.TrimSpace(string(logs))
+	if pod.Status.Phase == v1.PodFailed {
+		return output, errors.Errorf("%s could not be resolved", name)
+	}
+	return output, nil
+}
+
+// Restart deletes the pods of the cluster DNS, for their replication
+// controller to create them again with its current spec.
+func Restart(client kubernetes.Interface) error {
+	pods := client.Core().Pods("kube-system")
+	list, err := pods.List(v1.ListOptions{LabelSelector: Selector})
+	if err != nil {
+		return errors.Wrap(err, "Error listing the kube-dns pods")
+	}
+	for _, p := range list.Items {
+		if err := pods.Delete(p.Name, &v1.DeleteOptions{}); err != nil {
+			return errors.Wrapf(err, "Error deleting pod %s", p.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestValidateName(t *testing.T) {
+	var tests = []struct {
+		name  string
+		valid bool
+	}{
+		{name: "kubernetes", valid: true},
+		{name: "kubernetes.default.svc.cluster.local", valid: true},
+		{name: "kubernetes.default.svc.cluster.local.", valid: true},
+		{name: "_http._tcp.nginx.default", valid: true},
+		{name: "my-svc.my-namespace", valid: true},
+		{name: "", valid: false},
+		{name: "-svc", valid: false},
+		{name: "svc..default", valid: false},
+		{name: "svc; rm -rf /", valid: false},
+		{name: "$(id)", valid: false},
+	}
+	for _, test := range tests {
+		err := ValidateName(test.name)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateName(%q): expected valid %t, got error %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestQueryPod(t *testing.T) {
+	pod := QueryPod("kubernetes.default", "test")
+	if pod.Namespace != "test" {
+		t.Errorf("Expected the pod in namespace test, got %s", pod.Namespace)
+	}
+	if pod.Spec.RestartPolicy != v1.RestartPolicyNever {
+		t.Errorf("Expected the pod to run once, got restart policy %s", pod.Spec.RestartPolicy)
+	}
+	if pod.Spec.DNSPolicy != v1.DNSClusterFirst {
+		t.Errorf("Expected the pod to use the cluster DNS, got DNS policy %s", pod.Spec.DNSPolicy)
+	}
+	// The name is passed as an argument of the script rather than inside it
+	expected := []string{"sh", "-c", queryScript, "kubernetes.default"}
+	if c := pod.Spec.Containers[0].Command; !reflect.DeepEqual(c, expected) {
+		t.Errorf("Expected command %q, got %q", expected, c)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dockerdaemon renders the daemon.json of the docker daemon of the
+// VM from the docker-daemon.* settings of the config, which minikube start
+// writes again each time, the ISO losing it when the VM restarts.
+package dockerdaemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// Prefix prefixes the settings of the docker daemon, e.g.
+// docker-daemon.log-max-size
+const Prefix = "docker-daemon."
+
+// The settings, following Prefix
+const (
+	LogDriver      = "log-driver"
+	LogMaxSize     = "log-max-size"
+	LogMaxFile     = "log-max-file"
+	StorageDriver  = "storage-driver"
+	CgroupDriver   = "cgroup-driver"
+	LiveRestore    = "live-restore"
+	DefaultUlimits = "default-ulimits"
+)
+
+// The rotation of the logs of the containers when the settings don't set it,
+// which docker otherwise keeps growing until the disk of the VM is full
+const (
+	DefaultLogMaxSize = "10m"
+	DefaultLogMaxFile = "3"
+)
+
+// Fields are the settings, following Prefix
+var Fields = []string{LogDriver, LogMaxSize, LogMaxFile, StorageDriver, CgroupDriver, LiveRestore, DefaultUlimits}
+
+var (
+	logDrivers     = []string{"json-file", "journald", "syslog", "none"}
+	storageDrivers = []string{"overlay2", "overlay", "aufs", "devicemapper", "btrfs", "vfs"}
+	cgroupDrivers  = []string{"cgroupfs", "systemd"}
+	ulimitNames    = []string{"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice", "nofile", "nproc", "rss", "rtprio", "rttime", "sigpending", "stack"}
+
+	logSizePattern = regexp.MustCompile(`^[1-9][0-9]*[kmg]?$`)
+)
+
+// flags are the flags of the docker daemon setting what the fields of
+// daemon.json do, which it refuses to start with when both are given
+var flags = map[string]string{
+	LogDriver:      "log-driver",
+	LogMaxSize:     "log-opt",
+	LogMaxFile:     "log-opt",
+	StorageDriver:  "storage-driver",
+	CgroupDriver:   "exec-opt",
+	LiveRestore:    "live-restore",
+	DefaultUlimits: "default-ulimit",
+}
+
+// Settings are the settings of the docker daemon, keyed by the part of their
+// name following Prefix.
+type Settings map[string]string
+
+// Load returns the settings of the docker daemon in the config.
+func Load() (Settings, error) {
+	m, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	s := Settings{}
+	for k, v := range m {
+		if !strings.HasPrefix(k, Prefix) {
+			continue
+		}
+		val := fmt.Sprintf("%v", v)
+		if err := Validate(k, val); err != nil {
+			return nil, err
+		}
+		s[strings.TrimPrefix(k, Prefix)] = val
+	}
+	return s, nil
+}
+
+// Validate checks the value of a setting of the docker daemon.
+func Validate(name, val string) error {
+	field := strings.TrimPrefix(name, Prefix)
+	switch field {
+	case LogDriver:
+		return oneOf(field, val, logDrivers)
+	case LogMaxSize:
+		if !logSizePattern.MatchString(val) {
+			return errors.Errorf("%s is not a size of the form 10m, in bytes or with a k, m or g unit", val)
+		}
+	case LogMaxFile:
+		if n, err := strconv.Atoi(val); err != nil || n < 1 {
+			return errors.Errorf("%s is not a positive number of files", val)
+		}
+	case StorageDriver:
+		return oneOf(field, val, storageDrivers)
+	case CgroupDriver:
+		return oneOf(field, val, cgroupDrivers)
+	case LiveRestore:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return errors.Errorf("%s is not true or false", val)
+		}
+	case DefaultUlimits:
+		_, err := parseUlimits(val)
+		return err
+	default:
+		return errors.Errorf("%s is not a setting of the docker daemon, expected %s followed by one of %s", name, Prefix, strings.Join(Fields, ", "))
+	}
+	return nil
+}
+
+func oneOf(field, val string, values []string) error {
+	for _, v := range values {
+		if v == val {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not a %s, expected one of %s", val, field, strings.Join(values, ", "))
+}
+
+// ulimit is a limit of the containers, as docker reads it from daemon.json
+type ulimit struct {
+	Name string
+	Hard int64
+	Soft int64
+}
+
+// parseUlimits parses NAME=SOFT[:HARD],..., the hard limit defaulting to the
+// soft one.
+func parseUlimits(val string) (map[string]ulimit, error) {
+	limits := map[string]ulimit{}
+	for _, entry := range strings.Split(val, ",") {
+		entry = strings.TrimSpace(entry)
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("%s is not of the form NAME=SOFT[:HARD]", entry)
+		}
+		if err := oneOf("ulimit", kv[0], ulimitNames); err != nil {
+			return nil, err
+		}
+		values := strings.SplitN(kv[1], ":", 2)
+		soft, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s is not a limit of %s", values[0], kv[0])
+		}
+		hard := soft
+		if len(values) == 2 {
+			if hard, err = strconv.ParseInt(values[1], 10, 64); err != nil {
+				return nil, errors.Errorf("%s is not a limit of %s", values[1], kv[0])
+			}
+		}
+		if soft > hard {
+			return nil, errors.Errorf("The soft limit of %s, %d, is over its hard limit, %d", kv[0], soft, hard)
+		}
+		limits[kv[0]] = ulimit{Name: kv[0], Hard: hard, Soft: soft}
+	}
+	return limits, nil
+}
+
+// daemonConfig holds the fields of daemon.json the settings set
+type daemonConfig struct {
+	LogDriver      string            `json:"log-driver,omitempty"`
+	LogOpts        map[string]string `json:"log-opts,omitempty"`
+	StorageDriver  string            `json:"storage-driver,omitempty"`
+	ExecOpts       []string          `json:"exec-opts,omitempty"`
+	LiveRestore    bool              `json:"live-restore,omitempty"`
+	DefaultUlimits map[string]ulimit `json:"default-ulimits,omitempty"`
+}
+
+// Render returns the daemon.json of the settings, empty when there are none.
+func (s Settings) Render() (string, error) {
+	if len(s) == 0 {
+		return "", nil
+	}
+	c := daemonConfig{
+		LogDriver:     s[LogDriver],
+		StorageDriver: s[StorageDriver],
+	}
+	// The sizes and numbers of files only bound the logs of json-file
+	if s[LogMaxSize] != "" || s[LogMaxFile] != "" {
+		if c.LogDriver != "" && c.LogDriver != "json-file" {
+			return "", errors.Errorf("%s%s and %s%s only apply to the json-file log driver, not %s", Prefix, LogMaxSize, Prefix, LogMaxFile, c.LogDriver)
+		}
+		c.LogOpts = map[string]string{}
+		if s[LogMaxSize] != "" {
+			c.LogOpts["max-size"] = s[LogMaxSize]
+		}
+		if s[LogMaxFile] != "" {
+			c.LogOpts["max-file"] = s[LogMaxFile]
+		}
+	}
+	if s[CgroupDriver] != "" {
+		c.ExecOpts = []string{"native.cgroupdriver=" + s[CgroupDriver]}
+	}
+	c.LiveRestore, _ = strconv.ParseBool(s[LiveRestore])
+	if s[DefaultUlimits] != "" {
+		limits, err := parseUlimits(s[DefaultUlimits])
+		if err != nil {
+			return "", err
+		}
+		c.DefaultUlimits = limits
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "Error encoding daemon.json")
+	}
+	return string(b) + "\n", nil
+}
+
+// CheckFlags checks that none of the flags of the docker daemon given with
+// --docker-opt, KEY=VALUE, sets what a setting does.
+func (s Settings) CheckFlags(dockerOpt []string) error {
+	var fields []string
+	for field := range s {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		for _, opt := range dockerOpt {
+			if strings.SplitN(opt, "=", 2)[0] == flags[field] {
+				return errors.Errorf("--docker-opt %s conflicts with %s%s, the docker daemon refuses to start with both, unset one of them", opt, Prefix, field)
+			}
+		}
+	}
+	return nil
+}
+
+// WithLogRotation returns the settings with the rotation of the logs of the
+// containers they don't set, unless they use another log driver than
+// json-file, or the flags given with --docker-opt set the logging.
+func (s Settings) WithLogRotation(dockerOpt []string) Settings {
+	if s[LogDriver] != "" && s[LogDriver] != "json-file" {
+		return s
+	}
+	for _, opt := range dockerOpt {
+		if flag := strings.SplitN(opt, "=", 2)[0]; flag == flags[LogDriver] || flag == flags[LogMaxSize] {
+			return s
+		}
+	}
+	r := Settings{LogMaxSize: DefaultLogMaxSize, LogMaxFile: DefaultLogMaxFile}
+	for field, val := range s {
+		r[field] = val
+	}
+	return r
+}
+
+// ExtraOptions returns the options of the components the settings need: the
+// kubelet must use the cgroup driver of the docker daemon.
+func (s Settings) ExtraOptions() util.ExtraOptionSlice {
+	if s[CgroupDriver] == "" {
+		return nil
+	}
+	return util.ExtraOptionSlice{{Component: "kubelet", Key: "CgroupDriver", Value: s[CgroupDriver]}}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockerdaemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	var tcs = []struct {
+		name      string
+		val       string
+		shouldErr bool
+	}{
+		{name: "docker-daemon.log-driver", val: "journald"},
+		{name: "docker-daemon.log-max-size", val: "10m"},
+		{name: "docker-daemon.log-max-file", val: "3"},
+		{name: "docker-daemon.storage-driver", val: "overlay2"},
+		{name: "docker-daemon.cgroup-driver", val: "systemd"},
+		{name: "docker-daemon.live-restore", val: "true"},
+		{name: "docker-daemon.default-ulimits", val: "nofile=1024:65536,nproc=4096"},
+		{name: "docker-daemon.log-driver", val: "splunk", shouldErr: true},
+		{name: "docker-daemon.log-max-size", val: "10 MB", shouldErr: true},
+		{name: "docker-daemon.log-max-file", val: "0", shouldErr: true},
+		{name: "docker-daemon.cgroup-driver", val: "cgroupfs2", shouldErr: true},
+		{name: "docker-daemon.live-restore", val: "yes please", shouldErr: true},
+		{name: "docker-daemon.default-ulimits", val: "nofile=65536:1024", shouldErr: true},
+		{name: "docker-daemon.default-ulimits", val: "files=1024", shouldErr: true},
+		{name: "docker-daemon.debug", val: "true", shouldErr: true},
+	}
+	for _, test := range tcs {
+		if err := Validate(test.name, test.val); (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s = %s: %v", test.name, test.val, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	if out, err := (Settings{}).Render(); err != nil || out != "" {
+		t.Errorf("Expected no config without settings, got %q, %v", out, err)
+	}
+	s := Settings{
+		LogMaxSize:     "10m",
+		LogMaxFile:     "3",
+		CgroupDriver:   "systemd",
+		LiveRestore:    "true",
+		DefaultUlimits: "nofile=1024:65536",
+	}
+	expected := `{
+  "log-opts": {
+    "max-file": "3",
+    "max-size": "10m"
+  },
+  "exec-opts": [
+    "native.cgroupdriver=systemd"
+  ],
+  "live-restore": true,
+  "default-ulimits": {
+    "nofile": {
+      "Name": "nofile",
+      "Hard": 65536,
+      "Soft": 1024
+    }
+  }
+}
+`
+	out, err := s.Render()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if out != expected {
+		t.Errorf("Expected %s, got %s", expected, out)
+	}
+	s[LogDriver] = "journald"
+	if _, err := s.Render(); err == nil {
+		t.Errorf("Expected an error for log options of journald")
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	s := Settings{StorageDriver: "overlay2", LogMaxSize: "10m"}
+	if err := s.CheckFlags([]string{"debug=true", "log-level=warn"}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	for _, opt := range []string{"storage-driver=devicemapper", "log-opt=max-file=5"} {
+		if err := s.CheckFlags([]string{opt}); err == nil {
+			t.Errorf("Expected --docker-opt %s to conflict", opt)
+		}
+	}
+	if opts := s.ExtraOptions(); len(opts) != 0 {
+		t.Errorf("Expected no extra options, got %v", opts)
+	}
+	s[CgroupDriver] = "systemd"
+	if opts := s.ExtraOptions(); len(opts) != 1 || opts[0].String() != "kubelet.CgroupDriver=systemd" {
+		t.Errorf("Expected the cgroup driver of the kubelet, got %v", opts)
+	}
+}
+
+func TestWithLogRotation(t *testing.T) {
+	var tests = []struct {
+		settings  Settings
+		dockerOpt []string
+		expected  Settings
+	}{
+		{
+			settings: Settings{},
+			expected: Settings{LogMaxSize: DefaultLogMaxSize, LogMaxFile: DefaultLogMaxFile},
+		},
+		{
+			settings: Settings{LogMaxSize: "100m", StorageDriver: "overlay2"},
+			expected: Settings{LogMaxSize: "100m", LogMaxFile: DefaultLogMaxFile, StorageDriver: "overlay2"},
+		},
+		{
+			settings: Settings{LogDriver: "journald"},
+			expected: Settings{LogDriver: "journald"},
+		},
+		{
+			settings:  Settings{},
+			dockerOpt: []string{"log-opt=max-size=1g"},
+			expected:  Settings{},
+		},
+	}
+	for _, test := range tests {
+		if s := test.settings.WithLogRotation(test.dockerOpt); !reflect.DeepEqual(s, test.expected) {
+			t.Errorf("Expected %v for %v and %v, got %v", test.expected, test.settings, test.dockerOpt, s)
+		}
+	}
+}
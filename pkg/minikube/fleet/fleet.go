@@ -0,0 +1,212 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleet reports the clusters of the developers of a team to a shared
+// registry, for the platform teams supporting them to see their local
+// environments, and serves that registry.
+package fleet
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config settings of the reporting mode, the registry is only reported to
+// when the endpoint is set
+const (
+	EndpointSetting = "fleet-endpoint"
+	TokenSetting    = "fleet-token"
+)
+
+// DefaultTTL is how long the registry lists a cluster after its last
+// report, a reporter which stopped reporting drops out of the list.
+const DefaultTTL = 5 * time.Minute
+
+// Cluster is the cluster of a profile of a developer, as reported.
+type Cluster struct {
+	Host    string `json:"host"`
+	User    string `json:"user"`
+	Profile string `json:"profile"`
+	// Status is the one of the VM, Localkube the one of localkube when the
+	// VM runs
+	Status            string `json:"status"`
+	Localkube         string `json:"localkube,omitempty"`
+	Driver            string `json:"driver,omitempty"`
+	IP                string `json:"ip,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	MinikubeVersion   string `json:"minikubeVersion"`
+	// Reported is the time of the last report, set by the registry
+	Reported time.Time `json:"reported"`
+}
+
+func (c Cluster) key() string {
+	return c.Host + "/" + c.User + "/" + c.Profile
+}
+
+// clustersURL returns the URL the clusters are reported to and listed from.
+func clustersURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.Errorf("%q is not an http or https URL", endpoint)
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/clusters", nil
+}
+
+// ValidateEndpoint checks that the endpoint is the URL of a registry.
+func ValidateEndpoint(endpoint string) error {
+	_, err := clustersURL(endpoint)
+	return err
+}
+
+// client reports and lists the clusters, it is replaced in tests.
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func do(method, endpoint, token string, body []byte) (*http.Response, error) {
+	u, err := clustersURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, errors.Errorf("%s %s: %s", method, u, resp.Status)
+	}
+	return resp, nil
+}
+
+// Report registers the cluster with the registry at the endpoint.
+func Report(endpoint, token string, c Cluster) error {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	resp, err := do("POST", endpoint, token, body)
+	if err != nil {
+		return errors.Wrap(err, "Error reporting the cluster")
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// List returns the clusters of the registry at the endpoint.
+func List(endpoint, token string) ([]Cluster, error) {
+	resp, err := do("GET", endpoint, token, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing the clusters")
+	}
+	defer resp.Body.Close()
+	var clusters []Cluster
+	if err := json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+		return nil, errors.Wrap(err, "Error parsing the clusters")
+	}
+	return clusters, nil
+}
+
+// Registry holds the clusters reported to it, and lists those reported
+// within the TTL, sorted by host, user and profile. It serves /clusters,
+// requiring the token as a bearer token when it is set.
+type Registry struct {
+	Token string
+	TTL   time.Duration
+
+	now      func() time.Time
+	mu       sync.Mutex
+	clusters map[string]Cluster
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry(token string, ttl time.Duration) *Registry {
+	return &Registry{Token: token, TTL: ttl, now: time.Now, clusters: map[string]Cluster{}}
+}
+
+// Clusters returns the clusters reported within the TTL, forgetting the
+// others.
+func (r *Registry) Clusters() []Cluster {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clusters := []Cluster{}
+	for k, c := range r.clusters {
+		if r.now().Sub(c.Reported) > r.TTL {
+			delete(r.clusters, k)
+			continue
+		}
+		clusters = append(clusters, c)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].key() < clusters[j].key() })
+	return clusters
+}
+
+// Add records the cluster, replacing its previous report.
+func (r *Registry) Add(c Cluster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The clocks of the reporters may be off
+	c.Reported = r.now().UTC()
+	r.clusters[c.key()] = c
+}
+
+func (r *Registry) authorized(req *http.Request) bool {
+	if r.Token == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), []byte("Bearer "+r.Token)) == 1
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/clusters" {
+		http.NotFound(w, req)
+		return
+	}
+	if !r.authorized(req) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	switch req.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.Clusters())
+	case "POST":
+		var c Cluster
+		if err := json.NewDecoder(req.Body).Decode(&c); err != nil || c.Host == "" {
+			http.Error(w, "expected a cluster with its host", http.StatusBadRequest)
+			return
+		}
+		r.Add(c)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "expected GET or POST", http.StatusMethodNotAllowed)
+	}
+}
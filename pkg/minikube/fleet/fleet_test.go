@@ -0,0 +1,70 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportList(t *testing.T) {
+	now := time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry("s3cret", DefaultTTL)
+	r.now = func() time.Time { return now }
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	if err := Report(server.URL, "wrong", Cluster{Host: "laptop"}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the report to be refused without the token, got %v", err)
+	}
+	if err := Report(server.URL+"/", "s3cret", Cluster{}); err == nil {
+		t.Errorf("Expected an error for a cluster without host")
+	}
+
+	reports := []Cluster{
+		{Host: "laptop", User: "alice", Profile: "dev", Status: "Running", Driver: "kvm2"},
+		{Host: "desktop", User: "bob", Status: "Stopped"},
+		// The second report of a cluster replaces the first
+		{Host: "laptop", User: "alice", Profile: "dev", Status: "Stopped", Driver: "kvm2"},
+	}
+	for _, c := range reports {
+		if err := Report(server.URL, "s3cret", c); err != nil {
+			t.Fatalf("Unexpected error reporting %v: %s", c, err)
+		}
+	}
+	clusters, err := List(server.URL, "s3cret")
+	if err != nil {
+		t.Fatalf("Unexpected error listing the clusters: %s", err)
+	}
+	if len(clusters) != 2 || clusters[0].Host != "desktop" || clusters[1].Status != "Stopped" || !clusters[1].Reported.Equal(now) {
+		t.Errorf("Expected the clusters of desktop and laptop, stopped, got %v", clusters)
+	}
+
+	// The laptop keeps reporting, the desktop stopped
+	now = now.Add(3 * time.Minute)
+	r.Add(reports[2])
+	now = now.Add(3 * time.Minute)
+	if clusters := r.Clusters(); len(clusters) != 1 || clusters[0].Host != "laptop" {
+		t.Errorf("Expected the cluster which stopped reporting to drop out, got %v", clusters)
+	}
+
+	if _, err := List("ftp://example.com", ""); err == nil {
+		t.Errorf("Expected an error for an endpoint which is not http")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc finds what minikube and the cluster leave behind over time, so
+// that it can be removed by minikube gc.
+package gc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Categories of what is collected
+const (
+	Images  = "images"
+	Cache   = "cache"
+	Drivers = "drivers"
+	Pods    = "pods"
+	Tunnels = "tunnels"
+)
+
+// Categories lists all the categories, in the order they are collected
+var Categories = []string{Images, Cache, Drivers, Pods, Tunnels}
+
+// StaleCacheFiles returns the files cached on the host, ISOs and localkube
+// binaries, other than those in keep.
+func StaleCacheFiles(keep []string) ([]string, error) {
+	kept := map[string]bool{}
+	for _, k := range keep {
+		kept[filepath.Clean(k)] = true
+	}
+	var stale []string
+	for _, dir := range []string{constants.MakeMiniPath("cache", "iso"), constants.MakeMiniPath("cache", "localkube")} {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "Error reading %s", dir)
+		}
+		for _, f := range files {
+			path := filepath.Join(dir, f.Name())
+			if !f.IsDir() && !kept[path] {
+				stale = append(stale, path)
+			}
+		}
+	}
+	return stale, nil
+}
+
+// OrphanedMachines returns the directories in the machines directory left
+// by the driver which don't hold the config of the minikube machine or of
+// a node, e.g. after a failed creation.
+func OrphanedMachines() ([]string, error) {
+	dir := constants.MakeMiniPath("machines")
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error reading %s", dir)
+	}
+	var orphaned []string
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		if f.Name() == constants.MachineName || strings.HasPrefix(f.Name(), constants.NodeMachineName("")) {
+			if _, err := os.Stat(filepath.Join(path, "config.json")); err == nil {
+				continue
+			}
+		}
+		orphaned = append(orphaned, path)
+	}
+	return orphaned, nil
+}
+
+// RemovePaths removes the files and directories, and returns those removed
+// before any error.
+func RemovePaths(paths []string) ([]string, error) {
+	for i, p := range paths {
+		if err := os.RemoveAll(p); err != nil {
+			return paths[:i], errors.Wrapf(err, "Error removing %s", p)
+		}
+	}
+	return paths, nil
+}
+
+// CompletedPods returns the pods of all namespaces which ran to completion,
+// as namespace/name.
+func CompletedPods(pods corev1.PodsGetter) ([]string, error) {
+	list, err := pods.Pods(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing pods")
+	}
+	var completed []string
+	for _, p := range list.Items {
+		if p.Status.Phase == v1.PodSucceeded {
+			completed = append(completed, p.Namespace+"/"+p.Name)
+		}
+	}
+	return completed, nil
+}
+
+// CompletedJobs returns the jobs of all namespaces which completed, as
+// namespace/name.
+func CompletedJobs(jobs batchv1.JobsGetter) ([]string, error) {
+	list, err := jobs.Jobs(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing jobs")
+	}
+	var completed []string
+	for _, j := range list.Items {
+		for _, c := range j.Status.Conditions {
+			if c.Type == batch.JobComplete && c.Status == v1.ConditionTrue {
+				completed = append(completed, j.Namespace+"/"+j.Name)
+				break
+			}
+		}
+	}
+	return completed, nil
+}
+
+// DeletePods deletes the pods, given as namespace/name.
+func DeletePods(pods corev1.PodsGetter, names []string) error {
+	for _, n := range names {
+		ns, name := splitName(n)
+		if err := pods.Pods(ns).Delete(name, &v1.DeleteOptions{}); err != nil {
+			return errors.Wrapf(err, "Error deleting pod %s", n)
+		}
+	}
+	return nil
+}
+
+// DeleteJobs deletes the jobs, given as namespace/name.
+func DeleteJobs(jobs batchv1.JobsGetter, names []string) error {
+	for _, n := range names {
+		ns, name := splitName(n)
+		if err := jobs.Jobs(ns).Delete(name, &v1.DeleteOptions{}); err != nil {
+			return errors.Wrapf(err, "Error deleting job %s", n)
+		}
+	}
+	return nil
+}
+
+func splitName(n string) (namespace, name string) {
+	parts := strings.SplitN(n, "/", 2)
+	if len(parts) == 1 {
+		return v1.NamespaceDefault, n
+	}
+	return parts[0], parts[1]
+}
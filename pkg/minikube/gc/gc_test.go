@@ -0,0 +1,152 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func touch(t *testing.T, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte{}, 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestStaleCacheFiles(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	current := constants.MakeMiniPath("cache", "iso", "minikube-v1.0.7.iso")
+	old := constants.MakeMiniPath("cache", "iso", "minikube-v1.0.6.iso")
+	localkube := constants.MakeMiniPath("cache", "localkube", "localkube-v1.5.3")
+	oldLocalkube := constants.MakeMiniPath("cache", "localkube", "localkube-v1.5.2")
+	for _, f := range []string{current, old, localkube, oldLocalkube} {
+		touch(t, f)
+	}
+
+	stale, err := StaleCacheFiles([]string{current, localkube})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{old, oldLocalkube}; !reflect.DeepEqual(stale, expected) {
+		t.Errorf("Expected stale files %v, got %v", expected, stale)
+	}
+	removed, err := RemovePaths(stale)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Expected the stale files to be removed, got %v", removed)
+	}
+	if _, err := os.Stat(current); err != nil {
+		t.Errorf("Expected the current ISO to be kept: %s", err)
+	}
+}
+
+func TestOrphanedMachines(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	touch(t, constants.MakeMiniPath("machines", constants.MachineName, "config.json"))
+	touch(t, constants.MakeMiniPath("machines", "server.pem"))
+	touch(t, constants.MakeMiniPath("machines", "minikube-old", "disk.vmdk"))
+	touch(t, constants.MakeMiniPath("machines", constants.NodeMachineName("m02"), "config.json"))
+
+	orphaned, err := OrphanedMachines()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{constants.MakeMiniPath("machines", "minikube-old")}; !reflect.DeepEqual(orphaned, expected) {
+		t.Errorf("Expected orphaned machines %v, got %v", expected, orphaned)
+	}
+
+	os.Remove(constants.MakeMiniPath("machines", constants.MachineName, "config.json"))
+	orphaned, err = OrphanedMachines()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(orphaned) != 2 {
+		t.Errorf("Expected the machine without config to be orphaned, got %v", orphaned)
+	}
+}
+
+type mockPodsGetter struct {
+	pods    *mockPods
+	deleted []string
+}
+
+func (m *mockPodsGetter) Pods(namespace string) corev1.PodInterface {
+	m.pods.namespace = namespace
+	m.pods.getter = m
+	return m.pods
+}
+
+type mockPods struct {
+	fake.FakePods
+	list      *v1.PodList
+	namespace string
+	getter    *mockPodsGetter
+}
+
+func (m *mockPods) List(opts v1.ListOptions) (*v1.PodList, error) {
+	return m.list, nil
+}
+
+func (m *mockPods) Delete(name string, opts *v1.DeleteOptions) error {
+	m.getter.deleted = append(m.getter.deleted, m.namespace+"/"+name)
+	return nil
+}
+
+func TestCompletedPods(t *testing.T) {
+	pod := func(ns, name string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{ObjectMeta: v1.ObjectMeta{Namespace: ns, Name: name}, Status: v1.PodStatus{Phase: phase}}
+	}
+	getter := &mockPodsGetter{pods: &mockPods{list: &v1.PodList{Items: []v1.Pod{
+		pod("default", "web", v1.PodRunning),
+		pod("default", "migrate", v1.PodSucceeded),
+		pod("kube-system", "crashed", v1.PodFailed),
+		pod("batch", "report", v1.PodSucceeded),
+	}}}}
+
+	completed, err := CompletedPods(getter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []string{"default/migrate", "batch/report"}
+	if !reflect.DeepEqual(completed, expected) {
+		t.Fatalf("Expected completed pods %v, got %v", expected, completed)
+	}
+	if err := DeletePods(getter, completed); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(getter.deleted, expected) {
+		t.Errorf("Expected pods %v to be deleted, got %v", expected, getter.deleted)
+	}
+}
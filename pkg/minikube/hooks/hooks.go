@@ -0,0 +1,67 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks runs the commands users configure around the lifecycle
+// commands of minikube, e.g. to seed the cluster with data after start or to
+// clean up resources outside of it after delete.
+package hooks
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// Event is a point of the lifecycle of the cluster a hook runs at.
+type Event string
+
+const (
+	PreStart   Event = "pre-start"
+	PostStart  Event = "post-start"
+	PreStop    Event = "pre-stop"
+	PostDelete Event = "post-delete"
+)
+
+// Setting returns the config setting of the command of the hook of the
+// event, like hook-pre-start.
+func (e Event) Setting() string {
+	return "hook-" + string(e)
+}
+
+// shell returns the command running the command line with the shell of the
+// host.
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("/bin/sh", "-c", command)
+}
+
+// Run runs the command of the hook of the event with the shell, with the
+// environment of minikube, the variables and MINIKUBE_HOOK set to the event.
+func Run(e Event, command string, vars []string, stdout, stderr io.Writer) error {
+	cmd := shell(command)
+	cmd.Env = append(append(os.Environ(), vars...), "MINIKUBE_HOOK="+string(e))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "The %s hook %q failed", e, command)
+	}
+	return nil
+}
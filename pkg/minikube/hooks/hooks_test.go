@@ -0,0 +1,50 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The hooks of the test are shell commands")
+	}
+	var stdout, stderr bytes.Buffer
+	err := Run(PostStart, "echo $MINIKUBE_HOOK $MINIKUBE_PROFILE; echo oops >&2", []string{"MINIKUBE_PROFILE=dev"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Unexpected error running the hook: %s", err)
+	}
+	if stdout.String() != "post-start dev\n" {
+		t.Errorf("Expected the event and the profile in the environment, got %q", stdout.String())
+	}
+	if stderr.String() != "oops\n" {
+		t.Errorf("Expected the errors of the hook, got %q", stderr.String())
+	}
+
+	if err := Run(PreStop, "exit 3", nil, &stdout, &stderr); err == nil {
+		t.Errorf("Expected an error for a failing hook")
+	}
+}
+
+func TestSetting(t *testing.T) {
+	if s := PostDelete.Setting(); s != "hook-post-delete" {
+		t.Errorf("Expected the setting hook-post-delete, got %s", s)
+	}
+}
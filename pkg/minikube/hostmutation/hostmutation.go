@@ -0,0 +1,60 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostmutation lists the changes minikube can make to the
+// configuration of the host, outside of its own directories, which the
+// strict mode forbids for locked-down machines.
+package hostmutation
+
+import "fmt"
+
+// Setting forbids the mutations of the host when true, e.g. for a profile.
+const Setting = "no-host-mutation"
+
+// Mutation is a part of the configuration of the host a feature changes.
+type Mutation string
+
+const (
+	HostsFile      Mutation = "/etc/hosts"
+	RoutingTable   Mutation = "the routing table"
+	KubectlContext Mutation = "the current kubectl context"
+	TrustStore     Mutation = "the system trust store"
+	SystemServices Mutation = "the systemd services"
+)
+
+// All are the mutations the strict mode forbids.
+var All = []Mutation{HostsFile, RoutingTable, KubectlContext, TrustStore, SystemServices}
+
+// ForbiddenError is returned for a feature needing a mutation of the host
+// in the strict mode.
+type ForbiddenError struct {
+	Mutation Mutation
+	Feature  string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("%s needs to change %s, which %s forbids. Run \"minikube config set %s false\" to allow it",
+		e.Feature, e.Mutation, Setting, Setting)
+}
+
+// Check returns a ForbiddenError if strict, for the feature needing the
+// mutation.
+func Check(strict bool, m Mutation, feature string) error {
+	if !strict {
+		return nil
+	}
+	return &ForbiddenError{Mutation: m, Feature: feature}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostmutation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	if err := Check(false, HostsFile, "ingress-dns"); err != nil {
+		t.Errorf("Expected no error outside of the strict mode, got %s", err)
+	}
+	err := Check(true, HostsFile, "ingress-dns")
+	if err == nil {
+		t.Fatalf("Expected an error in the strict mode")
+	}
+	if _, ok := err.(*ForbiddenError); !ok {
+		t.Errorf("Expected a ForbiddenError, got %T", err)
+	}
+	for _, expected := range []string{"ingress-dns", "/etc/hosts", "minikube config set no-host-mutation false"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to contain %q: %s", expected, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integrations writes the files other tools, like IDEs, Lens,
+// Telepresence or Tilt, use to connect to the cluster, rendered from
+// templates after each start.
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Setting is the config setting listing the integrations written after
+// start, which can differ per profile.
+const Setting = "integrations"
+
+// The files of an integration kept in its directory under Dir.
+const (
+	pathFile     = "path"
+	templateFile = "template"
+)
+
+// Connection is what the paths and templates of the integrations are
+// rendered with.
+type Connection struct {
+	// Profile is the active config profile, empty for the global config
+	Profile string
+	// Context is the kubeconfig context of the cluster
+	Context string
+	// Server is the URL of the apiserver
+	Server string
+	// Kubeconfig is the kubeconfig file holding the context
+	Kubeconfig           string
+	CertificateAuthority string
+	ClientCertificate    string
+	ClientKey            string
+	// Home is the home directory of the user
+	Home string
+}
+
+// Integration renders the file a tool connects to the cluster with.
+type Integration struct {
+	Name string
+	// Path is the template of the path of the file
+	Path string
+	// Template is the template of the contents of the file
+	Template string
+	// Mode is the permissions of the file
+	Mode os.FileMode
+}
+
+// Builtins are the integrations shipped with minikube, which an integration
+// of the same name under Dir replaces.
+var Builtins = map[string]Integration{
+	"ide": {
+		Name: "ide",
+		Path: "{{.Home}}/.kube/{{.Context}}.env",
+		Template: `KUBECONFIG={{.Kubeconfig}}
+KUBE_CONTEXT={{.Context}}
+`,
+		Mode: 0600,
+	},
+	"lens": {
+		Name: "lens",
+		Path: "{{.Home}}/.kube/lens/{{.Context}}.yaml",
+		Template: `apiVersion: v1
+kind: Config
+clusters:
+- name: {{.Context}}
+  cluster:
+    server: {{.Server}}
+    certificate-authority: {{.CertificateAuthority}}
+users:
+- name: {{.Context}}
+  user:
+    client-certificate: {{.ClientCertificate}}
+    client-key: {{.ClientKey}}
+contexts:
+- name: {{.Context}}
+  context:
+    cluster: {{.Context}}
+    user: {{.Context}}
+current-context: {{.Context}}
+`,
+		Mode: 0600,
+	},
+	"telepresence": {
+		Name: "telepresence",
+		Path: "{{.Home}}/.kube/telepresence-{{.Context}}.sh",
+		Template: `#!/bin/sh
+# Runs telepresence against the {{.Context}} cluster, whatever the current context
+export KUBECONFIG={{.Kubeconfig}}
+exec telepresence --context {{.Context}} "$@"
+`,
+		Mode: 0700,
+	},
+	"tilt": {
+		Name: "tilt",
+		Path: "{{.Home}}/.tilt-dev/{{.Context}}.tiltfile",
+		Template: `# Load with: load_dynamic('{{.Home}}/.tilt-dev/{{.Context}}.tiltfile')
+allow_k8s_contexts('{{.Context}}')
+`,
+		Mode: 0600,
+	},
+}
+
+// Dir returns the directory of the user integrations: one directory per
+// integration, holding the template of the path of its file in "path", and
+// the template of its contents in "template".
+func Dir() string {
+	return constants.MakeMiniPath("integrations")
+}
+
+// Load returns the integration of the name, from Dir or the builtins.
+func Load(name string) (Integration, error) {
+	dir := filepath.Join(Dir(), name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if i, ok := Builtins[name]; ok {
+			return i, nil
+		}
+		return Integration{}, fmt.Errorf("Unknown integration %q, add it as %s", name, dir)
+	}
+	path, err := ioutil.ReadFile(filepath.Join(dir, pathFile))
+	if err != nil {
+		return Integration{}, errors.Wrapf(err, "Error reading the path of integration %s", name)
+	}
+	tmpl, err := ioutil.ReadFile(filepath.Join(dir, templateFile))
+	if err != nil {
+		return Integration{}, errors.Wrapf(err, "Error reading the template of integration %s", name)
+	}
+	return Integration{Name: name, Path: strings.TrimSpace(string(path)), Template: string(tmpl), Mode: 0600}, nil
+}
+
+// List returns the names of the builtin and user integrations, sorted.
+func List() ([]string, error) {
+	names := map[string]bool{}
+	for name := range Builtins {
+		names[name] = true
+	}
+	entries, err := ioutil.ReadDir(Dir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "Error listing the integrations")
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			names[e.Name()] = true
+		}
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// Enabled returns the names of the integrations in the value of the setting,
+// separated by commas.
+func Enabled(setting string) []string {
+	var names []string
+	for _, name := range strings.Split(setting, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Render returns the path and the contents of the file of the integration.
+func (i Integration) Render(c Connection) (string, []byte, error) {
+	path, err := render(i.Name+" path", i.Path, c)
+	if err != nil {
+		return "", nil, err
+	}
+	contents, err := render(i.Name, i.Template, c)
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.FromSlash(string(path)), contents, nil
+}
+
+// Write renders the file of the integration and writes it, returning its path.
+func (i Integration) Write(c Connection) (string, error) {
+	path, contents, err := i.Render(c)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", errors.Wrapf(err, "Error creating the directory of %s", path)
+	}
+	if err := ioutil.WriteFile(path, contents, i.Mode); err != nil {
+		return "", errors.Wrapf(err, "Error writing %s", path)
+	}
+	// WriteFile keeps the permissions of an existing file
+	if err := os.Chmod(path, i.Mode); err != nil {
+		return "", errors.Wrapf(err, "Error setting the permissions of %s", path)
+	}
+	return path, nil
+}
+
+func render(name, text string, c Connection) ([]byte, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the template of integration %s", name)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, c); err != nil {
+		return nil, errors.Wrapf(err, "Error rendering the template of integration %s", name)
+	}
+	return b.Bytes(), nil
+}
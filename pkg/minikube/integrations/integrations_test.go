@@ -0,0 +1,144 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func testConnection(home string) Connection {
+	return Connection{
+		Context:              "minikube",
+		Server:               "https://192.168.99.100:8443",
+		Kubeconfig:           filepath.Join(home, ".kube", "config"),
+		CertificateAuthority: "/certs/ca.crt",
+		ClientCertificate:    "/certs/apiserver.crt",
+		ClientKey:            "/certs/apiserver.key",
+		Home:                 home,
+	}
+}
+
+func TestWriteBuiltins(t *testing.T) {
+	home, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(home)
+	c := testConnection(home)
+
+	for name, i := range Builtins {
+		path, err := i.Write(c)
+		if err != nil {
+			t.Errorf("Unexpected error writing %s: %s", name, err)
+			continue
+		}
+		if !strings.HasPrefix(path, home) || !strings.Contains(path, "minikube") {
+			t.Errorf("Unexpected path %s for %s", path, name)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("Expected %s to be written: %s", path, err)
+			continue
+		}
+		if fi.Mode().Perm() != i.Mode {
+			t.Errorf("Expected %s to have mode %v, got %v", path, i.Mode, fi.Mode().Perm())
+		}
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(home, ".kube", "lens", "minikube.yaml"))
+	if err != nil {
+		t.Fatalf("Error reading the lens kubeconfig: %s", err)
+	}
+	if !strings.Contains(string(contents), "server: https://192.168.99.100:8443") {
+		t.Errorf("Expected the server in the lens kubeconfig, got:\n%s", contents)
+	}
+}
+
+func TestLoadUserIntegration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv(constants.MinikubeHome, dir)
+	defer os.Unsetenv(constants.MinikubeHome)
+
+	custom := filepath.Join(Dir(), "lens")
+	if err := os.MkdirAll(custom, 0755); err != nil {
+		t.Fatalf("Error creating %s: %s", custom, err)
+	}
+	ioutil.WriteFile(filepath.Join(custom, "path"), []byte("{{.Home}}/custom-{{.Context}}\n"), 0644)
+	ioutil.WriteFile(filepath.Join(custom, "template"), []byte("server={{.Server}}\n"), 0644)
+	if err := os.MkdirAll(filepath.Join(Dir(), "vscode"), 0755); err != nil {
+		t.Fatalf("Error creating the vscode integration: %s", err)
+	}
+
+	i, err := Load("lens")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	path, contents, err := i.Render(testConnection("/home/user"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if path != filepath.FromSlash("/home/user/custom-minikube") || string(contents) != "server=https://192.168.99.100:8443\n" {
+		t.Errorf("Expected the user integration to replace the builtin, got %s:\n%s", path, contents)
+	}
+
+	if _, err := Load("vscode"); err == nil {
+		t.Errorf("Expected an error for an integration without a template")
+	}
+	if _, err := Load("missing"); err == nil {
+		t.Errorf("Expected an error for an unknown integration")
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []string{"ide", "lens", "telepresence", "tilt", "vscode"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	for _, i := range []Integration{
+		{Name: "unparsable", Path: "/tmp/x", Template: "{{.Server"},
+		{Name: "unknown field", Path: "/tmp/{{.Cluster}}", Template: ""},
+	} {
+		if _, _, err := i.Render(testConnection("/home/user")); err == nil {
+			t.Errorf("Expected an error for the %s integration", i.Name)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if names := Enabled(" lens, tilt,,"); !reflect.DeepEqual(names, []string{"lens", "tilt"}) {
+		t.Errorf("Unexpected integrations %v", names)
+	}
+	if names := Enabled(""); len(names) != 0 {
+		t.Errorf("Expected no integrations, got %v", names)
+	}
+}
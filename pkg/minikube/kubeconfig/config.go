@@ -48,6 +48,10 @@ type KubeConfigSetup struct {
 	// Should the current context be kept when setting up this one
 	KeepContext bool
 
+	// EmbedCerts embeds the contents of the certs and the key rather than
+	// their paths, for the kubeconfig to be used on other machines
+	EmbedCerts bool
+
 	// kubeConfigFile is the path where the kube config is stored
 	// Only access this with atomic ops
 	kubeConfigFile atomic.Value
@@ -76,14 +80,25 @@ func SetupKubeConfig(cfg *KubeConfigSetup) error {
 	clusterName := cfg.ClusterName
 	cluster := api.NewCluster()
 	cluster.Server = cfg.ClusterServerAddress
-	cluster.CertificateAuthority = cfg.CertificateAuthority
-	config.Clusters[clusterName] = cluster
-
 	// user
 	userName := cfg.ClusterName
 	user := api.NewAuthInfo()
-	user.ClientCertificate = cfg.ClientCertificate
-	user.ClientKey = cfg.ClientKey
+	if cfg.EmbedCerts {
+		if cluster.CertificateAuthorityData, err = ioutil.ReadFile(cfg.CertificateAuthority); err != nil {
+			return errors.Wrap(err, "Error reading the certificate authority")
+		}
+		if user.ClientCertificateData, err = ioutil.ReadFile(cfg.ClientCertificate); err != nil {
+			return errors.Wrap(err, "Error reading the client certificate")
+		}
+		if user.ClientKeyData, err = ioutil.ReadFile(cfg.ClientKey); err != nil {
+			return errors.Wrap(err, "Error reading the client key")
+		}
+	} else {
+		cluster.CertificateAuthority = cfg.CertificateAuthority
+		user.ClientCertificate = cfg.ClientCertificate
+		user.ClientKey = cfg.ClientKey
+	}
+	config.Clusters[clusterName] = cluster
 	config.AuthInfos[userName] = user
 
 	// context
@@ -91,6 +106,10 @@ func SetupKubeConfig(cfg *KubeConfigSetup) error {
 	context := api.NewContext()
 	context.Cluster = cfg.ClusterName
 	context.AuthInfo = userName
+	// The default namespace set with minikube ns is kept
+	if existing, ok := config.Contexts[contextName]; ok {
+		context.Namespace = existing.Namespace
+	}
 	config.Contexts[contextName] = context
 
 	// Only set current context to minikube if the user has not used the keepContext flag
@@ -156,10 +175,13 @@ func WriteConfig(config *api.Config, filename string) error {
 		}
 	}
 
-	// write with restricted permissions
+	// write with restricted permissions, also when the file exists
 	if err := ioutil.WriteFile(filename, data, 0600); err != nil {
 		return errors.Wrapf(err, "Error writing file %s", filename)
 	}
+	if err := os.Chmod(filename, 0600); err != nil {
+		return errors.Wrapf(err, "Error restricting the permissions of %s", filename)
+	}
 	return nil
 }
 
@@ -128,6 +128,47 @@ func TestSetupKubeConfig(t *testing.T) {
 	}
 }
 
+func TestSetupKubeConfigEmbedCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"ca.crt", "apiserver.crt", "apiserver.key"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", f, err)
+		}
+	}
+	cfg := &KubeConfigSetup{
+		ClusterName:          "minikube",
+		ClusterServerAddress: "https://192.168.1.23:8443",
+		ClientCertificate:    filepath.Join(dir, "apiserver.crt"),
+		ClientKey:            filepath.Join(dir, "apiserver.key"),
+		CertificateAuthority: filepath.Join(dir, "ca.crt"),
+		EmbedCerts:           true,
+	}
+	cfg.SetKubeConfigFile(filepath.Join(dir, "kubeconfig"))
+	if err := SetupKubeConfig(cfg); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	config, err := ReadConfigOrNew(cfg.GetKubeConfigFile())
+	if err != nil {
+		t.Fatalf("Error reading kubeconfig file: %s", err)
+	}
+	cluster, user := config.Clusters["minikube"], config.AuthInfos["minikube"]
+	if cluster.CertificateAuthority != "" || user.ClientCertificate != "" || user.ClientKey != "" {
+		t.Errorf("Expected no paths in the kubeconfig, got %+v and %+v", cluster, user)
+	}
+	if string(cluster.CertificateAuthorityData) != "ca.crt" || string(user.ClientCertificateData) != "apiserver.crt" || string(user.ClientKeyData) != "apiserver.key" {
+		t.Errorf("Expected the certs to be embedded, got %+v and %+v", cluster, user)
+	}
+
+	cfg.ClientKey = filepath.Join(dir, "missing.key")
+	if err := SetupKubeConfig(cfg); err == nil {
+		t.Errorf("Expected an error for a missing key")
+	}
+}
+
 func TestEmptyConfig(t *testing.T) {
 	tmp := tempFile(t, []byte{})
 	defer os.Remove(tmp)
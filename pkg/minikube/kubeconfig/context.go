@@ -0,0 +1,72 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd/api"
+)
+
+// DefaultNamespace is the namespace of a context which doesn't set one
+const DefaultNamespace = "default"
+
+func readContext(filename, context string) (*api.Config, *api.Context, error) {
+	config, err := ReadConfigOrNew(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, ok := config.Contexts[context]
+	if !ok {
+		return nil, nil, errors.Errorf("There is no context %s in %s", context, filename)
+	}
+	return config, c, nil
+}
+
+// Namespace returns the default namespace of the context, of the kubectl
+// commands run in it without --namespace.
+func Namespace(filename, context string) (string, error) {
+	_, c, err := readContext(filename, context)
+	if err != nil {
+		return "", err
+	}
+	if c.Namespace == "" {
+		return DefaultNamespace, nil
+	}
+	return c.Namespace, nil
+}
+
+// SetNamespace sets the default namespace of the context.
+func SetNamespace(filename, context, namespace string) error {
+	config, c, err := readContext(filename, context)
+	if err != nil {
+		return err
+	}
+	c.Namespace = namespace
+	return WriteConfig(config, filename)
+}
+
+// UseContext makes the context the current one, returning the one it
+// replaces.
+func UseContext(filename, context string) (string, error) {
+	config, _, err := readContext(filename, context)
+	if err != nil {
+		return "", err
+	}
+	previous := config.CurrentContext
+	config.CurrentContext = context
+	return previous, WriteConfig(config, filename)
+}
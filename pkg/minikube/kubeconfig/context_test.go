@@ -0,0 +1,92 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContextNamespace(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	filename := filepath.Join(tmpDir, "kubeconfig")
+	if err := ioutil.WriteFile(filename, fakeKubeCfg, 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if ns, err := Namespace(filename, "la-croix"); err != nil || ns != DefaultNamespace {
+		t.Errorf("Expected the default namespace, got %s, %v", ns, err)
+	}
+	if err := SetNamespace(filename, "la-croix", "dev"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ns, err := Namespace(filename, "la-croix"); err != nil || ns != "dev" {
+		t.Errorf("Expected the namespace dev, got %s, %v", ns, err)
+	}
+	if err := SetNamespace(filename, "minikube", "dev"); err == nil {
+		t.Errorf("Expected an error for a context which is not in the kubeconfig")
+	}
+
+	// Setting up the context again on start keeps its namespace
+	setup := &KubeConfigSetup{ClusterName: "la-croix", ClusterServerAddress: "192.168.1.2:8443"}
+	setup.SetKubeConfigFile(filename)
+	if err := SetupKubeConfig(setup); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ns, err := Namespace(filename, "la-croix"); err != nil || ns != "dev" {
+		t.Errorf("Expected the namespace dev to be kept, got %s, %v", ns, err)
+	}
+}
+
+func TestUseContext(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	filename := filepath.Join(tmpDir, "kubeconfig")
+	setup := &KubeConfigSetup{ClusterName: "minikube", ClusterServerAddress: "192.168.99.100:8443"}
+	setup.SetKubeConfigFile(filename)
+	if err := ioutil.WriteFile(filename, fakeKubeCfg, 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	setup.KeepContext = true
+	if err := SetupKubeConfig(setup); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	previous, err := UseContext(filename, "minikube")
+	if err != nil || previous != "la-croix" {
+		t.Errorf("Expected to switch from la-croix, got %s, %v", previous, err)
+	}
+	config, err := ReadConfigOrNew(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if config.CurrentContext != "minikube" {
+		t.Errorf("Expected the current context minikube, got %s", config.CurrentContext)
+	}
+	if _, err := UseContext(filename, "kind"); err == nil {
+		t.Errorf("Expected an error for a context which is not in the kubeconfig")
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubectl installs the kubectl of the Kubernetes version of each
+// profile, and the shims running the one of a profile, so that the client and
+// the server of a cluster are never skewed.
+package kubectl
+
+import (
+	"crypto"
+	// The releases publish the sha1 of kubectl
+	_ "crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	download "github.com/jimmidyson/go-download"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// ReleaseURLPrefix is where the binaries of the Kubernetes releases are
+// published.
+var ReleaseURLPrefix = "https://storage.googleapis.com/kubernetes-release/release/"
+
+// DefaultProfile is the name of the shims of the default profile.
+const DefaultProfile = constants.MachineName
+
+var releaseVersion = regexp.MustCompile(`^v\d+\.\d+\.\d+(-(alpha|beta|rc)\.\d+)?$`)
+
+// Release returns the version of kubectl released for the Kubernetes
+// version, the one without build metadata.
+func Release(version string) string {
+	return strings.SplitN(version, "+", 2)[0]
+}
+
+// ValidateVersion checks that kubectl is released for the version: a localkube
+// URI has none.
+func ValidateVersion(version string) error {
+	if !releaseVersion.MatchString(Release(version)) {
+		return errors.Errorf("%q is not a released Kubernetes version like %s, set the version of kubectl with --kubernetes-version", version, constants.DefaultKubernetesVersion)
+	}
+	return nil
+}
+
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "kubectl.exe"
+	}
+	return "kubectl"
+}
+
+// URL returns where the kubectl of the version is published for the platform.
+func URL(version, goos, goarch string) string {
+	return fmt.Sprintf("%s%s/bin/%s/%s/%s", ReleaseURLPrefix, version, goos, goarch, binaryName(goos))
+}
+
+// BinaryPath returns where the kubectl of the version is installed.
+func BinaryPath(version string) string {
+	return constants.MakeMiniPath("bin", "kubectl", version, binaryName(runtime.GOOS))
+}
+
+// ShimDir returns the directory of the shims of the profile, added to the
+// PATH to use its kubectl.
+func ShimDir(profile string) string {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	return constants.MakeMiniPath("bin", "profiles", profile)
+}
+
+// clientVersion returns the output of kubectl version --client, it is
+// replaced in tests.
+var clientVersion = func(path string) (string, error) {
+	out, err := exec.Command(path, "version", "--client").CombinedOutput()
+	return string(out), err
+}
+
+// Install downloads the kubectl of the version, unless it is installed
+// already, checks that it runs as that version, and returns its path.
+func Install(version string) (string, error) {
+	if err := ValidateVersion(version); err != nil {
+		return "", err
+	}
+	version = Release(version)
+	path := BinaryPath(version)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		url := URL(version, runtime.GOOS, runtime.GOARCH)
+		opts := download.FileOptions{
+			Mkdirs: download.MkdirAll,
+			Options: download.Options{
+				Checksum:     url + ".sha1",
+				ChecksumHash: crypto.SHA1,
+				ProgressBars: &download.ProgressBarOptions{
+					MaxWidth: 80,
+				},
+			},
+		}
+		fmt.Printf("Downloading kubectl %s\n", version)
+		if err := download.ToFile(url, path, opts); err != nil {
+			return "", errors.Wrapf(err, "Error downloading kubectl %s", version)
+		}
+		if err := os.Chmod(path, 0755); err != nil {
+			return "", err
+		}
+	}
+	out, err := clientVersion(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error running %s: %s", path, out)
+	}
+	if !strings.Contains(out, fmt.Sprintf("GitVersion:%q", version)) {
+		os.Remove(path)
+		return "", errors.Errorf("%s is not kubectl %s, it was removed: %s", path, version, strings.TrimSpace(out))
+	}
+	return path, nil
+}
+
+// shim returns the name and the content of the shim running the binary.
+func shim(goos, binary string) (string, string) {
+	if goos == "windows" {
+		return "kubectl.cmd", fmt.Sprintf("@\"%s\" %%*\r\n", binary)
+	}
+	return "kubectl", fmt.Sprintf("#!/bin/sh\nexec '%s' \"$@\"\n", strings.Replace(binary, "'", `'\''`, -1))
+}
+
+// WriteShim points the shim of the profile to the kubectl of the version,
+// and returns the path of the shim.
+func WriteShim(profile, version string) (string, error) {
+	dir := ShimDir(profile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "Error creating the directory of the shims")
+	}
+	name, content := shim(runtime.GOOS, BinaryPath(Release(version)))
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0755); err != nil {
+		return "", errors.Wrap(err, "Error writing the shim")
+	}
+	return path, nil
+}
+
+// ShimVersion returns the version of kubectl the shim of the profile runs,
+// or an empty string if the profile has no shim.
+func ShimVersion(profile string) (string, error) {
+	name, _ := shim(runtime.GOOS, "")
+	content, err := ioutil.ReadFile(filepath.Join(ShimDir(profile), name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	binaries := constants.MakeMiniPath("bin", "kubectl") + string(filepath.Separator)
+	i := strings.Index(string(content), binaries)
+	if i < 0 {
+		return "", errors.Errorf("The shim of the profile does not run a kubectl installed by minikube")
+	}
+	version := string(content[i+len(binaries):])
+	return version[:strings.IndexRune(version, filepath.Separator)], nil
+}
+
+// PathWith returns the PATH with the directory first, once however often it
+// is applied.
+func PathWith(path, dir string) string {
+	return strings.Join(append([]string{dir}, splitPath(PathWithout(path, dir))...), string(os.PathListSeparator))
+}
+
+// PathWithout returns the PATH without the directory.
+func PathWithout(path, dir string) string {
+	var kept []string
+	for _, d := range splitPath(path) {
+		if filepath.Clean(d) != filepath.Clean(dir) {
+			kept = append(kept, d)
+		}
+	}
+	return strings.Join(kept, string(os.PathListSeparator))
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return filepath.SplitList(path)
+}
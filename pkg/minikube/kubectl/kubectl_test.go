@@ -0,0 +1,141 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestValidateVersion(t *testing.T) {
+	for version, valid := range map[string]bool{
+		"v1.6.0":          true,
+		"v1.7.0-beta.2":   true,
+		"v1.5.3+5c7e78a2": true,
+		"1.6.0":           false,
+		"https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64": false,
+	} {
+		if err := ValidateVersion(version); (err == nil) != valid {
+			t.Errorf("Expected %s to be valid: %t, got %v", version, valid, err)
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	expected := "https://storage.googleapis.com/kubernetes-release/release/v1.6.0/bin/windows/amd64/kubectl.exe"
+	if u := URL("v1.6.0", "windows", "amd64"); u != expected {
+		t.Errorf("Expected %s, got %s", expected, u)
+	}
+}
+
+func TestShim(t *testing.T) {
+	name, content := shim("linux", "/home/o'neil/.minikube/bin/kubectl/v1.6.0/kubectl")
+	if name != "kubectl" || content != "#!/bin/sh\nexec '/home/o'\\''neil/.minikube/bin/kubectl/v1.6.0/kubectl' \"$@\"\n" {
+		t.Errorf("Unexpected shim %s: %s", name, content)
+	}
+	name, content = shim("windows", `C:\Users\me\.minikube\bin\kubectl\v1.6.0\kubectl.exe`)
+	if name != "kubectl.cmd" || content != "@\"C:\\Users\\me\\.minikube\\bin\\kubectl\\v1.6.0\\kubectl.exe\" %*\r\n" {
+		t.Errorf("Unexpected shim %s: %s", name, content)
+	}
+}
+
+func TestPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	path := strings.Join([]string{"/usr/bin", "/shims", "/bin"}, sep)
+	if p := PathWith(path, "/shims/"); p != strings.Join([]string{"/shims/", "/usr/bin", "/bin"}, sep) {
+		t.Errorf("Unexpected PATH with the shims: %s", p)
+	}
+	if p := PathWithout(path, "/shims"); p != strings.Join([]string{"/usr/bin", "/bin"}, sep) {
+		t.Errorf("Unexpected PATH without the shims: %s", p)
+	}
+	if p := PathWith("", "/shims"); p != "/shims" {
+		t.Errorf("Unexpected PATH with the shims: %s", p)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %s", err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv(constants.MinikubeHome, dir)
+	defer os.Unsetenv(constants.MinikubeHome)
+
+	binary := []byte("kubectl")
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.6.0/bin/" + runtime.GOOS + "/" + runtime.GOARCH + "/" + binaryName(runtime.GOOS):
+			downloads++
+			w.Write(binary)
+		case "/v1.6.0/bin/" + runtime.GOOS + "/" + runtime.GOARCH + "/" + binaryName(runtime.GOOS) + ".sha1":
+			fmt.Fprintf(w, "%x", sha1.Sum(binary))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func(prefix string) { ReleaseURLPrefix = prefix }(ReleaseURLPrefix)
+	ReleaseURLPrefix = server.URL + "/"
+	reported := "v1.6.0"
+	defer func(f func(string) (string, error)) { clientVersion = f }(clientVersion)
+	clientVersion = func(string) (string, error) {
+		return fmt.Sprintf(`Client Version: version.Info{Major:"1", Minor:"6", GitVersion:%q}`, reported), nil
+	}
+
+	for i := 0; i < 2; i++ {
+		path, err := Install("v1.6.0")
+		if err != nil {
+			t.Fatalf("Unexpected error installing kubectl: %s", err)
+		}
+		if path != BinaryPath("v1.6.0") || downloads != 1 {
+			t.Errorf("Expected kubectl to be downloaded once to %s, got %s after %d downloads", BinaryPath("v1.6.0"), path, downloads)
+		}
+	}
+	if _, err := Install("v1.5.0"); err == nil {
+		t.Errorf("Expected an error installing a version which is not published")
+	}
+
+	if version, err := ShimVersion("dev"); err != nil || version != "" {
+		t.Errorf("Expected no shim, got %q, %v", version, err)
+	}
+	if _, err := WriteShim("dev", "v1.6.0"); err != nil {
+		t.Fatalf("Unexpected error writing the shim: %s", err)
+	}
+	if version, err := ShimVersion("dev"); err != nil || version != "v1.6.0" {
+		t.Errorf("Expected the shim to run v1.6.0, got %q, %v", version, err)
+	}
+
+	// A binary of another version is not kept
+	reported = "v1.5.3"
+	if _, err := Install("v1.6.0"); err == nil || !strings.Contains(err.Error(), "is not kubectl v1.6.0") {
+		t.Errorf("Expected an error for a binary of another version, got %v", err)
+	}
+	if _, err := os.Stat(BinaryPath("v1.6.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected the binary to be removed, got %v", err)
+	}
+}
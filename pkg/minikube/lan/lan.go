@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lan finds the addresses of the host on the local network, which
+// other machines reach it at.
+package lan
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// virtualPrefixes prefix the names of the interfaces created by the
+// hypervisors and container runtimes, whose networks only the host reaches
+var virtualPrefixes = []string{"vboxnet", "vmnet", "virbr", "docker", "br-", "veth", "bridge", "vEthernet", "tun", "tap", "utun", "wg", "cni", "flannel"}
+
+// Interface is a network interface of the host, with its addresses
+type Interface struct {
+	Name  string
+	Up    bool
+	Addrs []net.Addr
+}
+
+// HostIPs returns the IPv4 addresses of the host on the local network.
+func HostIPs() ([]net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing the network interfaces")
+	}
+	var interfaces []Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error getting the addresses of %s", iface.Name)
+		}
+		interfaces = append(interfaces, Interface{Name: iface.Name, Up: iface.Flags&net.FlagUp != 0, Addrs: addrs})
+	}
+	ips := FilterIPs(interfaces)
+	if len(ips) == 0 {
+		return nil, errors.New("The host has no address on the local network")
+	}
+	return ips, nil
+}
+
+// FilterIPs returns the IPv4 addresses of the interfaces which are up,
+// skipping the virtual interfaces and the loopback and link-local addresses.
+func FilterIPs(interfaces []Interface) []net.IP {
+	var ips []net.IP
+	for _, iface := range interfaces {
+		if !iface.Up || isVirtual(iface.Name) {
+			continue
+		}
+		for _, addr := range iface.Addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipnet.IP.To4()
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+func isVirtual(name string) bool {
+	for _, p := range virtualPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lan
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func addrs(cidrs ...string) []net.Addr {
+	var addrs []net.Addr
+	for _, c := range cidrs {
+		ip, ipnet, _ := net.ParseCIDR(c)
+		ipnet.IP = ip
+		addrs = append(addrs, ipnet)
+	}
+	return addrs
+}
+
+func TestFilterIPs(t *testing.T) {
+	interfaces := []Interface{
+		{Name: "eth0", Up: true, Addrs: addrs("192.168.1.23/24", "fe80::1/64")},
+		{Name: "wlan0", Up: false, Addrs: addrs("10.0.0.5/8")},
+		{Name: "en1", Up: true, Addrs: addrs("169.254.10.1/16", "10.1.2.3/16")},
+		{Name: "vboxnet0", Up: true, Addrs: addrs("192.168.99.1/24")},
+		{Name: "docker0", Up: true, Addrs: addrs("172.17.0.1/16")},
+		{Name: "virbr0", Up: true, Addrs: addrs("192.168.122.1/24")},
+		{Name: "vEthernet (Default Switch)", Up: true, Addrs: addrs("172.20.0.1/20")},
+	}
+	expected := []net.IP{net.ParseIP("192.168.1.23").To4(), net.ParseIP("10.1.2.3").To4()}
+	if actual := FilterIPs(interfaces); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loadbalancer forwards TCP connections to a set of backends, such
+// as the apiservers of the control planes of a cluster.
+package loadbalancer
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const dialTimeout = 3 * time.Second
+
+// LoadBalancer forwards each connection to the next backend, round robin,
+// skipping the backends which do not accept it.
+type LoadBalancer struct {
+	mu       sync.Mutex
+	backends []string
+	next     int
+}
+
+// New returns a load balancer for the backends, given as host:port.
+func New(backends []string) *LoadBalancer {
+	return &LoadBalancer{backends: backends}
+}
+
+// SetBackends replaces the backends, the open connections are kept.
+func (lb *LoadBalancer) SetBackends(backends []string) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.backends = backends
+}
+
+// Serve forwards the connections accepted by the listener until it is
+// closed.
+func (lb *LoadBalancer) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go lb.forward(conn)
+	}
+}
+
+// order returns the backends in the order they should be tried.
+func (lb *LoadBalancer) order() []string {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	n := len(lb.backends)
+	ordered := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		ordered = append(ordered, lb.backends[(lb.next+i)%n])
+	}
+	lb.next++
+	return ordered
+}
+
+func (lb *LoadBalancer) dial() (net.Conn, error) {
+	err := errors.New("No backends")
+	for _, b := range lb.order() {
+		var conn net.Conn
+		conn, err = net.DialTimeout("tcp", b, dialTimeout)
+		if err == nil {
+			return conn, nil
+		}
+		glog.Infof("Skipping backend %s: %s", b, err)
+	}
+	return nil, err
+}
+
+func (lb *LoadBalancer) forward(conn net.Conn) {
+	defer conn.Close()
+	backend, err := lb.dial()
+	if err != nil {
+		glog.Errorf("Error forwarding connection from %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+	defer backend.Close()
+
+	// Either side closing ends the connection
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(backend, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, backend)
+		done <- struct{}{}
+	}()
+	<-done
+}
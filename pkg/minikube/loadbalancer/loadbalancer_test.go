@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+)
+
+// serveName starts a backend which writes its name to each connection.
+func serveName(t *testing.T, name string) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(name))
+			conn.Close()
+		}
+	}()
+	return l
+}
+
+// closedAddress returns an address nothing listens on.
+func closedAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func TestLoadBalancer(t *testing.T) {
+	a := serveName(t, "a")
+	defer a.Close()
+	b := serveName(t, "b")
+	defer b.Close()
+
+	lb := New([]string{a.Addr().String(), closedAddress(t), b.Addr().String()})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer l.Close()
+	go lb.Serve(l)
+
+	get := func() string {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("Error connecting: %s", err)
+		}
+		defer conn.Close()
+		data, err := ioutil.ReadAll(conn)
+		if err != nil {
+			t.Fatalf("Error reading: %s", err)
+		}
+		return string(data)
+	}
+
+	// The closed backend is skipped for the next one
+	for i, expected := range []string{"a", "b", "b", "a"} {
+		if actual := get(); actual != expected {
+			t.Errorf("Connection %d: expected backend %s, got %s", i, expected, actual)
+		}
+	}
+
+	lb.SetBackends([]string{closedAddress(t)})
+	if actual := get(); actual != "" {
+		t.Errorf("Expected the connection to be closed without backends, got %s", actual)
+	}
+}
@@ -25,6 +25,9 @@ import (
 	"path/filepath"
 	"time"
 
+	"k8s.io/minikube/pkg/drivers/container"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	sshdriver "k8s.io/minikube/pkg/drivers/ssh"
 	"k8s.io/minikube/pkg/minikube/constants"
 
 	"github.com/docker/machine/drivers/virtualbox"
@@ -66,7 +69,45 @@ type rpcClientFactory struct{}
 func (*rpcClientFactory) NewClient(storePath, certsDir string) libmachine.API {
 	c := libmachine.NewClient(storePath, certsDir)
 	c.SSHClientType = ssh.Native
-	return c
+	return &rpcClient{
+		Client: c,
+		local:  (&localClientFactory{}).NewClient(storePath, certsDir).(*LocalClient),
+	}
+}
+
+// builtinDrivers are the drivers of minikube which docker-machine doesn't
+// know, so there is no plugin binary to run them: the RPC client runs them
+// in process, as the local client does.
+var builtinDrivers = map[string]bool{
+	"docker": true,
+	"podman": true,
+	"kvm2":   true,
+	"qemu":   true,
+	"ssh":    true,
+}
+
+// rpcClient runs the drivers as plugins, but the builtin ones.
+type rpcClient struct {
+	*libmachine.Client
+	local *LocalClient
+}
+
+func (api *rpcClient) NewHost(driverName string, rawDriver []byte) (*host.Host, error) {
+	if builtinDrivers[driverName] {
+		return api.local.NewHost(driverName, rawDriver)
+	}
+	return api.Client.NewHost(driverName, rawDriver)
+}
+
+func (api *rpcClient) Load(name string) (*host.Host, error) {
+	h, err := api.Filestore.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if builtinDrivers[h.DriverName] {
+		return api.local.Load(name)
+	}
+	return api.Client.Load(name)
 }
 
 var clientFactories = map[ClientType]clientFactory{
@@ -115,6 +156,30 @@ func getVirtualboxDriver(rawDriver []byte) (drivers.Driver, error) {
 	return driver, nil
 }
 
+func getSSHDriver(rawDriver []byte) (drivers.Driver, error) {
+	driver := sshdriver.NewDriver("", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling ssh driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
+func getQemuDriver(rawDriver []byte) (drivers.Driver, error) {
+	driver := qemu.NewDriver("", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling qemu driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
+func getContainerDriver(rawDriver []byte) (drivers.Driver, error) {
+	driver := container.NewDriver("", "", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling container driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
 func getDriverRPC(driverName string, rawDriver []byte) (drivers.Driver, error) {
 	return rpcdriver.NewRPCClientDriverFactory().NewRPCClientDriver(driverName, rawDriver)
 }
@@ -25,12 +25,18 @@ import (
 	"github.com/docker/machine/libmachine/drivers/plugin"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/hyperkit"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	sshdriver "k8s.io/minikube/pkg/drivers/ssh"
 )
 
 var driverMap = map[string]driverGetter{
 	"vmwarefusion": getVMWareFusionDriver,
 	"xhyve":        getXhyveDriver,
+	"hyperkit":     getHyperkitDriver,
 	"virtualbox":   getVirtualboxDriver,
+	"ssh":          getSSHDriver,
+	"qemu":         getQemuDriver,
 }
 
 func getVMWareFusionDriver(rawDriver []byte) (drivers.Driver, error) {
@@ -50,6 +56,14 @@ https://github.com/kubernetes/minikube/blob/master/DRIVERS.md#xhyve-driver
 `)
 }
 
+func getHyperkitDriver(rawDriver []byte) (drivers.Driver, error) {
+	driver := hyperkit.NewDriver("", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling hyperkit driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
 // StartDriver starts the desired machine driver if necessary.
 func registerDriver(driverName string) {
 	switch driverName {
@@ -57,6 +71,12 @@ func registerDriver(driverName string) {
 		plugin.RegisterDriver(virtualbox.NewDriver("", ""))
 	case "vmwarefusion":
 		plugin.RegisterDriver(vmwarefusion.NewDriver("", ""))
+	case "ssh":
+		plugin.RegisterDriver(sshdriver.NewDriver("", ""))
+	case "hyperkit":
+		plugin.RegisterDriver(hyperkit.NewDriver("", ""))
+	case "qemu":
+		plugin.RegisterDriver(qemu.NewDriver("", ""))
 	default:
 		glog.Exitf("Unsupported driver: %s\n", driverName)
 	}
@@ -17,16 +17,29 @@ limitations under the License.
 package machine
 
 import (
+	"encoding/json"
+
 	"github.com/docker/machine/drivers/virtualbox"
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/drivers/plugin"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/container"
+	"k8s.io/minikube/pkg/drivers/kvm2"
+	"k8s.io/minikube/pkg/drivers/none"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	sshdriver "k8s.io/minikube/pkg/drivers/ssh"
 )
 
 var driverMap = map[string]driverGetter{
 	"kvm":        getKVMDriver,
+	"kvm2":       getKVM2Driver,
 	"virtualbox": getVirtualboxDriver,
+	"docker":     getContainerDriver,
+	"podman":     getContainerDriver,
+	"none":       getNoneDriver,
+	"ssh":        getSSHDriver,
+	"qemu":       getQemuDriver,
 }
 
 func getKVMDriver(rawDriver []byte) (drivers.Driver, error) {
@@ -36,11 +49,37 @@ https://github.com/kubernetes/minikube/blob/master/DRIVERS.md#kvm-driver
 `)
 }
 
+func getKVM2Driver(rawDriver []byte) (drivers.Driver, error) {
+	driver := kvm2.NewDriver("", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling kvm2 driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
+func getNoneDriver(rawDriver []byte) (drivers.Driver, error) {
+	driver := none.NewDriver("", "")
+	if err := json.Unmarshal(rawDriver, driver); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling none driver %s", string(rawDriver))
+	}
+	return driver, nil
+}
+
 // StartDriver starts the desired machine driver if necessary.
 func registerDriver(driverName string) {
 	switch driverName {
 	case "virtualbox":
 		plugin.RegisterDriver(virtualbox.NewDriver("", ""))
+	case "kvm2":
+		plugin.RegisterDriver(kvm2.NewDriver("", ""))
+	case "docker", "podman":
+		plugin.RegisterDriver(container.NewDriver(driverName, "", ""))
+	case "none":
+		plugin.RegisterDriver(none.NewDriver("", ""))
+	case "ssh":
+		plugin.RegisterDriver(sshdriver.NewDriver("", ""))
+	case "qemu":
+		plugin.RegisterDriver(qemu.NewDriver("", ""))
 	default:
 		glog.Exitf("Unsupported driver: %s\n", driverName)
 	}
@@ -198,6 +198,23 @@ func TestNewAPIClient(t *testing.T) {
 	}
 }
 
+func TestRPCClientBuiltinDrivers(t *testing.T) {
+	c := clientFactories[ClientTypeRPC].NewClient("", "")
+
+	for _, driverName := range []string{"docker", "podman", "ssh"} {
+		if _, ok := driverMap[driverName]; !ok {
+			continue
+		}
+		h, err := c.NewHost(driverName, []byte(`{"MachineName": "minikube", "IPAddress": "192.168.1.20"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error creating a host with the builtin driver %s: %s", driverName, err)
+		}
+		if h.Driver.GetMachineName() != "minikube" {
+			t.Errorf("Expected the %s driver of minikube to run in process, got the machine %s", driverName, h.Driver.GetMachineName())
+		}
+	}
+}
+
 func makeTempDir() string {
 	tempDir, err := ioutil.TempDir("", "minipath")
 	if err != nil {
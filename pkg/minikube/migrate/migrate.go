@@ -0,0 +1,194 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrate moves the files of an existing ~/.minikube layout to the
+// config, cache and state directories they have been relocated to.
+package migrate
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Move is a file or directory to move out of the ~/.minikube layout
+type Move struct {
+	From string
+	To   string
+}
+
+// stateSkipped are the entries of the ~/.minikube directory which are not
+// part of the state.
+var stateSkipped = map[string]bool{
+	"config":   true,
+	"cache":    true,
+	"profiles": true,
+}
+
+// Plan returns the moves bringing the ~/.minikube layout of the kind to the
+// directory it is relocated to. Nothing is moved if it is not relocated.
+func Plan(kind constants.PathKind) ([]Move, error) {
+	to := constants.RelocatedPath(kind)
+	if to == "" {
+		return nil, nil
+	}
+	from := constants.LegacyPath(kind)
+	switch kind {
+	case constants.ConfigPath:
+		moves, err := entries(from, to, nil)
+		if err != nil {
+			return nil, err
+		}
+		profiles := filepath.Join(constants.LegacyPath(constants.StatePath), "profiles")
+		if _, err := os.Stat(profiles); err == nil {
+			moves = append(moves, Move{From: profiles, To: filepath.Join(to, "profiles")})
+		}
+		return moves, nil
+	case constants.StatePath:
+		machines, err := ioutil.ReadDir(filepath.Join(from, "machines"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "Error reading machines")
+		}
+		// The drivers and the kubeconfig refer to the files of a machine by
+		// their absolute path, which moving them would break.
+		if len(machines) > 0 {
+			return nil, errors.Errorf("%s holds machines, delete them with minikube delete before moving the state", from)
+		}
+		return entries(from, to, stateSkipped)
+	}
+	return entries(from, to, nil)
+}
+
+// entries returns the moves of the entries of the from directory, but those
+// skipped, into the to directory.
+func entries(from, to string, skipped map[string]bool) ([]Move, error) {
+	files, err := ioutil.ReadDir(from)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error reading %s", from)
+	}
+	var moves []Move
+	for _, f := range files {
+		path := filepath.Join(from, f.Name())
+		// The directory may be relocated under the ~/.minikube layout itself
+		if skipped[f.Name()] || filepath.Clean(path) == filepath.Clean(to) {
+			continue
+		}
+		moves = append(moves, Move{From: path, To: filepath.Join(to, f.Name())})
+	}
+	return moves, nil
+}
+
+// Run moves the files and returns the moves done, which are those before the
+// first error. Existing files are never overwritten.
+func Run(moves []Move) ([]Move, error) {
+	for i, m := range moves {
+		// minikube creates its directories at startup, so an empty one
+		// in the way is replaced
+		if err := removeEmptyDir(m.To); err != nil {
+			return moves[:i], err
+		}
+		if _, err := os.Lstat(m.To); err == nil {
+			return moves[:i], errors.Errorf("%s already exists", m.To)
+		}
+		if err := os.MkdirAll(filepath.Dir(m.To), 0755); err != nil {
+			return moves[:i], errors.Wrapf(err, "Error creating %s", filepath.Dir(m.To))
+		}
+		if err := move(m.From, m.To); err != nil {
+			return moves[:i], errors.Wrapf(err, "Error moving %s to %s", m.From, m.To)
+		}
+	}
+	return moves, nil
+}
+
+func removeEmptyDir(path string) error {
+	files, err := ioutil.ReadDir(path)
+	if err != nil || len(files) > 0 {
+		return nil
+	}
+	return errors.Wrapf(os.Remove(path), "Error removing %s", path)
+}
+
+// RemoveEmpty removes the directories of the ~/.minikube layout left empty
+// by the moves.
+func RemoveEmpty() {
+	for _, kind := range []constants.PathKind{constants.ConfigPath, constants.CachePath, constants.StatePath} {
+		if constants.RelocatedPath(kind) != "" {
+			// os.Remove fails on a directory which is not empty
+			os.Remove(constants.LegacyPath(kind))
+		}
+	}
+}
+
+// move renames the file or directory, or copies it when renaming fails,
+// e.g. when it is relocated to another filesystem.
+func move(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	if err := copyTree(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func copyTree(from, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(from, to string, mode os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func writeFile(t *testing.T, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Error creating dir: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(path), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	legacy := tests.MakeTempDir()
+	defer os.RemoveAll(filepath.Dir(legacy))
+	relocated := filepath.Join(filepath.Dir(legacy), "relocated")
+	for _, v := range []string{constants.MinikubeConfigDir, constants.MinikubeCacheDir, constants.MinikubeStateDir} {
+		defer os.Unsetenv(v)
+	}
+	os.Setenv(constants.MinikubeConfigDir, filepath.Join(relocated, "config"))
+	os.Setenv(constants.MinikubeCacheDir, filepath.Join(relocated, "cache"))
+	os.Setenv(constants.MinikubeStateDir, filepath.Join(relocated, "state"))
+
+	writeFile(t, filepath.Join(legacy, "config", "config.json"))
+	writeFile(t, filepath.Join(legacy, "profiles", "dev", "config.json"))
+	writeFile(t, filepath.Join(legacy, "cache", "iso", "minikube.iso"))
+	writeFile(t, filepath.Join(legacy, "certs", "ca.pem"))
+	// Created by minikube at startup
+	if err := os.MkdirAll(filepath.Join(relocated, "cache", "iso"), 0755); err != nil {
+		t.Fatalf("Error creating dir: %s", err)
+	}
+
+	var moves []Move
+	for _, kind := range []constants.PathKind{constants.ConfigPath, constants.CachePath, constants.StatePath} {
+		m, err := Plan(kind)
+		if err != nil {
+			t.Fatalf("Error planning %s: %s", kind, err)
+		}
+		moves = append(moves, m...)
+	}
+	if _, err := Run(moves); err != nil {
+		t.Fatalf("Error moving: %s", err)
+	}
+	RemoveEmpty()
+
+	for _, path := range []string{
+		filepath.Join(legacy, "config", "config.json"),
+		filepath.Join(legacy, "profiles", "dev", "config.json"),
+		filepath.Join(legacy, "cache", "iso", "minikube.iso"),
+		filepath.Join(legacy, "certs", "ca.pem"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be moved", path)
+		}
+	}
+	for _, path := range []string{
+		filepath.Join(relocated, "config", "config.json"),
+		filepath.Join(relocated, "config", "profiles", "dev", "config.json"),
+		filepath.Join(relocated, "cache", "iso", "minikube.iso"),
+		filepath.Join(relocated, "state", "certs", "ca.pem"),
+		filepath.Join(relocated, "state", "addons"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist: %s", path, err)
+		}
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Expected the empty %s to be removed", legacy)
+	}
+}
+
+func TestPlanStateWithMachines(t *testing.T) {
+	legacy := tests.MakeTempDir()
+	defer os.RemoveAll(filepath.Dir(legacy))
+	defer os.Unsetenv(constants.MinikubeStateDir)
+	os.Setenv(constants.MinikubeStateDir, filepath.Join(filepath.Dir(legacy), "state"))
+
+	writeFile(t, filepath.Join(legacy, "machines", "minikube", "config.json"))
+	if _, err := Plan(constants.StatePath); err == nil {
+		t.Error("Expected an error moving the state of a machine")
+	}
+}
+
+func TestRunDoesNotOverwrite(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(filepath.Dir(tempDir))
+
+	from := filepath.Join(tempDir, "from")
+	to := filepath.Join(tempDir, "to")
+	writeFile(t, from)
+	writeFile(t, to)
+	done, err := Run([]Move{{From: from, To: to}})
+	if err == nil {
+		t.Fatal("Expected an error moving over an existing file")
+	}
+	if len(done) != 0 {
+		t.Errorf("Expected nothing moved, got %v", done)
+	}
+	if _, err := os.Stat(from); err != nil {
+		t.Errorf("Expected %s to be kept: %s", from, err)
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(filepath.Dir(tempDir))
+
+	from := filepath.Join(tempDir, "from")
+	to := filepath.Join(tempDir, "to")
+	writeFile(t, filepath.Join(from, "a", "b"))
+	if err := copyTree(from, to); err != nil {
+		t.Fatalf("Error copying: %s", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(to, "a", "b"))
+	if err != nil {
+		t.Fatalf("Error reading the copy: %s", err)
+	}
+	if string(data) != filepath.Join(from, "a", "b") {
+		t.Errorf("Unexpected content %q", data)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package namespaces creates the namespaces declared in the config, with
+// their labels, quota and role bindings, once the cluster is ready.
+package namespaces
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	rbacv1alpha1 "k8s.io/client-go/kubernetes/typed/rbac/v1alpha1"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/resource"
+	"k8s.io/client-go/pkg/api/v1"
+	rbac "k8s.io/client-go/pkg/apis/rbac/v1alpha1"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// Setting lists the namespaces to create, NAMESPACE,NAMESPACE,...
+	Setting = "namespaces"
+	// Prefix prefixes the properties of a namespace, which also declare it:
+	// namespace.<name>.labels = KEY=VALUE,...
+	// namespace.<name>.quota = RESOURCE=QUANTITY,...
+	// namespace.<name>.role-bindings = CLUSTER ROLE=user:NAME|group:NAME|serviceaccount:NAMESPACE/NAME,...
+	Prefix = "namespace."
+
+	labelsField       = "labels"
+	quotaField        = "quota"
+	roleBindingsField = "role-bindings"
+
+	// objectPrefix prefixes the names of the quota and role bindings, and
+	// managedLabel labels them
+	objectPrefix = "minikube-"
+	managedLabel = "minikube.k8s.io/bootstrap"
+)
+
+// Namespace is a namespace declared in the config.
+type Namespace struct {
+	Name   string
+	Labels map[string]string
+	Quota  v1.ResourceList
+	// RoleBindings are the subjects bound to each cluster role in the
+	// namespace
+	RoleBindings map[string][]rbac.Subject
+}
+
+// ParseList parses the namespaces of the Setting.
+func ParseList(val string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(val, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.Contains(name, ".") {
+			return nil, errors.Errorf("%s is not a namespace name", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseProperty returns the namespace and field of a property.
+func parseProperty(name string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(name, Prefix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", errors.Errorf("%s is not of the form %s<namespace>.<%s|%s|%s>", name, Prefix, labelsField, quotaField, roleBindingsField)
+	}
+	switch parts[1] {
+	case labelsField, quotaField, roleBindingsField:
+		return parts[0], parts[1], nil
+	}
+	return "", "", errors.Errorf("%s is not one of %s, %s or %s", parts[1], labelsField, quotaField, roleBindingsField)
+}
+
+// Validate checks a property of a namespace.
+func Validate(name, val string) error {
+	_, field, err := parseProperty(name)
+	if err != nil {
+		return err
+	}
+	return set(&Namespace{}, field, val)
+}
+
+// set parses the value of the field into the namespace.
+func set(ns *Namespace, field, val string) error {
+	pairs, err := parsePairs(val)
+	if err != nil {
+		return err
+	}
+	switch field {
+	case labelsField:
+		ns.Labels = pairs
+	case quotaField:
+		ns.Quota = v1.ResourceList{}
+		for k, v := range pairs {
+			q, err := resource.ParseQuantity(v)
+			if err != nil {
+				return errors.Wrapf(err, "Error parsing the quantity of %s", k)
+			}
+			ns.Quota[v1.ResourceName(k)] = q
+		}
+	case roleBindingsField:
+		// A role is bound to several subjects with several pairs, which
+		// parsePairs collapses, so they are split again
+		ns.RoleBindings = map[string][]rbac.Subject{}
+		for _, pair := range strings.Split(val, ",") {
+			kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+			s, err := parseSubject(kv[1])
+			if err != nil {
+				return err
+			}
+			ns.RoleBindings[kv[0]] = append(ns.RoleBindings[kv[0]], s)
+		}
+	}
+	return nil
+}
+
+// parsePairs parses KEY=VALUE,..., the last value of a key given twice
+// winning.
+func parsePairs(val string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range strings.Split(val, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, errors.Errorf("%s is not of the form KEY=VALUE", pair)
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// parseSubject parses user:NAME, group:NAME or serviceaccount:NAMESPACE/NAME.
+func parseSubject(s string) (rbac.Subject, error) {
+	kv := strings.SplitN(s, ":", 2)
+	if len(kv) == 2 && kv[1] != "" {
+		switch kv[0] {
+		case "user":
+			return rbac.Subject{Kind: "User", Name: kv[1]}, nil
+		case "group":
+			return rbac.Subject{Kind: "Group", Name: kv[1]}, nil
+		case "serviceaccount":
+			ref := strings.Split(kv[1], "/")
+			if len(ref) == 2 && ref[0] != "" && ref[1] != "" {
+				return rbac.Subject{Kind: "ServiceAccount", Namespace: ref[0], Name: ref[1]}, nil
+			}
+		}
+	}
+	return rbac.Subject{}, errors.Errorf("%s is not of the form user:NAME, group:NAME or serviceaccount:NAMESPACE/NAME", s)
+}
+
+// Load returns the namespaces declared in the config, sorted.
+func Load() ([]Namespace, error) {
+	m, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]*Namespace{}
+	declare := func(name string) *Namespace {
+		if _, ok := byName[name]; !ok {
+			byName[name] = &Namespace{Name: name}
+		}
+		return byName[name]
+	}
+	if list, ok := m[Setting]; ok {
+		names, err := ParseList(fmt.Sprintf("%v", list))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			declare(name)
+		}
+	}
+	for k, v := range m {
+		if !strings.HasPrefix(k, Prefix) {
+			continue
+		}
+		name, field, err := parseProperty(k)
+		if err != nil {
+			return nil, err
+		}
+		if err := set(declare(name), field, fmt.Sprintf("%v", v)); err != nil {
+			return nil, errors.Wrapf(err, "Error parsing %s", k)
+		}
+	}
+	var namespaces []Namespace
+	for _, ns := range byName {
+		namespaces = append(namespaces, *ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces, nil
+}
+
+// Client is what creating the namespaces needs of the Kubernetes client
+type Client interface {
+	corev1.NamespacesGetter
+	corev1.ResourceQuotasGetter
+	rbacv1alpha1.RoleBindingsGetter
+}
+
+// Apply creates the namespaces, or updates those already there, with their
+// labels, quota and role bindings. The labels and objects not declared are
+// left alone. It goes on with the next namespace when one fails and returns
+// the errors of all of them.
+func Apply(client Client, namespaces []Namespace, w io.Writer) error {
+	m := util.MultiError{}
+	for _, ns := range namespaces {
+		err := util.RetryAfter(5, func() error {
+			if err := apply(client, ns); err != nil {
+				return &util.RetriableError{Err: err}
+			}
+			return nil
+		}, 2*time.Second)
+		if err != nil {
+			m.Collect(errors.Wrapf(err, "Error setting up namespace %s", ns.Name))
+			continue
+		}
+		fmt.Fprintf(w, "Set up namespace %s\n", ns.Name)
+	}
+	return m.ToError()
+}
+
+func apply(client Client, ns Namespace) error {
+	if err := applyNamespace(client, ns); err != nil {
+		return err
+	}
+	if len(ns.Quota) > 0 {
+		if err := applyQuota(client, ns); err != nil {
+			return errors.Wrap(err, "Error setting the quota")
+		}
+	}
+	var roles []string
+	for role := range ns.RoleBindings {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if err := applyRoleBinding(client, ns.Name, role, ns.RoleBindings[role]); err != nil {
+			return errors.Wrapf(err, "Error binding %s", role)
+		}
+	}
+	return nil
+}
+
+func applyNamespace(client Client, ns Namespace) error {
+	existing, err := client.Namespaces().Get(ns.Name)
+	if apierrors.IsNotFound(err) {
+		_, err = client.Namespaces().Create(&v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: ns.Name, Labels: ns.Labels}})
+		return err
+	}
+	if err != nil || len(ns.Labels) == 0 {
+		return err
+	}
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range ns.Labels {
+		existing.Labels[k] = v
+	}
+	_, err = client.Namespaces().Update(existing)
+	return err
+}
+
+func applyQuota(client Client, ns Namespace) error {
+	quotas := client.ResourceQuotas(ns.Name)
+	name := objectPrefix + "quota"
+	existing, err := quotas.Get(name)
+	if err == nil {
+		existing.Spec.Hard = ns.Quota
+		_, err = quotas.Update(existing)
+		return err
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = quotas.Create(&v1.ResourceQuota{
+		ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{managedLabel: "true"}},
+		Spec:       v1.ResourceQuotaSpec{Hard: ns.Quota},
+	})
+	return err
+}
+
+func applyRoleBinding(client Client, namespace, role string, subjects []rbac.Subject) error {
+	bindings := client.RoleBindings(namespace)
+	name := objectPrefix + role
+	existing, err := bindings.Get(name)
+	if err == nil {
+		existing.Subjects = subjects
+		_, err = bindings.Update(existing)
+		return err
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = bindings.Create(&rbac.RoleBinding{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{managedLabel: "true"}},
+		Subjects:   subjects,
+		RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: role},
+	})
+	return err
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaces
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	rbacv1alpha1 "k8s.io/client-go/kubernetes/typed/rbac/v1alpha1"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/resource"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	rbac "k8s.io/client-go/pkg/apis/rbac/v1alpha1"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestValidate(t *testing.T) {
+	var tcs = []struct {
+		name      string
+		val       string
+		shouldErr bool
+	}{
+		{name: "namespace.dev.labels", val: "team=web, env=dev"},
+		{name: "namespace.dev.quota", val: "pods=10,requests.cpu=2,requests.memory=2Gi"},
+		{name: "namespace.dev.role-bindings", val: "edit=user:alice,edit=group:devs,view=serviceaccount:ci/runner"},
+		{name: "namespace.dev", val: "team=web", shouldErr: true},
+		{name: "namespace.dev.annotations", val: "team=web", shouldErr: true},
+		{name: "namespace.dev.labels", val: "team", shouldErr: true},
+		{name: "namespace.dev.quota", val: "pods=ten", shouldErr: true},
+		{name: "namespace.dev.role-bindings", val: "edit=alice", shouldErr: true},
+		{name: "namespace.dev.role-bindings", val: "edit=serviceaccount:runner", shouldErr: true},
+	}
+	for _, test := range tcs {
+		if err := Validate(test.name, test.val); (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s = %s: %v", test.name, test.val, err)
+		}
+	}
+	if _, err := ParseList("dev,team.web"); err == nil {
+		t.Errorf("Expected an error for a namespace name with a dot")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	viper.Set(config.ProfileFlag, "namespaces")
+
+	path := config.ProfileConfigFile("namespaces")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	data := `{
+	"namespaces": "prod, dev",
+	"namespace.dev.labels": "team=web",
+	"namespace.ci.role-bindings": "edit=user:alice,edit=group:devs"
+}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	declared, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []Namespace{
+		{Name: "ci", RoleBindings: map[string][]rbac.Subject{"edit": {
+			{Kind: "User", Name: "alice"},
+			{Kind: "Group", Name: "devs"},
+		}}},
+		{Name: "dev", Labels: map[string]string{"team": "web"}},
+		{Name: "prod"},
+	}
+	if !reflect.DeepEqual(declared, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, declared)
+	}
+}
+
+func notFound(resource, name string) error {
+	return apierrors.NewNotFound(unversioned.GroupResource{Resource: resource}, name)
+}
+
+type mockClient struct {
+	namespaces map[string]*v1.Namespace
+	quotas     map[string]*v1.ResourceQuota
+	bindings   map[string]*rbac.RoleBinding
+}
+
+func (m *mockClient) Namespaces() corev1.NamespaceInterface {
+	return &mockNamespaces{namespaces: m.namespaces}
+}
+
+func (m *mockClient) ResourceQuotas(namespace string) corev1.ResourceQuotaInterface {
+	return &mockQuotas{quotas: m.quotas, namespace: namespace}
+}
+
+func (m *mockClient) RoleBindings(namespace string) rbacv1alpha1.RoleBindingInterface {
+	return &mockRoleBindings{bindings: m.bindings, namespace: namespace}
+}
+
+type mockNamespaces struct {
+	fake.FakeNamespaces
+	namespaces map[string]*v1.Namespace
+}
+
+func (m *mockNamespaces) Get(name string) (*v1.Namespace, error) {
+	ns, ok := m.namespaces[name]
+	if !ok {
+		return nil, notFound("namespaces", name)
+	}
+	copied := *ns
+	return &copied, nil
+}
+
+func (m *mockNamespaces) Create(ns *v1.Namespace) (*v1.Namespace, error) {
+	m.namespaces[ns.Name] = ns
+	return ns, nil
+}
+
+func (m *mockNamespaces) Update(ns *v1.Namespace) (*v1.Namespace, error) {
+	return m.Create(ns)
+}
+
+type mockQuotas struct {
+	fake.FakeResourceQuotas
+	quotas    map[string]*v1.ResourceQuota
+	namespace string
+}
+
+func (m *mockQuotas) Get(name string) (*v1.ResourceQuota, error) {
+	q, ok := m.quotas[m.namespace+"/"+name]
+	if !ok {
+		return nil, notFound("resourcequotas", name)
+	}
+	copied := *q
+	return &copied, nil
+}
+
+func (m *mockQuotas) Create(q *v1.ResourceQuota) (*v1.ResourceQuota, error) {
+	m.quotas[m.namespace+"/"+q.Name] = q
+	return q, nil
+}
+
+func (m *mockQuotas) Update(q *v1.ResourceQuota) (*v1.ResourceQuota, error) {
+	return m.Create(q)
+}
+
+// mockRoleBindings implements the calls made of the interface, there is no
+// fake of the rbac client
+type mockRoleBindings struct {
+	rbacv1alpha1.RoleBindingInterface
+	bindings  map[string]*rbac.RoleBinding
+	namespace string
+}
+
+func (m *mockRoleBindings) Get(name string) (*rbac.RoleBinding, error) {
+	b, ok := m.bindings[m.namespace+"/"+name]
+	if !ok {
+		return nil, notFound("rolebindings", name)
+	}
+	copied := *b
+	return &copied, nil
+}
+
+func (m *mockRoleBindings) Create(b *rbac.RoleBinding) (*rbac.RoleBinding, error) {
+	m.bindings[m.namespace+"/"+b.Name] = b
+	return b, nil
+}
+
+func (m *mockRoleBindings) Update(b *rbac.RoleBinding) (*rbac.RoleBinding, error) {
+	return m.Create(b)
+}
+
+func TestApply(t *testing.T) {
+	client := &mockClient{
+		namespaces: map[string]*v1.Namespace{
+			"dev": {ObjectMeta: v1.ObjectMeta{Name: "dev", Labels: map[string]string{"owner": "ops"}}},
+		},
+		quotas: map[string]*v1.ResourceQuota{},
+		bindings: map[string]*rbac.RoleBinding{
+			"dev/minikube-edit": {ObjectMeta: v1.ObjectMeta{Name: "minikube-edit"}, Subjects: []rbac.Subject{{Kind: "User", Name: "bob"}}},
+		},
+	}
+	alice := []rbac.Subject{{Kind: "User", Name: "alice"}}
+	declared := []Namespace{
+		{
+			Name:         "dev",
+			Labels:       map[string]string{"team": "web"},
+			Quota:        v1.ResourceList{v1.ResourcePods: resource.MustParse("10")},
+			RoleBindings: map[string][]rbac.Subject{"edit": alice, "view": alice},
+		},
+		{Name: "prod"},
+	}
+
+	var out bytes.Buffer
+	if err := Apply(client, declared, &out); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if labels := client.namespaces["dev"].Labels; !reflect.DeepEqual(labels, map[string]string{"owner": "ops", "team": "web"}) {
+		t.Errorf("Expected the labels to be merged, got %v", labels)
+	}
+	if _, ok := client.namespaces["prod"]; !ok {
+		t.Errorf("Expected the namespace prod to be created")
+	}
+	if q := client.quotas["dev/minikube-quota"]; q == nil || q.Spec.Hard[v1.ResourcePods] != resource.MustParse("10") {
+		t.Errorf("Expected the quota to be created, got %v", q)
+	}
+	if b := client.bindings["dev/minikube-edit"]; !reflect.DeepEqual(b.Subjects, alice) {
+		t.Errorf("Expected the subjects of the binding to be replaced, got %v", b.Subjects)
+	}
+	if b := client.bindings["dev/minikube-view"]; b == nil || b.RoleRef.Name != "view" || b.RoleRef.Kind != "ClusterRole" {
+		t.Errorf("Expected the binding of the view cluster role to be created, got %v", b)
+	}
+	expected := "Set up namespace dev\nSet up namespace prod\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,401 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package netbench measures the network throughput and latency between the
+// host, the nodes and the pods, to spot misconfigured virtual NICs.
+package netbench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// Image runs iperf3, the server and the clients of the measures in the
+	// cluster
+	Image = "networkstatic/iperf3"
+	// Port is the port the iperf3 server listens on
+	Port = 5201
+	// Label labels the pods of the benchmark
+	Label = "minikube-netbench"
+)
+
+// The paths the throughput is measured on
+const (
+	HostToNode = "host -> node"
+	NodeToPod  = "node -> pod"
+	PodToPod   = "pod -> pod"
+)
+
+// Baseline is the throughput in Mbit/s each path is expected to reach at
+// least with a driver.
+type Baseline map[string]float64
+
+// defaultBaseline applies to the drivers without a baseline of their own.
+var defaultBaseline = Baseline{HostToNode: 200, NodeToPod: 2000, PodToPod: 200}
+
+// baselines are the throughputs measured on typical laptops, halved. The
+// host is reached over ssh, whose encryption costs throughput, and the other
+// nodes over the virtual network of the driver.
+var baselines = map[string]Baseline{
+	"virtualbox":   {HostToNode: 200, NodeToPod: 2000, PodToPod: 200},
+	"vmwarefusion": {HostToNode: 400, NodeToPod: 2000, PodToPod: 400},
+	"xhyve":        {HostToNode: 200, NodeToPod: 2000, PodToPod: 200},
+	"hyperkit":     {HostToNode: 400, NodeToPod: 2000, PodToPod: 400},
+	"hyperv":       {HostToNode: 400, NodeToPod: 2000, PodToPod: 400},
+	"qemu":         {HostToNode: 100, NodeToPod: 1000, PodToPod: 100},
+	"kvm":          {HostToNode: 800, NodeToPod: 2000, PodToPod: 1000},
+	"kvm2":         {HostToNode: 800, NodeToPod: 2000, PodToPod: 1000},
+	"docker":       {HostToNode: 800, NodeToPod: 2000, PodToPod: 2000},
+	"podman":       {HostToNode: 800, NodeToPod: 2000, PodToPod: 2000},
+}
+
+// BaselineFor returns the expected throughputs of the driver.
+func BaselineFor(driver string) Baseline {
+	if b, ok := baselines[driver]; ok {
+		return b
+	}
+	return defaultBaseline
+}
+
+// Measurement is the throughput in Mbit/s and the round trip time of a path.
+type Measurement struct {
+	Throughput float64
+	Latency    time.Duration
+}
+
+// Result is the measurement of a path, compared with its baseline.
+type Result struct {
+	Path string
+	Measurement
+	Expected float64
+	// Err is why the path could not be measured
+	Err error
+	// Skipped is why the path was not measured
+	Skipped string
+}
+
+// Slow reports whether the path is slower than its baseline.
+func (r Result) Slow() bool {
+	return r.Err == nil && r.Skipped == "" && r.Throughput < r.Expected
+}
+
+// iperf3Output is the part of the JSON output of the iperf3 client which is
+// used.
+type iperf3Output struct {
+	End struct {
+		SumReceived struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+		} `json:"sum_received"`
+		Streams []struct {
+			Sender struct {
+				// MeanRTT is in microseconds, it is only reported on Linux
+				MeanRTT int64 `json:"mean_rtt"`
+			} `json:"sender"`
+		} `json:"streams"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+// ParseIperf3 returns the measurement of the JSON output of an iperf3 client.
+func ParseIperf3(out []byte) (Measurement, error) {
+	var o iperf3Output
+	if err := json.Unmarshal(out, &o); err != nil {
+		return Measurement{}, errors.Wrapf(err, "Error parsing the output of iperf3: %s", out)
+	}
+	if o.Error != "" {
+		return Measurement{}, errors.Errorf("iperf3 failed: %s", o.Error)
+	}
+	m := Measurement{Throughput: o.End.SumReceived.BitsPerSecond / 1e6}
+	if len(o.End.Streams) > 0 {
+		m.Latency = time.Duration(o.End.Streams[0].Sender.MeanRTT) * time.Microsecond
+	}
+	return m, nil
+}
+
+// ServerPod returns the pod running the iperf3 server on the node.
+func ServerPod(namespace, node string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: "netbench-server-",
+			Namespace:    namespace,
+			Labels:       map[string]string{"app": Label},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:            "iperf3",
+				Image:           Image,
+				ImagePullPolicy: v1.PullIfNotPresent,
+				Args:            []string{"-s", "-p", strconv.Itoa(Port)},
+			}},
+			NodeName:      node,
+			RestartPolicy: v1.RestartPolicyNever,
+		},
+	}
+}
+
+// ClientPod returns the pod measuring the throughput from the node to the
+// server for the duration, in the network of the node or of a pod.
+func ClientPod(namespace, node, server string, hostNetwork bool, duration time.Duration) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: "netbench-client-",
+			Namespace:    namespace,
+			Labels:       map[string]string{"app": Label},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:            "iperf3",
+				Image:           Image,
+				ImagePullPolicy: v1.PullIfNotPresent,
+				Args:            []string{"-c", server, "-p", strconv.Itoa(Port), "-t", strconv.Itoa(int(duration / time.Second)), "-J"},
+			}},
+			NodeName:      node,
+			HostNetwork:   hostNetwork,
+			RestartPolicy: v1.RestartPolicyNever,
+		},
+	}
+}
+
+// StartServer runs the iperf3 server on the node, and returns its pod once it
+// has an IP.
+func StartServer(client kubernetes.Interface, namespace, node string, timeout time.Duration) (*v1.Pod, error) {
+	pods := client.Core().Pods(namespace)
+	pod, err := pods.Create(ServerPod(namespace, node))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the iperf3 server pod")
+	}
+	running := func() error {
+		p, err := pods.Get(pod.Name)
+		if err != nil {
+			return err
+		}
+		pod = p
+		if p.Status.Phase != v1.PodRunning || p.Status.PodIP == "" {
+			return errors.Errorf("pod %s is %s", p.Name, p.Status.Phase)
+		}
+		return nil
+	}
+	if err := util.RetryAfter(int(timeout/time.Second), running, time.Second); err != nil {
+		pods.Delete(pod.Name, &v1.DeleteOptions{})
+		return nil, errors.Wrap(err, "Timed out waiting for the iperf3 server pod")
+	}
+	return pod, nil
+}
+
+// Measure runs the client pod until it completes, and returns its measurement.
+// The pod is deleted once done.
+func Measure(client kubernetes.Interface, pod *v1.Pod, timeout time.Duration) (Measurement, error) {
+	pods := client.Core().Pods(pod.Namespace)
+	pod, err := pods.Create(pod)
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error creating the iperf3 client pod")
+	}
+	defer pods.Delete(pod.Name, &v1.DeleteOptions{})
+
+	done := func() error {
+		p, err := pods.Get(pod.Name)
+		if err != nil {
+			return err
+		}
+		if p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed {
+			return errors.Errorf("pod %s is %s", p.Name, p.Status.Phase)
+		}
+		return nil
+	}
+	if err := util.RetryAfter(int(timeout/time.Second), done, time.Second); err != nil {
+		return Measurement{}, errors.Wrap(err, "Timed out waiting for the iperf3 client pod")
+	}
+	logs, err := pods.GetLogs(pod.Name, &v1.PodLogOptions{}).DoRaw()
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error getting the output of the iperf3 client pod")
+	}
+	return ParseIperf3(logs)
+}
+
+// MeasureHost measures the throughput from the host to the node by streaming
+// size bytes to it over ssh, and the latency by connecting to its sshd.
+func MeasureHost(d drivers.Driver, size int) (Measurement, error) {
+	ip, err := d.GetSSHHostname()
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error getting the ssh address of the node")
+	}
+	port, err := d.GetSSHPort()
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error getting the ssh port of the node")
+	}
+	latency, err := connectTime(net.JoinHostPort(ip, strconv.Itoa(port)), 5)
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error creating new ssh client")
+	}
+	defer client.Close()
+	s, err := client.NewSession()
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error creating new session via ssh client")
+	}
+	defer s.Close()
+	w, err := s.StdinPipe()
+	if err != nil {
+		return Measurement{}, errors.Wrap(err, "Error accessing StdinPipe via ssh session")
+	}
+	if err := s.Start("cat > /dev/null"); err != nil {
+		return Measurement{}, errors.Wrap(err, "Error starting the sink of the stream")
+	}
+	start := time.Now()
+	if _, err := io.CopyN(w, zeros{}, int64(size)); err != nil {
+		return Measurement{}, errors.Wrap(err, "Error streaming to the node")
+	}
+	w.Close()
+	if err := s.Wait(); err != nil {
+		return Measurement{}, errors.Wrap(err, "Error streaming to the node")
+	}
+	elapsed := time.Since(start)
+	return Measurement{Throughput: float64(size) * 8 / 1e6 / elapsed.Seconds(), Latency: latency}, nil
+}
+
+// connectTime returns the average time to connect to the address, the round
+// trip of the TCP handshake.
+func connectTime(address string, samples int) (time.Duration, error) {
+	var total time.Duration
+	for i := 0; i < samples; i++ {
+		start := time.Now()
+		c, err := net.DialTimeout("tcp", address, 5*time.Second)
+		if err != nil {
+			return 0, errors.Wrapf(err, "Error connecting to %s", address)
+		}
+		total += time.Since(start)
+		c.Close()
+	}
+	return total / time.Duration(samples), nil
+}
+
+// zeros reads zeros endlessly.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+// hostStreamSize is how much is streamed to the node to measure the
+// throughput from the host.
+const hostStreamSize = 128 << 20
+
+// Run measures the paths from the host to the node of the driver, from that
+// node to a pod, and from a pod of another node to that pod if there is one.
+// The pods run in the namespace, each iperf3 client for the duration.
+func Run(client kubernetes.Interface, d drivers.Driver, namespace string, duration, timeout time.Duration) ([]Result, error) {
+	baseline := BaselineFor(d.DriverName())
+	hostResult := Result{Path: HostToNode, Expected: baseline[HostToNode]}
+	if d.DriverName() == constants.DriverNone {
+		hostResult.Skipped = "the node is this machine"
+	} else {
+		hostResult.Measurement, hostResult.Err = MeasureHost(d, hostStreamSize)
+	}
+	results := []Result{hostResult}
+
+	nodes, err := client.Core().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return results, errors.Wrap(err, "Error listing the nodes")
+	}
+	primary, other := pickNodes(nodes.Items)
+	if primary == "" {
+		return results, errors.New("The cluster has no nodes")
+	}
+	server, err := StartServer(client, namespace, primary, timeout)
+	if err != nil {
+		return results, err
+	}
+	defer client.Core().Pods(namespace).Delete(server.Name, &v1.DeleteOptions{})
+
+	nodeResult := Result{Path: NodeToPod, Expected: baseline[NodeToPod]}
+	nodeResult.Measurement, nodeResult.Err = Measure(client, ClientPod(namespace, primary, server.Status.PodIP, true, duration), timeout)
+	results = append(results, nodeResult)
+
+	podResult := Result{Path: PodToPod, Expected: baseline[PodToPod]}
+	if other == "" {
+		podResult.Skipped = "the cluster has a single node"
+	} else {
+		podResult.Measurement, podResult.Err = Measure(client, ClientPod(namespace, other, server.Status.PodIP, false, duration), timeout)
+	}
+	return append(results, podResult), nil
+}
+
+// pickNodes returns the node of the minikube VM, the first one otherwise,
+// and another node, if any.
+func pickNodes(nodes []v1.Node) (primary, other string) {
+	for _, n := range nodes {
+		if n.Name == constants.MachineName {
+			primary = n.Name
+		}
+	}
+	for _, n := range nodes {
+		switch {
+		case primary == "":
+			primary = n.Name
+		case n.Name != primary && other == "":
+			other = n.Name
+		}
+	}
+	return primary, other
+}
+
+// PrintResults prints the results as a table, flagging the paths slower than
+// their baseline.
+func PrintResults(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tTHROUGHPUT\tLATENCY\tEXPECTED\tSTATUS")
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			fmt.Fprintf(tw, "%s\t-\t-\t-\tskipped: %s\n", r.Path, r.Skipped)
+		case r.Err != nil:
+			fmt.Fprintf(tw, "%s\t-\t-\t-\terror: %s\n", r.Path, strings.Split(r.Err.Error(), "\n")[0])
+		default:
+			status := "ok"
+			if r.Slow() {
+				status = "SLOW"
+			}
+			latency := "-"
+			if r.Latency > 0 {
+				latency = r.Latency.String()
+			}
+			fmt.Fprintf(tw, "%s\t%.0f Mbit/s\t%s\t>= %.0f Mbit/s\t%s\n", r.Path, r.Throughput, latency, r.Expected, status)
+		}
+	}
+	return tw.Flush()
+}
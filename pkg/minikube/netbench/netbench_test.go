@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netbench
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestParseIperf3(t *testing.T) {
+	out := `{"start": {}, "end": {"streams": [{"sender": {"bytes": 1, "mean_rtt": 250}}], "sum_received": {"bits_per_second": 2.5e9}}}`
+	m, err := ParseIperf3([]byte(out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if m.Throughput != 2500 || m.Latency != 250*time.Microsecond {
+		t.Errorf("Expected 2500 Mbit/s and 250µs, got %+v", m)
+	}
+
+	if _, err := ParseIperf3([]byte(`{"error": "unable to connect to server: Connection refused"}`)); err == nil {
+		t.Errorf("Expected an error for a failed client")
+	}
+	if _, err := ParseIperf3([]byte("iperf3: error")); err == nil {
+		t.Errorf("Expected an error for an output which is not JSON")
+	}
+}
+
+func TestClientPod(t *testing.T) {
+	p := ClientPod("default", "minikube-m02", "172.17.0.4", false, 10*time.Second)
+	args := strings.Join(p.Spec.Containers[0].Args, " ")
+	if args != "-c 172.17.0.4 -p 5201 -t 10 -J" {
+		t.Errorf("Unexpected arguments %q", args)
+	}
+	if p.Spec.NodeName != "minikube-m02" || p.Spec.HostNetwork {
+		t.Errorf("Expected a pod network client on minikube-m02, got %+v", p.Spec)
+	}
+}
+
+func TestPickNodes(t *testing.T) {
+	var tcs = []struct {
+		nodes   []string
+		primary string
+		other   string
+	}{
+		{nodes: nil},
+		{nodes: []string{"minikube"}, primary: "minikube"},
+		{nodes: []string{"minikube-m02", "minikube"}, primary: "minikube", other: "minikube-m02"},
+		{nodes: []string{"ci-node"}, primary: "ci-node"},
+	}
+	for _, test := range tcs {
+		var nodes []v1.Node
+		for _, n := range test.nodes {
+			nodes = append(nodes, v1.Node{ObjectMeta: v1.ObjectMeta{Name: n}})
+		}
+		primary, other := pickNodes(nodes)
+		if primary != test.primary || other != test.other {
+			t.Errorf("Expected %q and %q for %v, got %q and %q", test.primary, test.other, test.nodes, primary, other)
+		}
+	}
+}
+
+func TestPrintResults(t *testing.T) {
+	results := []Result{
+		{Path: HostToNode, Measurement: Measurement{Throughput: 50, Latency: time.Millisecond}, Expected: 200},
+		{Path: NodeToPod, Measurement: Measurement{Throughput: 9000}, Expected: 2000},
+		{Path: PodToPod, Expected: 200, Skipped: "the cluster has a single node"},
+		{Path: PodToPod, Expected: 200, Err: errors.New("Timed out")},
+	}
+	if !results[0].Slow() || results[1].Slow() || results[2].Slow() || results[3].Slow() {
+		t.Errorf("Expected only the host path to be slow")
+	}
+	var b bytes.Buffer
+	if err := PrintResults(&b, results); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{"50 Mbit/s", "1ms", "SLOW", "9000 Mbit/s", "skipped: the cluster has a single node", "error: Timed out"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected the results to contain %q:\n%s", expected, b.String())
+		}
+	}
+}
+
+func TestBaselineFor(t *testing.T) {
+	if BaselineFor("kvm")[HostToNode] <= BaselineFor("virtualbox")[HostToNode] {
+		t.Errorf("Expected kvm to be expected faster than virtualbox")
+	}
+	if BaselineFor("unknown")[NodeToPod] != defaultBaseline[NodeToPod] {
+		t.Errorf("Expected the default baseline for an unknown driver")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node drains and removes the Kubernetes nodes of the VMs managed by
+// minikube node.
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/pkg/api"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/util"
+)
+
+// mirrorPodAnnotation marks the pods mirroring the static pods of a kubelet
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// Client is what draining needs of the Kubernetes client
+type Client interface {
+	corev1.NodesGetter
+	corev1.PodsGetter
+}
+
+// Cordon marks the node unschedulable.
+func Cordon(client Client, name string) error {
+	n, err := client.Nodes().Get(name)
+	if err != nil {
+		return errors.Wrapf(err, "Error getting node %s", name)
+	}
+	if n.Spec.Unschedulable {
+		return nil
+	}
+	n.Spec.Unschedulable = true
+	if _, err := client.Nodes().Update(n); err != nil {
+		return errors.Wrapf(err, "Error cordoning node %s", name)
+	}
+	return nil
+}
+
+// Drain cordons the node and deletes its pods, but those of daemon sets and
+// the mirror pods which would come back anyway, then waits up to timeout
+// for them to be gone. It returns the pods deleted, as namespace/name.
+func Drain(client Client, name string, timeout time.Duration) ([]string, error) {
+	if err := Cordon(client, name); err != nil {
+		return nil, err
+	}
+	pods, err := drainedPods(client, name)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for _, p := range pods {
+		if err := client.Pods(p.Namespace).Delete(p.Name, &v1.DeleteOptions{}); err != nil {
+			return deleted, errors.Wrapf(err, "Error deleting pod %s/%s", p.Namespace, p.Name)
+		}
+		deleted = append(deleted, p.Namespace+"/"+p.Name)
+	}
+
+	const interval = 2 * time.Second
+	gone := func() error {
+		left, err := drainedPods(client, name)
+		if err != nil {
+			return err
+		}
+		if len(left) > 0 {
+			return &util.RetriableError{Err: fmt.Errorf("%d pods are still terminating on node %s", len(left), name)}
+		}
+		return nil
+	}
+	if err := util.RetryAfter(int(timeout/interval)+1, gone, interval); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// Delete removes the node from the cluster.
+func Delete(client Client, name string) error {
+	if err := client.Nodes().Delete(name, &v1.DeleteOptions{}); err != nil {
+		return errors.Wrapf(err, "Error deleting node %s", name)
+	}
+	return nil
+}
+
+// drainedPods returns the pods on the node which draining deletes.
+func drainedPods(client Client, name string) ([]v1.Pod, error) {
+	list, err := client.Pods(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing pods")
+	}
+	var pods []v1.Pod
+	for _, p := range list.Items {
+		if p.Spec.NodeName != name || isMirrorPod(p) || isDaemonSetPod(p) {
+			continue
+		}
+		if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		pods = append(pods, p)
+	}
+	return pods, nil
+}
+
+func isMirrorPod(p v1.Pod) bool {
+	_, ok := p.Annotations[mirrorPodAnnotation]
+	return ok
+}
+
+func isDaemonSetPod(p v1.Pod) bool {
+	for _, o := range p.OwnerReferences {
+		if o.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return strings.Contains(p.Annotations[api.CreatedByAnnotation], `"kind":"DaemonSet"`)
+}
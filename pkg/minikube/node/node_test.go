@@ -0,0 +1,127 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+type mockClient struct {
+	nodes *mockNodes
+	pods  *mockPods
+}
+
+func (m *mockClient) Nodes() corev1.NodeInterface {
+	return m.nodes
+}
+
+func (m *mockClient) Pods(namespace string) corev1.PodInterface {
+	m.pods.namespace = namespace
+	return m.pods
+}
+
+type mockNodes struct {
+	fake.FakeNodes
+	node    v1.Node
+	deleted []string
+}
+
+func (m *mockNodes) Get(name string) (*v1.Node, error) {
+	n := m.node
+	return &n, nil
+}
+
+func (m *mockNodes) Update(n *v1.Node) (*v1.Node, error) {
+	m.node = *n
+	return n, nil
+}
+
+func (m *mockNodes) Delete(name string, opts *v1.DeleteOptions) error {
+	m.deleted = append(m.deleted, name)
+	return nil
+}
+
+type mockPods struct {
+	fake.FakePods
+	pods      []v1.Pod
+	namespace string
+}
+
+func (m *mockPods) List(opts v1.ListOptions) (*v1.PodList, error) {
+	return &v1.PodList{Items: m.pods}, nil
+}
+
+func (m *mockPods) Delete(name string, opts *v1.DeleteOptions) error {
+	for i, p := range m.pods {
+		if p.Namespace == m.namespace && p.Name == name {
+			m.pods = append(m.pods[:i], m.pods[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	pod := func(ns, name, node string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: v1.ObjectMeta{Namespace: ns, Name: name, Annotations: map[string]string{}},
+			Spec:       v1.PodSpec{NodeName: node},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+	daemon := pod("kube-system", "fluentd", "m02")
+	daemon.OwnerReferences = []v1.OwnerReference{{Kind: "DaemonSet", Name: "fluentd"}}
+	mirror := pod("kube-system", "addon-manager-m02", "m02")
+	mirror.Annotations[mirrorPodAnnotation] = "hash"
+	done := pod("default", "migrate", "m02")
+	done.Status.Phase = v1.PodSucceeded
+
+	client := &mockClient{
+		nodes: &mockNodes{node: v1.Node{ObjectMeta: v1.ObjectMeta{Name: "m02"}}},
+		pods: &mockPods{pods: []v1.Pod{
+			pod("default", "web", "m02"),
+			pod("default", "db", "minikube"),
+			daemon,
+			mirror,
+			done,
+			pod("kube-system", "dns", "m02"),
+		}},
+	}
+
+	deleted, err := Drain(client, "m02", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"default/web", "kube-system/dns"}; !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Expected pods %v to be deleted, got %v", expected, deleted)
+	}
+	if !client.nodes.node.Spec.Unschedulable {
+		t.Error("Expected the node to be cordoned")
+	}
+
+	if err := Delete(client, "m02"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(client.nodes.deleted, []string{"m02"}) {
+		t.Errorf("Expected the node to be deleted, got %v", client.nodes.deleted)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugins finds the binaries named minikube-<name> in the PATH,
+// which minikube runs as its subcommand <name>, as kubectl does its plugins,
+// and provides the environment minikube runs them with.
+package plugins
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Prefix is the prefix of the names of the binaries of the plugins.
+const Prefix = "minikube-"
+
+// Plugin is a binary of a plugin.
+type Plugin struct {
+	// Name is the subcommand of the plugin, the name of the binary without
+	// the prefix
+	Name string
+	Path string
+	// ShadowedBy is the path of the binary of the same name earlier in the
+	// PATH, which runs instead of this one
+	ShadowedBy string
+}
+
+// Find returns the plugins in the directories of the path, in their order.
+func Find(path string) []Plugin {
+	var found []Plugin
+	first := map[string]string{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name, ok := pluginName(f)
+			if !ok {
+				continue
+			}
+			p := Plugin{Name: name, Path: filepath.Join(dir, f.Name()), ShadowedBy: first[name]}
+			if p.ShadowedBy == "" {
+				first[name] = p.Path
+			}
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// pluginName returns the name of the plugin of the file, if it is an
+// executable with the prefix.
+func pluginName(f os.FileInfo) (string, bool) {
+	name := f.Name()
+	if !strings.HasPrefix(name, Prefix) || f.IsDir() {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(name), ".exe") {
+			return "", false
+		}
+		name = name[:len(name)-len(".exe")]
+	} else if f.Mode()&0111 == 0 {
+		return "", false
+	}
+	name = strings.TrimPrefix(name, Prefix)
+	return name, name != ""
+}
+
+// Env is what minikube tells the plugins, in the environment variables of
+// Vars. The fields of the machine are empty when it isn't running.
+type Env struct {
+	// Profile is the active profile, empty for the global config
+	Profile string
+	// Binary is the minikube running the plugin
+	Binary string
+	// Kubeconfig and Context are where kubectl finds the cluster
+	Kubeconfig string
+	Context    string
+	// Machine is the name of the VM, and the fields below are how to reach it
+	Machine string
+	IP      string
+	SSHHost string
+	SSHPort int
+	SSHUser string
+	SSHKey  string
+}
+
+// Vars returns the environment variables of the plugins, leaving out the
+// empty fields. But for MINIKUBE_PROFILE, which has the minikube commands of
+// the plugin use the profile too, the names don't collide with the settings
+// minikube reads from the environment.
+func (e Env) Vars() []string {
+	port := ""
+	if e.SSHPort != 0 {
+		port = strconv.Itoa(e.SSHPort)
+	}
+	var vars []string
+	for _, v := range []struct{ name, value string }{
+		{"MINIKUBE_PROFILE", e.Profile},
+		{"MINIKUBE_BIN", e.Binary},
+		{"MINIKUBE_KUBECONFIG", e.Kubeconfig},
+		{"MINIKUBE_CONTEXT", e.Context},
+		{"MINIKUBE_MACHINE_NAME", e.Machine},
+		{"MINIKUBE_MACHINE_IP", e.IP},
+		{"MINIKUBE_MACHINE_SSH_HOST", e.SSHHost},
+		{"MINIKUBE_MACHINE_SSH_PORT", port},
+		{"MINIKUBE_MACHINE_SSH_USER", e.SSHUser},
+		{"MINIKUBE_MACHINE_SSH_KEY", e.SSHKey},
+	} {
+		if v.value != "" {
+			vars = append(vars, fmt.Sprintf("%s=%s", v.name, v.value))
+		}
+	}
+	return vars
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	first, second := filepath.Join(tempDir, "first"), filepath.Join(tempDir, "second")
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(first, "minikube-foo"):   0755,
+		filepath.Join(first, "minikube-notes"): 0644,
+		filepath.Join(first, "kubectl-foo"):    0755,
+		filepath.Join(second, "minikube-foo"):  0755,
+		filepath.Join(second, "minikube-bar"):  0755,
+		filepath.Join(second, "minikube-"):     0755,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := Find(strings.Join([]string{first, filepath.Join(tempDir, "missing"), second}, string(os.PathListSeparator)))
+	expected := []Plugin{
+		{Name: "foo", Path: filepath.Join(first, "minikube-foo")},
+		{Name: "bar", Path: filepath.Join(second, "minikube-bar")},
+		{Name: "foo", Path: filepath.Join(second, "minikube-foo"), ShadowedBy: filepath.Join(first, "minikube-foo")},
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("Expected plugins %+v, got %+v", expected, found)
+	}
+}
+
+func TestVars(t *testing.T) {
+	vars := Env{
+		Profile:    "dev",
+		Kubeconfig: "/home/user/.kube/config",
+		Context:    "minikube",
+		Machine:    "minikube",
+		SSHHost:    "192.168.99.100",
+		SSHPort:    22,
+	}.Vars()
+	expected := []string{
+		"MINIKUBE_PROFILE=dev",
+		"MINIKUBE_KUBECONFIG=/home/user/.kube/config",
+		"MINIKUBE_CONTEXT=minikube",
+		"MINIKUBE_MACHINE_NAME=minikube",
+		"MINIKUBE_MACHINE_SSH_HOST=192.168.99.100",
+		"MINIKUBE_MACHINE_SSH_PORT=22",
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Errorf("Expected %v, got %v", expected, vars)
+	}
+}
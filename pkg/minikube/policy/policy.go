@@ -0,0 +1,166 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy reads the policy the administrators of the machine set for
+// all its users, which constrains the choices of their config and flags, and
+// provides defaults below them.
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	units "github.com/docker/go-units"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Path is the policy file of the machine, it is replaced in tests.
+var Path = defaultPath()
+
+func defaultPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "minikube", "policy.yaml")
+	}
+	return "/etc/minikube/policy.yaml"
+}
+
+// Policy is the policy file. The fields left empty don't constrain anything.
+type Policy struct {
+	// AllowedDrivers are the only VM drivers users can start minikube with
+	AllowedDrivers []string `json:"allowedDrivers,omitempty"`
+	// MaxCPUs, MaxMemory in MB and MaxDiskSize, like 20g, cap the resources
+	// of each VM
+	MaxCPUs     int    `json:"maxCPUs,omitempty"`
+	MaxMemory   int    `json:"maxMemory,omitempty"`
+	MaxDiskSize string `json:"maxDiskSize,omitempty"`
+	// RegistryMirror is added to the registry mirrors of every VM, e.g. the
+	// mirror of the company
+	RegistryMirror string `json:"registryMirror,omitempty"`
+	// DisabledCommands are the commands users can't run, with their
+	// subcommands, like "tunnel" or "addons enable"
+	DisabledCommands []string `json:"disabledCommands,omitempty"`
+	// DisabledAddons are the addons which are never enabled
+	DisabledAddons []string `json:"disabledAddons,omitempty"`
+	// Defaults are values of settings, which the config and the flags of the
+	// users override
+	Defaults map[string]interface{} `json:"defaults,omitempty"`
+}
+
+// Load reads the policy file, a machine without one has an empty policy.
+func Load() (*Policy, error) {
+	data, err := ioutil.ReadFile(Path)
+	if os.IsNotExist(err) {
+		return &Policy{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the policy %s", Path)
+	}
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the policy %s", Path)
+	}
+	if p.MaxDiskSize != "" {
+		if _, err := units.FromHumanSize(p.MaxDiskSize); err != nil {
+			return nil, errors.Wrapf(err, "Invalid maxDiskSize in the policy %s", Path)
+		}
+	}
+	return p, nil
+}
+
+// notAllowed returns the error for a choice the policy forbids.
+func notAllowed(format string, args ...interface{}) error {
+	return fmt.Errorf("%s, by the policy of this machine in %s", fmt.Sprintf(format, args...), Path)
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckDriver returns an error if the driver isn't allowed.
+func (p *Policy) CheckDriver(driver string) error {
+	if len(p.AllowedDrivers) == 0 || contains(p.AllowedDrivers, driver) {
+		return nil
+	}
+	return notAllowed("The %s driver is not allowed, the allowed drivers are %s", driver, strings.Join(p.AllowedDrivers, ", "))
+}
+
+// CheckResources returns an error if a VM with the CPUs, memory and disk, in
+// MB, would exceed the maximums.
+func (p *Policy) CheckResources(cpus, memoryMB, diskMB int) error {
+	if p.MaxCPUs > 0 && cpus > p.MaxCPUs {
+		return notAllowed("%d CPUs exceed the maximum of %d", cpus, p.MaxCPUs)
+	}
+	if p.MaxMemory > 0 && memoryMB > p.MaxMemory {
+		return notAllowed("%dMB of memory exceed the maximum of %dMB", memoryMB, p.MaxMemory)
+	}
+	if p.MaxDiskSize != "" {
+		// Load checked the size
+		size, _ := units.FromHumanSize(p.MaxDiskSize)
+		if max := int(size / units.MB); diskMB > max {
+			return notAllowed("A disk of %dMB exceeds the maximum of %s", diskMB, p.MaxDiskSize)
+		}
+	}
+	return nil
+}
+
+// CheckCommand returns an error if the command, given as its path like
+// "minikube addons enable", or one of its parents is disabled.
+func (p *Policy) CheckCommand(path string) error {
+	fields := strings.Fields(path)
+	if len(fields) == 0 {
+		return nil
+	}
+	command := strings.Join(fields[1:], " ")
+	for _, disabled := range p.DisabledCommands {
+		disabled = strings.Join(strings.Fields(disabled), " ")
+		if disabled != "" && (command == disabled || strings.HasPrefix(command, disabled+" ")) {
+			return notAllowed("\"minikube %s\" is disabled", disabled)
+		}
+	}
+	return nil
+}
+
+// AddonDisabled reports whether the addon is disabled.
+func (p *Policy) AddonDisabled(name string) bool {
+	return contains(p.DisabledAddons, name)
+}
+
+// CheckAddon returns an error if the addon is disabled.
+func (p *Policy) CheckAddon(name string) error {
+	if p.AddonDisabled(name) {
+		return notAllowed("The addon %s is disabled", name)
+	}
+	return nil
+}
+
+// RegistryMirrors returns the registry mirrors of the user, with the mirror
+// of the policy first.
+func (p *Policy) RegistryMirrors(mirrors []string) []string {
+	if p.RegistryMirror == "" || contains(mirrors, p.RegistryMirror) {
+		return mirrors
+	}
+	return append([]string{p.RegistryMirror}, mirrors...)
+}
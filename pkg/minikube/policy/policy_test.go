@@ -0,0 +1,153 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testPolicy = `allowedDrivers: [virtualbox, kvm2]
+maxCPUs: 4
+maxMemory: 8192
+maxDiskSize: 30g
+registryMirror: https://mirror.example.com
+disabledCommands: [tunnel, addons enable]
+disabledAddons: [heapster]
+defaults:
+  memory: 4096
+  vm-driver: kvm2
+`
+
+func withPolicy(t *testing.T, contents string) func() {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := Path
+	Path = filepath.Join(dir, "policy.yaml")
+	if contents != "" {
+		if err := ioutil.WriteFile(Path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		Path = orig
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	defer withPolicy(t, testPolicy)()
+	p, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error loading the policy: %s", err)
+	}
+	if p.MaxCPUs != 4 || p.MaxMemory != 8192 || p.RegistryMirror != "https://mirror.example.com" {
+		t.Errorf("Unexpected policy %+v", p)
+	}
+	if p.Defaults["vm-driver"] != "kvm2" {
+		t.Errorf("Expected the default driver kvm2, got %v", p.Defaults)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	defer withPolicy(t, "")()
+	p, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error without a policy: %s", err)
+	}
+	if err := p.CheckDriver("xhyve"); err != nil {
+		t.Errorf("Expected an empty policy to allow every driver: %s", err)
+	}
+	if err := p.CheckResources(64, 1<<20, 1<<20); err != nil {
+		t.Errorf("Expected an empty policy to allow any resources: %s", err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	defer withPolicy(t, "maxDiskSize: lots\n")()
+	if _, err := Load(); err == nil {
+		t.Errorf("Expected an error for an invalid disk size")
+	}
+}
+
+func TestChecks(t *testing.T) {
+	defer withPolicy(t, testPolicy)()
+	p, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.CheckDriver("kvm2"); err != nil {
+		t.Errorf("Expected kvm2 to be allowed: %s", err)
+	}
+	if err := p.CheckDriver("none"); err == nil {
+		t.Errorf("Expected the none driver not to be allowed")
+	}
+
+	for _, r := range []struct {
+		cpus, memory, disk int
+		allowed            bool
+	}{
+		{4, 8192, 30000, true},
+		{6, 2048, 20000, false},
+		{2, 16384, 20000, false},
+		{2, 2048, 40000, false},
+	} {
+		if err := p.CheckResources(r.cpus, r.memory, r.disk); (err == nil) != r.allowed {
+			t.Errorf("Expected %+v to be allowed: %v, got %v", r, r.allowed, err)
+		}
+	}
+
+	for path, allowed := range map[string]bool{
+		"minikube start":          true,
+		"minikube tunnel":         false,
+		"minikube addons enable":  false,
+		"minikube addons disable": true,
+		"minikube addons":         true,
+	} {
+		if err := p.CheckCommand(path); (err == nil) != allowed {
+			t.Errorf("Expected %q to be allowed: %v, got %v", path, allowed, err)
+		}
+	}
+
+	if err := p.CheckAddon("heapster"); err == nil {
+		t.Errorf("Expected heapster to be disabled")
+	}
+	if err := p.CheckAddon("dashboard"); err != nil {
+		t.Errorf("Expected dashboard to be allowed: %s", err)
+	}
+}
+
+func TestRegistryMirrors(t *testing.T) {
+	p := &Policy{RegistryMirror: "https://mirror.example.com"}
+	for _, test := range []struct {
+		mirrors, expected []string
+	}{
+		{nil, []string{"https://mirror.example.com"}},
+		{[]string{"https://other"}, []string{"https://mirror.example.com", "https://other"}},
+		{[]string{"https://mirror.example.com"}, []string{"https://mirror.example.com"}},
+	} {
+		if got := p.RegistryMirrors(test.mirrors); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Expected %v for %v, got %v", test.expected, test.mirrors, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package portforward forwards local ports to pods and services of the cluster,
+// reconnecting when the pods backing them are restarted.
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubernetes/pkg/api"
+	kubeletportforward "k8s.io/kubernetes/pkg/kubelet/server/portforward"
+	"k8s.io/kubernetes/pkg/util/httpstream"
+	"k8s.io/kubernetes/pkg/util/httpstream/spdy"
+)
+
+// reconnectDelay is the time waited before connecting to a target again
+const reconnectDelay = 2 * time.Second
+
+// Forwarder forwards the ports of a single target
+type Forwarder struct {
+	client corev1.CoreV1Interface
+	config *rest.Config
+	target Target
+	out    io.Writer
+
+	mu        sync.Mutex
+	conn      httpstream.Connection
+	ports     map[int]int
+	requestID int
+}
+
+// NewForwarder creates a forwarder for the target, reporting its progress to out
+func NewForwarder(client corev1.CoreV1Interface, config *rest.Config, target Target, out io.Writer) *Forwarder {
+	return &Forwarder{
+		client: client,
+		config: config,
+		target: target,
+		out:    out,
+	}
+}
+
+// Run listens on the local ports of the target and forwards connections to
+// it until stop is closed. When the connection to the pod is lost, a running
+// pod is looked up again and connections are forwarded to it.
+func (f *Forwarder) Run(stop <-chan struct{}) error {
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for _, m := range f.target.Mappings {
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(m.Local)))
+		if err != nil {
+			return errors.Wrapf(err, "Error listening on port %d", m.Local)
+		}
+		listeners = append(listeners, l)
+		go f.accept(l, m.Local)
+	}
+
+	for {
+		conn, err := f.connect()
+		if err != nil {
+			glog.Infoln(err)
+			fmt.Fprintf(f.out, "Waiting for %s/%s: %s\n", f.target.Kind, f.target.Name, errors.Cause(err))
+		} else {
+			select {
+			case <-conn.CloseChan():
+				fmt.Fprintf(f.out, "Lost connection to %s/%s, reconnecting\n", f.target.Kind, f.target.Name)
+			case <-stop:
+				conn.Close()
+				return nil
+			}
+		}
+		select {
+		case <-time.After(reconnectDelay):
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// connect opens a port forwarding connection to a pod of the target
+func (f *Forwarder) connect() (httpstream.Connection, error) {
+	pod, mappings, err := resolve(f.client, f.target)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := f.dial(pod)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error connecting to pod %s", pod)
+	}
+	ports := map[int]int{}
+	for _, m := range mappings {
+		ports[m.Local] = m.Remote
+		fmt.Fprintf(f.out, "Forwarding from 127.0.0.1:%d -> %s:%d\n", m.Local, pod, m.Remote)
+	}
+	f.mu.Lock()
+	f.conn, f.ports = conn, ports
+	f.mu.Unlock()
+	return conn, nil
+}
+
+// dial upgrades a request to the portforward subresource of the pod to a streaming connection
+func (f *Forwarder) dial(pod string) (httpstream.Connection, error) {
+	tlsConfig, err := rest.TLSConfigFor(f.config)
+	if err != nil {
+		return nil, err
+	}
+	upgrader := spdy.NewRoundTripper(tlsConfig)
+	wrapper, err := rest.HTTPWrappersForConfig(f.config, upgrader)
+	if err != nil {
+		return nil, err
+	}
+	url := f.client.RESTClient().Post().
+		Resource("pods").
+		Namespace(f.target.Namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+	req, err := http.NewRequest("POST", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(httpstream.HeaderConnection, httpstream.HeaderUpgrade)
+	req.Header.Add(httpstream.HeaderUpgrade, spdy.HeaderSpdy31)
+	req.Header.Add(httpstream.HeaderProtocolVersion, kubeletportforward.PortForwardProtocolV1Name)
+	resp, err := wrapper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return upgrader.NewConnection(resp)
+}
+
+func (f *Forwarder) accept(l net.Listener, local int) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			// The listener is closed when the forwarder stops
+			return
+		}
+		go func() {
+			if err := f.handle(conn, local); err != nil {
+				glog.Errorln(err)
+			}
+		}()
+	}
+}
+
+// handle forwards a local connection over a pair of error and data streams
+func (f *Forwarder) handle(conn net.Conn, local int) error {
+	defer conn.Close()
+
+	f.mu.Lock()
+	streamConn, port := f.conn, f.ports[local]
+	f.requestID++
+	requestID := f.requestID
+	f.mu.Unlock()
+	if streamConn == nil || port == 0 {
+		return errors.Errorf("not connected to %s/%s", f.target.Kind, f.target.Name)
+	}
+
+	headers := http.Header{}
+	headers.Set(api.StreamType, api.StreamTypeError)
+	headers.Set(api.PortHeader, strconv.Itoa(port))
+	headers.Set(api.PortForwardRequestIDHeader, strconv.Itoa(requestID))
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating error stream for port %d", local)
+	}
+	// Nothing is written to the error stream
+	errorStream.Close()
+	errorChan := make(chan error)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errorChan <- errors.Wrapf(err, "Error reading from error stream for port %d", local)
+		case len(message) > 0:
+			errorChan <- errors.Errorf("Error forwarding port %d: %s", local, message)
+		}
+		close(errorChan)
+	}()
+
+	headers.Set(api.StreamType, api.StreamTypeData)
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating data stream for port %d", local)
+	}
+
+	localDone := make(chan struct{})
+	remoteDone := make(chan struct{})
+	go func() {
+		// Copy from the pod to the local connection
+		io.Copy(conn, dataStream)
+		close(remoteDone)
+	}()
+	go func() {
+		// Copy from the local connection to the pod, then tell the pod there is no more data
+		defer dataStream.Close()
+		io.Copy(dataStream, conn)
+		close(localDone)
+	}()
+
+	select {
+	case <-remoteDone:
+	case <-localDone:
+		<-remoteDone
+	}
+	dataStream.Reset()
+	return <-errorChan
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/labels"
+)
+
+// Target is a pod or service whose ports are forwarded
+type Target struct {
+	Namespace string
+	// Kind is either pod or service
+	Kind     string
+	Name     string
+	Mappings []Mapping
+}
+
+// Mapping forwards the Local port on the host to the Remote port of the target
+type Mapping struct {
+	Local  int
+	Remote int
+}
+
+// ParseTarget parses a target of the form [pod/]NAME or svc/NAME, service/NAME
+func ParseTarget(namespace, target string) (Target, error) {
+	t := Target{Namespace: namespace, Kind: "pod", Name: target}
+	if parts := strings.SplitN(target, "/", 2); len(parts) == 2 {
+		switch parts[0] {
+		case "pod", "pods", "po":
+		case "service", "services", "svc":
+			t.Kind = "service"
+		default:
+			return Target{}, errors.Errorf("unsupported target %s, only pods and services can be forwarded", target)
+		}
+		t.Name = parts[1]
+	}
+	if t.Name == "" {
+		return Target{}, errors.Errorf("invalid target %s", target)
+	}
+	return t, nil
+}
+
+// ParseMapping parses a port mapping of the form [LOCAL_PORT:]REMOTE_PORT
+func ParseMapping(mapping string) (Mapping, error) {
+	parts := strings.Split(mapping, ":")
+	if len(parts) > 2 {
+		return Mapping{}, errors.Errorf("invalid port mapping %s", mapping)
+	}
+	ports := make([]int, len(parts))
+	for i, p := range parts {
+		port, err := strconv.Atoi(p)
+		if err != nil || port <= 0 || port > 65535 {
+			return Mapping{}, errors.Errorf("invalid port %s in mapping %s", p, mapping)
+		}
+		ports[i] = port
+	}
+	if len(ports) == 1 {
+		return Mapping{Local: ports[0], Remote: ports[0]}, nil
+	}
+	return Mapping{Local: ports[0], Remote: ports[1]}, nil
+}
+
+// ParseArgs parses a list of targets, each followed by its port mappings,
+// e.g. svc/frontend 8080:80 pod/db 5432
+func ParseArgs(namespace string, args []string) ([]Target, error) {
+	var targets []Target
+	for _, arg := range args {
+		if _, err := strconv.Atoi(strings.Replace(arg, ":", "", 1)); err == nil && len(targets) > 0 {
+			m, err := ParseMapping(arg)
+			if err != nil {
+				return nil, err
+			}
+			t := &targets[len(targets)-1]
+			t.Mappings = append(t.Mappings, m)
+			continue
+		}
+		t, err := ParseTarget(namespace, arg)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	for _, t := range targets {
+		if len(t.Mappings) == 0 {
+			return nil, errors.Errorf("no ports given for %s/%s", t.Kind, t.Name)
+		}
+	}
+	return targets, nil
+}
+
+// resolve returns the name of a running pod backing the target, along with
+// the mappings translated to the ports of the pod.
+func resolve(client corev1.CoreV1Interface, t Target) (string, []Mapping, error) {
+	if t.Kind == "pod" {
+		pod, err := client.Pods(t.Namespace).Get(t.Name)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "Error getting pod %s", t.Name)
+		}
+		if pod.Status.Phase != v1.PodRunning {
+			return "", nil, errors.Errorf("pod %s is not running", t.Name)
+		}
+		return pod.Name, t.Mappings, nil
+	}
+
+	svc, err := client.Services(t.Namespace).Get(t.Name)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "Error getting service %s", t.Name)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", nil, errors.Errorf("service %s has no selector", t.Name)
+	}
+	pods, err := client.Pods(t.Namespace).List(v1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set(svc.Spec.Selector)).String(),
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "Error listing pods of service %s", t.Name)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		mappings, err := podMappings(svc, &pod, t.Mappings)
+		if err != nil {
+			return "", nil, err
+		}
+		return pod.Name, mappings, nil
+	}
+	return "", nil, errors.Errorf("no running pods for service %s", t.Name)
+}
+
+// podMappings translates mappings to service ports into mappings to the target ports of the pod
+func podMappings(svc *v1.Service, pod *v1.Pod, mappings []Mapping) ([]Mapping, error) {
+	var result []Mapping
+	for _, m := range mappings {
+		found := false
+		for _, p := range svc.Spec.Ports {
+			if int(p.Port) != m.Remote {
+				continue
+			}
+			found = true
+			port := p.TargetPort.IntValue()
+			if port == 0 && p.TargetPort.StrVal != "" {
+				port = namedPort(pod, p.TargetPort.StrVal)
+			}
+			if port == 0 {
+				port = int(p.Port)
+			}
+			result = append(result, Mapping{Local: m.Local, Remote: port})
+		}
+		if !found {
+			return nil, errors.Errorf("service %s has no port %d", svc.Name, m.Remote)
+		}
+	}
+	return result, nil
+}
+
+func namedPort(pod *v1.Pod, name string) int {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == name {
+				return int(p.ContainerPort)
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/util/intstr"
+)
+
+func TestParseArgs(t *testing.T) {
+	var tests = []struct {
+		description string
+		args        []string
+		expected    []Target
+		shouldErr   bool
+	}{
+		{
+			description: "pod with a single port",
+			args:        []string{"db", "5432"},
+			expected: []Target{
+				{Namespace: "default", Kind: "pod", Name: "db", Mappings: []Mapping{{5432, 5432}}},
+			},
+		},
+		{
+			description: "multiple targets",
+			args:        []string{"svc/frontend", "8080:80", "8443:443", "pod/db", "5432"},
+			expected: []Target{
+				{Namespace: "default", Kind: "service", Name: "frontend", Mappings: []Mapping{{8080, 80}, {8443, 443}}},
+				{Namespace: "default", Kind: "pod", Name: "db", Mappings: []Mapping{{5432, 5432}}},
+			},
+		},
+		{
+			description: "target without ports",
+			args:        []string{"svc/frontend", "pod/db", "5432"},
+			shouldErr:   true,
+		},
+		{
+			description: "unsupported kind",
+			args:        []string{"deployment/db", "5432"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid port",
+			args:        []string{"db", "70000"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			targets, err := ParseArgs("default", test.args)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if !test.shouldErr && !reflect.DeepEqual(targets, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, targets)
+			}
+		})
+	}
+}
+
+func TestPodMappings(t *testing.T) {
+	svc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 443, TargetPort: intstr.FromString("https")},
+			},
+		},
+	}
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+			},
+		},
+	}
+
+	mappings, err := podMappings(svc, pod, []Mapping{{9080, 80}, {9443, 443}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []Mapping{{9080, 8080}, {9443, 8443}}
+	if !reflect.DeepEqual(mappings, expected) {
+		t.Errorf("Expected %v, got %v", expected, mappings)
+	}
+
+	if _, err := podMappings(svc, pod, []Mapping{{9000, 9000}}); err == nil {
+		t.Errorf("Expected error for a port not exposed by the service")
+	}
+}
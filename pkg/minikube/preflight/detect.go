@@ -0,0 +1,147 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// driverPriorities ranks the drivers picked when none is configured, the
+// highest first: the hypervisors of the platforms, then those installed
+// separately, then the containers. The none and ssh drivers are never
+// picked, they change this machine or need another one.
+var driverPriorities = map[string]int{
+	"hyperkit":     9,
+	"kvm2":         9,
+	"hyperv":       9,
+	"virtualbox":   8,
+	"vmwarefusion": 7,
+	"qemu":         6,
+	"docker":       5,
+	"podman":       4,
+	"kvm":          3,
+	"xhyve":        3,
+}
+
+// driverAccess are the commands checking that the user may use the driver:
+// that the daemon runs and its socket can be reached, or that the user has
+// the rights to manage the VMs
+var driverAccess = map[string][]string{
+	"docker": {"docker", "info"},
+	"podman": {"podman", "info"},
+	"kvm":    {"virsh", "-c", "qemu:///system", "list"},
+	"kvm2":   {"virsh", "-c", "qemu:///system", "list"},
+	"hyperv": {"powershell", "-NoProfile", "-NonInteractive", "Get-VM"},
+}
+
+var (
+	runCommand = func(name string, args ...string) error {
+		return exec.Command(name, args...).Run()
+	}
+	virtualization = Virtualization
+)
+
+// Candidate is a driver probed on this host, with the results of its checks.
+type Candidate struct {
+	Driver   string
+	Priority int
+	Results  []Result
+}
+
+// Healthy returns whether none of the checks of the driver failed.
+func (c Candidate) Healthy() bool {
+	return !Report(c.Results).Failed()
+}
+
+// degraded returns whether a check of the driver warned, as qemu does when
+// it emulates the VM.
+func (c Candidate) degraded() bool {
+	for _, r := range c.Results {
+		if r.Status == Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// Probe checks that the driver is supported and installed, that the host can
+// run its VMs, and that the user may use it.
+func Probe(driver string) Candidate {
+	c := Candidate{Driver: driver, Priority: driverPriorities[driver]}
+	c.Results = append(c.Results, Driver(driver))
+	if !c.Healthy() {
+		return c
+	}
+	if !constants.IsContainerDriver(driver) {
+		c.Results = append(c.Results, virtualization(driver))
+	}
+	if cmd, ok := driverAccess[driver]; ok && c.Healthy() {
+		if err := runCommand(cmd[0], cmd[1:]...); err != nil {
+			c.Results = append(c.Results, Result{Check: "access", Status: Failed,
+				Message: fmt.Sprintf("%s failed: %s, check that the service runs and that the user may use it", strings.Join(cmd, " "), err)})
+		} else {
+			c.Results = append(c.Results, Result{Check: "access", Status: OK, Message: "the user may use " + driver})
+		}
+	}
+	return c
+}
+
+// Rank probes the drivers which can be picked, among those given, and sorts
+// them, the best first: the healthy ones before the others, those with
+// warnings after the rest, then by priority.
+func Rank(drivers []string) []Candidate {
+	var candidates []Candidate
+	for _, d := range drivers {
+		if driverPriorities[d] > 0 {
+			candidates = append(candidates, Probe(d))
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Healthy() != b.Healthy() {
+			return a.Healthy()
+		}
+		if a.degraded() != b.degraded() {
+			return !a.degraded()
+		}
+		return a.Priority > b.Priority
+	})
+	return candidates
+}
+
+// Pick returns the best healthy driver of the ranked candidates, or an error
+// with the reason each of them can't be used.
+func Pick(ranked []Candidate) (string, error) {
+	if len(ranked) > 0 && ranked[0].Healthy() {
+		return ranked[0].Driver, nil
+	}
+	var reasons []string
+	for _, c := range ranked {
+		for _, r := range c.Results {
+			if r.Status == Failed {
+				reasons = append(reasons, fmt.Sprintf("  %s: %s", c.Driver, r.Message))
+				break
+			}
+		}
+	}
+	return "", fmt.Errorf("No driver can be used on this host, install one of those of DRIVERS.md or set --vm-driver:\n%s", strings.Join(reasons, "\n"))
+}
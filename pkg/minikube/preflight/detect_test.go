@@ -0,0 +1,121 @@
+// +build linux
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// fakeHost replaces the probes of the host: the tools installed, the
+// virtualization of the drivers and the commands which fail
+type fakeHost struct {
+	installed map[string]bool
+	virt      map[string]Status
+	failing   map[string]bool
+}
+
+func withFakeHost(f fakeHost) func() {
+	origLookPath, origRun, origVirt := lookPath, runCommand, virtualization
+	lookPath = func(file string) (string, error) {
+		if f.installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	runCommand = func(name string, args ...string) error {
+		if f.failing[name] {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+	virtualization = func(driver string) Result {
+		status, ok := f.virt[driver]
+		if !ok {
+			status = OK
+		}
+		return Result{Check: "virtualization", Status: status, Message: string(status)}
+	}
+	return func() { lookPath, runCommand, virtualization = origLookPath, origRun, origVirt }
+}
+
+func drivers(candidates []Candidate) []string {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Driver)
+	}
+	return names
+}
+
+func TestRank(t *testing.T) {
+	// The drivers supported on Linux
+	all := constants.SupportedVMDrivers[:]
+	var tests = []struct {
+		description string
+		host        fakeHost
+		expected    string
+	}{
+		{
+			description: "the hypervisor of the platform first",
+			host:        fakeHost{installed: map[string]bool{"VBoxManage": true, "virsh": true, "docker": true}},
+			expected:    "kvm2",
+		},
+		{
+			description: "without access to libvirt",
+			host: fakeHost{installed: map[string]bool{"VBoxManage": true, "virsh": true, "docker": true},
+				failing: map[string]bool{"virsh": true}},
+			expected: "virtualbox",
+		},
+		{
+			description: "without virtualization",
+			host: fakeHost{installed: map[string]bool{"VBoxManage": true, "qemu-system-x86_64": true, "docker": true},
+				virt: map[string]Status{"virtualbox": Failed, "qemu": Warning}},
+			expected: "docker",
+		},
+		{
+			description: "with the docker daemon stopped",
+			host: fakeHost{installed: map[string]bool{"qemu-system-x86_64": true, "docker": true},
+				virt: map[string]Status{"qemu": Warning}, failing: map[string]bool{"docker": true}},
+			expected: "qemu",
+		},
+	}
+	for _, test := range tests {
+		restore := withFakeHost(test.host)
+		ranked := Rank(all)
+		restore()
+		driver, err := Pick(ranked)
+		if err != nil || driver != test.expected {
+			t.Errorf("%s: expected %s, got %s, %v, ranked %v", test.description, test.expected, driver, err, drivers(ranked))
+		}
+		for _, c := range ranked {
+			if c.Driver == "none" || c.Driver == "ssh" {
+				t.Errorf("%s: expected the %s driver not to be picked", test.description, c.Driver)
+			}
+		}
+	}
+
+	defer withFakeHost(fakeHost{})()
+	_, err := Pick(Rank(all))
+	if err == nil || !strings.Contains(err.Error(), "not in the PATH") {
+		t.Errorf("Expected an error with the missing tools, got %v", err)
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight checks that minikube start can create the cluster on
+// this host, without changing anything.
+package preflight
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/usage"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	OK      Status = "ok"
+	Warning Status = "warn"
+	Failed  Status = "fail"
+	Skipped Status = "skip"
+)
+
+// Result is the outcome of a check, with what was found
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("[%-4s] %s: %s", r.Status, r.Check, r.Message)
+}
+
+// Report holds the results of all the checks
+type Report []Result
+
+// Failed returns whether any check failed.
+func (r Report) Failed() bool {
+	for _, result := range r {
+		if result.Status == Failed {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes a line per result to w.
+func (r Report) Print(w io.Writer) {
+	for _, result := range r {
+		fmt.Fprintln(w, result)
+	}
+}
+
+// Check returns the result of the check named name: a failure with the
+// message of err if any, ok with message otherwise.
+func Check(name string, err error, message string) Result {
+	if err != nil {
+		return Result{Check: name, Status: Failed, Message: err.Error()}
+	}
+	return Result{Check: name, Status: OK, Message: message}
+}
+
+var lookPath = exec.LookPath
+
+// qemuEmulationMessage warns that qemu emulates the VM, without hardware
+// virtualization it can use
+const qemuEmulationMessage = "qemu will emulate the VM, which is several times slower"
+
+// driverTools are the commands each driver runs to manage the VM, or the
+// plugins it is run as
+var driverTools = map[string][]string{
+	"virtualbox":   {"VBoxManage"},
+	"vmwarefusion": {"vmrun"},
+	"kvm":          {"docker-machine-driver-kvm", "virsh"},
+	"kvm2":         {"virsh"},
+	"xhyve":        {"docker-machine-driver-xhyve"},
+	"hyperkit":     {"docker-machine-driver-hyperkit", "hyperkit"},
+	"hyperv":       {"powershell"},
+	"qemu":         {"qemu-system-x86_64"},
+	"docker":       {"docker"},
+	"podman":       {"podman"},
+	"none":         {"systemctl"},
+}
+
+// Driver checks that the driver is supported on this platform, and that the
+// tools it needs are installed.
+func Driver(name string) Result {
+	supported := false
+	for _, d := range constants.SupportedVMDrivers {
+		if d == name {
+			supported = true
+		}
+	}
+	if !supported {
+		return Result{Check: "driver", Status: Failed,
+			Message: fmt.Sprintf("%s is not supported on this platform, expected one of %v, see DRIVERS.md", name, constants.SupportedVMDrivers)}
+	}
+	var found []string
+	for _, tool := range driverTools[name] {
+		path, err := lookPath(tool)
+		if err != nil {
+			return Result{Check: "driver", Status: Failed,
+				Message: fmt.Sprintf("%s needs %s, which is not in the PATH, see DRIVERS.md", name, tool)}
+		}
+		found = append(found, path)
+	}
+	return Result{Check: "driver", Status: OK, Message: fmt.Sprintf("%s, using %s", name, strings.Join(found, ", "))}
+}
+
+// hasVirtualizationFlags returns whether the flags of the CPUs listed in
+// /proc/cpuinfo include Intel VT-x or AMD-V.
+func hasVirtualizationFlags(cpuinfo string) bool {
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) != "flags" {
+			continue
+		}
+		for _, flag := range strings.Fields(parts[1]) {
+			if flag == "vmx" || flag == "svm" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Resources checks the allocation of the VM, added to those of the other
+// profiles in total, against the limits set in the config and the capacity
+// of the host.
+func Resources(allocation, total, limits, capacity usage.Allocation) []Result {
+	requested := fmt.Sprintf("%d CPUs, %dMB of memory and %dMB of disk", allocation.CPUs, allocation.MemoryMB, allocation.DiskMB)
+	if err := usage.CheckLimits(total, limits); err != nil {
+		return []Result{{Check: "resources", Status: Failed, Message: err.Error()}}
+	}
+	warnings := usage.Oversubscribed(total, capacity)
+	if len(warnings) == 0 {
+		return []Result{{Check: "resources", Status: OK, Message: requested + " fit on the host"}}
+	}
+	var results []Result
+	for _, w := range warnings {
+		results = append(results, Result{Check: "resources", Status: Warning, Message: w})
+	}
+	return results
+}
+
+// proxyVars are the environment variables holding the proxies used by
+// minikube, kubectl and the docker client, in upper and lower case
+var proxyVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"}
+
+// Proxy checks the proxies set in the environment, which must be URLs, and
+// the VM network must be excluded from with NO_PROXY, or the apiserver is
+// reached through the proxy. vmNetwork is empty when it is not known.
+func Proxy(getenv func(string) string, vmNetwork string) []Result {
+	var results []Result
+	proxied := false
+	for _, v := range proxyVars {
+		value := getenv(v)
+		if value == "" {
+			continue
+		}
+		proxied = true
+		if u, err := url.Parse(value); err != nil || u.Host == "" {
+			results = append(results, Result{Check: "proxy", Status: Failed, Message: fmt.Sprintf("%s=%s is not a URL, like http://proxy.example.com:3128", v, value)})
+		}
+	}
+	if !proxied {
+		return []Result{{Check: "proxy", Status: OK, Message: "no proxy set"}}
+	}
+	// The network of the VM is only known ahead for some drivers
+	if vmNetwork == "" {
+		return append(results, Result{Check: "proxy", Status: OK, Message: "a proxy is set, the IP of the VM may need to be added to NO_PROXY"})
+	}
+	noProxy := getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = getenv("no_proxy")
+	}
+	if !excludes(noProxy, vmNetwork) {
+		results = append(results, Result{Check: "proxy", Status: Warning,
+			Message: fmt.Sprintf("a proxy is set but NO_PROXY doesn't exclude the VM network %s, add it or the IP of the VM for kubectl to reach the apiserver", vmNetwork)})
+	}
+	if len(results) == 0 {
+		results = append(results, Result{Check: "proxy", Status: OK, Message: "the VM network is excluded with NO_PROXY"})
+	}
+	return results
+}
+
+// excludes returns whether the NO_PROXY list includes the network, or an
+// address of it.
+func excludes(noProxy, network string) bool {
+	_, ipnet, err := net.ParseCIDR(network)
+	if err != nil {
+		return false
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == network {
+			return true
+		}
+		if ip := net.ParseIP(entry); ip != nil && ipnet.Contains(ip) {
+			return true
+		}
+		if _, n, err := net.ParseCIDR(entry); err == nil && n.Contains(ipnet.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// Download checks that the file at url can be downloaded, through the
+// proxies set in the environment.
+func Download(name, url string) Result {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Head(url)
+	if err != nil {
+		return Result{Check: name, Status: Failed, Message: fmt.Sprintf("%s can't be downloaded: %s", url, err)}
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return Result{Check: name, Status: Failed, Message: fmt.Sprintf("%s can't be downloaded: %s", url, resp.Status)}
+	}
+	return Result{Check: name, Status: OK, Message: fmt.Sprintf("%s can be downloaded", url)}
+}
+
+// Port checks that nothing listens on the port of the host address, which
+// minikube would listen on.
+func Port(name, address string, port int) Result {
+	l, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return Result{Check: name, Status: Failed, Message: fmt.Sprintf("port %d of %s can't be listened on: %s", port, address, err)}
+	}
+	l.Close()
+	return Result{Check: name, Status: OK, Message: fmt.Sprintf("port %d of %s is free", port, address)}
+}
+
+// dockerVersionPattern matches the output of docker --version, e.g. Docker
+// version 17.06.0-ce, build 02c1d87
+var dockerVersionPattern = regexp.MustCompile(`^Docker version ([0-9]+)\.[^, ]*`)
+
+// dockerClientOutput returns the output of docker --version
+var dockerClientOutput = func() (string, error) {
+	out, err := exec.Command("docker", "--version").Output()
+	return string(out), err
+}
+
+// DockerClient checks that the docker client of the host can talk to the
+// daemon of the VM with the API version docker-env pins, instead of failing
+// each command once the environment is set.
+func DockerClient(apiVersion string) Result {
+	if _, err := lookPath("docker"); err != nil {
+		return Result{Check: "docker client", Status: Skipped, Message: "no docker client in the PATH, docker-env is not used"}
+	}
+	out, err := dockerClientOutput()
+	if err != nil {
+		return Result{Check: "docker client", Status: Warning, Message: fmt.Sprintf("docker --version failed: %s", err)}
+	}
+	return dockerClient(strings.TrimSpace(out), apiVersion)
+}
+
+func dockerClient(output, apiVersion string) Result {
+	m := dockerVersionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return Result{Check: "docker client", Status: Skipped, Message: fmt.Sprintf("%q is not a docker client version", output)}
+	}
+	version := strings.TrimPrefix(m[0], "Docker version ")
+	// The clients from 25.0 on dropped the API versions before 1.24, the
+	// earlier ones talk any version from 1.12 on
+	major, _ := strconv.Atoi(m[1])
+	if major >= 25 && apiOlder(apiVersion, "1.24") {
+		return Result{Check: "docker client", Status: Warning,
+			Message: fmt.Sprintf("docker %s only talks API 1.24 and later, the daemon of the VM talks %s: the commands run in the environment of minikube docker-env will fail with \"client version %s is too old\", install a docker client older than 25.0 to use it", version, apiVersion, apiVersion)}
+	}
+	return Result{Check: "docker client", Status: OK, Message: fmt.Sprintf("docker %s, minikube docker-env sets DOCKER_API_VERSION=%s for the daemon of the VM", version, apiVersion)}
+}
+
+// apiOlder returns whether the API version a, e.g. 1.23, is older than b.
+func apiOlder(a, b string) bool {
+	parse := func(v string) (int, int) {
+		parts := strings.SplitN(v, ".", 2)
+		major, _ := strconv.Atoi(parts[0])
+		minor := 0
+		if len(parts) == 2 {
+			minor, _ = strconv.Atoi(parts[1])
+		}
+		return major, minor
+	}
+	aMajor, aMinor := parse(a)
+	bMajor, bMinor := parse(b)
+	return aMajor < bMajor || (aMajor == bMajor && aMinor < bMinor)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/usage"
+)
+
+func TestDriver(t *testing.T) {
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	installed := map[string]bool{}
+	lookPath = func(file string) (string, error) {
+		if installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	driver := constants.SupportedVMDrivers[0]
+	if r := Driver(driver); r.Status != Failed || len(driverTools[driver]) == 0 {
+		t.Errorf("Expected %s to fail without its tools, got %s", driver, r)
+	}
+	for _, tool := range driverTools[driver] {
+		installed[tool] = true
+	}
+	if r := Driver(driver); r.Status != OK {
+		t.Errorf("Expected %s to pass with its tools, got %s", driver, r)
+	}
+	if r := Driver("parallels"); r.Status != Failed {
+		t.Errorf("Expected an unsupported driver to fail, got %s", r)
+	}
+}
+
+func TestHasVirtualizationFlags(t *testing.T) {
+	var tests = []struct {
+		cpuinfo  string
+		expected bool
+	}{
+		{"processor\t: 0\nflags\t\t: fpu vme de pse vmx sse2\n", true},
+		{"processor\t: 0\nflags\t\t: fpu svm lm\n", true},
+		{"processor\t: 0\nflags\t\t: fpu vme de pse sse2\n", false},
+		{"processor\t: 0\nvmx flags\t: ept vpid\n", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if actual := hasVirtualizationFlags(test.cpuinfo); actual != test.expected {
+			t.Errorf("Expected %t for %q, got %t", test.expected, test.cpuinfo, actual)
+		}
+	}
+}
+
+func TestResources(t *testing.T) {
+	allocation := usage.Allocation{CPUs: 2, MemoryMB: 2048, DiskMB: 20000}
+	capacity := usage.Allocation{CPUs: 4, MemoryMB: 8192, DiskMB: 100000}
+	var tests = []struct {
+		total    usage.Allocation
+		limits   usage.Allocation
+		expected Status
+	}{
+		{allocation, usage.Allocation{}, OK},
+		{usage.Allocation{CPUs: 6, MemoryMB: 4096, DiskMB: 40000}, usage.Allocation{}, Warning},
+		{usage.Allocation{CPUs: 4, MemoryMB: 4096, DiskMB: 40000}, usage.Allocation{MemoryMB: 3072}, Failed},
+	}
+	for _, test := range tests {
+		results := Resources(allocation, test.total, test.limits, capacity)
+		if len(results) == 0 || results[0].Status != test.expected {
+			t.Errorf("Expected %s for %+v with limits %+v, got %v", test.expected, test.total, test.limits, results)
+		}
+	}
+}
+
+func TestProxy(t *testing.T) {
+	network := "192.168.99.0/24"
+	var tests = []struct {
+		env      map[string]string
+		expected []Status
+	}{
+		{map[string]string{}, []Status{OK}},
+		{map[string]string{"HTTP_PROXY": "http://proxy:3128", "NO_PROXY": "localhost,192.168.99.0/24"}, []Status{OK}},
+		{map[string]string{"https_proxy": "http://proxy:3128", "no_proxy": "192.168.99.100"}, []Status{OK}},
+		{map[string]string{"HTTPS_PROXY": "http://proxy:3128", "NO_PROXY": "192.168.0.0/16"}, []Status{OK}},
+		{map[string]string{"HTTP_PROXY": "http://proxy:3128"}, []Status{Warning}},
+		{map[string]string{"HTTP_PROXY": "proxy:3128", "NO_PROXY": "192.168.99.0/24"}, []Status{Failed}},
+	}
+	if r := Proxy(func(string) string { return "http://proxy:3128" }, ""); r[0].Status != OK {
+		t.Errorf("Expected no NO_PROXY check for an unknown network, got %v", r)
+	}
+	for _, test := range tests {
+		results := Proxy(func(k string) string { return test.env[k] }, network)
+		var actual []Status
+		for _, r := range results {
+			actual = append(actual, r.Status)
+		}
+		if len(actual) != len(test.expected) || actual[0] != test.expected[0] {
+			t.Errorf("Expected %v for %v, got %v", test.expected, test.env, results)
+		}
+	}
+}
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/minikube.iso" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	if r := Download("iso", server.URL+"/minikube.iso"); r.Status != OK {
+		t.Errorf("Expected the iso to be downloadable, got %s", r)
+	}
+	if r := Download("iso", server.URL+"/missing.iso"); r.Status != Failed {
+		t.Errorf("Expected a missing iso to fail, got %s", r)
+	}
+}
+
+func TestPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	if r := Port("apiserver", "127.0.0.1", port); r.Status != Failed {
+		t.Errorf("Expected a port in use to fail, got %s", r)
+	}
+	l.Close()
+	if r := Port("apiserver", "127.0.0.1", port); r.Status != OK {
+		t.Errorf("Expected a free port to pass, got %s", r)
+	}
+}
+
+func TestReportFailed(t *testing.T) {
+	r := Report{{Check: "driver", Status: OK}, {Check: "proxy", Status: Warning}}
+	if r.Failed() {
+		t.Errorf("Expected warnings not to fail the report")
+	}
+	r = append(r, Check("config", errors.New("invalid"), ""))
+	if !r.Failed() {
+		t.Errorf("Expected the report to fail")
+	}
+}
+
+func TestDockerClient(t *testing.T) {
+	var tcs = []struct {
+		output   string
+		expected Status
+	}{
+		{output: "Docker version 1.13.1, build 092cba3", expected: OK},
+		{output: "Docker version 17.06.0-ce, build 02c1d87", expected: OK},
+		{output: "Docker version 24.0.7, build afdd53b", expected: OK},
+		{output: "Docker version 25.0.3, build 4debf41", expected: Warning},
+		{output: "podman version 4.9.3", expected: Skipped},
+	}
+	for _, test := range tcs {
+		if r := dockerClient(test.output, constants.DockerAPIVersion); r.Status != test.expected {
+			t.Errorf("Expected %s for %q, got %s", test.expected, test.output, r)
+		}
+	}
+	if r := dockerClient("Docker version 25.0.3, build 4debf41", "1.24"); r.Status != OK {
+		t.Errorf("Expected a daemon talking API 1.24 to pass, got %s", r)
+	}
+}
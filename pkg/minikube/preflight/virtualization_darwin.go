@@ -0,0 +1,43 @@
+// +build darwin
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// Virtualization checks that the CPU supports hardware virtualization,
+// through the Hypervisor framework xhyve uses.
+func Virtualization(driver string) Result {
+	supported, err := unix.SysctlUint32("kern.hv_support")
+	if err != nil || supported == 0 {
+		if driver == "qemu" {
+			return Result{Check: "virtualization", Status: Warning, Message: qemuEmulationMessage}
+		}
+		return Result{Check: "virtualization", Status: Failed,
+			Message: "the CPU doesn't support hardware virtualization, kern.hv_support is not set"}
+	}
+	// The Hypervisor framework of Apple Silicon can't run the x86_64 ISO
+	if driver == "qemu" && runtime.GOARCH != "amd64" {
+		return Result{Check: "virtualization", Status: Warning, Message: qemuEmulationMessage}
+	}
+	return Result{Check: "virtualization", Status: OK, Message: "the CPU supports hardware virtualization"}
+}
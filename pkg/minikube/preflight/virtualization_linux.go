@@ -0,0 +1,55 @@
+// +build linux
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// Virtualization checks that the CPU supports hardware virtualization, and
+// that the kvm driver can use it. The qemu driver emulates the VM without it.
+func Virtualization(driver string) Result {
+	cpuinfo, err := ioutil.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return Result{Check: "virtualization", Status: Skipped, Message: fmt.Sprintf("Error reading /proc/cpuinfo: %s", err)}
+	}
+	if !hasVirtualizationFlags(string(cpuinfo)) {
+		if driver == "qemu" {
+			return Result{Check: "virtualization", Status: Warning, Message: qemuEmulationMessage}
+		}
+		return Result{Check: "virtualization", Status: Failed,
+			Message: "the CPU doesn't support VT-x or AMD-V, or it is disabled in the BIOS"}
+	}
+	if driver == "kvm" || driver == "kvm2" {
+		if _, err := os.Stat("/dev/kvm"); err != nil {
+			return Result{Check: "virtualization", Status: Failed,
+				Message: "/dev/kvm doesn't exist, load the kvm_intel or kvm_amd module"}
+		}
+	}
+	if driver == "qemu" {
+		f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+		if err != nil {
+			return Result{Check: "virtualization", Status: Warning, Message: qemuEmulationMessage + ": " + err.Error()}
+		}
+		f.Close()
+	}
+	return Result{Check: "virtualization", Status: OK, Message: "the CPU supports hardware virtualization"}
+}
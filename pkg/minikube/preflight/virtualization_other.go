@@ -0,0 +1,25 @@
+// +build !linux,!darwin
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+// Virtualization can't tell whether hardware virtualization is supported on
+// this platform, the driver reports it when creating the VM.
+func Virtualization(driver string) Result {
+	return Result{Check: "virtualization", Status: Skipped, Message: "can't be checked on this platform"}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package proxy passes the proxies set in the environment of the host to
+// the docker daemon of the VM, and keeps the IP of the VM out of them.
+package proxy
+
+import (
+	"net"
+	"strings"
+)
+
+// EnvVars are the variables of the proxies, which are also read in lower case
+var EnvVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
+
+// lookup returns the value of the variable, in upper or else lower case.
+func lookup(getenv func(string) string, name string) string {
+	if value := getenv(name); value != "" {
+		return value
+	}
+	return getenv(strings.ToLower(name))
+}
+
+// IsSet returns whether an HTTP or HTTPS proxy is set in the environment.
+func IsSet(getenv func(string) string) bool {
+	return lookup(getenv, "HTTP_PROXY") != "" || lookup(getenv, "HTTPS_PROXY") != ""
+}
+
+// isProxyVar returns whether the KEY=VALUE entry sets a proxy variable.
+func isProxyVar(entry string) bool {
+	key := strings.ToUpper(strings.SplitN(entry, "=", 2)[0])
+	for _, v := range EnvVars {
+		if key == v {
+			return true
+		}
+	}
+	return false
+}
+
+// DockerEnv returns the environment of the docker daemon of the VM, KEY=VALUE:
+// the one given, with the proxy variables of the environment it doesn't set,
+// which are returned too.
+func DockerEnv(given []string, getenv func(string) string) ([]string, []string) {
+	set := map[string]bool{}
+	for _, entry := range given {
+		set[strings.ToUpper(strings.SplitN(entry, "=", 2)[0])] = true
+	}
+	if !IsSet(getenv) {
+		return given, nil
+	}
+	env := append([]string{}, given...)
+	var added []string
+	for _, v := range EnvVars {
+		if value := lookup(getenv, v); value != "" && !set[v] {
+			env = append(env, v+"="+value)
+			added = append(added, v)
+		}
+	}
+	return env, added
+}
+
+// MergeEnv returns the saved environment of the docker daemon with its proxy
+// variables replaced by those of env, when it sets any, so that the proxies
+// changed since the VM was created apply.
+func MergeEnv(saved, env []string) []string {
+	var proxies []string
+	for _, entry := range env {
+		if isProxyVar(entry) {
+			proxies = append(proxies, entry)
+		}
+	}
+	if len(proxies) == 0 {
+		return saved
+	}
+	var merged []string
+	for _, entry := range saved {
+		if !isProxyVar(entry) {
+			merged = append(merged, entry)
+		}
+	}
+	return append(merged, proxies...)
+}
+
+// Excludes returns whether the NO_PROXY list excludes the IP: lists it, or a
+// network it is in.
+func Excludes(noProxy, ip string) bool {
+	addr := net.ParseIP(ip)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == ip {
+			return true
+		}
+		if _, n, err := net.ParseCIDR(entry); err == nil && addr != nil && n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// NoProxy returns the NO_PROXY list with the IP, added unless it is
+// already excluded.
+func NoProxy(noProxy, ip string) string {
+	switch {
+	case noProxy == "":
+		return ip
+	case Excludes(noProxy, ip):
+		return noProxy
+	}
+	return noProxy + "," + ip
+}
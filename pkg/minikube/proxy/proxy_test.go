@@ -0,0 +1,75 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestDockerEnv(t *testing.T) {
+	given := []string{"HTTP_PROXY=http://given:3128", "FOO=bar"}
+	if env, added := DockerEnv(given, env(map[string]string{"no_proxy": "localhost"})); !reflect.DeepEqual(env, given) || added != nil {
+		t.Errorf("Expected the environment to be left alone without a proxy, got %v", env)
+	}
+
+	getenv := env(map[string]string{
+		"HTTP_PROXY":  "http://proxy:3128",
+		"https_proxy": "http://proxy:3129",
+		"NO_PROXY":    "localhost",
+	})
+	env, added := DockerEnv(given, getenv)
+	expected := []string{"HTTP_PROXY=http://given:3128", "FOO=bar", "HTTPS_PROXY=http://proxy:3129", "NO_PROXY=localhost"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("Expected %v, got %v", expected, env)
+	}
+	if !reflect.DeepEqual(added, []string{"HTTPS_PROXY", "NO_PROXY"}) {
+		t.Errorf("Expected HTTPS_PROXY and NO_PROXY to be added, got %v", added)
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	saved := []string{"HTTP_PROXY=http://old:3128", "no_proxy=old", "FOO=bar"}
+	if merged := MergeEnv(saved, []string{"FOO=baz"}); !reflect.DeepEqual(merged, saved) {
+		t.Errorf("Expected the saved environment without proxies to apply, got %v", merged)
+	}
+	expected := []string{"FOO=bar", "HTTPS_PROXY=http://new:3128"}
+	if merged := MergeEnv(saved, []string{"HTTPS_PROXY=http://new:3128"}); !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+}
+
+func TestNoProxy(t *testing.T) {
+	var tcs = []struct {
+		noProxy, expected string
+	}{
+		{noProxy: "", expected: "192.168.99.10"},
+		{noProxy: "localhost", expected: "localhost,192.168.99.10"},
+		{noProxy: "localhost,192.168.99.10", expected: "localhost,192.168.99.10"},
+		{noProxy: "192.168.99.0/24", expected: "192.168.99.0/24"},
+		{noProxy: "192.168.99.100", expected: "192.168.99.100,192.168.99.10"},
+	}
+	for _, test := range tcs {
+		if actual := NoProxy(test.noProxy, "192.168.99.10"); actual != test.expected {
+			t.Errorf("Expected %q for %q, got %q", test.expected, test.noProxy, actual)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycreds
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// PullSecrets are the image pull secrets the addon keeps up to date in
+// kube-system, one per registry
+var PullSecrets = []string{"awsecr-cred", "gcr-secret", "dpr-secret"}
+
+// defaultServiceAccount is the service account of the pods which don't name
+// one
+const defaultServiceAccount = "default"
+
+// Client is what propagating the pull secrets needs of the Kubernetes client
+type Client interface {
+	corev1.NamespacesGetter
+	corev1.SecretsGetter
+	corev1.ServiceAccountsGetter
+}
+
+// Propagate copies the pull secrets of the addon to the namespaces matching
+// the patterns, * matching any part of a name, and adds them to the
+// imagePullSecrets of the default service account of each. It returns the
+// namespaces propagated to.
+func Propagate(client Client, patterns []string) ([]string, error) {
+	secrets, err := pullSecrets(client)
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := matchingNamespaces(client, patterns)
+	if err != nil {
+		return nil, err
+	}
+	var propagated []string
+	for _, ns := range namespaces {
+		for _, s := range secrets {
+			if err := copySecret(client, ns, s); err != nil {
+				return propagated, errors.Wrapf(err, "Error copying the secret %s to %s", s.Name, ns)
+			}
+		}
+		if err := addPullSecrets(client, ns, secrets); err != nil {
+			return propagated, errors.Wrapf(err, "Error adding the pull secrets to the service account of %s", ns)
+		}
+		propagated = append(propagated, ns)
+	}
+	return propagated, nil
+}
+
+// pullSecrets returns the pull secrets the addon wrote in kube-system, for
+// the registries configured.
+func pullSecrets(client Client) ([]*v1.Secret, error) {
+	var secrets []*v1.Secret
+	for _, name := range PullSecrets {
+		s, err := client.Secrets(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			// The registry isn't configured
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error getting the secret %s", name)
+		}
+		secrets = append(secrets, s)
+	}
+	if len(secrets) == 0 {
+		return nil, errors.Errorf("No pull secret of the %s addon in %s, enable it with: minikube addons enable %s", AddonName, namespace, AddonName)
+	}
+	return secrets, nil
+}
+
+// matchingNamespaces returns the namespaces matching any of the patterns, but
+// the one of the addon the secrets are copied from.
+func matchingNamespaces(client Client, patterns []string) ([]string, error) {
+	list, err := client.Namespaces().List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing namespaces")
+	}
+	var namespaces []string
+	for _, ns := range list.Items {
+		if ns.Name == namespace {
+			continue
+		}
+		for _, p := range patterns {
+			if ok, err := path.Match(strings.TrimSpace(p), ns.Name); err != nil {
+				return nil, errors.Wrapf(err, "Error matching %q", p)
+			} else if ok {
+				namespaces = append(namespaces, ns.Name)
+				break
+			}
+		}
+	}
+	if len(namespaces) == 0 {
+		return nil, errors.Errorf("No namespace matches %s", strings.Join(patterns, ","))
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// copySecret creates the secret in the namespace, or updates the one there.
+func copySecret(client Client, ns string, s *v1.Secret) error {
+	secrets := client.Secrets(ns)
+	existing, err := secrets.Get(s.Name)
+	if err == nil {
+		existing.Type = s.Type
+		existing.Data = s.Data
+		_, err = secrets.Update(existing)
+		return err
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = secrets.Create(&v1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      s.Name,
+			Namespace: ns,
+			Labels:    map[string]string{"kubernetes.io/minikube-addons": AddonName},
+		},
+		Type: s.Type,
+		Data: s.Data,
+	})
+	return err
+}
+
+// addPullSecrets adds the secrets the default service account of the
+// namespace doesn't pull images with yet.
+func addPullSecrets(client Client, ns string, secrets []*v1.Secret) error {
+	accounts := client.ServiceAccounts(ns)
+	sa, err := accounts.Get(defaultServiceAccount)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, ref := range sa.ImagePullSecrets {
+		names[ref.Name] = true
+	}
+	changed := false
+	for _, s := range secrets {
+		if !names[s.Name] {
+			sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{Name: s.Name})
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	_, err = accounts.Update(sa)
+	return err
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycreds
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+type mockClient struct {
+	namespaces []string
+	secrets    map[string]*v1.Secret
+	accounts   map[string]*v1.ServiceAccount
+}
+
+func (m *mockClient) Namespaces() corev1.NamespaceInterface {
+	return &mockNamespaces{names: m.namespaces}
+}
+
+func (m *mockClient) Secrets(namespace string) corev1.SecretInterface {
+	return &mockSecrets{secrets: m.secrets, namespace: namespace}
+}
+
+func (m *mockClient) ServiceAccounts(namespace string) corev1.ServiceAccountInterface {
+	return &mockServiceAccounts{accounts: m.accounts, namespace: namespace}
+}
+
+type mockNamespaces struct {
+	fake.FakeNamespaces
+	names []string
+}
+
+func (m *mockNamespaces) List(opts v1.ListOptions) (*v1.NamespaceList, error) {
+	list := &v1.NamespaceList{}
+	for _, name := range m.names {
+		list.Items = append(list.Items, v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name}})
+	}
+	return list, nil
+}
+
+type mockSecrets struct {
+	fake.FakeSecrets
+	secrets   map[string]*v1.Secret
+	namespace string
+}
+
+func (m *mockSecrets) Get(name string) (*v1.Secret, error) {
+	s, ok := m.secrets[m.namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(unversioned.GroupResource{Resource: "secrets"}, name)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *mockSecrets) Create(s *v1.Secret) (*v1.Secret, error) {
+	m.secrets[m.namespace+"/"+s.Name] = s
+	return s, nil
+}
+
+func (m *mockSecrets) Update(s *v1.Secret) (*v1.Secret, error) {
+	return m.Create(s)
+}
+
+type mockServiceAccounts struct {
+	fake.FakeServiceAccounts
+	accounts  map[string]*v1.ServiceAccount
+	namespace string
+}
+
+func (m *mockServiceAccounts) Get(name string) (*v1.ServiceAccount, error) {
+	sa := *m.accounts[m.namespace]
+	return &sa, nil
+}
+
+func (m *mockServiceAccounts) Update(sa *v1.ServiceAccount) (*v1.ServiceAccount, error) {
+	m.accounts[m.namespace] = sa
+	return sa, nil
+}
+
+func TestPropagate(t *testing.T) {
+	dockercfg := map[string][]byte{v1.DockerConfigKey: []byte(`{"registry.example.com":{}}`)}
+	client := &mockClient{
+		namespaces: []string{"default", "dev-web", "dev-db", "kube-system", "prod"},
+		secrets: map[string]*v1.Secret{
+			"kube-system/dpr-secret": {ObjectMeta: v1.ObjectMeta{Name: "dpr-secret"}, Type: v1.SecretTypeDockercfg, Data: dockercfg},
+			"dev-db/dpr-secret":      {ObjectMeta: v1.ObjectMeta{Name: "dpr-secret"}, Type: v1.SecretTypeDockercfg},
+		},
+		accounts: map[string]*v1.ServiceAccount{
+			"dev-web": {},
+			"dev-db":  {ImagePullSecrets: []v1.LocalObjectReference{{Name: "dpr-secret"}}},
+		},
+	}
+
+	if _, err := Propagate(client, []string{"nope-*"}); err == nil {
+		t.Errorf("Expected an error when no namespace matches")
+	}
+	namespaces, err := Propagate(client, []string{"dev-*", "kube-*"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"dev-db", "dev-web"}; !reflect.DeepEqual(namespaces, expected) {
+		t.Errorf("Expected the secrets to be propagated to %v, got %v", expected, namespaces)
+	}
+	for _, ns := range namespaces {
+		s, ok := client.secrets[ns+"/dpr-secret"]
+		if !ok || !reflect.DeepEqual(s.Data, dockercfg) {
+			t.Errorf("Expected the secret to be copied to %s, got %v", ns, s)
+		}
+		if refs := client.accounts[ns].ImagePullSecrets; len(refs) != 1 || refs[0].Name != "dpr-secret" {
+			t.Errorf("Expected the default service account of %s to pull with dpr-secret once, got %v", ns, refs)
+		}
+	}
+
+	delete(client.secrets, "kube-system/dpr-secret")
+	if _, err := Propagate(client, []string{"*"}); err == nil {
+		t.Errorf("Expected an error when the addon wrote no pull secret")
+	}
+}
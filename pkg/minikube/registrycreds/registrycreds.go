@@ -0,0 +1,177 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registrycreds records the answers given when enabling the
+// registry-creds addon, and writes the Secrets the addon reads the
+// credentials of the registries from, so that they can be written again for
+// a new cluster without asking.
+package registrycreds
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/security"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+const (
+	// AddonName is the name of the addon
+	AddonName = "registry-creds"
+	// CredentialsFile records the secret answers, in the directory of the
+	// profile, readable only by the user
+	CredentialsFile = "registry-creds.json"
+
+	// unset is the value of the credentials of the registries not used, the
+	// addon expects all of them
+	unset     = "changeme"
+	namespace = "kube-system"
+)
+
+// Answers are the answers given when enabling the addon, empty for the
+// registries not used.
+type Answers struct {
+	// Recorded as addon values, in the config of the profile
+	AWSRegion    string `json:"-"`
+	AWSAccount   string `json:"-"`
+	DockerServer string `json:"-"`
+	DockerUser   string `json:"-"`
+
+	// Recorded in CredentialsFile
+	AWSAccessKeyID     string `json:"awsAccessKeyID,omitempty"`
+	AWSSecretAccessKey string `json:"awsSecretAccessKey,omitempty"`
+	GCRCredentials     string `json:"gcrCredentials,omitempty"`
+	DockerPassword     string `json:"dockerPassword,omitempty"`
+}
+
+// Values returns the answers which are not secret, by addon value key.
+func (a Answers) Values() map[string]string {
+	return map[string]string{
+		"aws-region":    a.AWSRegion,
+		"aws-account":   a.AWSAccount,
+		"docker-server": a.DockerServer,
+		"docker-user":   a.DockerUser,
+	}
+}
+
+// credentialsPath returns the file of the secret answers of the active
+// profile.
+func credentialsPath() string {
+	if profile := config.ActiveProfile(); profile != "" {
+		return filepath.Join(filepath.Dir(config.ProfileConfigFile(profile)), CredentialsFile)
+	}
+	return constants.MakeMiniPath(CredentialsFile)
+}
+
+// Load returns the answers recorded for the active profile, and whether
+// any were.
+func Load() (Answers, bool, error) {
+	var a Answers
+	path := credentialsPath()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return a, false, nil
+	}
+	if err != nil {
+		return a, false, errors.Wrapf(err, "Error reading %s", path)
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return a, false, errors.Wrapf(err, "Error parsing %s", path)
+	}
+	values, err := assets.Addons[AddonName].Values()
+	if err != nil {
+		return a, false, err
+	}
+	a.AWSRegion = values["aws-region"]
+	a.AWSAccount = values["aws-account"]
+	a.DockerServer = values["docker-server"]
+	a.DockerUser = values["docker-user"]
+	return a, true, nil
+}
+
+// SaveCredentials records the secret answers for the active profile, the
+// others are recorded with the config.
+func SaveCredentials(a Answers) error {
+	path := credentialsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "Error creating directory of %s", path)
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, security.FileMode); err != nil {
+		return errors.Wrapf(err, "Error recording the credentials of %s", AddonName)
+	}
+	return nil
+}
+
+// Apply writes the Secrets of the addon from the answers, replacing the
+// previous ones.
+func Apply(a Answers) error {
+	secrets := []struct {
+		name  string
+		cloud string
+		data  map[string]string
+	}{
+		{"registry-creds-ecr", "ecr", map[string]string{
+			"AWS_ACCESS_KEY_ID":     orUnset(a.AWSAccessKeyID),
+			"AWS_SECRET_ACCESS_KEY": orUnset(a.AWSSecretAccessKey),
+			"aws-account":           orUnset(a.AWSAccount),
+			"aws-region":            orUnset(a.AWSRegion),
+		}},
+		{"registry-creds-gcr", "gcr", map[string]string{
+			"application_default_credentials.json": orUnset(a.GCRCredentials),
+		}},
+		{"registry-creds-dpr", "dpr", map[string]string{
+			"DOCKER_PRIVATE_REGISTRY_SERVER":   orUnset(a.DockerServer),
+			"DOCKER_PRIVATE_REGISTRY_USER":     orUnset(a.DockerUser),
+			"DOCKER_PRIVATE_REGISTRY_PASSWORD": orUnset(a.DockerPassword),
+		}},
+	}
+	for _, s := range secrets {
+		labels := map[string]string{
+			"app":                           AddonName,
+			"cloud":                         s.cloud,
+			"kubernetes.io/minikube-addons": AddonName,
+		}
+		if err := service.CreateSecret(namespace, s.name, s.data, labels); err != nil {
+			return errors.Wrapf(err, "Error creating the secret %s", s.name)
+		}
+	}
+	return nil
+}
+
+// Delete removes the Secrets of the addon, the recorded answers are kept for
+// the addon to be enabled again.
+func Delete() {
+	service.DeleteSecret(namespace, "registry-creds-ecr")
+	service.DeleteSecret(namespace, "registry-creds-gcr")
+	service.DeleteSecret(namespace, "registry-creds-dpr")
+}
+
+func orUnset(s string) string {
+	if s == "" {
+		return unset
+	}
+	return s
+}
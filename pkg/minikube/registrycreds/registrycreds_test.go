@@ -0,0 +1,77 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycreds
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/security"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestLoad(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	// The global config file is resolved before the temp dir is set, the
+	// one of a profile is not
+	defer viper.Reset()
+	viper.Set(config.ProfileFlag, "creds-test")
+
+	if _, recorded, err := Load(); err != nil || recorded {
+		t.Fatalf("Expected no answers to be recorded, got %v, %v", recorded, err)
+	}
+
+	want := Answers{
+		DockerServer:   "registry.example.com",
+		DockerUser:     "dev",
+		DockerPassword: "secret",
+	}
+	if err := SaveCredentials(want); err != nil {
+		t.Fatalf("Unexpected error recording the credentials: %s", err)
+	}
+	info, err := os.Stat(credentialsPath())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if info.Mode().Perm() != security.FileMode {
+		t.Errorf("Expected the credentials to have mode %v, got %v", security.FileMode, info.Mode().Perm())
+	}
+	data, err := ioutil.ReadFile(credentialsPath())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if strings.Contains(string(data), want.DockerServer) {
+		t.Errorf("Expected the answers which are not secret to be recorded with the config, got %s", data)
+	}
+
+	profile := `{"addon.registry-creds.docker-server": "registry.example.com", "addon.registry-creds.docker-user": "dev"}`
+	if err := ioutil.WriteFile(config.ProfileConfigFile("creds-test"), []byte(profile), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	got, recorded, err := Load()
+	if err != nil || !recorded {
+		t.Fatalf("Expected the answers to be recorded, got %v, %v", recorded, err)
+	}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scan scans the images of the minikube VM for vulnerabilities, with a
+// scanner running in a container of the VM, for minikube image scan.
+package scan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severities are the severities of the findings, from the lowest.
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Finding is a vulnerability found in a package of an image.
+type Finding struct {
+	Image     string
+	ID        string
+	Severity  string
+	Package   string
+	Installed string
+	Fixed     string
+	Title     string
+}
+
+// Scanner scans the images of the docker daemon of the VM.
+type Scanner interface {
+	// Command returns the command scanning the image in the VM, printing the
+	// findings of the severities.
+	Command(image string, severities []string) string
+	// Parse returns the findings printed by the command.
+	Parse(image, out string) ([]Finding, error)
+	// Image is the image of the scanner, which is not scanned itself.
+	Image() string
+}
+
+// scanners are the scanners by name, created with their image.
+var scanners = map[string]struct {
+	defaultImage string
+	new          func(image string) Scanner
+}{
+	"trivy": {TrivyImage, func(image string) Scanner { return &trivy{image: image} }},
+}
+
+// Names returns the names of the scanners, sorted.
+func Names() []string {
+	var names []string
+	for n := range scanners {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the scanner of the name, running the image or the default image
+// of the scanner when empty.
+func Get(name, image string) (Scanner, error) {
+	s, ok := scanners[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown scanner %s, the scanners are %s", name, strings.Join(Names(), ", "))
+	}
+	if image == "" {
+		image = s.defaultImage
+	}
+	if err := ValidateImageName(image); err != nil {
+		return nil, err
+	}
+	return s.new(image), nil
+}
+
+var validImageName = regexp.MustCompile(`^[a-zA-Z0-9][-_./:@a-zA-Z0-9]*$`)
+
+// ValidateImageName checks that the image name can be passed to the scanner
+// in the command run in the VM.
+func ValidateImageName(image string) error {
+	if !validImageName.MatchString(image) {
+		return fmt.Errorf("%q is not a valid image name", image)
+	}
+	return nil
+}
+
+// ParseSeverities parses a comma separated list of severities, in any case.
+func ParseSeverities(s string) ([]string, error) {
+	var severities []string
+	for _, sev := range strings.Split(s, ",") {
+		sev = strings.ToUpper(strings.TrimSpace(sev))
+		if sev == "" {
+			continue
+		}
+		if severityRank(sev) < 0 {
+			return nil, fmt.Errorf("Unknown severity %s, the severities are %s", sev, strings.Join(Severities, ","))
+		}
+		severities = append(severities, sev)
+	}
+	if len(severities) == 0 {
+		return nil, fmt.Errorf("No severity given, the severities are %s", strings.Join(Severities, ","))
+	}
+	return severities, nil
+}
+
+// Filter returns the findings of the severities. Scanners are asked for those
+// severities only, but not all of them can filter.
+func Filter(findings []Finding, severities []string) []Finding {
+	wanted := map[string]bool{}
+	for _, s := range severities {
+		wanted[s] = true
+	}
+	var filtered []Finding
+	for _, f := range findings {
+		if wanted[f.Severity] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// Sort sorts the findings by image, then from the most severe, then by id.
+func Sort(findings []Finding) {
+	sort.Sort(byImageAndSeverity(findings))
+}
+
+type byImageAndSeverity []Finding
+
+func (f byImageAndSeverity) Len() int      { return len(f) }
+func (f byImageAndSeverity) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f byImageAndSeverity) Less(i, j int) bool {
+	if f[i].Image != f[j].Image {
+		return f[i].Image < f[j].Image
+	}
+	if ri, rj := severityRank(f[i].Severity), severityRank(f[j].Severity); ri != rj {
+		return ri > rj
+	}
+	return f[i].ID < f[j].ID
+}
+
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSeverities(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected []string
+		err      bool
+	}{
+		{input: "HIGH,CRITICAL", expected: []string{"HIGH", "CRITICAL"}},
+		{input: "medium, high", expected: []string{"MEDIUM", "HIGH"}},
+		{input: "HIGH,SEVERE", err: true},
+		{input: ",", err: true},
+	}
+	for _, test := range tests {
+		severities, err := ParseSeverities(test.input)
+		if (err != nil) != test.err {
+			t.Errorf("ParseSeverities(%q): expected error %t, got %v", test.input, test.err, err)
+		}
+		if !test.err && !reflect.DeepEqual(severities, test.expected) {
+			t.Errorf("ParseSeverities(%q): expected %v, got %v", test.input, test.expected, severities)
+		}
+	}
+}
+
+func TestFilterAndSort(t *testing.T) {
+	findings := []Finding{
+		{Image: "b:1", ID: "CVE-3", Severity: "LOW"},
+		{Image: "a:1", ID: "CVE-2", Severity: "HIGH"},
+		{Image: "a:1", ID: "CVE-1", Severity: "MEDIUM"},
+		{Image: "a:1", ID: "CVE-4", Severity: "CRITICAL"},
+	}
+	filtered := Filter(findings, []string{"MEDIUM", "HIGH", "CRITICAL"})
+	Sort(filtered)
+	var ids []string
+	for _, f := range filtered {
+		ids = append(ids, f.ID)
+	}
+	if expected := []string{"CVE-4", "CVE-2", "CVE-1"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestGet(t *testing.T) {
+	s, err := Get("trivy", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if s.Image() != TrivyImage {
+		t.Errorf("Expected the default image %s, got %s", TrivyImage, s.Image())
+	}
+	if s, err := Get("trivy", "registry.local/trivy:dev"); err != nil || s.Image() != "registry.local/trivy:dev" {
+		t.Errorf("Expected the given image, got %v, %v", s, err)
+	}
+	if _, err := Get("clair", ""); err == nil {
+		t.Errorf("Expected an error for an unknown scanner")
+	}
+	if _, err := Get("trivy", "trivy; rm -rf /"); err == nil {
+		t.Errorf("Expected an error for an invalid image")
+	}
+}
+
+func TestTrivyParse(t *testing.T) {
+	s, _ := Get("trivy", "")
+	out := `{"Results": [{"Target": "alpine:3.4 (alpine 3.4.6)", "Vulnerabilities": [
+	  {"VulnerabilityID": "CVE-2018-0732", "PkgName": "libssl1.0", "InstalledVersion": "1.0.2n-r0",
+	   "FixedVersion": "1.0.2o-r1", "Severity": "HIGH", "Title": "openssl: Malicious server can send large prime"}
+	]}, {"Target": "app"}]}`
+	findings, err := s.Parse("alpine:3.4", out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []Finding{{
+		Image:     "alpine:3.4",
+		ID:        "CVE-2018-0732",
+		Severity:  "HIGH",
+		Package:   "libssl1.0",
+		Installed: "1.0.2n-r0",
+		Fixed:     "1.0.2o-r1",
+		Title:     "openssl: Malicious server can send large prime",
+	}}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected %v, got %v", expected, findings)
+	}
+
+	if _, err := s.Parse("alpine:3.4", "FATAL error"); err == nil {
+		t.Errorf("Expected an error for an invalid report")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// TrivyImage is the default image of the trivy scanner.
+const TrivyImage = "aquasec/trivy:0.50.1"
+
+// trivyCachePath keeps the vulnerability database of trivy on the disk of the
+// VM, so that it is only downloaded once.
+const trivyCachePath = constants.ScannerCachePath + "/trivy"
+
+type trivy struct {
+	image string
+}
+
+func (t *trivy) Image() string {
+	return t.image
+}
+
+// Command runs trivy against the docker daemon of the VM, which it reaches
+// through its socket. Only the report is printed, unless trivy fails, as the
+// output is parsed.
+func (t *trivy) Command(image string, severities []string) string {
+	return fmt.Sprintf(`
+sudo mkdir -p %[1]s
+docker inspect --type=image %[2]s >/dev/null 2>&1 || docker pull %[2]s >/dev/null 2>&1
+docker run --rm -v /var/run/docker.sock:/var/run/docker.sock -v %[1]s:/cache %[2]s \
+  image --cache-dir /cache --quiet --format json --severity %[3]s %[4]s 2>/tmp/scan.err || { cat /tmp/scan.err; exit 1; }
+`, trivyCachePath, t.image, strings.Join(severities, ","), image)
+}
+
+type trivyReport struct {
+	Results []struct {
+		Target          string
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+		}
+	}
+}
+
+func (t *trivy) Parse(image, out string) ([]Finding, error) {
+	var report trivyReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the trivy report: %s", out)
+	}
+	var findings []Finding
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			findings = append(findings, Finding{
+				Image:     image,
+				ID:        v.VulnerabilityID,
+				Severity:  v.Severity,
+				Package:   v.PkgName,
+				Installed: v.InstalledVersion,
+				Fixed:     v.FixedVersion,
+				Title:     v.Title,
+			})
+		}
+	}
+	return findings, nil
+}
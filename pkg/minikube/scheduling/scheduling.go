@@ -0,0 +1,284 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scheduling explains why a pod is pending, for minikube why-pending,
+// from its events, the claims of its volumes and the nodes it could run on.
+package scheduling
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/pkg/api"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Cluster is what the scheduler considers to place a pod.
+type Cluster struct {
+	Nodes []v1.Node
+	// Pods are the pods of all namespaces, to sum the requests on the nodes
+	Pods []v1.Pod
+	// Events are the events of the pod
+	Events []v1.Event
+	// Claims are the claims of the namespace of the pod by name
+	Claims map[string]v1.PersistentVolumeClaim
+}
+
+// Explain returns why the pod is pending, most likely reasons first: the
+// reasons its containers are waiting once it is scheduled, else the warnings
+// of its events, its unbound claims and, for each node, why it doesn't fit.
+func Explain(pod *v1.Pod, c Cluster) []string {
+	if pod.Status.Phase != v1.PodPending {
+		return []string{fmt.Sprintf("Pod %s is not pending, it is %s.", pod.Name, pod.Status.Phase)}
+	}
+	if pod.Spec.NodeName != "" {
+		return explainWaiting(pod)
+	}
+
+	var reasons []string
+	reasons = append(reasons, warningEvents(c.Events)...)
+	reasons = append(reasons, unboundClaims(pod, c.Claims)...)
+	if len(c.Nodes) == 0 {
+		reasons = append(reasons, "The cluster has no node.")
+	}
+	for _, n := range c.Nodes {
+		for _, r := range nodeMisfits(pod, &n, c.Pods) {
+			reasons = append(reasons, fmt.Sprintf("Node %s: %s", n.Name, r))
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "No reason found, the scheduler may not have tried to schedule the pod yet.")
+	}
+	return reasons
+}
+
+// explainWaiting returns why the containers of a scheduled pod don't run yet.
+func explainWaiting(pod *v1.Pod) []string {
+	var reasons []string
+	for _, s := range pod.Status.ContainerStatuses {
+		if w := s.State.Waiting; w != nil {
+			r := fmt.Sprintf("Container %s is waiting: %s", s.Name, w.Reason)
+			if w.Message != "" {
+				r += ", " + w.Message
+			}
+			reasons = append(reasons, r)
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("Pod %s is scheduled on node %s, its containers are being created.", pod.Name, pod.Spec.NodeName))
+	}
+	return reasons
+}
+
+// warningEvents returns the distinct messages of the warning events, like
+// those of failed scheduling, the most recent first.
+func warningEvents(events []v1.Event) []string {
+	sorted := make([]v1.Event, len(events))
+	copy(sorted, events)
+	sort.Sort(byLastTimestamp(sorted))
+	var messages []string
+	seen := map[string]bool{}
+	for _, e := range sorted {
+		if e.Type != v1.EventTypeWarning || seen[e.Message] {
+			continue
+		}
+		seen[e.Message] = true
+		messages = append(messages, fmt.Sprintf("Event %s: %s", e.Reason, e.Message))
+	}
+	return messages
+}
+
+type byLastTimestamp []v1.Event
+
+func (e byLastTimestamp) Len() int           { return len(e) }
+func (e byLastTimestamp) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byLastTimestamp) Less(i, j int) bool { return e[j].LastTimestamp.Before(e[i].LastTimestamp) }
+
+// unboundClaims returns the claims of the volumes of the pod which are
+// missing or not bound to a persistent volume.
+func unboundClaims(pod *v1.Pod, claims map[string]v1.PersistentVolumeClaim) []string {
+	var reasons []string
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		name := v.PersistentVolumeClaim.ClaimName
+		claim, ok := claims[name]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("Claim %s of volume %s does not exist.", name, v.Name))
+		case claim.Status.Phase != v1.ClaimBound:
+			r := fmt.Sprintf("Claim %s of volume %s is %s: no persistent volume matches it", name, v.Name, claim.Status.Phase)
+			if class := claim.Annotations["volume.beta.kubernetes.io/storage-class"]; class != "" {
+				r += fmt.Sprintf(" and storage class %s did not provision one", class)
+			}
+			reasons = append(reasons, r+".")
+		}
+	}
+	return reasons
+}
+
+// nodeMisfits returns why the pod doesn't fit on the node.
+func nodeMisfits(pod *v1.Pod, node *v1.Node, pods []v1.Pod) []string {
+	var reasons []string
+	if node.Spec.Unschedulable {
+		reasons = append(reasons, "it is cordoned, see kubectl uncordon")
+	}
+	if !nodeReady(node) {
+		reasons = append(reasons, "it is not ready")
+	}
+	var missing []string
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			missing = append(missing, k+"="+v)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		reasons = append(reasons, fmt.Sprintf("it lacks the labels %s of the node selector", strings.Join(missing, ", ")))
+	}
+	reasons = append(reasons, untoleratedTaints(pod, node)...)
+
+	var podsOnNode []v1.Pod
+	for _, p := range pods {
+		if p.Spec.NodeName == node.Name && p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed {
+			podsOnNode = append(podsOnNode, p)
+		}
+	}
+	reasons = append(reasons, insufficientResources(pod, node, podsOnNode)...)
+	reasons = append(reasons, hostPortConflicts(pod, podsOnNode)...)
+	return reasons
+}
+
+func nodeReady(node *v1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// untoleratedTaints returns the taints of the node which keep the pod away.
+// They are annotations in this version of Kubernetes.
+func untoleratedTaints(pod *v1.Pod, node *v1.Node) []string {
+	var taints []v1.Taint
+	if a := node.Annotations[api.TaintsAnnotationKey]; a != "" {
+		if err := json.Unmarshal([]byte(a), &taints); err != nil {
+			return []string{fmt.Sprintf("its taints can't be parsed: %s", err)}
+		}
+	}
+	var tolerations []v1.Toleration
+	if a := pod.Annotations[api.TolerationsAnnotationKey]; a != "" {
+		if err := json.Unmarshal([]byte(a), &tolerations); err != nil {
+			return []string{fmt.Sprintf("the tolerations of the pod can't be parsed: %s", err)}
+		}
+	}
+	var reasons []string
+	for _, taint := range taints {
+		if taint.Effect != v1.TaintEffectNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, t := range tolerations {
+			if tolerates(t, taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			reasons = append(reasons, fmt.Sprintf("the pod doesn't tolerate its taint %s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+	}
+	return reasons
+}
+
+func tolerates(t v1.Toleration, taint v1.Taint) bool {
+	if t.Key != taint.Key || (t.Effect != "" && t.Effect != taint.Effect) {
+		return false
+	}
+	return t.Operator == v1.TolerationOpExists || t.Value == taint.Value
+}
+
+// insufficientResources compares the requests of the pod with what the pods
+// on the node leave of its allocatable CPU, memory and pods.
+func insufficientResources(pod *v1.Pod, node *v1.Node, podsOnNode []v1.Pod) []string {
+	var reasons []string
+	requested := podRequests(pod)
+	used := v1.ResourceList{}
+	for i := range podsOnNode {
+		for name, q := range podRequests(&podsOnNode[i]) {
+			total := used[name]
+			total.Add(q)
+			used[name] = total
+		}
+	}
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		req, ok := requested[name]
+		if !ok || req.IsZero() {
+			continue
+		}
+		allocatable := node.Status.Allocatable[name]
+		free := allocatable.Copy()
+		free.Sub(used[name])
+		if free.Cmp(req) < 0 {
+			reasons = append(reasons, fmt.Sprintf("insufficient %s, the pod requests %s but %s of %s allocatable are free", name, req.String(), free.String(), allocatable.String()))
+		}
+	}
+	if max, ok := node.Status.Allocatable[v1.ResourcePods]; ok && int64(len(podsOnNode)) >= max.Value() {
+		reasons = append(reasons, fmt.Sprintf("it already runs its maximum of %d pods", max.Value()))
+	}
+	return reasons
+}
+
+// podRequests returns the sum of the requests of the containers of the pod.
+func podRequests(pod *v1.Pod) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			total := requests[name]
+			total.Add(q)
+			requests[name] = total
+		}
+	}
+	return requests
+}
+
+// hostPortConflicts returns the host ports of the pod already taken on the
+// node.
+func hostPortConflicts(pod *v1.Pod, podsOnNode []v1.Pod) []string {
+	taken := map[int32]string{}
+	for _, p := range podsOnNode {
+		for _, c := range p.Spec.Containers {
+			for _, port := range c.Ports {
+				if port.HostPort != 0 {
+					taken[port.HostPort] = p.Namespace + "/" + p.Name
+				}
+			}
+		}
+	}
+	var reasons []string
+	for _, c := range pod.Spec.Containers {
+		for _, port := range c.Ports {
+			if owner, ok := taken[port.HostPort]; port.HostPort != 0 && ok {
+				reasons = append(reasons, fmt.Sprintf("host port %d is taken by pod %s", port.HostPort, owner))
+			}
+		}
+	}
+	return reasons
+}
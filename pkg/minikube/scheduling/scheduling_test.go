@@ -0,0 +1,156 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/pkg/api/resource"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func pendingPod(name string, cpu string, mutate func(*v1.Pod)) v1.Pod {
+	p := v1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name: "app",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse(cpu),
+			}},
+		}}},
+		Status: v1.PodStatus{Phase: v1.PodPending},
+	}
+	if mutate != nil {
+		mutate(&p)
+	}
+	return p
+}
+
+func readyNode(mutate func(*v1.Node)) v1.Node {
+	n := v1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: "minikube", Labels: map[string]string{"kubernetes.io/hostname": "minikube"}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+	if mutate != nil {
+		mutate(&n)
+	}
+	return n
+}
+
+func TestExplain(t *testing.T) {
+	running := pendingPod("db", "1500m", func(p *v1.Pod) {
+		p.Spec.NodeName = "minikube"
+		p.Status.Phase = v1.PodRunning
+		p.Spec.Containers[0].Ports = []v1.ContainerPort{{HostPort: 8080}}
+	})
+	now := unversioned.NewTime(time.Now())
+
+	var tests = []struct {
+		description string
+		pod         v1.Pod
+		cluster     Cluster
+		expected    []string
+	}{
+		{
+			description: "not pending",
+			pod:         running,
+			expected:    []string{"Pod db is not pending, it is Running."},
+		},
+		{
+			description: "scheduled, pulling",
+			pod: pendingPod("web", "100m", func(p *v1.Pod) {
+				p.Spec.NodeName = "minikube"
+				p.Status.ContainerStatuses = []v1.ContainerStatus{{
+					Name:  "app",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image \"app:dev\""}},
+				}}
+			}),
+			expected: []string{`Container app is waiting: ImagePullBackOff, Back-off pulling image "app:dev"`},
+		},
+		{
+			description: "insufficient cpu and host port",
+			pod: pendingPod("web", "1", func(p *v1.Pod) {
+				p.Spec.Containers[0].Ports = []v1.ContainerPort{{HostPort: 8080}}
+			}),
+			cluster: Cluster{
+				Nodes: []v1.Node{readyNode(nil)},
+				Pods:  []v1.Pod{running},
+				Events: []v1.Event{
+					{Type: v1.EventTypeWarning, Reason: "FailedScheduling", Message: "old", LastTimestamp: unversioned.NewTime(now.Add(-time.Minute))},
+					{Type: v1.EventTypeWarning, Reason: "FailedScheduling", Message: "pod (web) failed to fit in any node", LastTimestamp: now},
+					{Type: v1.EventTypeWarning, Reason: "FailedScheduling", Message: "old", LastTimestamp: unversioned.NewTime(now.Add(-2 * time.Minute))},
+					{Type: v1.EventTypeNormal, Reason: "Scheduled", Message: "normal"},
+				},
+			},
+			expected: []string{
+				"Event FailedScheduling: pod (web) failed to fit in any node",
+				"Event FailedScheduling: old",
+				"Node minikube: insufficient cpu, the pod requests 1 but 500m of 2 allocatable are free",
+				"Node minikube: host port 8080 is taken by pod default/db",
+			},
+		},
+		{
+			description: "selector, taint, cordon and claim",
+			pod: pendingPod("web", "100m", func(p *v1.Pod) {
+				p.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+				p.Annotations = map[string]string{"scheduler.alpha.kubernetes.io/tolerations": `[{"key":"gpu","operator":"Exists"}]`}
+				p.Spec.Volumes = []v1.Volume{
+					{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+					{Name: "logs", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "logs"}}},
+				}
+			}),
+			cluster: Cluster{
+				Nodes: []v1.Node{readyNode(func(n *v1.Node) {
+					n.Spec.Unschedulable = true
+					n.Annotations = map[string]string{"scheduler.alpha.kubernetes.io/taints": `[{"key":"gpu","value":"true","effect":"NoSchedule"},{"key":"dedicated","value":"infra","effect":"NoSchedule"}]`}
+				})},
+				Claims: map[string]v1.PersistentVolumeClaim{
+					"data": {Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending}},
+				},
+			},
+			expected: []string{
+				"Claim data of volume data is Pending: no persistent volume matches it.",
+				"Claim logs of volume logs does not exist.",
+				"Node minikube: it is cordoned, see kubectl uncordon",
+				"Node minikube: it lacks the labels disk=ssd of the node selector",
+				"Node minikube: the pod doesn't tolerate its taint dedicated=infra:NoSchedule",
+			},
+		},
+		{
+			description: "nothing found",
+			pod:         pendingPod("web", "100m", nil),
+			cluster:     Cluster{Nodes: []v1.Node{readyNode(nil)}},
+			expected:    []string{"No reason found, the scheduler may not have tried to schedule the pod yet."},
+		},
+	}
+	for _, test := range tests {
+		reasons := Explain(&test.pod, test.cluster)
+		if !reflect.DeepEqual(reasons, test.expected) {
+			t.Errorf("%s: expected\n%v\ngot\n%v", test.description, test.expected, reasons)
+		}
+	}
+}
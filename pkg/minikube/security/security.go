@@ -0,0 +1,161 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package security audits the permissions of the credentials minikube
+// generates: the keys and certs in the minikube dir, the SSH keys of the
+// machines and the kubeconfig.
+package security
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Permissions of the credentials and of the directories holding only
+// credentials
+const (
+	FileMode os.FileMode = 0600
+	DirMode  os.FileMode = 0700
+)
+
+// credentialPatterns match the credentials in the minikube dir
+var credentialPatterns = [][]string{
+	{"*.key"},
+	{"*.crt"},
+	{"certs", "*.pem"},
+	{"machines", "*.pem"},
+	{"machines", "*", "*.pem"},
+	{"machines", "*", "id_rsa"},
+	// The answers of the registry-creds addon, of the default profile and
+	// of the others
+	{"registry-creds.json"},
+	{"profiles", "*", "registry-creds.json"},
+}
+
+// credentialDirs hold only credentials
+var credentialDirs = [][]string{
+	{"certs"},
+}
+
+// Finding is a credential which other users than its owner can access
+type Finding struct {
+	Path string
+	Mode os.FileMode
+	Want os.FileMode
+	// Key is set for private key material
+	Key bool
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s has mode %04o, it should be %04o", f.Path, f.Mode.Perm(), f.Want)
+}
+
+// WorldReadable reports whether any user can read a private key.
+func (f Finding) WorldReadable() bool {
+	return f.Key && !f.Mode.IsDir() && f.Mode&0004 != 0
+}
+
+// Audit returns the credentials, including the kubeconfig if not empty, and
+// the directories of credentials which other users than their owner can
+// access. Nothing is reported on Windows, where modes are not enforced.
+func Audit(kubeconfig string) ([]Finding, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+	var findings []Finding
+	for _, d := range credentialDirs {
+		f, err := check(constants.MakeMiniPath(d...), DirMode)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, f...)
+	}
+	paths, err := credentials()
+	if err != nil {
+		return nil, err
+	}
+	if kubeconfig != "" {
+		paths = append(paths, kubeconfig)
+	}
+	for _, p := range paths {
+		f, err := check(p, FileMode)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, f...)
+	}
+	return findings, nil
+}
+
+// Fix restricts the permissions of the findings to what they should be.
+func Fix(findings []Finding) error {
+	for _, f := range findings {
+		if err := os.Chmod(f.Path, f.Want); err != nil {
+			return errors.Wrapf(err, "Error changing the mode of %s", f.Path)
+		}
+	}
+	return nil
+}
+
+// WorldReadableKeys returns the private keys among the findings which any
+// user can read.
+func WorldReadableKeys(findings []Finding) []Finding {
+	var keys []Finding
+	for _, f := range findings {
+		if f.WorldReadable() {
+			keys = append(keys, f)
+		}
+	}
+	return keys
+}
+
+func credentials() ([]string, error) {
+	var paths []string
+	for _, p := range credentialPatterns {
+		matches, err := filepath.Glob(constants.MakeMiniPath(p...))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error listing credentials")
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// check returns a finding if the path exists with permissions beyond want.
+func check(path string, want os.FileMode) ([]Finding, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error checking %s", path)
+	}
+	if info.Mode().Perm()&^want == 0 {
+		return nil, nil
+	}
+	return []Finding{{Path: path, Mode: info.Mode(), Want: want, Key: isKey(path)}}, nil
+}
+
+func isKey(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".key") || strings.HasSuffix(name, "key.pem") || name == "id_rsa"
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func write(t *testing.T, path string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Error creating dir: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte{}, mode); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+	// The umask may have restricted the mode
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatalf("Error changing mode: %s", err)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	write(t, constants.MakeMiniPath("ca.key"), 0644)
+	write(t, constants.MakeMiniPath("ca.crt"), 0644)
+	write(t, constants.MakeMiniPath("apiserver.key"), 0600)
+	write(t, constants.MakeMiniPath("machines", "minikube", "id_rsa"), 0640)
+	write(t, constants.MakeMiniPath("machines", "minikube", "config.json"), 0644)
+	kubeconfig := filepath.Join(tempDir, "kubeconfig")
+	write(t, kubeconfig, 0600)
+	write(t, constants.MakeMiniPath("certs", "ca.pem"), 0600)
+	if err := os.Chmod(constants.MakeMiniPath("certs"), 0755); err != nil {
+		t.Fatalf("Error changing mode: %s", err)
+	}
+
+	findings, err := Audit(kubeconfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var paths []string
+	for _, f := range findings {
+		paths = append(paths, f.Path)
+	}
+	sort.Strings(paths)
+	expected := []string{
+		constants.MakeMiniPath("ca.crt"),
+		constants.MakeMiniPath("ca.key"),
+		constants.MakeMiniPath("certs"),
+		constants.MakeMiniPath("machines", "minikube", "id_rsa"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Expected findings %v, got %v", expected, paths)
+	}
+
+	keys := WorldReadableKeys(findings)
+	if len(keys) != 1 || keys[0].Path != constants.MakeMiniPath("ca.key") {
+		t.Errorf("Expected only ca.key to be a world readable key, got %v", keys)
+	}
+
+	if err := Fix(findings); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	findings, err = Audit(kubeconfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings once fixed, got %v", findings)
+	}
+	if info, _ := os.Stat(constants.MakeMiniPath("certs")); info.Mode().Perm() != DirMode {
+		t.Errorf("Expected the certs dir to be %04o, got %04o", DirMode, info.Mode().Perm())
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package seed creates the Secrets and ConfigMaps declared in the config,
+// from files and environment variables of the host, once the cluster is
+// ready.
+package seed
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// SecretPrefix prefixes the properties declaring a Secret,
+	// seed.secret.<namespace>/<name> = KEY=file:PATH,KEY=env:VAR,...
+	SecretPrefix = "seed.secret."
+	// ConfigMapPrefix prefixes the properties declaring a ConfigMap, of the
+	// same form as the Secrets
+	ConfigMapPrefix = "seed.configmap."
+
+	fromFile = "file:"
+	fromEnv  = "env:"
+
+	// seededLabel marks the objects created from the config
+	seededLabel = "minikube.k8s.io/seeded"
+)
+
+// Object is a Secret or ConfigMap declared in the config.
+type Object struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Sources are the files or environment variables of the host, by key
+	Sources map[string]string
+}
+
+func (o Object) String() string {
+	return fmt.Sprintf("%s %s/%s", strings.ToLower(o.Kind), o.Namespace, o.Name)
+}
+
+// Keys returns the keys of the object, sorted.
+func (o Object) Keys() []string {
+	var keys []string
+	for k := range o.Sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Host reads the files and environment variables the objects are seeded
+// from.
+type Host struct {
+	ReadFile func(string) ([]byte, error)
+	Getenv   func(string) (string, bool)
+}
+
+// LocalHost returns the host minikube runs on.
+func LocalHost() Host {
+	return Host{ReadFile: ioutil.ReadFile, Getenv: os.LookupEnv}
+}
+
+// Data reads the values of the object from the host. The errors name the
+// files and variables, never their values.
+func (h Host) Data(o Object) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for key, source := range o.Sources {
+		switch {
+		case strings.HasPrefix(source, fromFile):
+			b, err := h.ReadFile(strings.TrimPrefix(source, fromFile))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error reading %s of %s", key, o)
+			}
+			data[key] = b
+		case strings.HasPrefix(source, fromEnv):
+			name := strings.TrimPrefix(source, fromEnv)
+			v, ok := h.Getenv(name)
+			if !ok {
+				return nil, errors.Errorf("Environment variable %s of %s is not set", name, o)
+			}
+			data[key] = []byte(v)
+		}
+	}
+	return data, nil
+}
+
+// Parse parses a property declaring an object.
+func Parse(name, val string) (Object, error) {
+	var o Object
+	var ref string
+	switch {
+	case strings.HasPrefix(name, SecretPrefix):
+		o.Kind, ref = "Secret", strings.TrimPrefix(name, SecretPrefix)
+	case strings.HasPrefix(name, ConfigMapPrefix):
+		o.Kind, ref = "ConfigMap", strings.TrimPrefix(name, ConfigMapPrefix)
+	default:
+		return o, errors.Errorf("%s is not of the form %s<namespace>/<name> or %s<namespace>/<name>", name, SecretPrefix, ConfigMapPrefix)
+	}
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return o, errors.Errorf("%s is not of the form %s<namespace>/<name>", name, strings.TrimSuffix(name, ref))
+	}
+	o.Namespace, o.Name = parts[0], parts[1]
+	o.Sources = map[string]string{}
+	for _, pair := range strings.Split(val, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return o, errors.Errorf("%s is not of the form KEY=%sPATH or KEY=%sVAR", pair, fromFile, fromEnv)
+		}
+		source := kv[1]
+		if source == fromFile || source == fromEnv || !(strings.HasPrefix(source, fromFile) || strings.HasPrefix(source, fromEnv)) {
+			return o, errors.Errorf("The value of %s is not of the form %sPATH or %sVAR", kv[0], fromFile, fromEnv)
+		}
+		if _, ok := o.Sources[kv[0]]; ok {
+			return o, errors.Errorf("Key %s is given twice", kv[0])
+		}
+		o.Sources[kv[0]] = source
+	}
+	return o, nil
+}
+
+// Load returns the objects declared in the config, sorted.
+func Load() ([]Object, error) {
+	m, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	var objects []Object
+	for k, v := range m {
+		if !strings.HasPrefix(k, SecretPrefix) && !strings.HasPrefix(k, ConfigMapPrefix) {
+			continue
+		}
+		o, err := Parse(k, fmt.Sprintf("%v", v))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, o)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].String() < objects[j].String() })
+	return objects, nil
+}
+
+// Client is what seeding needs of the Kubernetes client
+type Client interface {
+	corev1.NamespacesGetter
+	corev1.SecretsGetter
+	corev1.ConfigMapsGetter
+}
+
+// Apply creates the objects, or updates those already there, in the
+// namespaces created if needed. Only the names of the objects and their keys
+// are written to w. It goes on with the next object when one fails and
+// returns the errors of all of them.
+func Apply(client Client, h Host, objects []Object, w io.Writer) error {
+	m := util.MultiError{}
+	for _, o := range objects {
+		data, err := h.Data(o)
+		if err != nil {
+			m.Collect(err)
+			continue
+		}
+		err = util.RetryAfter(5, func() error {
+			if err := write(client, o, data); err != nil {
+				return &util.RetriableError{Err: err}
+			}
+			return nil
+		}, 2*time.Second)
+		if err != nil {
+			m.Collect(errors.Wrapf(err, "Error seeding %s", o))
+			continue
+		}
+		fmt.Fprintf(w, "Seeded %s (%s)\n", o, strings.Join(o.Keys(), ", "))
+	}
+	return m.ToError()
+}
+
+func write(client Client, o Object, data map[string][]byte) error {
+	if err := ensureNamespace(client, o.Namespace); err != nil {
+		return err
+	}
+	meta := v1.ObjectMeta{Name: o.Name, Namespace: o.Namespace, Labels: map[string]string{seededLabel: "true"}}
+	if o.Kind == "ConfigMap" {
+		strData := map[string]string{}
+		for k, v := range data {
+			strData[k] = string(v)
+		}
+		configMaps := client.ConfigMaps(o.Namespace)
+		existing, err := configMaps.Get(o.Name)
+		if err == nil {
+			existing.Data = strData
+			_, err = configMaps.Update(existing)
+			return err
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		_, err = configMaps.Create(&v1.ConfigMap{ObjectMeta: meta, Data: strData})
+		return err
+	}
+	secrets := client.Secrets(o.Namespace)
+	existing, err := secrets.Get(o.Name)
+	if err == nil {
+		existing.Data = data
+		_, err = secrets.Update(existing)
+		return err
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = secrets.Create(&v1.Secret{ObjectMeta: meta, Data: data, Type: v1.SecretTypeOpaque})
+	return err
+}
+
+func ensureNamespace(client Client, name string) error {
+	_, err := client.Namespaces().Get(name)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = client.Namespaces().Create(&v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name}})
+	return err
+}
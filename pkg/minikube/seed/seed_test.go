@@ -0,0 +1,207 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package seed
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	apierrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestParse(t *testing.T) {
+	var tcs = []struct {
+		name      string
+		val       string
+		expected  Object
+		shouldErr bool
+	}{
+		{
+			name: "seed.secret.dev/db",
+			val:  "password=env:DB_PASSWORD, tls.crt=file:/home/user/db.crt",
+			expected: Object{Kind: "Secret", Namespace: "dev", Name: "db", Sources: map[string]string{
+				"password": "env:DB_PASSWORD",
+				"tls.crt":  "file:/home/user/db.crt",
+			}},
+		},
+		{
+			name:     "seed.configmap.default/app.settings",
+			val:      "settings.yaml=file:settings.yaml",
+			expected: Object{Kind: "ConfigMap", Namespace: "default", Name: "app.settings", Sources: map[string]string{"settings.yaml": "file:settings.yaml"}},
+		},
+		{name: "seed.secret.db", val: "password=env:DB_PASSWORD", shouldErr: true},
+		{name: "seed.secret.dev/db", val: "password", shouldErr: true},
+		{name: "seed.secret.dev/db", val: "password=DB_PASSWORD", shouldErr: true},
+		{name: "seed.secret.dev/db", val: "password=env:", shouldErr: true},
+		{name: "seed.secret.dev/db", val: "password=env:A,password=env:B", shouldErr: true},
+		{name: "seed.pod.dev/db", val: "password=env:DB_PASSWORD", shouldErr: true},
+	}
+	for _, test := range tcs {
+		o, err := Parse(test.name, test.val)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %s = %s: %v", test.name, test.val, err)
+		}
+		if !test.shouldErr && !reflect.DeepEqual(o, test.expected) {
+			t.Errorf("Expected %+v for %s = %s, got %+v", test.expected, test.name, test.val, o)
+		}
+	}
+}
+
+type mockClient struct {
+	namespaces map[string]bool
+	secrets    map[string]*v1.Secret
+	configMaps map[string]*v1.ConfigMap
+}
+
+func notFound(resource, name string) error {
+	return apierrors.NewNotFound(unversioned.GroupResource{Resource: resource}, name)
+}
+
+func (m *mockClient) Namespaces() corev1.NamespaceInterface {
+	return &mockNamespaces{namespaces: m.namespaces}
+}
+
+func (m *mockClient) Secrets(namespace string) corev1.SecretInterface {
+	return &mockSecrets{secrets: m.secrets, namespace: namespace}
+}
+
+func (m *mockClient) ConfigMaps(namespace string) corev1.ConfigMapInterface {
+	return &mockConfigMaps{configMaps: m.configMaps, namespace: namespace}
+}
+
+type mockNamespaces struct {
+	fake.FakeNamespaces
+	namespaces map[string]bool
+}
+
+func (m *mockNamespaces) Get(name string) (*v1.Namespace, error) {
+	if !m.namespaces[name] {
+		return nil, notFound("namespaces", name)
+	}
+	return &v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name}}, nil
+}
+
+func (m *mockNamespaces) Create(ns *v1.Namespace) (*v1.Namespace, error) {
+	m.namespaces[ns.Name] = true
+	return ns, nil
+}
+
+type mockSecrets struct {
+	fake.FakeSecrets
+	secrets   map[string]*v1.Secret
+	namespace string
+}
+
+func (m *mockSecrets) Get(name string) (*v1.Secret, error) {
+	s, ok := m.secrets[m.namespace+"/"+name]
+	if !ok {
+		return nil, notFound("secrets", name)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *mockSecrets) Create(s *v1.Secret) (*v1.Secret, error) {
+	m.secrets[m.namespace+"/"+s.Name] = s
+	return s, nil
+}
+
+func (m *mockSecrets) Update(s *v1.Secret) (*v1.Secret, error) {
+	return m.Create(s)
+}
+
+type mockConfigMaps struct {
+	fake.FakeConfigMaps
+	configMaps map[string]*v1.ConfigMap
+	namespace  string
+}
+
+func (m *mockConfigMaps) Get(name string) (*v1.ConfigMap, error) {
+	c, ok := m.configMaps[m.namespace+"/"+name]
+	if !ok {
+		return nil, notFound("configmaps", name)
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (m *mockConfigMaps) Create(c *v1.ConfigMap) (*v1.ConfigMap, error) {
+	m.configMaps[m.namespace+"/"+c.Name] = c
+	return c, nil
+}
+
+func (m *mockConfigMaps) Update(c *v1.ConfigMap) (*v1.ConfigMap, error) {
+	return m.Create(c)
+}
+
+func TestApply(t *testing.T) {
+	h := Host{
+		ReadFile: func(path string) ([]byte, error) {
+			if path == "settings.yaml" {
+				return []byte("debug: true"), nil
+			}
+			return nil, os.ErrNotExist
+		},
+		Getenv: func(name string) (string, bool) {
+			if name == "DB_PASSWORD" {
+				return "hunter2", true
+			}
+			return "", false
+		},
+	}
+	client := &mockClient{
+		namespaces: map[string]bool{"default": true},
+		secrets:    map[string]*v1.Secret{},
+		configMaps: map[string]*v1.ConfigMap{
+			"default/app": {ObjectMeta: v1.ObjectMeta{Name: "app"}, Data: map[string]string{"old": "value"}},
+		},
+	}
+	objects := []Object{
+		{Kind: "ConfigMap", Namespace: "default", Name: "app", Sources: map[string]string{"settings.yaml": "file:settings.yaml"}},
+		{Kind: "Secret", Namespace: "dev", Name: "broken", Sources: map[string]string{"token": "env:TOKEN"}},
+		{Kind: "Secret", Namespace: "dev", Name: "db", Sources: map[string]string{"password": "env:DB_PASSWORD"}},
+	}
+
+	var out bytes.Buffer
+	err := Apply(client, h, objects, &out)
+	if err == nil || !strings.Contains(err.Error(), "TOKEN") {
+		t.Errorf("Expected an error naming the variable which is not set, got %v", err)
+	}
+	if c := client.configMaps["default/app"]; !reflect.DeepEqual(c.Data, map[string]string{"settings.yaml": "debug: true"}) {
+		t.Errorf("Expected the config map to be updated, got %v", c.Data)
+	}
+	if !client.namespaces["dev"] {
+		t.Errorf("Expected the namespace dev to be created")
+	}
+	if s := client.secrets["dev/db"]; s == nil || string(s.Data["password"]) != "hunter2" {
+		t.Errorf("Expected the secret to be created after the one failing, got %v", s)
+	}
+	expected := "Seeded configmap default/app (settings.yaml)\nSeeded secret dev/db (password)\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+	if strings.Contains(out.String()+err.Error(), "hunter2") {
+		t.Errorf("Expected the values to be redacted")
+	}
+}
@@ -19,7 +19,6 @@ package service
 import (
 	"bytes"
 	"fmt"
-	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -30,6 +29,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"text/template"
@@ -52,24 +52,59 @@ func init() {
 }
 
 func (*K8sClientGetter) GetCoreClient() (corev1.CoreV1Interface, error) {
+	client, err := GetClientset()
+	if err != nil {
+		return nil, err
+	}
+	return client.Core(), nil
+}
+
+// GetClientConfig returns the client config for the cluster of the current kubectl context
+func GetClientConfig() (*rest.Config, error) {
+	return getClientConfig(&clientcmd.ConfigOverrides{})
+}
+
+func getClientConfig(configOverrides *clientcmd.ConfigOverrides) (*rest.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	configOverrides := &clientcmd.ConfigOverrides{}
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 	config, err := kubeConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("Error creating kubeConfig: %s", err)
 	}
+	return config, nil
+}
+
+// GetClientset returns a client for the cluster of the current kubectl context
+func GetClientset() (*kubernetes.Clientset, error) {
+	config, err := GetClientConfig()
+	if err != nil {
+		return nil, err
+	}
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
 	}
-	return client.Core(), nil
+	return client, nil
+}
+
+// GetClientsetForContext returns a client for the cluster of the kubectl
+// context, whichever the current one is
+func GetClientsetForContext(context string) (*kubernetes.Clientset, error) {
+	config, err := getClientConfig(&clientcmd.ConfigOverrides{CurrentContext: context})
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
+	}
+	return client, nil
 }
 
 type ServiceURL struct {
-	Namespace string
-	Name      string
-	URLs      []string
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	URLs      []string `json:"urls"`
 }
 
 type ServiceURLs []ServiceURL
@@ -111,9 +146,44 @@ func GetServiceURLs(api libmachine.API, namespace string, t *template.Template)
 	return serviceURLs, nil
 }
 
-// Returns all the node ports for a service in a namespace
-// with optional formatting
-func GetServiceURLsForService(api libmachine.API, namespace, service string, t *template.Template) ([]string, error) {
+// PortSelector selects the ports of a service by name or by number, the
+// zero value selecting all of them.
+type PortSelector struct {
+	Name   string
+	Number int32
+}
+
+func (s PortSelector) matches(p v1.ServicePort) bool {
+	return (s.Name == "" || p.Name == s.Name) && (s.Number == 0 || p.Port == s.Number)
+}
+
+func (s PortSelector) String() string {
+	switch {
+	case s.Name != "" && s.Number != 0:
+		return fmt.Sprintf("named %s with number %d", s.Name, s.Number)
+	case s.Name != "":
+		return "named " + s.Name
+	}
+	return fmt.Sprintf("with number %d", s.Number)
+}
+
+// portURL is the URL of a node port of a service
+type portURL struct {
+	name string
+	url  string
+}
+
+// Returns all the node ports for a service in a namespace, or those of the
+// selected ports, with optional formatting
+func GetServiceURLsForService(api libmachine.API, namespace, service string, t *template.Template, selector PortSelector) ([]string, error) {
+	urls, err := getPortURLs(api, namespace, service, t, selector, false)
+	if err != nil {
+		return nil, err
+	}
+	return urlsOf(urls), nil
+}
+
+func getPortURLs(api libmachine.API, namespace, service string, t *template.Template, selector PortSelector, https bool) ([]portURL, error) {
 	host, err := cluster.CheckIfApiExistsAndLoad(api)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error checking if api exist and loading it")
@@ -129,10 +199,33 @@ func GetServiceURLsForService(api libmachine.API, namespace, service string, t *
 		return nil, err
 	}
 
-	return printURLsForService(client, ip, service, namespace, t)
+	return portURLsForService(client, ip, service, namespace, t, selector, https)
 }
 
 func printURLsForService(c corev1.CoreV1Interface, ip, service, namespace string, t *template.Template) ([]string, error) {
+	urls, err := portURLsForService(c, ip, service, namespace, t, PortSelector{}, false)
+	if err != nil {
+		return nil, err
+	}
+	return urlsOf(urls), nil
+}
+
+// URLTemplate holds the values of each port the --format template of the
+// service URLs is executed with.
+type URLTemplate struct {
+	// Scheme is https for the ports named https or numbered 443, or all of
+	// them with --https, http otherwise
+	Scheme string
+	IP     string
+	// Port is the node port
+	Port int32
+	Name string
+}
+
+// portURLsForService returns the URLs of the selected node ports of the
+// service, formatted with the template as they are. Ports named https or
+// numbered 443, or all of them for https, get https URLs.
+func portURLsForService(c corev1.CoreV1Interface, ip, service, namespace string, t *template.Template, selector PortSelector, https bool) ([]portURL, error) {
 	if t == nil {
 		return nil, errors.New("Error, attempted to generate service url with nil --format template")
 	}
@@ -142,38 +235,73 @@ func printURLsForService(c corev1.CoreV1Interface, ip, service, namespace string
 	if err != nil {
 		return nil, errors.Wrapf(err, "service '%s' could not be found running", service)
 	}
-	var nodePorts []int32
-	if len(svc.Spec.Ports) > 0 {
-		for _, port := range svc.Spec.Ports {
-			if port.NodePort > 0 {
-				nodePorts = append(nodePorts, port.NodePort)
-			}
+	var ports []v1.ServicePort
+	var names []string
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort <= 0 {
+			continue
+		}
+		names = append(names, portName(port))
+		if selector.matches(port) {
+			ports = append(ports, port)
 		}
 	}
-	urls := []string{}
-	for _, port := range nodePorts {
+	if len(ports) == 0 && selector != (PortSelector{}) {
+		return nil, errors.Errorf("service '%s' has no node port %s, its ports are: %s", service, selector, strings.Join(names, ", "))
+	}
+	urls := []portURL{}
+	for _, port := range ports {
+		portHTTPS := https || port.Name == "https" || port.Port == 443
+		scheme := "http"
+		if portHTTPS {
+			scheme = "https"
+		}
 		var doc bytes.Buffer
-		err = t.Execute(&doc, struct {
-			IP   string
-			Port int32
-		}{
-			ip,
-			port,
+		err = t.Execute(&doc, URLTemplate{
+			Scheme: scheme,
+			IP:     ip,
+			Port:   port.NodePort,
+			Name:   port.Name,
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		u, err := url.Parse(doc.String())
-		if err != nil {
-			return nil, err
+		// The output may not be a URL, e.g. {{.IP}}:{{.Port}}
+		urlString := doc.String()
+		if portHTTPS {
+			urlString = httpsURL(urlString)
 		}
-
-		urls = append(urls, u.String())
+		urls = append(urls, portURL{name: portName(port), url: urlString})
 	}
 	return urls, nil
 }
 
+// portName describes the port for the selectors, e.g. http(80) or 8080.
+func portName(p v1.ServicePort) string {
+	if p.Name == "" {
+		return fmt.Sprint(p.Port)
+	}
+	return fmt.Sprintf("%s(%d)", p.Name, p.Port)
+}
+
+func urlsOf(urls []portURL) []string {
+	s := []string{}
+	for _, u := range urls {
+		s = append(s, u.url)
+	}
+	return s
+}
+
+// httpsURL replaces the http scheme of the URL with https, leaving the URLs
+// formatted without a scheme as they are.
+func httpsURL(u string) string {
+	if strings.HasPrefix(u, "http://") {
+		return "https://" + strings.TrimPrefix(u, "http://")
+	}
+	return u
+}
+
 // CheckService waits for the specified service to be ready by returning an error until the service is up
 // The check is done by polling the endpoint associated with the service and when the endpoint exists, returning no error->service-online
 func CheckService(namespace string, service string) error {
@@ -216,24 +344,32 @@ func checkEndpointReady(endpoints corev1.EndpointsInterface, service string) err
 	return nil
 }
 
-func WaitAndMaybeOpenService(api libmachine.API, namespace string, service string, urlTemplate *template.Template, urlMode bool, https bool) error {
+// WaitAndMaybeOpenService waits for the service to have ready endpoints, then
+// opens the URL of its node port in the browser, or prints it in urlMode. A
+// service with several node ports has them all printed, unless the selector
+// picks the one to open.
+func WaitAndMaybeOpenService(api libmachine.API, namespace string, service string, urlTemplate *template.Template, urlMode bool, https bool, selector PortSelector) error {
 	if err := util.RetryAfter(20, func() error { return CheckService(namespace, service) }, 6*time.Second); err != nil {
 		return errors.Wrapf(err, "Could not find finalized endpoint being pointed to by %s", service)
 	}
 
-	urls, err := GetServiceURLsForService(api, namespace, service, urlTemplate)
+	urls, err := getPortURLs(api, namespace, service, urlTemplate, selector, https)
 	if err != nil {
 		return errors.Wrap(err, "Check that minikube is running and that you have specified the correct namespace")
 	}
-	for _, url := range urls {
-		if https {
-			url = strings.Replace(url, "http", "https", 1)
+	if !urlMode && len(urls) > 1 {
+		fmt.Fprintf(os.Stdout, "Service %s/%s has several ports, open one with --port-name or --port-number:\n", namespace, service)
+		for _, u := range urls {
+			fmt.Fprintf(os.Stdout, "%s: %s\n", u.name, u.url)
 		}
-		if urlMode || !strings.HasPrefix(url, "http") {
-			fmt.Fprintln(os.Stdout, url)
+		return nil
+	}
+	for _, u := range urls {
+		if urlMode || !strings.HasPrefix(u.url, "http") {
+			fmt.Fprintln(os.Stdout, u.url)
 		} else {
 			fmt.Fprintln(os.Stdout, "Opening kubernetes service "+namespace+"/"+service+" in default browser...")
-			browser.OpenURL(url)
+			browser.OpenURL(u.url)
 		}
 	}
 	return nil
@@ -61,8 +61,8 @@ var defaultNamespaceServiceInterface = &MockServiceInterface{
 				},
 				Spec: v1.ServiceSpec{
 					Ports: []v1.ServicePort{
-						{NodePort: int32(1111)},
-						{NodePort: int32(2222)},
+						{Name: "http", Port: 80, NodePort: int32(1111)},
+						{Name: "https", Port: 443, NodePort: int32(2222)},
 					},
 				},
 			},
@@ -267,7 +267,80 @@ func TestPrintURLsForService(t *testing.T) {
 				t.Errorf("Expected error but got none")
 			}
 			if !reflect.DeepEqual(urls, test.expectedOutput) {
-				t.Errorf("Expected %+v \n\n Actual: %+v \n\n")
+				t.Errorf("Expected %+v \n\n Actual: %+v \n\n", test.expectedOutput, urls)
+			}
+		})
+	}
+}
+
+func TestPortURLsForService(t *testing.T) {
+	httpTemplate := template.Must(template.New("svc-template").Parse("http://{{.IP}}:{{.Port}}"))
+	client := &MockCoreClient{
+		servicesMap: serviceNamespaces,
+	}
+	var tests = []struct {
+		description string
+		tmpl        *template.Template
+		selector    PortSelector
+		https       bool
+		expected    []string
+		err         bool
+	}{
+		{
+			description: "all the ports, https for the https one",
+			tmpl:        httpTemplate,
+			expected:    []string{"http://127.0.0.1:1111", "https://127.0.0.1:2222"},
+		},
+		{
+			description: "port by name",
+			tmpl:        httpTemplate,
+			selector:    PortSelector{Name: "http"},
+			expected:    []string{"http://127.0.0.1:1111"},
+		},
+		{
+			description: "port by number",
+			tmpl:        httpTemplate,
+			selector:    PortSelector{Number: 443},
+			expected:    []string{"https://127.0.0.1:2222"},
+		},
+		{
+			description: "scheme and path in the template",
+			tmpl:        template.Must(template.New("svc-template").Parse("{{.Scheme}}://{{.IP}}:{{.Port}}/{{.Name}}/")),
+			expected:    []string{"http://127.0.0.1:1111/http/", "https://127.0.0.1:2222/https/"},
+		},
+		{
+			description: "https for all the ports",
+			tmpl:        template.Must(template.New("svc-template").Parse("{{.Scheme}}://{{.IP}}:{{.Port}}")),
+			https:       true,
+			expected:    []string{"https://127.0.0.1:1111", "https://127.0.0.1:2222"},
+		},
+		{
+			description: "output which is not a URL",
+			tmpl:        template.Must(template.New("svc-template").Parse("{{.IP}}:{{.Port}}")),
+			selector:    PortSelector{Name: "http"},
+			expected:    []string{"127.0.0.1:1111"},
+		},
+		{
+			description: "no such port",
+			tmpl:        httpTemplate,
+			selector:    PortSelector{Name: "metrics"},
+			err:         true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			urls, err := portURLsForService(client, "127.0.0.1", "mock-dashboard", "default", test.tmpl, test.selector, test.https)
+			if (err != nil) != test.err {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if test.err {
+				if !strings.Contains(err.Error(), "http(80), https(443)") {
+					t.Errorf("Expected the error to list the ports, got %s", err)
+				}
+				return
+			}
+			if actual := urlsOf(urls); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
 			}
 		})
 	}
@@ -389,7 +462,7 @@ func TestGetServiceURLsForService(t *testing.T) {
 			k8s = &MockClientGetter{
 				servicesMap: serviceNamespaces,
 			}
-			urls, err := GetServiceURLsForService(test.api, test.namespace, test.service, defaultTemplate)
+			urls, err := GetServiceURLsForService(test.api, test.namespace, test.service, defaultTemplate, PortSelector{})
 			if err != nil && !test.err {
 				t.Errorf("Error GetServiceURLsForService %s", err)
 			}
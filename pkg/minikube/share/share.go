@@ -0,0 +1,161 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	machinessh "github.com/docker/machine/libmachine/ssh"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// Config settings of the relay
+const (
+	RelaySetting        = "share-relay"
+	RelayKeySetting     = "share-relay-key"
+	RelayHostKeySetting = "share-relay-host-key"
+)
+
+const defaultSSHPort = "22"
+
+// Relay is an SSH server allowing remote port forwarding on its public
+// interfaces, e.g. an OpenSSH server with GatewayPorts set to clientspecified.
+type Relay struct {
+	User string
+	// Address is the host:port of the SSH server
+	Address string
+	// KeyFile is the private key used to log in
+	KeyFile string
+	// HostKey is the public key of the server, in the authorized_keys format.
+	// When empty, the key of the server isn't checked.
+	HostKey string
+}
+
+// ParseRelay parses a relay given as ssh://user@host[:port].
+func ParseRelay(relay, keyFile, hostKey string) (*Relay, error) {
+	u, err := url.Parse(relay)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid relay %q", relay)
+	}
+	if u.Scheme != "ssh" || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("Invalid relay %q, expected ssh://user@host[:port]", relay)
+	}
+	address := u.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, defaultSSHPort)
+	}
+	return &Relay{
+		User:    u.User.Username(),
+		Address: address,
+		KeyFile: keyFile,
+		HostKey: hostKey,
+	}, nil
+}
+
+// Host returns the host name of the relay, under which services are shared.
+func (r *Relay) Host() string {
+	host, _, err := net.SplitHostPort(r.Address)
+	if err != nil {
+		return r.Address
+	}
+	return host
+}
+
+// Dial logs in to the relay.
+func (r *Relay) Dial() (*ssh.Client, error) {
+	auth := &machinessh.Auth{}
+	if r.KeyFile != "" {
+		auth.Keys = []string{r.KeyFile}
+	}
+	config, err := machinessh.NewNativeConfig(r.User, auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating ssh config for the relay")
+	}
+	if r.HostKey != "" {
+		expected, _, _, _, err := ssh.ParseAuthorizedKey([]byte(r.HostKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing %s", RelayHostKeySetting)
+		}
+		config.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if !bytes.Equal(key.Marshal(), expected.Marshal()) {
+				return fmt.Errorf("The host key of the relay %s doesn't match %s", hostname, RelayHostKeySetting)
+			}
+			return nil
+		}
+	} else {
+		glog.Warningf("%s is not set, the host key of the relay %s isn't checked", RelayHostKeySetting, r.Address)
+	}
+	client, err := ssh.Dial("tcp", r.Address, &config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error connecting to the relay %s", r.Address)
+	}
+	return client, nil
+}
+
+// Listen asks the relay to listen on a port allocated by it, on all its
+// interfaces, and returns the listener along with the URL it is reached at.
+func Listen(r *Relay, client *ssh.Client) (net.Listener, string, error) {
+	l, err := client.Listen("tcp", "0.0.0.0:0")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Error listening on the relay, check that it allows remote port forwarding on its public interfaces")
+	}
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		l.Close()
+		return nil, "", errors.Wrap(err, "Error getting the port allocated by the relay")
+	}
+	return l, "http://" + net.JoinHostPort(r.Host(), port), nil
+}
+
+// Handler returns the handler proxying requests to the target, once they
+// pass basic authentication with the user and password.
+func Handler(target *url.URL, user, password string) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		u, p, ok := req.BasicAuth()
+		if !ok || !secureEqual(u, user) || !secureEqual(p, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="minikube share"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		req.Header.Del("Authorization")
+		proxy.ServeHTTP(w, req)
+	})
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// GeneratePassword returns a random password for sharing a service.
+func GeneratePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Error generating password")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseRelay(t *testing.T) {
+	var tcs = []struct {
+		relay     string
+		expected  *Relay
+		shouldErr bool
+	}{
+		{
+			relay:    "ssh://share@relay.example.com",
+			expected: &Relay{User: "share", Address: "relay.example.com:22", KeyFile: "key"},
+		},
+		{
+			relay:    "ssh://share@relay.example.com:2222",
+			expected: &Relay{User: "share", Address: "relay.example.com:2222", KeyFile: "key"},
+		},
+		{relay: "relay.example.com", shouldErr: true},
+		{relay: "ssh://relay.example.com", shouldErr: true},
+		{relay: "https://share@relay.example.com", shouldErr: true},
+	}
+	for _, test := range tcs {
+		t.Run(test.relay, func(t *testing.T) {
+			r, err := ParseRelay(test.relay, "key", "")
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if !reflect.DeepEqual(r, test.expected) {
+				t.Errorf("Expected relay %+v, got %+v", test.expected, r)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected the credentials not to be passed to the service")
+		}
+		w.Write([]byte("shared"))
+	}))
+	defer backend.Close()
+	target, _ := url.Parse(backend.URL)
+	server := httptest.NewServer(Handler(target, "minikube", "secret"))
+	defer server.Close()
+
+	var tcs = []struct {
+		description    string
+		user, password string
+		expected       int
+	}{
+		{description: "valid credentials", user: "minikube", password: "secret", expected: http.StatusOK},
+		{description: "wrong password", user: "minikube", password: "guess", expected: http.StatusUnauthorized},
+		{description: "no credentials", expected: http.StatusUnauthorized},
+	}
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			if test.user != "" {
+				req.SetBasicAuth(test.user, test.password)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.expected {
+				t.Errorf("Expected status %d, got %d", test.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	a, err := GeneratePassword()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	b, err := GeneratePassword()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if a == b || len(a) < 16 {
+		t.Errorf("Expected distinct random passwords, got %s and %s", a, b)
+	}
+}
@@ -22,10 +22,12 @@ import (
 	"net"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/docker/machine/libmachine/drivers"
 	machinessh "github.com/docker/machine/libmachine/ssh"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -63,23 +65,74 @@ func NewSSHClient(d drivers.Driver) (*ssh.Client, error) {
 }
 
 func DeleteAddon(a *assets.Addon, client *ssh.Client) error {
-	var err error
-	for _, f := range a.Assets {
+	for _, f := range a.GetTargets() {
 		if err := DeleteFile(f, client); err != nil {
-			err = errors.Wrap(err, "")
+			return errors.Wrapf(err, "Error deleting %s", f.GetTargetName())
 		}
 	}
-	return err
+	return nil
 }
 
 func TransferAddon(a *assets.Addon, client *ssh.Client) error {
-	var err error
-	for _, f := range a.Assets {
+	files, err := a.GetAssets()
+	if err != nil {
+		return errors.Wrap(err, "Error getting addon assets")
+	}
+	return TransferFiles(files, client)
+}
+
+// TransferFiles copies the files to the remote machine, skipping the ones
+// whose contents on the machine already match.
+func TransferFiles(files []assets.CopyableFile, client *ssh.Client) error {
+	checksums, err := remoteChecksums(files, client)
+	if err != nil {
+		return errors.Wrap(err, "Error getting checksums of remote files")
+	}
+	for _, f := range files {
+		if sum := f.GetChecksum(); sum != "" && checksums[targetPath(f)] == sum {
+			glog.Infof("Skipping %s, contents unchanged", targetPath(f))
+			continue
+		}
 		if err := TransferFile(f, client); err != nil {
-			errors.Wrap(err, "")
+			return errors.Wrapf(err, "Error transferring %s", f.GetTargetName())
+		}
+	}
+	return nil
+}
+
+// remoteChecksums returns the sha256 of the files already on the remote
+// machine, keyed by path. Files that don't exist yet are left out.
+func remoteChecksums(files []assets.CopyableFile, client *ssh.Client) (map[string]string, error) {
+	checksums := map[string]string{}
+	if len(files) == 0 {
+		return checksums, nil
+	}
+	out, err := RunCommandOutput(client, GetChecksumCommand(files))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
 		}
+		checksums[fields[1]] = fields[0]
 	}
-	return err
+	return checksums, nil
+}
+
+// GetChecksumCommand returns the command printing the sha256 of the
+// files on the remote machine.
+func GetChecksumCommand(files []assets.CopyableFile) string {
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, targetPath(f))
+	}
+	return fmt.Sprintf("sudo sha256sum %s 2>/dev/null || true", strings.Join(paths, " "))
+}
+
+func targetPath(f assets.CopyableFile) string {
+	return filepath.Join(f.GetTargetDir(), f.GetTargetName())
 }
 
 func TransferFile(f assets.CopyableFile, client *ssh.Client) error {
@@ -140,6 +193,38 @@ func RunCommand(c *ssh.Client, cmd string) error {
 	return s.Run(cmd)
 }
 
+// RunCommandOutput runs the command on the remote machine and returns its output.
+func RunCommandOutput(c *ssh.Client, cmd string) (string, error) {
+	s, err := c.NewSession()
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating new session for ssh client")
+	}
+	defer s.Close()
+
+	out, err := s.Output(cmd)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error running command: %s", cmd)
+	}
+	return string(out), nil
+}
+
+// RunCommandWithInput runs the command on the remote machine, reading in as
+// its input, and returns its output.
+func RunCommandWithInput(c *ssh.Client, cmd string, in io.Reader) (string, error) {
+	s, err := c.NewSession()
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating new session for ssh client")
+	}
+	defer s.Close()
+
+	s.Stdin = in
+	out, err := s.CombinedOutput(cmd)
+	if err != nil {
+		return string(out), errors.Wrapf(err, "Error running command: %s", cmd)
+	}
+	return string(out), nil
+}
+
 type sshHost struct {
 	IP         string
 	Port       int
@@ -170,5 +255,5 @@ func DeleteFile(f assets.CopyableFile, client *ssh.Client) error {
 }
 
 func GetDeleteFileCommand(f assets.CopyableFile) string {
-	return fmt.Sprintf("sudo rm %s", filepath.Join(f.GetTargetDir(), f.GetTargetName()))
+	return fmt.Sprintf("sudo rm %s", targetPath(f))
 }
@@ -18,10 +18,12 @@ package sshutil
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/docker/machine/libmachine/drivers"
 
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/tests"
 )
 
@@ -115,3 +117,40 @@ func TestTransfer(t *testing.T) {
 		t.Fatalf("Unexpected error: %s", err)
 	}
 }
+
+func TestTransferFilesSkipsUnchanged(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	c, err := NewSSHClient(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	unchanged := assets.NewMemoryAssetFromBytes([]byte("unchanged"), "/etc/unchanged", "file", "0640")
+	changed := assets.NewMemoryAssetFromBytes([]byte("changed"), "/etc/changed", "file", "0640")
+	files := []assets.CopyableFile{unchanged, changed}
+	s.SetCommandToOutput(map[string]string{
+		GetChecksumCommand(files): fmt.Sprintf("%s  /etc/unchanged/file\n%s  /etc/changed/file\n",
+			unchanged.GetChecksum(), "0000"),
+	})
+
+	if err := TransferFiles(files, c); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, ok := s.Commands["sudo scp -t /etc/unchanged"]; ok {
+		t.Fatalf("Expected unchanged file not to be transferred")
+	}
+	if _, ok := s.Commands["sudo scp -t /etc/changed"]; !ok {
+		t.Fatalf("Expected changed file to be transferred")
+	}
+}
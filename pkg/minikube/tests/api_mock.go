@@ -80,7 +80,11 @@ func (api *MockAPI) Exists(name string) (bool, error) {
 
 // List the existing hosts.
 func (api *MockAPI) List() ([]string, error) {
-	return []string{}, nil
+	names := []string{}
+	for name := range api.Hosts {
+		names = append(names, name)
+	}
+	return names, nil
 }
 
 // Load loads a host from disk.
@@ -0,0 +1,77 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package top
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	units "github.com/docker/go-units"
+)
+
+// Render writes the snapshot as tables, showing at most maxPods pods
+func Render(w io.Writer, s *Snapshot, maxPods int) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "VM\tDISK\tMEMORY\n")
+	fmt.Fprintf(tw, "minikube\t%s\t%s\n",
+		ratio(s.VM.DiskUsedBytes, s.VM.DiskTotalBytes),
+		ratio(s.VM.MemoryUsedBytes, s.VM.MemoryTotalBytes))
+	fmt.Fprintln(tw)
+
+	fmt.Fprintf(tw, "NODE\tCPU\tMEMORY\n")
+	fmt.Fprintf(tw, "%s\t%dm\t%s\n", s.Node.Name, s.Node.CPU, units.BytesSize(float64(s.Node.MemoryBytes)))
+	fmt.Fprintln(tw)
+
+	fmt.Fprintf(tw, "NAMESPACE\tPOD\tCPU\tMEMORY\n")
+	for i, p := range s.Pods {
+		if i == maxPods {
+			fmt.Fprintf(tw, "...\t%d more\t\t\n", len(s.Pods)-maxPods)
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%dm\t%s\n", p.Namespace, p.Name, p.CPU, units.BytesSize(float64(p.MemoryBytes)))
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintf(tw, "ADDON\tREADY\tSTATUS\n")
+	for _, a := range s.Addons {
+		fmt.Fprintf(tw, "%s\t%d/%d\t%s\n", a.Name, a.Ready, a.Desired, a.Status())
+	}
+	for _, err := range s.Errors {
+		fmt.Fprintf(tw, "\n%s\n", err)
+	}
+	return tw.Flush()
+}
+
+// Status summarizes the health of the addon
+func (a AddonHealth) Status() string {
+	switch {
+	case a.Desired == 0:
+		return "NotFound"
+	case a.Ready < a.Desired:
+		return "Pending"
+	default:
+		return "Healthy"
+	}
+}
+
+func ratio(used, total uint64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%s (%d%%)", units.BytesSize(float64(used)), units.BytesSize(float64(total)), used*100/total)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package top collects the resource usage of the minikube VM and cluster.
+package top
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// addonLabel is set on the objects created by minikube addons
+const addonLabel = "kubernetes.io/minikube-addons"
+
+// vmUsageCommand prints the disk usage of the persistent storage followed by the memory usage of the VM
+const vmUsageCommand = "df -k /mnt/sda1 | tail -n 1; free -k | grep Mem:"
+
+// Snapshot is the resource usage at a point in time
+type Snapshot struct {
+	VM     VMUsage
+	Node   Usage
+	Pods   []PodUsage
+	Addons []AddonHealth
+	// Errors are the failures collecting parts of the snapshot
+	Errors []error
+}
+
+// Usage is the CPU and memory used by a node or pod
+type Usage struct {
+	Name string
+	// CPU is in millicores
+	CPU         uint64
+	MemoryBytes uint64
+}
+
+// PodUsage is the usage of a pod
+type PodUsage struct {
+	Namespace string
+	Usage
+}
+
+// VMUsage is the disk and memory usage of the VM
+type VMUsage struct {
+	DiskUsedBytes    uint64
+	DiskTotalBytes   uint64
+	MemoryUsedBytes  uint64
+	MemoryTotalBytes uint64
+}
+
+// AddonHealth is the number of ready and desired pods of an enabled addon
+type AddonHealth struct {
+	Name    string
+	Ready   int
+	Desired int
+}
+
+type sshRunner interface {
+	RunSSHCommand(string) (string, error)
+}
+
+// GetVMUsage returns the disk and memory usage of the VM
+func GetVMUsage(h sshRunner) (VMUsage, error) {
+	out, err := h.RunSSHCommand(vmUsageCommand)
+	if err != nil {
+		return VMUsage{}, errors.Wrapf(err, "Error getting VM usage: %s", out)
+	}
+	return parseVMUsage(out)
+}
+
+func parseVMUsage(out string) (VMUsage, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		return VMUsage{}, errors.Errorf("unexpected VM usage output: %q", out)
+	}
+	// Filesystem 1K-blocks Used Available Use% Mounted on
+	df := strings.Fields(lines[0])
+	// Mem: total used free ...
+	free := strings.Fields(lines[1])
+	if len(df) < 3 || len(free) < 3 {
+		return VMUsage{}, errors.Errorf("unexpected VM usage output: %q", out)
+	}
+	var kb [4]uint64
+	for i, s := range []string{df[1], df[2], free[1], free[2]} {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return VMUsage{}, errors.Wrapf(err, "unexpected VM usage output: %q", out)
+		}
+		kb[i] = v * 1024
+	}
+	return VMUsage{
+		DiskTotalBytes:   kb[0],
+		DiskUsedBytes:    kb[1],
+		MemoryTotalBytes: kb[2],
+		MemoryUsedBytes:  kb[3],
+	}, nil
+}
+
+// summary is the subset of the kubelet stats summary used
+type summary struct {
+	Node struct {
+		NodeName string `json:"nodeName"`
+		CPU      cpuStats
+		Memory   memoryStats
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			CPU    cpuStats    `json:"cpu"`
+			Memory memoryStats `json:"memory"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+type cpuStats struct {
+	UsageNanoCores uint64 `json:"usageNanoCores"`
+}
+
+type memoryStats struct {
+	WorkingSetBytes uint64 `json:"workingSetBytes"`
+}
+
+// GetClusterUsage returns the usage of the node and its pods, as reported by
+// the cadvisor stats of the kubelet, with the pods sorted by CPU usage.
+func GetClusterUsage(client kubernetes.Interface) (Usage, []PodUsage, error) {
+	nodes, err := client.Core().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return Usage{}, nil, errors.Wrap(err, "Error listing nodes")
+	}
+	if len(nodes.Items) == 0 {
+		return Usage{}, nil, errors.New("no nodes registered")
+	}
+	raw, err := client.Core().RESTClient().Get().
+		AbsPath("/api/v1/nodes", nodes.Items[0].Name, "proxy/stats/summary").
+		Do().Raw()
+	if err != nil {
+		return Usage{}, nil, errors.Wrap(err, "Error getting kubelet stats")
+	}
+	return parseSummary(raw)
+}
+
+func parseSummary(raw []byte) (Usage, []PodUsage, error) {
+	var s summary
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return Usage{}, nil, errors.Wrap(err, "Error parsing kubelet stats")
+	}
+	node := Usage{
+		Name:        s.Node.NodeName,
+		CPU:         s.Node.CPU.UsageNanoCores / 1e6,
+		MemoryBytes: s.Node.Memory.WorkingSetBytes,
+	}
+	var pods []PodUsage
+	for _, p := range s.Pods {
+		pod := PodUsage{Namespace: p.PodRef.Namespace, Usage: Usage{Name: p.PodRef.Name}}
+		for _, c := range p.Containers {
+			pod.CPU += c.CPU.UsageNanoCores / 1e6
+			pod.MemoryBytes += c.Memory.WorkingSetBytes
+		}
+		pods = append(pods, pod)
+	}
+	sort.Sort(byCPU(pods))
+	return node, pods, nil
+}
+
+type byCPU []PodUsage
+
+func (p byCPU) Len() int      { return len(p) }
+func (p byCPU) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byCPU) Less(i, j int) bool {
+	if p[i].CPU != p[j].CPU {
+		return p[i].CPU > p[j].CPU
+	}
+	return p[i].Namespace+"/"+p[i].Name < p[j].Namespace+"/"+p[j].Name
+}
+
+// GetAddonHealth returns the number of ready pods of the enabled addons,
+// counting the replication controllers and standalone pods labeled with the addon name.
+func GetAddonHealth(client kubernetes.Interface) ([]AddonHealth, error) {
+	var names []string
+	for name, addon := range assets.Addons {
+		if enabled, err := addon.IsEnabled(); err == nil && enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var health []AddonHealth
+	for _, name := range names {
+		h := AddonHealth{Name: name}
+		opts := v1.ListOptions{LabelSelector: addonLabel + "=" + name}
+		rcs, err := client.Core().ReplicationControllers(v1.NamespaceAll).List(opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing replication controllers of addon %s", name)
+		}
+		for _, rc := range rcs.Items {
+			if rc.Spec.Replicas != nil {
+				h.Desired += int(*rc.Spec.Replicas)
+			}
+			h.Ready += int(rc.Status.ReadyReplicas)
+		}
+		pods, err := client.Core().Pods(v1.NamespaceAll).List(opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing pods of addon %s", name)
+		}
+		for _, pod := range pods.Items {
+			h.Desired++
+			if pod.Status.Phase == v1.PodRunning {
+				h.Ready++
+			}
+		}
+		health = append(health, h)
+	}
+	return health, nil
+}
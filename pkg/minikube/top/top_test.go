@@ -0,0 +1,93 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package top
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseVMUsage(t *testing.T) {
+	out := `/dev/sda1        19049892  1234567  16826309   7% /mnt/sda1
+Mem:        2048000     1024000      512000       12000      512000     1536000
+`
+	usage, err := parseVMUsage(out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := VMUsage{
+		DiskTotalBytes:   19049892 * 1024,
+		DiskUsedBytes:    1234567 * 1024,
+		MemoryTotalBytes: 2048000 * 1024,
+		MemoryUsedBytes:  1024000 * 1024,
+	}
+	if usage != expected {
+		t.Errorf("Expected %+v, got %+v", expected, usage)
+	}
+
+	if _, err := parseVMUsage("df: /mnt/sda1: No such file or directory"); err == nil {
+		t.Errorf("Expected error parsing unexpected output")
+	}
+}
+
+func TestParseSummary(t *testing.T) {
+	raw := []byte(`{
+  "node": {"nodeName": "minikube", "cpu": {"usageNanoCores": 250000000}, "memory": {"workingSetBytes": 1048576}},
+  "pods": [
+    {"podRef": {"name": "dns", "namespace": "kube-system"},
+     "containers": [{"cpu": {"usageNanoCores": 1000000}, "memory": {"workingSetBytes": 1024}},
+                    {"cpu": {"usageNanoCores": 2000000}, "memory": {"workingSetBytes": 1024}}]},
+    {"podRef": {"name": "web", "namespace": "default"},
+     "containers": [{"cpu": {"usageNanoCores": 50000000}, "memory": {"workingSetBytes": 4096}}]}
+  ]
+}`)
+	node, pods, err := parseSummary(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if node.Name != "minikube" || node.CPU != 250 || node.MemoryBytes != 1048576 {
+		t.Errorf("Unexpected node usage %+v", node)
+	}
+	if len(pods) != 2 || pods[0].Name != "web" || pods[1].CPU != 3 || pods[1].MemoryBytes != 2048 {
+		t.Errorf("Unexpected pod usage %+v", pods)
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := &Snapshot{
+		Node: Usage{Name: "minikube", CPU: 250},
+		Pods: []PodUsage{
+			{Namespace: "default", Usage: Usage{Name: "web", CPU: 50}},
+			{Namespace: "kube-system", Usage: Usage{Name: "dns", CPU: 3}},
+		},
+		Addons: []AddonHealth{{Name: "dashboard", Ready: 0, Desired: 1}},
+	}
+	var b bytes.Buffer
+	if err := Render(&b, s, 1); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	out := b.String()
+	for _, expected := range []string{"web", "1 more", "dashboard  0/1    Pending"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "dns") {
+		t.Errorf("Expected pods beyond the maximum to be omitted, got:\n%s", out)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tunnel routes the service network of the cluster through the
+// minikube VM, and removes the routes left behind by a tunnel which was killed.
+package tunnel
+
+import (
+	"net"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/util"
+)
+
+// DaemonName is the name the tunnel is registered with as a daemon
+const DaemonName = "tunnel"
+
+// runCommand runs a command changing the routing table, replaced in tests
+var runCommand = func(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	return string(out), err
+}
+
+// notFound are the outputs of the route commands of linux, darwin and
+// windows when deleting a route which doesn't exist
+var notFound = []string{"No such process", "not in table", "Element not found"}
+
+// ServiceRoute returns the route of the service network, the default one
+// when empty, through the IP of the VM.
+func ServiceRoute(serviceCIDR, ip string) (daemons.Route, error) {
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceClusterIPRange
+	}
+	_, n, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return daemons.Route{}, errors.Wrap(err, "Error parsing the service cluster IP range")
+	}
+	if net.ParseIP(ip) == nil {
+		return daemons.Route{}, errors.Errorf("%q is not the IP of the VM", ip)
+	}
+	return daemons.Route{Network: n.String(), Gateway: ip}, nil
+}
+
+// routeCommand returns the command adding the route, or deleting it, on the OS.
+func routeCommand(goos string, r daemons.Route, add bool) ([]string, error) {
+	_, n, err := net.ParseCIDR(r.Network)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the network of route %s", r.Network)
+	}
+	switch goos {
+	case "linux":
+		if add {
+			return []string{"sudo", "ip", "route", "add", r.Network, "via", r.Gateway}, nil
+		}
+		return []string{"sudo", "ip", "route", "del", r.Network}, nil
+	case "darwin":
+		if add {
+			return []string{"sudo", "route", "-n", "add", "-net", r.Network, r.Gateway}, nil
+		}
+		return []string{"sudo", "route", "-n", "delete", "-net", r.Network}, nil
+	case "windows":
+		if add {
+			return []string{"route", "ADD", n.IP.String(), "MASK", net.IP(n.Mask).String(), r.Gateway}, nil
+		}
+		return []string{"route", "DELETE", n.IP.String()}, nil
+	}
+	return nil, errors.Errorf("Routes are not supported on %s", goos)
+}
+
+// AddRoute adds the route to the routing table of the host.
+func AddRoute(r daemons.Route) error {
+	cmd, err := routeCommand(runtime.GOOS, r, true)
+	if err != nil {
+		return err
+	}
+	if out, err := runCommand(cmd[0], cmd[1:]...); err != nil {
+		return errors.Wrapf(err, "Error adding the route to %s via %s: %s", r.Network, r.Gateway, out)
+	}
+	return nil
+}
+
+// DeleteRoute removes the route from the routing table of the host. A route
+// which is already gone, e.g. after a reboot, is not an error.
+func DeleteRoute(r daemons.Route) error {
+	cmd, err := routeCommand(runtime.GOOS, r, false)
+	if err != nil {
+		return err
+	}
+	out, err := runCommand(cmd[0], cmd[1:]...)
+	if err == nil {
+		return nil
+	}
+	for _, s := range notFound {
+		if strings.Contains(out, s) {
+			glog.Infof("The route to %s is already gone", r.Network)
+			return nil
+		}
+	}
+	return errors.Wrapf(err, "Error deleting the route to %s via %s: %s", r.Network, r.Gateway, out)
+}
+
+// Cleanup removes the routes a daemon which exited left behind, then its
+// registration, which is kept if a route can't be removed.
+func Cleanup(d daemons.Daemon) error {
+	for _, r := range d.Routes {
+		if err := DeleteRoute(r); err != nil {
+			return err
+		}
+	}
+	return daemons.Unregister(d)
+}
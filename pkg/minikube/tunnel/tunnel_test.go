@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemons"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestServiceRoute(t *testing.T) {
+	r, err := ServiceRoute("", "192.168.99.100")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := (daemons.Route{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}); r != expected {
+		t.Errorf("Expected the route of the default service network %v, got %v", expected, r)
+	}
+	r, err = ServiceRoute("10.96.0.1/12", "192.168.99.100")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if r.Network != "10.96.0.0/12" {
+		t.Errorf("Expected the network 10.96.0.0/12, got %s", r.Network)
+	}
+	if _, err := ServiceRoute("10.0.0.0/24", ""); err == nil {
+		t.Errorf("Expected an error without the IP of the VM")
+	}
+	if _, err := ServiceRoute("10.0.0.0", "192.168.99.100"); err == nil {
+		t.Errorf("Expected an error for an invalid service network")
+	}
+}
+
+func TestRouteCommand(t *testing.T) {
+	r := daemons.Route{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}
+	var tests = []struct {
+		goos   string
+		add    bool
+		expect string
+	}{
+		{goos: "linux", add: true, expect: "sudo ip route add 10.0.0.0/24 via 192.168.99.100"},
+		{goos: "linux", expect: "sudo ip route del 10.0.0.0/24"},
+		{goos: "darwin", add: true, expect: "sudo route -n add -net 10.0.0.0/24 192.168.99.100"},
+		{goos: "darwin", expect: "sudo route -n delete -net 10.0.0.0/24"},
+		{goos: "windows", add: true, expect: "route ADD 10.0.0.0 MASK 255.255.255.0 192.168.99.100"},
+		{goos: "windows", expect: "route DELETE 10.0.0.0"},
+	}
+	for _, test := range tests {
+		cmd, err := routeCommand(test.goos, r, test.add)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if c := strings.Join(cmd, " "); c != test.expect {
+			t.Errorf("Expected %q on %s, got %q", test.expect, test.goos, c)
+		}
+	}
+	if _, err := routeCommand("plan9", r, true); err == nil {
+		t.Errorf("Expected an error on an unsupported OS")
+	}
+}
+
+type fakeRoutes struct {
+	commands []string
+	output   string
+	err      error
+}
+
+func (f *fakeRoutes) run(name string, args ...string) (string, error) {
+	f.commands = append(f.commands, strings.Join(append([]string{name}, args...), " "))
+	return f.output, f.err
+}
+
+func fakeRunCommand(f *fakeRoutes) func() {
+	run := runCommand
+	runCommand = f.run
+	return func() { runCommand = run }
+}
+
+func TestDeleteRoute(t *testing.T) {
+	if _, err := routeCommand(runtime.GOOS, daemons.Route{Network: "10.0.0.0/24"}, false); err != nil {
+		t.Skipf("Routes are not supported on %s", runtime.GOOS)
+	}
+	r := daemons.Route{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}
+	f := &fakeRoutes{}
+	defer fakeRunCommand(f)()
+
+	if err := DeleteRoute(r); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(f.commands) != 1 {
+		t.Fatalf("Expected one command, got %v", f.commands)
+	}
+
+	f.output, f.err = "RTNETLINK answers: No such process", errors.New("exit status 2")
+	if err := DeleteRoute(r); err != nil {
+		t.Errorf("Expected no error for a route already gone, got %s", err)
+	}
+	f.output = "sudo: a password is required"
+	if err := DeleteRoute(r); err == nil {
+		t.Errorf("Expected an error when the route can't be deleted")
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	if _, err := routeCommand(runtime.GOOS, daemons.Route{Network: "10.0.0.0/24"}, false); err != nil {
+		t.Skipf("Routes are not supported on %s", runtime.GOOS)
+	}
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	f := &fakeRoutes{}
+	defer fakeRunCommand(f)()
+
+	exited := daemons.Daemon{
+		Name:   "tunnel-1",
+		PID:    1 << 22,
+		Routes: []daemons.Route{{Network: "10.0.0.0/24", Gateway: "192.168.99.100"}},
+	}
+	data, _ := json.Marshal(exited)
+	if err := os.MkdirAll(constants.MakeMiniPath("daemons"), 0755); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := ioutil.WriteFile(constants.MakeMiniPath("daemons", exited.Name+".json"), data, 0644); err != nil {
+		t.Fatalf("Unexpected error writing daemon: %s", err)
+	}
+
+	f.output, f.err = "sudo: a password is required", errors.New("exit status 1")
+	if err := Cleanup(exited); err == nil {
+		t.Fatalf("Expected an error when the route can't be deleted")
+	}
+	if stale, _ := daemons.Stale(); len(stale) != 1 {
+		t.Fatalf("Expected the registration to be kept until its routes are deleted, got %v", stale)
+	}
+
+	f.commands, f.output, f.err = nil, "", nil
+	if err := Cleanup(exited); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected, _ := routeCommand(runtime.GOOS, exited.Routes[0], false)
+	if !reflect.DeepEqual(f.commands, []string{strings.Join(expected, " ")}) {
+		t.Errorf("Expected the route to be deleted with %v, got %v", expected, f.commands)
+	}
+	if stale, _ := daemons.Stale(); len(stale) != 0 {
+		t.Errorf("Expected the registration to be removed, got %v", stale)
+	}
+}
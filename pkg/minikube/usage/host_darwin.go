@@ -0,0 +1,44 @@
+// +build darwin
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"syscall"
+
+	"github.com/golang/glog"
+	"golang.org/x/sys/unix"
+)
+
+func hostMemoryMB() int {
+	bytes, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		glog.Infof("Error getting the memory of the host: %s", err)
+		return 0
+	}
+	return int(bytes / (1024 * 1024))
+}
+
+func hostDiskMB(path string) int {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		glog.Infof("Error getting the size of the disk of %s: %s", path, err)
+		return 0
+	}
+	return int(fs.Blocks * uint64(fs.Bsize) / (1024 * 1024))
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage accounts for the host resources the VMs are configured with,
+// so that starting another one can be checked against the limits set in the
+// global config and the capacity of the host. The allocations are kept by
+// machine, which the profiles may share.
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Config settings of the limits across all VMs, in the global config
+const (
+	MaxCPUsSetting     = "max-cpus"
+	MaxMemorySetting   = "max-memory"
+	MaxDiskSizeSetting = "max-disk-size"
+)
+
+const allocationFile = "allocation.json"
+
+// Allocation is an amount of host resources. A zero field of limits or of
+// the host capacity means it is unlimited or unknown.
+type Allocation struct {
+	CPUs     int `json:"cpus"`
+	MemoryMB int `json:"memoryMB"`
+	DiskMB   int `json:"diskMB"`
+}
+
+// Add returns the sum of the allocations.
+func (a Allocation) Add(b Allocation) Allocation {
+	return Allocation{
+		CPUs:     a.CPUs + b.CPUs,
+		MemoryMB: a.MemoryMB + b.MemoryMB,
+		DiskMB:   a.DiskMB + b.DiskMB,
+	}
+}
+
+// allocationPath returns the file recording the allocation of the machine,
+// in its directory, which deleting the machine removes.
+func allocationPath(machine string) string {
+	return constants.MakeMiniPath("machines", machine, allocationFile)
+}
+
+// Record saves the resources the VM of the machine is configured with.
+func Record(machine string, a Allocation) error {
+	path := allocationPath(machine)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "Error creating directory of %s", path)
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "Error recording the resources of machine %q", machine)
+	}
+	return nil
+}
+
+// Remove forgets the allocation of the machine, once its VM is deleted.
+func Remove(machine string) error {
+	if err := os.Remove(allocationPath(machine)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Error removing the resources of machine %q", machine)
+	}
+	return nil
+}
+
+// List returns the recorded allocations by machine.
+func List() (map[string]Allocation, error) {
+	allocations := map[string]Allocation{}
+	dirs, err := ioutil.ReadDir(constants.MakeMiniPath("machines"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "Error reading machines directory")
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		path := allocationPath(d.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "Error reading %s", path)
+		}
+		var a Allocation
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, errors.Wrapf(err, "Error parsing %s", path)
+		}
+		allocations[d.Name()] = a
+	}
+	return allocations, nil
+}
+
+// Machines returns the names of the machines of the allocations, sorted.
+func Machines(allocations map[string]Allocation) []string {
+	var machines []string
+	for m := range allocations {
+		machines = append(machines, m)
+	}
+	sort.Strings(machines)
+	return machines
+}
+
+// Total returns the sum of the allocations of all the machines.
+func Total(allocations map[string]Allocation) Allocation {
+	var total Allocation
+	for _, a := range allocations {
+		total = total.Add(a)
+	}
+	return total
+}
+
+// CheckLimits returns an error if the total exceeds any of the limits.
+func CheckLimits(total, limits Allocation) error {
+	for _, r := range compare(total, limits) {
+		if r.requested > r.available {
+			return fmt.Errorf("%s across all VMs would be %s, above the %s limit of %s set with %q",
+				r.name, r.format(r.requested), r.name, r.format(r.available), r.setting)
+		}
+	}
+	return nil
+}
+
+// Oversubscribed returns warnings for each resource of the total exceeding
+// the capacity of the host.
+func Oversubscribed(total, capacity Allocation) []string {
+	var warnings []string
+	for _, r := range compare(total, capacity) {
+		if r.requested > r.available {
+			warnings = append(warnings, fmt.Sprintf("%s across all VMs would be %s, but the host only has %s",
+				r.name, r.format(r.requested), r.format(r.available)))
+		}
+	}
+	return warnings
+}
+
+type resource struct {
+	name, setting        string
+	requested, available int
+	format               func(int) string
+}
+
+// compare pairs the resources of a and b, skipping those unset in b.
+func compare(a, b Allocation) []resource {
+	cpus := func(n int) string { return fmt.Sprintf("%d CPUs", n) }
+	mb := func(n int) string { return fmt.Sprintf("%dMB", n) }
+	var resources []resource
+	for _, r := range []resource{
+		{"CPUs", MaxCPUsSetting, a.CPUs, b.CPUs, cpus},
+		{"Memory", MaxMemorySetting, a.MemoryMB, b.MemoryMB, mb},
+		{"Disk", MaxDiskSizeSetting, a.DiskMB, b.DiskMB, mb},
+	} {
+		if r.available > 0 {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// HostCapacity returns the CPUs and memory of the host, and the size of the
+// disk holding the minikube directory, where the VM disks are created.
+func HostCapacity() Allocation {
+	return Allocation{
+		CPUs:     runtime.NumCPU(),
+		MemoryMB: hostMemoryMB(),
+		DiskMB:   hostDiskMB(constants.GetMinipath()),
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestRecord(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	expected := map[string]Allocation{
+		"minikube":       {CPUs: 2, MemoryMB: 2048, DiskMB: 20000},
+		"minikube-node2": {CPUs: 4, MemoryMB: 8192, DiskMB: 40000},
+	}
+	for m, a := range expected {
+		if err := Record(m, a); err != nil {
+			t.Fatalf("Unexpected error recording machine %q: %s", m, err)
+		}
+	}
+	// Recording again replaces the allocation of the machine
+	if err := Record("minikube", expected["minikube"]); err != nil {
+		t.Fatalf("Unexpected error recording machine again: %s", err)
+	}
+	allocations, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing allocations: %s", err)
+	}
+	if !reflect.DeepEqual(allocations, expected) {
+		t.Errorf("Expected allocations %v, got %v", expected, allocations)
+	}
+	if total := Total(allocations); total != (Allocation{CPUs: 6, MemoryMB: 10240, DiskMB: 60000}) {
+		t.Errorf("Unexpected total %+v", total)
+	}
+
+	if err := Remove("minikube-node2"); err != nil {
+		t.Fatalf("Unexpected error removing machine: %s", err)
+	}
+	if err := Remove("minikube-node2"); err != nil {
+		t.Fatalf("Unexpected error removing machine twice: %s", err)
+	}
+	allocations, err = List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing allocations: %s", err)
+	}
+	if _, ok := allocations["minikube-node2"]; ok || len(allocations) != 1 {
+		t.Errorf("Expected only the minikube machine to be left, got %v", allocations)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	total := Allocation{CPUs: 6, MemoryMB: 10240, DiskMB: 60000}
+	var tcs = []struct {
+		description string
+		limits      Allocation
+		shouldErr   bool
+	}{
+		{description: "no limits"},
+		{description: "within limits", limits: Allocation{CPUs: 8, MemoryMB: 16384, DiskMB: 100000}},
+		{description: "at the limits", limits: total},
+		{description: "above the cpu limit", limits: Allocation{CPUs: 4}, shouldErr: true},
+		{description: "above the memory limit", limits: Allocation{MemoryMB: 8192}, shouldErr: true},
+		{description: "above the disk limit", limits: Allocation{DiskMB: 50000}, shouldErr: true},
+	}
+	for _, test := range tcs {
+		t.Run(test.description, func(t *testing.T) {
+			err := CheckLimits(total, test.limits)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestOversubscribed(t *testing.T) {
+	total := Allocation{CPUs: 6, MemoryMB: 10240, DiskMB: 60000}
+	if w := Oversubscribed(total, Allocation{CPUs: 8, MemoryMB: 16384}); len(w) != 0 {
+		t.Errorf("Expected no warnings, got %v", w)
+	}
+	expected := []string{
+		"CPUs across all VMs would be 6 CPUs, but the host only has 4 CPUs",
+		"Memory across all VMs would be 10240MB, but the host only has 8192MB",
+	}
+	if w := Oversubscribed(total, Allocation{CPUs: 4, MemoryMB: 8192}); !reflect.DeepEqual(w, expected) {
+		t.Errorf("Expected warnings %v, got %v", expected, w)
+	}
+}
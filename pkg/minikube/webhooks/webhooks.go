@@ -0,0 +1,273 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks posts the lifecycle events of the clusters of this
+// machine, as JSON, to the URLs registered with minikube webhook add, so chat
+// bots and dashboards can keep track of the clusters of a team.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Event is a lifecycle event of a cluster.
+type Event string
+
+const (
+	Started      Event = "started"
+	Stopped      Event = "stopped"
+	AddonEnabled Event = "addon-enabled"
+	CertRotated  Event = "cert-rotated"
+)
+
+// Events are the events webhooks can be registered for.
+var Events = []Event{Started, Stopped, AddonEnabled, CertRotated}
+
+// The headers of the requests, with the event and the signature of the body
+const (
+	EventHeader     = "X-Minikube-Event"
+	SignatureHeader = "X-Minikube-Signature"
+)
+
+// Webhook is a URL the events are posted to.
+type Webhook struct {
+	URL string `json:"url"`
+	// Events are the events posted, all of them when empty
+	Events []Event `json:"events,omitempty"`
+	// Secret is the key of the HMAC-SHA256 of the body in the signature
+	// header, for the receiver to check the events come from minikube
+	Secret string `json:"secret,omitempty"`
+}
+
+// Wants returns whether the event is posted to the webhook.
+func (w Webhook) Wants(e Event) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, want := range w.Events {
+		if want == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is the body of the requests.
+type Notification struct {
+	Event Event     `json:"event"`
+	Time  time.Time `json:"time"`
+	// Profile is the profile of the cluster, empty for the global config
+	Profile string `json:"profile"`
+	// Host and User are the machine and the user running minikube
+	Host    string            `json:"host"`
+	User    string            `json:"user"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Path is the file the webhooks are registered in, for all the profiles.
+func Path() string {
+	return constants.MakeMiniPath("webhooks.json")
+}
+
+// List returns the registered webhooks.
+func List() ([]Webhook, error) {
+	data, err := ioutil.ReadFile(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the webhooks")
+	}
+	var hooks []Webhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the webhooks in %s", Path())
+	}
+	return hooks, nil
+}
+
+func save(hooks []Webhook) error {
+	data, err := json.MarshalIndent(hooks, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The file holds the secrets
+	if err := ioutil.WriteFile(Path(), data, 0600); err != nil {
+		return errors.Wrap(err, "Error writing the webhooks")
+	}
+	return nil
+}
+
+// Validate checks the URL and the events of the webhook.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("%q is not an http or https URL", w.URL)
+	}
+	for _, e := range w.Events {
+		valid := false
+		for _, known := range Events {
+			valid = valid || e == known
+		}
+		if !valid {
+			return errors.Errorf("Unknown event %q, the events are %v", e, Events)
+		}
+	}
+	return nil
+}
+
+// Add registers the webhook, replacing the one of the same URL.
+func Add(w Webhook) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	hooks, err := List()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range hooks {
+		if hooks[i].URL == w.URL {
+			hooks[i], replaced = w, true
+		}
+	}
+	if !replaced {
+		hooks = append(hooks, w)
+	}
+	return save(hooks)
+}
+
+// Remove unregisters the webhook of the URL, and returns whether it was
+// registered.
+func Remove(rawURL string) (bool, error) {
+	hooks, err := List()
+	if err != nil {
+		return false, err
+	}
+	var kept []Webhook
+	for _, w := range hooks {
+		if w.URL != rawURL {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == len(hooks) {
+		return false, nil
+	}
+	return true, save(kept)
+}
+
+// client posts the notifications, it is replaced in tests.
+var client = &http.Client{Timeout: 5 * time.Second}
+
+// Sign returns the signature of the body with the secret, as in the
+// signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts the notification to the webhooks wanting its event, at the same
+// time, and returns the errors of the ones which failed.
+func Send(hooks []Webhook, n Notification) []error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return []error{err}
+	}
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, w := range hooks {
+		if !w.Wants(n.Event) {
+			continue
+		}
+		wg.Add(1)
+		go func(w Webhook) {
+			defer wg.Done()
+			if err := post(w, n.Event, body); err != nil {
+				mu.Lock()
+				errs = append(errs, errors.Wrapf(err, "Error posting the %s event to %s", n.Event, w.URL))
+				mu.Unlock()
+			}
+		}(w)
+	}
+	wg.Wait()
+	return errs
+}
+
+func post(w Webhook, e Event, body []byte) error {
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(e))
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
+
+// Notify posts the event of the cluster of the active profile to the
+// registered webhooks, and warns about the ones which failed on stderr: the
+// event happened regardless.
+func Notify(e Event, details map[string]string, stderr io.Writer) {
+	hooks, err := List()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: %s\n", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	n := Notification{
+		Event:   e,
+		Time:    time.Now().UTC(),
+		Profile: config.ActiveProfile(),
+		Details: details,
+	}
+	n.Host, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		n.User = u.Username
+	}
+	for _, err := range Send(hooks, n) {
+		fmt.Fprintf(stderr, "Warning: %s\n", err)
+	}
+}
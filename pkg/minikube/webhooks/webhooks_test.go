@@ -0,0 +1,133 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestAddRemove(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if err := Add(Webhook{URL: "ftp://example.com"}); err == nil {
+		t.Errorf("Expected an error for a URL which is not http")
+	}
+	if err := Add(Webhook{URL: "https://example.com/hook", Events: []Event{"exploded"}}); err == nil {
+		t.Errorf("Expected an error for an unknown event")
+	}
+
+	if err := Add(Webhook{URL: "https://example.com/hook"}); err != nil {
+		t.Fatalf("Unexpected error adding the webhook: %s", err)
+	}
+	if err := Add(Webhook{URL: "https://chat.example.com/bot", Events: []Event{Started}}); err != nil {
+		t.Fatalf("Unexpected error adding the webhook: %s", err)
+	}
+	// Adding the URL again replaces its webhook
+	if err := Add(Webhook{URL: "https://example.com/hook", Secret: "s3cret"}); err != nil {
+		t.Fatalf("Unexpected error adding the webhook: %s", err)
+	}
+	hooks, err := List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing the webhooks: %s", err)
+	}
+	expected := []Webhook{
+		{URL: "https://example.com/hook", Secret: "s3cret"},
+		{URL: "https://chat.example.com/bot", Events: []Event{Started}},
+	}
+	if !reflect.DeepEqual(hooks, expected) {
+		t.Errorf("Expected the webhooks %v, got %v", expected, hooks)
+	}
+
+	if removed, err := Remove("https://example.com/hook"); err != nil || !removed {
+		t.Errorf("Expected the webhook to be removed, got %v, %v", removed, err)
+	}
+	if removed, err := Remove("https://example.com/hook"); err != nil || removed {
+		t.Errorf("Expected nothing to remove, got %v, %v", removed, err)
+	}
+	if hooks, _ := List(); len(hooks) != 1 {
+		t.Errorf("Expected one webhook left, got %v", hooks)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]*http.Request{}
+	bodies := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		received[r.URL.Path], bodies[r.URL.Path] = r, body
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	hooks := []Webhook{
+		{URL: server.URL + "/all", Secret: "s3cret"},
+		{URL: server.URL + "/stops", Events: []Event{Stopped}},
+		{URL: server.URL + "/broken"},
+	}
+	n := Notification{
+		Event:   AddonEnabled,
+		Time:    time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC),
+		Profile: "dev",
+		Host:    "laptop",
+		User:    "alice",
+		Details: map[string]string{"addon": "ingress"},
+	}
+	errs := Send(hooks, n)
+	if len(errs) != 1 {
+		t.Errorf("Expected the error of the broken webhook, got %v", errs)
+	}
+	if _, ok := received["/stops"]; ok {
+		t.Errorf("Expected the event not to be posted to the webhook of the other events")
+	}
+
+	r, ok := received["/all"]
+	if !ok {
+		t.Fatalf("Expected the event to be posted")
+	}
+	if r.Header.Get(EventHeader) != "addon-enabled" || r.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected headers %v", r.Header)
+	}
+	if sig := r.Header.Get(SignatureHeader); sig != Sign("s3cret", bodies["/all"]) {
+		t.Errorf("Expected the body signed with the secret, got %s", sig)
+	}
+	if sig := received["/broken"].Header.Get(SignatureHeader); sig != "" {
+		t.Errorf("Expected no signature without a secret, got %s", sig)
+	}
+	var got Notification
+	if err := json.Unmarshal(bodies["/all"], &got); err != nil {
+		t.Fatalf("Error parsing the body: %s", err)
+	}
+	if !reflect.DeepEqual(got, n) {
+		t.Errorf("Expected the notification %v, got %v", n, got)
+	}
+}
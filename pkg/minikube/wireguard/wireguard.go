@@ -0,0 +1,137 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wireguard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Defaults of the endpoint exposed on the VM
+const (
+	DefaultPort      = 51820
+	DefaultSubnet    = "10.99.0.0/24"
+	keepaliveSeconds = 25
+)
+
+// Key is a Curve25519 WireGuard key.
+type Key [32]byte
+
+// GeneratePrivateKey returns a new private key, clamped as described in
+// https://cr.yp.to/ecdh.html
+func GeneratePrivateKey() (Key, error) {
+	var k Key
+	if _, err := io.ReadFull(rand.Reader, k[:]); err != nil {
+		return k, errors.Wrap(err, "Error generating WireGuard key")
+	}
+	k[0] &= 248
+	k[31] &= 127
+	k[31] |= 64
+	return k, nil
+}
+
+// PublicKey returns the public key of the private key.
+func (k Key) PublicKey() Key {
+	var pub Key
+	priv := [32]byte(k)
+	curve25519.ScalarBaseMult((*[32]byte)(&pub), &priv)
+	return pub
+}
+
+// String returns the key in the base64 form used by wg and config files.
+func (k Key) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// ParseKey parses a base64 encoded key, as printed by wg.
+func ParseKey(s string) (Key, error) {
+	var k Key
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return k, errors.Wrapf(err, "Error decoding WireGuard key %q", s)
+	}
+	if len(b) != len(k) {
+		return k, fmt.Errorf("Invalid WireGuard key %q: expected %d bytes, got %d", s, len(k), len(b))
+	}
+	copy(k[:], b)
+	return k, nil
+}
+
+// Addresses returns the tunnel addresses of the VM and of the client in the
+// subnet: its first and second hosts.
+func Addresses(subnet string) (server, client *net.IPNet, err error) {
+	_, n, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid WireGuard subnet %q", subnet)
+	}
+	ip := n.IP.To4()
+	if ip == nil {
+		return nil, nil, fmt.Errorf("Invalid WireGuard subnet %q: only IPv4 subnets are supported", subnet)
+	}
+	if ones, bits := n.Mask.Size(); bits-ones < 2 {
+		return nil, nil, fmt.Errorf("Invalid WireGuard subnet %q: it must hold at least two hosts", subnet)
+	}
+	server = &net.IPNet{IP: nthIP(ip, 1), Mask: n.Mask}
+	client = &net.IPNet{IP: nthIP(ip, 2), Mask: net.CIDRMask(32, 32)}
+	return server, client, nil
+}
+
+func nthIP(base net.IP, n byte) net.IP {
+	ip := make(net.IP, len(base))
+	copy(ip, base)
+	ip[len(ip)-1] += n
+	return ip
+}
+
+// ClientConfig is the configuration of the peer connecting to the VM.
+type ClientConfig struct {
+	PrivateKey Key
+	Address    *net.IPNet
+	// ServerPublicKey is the key of the endpoint on the VM, reached at Endpoint
+	ServerPublicKey Key
+	Endpoint        string
+	// AllowedIPs are the networks routed through the tunnel
+	AllowedIPs []string
+}
+
+var clientConfigTemplate = template.Must(template.New("wg").Parse(`[Interface]
+PrivateKey = {{.PrivateKey}}
+Address = {{.Address}}
+
+[Peer]
+PublicKey = {{.ServerPublicKey}}
+Endpoint = {{.Endpoint}}
+AllowedIPs = {{.JoinedAllowedIPs}}
+PersistentKeepalive = {{.Keepalive}}
+`))
+
+// Write writes the config in the wg-quick format.
+func (c *ClientConfig) Write(w io.Writer) error {
+	return clientConfigTemplate.Execute(w, struct {
+		*ClientConfig
+		JoinedAllowedIPs string
+		Keepalive        int
+	}{c, strings.Join(c.AllowedIPs, ", "), keepaliveSeconds})
+}
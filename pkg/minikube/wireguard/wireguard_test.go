@@ -0,0 +1,115 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wireguard
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestPublicKey(t *testing.T) {
+	// Test vector from RFC 7748, section 6.1
+	var priv Key
+	b, _ := hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	copy(priv[:], b)
+	expected := "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a"
+	if pub := priv.PublicKey(); hex.EncodeToString(pub[:]) != expected {
+		t.Errorf("Expected public key %s, got %x", expected, pub[:])
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	k, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	parsed, err := ParseKey(k.String() + "\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if parsed != k {
+		t.Errorf("Expected the parsed key to be %s, got %s", k, parsed)
+	}
+	for _, invalid := range []string{"", "not base64!", "c2hvcnQ="} {
+		if _, err := ParseKey(invalid); err == nil {
+			t.Errorf("Expected an error parsing %q", invalid)
+		}
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	var tcs = []struct {
+		subnet    string
+		server    string
+		client    string
+		shouldErr bool
+	}{
+		{subnet: "10.99.0.0/24", server: "10.99.0.1/24", client: "10.99.0.2/32"},
+		{subnet: "192.168.7.16/30", server: "192.168.7.17/30", client: "192.168.7.18/32"},
+		{subnet: "10.99.0.0/31", shouldErr: true},
+		{subnet: "fd00::/64", shouldErr: true},
+		{subnet: "10.99.0.0", shouldErr: true},
+	}
+	for _, test := range tcs {
+		t.Run(test.subnet, func(t *testing.T) {
+			server, client, err := Addresses(test.subnet)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if err != nil {
+				return
+			}
+			if server.String() != test.server || client.String() != test.client {
+				t.Errorf("Expected addresses %s and %s, got %s and %s", test.server, test.client, server, client)
+			}
+		})
+	}
+}
+
+func TestClientConfigWrite(t *testing.T) {
+	var priv, serverKey Key
+	priv[0], serverKey[0] = 1, 2
+	_, client, _ := Addresses(DefaultSubnet)
+	c := &ClientConfig{
+		PrivateKey:      priv,
+		Address:         client,
+		ServerPublicKey: serverKey,
+		Endpoint:        "192.168.99.100:51820",
+		AllowedIPs:      []string{DefaultSubnet, "10.0.0.0/24"},
+	}
+	var b bytes.Buffer
+	if err := c.Write(&b); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `[Interface]
+PrivateKey = AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=
+Address = 10.99.0.2/32
+
+[Peer]
+PublicKey = AgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=
+Endpoint = 192.168.99.100:51820
+AllowedIPs = 10.99.0.0/24, 10.0.0.0/24
+PersistentKeepalive = 25
+`
+	if b.String() != expected {
+		t.Errorf("Unexpected client config:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
@@ -41,6 +41,9 @@ func init() {
 	provision.Register("Buildroot", &provision.RegisteredProvisioner{
 		New: NewBuildrootProvisioner,
 	})
+	provision.Register("MinikubeNode", &provision.RegisteredProvisioner{
+		New: NewNodeProvisioner,
+	})
 }
 
 func NewBuildrootProvisioner(d drivers.Driver) provision.Provisioner {
@@ -49,6 +52,14 @@ func NewBuildrootProvisioner(d drivers.Driver) provision.Provisioner {
 	}
 }
 
+// NewNodeProvisioner provisions the node image of the container drivers,
+// which sets up docker as the ISO does.
+func NewNodeProvisioner(d drivers.Driver) provision.Provisioner {
+	return &BuildrootProvisioner{
+		provision.NewSystemdProvisioner("minikube-node", d),
+	}
+}
+
 func (p *BuildrootProvisioner) String() string {
 	return "buildroot"
 }
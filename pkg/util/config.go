@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	utilconfig "k8s.io/kubernetes/pkg/util/config"
 	utilnet "k8s.io/kubernetes/pkg/util/net"
 )
 
@@ -91,6 +92,12 @@ func setElement(e reflect.Value, v string) error {
 		case []string:
 			vals := strings.Split(v, ",")
 			e.Set(reflect.ValueOf(vals))
+		case utilconfig.ConfigurationMap:
+			m := utilconfig.ConfigurationMap{}
+			if err := m.Set(v); err != nil {
+				return fmt.Errorf("Error converting input %s to a ConfigurationMap: %s", v, err)
+			}
+			e.Set(reflect.ValueOf(m))
 		default:
 			return fmt.Errorf("Unable to set type %T.", t)
 		}
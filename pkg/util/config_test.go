@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"testing"
 
+	utilconfig "k8s.io/kubernetes/pkg/util/config"
 	utilnet "k8s.io/kubernetes/pkg/util/net"
 )
 
@@ -58,6 +59,7 @@ type subConfig3 struct {
 	S []string
 	T aliasedString
 	U net.IPNet
+	V utilconfig.ConfigurationMap
 }
 
 func buildConfig() testConfig {
@@ -180,6 +182,9 @@ func TestSetElement(t *testing.T) {
 		{"D.I.S", "a,b", func(t testConfig) bool { return reflect.DeepEqual(t.D.I.S, []string{"a", "b"}) }},
 		{"D.I.T", "foo", func(t testConfig) bool { return t.D.I.T == "foo" }},
 		{"D.I.U", "11.22.0.0/16", func(t testConfig) bool { return t.D.I.U.String() == "11.22.0.0/16" }},
+		{"D.I.V", "cpu=100m,memory=1Gi", func(t testConfig) bool {
+			return reflect.DeepEqual(t.D.I.V, utilconfig.ConfigurationMap{"cpu": "100m", "memory": "1Gi"})
+		}},
 	} {
 		a := buildConfig()
 		if err := FindAndSet(tc.path, &a, tc.newval); err != nil {
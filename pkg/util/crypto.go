@@ -34,7 +34,7 @@ import (
 )
 
 func GenerateCACert(certPath, keyPath string, name string) error {
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	priv, err := rsa.GenerateKey(rand.Reader, rsaKeyBits())
 	if err != nil {
 		return errors.Wrap(err, "Error generating rsa key")
 	}
@@ -50,7 +50,8 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
-		IsCA: true,
+		IsCA:                  true,
+		SignatureAlgorithm:    signatureAlgorithm(),
 	}
 
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, &template, priv)
@@ -98,6 +99,7 @@ func GenerateSignedCert(certPath, keyPath string, ips []net.IP, alternateDNS []s
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
+		SignatureAlgorithm:    signatureAlgorithm(),
 	}
 
 	template.IPAddresses = append(template.IPAddresses, ips...)
@@ -111,18 +113,33 @@ func GenerateSignedCert(certPath, keyPath string, ips []net.IP, alternateDNS []s
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
-func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
+// KeyMeetsCryptoMode reports whether the RSA key at keyPath is at least as
+// large as the keys the crypto mode generates.
+func KeyMeetsCryptoMode(keyPath string) bool {
+	priv, err := loadPrivateKey(keyPath)
+	return err == nil && priv.N.BitLen() >= rsaKeyBits()
+}
+
+func loadPrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
-	if err == nil {
-		decodedKey, _ := pem.Decode(keyBytes)
-		if decodedKey != nil {
-			priv, err := x509.ParsePKCS1PrivateKey(decodedKey.Bytes)
-			if err == nil {
-				return priv, nil
-			}
-		}
-	}
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	decodedKey, _ := pem.Decode(keyBytes)
+	if decodedKey == nil {
+		return nil, errors.New("Unable to decode key.")
+	}
+	return x509.ParsePKCS1PrivateKey(decodedKey.Bytes)
+}
+
+// loadOrGeneratePrivateKey keeps the existing key, unless it is smaller than
+// the crypto mode requires.
+func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
+	priv, err := loadPrivateKey(keyPath)
+	if err == nil && priv.N.BitLen() >= rsaKeyBits() {
+		return priv, nil
+	}
+	priv, err = rsa.GenerateKey(rand.Reader, rsaKeyBits())
 	if err != nil {
 		return nil, errors.Wrap(err, "Error generating RSA key")
 	}
@@ -148,7 +165,8 @@ func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *r
 	if err := os.MkdirAll(filepath.Dir(certPath), os.FileMode(0755)); err != nil {
 		return errors.Wrap(err, "Error creating certificate directory")
 	}
-	if err := ioutil.WriteFile(certPath, certBuffer.Bytes(), os.FileMode(0644)); err != nil {
+	// Certs are only read by their owner, the VM gets its own copies
+	if err := ioutil.WriteFile(certPath, certBuffer.Bytes(), os.FileMode(0600)); err != nil {
 		return errors.Wrap(err, "Error writing certificate to cert path")
 	}
 
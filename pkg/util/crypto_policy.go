@@ -0,0 +1,143 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// The crypto modes restrict the algorithms of the generated certs and of TLS.
+// The fips mode only allows those approved by FIPS 140-2.
+const (
+	CryptoModeDefault = "default"
+	CryptoModeFIPS    = "fips"
+)
+
+// CryptoModeSetting is the config setting and the flag of the crypto mode
+const CryptoModeSetting = "crypto-mode"
+
+// CryptoModes are the valid crypto modes
+var CryptoModes = []string{CryptoModeDefault, CryptoModeFIPS}
+
+// ApprovedCipherSuites are the TLS 1.2 cipher suites allowed in the fips mode.
+var ApprovedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+var cipherSuiteNames = map[uint16]string{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+}
+
+// The fips build tag makes the fips mode the default, see fips.go
+var cryptoMode = defaultCryptoMode
+
+// ValidateCryptoMode checks that the mode is one of CryptoModes.
+func ValidateCryptoMode(mode string) error {
+	for _, m := range CryptoModes {
+		if mode == m {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not a valid crypto mode, expected one of %v", mode, CryptoModes)
+}
+
+// SetCryptoMode sets the crypto mode of the process. A FIPS build cannot
+// leave the fips mode.
+func SetCryptoMode(mode string) error {
+	if err := ValidateCryptoMode(mode); err != nil {
+		return err
+	}
+	if FIPSBuild() && mode != CryptoModeFIPS {
+		return errors.Errorf("The crypto mode of a FIPS build is %s", CryptoModeFIPS)
+	}
+	cryptoMode = mode
+	return nil
+}
+
+// GetCryptoMode returns the crypto mode of the process.
+func GetCryptoMode() string {
+	return cryptoMode
+}
+
+// FIPSBuild reports whether the binary was built with the fips tag, which
+// restricts all of its TLS connections to the FIPS validated crypto module.
+func FIPSBuild() bool {
+	return fipsBuild
+}
+
+// TLSConfig returns the TLS settings of the crypto mode.
+func TLSConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cryptoMode == CryptoModeFIPS {
+		config.CipherSuites = ApprovedCipherSuites
+		config.PreferServerCipherSuites = true
+	}
+	return config
+}
+
+// rsaKeyBits returns the size of the generated RSA keys.
+func rsaKeyBits() int {
+	if cryptoMode == CryptoModeFIPS {
+		return 3072
+	}
+	return 2048
+}
+
+// signatureAlgorithm returns the signature algorithm of the generated
+// certs, zero letting x509 choose one.
+func signatureAlgorithm() x509.SignatureAlgorithm {
+	if cryptoMode == CryptoModeFIPS {
+		return x509.SHA256WithRSA
+	}
+	return x509.UnknownSignatureAlgorithm
+}
+
+// CryptoPosture describes the active crypto settings.
+type CryptoPosture struct {
+	Mode         string
+	FIPSBuild    bool
+	KeyAlgorithm string
+	TLSVersion   string
+	CipherSuites []string
+}
+
+// GetCryptoPosture returns the active crypto settings.
+func GetCryptoPosture() CryptoPosture {
+	p := CryptoPosture{
+		Mode:         cryptoMode,
+		FIPSBuild:    FIPSBuild(),
+		KeyAlgorithm: fmt.Sprintf("RSA %d", rsaKeyBits()),
+		TLSVersion:   "1.2 or later",
+	}
+	if cryptoMode == CryptoModeFIPS {
+		p.KeyAlgorithm += " with SHA-256"
+		for _, c := range ApprovedCipherSuites {
+			p.CipherSuites = append(p.CipherSuites, cipherSuiteNames[c])
+		}
+	}
+	return p
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestSetCryptoMode(t *testing.T) {
+	defer SetCryptoMode(defaultCryptoMode)
+
+	if err := SetCryptoMode("weak"); err == nil {
+		t.Error("Expected an error for an invalid crypto mode")
+	}
+	if err := SetCryptoMode(CryptoModeFIPS); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if GetCryptoMode() != CryptoModeFIPS {
+		t.Errorf("Expected the crypto mode to be %s, got %s", CryptoModeFIPS, GetCryptoMode())
+	}
+
+	config := TLSConfig()
+	if config.MinVersion != tls.VersionTLS12 || len(config.CipherSuites) != len(ApprovedCipherSuites) {
+		t.Errorf("Expected TLS to be restricted to the approved cipher suites, got %+v", config)
+	}
+	posture := GetCryptoPosture()
+	if posture.KeyAlgorithm != "RSA 3072 with SHA-256" || len(posture.CipherSuites) != len(ApprovedCipherSuites) {
+		t.Errorf("Unexpected crypto posture %+v", posture)
+	}
+
+	if FIPSBuild() {
+		return
+	}
+	if err := SetCryptoMode(CryptoModeDefault); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if config := TLSConfig(); config.CipherSuites != nil {
+		t.Errorf("Expected the default cipher suites, got %v", config.CipherSuites)
+	}
+}
+
+func TestFIPSCerts(t *testing.T) {
+	defer SetCryptoMode(defaultCryptoMode)
+
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	caCert := filepath.Join(tmpDir, "ca.crt")
+	caKey := filepath.Join(tmpDir, "ca.key")
+	cert := filepath.Join(tmpDir, "apiserver.crt")
+	key := filepath.Join(tmpDir, "apiserver.key")
+
+	if !FIPSBuild() {
+		if err := GenerateCACert(caCert, caKey, constants.APIServerName); err != nil {
+			t.Fatalf("Error generating CA cert: %s", err)
+		}
+		if err := SetCryptoMode(CryptoModeFIPS); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if KeyMeetsCryptoMode(caKey) {
+			t.Fatal("Expected the 2048 bits key not to meet the fips mode")
+		}
+	}
+
+	if err := GenerateCACert(caCert, caKey, constants.APIServerName); err != nil {
+		t.Fatalf("Error generating CA cert: %s", err)
+	}
+	if err := GenerateSignedCert(cert, key, nil, nil, caCert, caKey); err != nil {
+		t.Fatalf("Error generating signed cert: %s", err)
+	}
+	for _, k := range []string{caKey, key} {
+		if !KeyMeetsCryptoMode(k) {
+			t.Errorf("Expected %s to meet the fips mode", k)
+		}
+	}
+	certBytes, err := ioutil.ReadFile(cert)
+	if err != nil {
+		t.Fatalf("Error reading cert data: %v", err)
+	}
+	data, _ := pem.Decode(certBytes)
+	c, err := x509.ParseCertificate(data.Bytes)
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %v", err)
+	}
+	if c.SignatureAlgorithm != x509.SHA256WithRSA {
+		t.Errorf("Expected a SHA-256 signature, got %s", c.SignatureAlgorithm)
+	}
+}
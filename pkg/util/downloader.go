@@ -46,7 +46,7 @@ func (f DefaultDownloader) GetISOFileURI(isoURL string) string {
 	if urlObj.Scheme == fileScheme {
 		return isoURL
 	}
-	isoPath := filepath.Join(constants.GetMinipath(), "cache", "iso", filepath.Base(isoURL))
+	isoPath := constants.MakeMiniPath("cache", "iso", filepath.Base(isoURL))
 	// As this is a file URL there should be no backslashes regardless of platform running on.
 	return "file://" + filepath.ToSlash(isoPath)
 }
@@ -97,7 +97,7 @@ func (f DefaultDownloader) ShouldCacheMinikubeISO(isoURL string) bool {
 }
 
 func (f DefaultDownloader) GetISOCacheFilepath(isoURL string) string {
-	return filepath.Join(constants.GetMinipath(), "cache", "iso", filepath.Base(isoURL))
+	return constants.MakeMiniPath("cache", "iso", filepath.Base(isoURL))
 }
 
 func (f DefaultDownloader) IsMinikubeISOCached(isoURL string) bool {
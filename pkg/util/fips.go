@@ -0,0 +1,31 @@
+// +build fips
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// A FIPS build needs a Go toolchain with the BoringCrypto module, see the
+// FIPS variable of the Makefile.
+import (
+	// Restricts all TLS connections to the FIPS approved settings
+	_ "crypto/tls/fipsonly"
+)
+
+const (
+	fipsBuild         = true
+	defaultCryptoMode = CryptoModeFIPS
+)
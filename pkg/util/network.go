@@ -0,0 +1,80 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultServiceClusterIPRange is the network the services get their cluster
+// IPs from, unless another is set
+const DefaultServiceClusterIPRange = DefaultServiceClusterIP + "/24"
+
+// ServiceIPs returns the cluster IPs of the apiserver and of the cluster DNS
+// in the service network, its first and tenth addresses, the defaults for an
+// empty one.
+func ServiceIPs(serviceCIDR string) (net.IP, net.IP, error) {
+	if serviceCIDR == "" {
+		return net.ParseIP(DefaultServiceClusterIP), net.ParseIP(DefaultDNSIP), nil
+	}
+	_, n, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing the service cluster IP range")
+	}
+	if n.IP.To4() == nil {
+		return nil, nil, errors.Errorf("The service cluster IP range %s is not an IPv4 network", serviceCIDR)
+	}
+	if ones, _ := n.Mask.Size(); ones > 28 {
+		return nil, nil, errors.Errorf("The service cluster IP range %s is too small, use a prefix of at most /28", serviceCIDR)
+	}
+	return nthIP(n.IP, 1), nthIP(n.IP, 10), nil
+}
+
+func nthIP(network net.IP, n byte) net.IP {
+	ip := make(net.IP, len(network.To4()))
+	copy(ip, network.To4())
+	ip[len(ip)-1] += n
+	return ip
+}
+
+// ValidateClusterCIDRs checks the networks of the pods and of the services of
+// the cluster, empty for the defaults, which must not overlap.
+func ValidateClusterCIDRs(podCIDR, serviceCIDR string) error {
+	if _, _, err := ServiceIPs(serviceCIDR); err != nil {
+		return err
+	}
+	if podCIDR == "" {
+		return nil
+	}
+	_, pods, err := net.ParseCIDR(podCIDR)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing the pod network CIDR")
+	}
+	if pods.IP.To4() == nil {
+		return errors.Errorf("The pod network %s is not an IPv4 network", podCIDR)
+	}
+	if serviceCIDR == "" {
+		serviceCIDR = DefaultServiceClusterIPRange
+	}
+	_, services, _ := net.ParseCIDR(serviceCIDR)
+	if pods.Contains(services.IP) || services.Contains(pods.IP) {
+		return errors.Errorf("The pod network %s overlaps the service cluster IP range %s", podCIDR, serviceCIDR)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestServiceIPs(t *testing.T) {
+	var tcs = []struct {
+		cidr, apiserver, dns string
+		shouldErr            bool
+	}{
+		{cidr: "", apiserver: "10.0.0.1", dns: "10.0.0.10"},
+		{cidr: "172.30.0.0/16", apiserver: "172.30.0.1", dns: "172.30.0.10"},
+		{cidr: "172.30.5.7/24", apiserver: "172.30.5.1", dns: "172.30.5.10"},
+		{cidr: "172.30.0.0/29", shouldErr: true},
+		{cidr: "fd00::/64", shouldErr: true},
+		{cidr: "172.30.0.0", shouldErr: true},
+	}
+	for _, test := range tcs {
+		apiserver, dns, err := ServiceIPs(test.cidr)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %q: %v", test.cidr, err)
+			continue
+		}
+		if !test.shouldErr && (apiserver.String() != test.apiserver || dns.String() != test.dns) {
+			t.Errorf("Expected %s and %s for %q, got %s and %s", test.apiserver, test.dns, test.cidr, apiserver, dns)
+		}
+	}
+}
+
+func TestValidateClusterCIDRs(t *testing.T) {
+	var tcs = []struct {
+		pods, services string
+		shouldErr      bool
+	}{
+		{pods: "", services: ""},
+		{pods: "172.16.0.0/16", services: "172.17.0.0/16"},
+		{pods: "10.0.0.0/8", services: "", shouldErr: true},
+		{pods: "172.16.0.0/16", services: "172.16.128.0/20", shouldErr: true},
+		{pods: "pods", services: "", shouldErr: true},
+	}
+	for _, test := range tcs {
+		if err := ValidateClusterCIDRs(test.pods, test.services); (err != nil) != test.shouldErr {
+			t.Errorf("Unexpected error for %q and %q: %v", test.pods, test.services, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of names returned by Suggestions
+const maxSuggestions = 3
+
+// Suggestions returns the candidates closest to name, for did you mean hints:
+// those within an edit distance of a third of the length of name, or having
+// name as a prefix, ignoring case, closest first.
+func Suggestions(name string, candidates []string) []string {
+	lower := strings.ToLower(name)
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	var found []suggestion
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		d := editDistance(lower, strings.ToLower(c))
+		if d <= maxDistance || (name != "" && strings.HasPrefix(strings.ToLower(c), lower)) {
+			found = append(found, suggestion{c, d})
+		}
+	}
+	sort.Sort(byDistance(found))
+
+	var names []string
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		names = append(names, found[i].name)
+	}
+	return names
+}
+
+type suggestion struct {
+	name     string
+	distance int
+}
+
+type byDistance []suggestion
+
+func (s byDistance) Len() int      { return len(s) }
+func (s byDistance) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDistance) Less(i, j int) bool {
+	if s[i].distance != s[j].distance {
+		return s[i].distance < s[j].distance
+	}
+	return s[i].name < s[j].name
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	candidates := []string{"memory", "cpus", "ingress", "iso-url", "kube-dns", "heapster", "dashboard", "v"}
+	var tcs = []struct {
+		name     string
+		expected []string
+	}{
+		{name: "memry", expected: []string{"memory"}},
+		{name: "ingres", expected: []string{"ingress"}},
+		{name: "MEMORY", expected: []string{"memory"}},
+		{name: "memory"},
+		{name: "cpu", expected: []string{"cpus"}},
+		{name: "kube", expected: []string{"kube-dns"}},
+		{name: "dashbaord", expected: []string{"dashboard"}},
+		{name: "vm-driver"},
+		{name: "x", expected: []string{"v"}},
+	}
+	for _, test := range tcs {
+		t.Run(test.name, func(t *testing.T) {
+			if s := Suggestions(test.name, candidates); !reflect.DeepEqual(s, test.expected) {
+				t.Errorf("Expected suggestions %v, got %v", test.expected, s)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"memory", "memry", 1},
+	} {
+		if d := editDistance(test.a, test.b); d != test.distance {
+			t.Errorf("Expected the distance between %q and %q to be %d, got %d", test.a, test.b, test.distance, d)
+		}
+	}
+}